@@ -0,0 +1,33 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// RemoveCmd ...
+var RemoveCmd = &cobra.Command{
+	Use:   "rm <app>",
+	Short: "Destroy an app and remove it from Nanobox.",
+	Long: `
+Destroys an app from anywhere, like 'nanobox destroy' does from
+inside its directory. The code of the app is left alone.
+	`,
+	PreRun: steps.Run("start"),
+	Run:    removeFn,
+}
+
+// removeFn ...
+func removeFn(ccmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Printf("\n! Please provide the app to remove, ie: nanobox app rm myapp\n\n")
+		return
+	}
+
+	display.CommandErr(processors.RemoveApp(args[0]))
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// RenameCmd ...
+var RenameCmd = &cobra.Command{
+	Use:   "rename <app> <new-name>",
+	Short: "Rename an app.",
+	Long: `
+Changes the name nanobox shows an app with. The app can be
+given by its name or its path, see 'nanobox apps'.
+	`,
+	Run: renameFn,
+}
+
+// renameFn ...
+func renameFn(ccmd *cobra.Command, args []string) {
+	if len(args) != 2 {
+		fmt.Printf("\n! Please provide the app and its new name, ie: nanobox app rename myapp shop\n\n")
+		return
+	}
+
+	display.CommandErr(processors.RenameApp(args[0], args[1]))
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/app"
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// AppsCmd ...
+	AppsCmd = &cobra.Command{
+		Use:   "apps",
+		Short: "List every app on this machine.",
+		Long: `
+Lists every app nanobox knows about with the status, the
+number of services, the disk used and the IP of each of
+its environments.
+		`,
+		Run: appsFn,
+	}
+
+	// AppCmd ...
+	AppCmd = &cobra.Command{
+		Use:   "app",
+		Short: "Manage the apps on this machine.",
+		Long:  ``,
+	}
+)
+
+func init() {
+	AppCmd.AddCommand(app.RenameCmd)
+	AppCmd.AddCommand(app.RemoveCmd)
+}
+
+// appsFn ...
+func appsFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.Apps())
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/helpers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// ArchiveCmd ...
+	ArchiveCmd = &cobra.Command{
+		Use:   "archive [local | dry-run]",
+		Short: "Archive a dormant app and free its resources.",
+		Long: `
+Stops the app, packs its data and state into a single archive
+and removes the live containers. Use 'nanobox unarchive' to
+restore the app when you need it again.
+		`,
+		PreRun: steps.Run("start"),
+		Run:    archiveFn,
+	}
+
+	// UnarchiveCmd ...
+	UnarchiveCmd = &cobra.Command{
+		Use:   "unarchive [local | dry-run]",
+		Short: "Restore an archived app.",
+		Long: `
+Restores an app from its archive, recreating its data services
+and loading the archived data back into them.
+		`,
+		PreRun: steps.Run("start"),
+		Run:    unarchiveFn,
+	}
+)
+
+// archiveFn ...
+func archiveFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	args, location, name := helpers.Endpoint(envModel, args, 0)
+
+	switch location {
+	case "local":
+		appModel, err := models.FindAppBySlug(config.EnvID(), name)
+		if err != nil {
+			fmt.Println("Could not find the application")
+			return
+		}
+
+		display.CommandErr(app.Archive(appModel))
+	case "production":
+		fmt.Printf(`
+-----------------------------------------------
+Archiving production apps is not yet supported.
+-----------------------------------------------
+
+`)
+	}
+}
+
+// unarchiveFn ...
+func unarchiveFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	args, location, name := helpers.Endpoint(envModel, args, 0)
+
+	switch location {
+	case "local":
+		archiveModel, err := models.FindArchiveByApp(fmt.Sprintf("%s_%s", config.EnvID(), name))
+		if err != nil {
+			fmt.Println("Could not find an archive for the application")
+			return
+		}
+
+		display.CommandErr(app.Unarchive(envModel, archiveModel))
+	case "production":
+		fmt.Printf(`
+-------------------------------------------------
+Unarchiving production apps is not yet supported.
+-------------------------------------------------
+
+`)
+	}
+}
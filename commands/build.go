@@ -1,13 +1,20 @@
 package commands
 
 import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/nanobox-io/nanobox-boxfile"
 	"github.com/spf13/cobra"
 
 	"github.com/nanobox-io/nanobox/commands/steps"
+	container_generator "github.com/nanobox-io/nanobox/generators/containers"
 	"github.com/nanobox-io/nanobox/generators/hooks/build"
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/processors/code"
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/config"
 	"github.com/nanobox-io/nanobox/util/display"
@@ -27,22 +34,101 @@ locally and in live environments.
 		Aliases: []string{"build"},
 	}
 
-	cacheClear bool
+	cacheClear     bool
+	noCache        bool
+	imageTag       string
+	remoteHost     string
+	remoteInsecure bool
+	buildArgs      []string
+	reproducible   bool
+	buildTimeout   time.Duration
+	forceBuild     bool
+	buildWatch     bool
 )
 
 func init() {
 	steps.Build("build-runtime", buildComplete, buildFn)
 
 	BuildCmd.Flags().BoolVar(&cacheClear, "clear-cache", false, "Clear package cache for this build.")
+	BuildCmd.Flags().BoolVar(&noCache, "no-cache", false, "Run every build phase, even if its inputs are unchanged.")
+	BuildCmd.Flags().StringVar(&imageTag, "image", "", "Compile the app and package it as a docker image with this tag, ie: myapp:dev")
+	BuildCmd.Flags().StringSliceVar(&buildArgs, "arg", []string{}, "Pass a build-time variable, ie: NPM_TOKEN=abc. A name alone takes the value from your environment.")
+	BuildCmd.Flags().BoolVar(&reproducible, "reproducible", false, "Build with a pinned engine, mirrors and timestamps, and record the fingerprint of the build.")
+	BuildCmd.Flags().DurationVar(&buildTimeout, "timeout", 0, "Cancel the build if it runs longer than this, ie: 30m")
+	BuildCmd.Flags().BoolVar(&forceBuild, "force", false, "Stop a build that is already running for this app and build anyway.")
+	BuildCmd.Flags().BoolVar(&buildWatch, "watch", false, "Rebuild whenever the source changes, until ctrl + c.")
+	BuildCmd.Flags().StringVar(&remoteHost, "remote", "", "Run the build on a remote docker host over tls, ie: tcp://builder:2376")
+	BuildCmd.Flags().BoolVar(&remoteInsecure, "remote-insecure", false, "Talk to the remote docker host without tls.")
 }
 
 func buildFn(ccmd *cobra.Command, args []string) {
 	if cacheClear {
 		build.ClearPkgCache = true
+		// the runtimes have to be installed again without the package cache
+		code.NoCache = true
+	}
+
+	if noCache {
+		code.NoCache = true
+	}
+
+	vars, err := parseBuildArgs(buildArgs)
+	if err != nil {
+		fmt.Printf("\n! %s\n\n", err.Error())
+		return
 	}
+	container_generator.BuildArgs = vars
+
+	code.Reproducible = reproducible
+	code.RemoteInsecure = remoteInsecure
+	processors.BuildTimeout = buildTimeout
+	processors.ForceBuild = forceBuild
 
 	env, _ := models.FindEnvByID(config.EnvID())
-	display.CommandErr(processors.Build(env))
+
+	switch {
+	case remoteHost != "":
+		// offload the build and bring the artifacts back
+		display.CommandErr(processors.BuildRemote(env, remoteHost))
+	case buildWatch:
+		display.CommandErr(processors.BuildWatch(env))
+		return
+	default:
+		display.CommandErr(processors.Build(env))
+	}
+
+	// package the compiled app as an image that runs outside of nanobox
+	if imageTag != "" {
+		display.CommandErr(processors.Compile(env))
+		display.CommandErr(processors.Image(env, imageTag))
+	}
+}
+
+// parseBuildArgs parses KEY=VAL build args. A KEY without a value is read from
+// the environment, so secrets don't have to show up in the shell history.
+func parseBuildArgs(args []string) (map[string]string, error) {
+	vars := map[string]string{}
+
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if key == "" {
+			return nil, fmt.Errorf("Invalid build arg '%s', ie: --arg NPM_TOKEN=abc", arg)
+		}
+
+		if len(parts) == 2 {
+			vars[key] = parts[1]
+			continue
+		}
+
+		val, ok := os.LookupEnv(key)
+		if !ok {
+			return nil, fmt.Errorf("The build arg '%s' has no value and isn't set in your environment", key)
+		}
+		vars[key] = val
+	}
+
+	return vars, nil
 }
 
 // update: this runs on deploy
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// BuildListCmd ...
+	BuildListCmd = &cobra.Command{
+		Use:     "ls",
+		Short:   "List the recent builds of your app.",
+		Aliases: []string{"list"},
+		Long: `
+Lists the recent builds of your app, newest first. The
+number of builds kept can be changed with
+'nanobox config set build-history <count>'.
+		`,
+		Run: buildListFn,
+	}
+
+	// BuildLogsCmd ...
+	BuildLogsCmd = &cobra.Command{
+		Use:   "logs <build>",
+		Short: "Print the full output of a build.",
+		Long: `
+Prints the full output of a build from the build history,
+ie: nanobox build logs 12 > good.log
+		`,
+		Run: buildLogsFn,
+	}
+)
+
+func init() {
+	BuildCmd.AddCommand(BuildListCmd)
+	BuildCmd.AddCommand(BuildLogsCmd)
+}
+
+// buildListFn ...
+func buildListFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	display.CommandErr(processors.BuildList(envModel))
+}
+
+// buildLogsFn ...
+func buildLogsFn(ccmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Printf("\n! Please provide the build to print, ie: nanobox build logs 12\n\n")
+		return
+	}
+
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	display.CommandErr(processors.BuildLogs(envModel, args[0], os.Stdout))
+}
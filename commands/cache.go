@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/env"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// CacheCmd ...
+	CacheCmd = &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the build cache.",
+		Long: `
+Manages the build cache. Builds skip the phases whose
+inputs (the boxfile, the engine and the dependency
+manifests) haven't changed since the last build.
+		`,
+	}
+
+	// CacheCleanCmd ...
+	CacheCleanCmd = &cobra.Command{
+		Use:   "clean",
+		Short: "Remove the build cache.",
+		Long: `
+Removes the build and package caches of this app and
+reports the space freed. The next build runs every phase.
+		`,
+		PreRun: steps.Run("start"),
+		Run:    cacheCleanFn,
+	}
+)
+
+func init() {
+	CacheCmd.AddCommand(CacheCleanCmd)
+}
+
+// cacheCleanFn ...
+func cacheCleanFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	display.CommandErr(env.CacheClean(envModel))
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/jcelliott/lumber"
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// CICmd ...
+	CICmd = &cobra.Command{
+		Use:   "ci",
+		Short: "Validate, build, test and optionally deploy in one go, for CI.",
+		Long: `
+Validates the boxfile, builds the runtime, compiles the app and
+runs the tests of the boxfile against fresh services, then exports
+and deploys it when asked to. Nothing prompts, the output isn't
+styled, and the first stage to fail stops the run with its exit
+code, the one of the failing test for the tests. ie:
+
+  nanobox ci --export app.tar.gz --deploy production --summary ci.json
+
+  --strict       fail on the lint warnings of the boxfile too
+  --skip-tests   don't run the tests
+  --export       export the compiled app to a dir or a tarball
+  --deploy       deploy to dry-run or a remote alias
+  --summary      write a json summary of the stages to a file
+  --cache-dir    restore and save the build cache in a dir, for the
+                 ci provider to keep between runs
+		`,
+		PreRun: ciPreRun,
+		Run:    ciFn,
+	}
+
+	// ciCmdFlags ...
+	ciCmdFlags = struct {
+		strict    bool
+		skipTests bool
+		export    string
+		deploy    string
+		summary   string
+		cacheDir  string
+	}{}
+)
+
+func init() {
+	CICmd.Flags().BoolVarP(&ciCmdFlags.strict, "strict", "", false, "fail on the lint warnings of the boxfile too")
+	CICmd.Flags().BoolVarP(&ciCmdFlags.skipTests, "skip-tests", "", false, "don't run the tests")
+	CICmd.Flags().StringVarP(&ciCmdFlags.export, "export", "", "", "export the compiled app to a dir or a tarball")
+	CICmd.Flags().StringVarP(&ciCmdFlags.deploy, "deploy", "", "", "deploy to dry-run or a remote alias")
+	CICmd.Flags().StringVarP(&ciCmdFlags.summary, "summary", "", "", "write a json summary of the stages to a file")
+	CICmd.Flags().StringVarP(&ciCmdFlags.cacheDir, "cache-dir", "", "", "restore and save the build cache in a dir")
+}
+
+// ciPreRun turns on ci mode for the run, and logs in for a remote deploy
+func ciPreRun(ccmd *cobra.Command, args []string) {
+	// the generators and hooks read ci mode from the config
+	os.Setenv("NANOBOX_CI_MODE", "true")
+	lumber.Level(lumber.INFO)
+	display.Summary = false
+	display.Level = "info"
+	display.Interactive = false
+
+	if ciCmdFlags.deploy != "" && ciCmdFlags.deploy != "dry-run" {
+		steps.Run("login")(ccmd, args)
+	}
+}
+
+// ciFn ...
+func ciFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+
+	ciConfig := processors.CIConfig{
+		Strict:    ciCmdFlags.strict,
+		SkipTests: ciCmdFlags.skipTests,
+		Export:    ciCmdFlags.export,
+		Deploy:    ciCmdFlags.deploy,
+		Summary:   ciCmdFlags.summary,
+		CacheDir:  ciCmdFlags.cacheDir,
+	}
+
+	display.CommandErr(processors.CI(envModel, ciConfig))
+}
@@ -1,12 +1,9 @@
 package commands
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
 
 	"github.com/nanobox-io/nanobox/commands/steps"
-	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/processors"
 	"github.com/nanobox-io/nanobox/util/display"
 )
@@ -16,24 +13,45 @@ var (
 	// CleanCmd ...
 	CleanCmd = &cobra.Command{
 		Use:   "clean",
-		Short: "Clean out any apps that no longer exist.",
+		Short: "Report the disk nanobox uses, and remove what no app needs.",
 		Long: `
-Clean out any apps whose working directory no longer exists. This
-will remove all associated app information from your Nanobox database.
+Reports the disk used by the images, the build cache, the
+volumes and the logs of each app, and what no app uses
+anymore. The flags remove it:
+
+  --images   the dangling images no tag points at
+  --volumes  the volumes of apps nanobox no longer knows about
+  --stale    the apps whose working directory no longer exists
+
+Nothing an app nanobox knows about uses is removed.
 `,
 		PreRun: steps.Run("start"),
 		Run:    cleanFn,
 	}
+
+	// cleanCmdFlags ...
+	cleanCmdFlags = struct {
+		images  bool
+		volumes bool
+		stale   bool
+		all     bool
+	}{}
 )
 
+func init() {
+	CleanCmd.Flags().BoolVarP(&cleanCmdFlags.images, "images", "", false, "Remove the dangling images")
+	CleanCmd.Flags().BoolVarP(&cleanCmdFlags.volumes, "volumes", "", false, "Remove the volumes of apps that were removed")
+	CleanCmd.Flags().BoolVarP(&cleanCmdFlags.stale, "stale", "", false, "Remove the apps whose directory no longer exists")
+	CleanCmd.Flags().BoolVarP(&cleanCmdFlags.all, "all", "a", false, "Remove all of the above")
+}
+
 // cleanFn ...
 func cleanFn(ccmd *cobra.Command, args []string) {
-	// get the environments
-	envs, err := models.AllEnvs()
-	if err != nil {
-		fmt.Printf("TODO: write message for command clean: %s\n", err.Error())
-		return
+	config := processors.CleanConfig{
+		Images:  cleanCmdFlags.images || cleanCmdFlags.all,
+		Volumes: cleanCmdFlags.volumes || cleanCmdFlags.all,
+		Stale:   cleanCmdFlags.stale || cleanCmdFlags.all,
 	}
 
-	display.CommandErr(processors.Clean(envs))
+	display.CommandErr(processors.Clean(config))
 }
@@ -4,7 +4,6 @@ package commands
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/jcelliott/lumber"
@@ -15,8 +14,15 @@ import (
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/processors"
 	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/audit"
 	"github.com/nanobox-io/nanobox/util/config"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/idle"
+	"github.com/nanobox-io/nanobox/util/locker"
+	"github.com/nanobox-io/nanobox/util/logging"
+	"github.com/nanobox-io/nanobox/util/notify"
+	"github.com/nanobox-io/nanobox/util/odin"
+	"github.com/nanobox-io/nanobox/util/telemetry"
 	"github.com/nanobox-io/nanobox/util/update"
 )
 
@@ -24,8 +30,8 @@ var (
 	// debug mode
 	debugMode bool
 
-	// display level debug
-	displayDebugMode bool
+	// display level debug, or trace when repeated (-vv)
+	verbosity int
 
 	// display level trace
 	displayTraceMode bool
@@ -33,6 +39,12 @@ var (
 	internalCommand bool
 	showVersion     bool
 	endpoint        string
+	apiToken        string
+	forceLock       bool
+	outputMode      string
+	configOverrides configFlag
+	verifyMode      bool
+	quietMode       bool
 
 	// NanoboxCmd ...
 	NanoboxCmd = &cobra.Command{
@@ -46,31 +58,31 @@ var (
 
 			registry.Set("debug", debugMode)
 
-			// setup the display output
-			if displayDebugMode {
-				lumber.Level(lumber.DEBUG)
-				display.Summary = false
-				display.Level = "debug"
+			// scripts read the results, the task output would be in the way
+			if err := display.SetOutput(outputMode, quietMode); err != nil {
+				display.CommandErr(err)
 			}
 
-			if displayTraceMode {
-				lumber.Level(lumber.TRACE)
-				display.Summary = false
-				display.Level = "trace"
+			// setup the display output
+			if err := setVerbosity(); err != nil {
+				display.CommandErr(err)
 			}
 
 			// alert the user if an update is needed
-			update.Check()
+			if display.Styled() {
+				update.Check()
+			}
+
+			// reject the images and the engines whose signatures don't check out
+			if verifyMode {
+				models.SetConfigFlag("verify=true")
+			}
 
 			configModel, _ := models.LoadConfig()
 
 			// TODO: look into global messaging
 			if internalCommand {
 				registry.Set("internal", internalCommand)
-				// setup a file logger, this will be replaced in verbose mode.
-				fileLogger, _ := lumber.NewAppendLogger(filepath.ToSlash(filepath.Join(config.GlobalDir(), "nanobox.log")))
-				lumber.SetLogger(fileLogger)
-
 			} else {
 				// We should only allow admin in 3 cases
 				// 1 cimode
@@ -84,7 +96,7 @@ var (
 					// if it is not an internal command (starting the server requires privilages)
 					// we wont run nanobox as privilage
 					display.UnexpectedPrivilage()
-					os.Exit(1)
+					os.Exit(util.ExitUser)
 				}
 			}
 
@@ -92,15 +104,50 @@ var (
 				registry.Set("endpoint", endpoint)
 			}
 
+			if apiToken != "" {
+				odin.SetToken(apiToken)
+			}
+
+			locker.Force = forceLock
+
 			if configModel.CIMode {
 				lumber.Level(lumber.INFO)
 				display.Summary = false
 				display.Level = "info"
 			}
+
+			// the commands nanobox runs itself aren't in the audit log or the
+			// telemetry
+			if !internalCommand {
+				audit.Start(strings.Replace(ccmd.CommandPath(), "nanobox ", "", 1), args)
+				telemetry.Start(strings.Replace(ccmd.CommandPath(), "nanobox ", "", 1))
+
+				// only someone at the terminal could have tabbed away
+				if display.Interactive {
+					notify.Start(strings.Replace(ccmd.CommandPath(), "nanobox ", "", 1))
+				}
+
+				// a running command keeps its app from being suspended
+				idle.Track(config.EnvID())
+			}
+		},
+
+		// the command didn't fail, failures are recorded by display.CommandErr
+		PersistentPostRun: func(ccmd *cobra.Command, args []string) {
+			audit.Finish(nil)
+			telemetry.Finish(false, "")
+			notify.Finish(false)
+
+			// the watcher suspends the apps once they're idle
+			if configModel, _ := models.LoadConfig(); !internalCommand && configModel.IdleTimeout > 0 && !configModel.CIMode {
+				if err := idle.Spawn(); err != nil {
+					lumber.Debug("commands:idle.Spawn(): %s", err.Error())
+				}
+			}
 		},
 
 		Run: func(ccmd *cobra.Command, args []string) {
-			if displayDebugMode || showVersion {
+			if verbosity > 0 || showVersion {
 				fmt.Println(models.VersionString())
 				return
 			}
@@ -110,18 +157,60 @@ var (
 	}
 )
 
+// setVerbosity sets the level of the display output and of the log from the
+// flags or, without them, NANOBOX_LOG
+func setVerbosity() error {
+	name := strings.ToLower(strings.TrimSpace(os.Getenv("NANOBOX_LOG")))
+
+	switch {
+	case verbosity > 1 || displayTraceMode:
+		name = "trace"
+	case verbosity == 1:
+		name = "debug"
+	}
+
+	if name == "" {
+		return nil
+	}
+
+	level, err := logging.Level(name)
+	if err != nil {
+		return util.Errorf("[USER] NANOBOX_LOG is invalid, %s", err.Error())
+	}
+
+	display.Level = name
+
+	// the summary would hide the details
+	if level <= lumber.DEBUG {
+		display.Summary = false
+	}
+
+	// the log always has the debug output
+	if level < lumber.DEBUG {
+		lumber.Level(level)
+	}
+
+	return nil
+}
+
 // init creates the list of available nanobox commands and sub commands
 func init() {
 
 	// persistent flags
 	NanoboxCmd.PersistentFlags().StringVarP(&endpoint, "endpoint", "e", "", "production endpoint")
 	NanoboxCmd.PersistentFlags().MarkHidden("endpoint")
+	NanoboxCmd.PersistentFlags().StringVarP(&apiToken, "token", "", "", "Authenticate with an api token instead of the login (also NANOBOX_TOKEN)")
+	NanoboxCmd.PersistentFlags().BoolVarP(&forceLock, "ignore-lock", "", false, "Run even if another nanobox command is running for the app")
+	NanoboxCmd.PersistentFlags().StringVarP(&outputMode, "output", "o", "text", "Print the results as text or json")
+	NanoboxCmd.PersistentFlags().BoolVarP(&quietMode, "quiet", "q", false, "Only print the results and the errors")
 	NanoboxCmd.PersistentFlags().BoolVarP(&internalCommand, "internal", "", false, "Skip pre-requisite checks")
 	NanoboxCmd.PersistentFlags().MarkHidden("internal")
 	NanoboxCmd.PersistentFlags().BoolVarP(&debugMode, "debug", "", false, "In the event of a failure, drop into debug context")
-	NanoboxCmd.PersistentFlags().BoolVarP(&displayDebugMode, "verbose", "v", false, "Increases display output and sets level to debug")
+	NanoboxCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "Increases display output, -v sets level to debug and -vv to trace")
 	NanoboxCmd.PersistentFlags().BoolVarP(&showVersion, "version", "", false, "Print version information and exit")
 	NanoboxCmd.PersistentFlags().BoolVarP(&displayTraceMode, "trace", "t", false, "Increases display output and sets level to trace")
+	NanoboxCmd.PersistentFlags().Var(&configOverrides, "config", "Set a configuration key for this command, ie: --config cpus=2")
+	NanoboxCmd.PersistentFlags().BoolVarP(&verifyMode, "verify", "", false, "Reject images and engines whose signatures don't check out (also the verify config)")
 
 	// log specific flags
 	LogCmd.Flags().BoolVarP(&logRaw, "raw", "r", false, "Print raw log timestamps instead")
@@ -134,26 +223,62 @@ func init() {
 
 	// subcommands
 	NanoboxCmd.AddCommand(ConfigureCmd)
+	NanoboxCmd.AddCommand(InitCmd)
+	NanoboxCmd.AddCommand(MigrateCmd)
 	NanoboxCmd.AddCommand(RunCmd)
+	NanoboxCmd.AddCommand(TestCmd)
+	NanoboxCmd.AddCommand(CICmd)
+	NanoboxCmd.AddCommand(GenerateCmd)
+	NanoboxCmd.AddCommand(GithooksCmd)
+	NanoboxCmd.AddCommand(IdleWatchCmd)
 	NanoboxCmd.AddCommand(BuildCmd)
+	NanoboxCmd.AddCommand(CacheCmd)
+	NanoboxCmd.AddCommand(FetchCmd)
 	NanoboxCmd.AddCommand(CompileCmd)
+	NanoboxCmd.AddCommand(ExportCmd)
 	NanoboxCmd.AddCommand(DeployCmd)
+	NanoboxCmd.AddCommand(PreviewCmd)
+	NanoboxCmd.AddCommand(RollbackCmd)
 	NanoboxCmd.AddCommand(ConsoleCmd)
+	NanoboxCmd.AddCommand(CpCmd)
 	NanoboxCmd.AddCommand(RemoteCmd)
 	NanoboxCmd.AddCommand(StatusCmd)
+	NanoboxCmd.AddCommand(AppsCmd)
+	NanoboxCmd.AddCommand(AppCmd)
+	NanoboxCmd.AddCommand(WorkspaceCmd)
 	NanoboxCmd.AddCommand(LoginCmd)
 	NanoboxCmd.AddCommand(LogoutCmd)
+	NanoboxCmd.AddCommand(TokenCmd)
+	NanoboxCmd.AddCommand(RegistryCmd)
 	NanoboxCmd.AddCommand(CleanCmd)
+	NanoboxCmd.AddCommand(VMCmd)
+	NanoboxCmd.AddCommand(DoctorCmd)
+	NanoboxCmd.AddCommand(HistoryCmd)
+	NanoboxCmd.AddCommand(CompletionCmd)
 	NanoboxCmd.AddCommand(InfoCmd)
+	NanoboxCmd.AddCommand(StatsCmd)
+	NanoboxCmd.AddCommand(DashboardCmd)
 	NanoboxCmd.AddCommand(TunnelCmd)
 	NanoboxCmd.AddCommand(ImplodeCmd)
 	NanoboxCmd.AddCommand(DestroyCmd)
+	NanoboxCmd.AddCommand(ArchiveCmd)
+	NanoboxCmd.AddCommand(UnarchiveCmd)
+	NanoboxCmd.AddCommand(StateCmd)
 	NanoboxCmd.AddCommand(StartCmd)
 	NanoboxCmd.AddCommand(StopCmd)
 	NanoboxCmd.AddCommand(UpdateCmd)
+	NanoboxCmd.AddCommand(UpdateCLICmd)
+	NanoboxCmd.AddCommand(TelemetryCmd)
+	NanoboxCmd.AddCommand(WebhookCmd)
+	NanoboxCmd.AddCommand(ValidateCmd)
+	NanoboxCmd.AddCommand(LintCmd)
+	NanoboxCmd.AddCommand(RenderCmd)
 	NanoboxCmd.AddCommand(EvarCmd)
+	NanoboxCmd.AddCommand(SecretCmd)
+	NanoboxCmd.AddCommand(CredsCmd)
 	NanoboxCmd.AddCommand(DnsCmd)
 	NanoboxCmd.AddCommand(LogCmd)
+	NanoboxCmd.AddCommand(LogsCmd)
 	NanoboxCmd.AddCommand(VersionCmd)
 	NanoboxCmd.AddCommand(server.ServerCmd)
 
@@ -161,3 +286,24 @@ func init() {
 	NanoboxCmd.AddCommand(EnvCmd)
 	NanoboxCmd.AddCommand(InspectCmd)
 }
+
+// configFlag sets configuration keys for the command that runs, it can be
+// repeated: --config cpus=2 --config ram=4
+type configFlag []string
+
+func (f *configFlag) String() string {
+	return strings.Join(*f, " ")
+}
+
+func (f *configFlag) Set(value string) error {
+	if err := models.SetConfigFlag(value); err != nil {
+		return err
+	}
+
+	*f = append(*f, value)
+	return nil
+}
+
+func (f *configFlag) Type() string {
+	return "key=value"
+}
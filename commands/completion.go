@@ -0,0 +1,217 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util/config"
+)
+
+var (
+
+	// CompletionCmd ...
+	CompletionCmd = &cobra.Command{
+		Use:   "completion <bash | zsh | fish | powershell>",
+		Short: "Generate the tab completion script of a shell.",
+		Long: `
+Prints the script that completes the commands, the flags, the
+names of your apps and the components of the current app, ie:
+
+  bash:       source <(nanobox completion bash)
+  zsh:        source <(nanobox completion zsh)
+  fish:       nanobox completion fish | source
+  powershell: nanobox completion powershell | Out-String | Invoke-Expression
+
+Add the line to your shell's profile to load it in every session.
+`,
+		PersistentPreRun: func(ccmd *cobra.Command, args []string) {},
+		Run:              completionFn,
+	}
+
+	// completeCmd prints the completions of a command line, the scripts call
+	// it on every tab, ie: nanobox __complete -- console d
+	completeCmd = &cobra.Command{
+		Use:              "__complete",
+		Hidden:           true,
+		PersistentPreRun: func(ccmd *cobra.Command, args []string) {},
+		Run:              completeFn,
+	}
+)
+
+func init() {
+	NanoboxCmd.AddCommand(completeCmd)
+}
+
+// completionFn ...
+func completionFn(ccmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Printf("\n! Please provide a shell, ie: nanobox completion bash\n\n")
+		ccmd.HelpFunc()(ccmd, args)
+		return
+	}
+
+	script, ok := completionScripts[args[0]]
+	if !ok {
+		fmt.Printf("\n! '%s' isn't supported, use bash, zsh, fish or powershell\n\n", args[0])
+		return
+	}
+
+	fmt.Print(script)
+}
+
+// completeFn prints the completions of the last word, one per line
+func completeFn(ccmd *cobra.Command, args []string) {
+	for _, candidate := range complete(NanoboxCmd, args) {
+		fmt.Println(candidate)
+	}
+}
+
+// complete returns what the last of the words can be completed with. The
+// words are the command line after 'nanobox', the last one is the word
+// being completed and may be empty.
+func complete(root *cobra.Command, words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+
+	cmd := root
+	positional := 0
+	skipValue := false
+
+	for _, word := range words[:len(words)-1] {
+		switch {
+		case skipValue:
+			skipValue = false
+		case strings.HasPrefix(word, "-"):
+			// the value of a flag is the next word, unless it's a switch
+			if flag := findFlag(cmd, word); flag != nil && !strings.Contains(word, "=") {
+				skipValue = flag.Value.Type() != "bool"
+			}
+		default:
+			if sub := findCommand(cmd, word); sub != nil && positional == 0 {
+				cmd = sub
+			} else {
+				positional++
+			}
+		}
+	}
+
+	// the values of flags aren't known
+	if skipValue {
+		return []string{}
+	}
+
+	partial := words[len(words)-1]
+	candidates := []string{}
+
+	if strings.HasPrefix(partial, "-") {
+		addFlag := func(flag *pflag.Flag) {
+			if flag.Hidden {
+				return
+			}
+			candidates = append(candidates, "--"+flag.Name)
+			if flag.Shorthand != "" {
+				candidates = append(candidates, "-"+flag.Shorthand)
+			}
+		}
+		cmd.LocalFlags().VisitAll(addFlag)
+		cmd.InheritedFlags().VisitAll(addFlag)
+	} else {
+		if positional == 0 {
+			for _, sub := range cmd.Commands() {
+				if !sub.Hidden {
+					candidates = append(candidates, sub.Name())
+				}
+			}
+		}
+		candidates = append(candidates, argCompletions(cmd, positional)...)
+	}
+
+	return matching(candidates, partial)
+}
+
+// argCompletions returns the names from the database a command takes as
+// arguments, going by its usage, ie: console [local | dry-run] <component.id>
+func argCompletions(cmd *cobra.Command, positional int) []string {
+	names := []string{}
+
+	if positional == 0 && strings.Contains(cmd.Use, "dry-run") {
+		if strings.Contains(cmd.Use, "local") {
+			names = append(names, "local")
+		}
+		names = append(names, "dry-run")
+
+		if strings.Contains(cmd.Use, "remote-alias") {
+			envModel, _ := models.FindEnvByID(config.EnvID())
+			for alias := range envModel.Remotes {
+				names = append(names, alias)
+			}
+		}
+	}
+
+	if strings.Contains(cmd.Use, "<component") {
+		appModel, _ := models.FindAppBySlug(config.EnvID(), "dev")
+		components, _ := appModel.Components()
+		for _, component := range components {
+			names = append(names, component.Name)
+		}
+	}
+
+	if positional == 0 && strings.Contains(cmd.Use, "<app>") {
+		envs, _ := models.AllEnvs()
+		for _, envModel := range envs {
+			names = append(names, envModel.Name)
+		}
+	}
+
+	return names
+}
+
+// findCommand returns the subcommand a word names
+func findCommand(cmd *cobra.Command, word string) *cobra.Command {
+	for _, sub := range cmd.Commands() {
+		if sub.Name() == word || sub.HasAlias(word) {
+			return sub
+		}
+	}
+
+	return nil
+}
+
+// findFlag returns the flag a word sets, ie: --output=json or -o
+func findFlag(cmd *cobra.Command, word string) *pflag.Flag {
+	name := strings.SplitN(strings.TrimLeft(word, "-"), "=", 2)[0]
+	long := strings.HasPrefix(word, "--")
+
+	var found *pflag.Flag
+	find := func(flag *pflag.Flag) {
+		if (long && flag.Name == name) || (!long && flag.Shorthand == name) {
+			found = flag
+		}
+	}
+	cmd.LocalFlags().VisitAll(find)
+	cmd.InheritedFlags().VisitAll(find)
+
+	return found
+}
+
+// matching returns the sorted, unique candidates that start with the prefix
+func matching(candidates []string, prefix string) []string {
+	seen := map[string]bool{}
+	matches := []string{}
+
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) && !seen[candidate] {
+			seen[candidate] = true
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+
+	return matches
+}
@@ -0,0 +1,64 @@
+package commands
+
+// completionScripts are the completion scripts of each shell. They hand the
+// command line to 'nanobox __complete', which knows the commands, the flags
+// and the names in the database.
+var completionScripts = map[string]string{
+	"bash": `# bash completion for nanobox
+_nanobox() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local words=("${COMP_WORDS[@]:1:COMP_CWORD}")
+
+    COMPREPLY=( $(compgen -W "$(nanobox __complete -- "${words[@]}" 2>/dev/null)" -- "$cur") )
+}
+
+complete -o default -F _nanobox nanobox
+`,
+
+	"zsh": `#compdef nanobox
+# zsh completion for nanobox
+_nanobox() {
+    local -a candidates
+    candidates=("${(@f)$(nanobox __complete -- "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+
+    compadd -- ${candidates:#}
+}
+
+compdef _nanobox nanobox
+`,
+
+	"fish": `# fish completion for nanobox
+function __nanobox_complete
+    set -l words (commandline -opc)
+    set -e words[1]
+    set -l cur (commandline -ct)
+
+    nanobox __complete -- $words "$cur" 2>/dev/null
+end
+
+complete -c nanobox -f -a '(__nanobox_complete)'
+`,
+
+	"powershell": `# powershell completion for nanobox
+Register-ArgumentCompleter -Native -CommandName nanobox -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $words = @()
+    foreach ($element in $commandAst.CommandElements | Select-Object -Skip 1) {
+        if ($element.Extent.StartOffset -ge $cursorPosition) {
+            break
+        }
+        $words += $element.ToString()
+    }
+
+    # an empty argument is dropped unless it's quoted
+    if ($wordToComplete -eq '') {
+        $words += '""'
+    }
+
+    nanobox __complete -- @words 2>$null | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`,
+}
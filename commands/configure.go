@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -21,6 +20,14 @@ var (
 		Long: `
 Walks through a series of question prompts that modify your local
 Nanobox configuration (~/.nanobox/config.yml).
+
+Each key is read from, in order of precedence:
+
+  --config <key>=<value> flags
+  NANOBOX_<KEY> environment variables, ie: NANOBOX_CPUS=2
+  the config section of the app's .nanobox/config.yml
+  the global configuration, ~/.nanobox/config.yml
+  the defaults
 		`,
 		Run:     configureFn,
 		Aliases: []string{"config"},
@@ -30,7 +37,9 @@ Nanobox configuration (~/.nanobox/config.yml).
 		Use:   "set <config-key> <config-value>",
 		Short: "Set a configuration key",
 		Long: `
-Set a key in the configuration
+Set a key in the global configuration, or with --app in the
+configuration of the app in the current directory, which is
+committed with the code.
 		`,
 		Run: configureSetFn,
 	}
@@ -48,11 +57,14 @@ Get a key from the configuration
 		Use:   "show",
 		Short: "Show the full configuration",
 		Long: `
-List the full configuration.
+List the full configuration and where each value comes from.
 		`,
 		Run:     configureListFn,
 		Aliases: []string{"list", "ls"},
 	}
+
+	// configureApp sets the key for the app instead of globally
+	configureApp bool
 )
 
 func init() {
@@ -62,6 +74,8 @@ func init() {
 	ConfigureCmd.AddCommand(ConfigureGetCmd)
 	ConfigureCmd.AddCommand(ConfigureListCmd)
 
+	ConfigureSetCmd.Flags().BoolVar(&configureApp, "app", false, "set the key for the app in the current directory")
+
 }
 
 // configureFn ...
@@ -75,7 +89,7 @@ func configureSetFn(ccmd *cobra.Command, args []string) {
 		fmt.Println("setting a key requires <key> <value>")
 		return
 	}
-	display.CommandErr(processors.ConfigureSet(args[0], args[1]))
+	display.CommandErr(processors.ConfigureSet(args[0], args[1], configureApp))
 }
 
 func configureGetFn(ccmd *cobra.Command, args []string) {
@@ -83,20 +97,11 @@ func configureGetFn(ccmd *cobra.Command, args []string) {
 		fmt.Println("what is the key you would like to see")
 		return
 	}
-	config, _ := models.LoadConfig()
-	jsonData, _ := json.Marshal(config)
-	configMap := map[string]interface{}{}
-	json.Unmarshal(jsonData, &configMap)
-	fmt.Println(configMap[args[0]])
-	return
-
+	display.CommandErr(processors.ConfigureGet(args[0]))
 }
 
 func configureListFn(ccmd *cobra.Command, args []string) {
-	config, _ := models.LoadConfig()
-	prettyJson, _ := json.MarshalIndent(config, "", "  ")
-	fmt.Printf("%s\n", prettyJson)
-	return
+	display.CommandErr(processors.ConfigureList())
 }
 
 func configureComplete() bool {
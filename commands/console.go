@@ -10,6 +10,7 @@ import (
 	"github.com/nanobox-io/nanobox/helpers"
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/processors/app"
 	"github.com/nanobox-io/nanobox/processors/env"
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/config"
@@ -21,16 +22,28 @@ var (
 
 	// ConsoleCmd ...
 	ConsoleCmd = &cobra.Command{
-		Use:   "console [<local | dry-run | {remote-alias}>] <component.id>",
+		Use:   "console [<local | dry-run | {remote-alias}>] <component>",
 		Short: "Open an interactive console inside a component.",
-		Long:  ``,
-		Run:   consoleFn,
+		Long: `
+Opens a shell inside a component, named in full, ie: data.db, or
+by the name after its prefix, ie: db. The terminal is resized with
+the window and the exit code of the shell is nanobox's.
+
+  --user    the user to console in as, gonano by default
+  --cmd     run a command instead of a shell, ie: for scripts:
+            nanobox console db --cmd 'pg_dump gonano' > dump.sql
+		`,
+		Run: consoleFn,
 	}
 	user string
+
+	// consoleCommand is run instead of a shell
+	consoleCommand string
 )
 
 func init() {
 	ConsoleCmd.Flags().StringVarP(&user, "user", "u", "", "user you would like to console in as")
+	ConsoleCmd.Flags().StringVarP(&consoleCommand, "cmd", "c", "", "run a command instead of a shell")
 }
 
 // consoleFn ...
@@ -54,17 +67,6 @@ ex: nanobox console dry-run web.site
 		return
 	}
 
-	if name == "dev" && isCode(strings.Join(args, " ")) {
-		display.ConsoleLocalCode()
-		display.CommandErr(util.Err{
-			Message: "Console to local code node not valid",
-			Code:    "USER",
-			Stack:   []string{"failed to console"},
-			Suggest: "It appears you are trying to console to a local code node. Please use `nanobox run` instead.",
-		})
-		return
-	}
-
 	switch location {
 	case "local":
 		appModel, _ := models.FindAppBySlug(config.EnvID(), name)
@@ -73,17 +75,30 @@ ex: nanobox console dry-run web.site
 			return
 		}
 
-		componentModel, _ := models.FindComponentBySlug(config.EnvID()+"_"+name, args[0])
-		// todo: determine ways this errors and handle it, use util.Err for suggestions
-		// componentModel, err := models.FindComponentBySlug(config.EnvID()+"_"+name, args[0])
-		// if err != nil {
-		// 	display.CommandErr(err)
-		// 	return
-		// }
+		componentModel, err := app.FindComponent(appModel, args[0])
+		if err != nil {
+			display.CommandErr(err)
+			return
+		}
 
-		display.CommandErr(env.Console(componentModel, console.ConsoleConfig{}))
+		if name == "dev" && isCode(componentModel.Name) {
+			display.ConsoleLocalCode()
+			display.CommandErr(util.Err{
+				Message: "Console to local code node not valid",
+				Code:    "USER",
+				Stack:   []string{"failed to console"},
+				Suggest: "It appears you are trying to console to a local code node. Please use `nanobox run` instead.",
+			})
+			return
+		}
+
+		display.CommandErr(env.Console(componentModel, console.ConsoleConfig{Command: consoleCommand}))
 
 	case "production":
+		if consoleCommand != "" {
+			display.CommandErr(util.Errorf("[USER] --cmd is only available on the local and dry-run components"))
+			return
+		}
 
 		consoleConfig := processors.ConsoleConfig{
 			App:  name,
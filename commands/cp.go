@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/helpers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// CpCmd ...
+	CpCmd = &cobra.Command{
+		Use:   "cp [local | dry-run] <src> <dest>",
+		Short: "Copy files between your machine and a component.",
+		Long: `
+Copies a file or a directory, recursively, between your machine and
+a component. The component side is written <component>:<path>,
+where the component is named like in 'nanobox console', ie:
+
+  nanobox cp local db:/tmp/core.1234 .
+  nanobox cp local ./seed.sql db:/tmp/
+
+Like cp, if the destination is a directory the source is copied
+into it, otherwise the source is copied as the destination.
+		`,
+		PreRun: steps.Run("start"),
+		Run:    cpFn,
+	}
+)
+
+// cpFn ...
+func cpFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	args, location, name := helpers.Endpoint(envModel, args, 3)
+
+	if len(args) != 2 {
+		fmt.Printf("\n! Please provide a source and a destination, ie: nanobox cp local db:/tmp/core.1234 .\n\n")
+		return
+	}
+
+	switch location {
+	case "local":
+		appModel, _ := models.FindAppBySlug(config.EnvID(), name)
+		if appModel.Status != "up" {
+			fmt.Println("unable to continue until the app is up")
+			return
+		}
+
+		srcComponent, srcPath := splitComponentPath(args[0])
+		destComponent, destPath := splitComponentPath(args[1])
+
+		if (srcComponent == "") == (destComponent == "") {
+			display.CommandErr(util.Errorf("[USER] one of the source and the destination has to be <component>:<path>, the other a path on your machine"))
+			return
+		}
+
+		component, toComponent := srcComponent, false
+		if destComponent != "" {
+			component, toComponent = destComponent, true
+		}
+
+		componentModel, err := app.FindComponent(appModel, component)
+		if err != nil {
+			display.CommandErr(err)
+			return
+		}
+
+		if name == "dev" && isCode(componentModel.Name) {
+			display.CommandErr(util.Errorf("[USER] the code of a local app is already on your machine, it is mounted in the container of 'nanobox run'"))
+			return
+		}
+
+		if toComponent {
+			display.CommandErr(app.CopyTo(componentModel, srcPath, destPath))
+			return
+		}
+		display.CommandErr(app.CopyFrom(componentModel, srcPath, destPath))
+
+	case "production":
+		fmt.Printf(`
+--------------------------------------------------------
+Copying files to production is not yet implemented.
+--------------------------------------------------------
+
+`)
+	}
+}
+
+// splitComponentPath splits <component>:<path>, the component is empty for
+// a path on the host. A drive letter, ie: C:\dumps, isn't a component.
+func splitComponentPath(arg string) (string, string) {
+	i := strings.Index(arg, ":")
+	if i < 1 || strings.ContainsAny(arg[:i], `/\`) {
+		return "", arg
+	}
+	if runtime.GOOS == "windows" && i == 1 {
+		return "", arg
+	}
+
+	return arg[:i], arg[i+1:]
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/helpers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/processors/component"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// CredsCmd ...
+	CredsCmd = &cobra.Command{
+		Use:   "creds",
+		Short: "Manage component credentials.",
+		Long: `
+Manages the credentials of your data components. Passwords are
+generated with the policy set by the 'password-length',
+'password-charset' and 'password-seed' configuration keys.
+		`,
+	}
+
+	// CredsRotateCmd ...
+	CredsRotateCmd = &cobra.Command{
+		Use:   "rotate [local | dry-run] <component>",
+		Short: "Generate new passwords for a component.",
+		Long: `
+Generates new passwords for the users of a component, updates the
+environment variables and reconfigures the component to use them.
+The code components are restarted to pick them up, after asking
+unless --restart is passed.
+		`,
+		PreRun: steps.Run("start"),
+		Run:    credsRotateFn,
+	}
+)
+
+func init() {
+	CredsRotateCmd.Flags().BoolVarP(&app.Restart, "restart", "", false, "restart the code components without asking")
+	CredsCmd.AddCommand(CredsRotateCmd)
+}
+
+// credsRotateFn ...
+func credsRotateFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	args, location, name := helpers.Endpoint(envModel, args, 2)
+
+	if len(args) != 1 {
+		fmt.Printf("\n! Please provide the component to rotate, ie: data.db\n\n")
+		return
+	}
+
+	switch location {
+	case "local":
+		appModel, _ := models.FindAppBySlug(config.EnvID(), name)
+		componentModel, err := models.FindComponentBySlug(appModel.ID, args[0])
+		if err != nil {
+			fmt.Printf("\n! Could not find the component '%s'\n\n", args[0])
+			return
+		}
+
+		// init docker client
+		display.CommandErr(provider.Init())
+		display.CommandErr(component.RotateCredentials(appModel, componentModel))
+		display.CommandErr(app.PropagateEvars(appModel))
+	case "production":
+		fmt.Printf(`
+-----------------------------------------------------------
+Rotating production credentials is not yet implemented.
+-----------------------------------------------------------
+
+`)
+	}
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/helpers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// DashboardCmd ...
+	DashboardCmd = &cobra.Command{
+		Use:   "dashboard [local | dry-run]",
+		Short: "Watch your app's components in a terminal dashboard.",
+		Long: `
+Shows every component of the specified environment with its state,
+IP, health and resource usage, and follows the log of the selected
+component.
+
+  up/down  select a component
+  r        restart the selected component
+  c        open a console in the selected component
+  q        quit
+		`,
+		PreRun: steps.Run("start"),
+		Run:    dashboardFn,
+	}
+)
+
+// dashboardFn ...
+func dashboardFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	args, location, name := helpers.Endpoint(envModel, args, 0)
+
+	switch location {
+	case "local":
+		appModel, _ := models.FindAppBySlug(config.EnvID(), name)
+		display.CommandErr(app.Dashboard(appModel))
+	case "production":
+		fmt.Printf(`
+--------------------------------------------------------
+The dashboard of production apps is not yet implemented.
+--------------------------------------------------------
+
+`)
+	}
+}
@@ -2,6 +2,8 @@ package commands
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -26,6 +28,12 @@ var (
 		Short: "Deploy your application to a live remote or a dry-run environment.",
 		Long:  ``,
 		PreRun: func(ccmd *cobra.Command, args []string) {
+			// a plan doesn't build or run anything
+			if deployCmdFlags.plan {
+				steps.Run("configure")(ccmd, args)
+				return
+			}
+
 			registry.Set("skip-compile", deployCmdFlags.skipCompile)
 			steps.Run("configure", "start", "build-runtime", "compile-app")(ccmd, args)
 		},
@@ -37,6 +45,8 @@ var (
 		skipCompile bool
 		message     string
 		force       bool
+		canary      string
+		plan        bool
 	}{}
 )
 
@@ -44,6 +54,8 @@ var (
 func init() {
 	DeployCmd.Flags().BoolVarP(&deployCmdFlags.skipCompile, "skip-compile", "", false, "skip compiling the app")
 	DeployCmd.Flags().BoolVarP(&deployCmdFlags.force, "force", "", false, "force the deploy even if you have used this build on a previous deploy")
+	DeployCmd.Flags().StringVarP(&deployCmdFlags.canary, "canary", "", "", "send a share of the web traffic to the new build before it goes live, ie: --canary 10%")
+	DeployCmd.Flags().BoolVarP(&deployCmdFlags.plan, "plan", "", false, "show what the deploy would create, update and destroy without deploying")
 	DeployCmd.Flags().StringVarP(&deployCmdFlags.message, "message", "m", "", "Allows you to append a message to the deploy. These messages appear in your app's deploy history in your dashboard.")
 }
 
@@ -52,15 +64,27 @@ func deployFn(ccmd *cobra.Command, args []string) {
 	envModel, _ := models.FindEnvByID(config.EnvID())
 	args, location, name := helpers.Endpoint(envModel, args, 1)
 
+	canary, err := parseCanary(deployCmdFlags.canary)
+	if err != nil {
+		fmt.Printf("\n! %s\n\n", err.Error())
+		return
+	}
+
+	if deployCmdFlags.plan {
+		planFn(ccmd, args, envModel, location, name)
+		return
+	}
+
 	switch location {
 	case "local":
 		switch name {
 		case "dev":
 			fmt.Println("deploying is not necessary in this context, 'nanobox run' instead")
 			return
-		case "sim":
+		default:
 			steps.Run("sim start")(ccmd, args)
-			appModel, _ := models.FindAppBySlug(envModel.ID, "sim")
+			appModel, _ := models.FindAppBySlug(envModel.ID, name)
+			app.Canary = canary
 			display.CommandErr(app.Deploy(envModel, appModel))
 			steps.Run("sim stop")(ccmd, args)
 		}
@@ -70,9 +94,44 @@ func deployFn(ccmd *cobra.Command, args []string) {
 			App:     name,
 			Message: deployCmdFlags.message,
 			Force:   deployCmdFlags.force,
+			Canary:  canary,
 		}
 
 		// set the meta arguments to be used in the processor and run the processor
 		display.CommandErr(processors.Deploy(envModel, deployConfig))
 	}
 }
+
+// planFn prints the plan of a deploy of the last build
+func planFn(ccmd *cobra.Command, args []string, envModel *models.Env, location, name string) {
+	if !buildComplete() {
+		fmt.Printf("\n! Your code changed since the last build, the plan is for the last build\n")
+	}
+
+	switch location {
+	case "local":
+		if name == "dev" {
+			fmt.Println("deploying is not necessary in this context, 'nanobox run' instead")
+			return
+		}
+		appModel, _ := models.FindAppBySlug(envModel.ID, name)
+		display.CommandErr(app.Plan(envModel, appModel))
+	case "production":
+		steps.Run("login")(ccmd, args)
+		display.CommandErr(processors.Plan(envModel, processors.DeployConfig{App: name}))
+	}
+}
+
+// parseCanary parses the share of the traffic for a canary, ie: 10%
+func parseCanary(share string) (int, error) {
+	if share == "" {
+		return 0, nil
+	}
+
+	percent, err := strconv.Atoi(strings.TrimSuffix(share, "%"))
+	if err != nil || percent < 1 || percent > 99 {
+		return 0, fmt.Errorf("The canary has to be a share between 1%% and 99%%, ie: --canary 10%%")
+	}
+
+	return percent, nil
+}
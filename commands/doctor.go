@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// DoctorCmd ...
+	DoctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "Find and repair state that's out of sync with the vm.",
+		Long: `
+Cross-checks the Nanobox database against the containers, the nat
+rules and the reserved IPs in the vm, and reports:
+
+  - containers of apps or components that no longer exist
+  - components whose container is gone
+  - nat rules of components that no longer exist, or missing the
+    other rule of their pair
+  - reserved IPs nothing uses
+
+With --fix the containers are removed, the components are destroyed
+so the next run or deploy creates them again, the nat rules are
+removed and the IPs are released.
+`,
+		PreRun: steps.Run("start"),
+		Run:    doctorFn,
+	}
+
+	// doctorFix repairs the problems
+	doctorFix bool
+)
+
+func init() {
+	DoctorCmd.Flags().BoolVarP(&doctorFix, "fix", "", false, "repair the problems that are found")
+}
+
+// doctorFn ...
+func doctorFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.Doctor(doctorFix))
+}
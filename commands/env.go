@@ -8,19 +8,26 @@ import (
 
 var (
 
-	// EnvCmd ...; currently a hidden command because its only used for one thing
+	// EnvCmd ...
 	EnvCmd = &cobra.Command{
 		Use:   "env",
-		Short: "Shared environment provisioning",
+		Short: "Manage the dry-run environments of your app.",
 		Long: `
-A namespaced collection of hidded subcommands used primarily as share provisioning processes.
+Manages the environments 'dry-run' can point at, ie: staging.
+Each environment runs separately from the others. The setup of
+an app can be shared with a team through .nanobox/config.yml.
 		`,
-		Hidden: true,
 	}
 )
 
 //
 func init() {
+	EnvCmd.AddCommand(env.UseCmd)
+	EnvCmd.AddCommand(env.ListCmd)
+	EnvCmd.AddCommand(env.RemoveCmd)
+	EnvCmd.AddCommand(env.ExportCmd)
+	EnvCmd.AddCommand(env.ImportCmd)
+
 	// hidden subcommands
 	EnvCmd.AddCommand(env.ServerCmd)
 }
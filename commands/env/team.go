@@ -0,0 +1,60 @@
+package env
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/env"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// ExportCmd ...
+	ExportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Write the shareable config of your app to .nanobox/config.yml.",
+		Long: `
+Writes the name, remotes, dry-run environments and dns aliases
+of your app to .nanobox/config.yml. Commit it and everyone on
+your team gets the same setup, evars are never exported. The
+boxfile overrides, tunnel ports and config keys you added to
+the file are kept.
+		`,
+		Run: exportFn,
+	}
+
+	// ImportCmd ...
+	ImportCmd = &cobra.Command{
+		Use:   "import",
+		Short: "Apply .nanobox/config.yml to your app.",
+		Long: `
+Applies .nanobox/config.yml to your app. Changes to the file are
+picked up automatically, except for the dns aliases which need
+administrative privileges and are only added by this command.
+You're asked before the remote, registry and other endpoints
+in the file are used, the ones you don't trust are left out.
+		`,
+		PreRun: steps.Run("start"),
+		Run:    importFn,
+	}
+)
+
+// exportFn ...
+func exportFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	display.CommandErr(env.ExportTeamConfig(envModel))
+}
+
+// importFn ...
+func importFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	if err := envModel.Generate(); err != nil {
+		display.CommandErr(err)
+		return
+	}
+
+	display.CommandErr(env.ImportTeamConfig(envModel))
+}
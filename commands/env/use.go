@@ -0,0 +1,73 @@
+package env
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/env"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// UseCmd ...
+	UseCmd = &cobra.Command{
+		Use:   "use <name>",
+		Short: "Point dry-run at an environment.",
+		Long: `
+Points 'dry-run' at an environment, ie: nanobox env use staging.
+Every environment has its own data, IPs, containers and evars,
+so several can run side by side. An environment is created the
+first time it is started, 'nanobox env use sim' goes back to
+the default one.
+		`,
+		Run: useFn,
+	}
+
+	// ListCmd ...
+	ListCmd = &cobra.Command{
+		Use:   "ls",
+		Short: "List the dry-run environments.",
+		Long:  ``,
+		Run:   listFn,
+	}
+
+	// RemoveCmd ...
+	RemoveCmd = &cobra.Command{
+		Use:   "rm <name>",
+		Short: "Destroy a dry-run environment.",
+		Long:  ``,
+		Run:   removeFn,
+	}
+)
+
+// useFn ...
+func useFn(ccmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Printf("\n! Please provide the environment to use, ie: nanobox env use staging\n\n")
+		return
+	}
+
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	display.CommandErr(env.UseDryRun(envModel, args[0]))
+}
+
+// listFn ...
+func listFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	display.CommandErr(env.ListDryRuns(envModel))
+}
+
+// removeFn ...
+func removeFn(ccmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Printf("\n! Please provide the environment to remove, ie: nanobox env rm staging\n\n")
+		return
+	}
+
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	display.CommandErr(env.RemoveDryRun(envModel, args[0]))
+}
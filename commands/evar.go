@@ -24,4 +24,5 @@ func init() {
 	EvarCmd.AddCommand(evar.LoadCmd)
 	EvarCmd.AddCommand(evar.RemoveCmd)
 	EvarCmd.AddCommand(evar.ListCmd)
+	EvarCmd.AddCommand(evar.ExportCmd)
 }
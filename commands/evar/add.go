@@ -11,18 +11,43 @@ import (
 	"github.com/nanobox-io/nanobox/commands/steps"
 	"github.com/nanobox-io/nanobox/helpers"
 	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
 	app_evar "github.com/nanobox-io/nanobox/processors/app/evar"
 	production_evar "github.com/nanobox-io/nanobox/processors/evar"
+	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/config"
 	"github.com/nanobox-io/nanobox/util/display"
 )
 
+// component scopes the evars to a component, see --component
+var component string
+
 // AddCmd ...
 var AddCmd = &cobra.Command{
 	Use:   "add [local|dry-run|remote-alias] key=val [key=val key=val]",
 	Short: "Adds environment variable(s)",
-	Long:  ``,
-	Run:   addFn,
+	Long: `Adds environment variable(s).
+
+A value can reference a secret in Vault instead, ie:
+
+  nanobox evar add API_KEY=vault:secret/data/app#api_key
+
+It is read when the containers start, with VAULT_ADDR and VAULT_TOKEN
+(or ~/.vault-token), and never stored by nanobox.
+
+A value can reference other evars, like the ones nanobox generates for
+the data components, ie:
+
+  nanobox evar add 'CACHE_URL=redis://${DATA_CACHE_HOST}:6379/0'
+
+They're expanded when the containers start, use $${NAME} for a literal
+${NAME}.`,
+	Run: addFn,
+}
+
+func init() {
+	AddCmd.Flags().StringVarP(&component, "component", "c", "", "only add to a component, ie: web.main")
+	AddCmd.Flags().BoolVarP(&app.Restart, "restart", "", false, "restart the code components without asking")
 }
 
 // addFn ...
@@ -46,14 +71,24 @@ func addFn(ccmd *cobra.Command, argss []string) {
 	switch location {
 	case "local":
 		app, _ := models.FindAppBySlug(config.EnvID(), name)
-		display.CommandErr(app_evar.Add(env, app, evars))
+		display.CommandErr(app_evar.Add(env, app, component, evars))
 	case "production":
+		if component != "" {
+			display.CommandErr(scopedErr())
+			return
+		}
+
 		steps.Run("login")(ccmd, args)
 
 		production_evar.Add(env, name, evars)
 	}
 }
 
+// scopedErr is the error for component evars on a live app
+func scopedErr() error {
+	return util.Errorf("[USER] evars can only be scoped to a component in local and dry-run environments")
+}
+
 // parseEvars parses evars already split into key="val" pairs.
 func parseEvars(args []string) map[string]string {
 	evars := map[string]string{}
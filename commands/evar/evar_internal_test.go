@@ -1,7 +1,11 @@
 package evar
 
 import (
+	"bytes"
+	"reflect"
 	"testing"
+
+	app_evar "github.com/nanobox-io/nanobox/processors/app/evar"
 )
 
 // TestEvarAdd tests the adding of environment variables from the cli.
@@ -56,6 +60,32 @@ func TestEvarLoad(t *testing.T) {
 	}
 }
 
+// TestEvarExportLoad tests that exported evars load back as they were.
+func TestEvarExportLoad(t *testing.T) {
+	exported := map[string]string{
+		"KEY1": "val",
+		"KEY2": "this\nis\na\nmultiline",
+		"KEY3": "you're \"welcome ;)",
+		"KEY4": "yes, even spaces and = are allowed as values",
+	}
+
+	buf := &bytes.Buffer{}
+	app_evar.WriteDotenv(buf, exported)
+
+	vars, _ := loadVars([]string{""}, stringGetter(buf.String()))
+	evars := parseEvars(vars)
+
+	if !reflect.DeepEqual(evars, exported) {
+		t.Fatalf("exported evars didn't load back - %q", evars)
+	}
+}
+
+type stringGetter string
+
+func (s stringGetter) getContents(filename string) (string, error) {
+	return string(s), nil
+}
+
 type testGetter struct{}
 
 func (f testGetter) getContents(filename string) (string, error) {
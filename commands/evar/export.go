@@ -0,0 +1,62 @@
+package evar
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/helpers"
+	"github.com/nanobox-io/nanobox/models"
+	app_evar "github.com/nanobox-io/nanobox/processors/app/evar"
+	production_evar "github.com/nanobox-io/nanobox/processors/evar"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// ExportCmd prints the evars as a .env file
+	ExportCmd = &cobra.Command{
+		Use:   "export [local|dry-run|remote-alias]",
+		Short: "Export environment variable(s) as a .env file",
+		Long: `Export environment variable(s) as a .env file, ie:
+
+  nanobox evar export > .env
+
+The variables nanobox generates, like the credentials of your data
+components, are left out unless --all is passed.`,
+		Run: exportFn,
+	}
+
+	// exportCmdFlags ...
+	exportCmdFlags = struct {
+		all bool
+	}{}
+)
+
+func init() {
+	ExportCmd.Flags().StringVarP(&component, "component", "c", "", "export the evars a component runs with, ie: web.main")
+	ExportCmd.Flags().BoolVarP(&exportCmdFlags.all, "all", "a", false, "include the generated variables")
+}
+
+// exportFn ...
+func exportFn(ccmd *cobra.Command, args []string) {
+	env, _ := models.FindEnvByID(config.EnvID())
+	args, location, name := helpers.Endpoint(env, args, 0)
+
+	switch location {
+	case "local":
+		app, _ := models.FindAppBySlug(config.EnvID(), name)
+		display.CommandErr(app_evar.Export(app, component, os.Stdout, exportCmdFlags.all))
+	case "production":
+		if component != "" {
+			display.CommandErr(scopedErr())
+			return
+		}
+
+		steps.Run("login")(ccmd, args)
+
+		display.CommandErr(production_evar.Export(env, name, os.Stdout))
+	}
+}
@@ -23,6 +23,10 @@ var ListCmd = &cobra.Command{
 	Run:   listFn,
 }
 
+func init() {
+	ListCmd.Flags().StringVarP(&component, "component", "c", "", "list the evars a component runs with, ie: web.main")
+}
+
 // listFn ...
 func listFn(ccmd *cobra.Command, args []string) {
 
@@ -41,8 +45,13 @@ func listFn(ccmd *cobra.Command, args []string) {
 	switch location {
 	case "local":
 		app, _ := models.FindAppBySlug(config.EnvID(), name)
-		display.CommandErr(app_evar.List(app))
+		display.CommandErr(app_evar.List(app, component))
 	case "production":
+		if component != "" {
+			display.CommandErr(scopedErr())
+			return
+		}
+
 		steps.Run("login")(ccmd, args)
 
 		env, _ := models.FindEnvByID(config.EnvID())
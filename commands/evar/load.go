@@ -11,6 +11,7 @@ import (
 	"github.com/nanobox-io/nanobox/commands/steps"
 	"github.com/nanobox-io/nanobox/helpers"
 	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
 	app_evar "github.com/nanobox-io/nanobox/processors/app/evar"
 	production_evar "github.com/nanobox-io/nanobox/processors/evar"
 	"github.com/nanobox-io/nanobox/util"
@@ -20,23 +21,37 @@ import (
 
 // LoadCmd loads variables from a file.
 var LoadCmd = &cobra.Command{
-	Use:   "load [local|dry-run|remote-alias] filename",
+	Use:   "load [local|dry-run|remote-alias] [filename]",
 	Short: "Loads environment variable(s) from a file",
 	Long: `Loads environment variable(s) from a file.
 
 The alias must be used when loading variables for a production app.
 If you would like to load variables for a different app, please add
 it as a remote as follows: 'nanobox remote add <APPNAME> <ALIAS>'.
-You may then perform the 'load' again, specifying that alias.`,
+You may then perform the 'load' again, specifying that alias.
+
+The file defaults to .env, the variables nanobox generates, like the
+credentials of your data components, are never overwritten.`,
 	Run: loadFn,
 }
 
+func init() {
+	LoadCmd.Flags().StringVarP(&component, "component", "c", "", "only load into a component, ie: web.main")
+	LoadCmd.Flags().BoolVarP(&app.Restart, "restart", "", false, "restart the code components without asking")
+}
+
 // loadFn parses a specified file and adds the contained variables to nanobox.
 // Read in the file, strip out 'export ', parse, add resulting vars
 func loadFn(ccmd *cobra.Command, args []string) {
 	// parse the evars excluding the context
 	env, _ := models.FindEnvByID(config.EnvID())
 	args, location, name := helpers.Endpoint(env, args, 0)
+
+	// the dotenv file in the project by default
+	if len(args) == 0 {
+		args = []string{".env"}
+	}
+
 	vars, err := loadVars(args, fileGetter{})
 	if err != nil {
 		display.CommandErr(util.Err{
@@ -52,8 +67,13 @@ func loadFn(ccmd *cobra.Command, args []string) {
 	switch location {
 	case "local":
 		app, _ := models.FindAppBySlug(config.EnvID(), name)
-		display.CommandErr(app_evar.Add(env, app, evars))
+		display.CommandErr(app_evar.Load(env, app, component, evars))
 	case "production":
+		if component != "" {
+			display.CommandErr(scopedErr())
+			return
+		}
+
 		steps.Run("login")(ccmd, args)
 
 		production_evar.Add(env, name, evars)
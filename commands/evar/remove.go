@@ -9,6 +9,7 @@ import (
 	"github.com/nanobox-io/nanobox/commands/steps"
 	"github.com/nanobox-io/nanobox/helpers"
 	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
 	app_evar "github.com/nanobox-io/nanobox/processors/app/evar"
 	production_evar "github.com/nanobox-io/nanobox/processors/evar"
 	"github.com/nanobox-io/nanobox/util/config"
@@ -28,6 +29,11 @@ You may then perform the 'rm' again, specifying that alias.`,
 	Run: removeFn,
 }
 
+func init() {
+	RemoveCmd.Flags().StringVarP(&component, "component", "c", "", "only remove from a component, ie: web.main")
+	RemoveCmd.Flags().BoolVarP(&app.Restart, "restart", "", false, "restart the code components without asking")
+}
+
 // removeFn ...
 func removeFn(ccmd *cobra.Command, args []string) {
 	// parse the evars excluding the context
@@ -50,8 +56,13 @@ Please provide the key you'd like to remove!
 	switch location {
 	case "local":
 		app, _ := models.FindAppBySlug(config.EnvID(), name)
-		display.CommandErr(app_evar.Remove(app, evars))
+		display.CommandErr(app_evar.Remove(app, component, evars))
 	case "production":
+		if component != "" {
+			display.CommandErr(scopedErr())
+			return
+		}
+
 		steps.Run("login")(ccmd, args)
 
 		env, _ := models.FindEnvByID(config.EnvID())
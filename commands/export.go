@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// ExportCmd ...
+	ExportCmd = &cobra.Command{
+		Use:   "export <dir | file.tar | file.tar.gz>",
+		Short: "Export the compiled application.",
+		Long: `
+Builds and compiles your application, then copies it out
+to the host so it can be shipped to CI systems or custom
+deploy targets. The export holds the compiled code in app/
+and the runtime in data/.
+		`,
+		PreRun: steps.Run("start", "build-runtime", "compile-app"),
+		Run:    exportFn,
+	}
+)
+
+// exportFn ...
+func exportFn(ccmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Printf("\n! Please provide where to export to, ie: nanobox export app.tar.gz\n\n")
+		return
+	}
+
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	display.CommandErr(processors.Export(envModel, args[0]))
+}
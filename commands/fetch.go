@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// FetchCmd ...
+	FetchCmd = &cobra.Command{
+		Use:   "fetch",
+		Short: "Pull the images and the engine the app needs, for working offline.",
+		Long: `
+Pulls every image the app needs and fetches its pinned engine,
+so the app can be set up on a plane or an air-gapped network.
+
+--pin pulls the images fresh and pins them to their digests in
+nanobox.lock, commit it so teammates run the same images. It
+tells which tags moved since they were last pinned.
+
+--export writes them to a bundle, and 'nanobox fetch --import'
+loads a bundle on a machine without a network.
+		`,
+		PreRun: steps.Run("start"),
+		Run:    fetchFn,
+	}
+
+	// fetchCmdFlags ...
+	fetchCmdFlags = struct {
+		export     string
+		importPath string
+		pin        bool
+	}{}
+)
+
+func init() {
+	FetchCmd.Flags().StringVarP(&fetchCmdFlags.export, "export", "", "", "Write the images and the engine to a bundle, ie: shop.tgz")
+	FetchCmd.Flags().StringVarP(&fetchCmdFlags.importPath, "import", "", "", "Load the images and the engine of a bundle")
+	FetchCmd.Flags().BoolVarP(&fetchCmdFlags.pin, "pin", "", false, "Pin the images to their digests in nanobox.lock")
+}
+
+// fetchFn ...
+func fetchFn(ccmd *cobra.Command, args []string) {
+	if fetchCmdFlags.importPath != "" {
+		display.CommandErr(processors.FetchImport(fetchCmdFlags.importPath))
+		return
+	}
+
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	display.CommandErr(processors.Fetch(envModel, fetchCmdFlags.export, fetchCmdFlags.pin))
+}
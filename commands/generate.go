@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/ciconfig"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// GenerateCmd ...
+	GenerateCmd = &cobra.Command{
+		Use:   "generate",
+		Short: "Generate configuration for the tools around nanobox.",
+		Long: `
+Generates configuration for the tools around nanobox, into the
+app in the current directory.
+		`,
+	}
+
+	// GenerateCICmd ...
+	GenerateCICmd = &cobra.Command{
+		Use:   "ci",
+		Short: "Generate the pipeline of a ci provider.",
+		Long: `
+Generates the pipeline of a ci provider, which installs nanobox
+and runs 'nanobox ci' on every push. The build cache is restored
+before the build and saved after the compile, keyed the way the
+provider caches best. ie:
+
+  nanobox generate ci --github --deploy production
+
+  --github      .github/workflows/nanobox.yml
+  --gitlab      .gitlab-ci.yml
+  --circle      .circleci/config.yml
+  --deploy      deploy the pushes to the branch to a remote alias
+  --branch      the branch that is deployed, main by default
+  --overwrite   replace an existing pipeline
+		`,
+		Run: generateCIFn,
+	}
+
+	// generateCICmdFlags ...
+	generateCICmdFlags = struct {
+		github    bool
+		gitlab    bool
+		circle    bool
+		deploy    string
+		branch    string
+		overwrite bool
+	}{}
+)
+
+func init() {
+	GenerateCICmd.Flags().BoolVarP(&generateCICmdFlags.github, "github", "", false, "generate a github actions workflow")
+	GenerateCICmd.Flags().BoolVarP(&generateCICmdFlags.gitlab, "gitlab", "", false, "generate a gitlab ci pipeline")
+	GenerateCICmd.Flags().BoolVarP(&generateCICmdFlags.circle, "circle", "", false, "generate a circleci config")
+	GenerateCICmd.Flags().StringVarP(&generateCICmdFlags.deploy, "deploy", "", "", "deploy the pushes to the branch to a remote alias")
+	GenerateCICmd.Flags().StringVarP(&generateCICmdFlags.branch, "branch", "", "main", "the branch that is deployed")
+	GenerateCICmd.Flags().BoolVarP(&generateCICmdFlags.overwrite, "overwrite", "", false, "replace an existing pipeline")
+
+	GenerateCmd.AddCommand(GenerateCICmd)
+}
+
+// generateCIFn ...
+func generateCIFn(ccmd *cobra.Command, args []string) {
+	providers := []string{}
+	for name, set := range map[string]bool{"github": generateCICmdFlags.github, "gitlab": generateCICmdFlags.gitlab, "circle": generateCICmdFlags.circle} {
+		if set {
+			providers = append(providers, name)
+		}
+	}
+
+	if len(providers) != 1 {
+		display.CommandErr(util.Errorf("[USER] choose one ci provider: --github, --gitlab or --circle"))
+		return
+	}
+
+	opts := ciconfig.Options{Deploy: generateCICmdFlags.deploy, Branch: generateCICmdFlags.branch}
+	display.CommandErr(processors.GenerateCI(config.LocalDir(), providers[0], opts, generateCICmdFlags.overwrite))
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// GithooksCmd ...
+	GithooksCmd = &cobra.Command{
+		Use:   "githooks",
+		Short: "Manage the git hooks that keep the runtime up to date.",
+		Long: `
+Manages the git hooks that notice when a checkout or a merge
+changes the boxfile or the dependency manifests.
+		`,
+	}
+
+	// GithooksInstallCmd ...
+	GithooksInstallCmd = &cobra.Command{
+		Use:   "install",
+		Short: "Install the post-checkout and post-merge hooks.",
+		Long: `
+Installs the post-checkout and post-merge hooks into the git repo
+of the app. When a checkout or a merge changes the boxfile or the
+dependency manifests, the hooks ask to rebuild the runtime, and a
+running local environment gets the data components the new
+boxfile adds or changes. With --auto they don't ask. Hooks that
+were there before are kept and run first.
+		`,
+		Run: githooksInstallFn,
+	}
+
+	// GithooksUninstallCmd ...
+	GithooksUninstallCmd = &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove the git hooks.",
+		Long: `
+Removes the hooks and puts back the ones they replaced.
+		`,
+		Run: githooksUninstallFn,
+	}
+
+	// GithooksRunCmd is what the hooks run
+	GithooksRunCmd = &cobra.Command{
+		Use:    "run <hook> -- <hook args>",
+		Short:  "Run a git hook.",
+		Long:   ``,
+		Hidden: true,
+		Run:    githooksRunFn,
+	}
+
+	// githooksCmdFlags ...
+	githooksCmdFlags = struct {
+		auto bool
+	}{}
+)
+
+func init() {
+	GithooksInstallCmd.Flags().BoolVarP(&githooksCmdFlags.auto, "auto", "", false, "rebuild without asking")
+	GithooksRunCmd.Flags().BoolVarP(&githooksCmdFlags.auto, "auto", "", false, "rebuild without asking")
+
+	GithooksCmd.AddCommand(GithooksInstallCmd)
+	GithooksCmd.AddCommand(GithooksUninstallCmd)
+	GithooksCmd.AddCommand(GithooksRunCmd)
+}
+
+// githooksInstallFn ...
+func githooksInstallFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.GithooksInstall(githooksCmdFlags.auto))
+}
+
+// githooksUninstallFn ...
+func githooksUninstallFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.GithooksUninstall())
+}
+
+// githooksRunFn ...
+func githooksRunFn(ccmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		fmt.Printf("\n! Please provide the hook to run, ie: nanobox githooks run post-merge\n\n")
+		return
+	}
+
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	display.CommandErr(processors.GithooksRun(envModel, args[0], args[1:], githooksCmdFlags.auto))
+}
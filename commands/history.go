@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// HistoryCmd ...
+	HistoryCmd = &cobra.Command{
+		Use:   "history",
+		Short: "Show the commands that changed your apps.",
+		Long: `
+Shows who ran the commands that changed the app in the current
+directory, when they ran, how long they took and whether they
+failed. The values of evars and secrets are left out.
+
+The audit log is kept in ~/.nanobox/audit.log.
+`,
+		Run: historyFn,
+	}
+
+	// historyAll shows the commands of every app
+	historyAll bool
+
+	// historyNumber limits the number of commands shown
+	historyNumber int
+)
+
+func init() {
+	HistoryCmd.Flags().BoolVarP(&historyAll, "all", "a", false, "show the commands of every app")
+	HistoryCmd.Flags().IntVarP(&historyNumber, "number", "n", 20, "number of commands to show, 0 shows all")
+}
+
+// historyFn ...
+func historyFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.History(historyAll, historyNumber))
+}
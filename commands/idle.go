@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// IdleWatchCmd ...
+	IdleWatchCmd = &cobra.Command{
+		Use:    "idle-watch",
+		Short:  "Suspend the apps that are idle.",
+		Long:   ``,
+		Hidden: true,
+		Run:    idleWatchFn,
+	}
+)
+
+// idleWatchFn ...
+func idleWatchFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.IdleWatch())
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// RegistryCmd ...
+	RegistryCmd = &cobra.Command{
+		Use:   "registry",
+		Short: "Manage private docker registry credentials.",
+		Long: `
+Manages the credentials used to pull images from private docker
+registries. Any image in your boxfile that lives on a registry you
+have logged in to will be pulled with the stored credentials.
+		`,
+	}
+
+	// RegistryLoginCmd ...
+	RegistryLoginCmd = &cobra.Command{
+		Use:   "login [host]",
+		Short: "Store credentials for a docker registry.",
+		Long: `
+Verifies and stores credentials for a docker registry. If no host is
+provided, Docker Hub (docker.io) is assumed. The password is encrypted
+before it is stored.
+		`,
+		PreRun: steps.Run("start"),
+		Run:    registryLoginFn,
+	}
+
+	// RegistryLogoutCmd ...
+	RegistryLogoutCmd = &cobra.Command{
+		Use:   "logout [host]",
+		Short: "Remove the credentials of a docker registry.",
+		Long:  ``,
+		Run:   registryLogoutFn,
+	}
+
+	// RegistryListCmd ...
+	RegistryListCmd = &cobra.Command{
+		Use:   "ls",
+		Short: "List the registries with stored credentials.",
+		Long:  ``,
+		Run:   registryListFn,
+	}
+
+	// registryLoginCmdFlags ...
+	registryLoginCmdFlags = struct {
+		username string
+		password string
+	}{}
+)
+
+func init() {
+	RegistryLoginCmd.Flags().StringVarP(&registryLoginCmdFlags.username, "username", "u", "", "username")
+	RegistryLoginCmd.Flags().StringVarP(&registryLoginCmdFlags.password, "password", "p", "", "password")
+
+	RegistryCmd.AddCommand(RegistryLoginCmd)
+	RegistryCmd.AddCommand(RegistryLogoutCmd)
+	RegistryCmd.AddCommand(RegistryListCmd)
+}
+
+// registryLoginFn ...
+func registryLoginFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.RegistryLogin(registryHost(args), registryLoginCmdFlags.username, registryLoginCmdFlags.password))
+}
+
+// registryLogoutFn ...
+func registryLogoutFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.RegistryLogout(registryHost(args)))
+}
+
+// registryListFn ...
+func registryListFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.RegistryList())
+}
+
+// registryHost returns the host argument, if provided
+func registryHost(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	return args[0]
+}
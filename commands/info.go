@@ -19,8 +19,10 @@ var (
 		Use:   "info [local | dry-run]",
 		Short: "Show information about the specified environment.",
 		Long: `
-Shows information about the specified environment. You must
-specify which environment you would like information about.
+Shows everything about the specified environment in one place:
+the provider status, the mounted code, the urls of the app and the
+IPs, ports and credentials of its components. Use '--output json'
+to read it from a script.
 `,
 		Run: infoFn,
 	}
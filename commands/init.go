@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// InitCmd ...
+	InitCmd = &cobra.Command{
+		Use:   "init",
+		Short: "Generate a boxfile.yml for the app.",
+		Long: `
+Generates a boxfile.yml for the app in the current directory, from
+the docker-compose.yml or Vagrantfile it finds, as 'nanobox migrate'
+does.
+
+With --from-compose, the services of a docker-compose file are
+translated: services built from the project become web components
+with their ports and shared volumes, known databases become data
+components and any other image runs as a sidecar. The environment of
+the app is written to nanobox.env, load it with
+'nanobox evar load local nanobox.env'. Anything that couldn't be
+translated is listed in nanobox-migration.txt.
+
+With --template rails|django|node|go, a new app gets a boxfile.yml
+for its framework. The data components it can use are asked for,
+or given with --service, ie: --service postgres,redis. The files
+nanobox writes into the app are added to its .gitignore.
+		`,
+		Run: initFn,
+	}
+
+	// initCmdFlags ...
+	initCmdFlags = struct {
+		fromCompose string
+		template    string
+		services    []string
+	}{}
+)
+
+func init() {
+	InitCmd.Flags().StringVarP(&initCmdFlags.fromCompose, "from-compose", "", "", "translate a docker-compose file, ie: docker-compose.yml")
+	InitCmd.Flags().StringVarP(&initCmdFlags.template, "template", "", "", "generate the boxfile of a framework: rails, django, node or go")
+	InitCmd.Flags().StringSliceVarP(&initCmdFlags.services, "service", "", nil, "the data components of the template, instead of asking")
+}
+
+// initFn ...
+func initFn(ccmd *cobra.Command, args []string) {
+	if initCmdFlags.template == "" {
+		display.CommandErr(processors.Migrate(config.LocalDir(), initCmdFlags.fromCompose))
+		return
+	}
+
+	if initCmdFlags.fromCompose != "" {
+		display.CommandErr(util.Errorf("[USER] --template and --from-compose can't be used together"))
+		return
+	}
+
+	ask := !ccmd.Flags().Changed("service")
+	display.CommandErr(processors.Scaffold(config.LocalDir(), initCmdFlags.template, initCmdFlags.services, ask))
+}
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// LintCmd ...
+	LintCmd = &cobra.Command{
+		Use:   "lint",
+		Short: "Checks the boxfile.yml for risky patterns.",
+		Long: `
+Validates the boxfile.yml, as 'nanobox validate' does, and checks
+it for patterns that work but are risky. Each problem names its
+rule:
+
+  unpinned-engine      the engine isn't pinned to a version
+  unpinned-image       an image has no tag, or is 'latest'
+  world-open-port      a database, ssh or docker port is public
+  huge-memory          a memory limit over 8g
+  missing-healthcheck  a web component has no healthcheck
+  deprecated-node      a node of the first version of the boxfile
+
+Errors fail the command. With --strict, for CI, warnings do too,
+suggestions (info) never do.
+		`,
+		Run: lintFn,
+	}
+
+	// lintCmdFlags ...
+	lintCmdFlags = struct {
+		strict bool
+	}{}
+)
+
+func init() {
+	LintCmd.Flags().BoolVarP(&lintCmdFlags.strict, "strict", "", false, "fail on warnings too")
+}
+
+// lintFn ...
+func lintFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.Lint(lintCmdFlags.strict))
+}
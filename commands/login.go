@@ -7,6 +7,7 @@ import (
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/processors"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/odin"
 )
 
 var (
@@ -19,6 +20,12 @@ var (
 Authenticate with your nanobox account by passing the username and password in or using the following environment variables:
 NANOBOX_USERNAME
 NANOBOX_PASSWORD
+
+CI pipelines can skip the login and authenticate every command with an api
+token, set in NANOBOX_TOKEN or passed with --token.
+
+If your org requires single sign-on or two-factor authentication, use --sso
+to log in through the dashboard in your browser instead.
 `,
 		Run: loginFn,
 	}
@@ -28,6 +35,7 @@ NANOBOX_PASSWORD
 		username string
 		password string
 		endpoint string
+		sso      bool
 	}{}
 )
 
@@ -36,18 +44,29 @@ func init() {
 	LoginCmd.Flags().StringVarP(&loginCmdFlags.username, "username", "u", "", "username")
 	LoginCmd.Flags().StringVarP(&loginCmdFlags.password, "password", "p", "", "password")
 	LoginCmd.Flags().StringVarP(&loginCmdFlags.endpoint, "endpoint", "e", "", "endpoint")
+	LoginCmd.Flags().BoolVarP(&loginCmdFlags.sso, "sso", "", false, "log in through the browser")
 
 	steps.Build("login", loginCheck, loginFn)
 }
 
 // loginFn ...
 func loginFn(ccmd *cobra.Command, args []string) {
+	if loginCmdFlags.sso {
+		display.CommandErr(processors.LoginSSO(loginCmdFlags.endpoint))
+		return
+	}
+
 	err := processors.Login(loginCmdFlags.username, loginCmdFlags.password, loginCmdFlags.endpoint)
 
 	display.CommandErr(err)
 }
 
 func loginCheck() bool {
+	// a token replaces the login
+	if odin.Token() != "" {
+		return true
+	}
+
 	auth, _ := models.LoadAuth()
 	return auth.Key != ""
 }
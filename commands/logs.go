@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/helpers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/logs"
+)
+
+var (
+
+	// LogsCmd ...
+	LogsCmd = &cobra.Command{
+		Use:   "logs [local|dry-run] [component]",
+		Short: "Show the logs of your app's components.",
+		Long: `
+Shows the output of the containers of the components of the local
+(default) or dry-run environment, with the output of the hooks run
+in them, in order. Each component has a prefix of its own color.
+
+The logs of a container are stored in ~/.nanobox/apps/<app>/logs
+when it's removed, so they're still shown once it's recreated. The
+files are rotated at 10MB, the last 5 are kept.
+
+A component is named in full, ie: data.db, or by the name after
+its prefix, ie: db. For the logs of a remote app, use 'nanobox log'.
+
+  --follow     keep showing the new lines
+  --since      how far back to go, ie: 10m, yesterday or
+               2017-03-02T15:04:05Z
+  --grep       only the lines that match a regular expression
+  --forward    also ship the lines to the sinks of the
+               log-forward config, ie: syslog://localhost:514
+		`,
+		PreRun: steps.Run("start"),
+		Run:    logsFn,
+	}
+
+	// logsCmdFlags ...
+	logsCmdFlags = struct {
+		follow     bool
+		since      string
+		grep       string
+		timestamps bool
+		forward    bool
+	}{}
+)
+
+func init() {
+	LogsCmd.Flags().BoolVarP(&logsCmdFlags.follow, "follow", "f", false, "keep showing the new lines")
+	LogsCmd.Flags().StringVarP(&logsCmdFlags.since, "since", "", "", "how far back to go, ie: 10m or yesterday")
+	LogsCmd.Flags().StringVarP(&logsCmdFlags.grep, "grep", "g", "", "only show the lines that match a pattern")
+	LogsCmd.Flags().BoolVarP(&logsCmdFlags.timestamps, "timestamps", "", false, "show the time of each line")
+	LogsCmd.Flags().BoolVarP(&logsCmdFlags.forward, "forward", "", false, "also ship the lines to the log-forward sinks")
+}
+
+// logsFn ...
+func logsFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+
+	// the logs are of the local environment unless another one is given
+	name := "dev"
+	if len(args) > 0 && (args[0] == "local" || args[0] == "dry-run") {
+		args, _, name = helpers.Endpoint(envModel, args, 2)
+	}
+
+	component := ""
+	if len(args) > 0 {
+		component = args[0]
+	}
+
+	since, err := logs.ParseSince(logsCmdFlags.since, time.Now())
+	if err != nil {
+		display.CommandErr(util.Errorf("[USER] invalid --since, %s", err.Error()))
+		return
+	}
+
+	filter := logs.Filter{Since: since, Follow: logsCmdFlags.follow}
+	if logsCmdFlags.grep != "" {
+		filter.Grep, err = regexp.Compile(logsCmdFlags.grep)
+		if err != nil {
+			display.CommandErr(util.Errorf("[USER] invalid --grep, %s", err.Error()))
+			return
+		}
+	}
+
+	appModel, _ := models.FindAppBySlug(config.EnvID(), name)
+	display.CommandErr(app.Logs(appModel, component, filter, logsCmdFlags.timestamps, logsCmdFlags.forward))
+}
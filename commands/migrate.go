@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// MigrateCmd ...
+	MigrateCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "Generate a boxfile from a Vagrantfile or docker-compose.yml.",
+		Long: `
+Inspects the Vagrantfile or docker-compose.yml in the current
+directory, translates its services and provisioning steps into a
+boxfile.yml and writes a report of anything that couldn't be
+translated to nanobox-migration.txt.
+		`,
+		Run: migrateFn,
+	}
+
+	// migrateCmdFlags ...
+	migrateCmdFlags = struct {
+		start bool
+	}{}
+)
+
+func init() {
+	MigrateCmd.Flags().BoolVarP(&migrateCmdFlags.start, "start", "", false, "start the local environment after migrating")
+}
+
+// migrateFn ...
+func migrateFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.Migrate(config.LocalDir(), ""))
+
+	if !migrateCmdFlags.start {
+		return
+	}
+
+	// run the first start as 'nanobox run' would
+	RunCmd.PreRun(ccmd, []string{})
+	runFn(ccmd, []string{})
+	RunCmd.PostRun(ccmd, []string{})
+}
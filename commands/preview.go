@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// PreviewCmd ...
+	PreviewCmd = &cobra.Command{
+		Use:   "preview",
+		Short: "Deploy the current git branch to its own preview environment.",
+		Long: `
+Builds the app and deploys it to the preview environment of the
+current git branch, ie: preview-feature-login. Every branch gets
+its own IPs, data and hostname, ie: feature-login.blog.nanobox.dev,
+so several branches can be previewed side by side. A preview keeps
+running until 'nanobox preview clean' reaps it.
+		`,
+		PreRun: steps.Run("configure", "start", "build-runtime", "compile-app"),
+		Run:    previewFn,
+	}
+
+	// PreviewCleanCmd ...
+	PreviewCleanCmd = &cobra.Command{
+		Use:   "clean",
+		Short: "Destroy the stale preview environments.",
+		Long: `
+Destroys the preview environments of the branches that no longer
+exist locally or on a remote, and the ones that weren't previewed
+for longer than --older-than, with their data and hostnames.
+		`,
+		Run: previewCleanFn,
+	}
+
+	// previewCmdFlags ...
+	previewCmdFlags = struct {
+		branch    string
+		olderThan time.Duration
+		all       bool
+	}{}
+)
+
+func init() {
+	PreviewCmd.Flags().StringVarP(&previewCmdFlags.branch, "branch", "b", "", "preview this branch instead of the current one")
+	PreviewCleanCmd.Flags().DurationVarP(&previewCmdFlags.olderThan, "older-than", "", 14*24*time.Hour, "destroy the previews not deployed for this long, 0 keeps them")
+	PreviewCleanCmd.Flags().BoolVarP(&previewCmdFlags.all, "all", "", false, "destroy every preview environment")
+
+	PreviewCmd.AddCommand(PreviewCleanCmd)
+}
+
+// previewFn ...
+func previewFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	display.CommandErr(processors.Preview(envModel, previewCmdFlags.branch))
+}
+
+// previewCleanFn ...
+func previewCleanFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	display.CommandErr(processors.PreviewClean(envModel, previewCmdFlags.olderThan, previewCmdFlags.all))
+}
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/helpers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// RenderCmd ...
+	RenderCmd = &cobra.Command{
+		Use:   "render [local|dry-run|remote-alias]",
+		Short: "Preview the boxfile.yml of an environment.",
+		Long: `
+Prints the boxfile.yml as the environment is deployed with it, local
+by default: the process types of the Procfile are added, the overlay
+of the environment, ie: boxfile.dev.yml, is merged onto it and its
+variables are resolved.
+
+A variable, ie: image: nanobox/postgresql:${PG_VERSION:-9.6}, is
+resolved from the environment of the host first, then from the evars
+of the app and falls back to its default. The commands, like start
+and extra_steps, are left to the shell that runs them.
+		`,
+		Run: renderFn,
+	}
+)
+
+// renderFn ...
+func renderFn(ccmd *cobra.Command, args []string) {
+	env, _ := models.FindEnvByID(config.EnvID())
+
+	// the local environment is the one worked on
+	if len(args) == 0 {
+		args = []string{"local"}
+	}
+
+	_, location, name := helpers.Endpoint(env, args, 1)
+
+	display.CommandErr(processors.Render(env, location, name))
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/helpers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// RollbackCmd ...
+	RollbackCmd = &cobra.Command{
+		Use:   "rollback [dry-run|remote-alias]",
+		Short: "Roll back to the previous deploy.",
+		Long: `
+Deploys the build that ran before the current deploy
+again. Rolling back again goes further back in the
+deploy history.
+		`,
+		PreRun: steps.Run("start"),
+		Run:    rollbackFn,
+	}
+
+	// rollbackCmdFlags ...
+	rollbackCmdFlags = struct {
+		message string
+	}{}
+)
+
+func init() {
+	RollbackCmd.Flags().StringVarP(&rollbackCmdFlags.message, "message", "m", "", "Allows you to append a message to the rollback.")
+}
+
+// rollbackFn ...
+func rollbackFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	args, location, name := helpers.Endpoint(envModel, args, 1)
+
+	switch location {
+	case "local":
+		switch name {
+		case "dev":
+			fmt.Println("rolling back is not necessary in this context, 'nanobox run' instead")
+			return
+		default:
+			steps.Run("sim start")(ccmd, args)
+			appModel, _ := models.FindAppBySlug(envModel.ID, name)
+			display.CommandErr(app.Rollback(appModel))
+			steps.Run("sim stop")(ccmd, args)
+		}
+	case "production":
+		steps.Run("login")(ccmd, args)
+		deployConfig := processors.DeployConfig{
+			App:     name,
+			Message: rollbackCmdFlags.message,
+		}
+
+		display.CommandErr(processors.Rollback(envModel, deployConfig))
+	}
+}
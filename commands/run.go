@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -9,6 +10,7 @@ import (
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/processors"
 	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/processors/component"
 	"github.com/nanobox-io/nanobox/util/config"
 	"github.com/nanobox-io/nanobox/util/console"
 	"github.com/nanobox-io/nanobox/util/display"
@@ -28,18 +30,39 @@ interactive console inside the environment.
 You can also pass a command into 'run'. Nanobox will
 run the command without dropping you into a console
 in your local environment.
+
+To run a one-off job, such as a migration, name the
+component before '--'. The command runs in a fresh
+container with the app evars, which is removed when
+the command exits:
+
+  nanobox run web.site -- rake db:migrate
 	`,
 	PreRun:  steps.Run("start", "build-runtime", "dev start", "dev deploy"),
 	Run:     runFn,
 	PostRun: steps.Run("dev stop"),
 }
 
+// runWatch rebuilds the runtime when the build inputs change
+var runWatch bool
+
 // runFn ...
 func runFn(ccmd *cobra.Command, args []string) {
+	processors.RunWatch = runWatch
 
 	envModel, _ := models.FindEnvByID(config.EnvID())
 	appModel, _ := models.FindAppBySlug(config.EnvID(), "dev")
 
+	// run a one-off job, ie: nanobox run web.site -- rake db:migrate
+	if ccmd.ArgsLenAtDash() == 1 {
+		if len(args) < 2 {
+			fmt.Printf("\n! Please provide a command to run, ie: nanobox run web.site -- rake db:migrate\n\n")
+			return
+		}
+		display.CommandErr(processors.RunJob(appModel, args[0], args[1:]))
+		return
+	}
+
 	consoleConfig := console.ConsoleConfig{}
 
 	if len(args) > 0 {
@@ -52,6 +75,8 @@ func runFn(ccmd *cobra.Command, args []string) {
 
 func init() {
 	steps.Build("dev deploy", devDeployComplete, devDeploy)
+
+	RunCmd.Flags().BoolVar(&runWatch, "watch", false, "Rebuild the runtime when the boxfile or the dependency manifests change.")
 }
 
 // devDeploy ...
@@ -64,5 +89,7 @@ func devDeploy(ccmd *cobra.Command, args []string) {
 func devDeployComplete() bool {
 	app, _ := models.FindAppBySlug(config.EnvID(), "dev")
 	env, _ := app.Env()
-	return app.DeployedBoxfile != "" && env.BuiltBoxfile == app.DeployedBoxfile && buildComplete()
+	// the overlay, the Procfile or a variable may have changed since
+	box, err := component.Boxfile(env, app)
+	return app.DeployedBoxfile != "" && err == nil && box == app.DeployedBoxfile && buildComplete()
 }
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/secret"
+)
+
+var (
+
+	// SecretCmd ...
+	SecretCmd = &cobra.Command{
+		Use:   "secret",
+		Short: "Manage encrypted secrets.",
+		Long: `
+Manages secrets, like api keys, in your local and dry-run environments.
+Secrets are encrypted at rest, kept apart from the evars and only exist
+in the environment of the containers.
+		`,
+	}
+)
+
+func init() {
+	SecretCmd.AddCommand(secret.SetCmd)
+	SecretCmd.AddCommand(secret.ListCmd)
+	SecretCmd.AddCommand(secret.RemoveCmd)
+}
@@ -0,0 +1,33 @@
+package secret
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/helpers"
+	"github.com/nanobox-io/nanobox/models"
+	app_secret "github.com/nanobox-io/nanobox/processors/app/secret"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// ListCmd lists the secrets
+var ListCmd = &cobra.Command{
+	Use:   "ls [local|dry-run]",
+	Short: "List secret(s), without their values",
+	Long:  ``,
+	Run:   listFn,
+}
+
+// listFn ...
+func listFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	_, location, name := helpers.Endpoint(envModel, args, 0)
+
+	if location != "local" {
+		display.CommandErr(productionErr())
+		return
+	}
+
+	appModel, _ := models.FindAppBySlug(config.EnvID(), name)
+	display.CommandErr(app_secret.List(appModel))
+}
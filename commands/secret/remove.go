@@ -0,0 +1,47 @@
+package secret
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/helpers"
+	"github.com/nanobox-io/nanobox/models"
+	app_secret "github.com/nanobox-io/nanobox/processors/app/secret"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// RemoveCmd removes secrets
+var RemoveCmd = &cobra.Command{
+	Use:   "rm [local|dry-run] key [key]",
+	Short: "Remove secret(s)",
+	Long:  ``,
+	Run:   removeFn,
+}
+
+// removeFn ...
+func removeFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	args, location, name := helpers.Endpoint(envModel, args, 0)
+
+	if len(args) < 1 {
+		fmt.Printf("\n! Please provide the key you'd like to remove\n\n")
+		ccmd.HelpFunc()(ccmd, args)
+		return
+	}
+
+	if location != "local" {
+		display.CommandErr(productionErr())
+		return
+	}
+
+	keys := []string{}
+	for _, arg := range args {
+		keys = append(keys, strings.ToUpper(arg))
+	}
+
+	appModel, _ := models.FindAppBySlug(config.EnvID(), name)
+	display.CommandErr(app_secret.Remove(appModel, keys))
+}
@@ -0,0 +1,88 @@
+package secret
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/helpers"
+	"github.com/nanobox-io/nanobox/models"
+	app_secret "github.com/nanobox-io/nanobox/processors/app/secret"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// SetCmd sets secrets
+var SetCmd = &cobra.Command{
+	Use:   "set [local|dry-run] key[=val] [key[=val]]",
+	Short: "Set secret(s)",
+	Long: `Set secret(s).
+
+Leave the value out to type it in without it showing up in your shell
+history. The containers pick up the secrets the next time they start.`,
+	Run: setFn,
+}
+
+// setFn ...
+func setFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	args, location, name := helpers.Endpoint(envModel, args, 0)
+
+	if len(args) < 1 {
+		fmt.Printf("\n! Please provide the secret(s) you'd like to set\n\n")
+		ccmd.HelpFunc()(ccmd, args)
+		return
+	}
+
+	if location != "local" {
+		display.CommandErr(productionErr())
+		return
+	}
+
+	values, err := parseSecrets(args)
+	if err != nil {
+		display.CommandErr(err)
+		return
+	}
+
+	appModel, _ := models.FindAppBySlug(config.EnvID(), name)
+	display.CommandErr(app_secret.Set(envModel, appModel, values))
+}
+
+// parseSecrets parses key=val pairs, the value of a bare key is read from
+// the terminal
+func parseSecrets(args []string) (map[string]string, error) {
+	values := map[string]string{}
+
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if parts[0] == "" {
+			return nil, util.Errorf("[USER] '%s' has no key", arg)
+		}
+
+		key := strings.ToUpper(parts[0])
+		if len(parts) == 2 {
+			values[key] = parts[1]
+			continue
+		}
+
+		if !display.CanPrompt {
+			return nil, util.Errorf("[USER] no value for %s, use %s=val", key, key)
+		}
+
+		val, err := display.ReadPassword(key)
+		if err != nil {
+			return nil, util.ErrorAppend(err, "failed to read the value of %s", key)
+		}
+		values[key] = val
+	}
+
+	return values, nil
+}
+
+// productionErr is the error for secrets on a live app
+func productionErr() error {
+	return util.Errorf("[USER] secrets are kept for local and dry-run environments, use 'nanobox evar add' for live apps")
+}
@@ -23,20 +23,20 @@ func init() {
 // simStart ...
 func simStart(ccmd *cobra.Command, args []string) {
 	envModel, _ := models.FindEnvByID(config.EnvID())
-	appModel, _ := models.FindAppBySlug(config.EnvID(), "sim")
+	appModel, _ := models.FindAppBySlug(config.EnvID(), envModel.DryRunName())
 
 	display.CommandErr(env.Setup(envModel))
-	display.CommandErr(app.Start(envModel, appModel, "sim"))
+	display.CommandErr(app.Start(envModel, appModel, envModel.DryRunName()))
 }
 
 func startCheck() bool {
-	app, _ := models.FindAppBySlug(config.EnvID(), "sim")
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	app, _ := models.FindAppBySlug(config.EnvID(), envModel.DryRunName())
 	if app.Status != "up" {
 		return false
 	}
 
 	// make sure im mounted and ready to go
-	envModel, _ := models.FindEnvByID(config.EnvID())
 	if !util_provider.HasMount(fmt.Sprintf("%s%s/code", util_provider.HostShareDir(), envModel.ID)) {
 		return false
 	}
@@ -17,7 +17,8 @@ func init() {
 
 // stopFn ...
 func stopFn(ccmd *cobra.Command, args []string) {
-	appModel, _ := models.FindAppBySlug(config.EnvID(), "sim")
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	appModel, _ := models.FindAppBySlug(config.EnvID(), envModel.DryRunName())
 	display.CommandErr(app.Stop(appModel))
 }
 
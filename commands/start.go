@@ -4,6 +4,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/processors"
 	"github.com/nanobox-io/nanobox/processors/provider/bridge"
 	"github.com/nanobox-io/nanobox/util/display"
@@ -17,8 +18,12 @@ var (
 	StartCmd = &cobra.Command{
 		Use:   "start",
 		Short: "Start the Nanobox virtual machine.",
-		Long:  ``,
-		Run:   startFn,
+		Long: `
+Starts the Nanobox virtual machine, and resumes the
+environments 'nanobox stop --suspend' or the idle-timeout
+suspended.
+		`,
+		Run: startFn,
 	}
 )
 
@@ -28,7 +33,7 @@ func init() {
 
 // startFn ...
 func startFn(ccmd *cobra.Command, args []string) {
-	display.CommandErr(processors.Start())
+	display.CommandErr(processors.Resume())
 }
 
 func startCheck() bool {
@@ -36,5 +41,9 @@ func startCheck() bool {
 	if provider.BridgeRequired() {
 		bridgeReady = bridge.Connected()
 	}
-	return provider.IsReady() && service.Running("nanobox-server") && bridgeReady
+
+	// the apps that were suspended are resumed by the next command
+	suspended, _ := models.AllAppsByStatus(models.StatusSuspended)
+
+	return provider.IsReady() && service.Running("nanobox-server") && bridgeReady && len(suspended) == 0
 }
@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/state"
+)
+
+var (
+
+	// StateCmd ...
+	StateCmd = &cobra.Command{
+		Use:   "state",
+		Short: "Move your apps to another machine.",
+		Long: `
+Exports the apps nanobox knows about, their evars, dns aliases and
+data services into a single file, and imports it on another machine.
+		`,
+	}
+)
+
+func init() {
+	StateCmd.AddCommand(state.ExportCmd)
+	StateCmd.AddCommand(state.ImportCmd)
+}
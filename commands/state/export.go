@@ -0,0 +1,55 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/processors/state"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// ExportCmd bundles the local state
+	ExportCmd = &cobra.Command{
+		Use:   "export file.tar.gz",
+		Short: "Export the apps on this machine.",
+		Long: `
+Exports the apps on this machine, their evars, dns aliases and the
+config of their data services into a single file. With --data the
+data of the data services is exported too, the apps are stopped
+while it's read.
+
+Secrets are encrypted with a key that stays on this machine, set
+them again after the import.
+		`,
+		Run: exportFn,
+	}
+
+	// exportCmdFlags ...
+	exportCmdFlags = struct {
+		data bool
+	}{}
+)
+
+func init() {
+	ExportCmd.Flags().BoolVarP(&exportCmdFlags.data, "data", "d", false, "export the data of the data services")
+}
+
+// exportFn ...
+func exportFn(ccmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Printf("\n! Please provide where to export to, ie: nanobox state export state.tar.gz\n\n")
+		ccmd.HelpFunc()(ccmd, args)
+		return
+	}
+
+	// the data is read from the containers in the vm
+	if exportCmdFlags.data {
+		steps.Run("start")(ccmd, args)
+	}
+
+	display.CommandErr(state.Export(args[0], exportCmdFlags.data))
+}
@@ -0,0 +1,64 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/processors/state"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// ImportCmd restores the local state
+	ImportCmd = &cobra.Command{
+		Use:   "import file.tar.gz",
+		Short: "Import the apps of another machine.",
+		Long: `
+Imports the apps of a 'nanobox state export'. The data services are
+created again, with their data if it was exported. The code runs
+again with the next 'nanobox run' or deploy. Apps that already exist
+on this machine are skipped.
+
+The apps are found by their directory. When the code lives somewhere
+else on this machine, map the directories with --move, ie:
+
+  nanobox state import state.tar.gz --move /Users/old=/Users/new
+		`,
+		PreRun: steps.Run("start"),
+		Run:    importFn,
+	}
+
+	// importCmdFlags ...
+	importCmdFlags = struct {
+		moves []string
+	}{}
+)
+
+func init() {
+	ImportCmd.Flags().StringSliceVarP(&importCmdFlags.moves, "move", "m", nil, "map a directory of the other machine to this one, old=new")
+}
+
+// importFn ...
+func importFn(ccmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Printf("\n! Please provide the file to import, ie: nanobox state import state.tar.gz\n\n")
+		ccmd.HelpFunc()(ccmd, args)
+		return
+	}
+
+	moves := map[string]string{}
+	for _, move := range importCmdFlags.moves {
+		parts := strings.SplitN(move, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			fmt.Printf("\n! '%s' isn't a move, use old=new\n\n", move)
+			return
+		}
+		moves[parts[0]] = parts[1]
+	}
+
+	display.CommandErr(state.Import(args[0], moves))
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/helpers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// StatsCmd ...
+	StatsCmd = &cobra.Command{
+		Use:   "stats [local | dry-run]",
+		Short: "Show resource usage of your app's components.",
+		Long: `
+Shows the cpu, memory and network usage of each component of the
+specified environment, and the times its container was restarted.
+Limits can be set per component with 'cpu' and 'memory' in the
+component's boxfile config node.
+
+  --watch      keep redrawing the table until ctrl + c
+  --metrics    serve the usage in prometheus' format at /metrics
+               on an address, ie: --metrics localhost:9100
+		`,
+		PreRun: steps.Run("start"),
+		Run:    statsFn,
+	}
+
+	// statsCmdFlags ...
+	statsCmdFlags = struct {
+		watch   bool
+		metrics string
+	}{}
+)
+
+func init() {
+	StatsCmd.Flags().BoolVarP(&statsCmdFlags.watch, "watch", "w", false, "keep redrawing the table")
+	StatsCmd.Flags().StringVarP(&statsCmdFlags.metrics, "metrics", "", "", "serve prometheus metrics on an address, ie: localhost:9100")
+}
+
+// statsFn ...
+func statsFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	args, location, name := helpers.Endpoint(envModel, args, 0)
+
+	switch location {
+	case "local":
+		appModel, _ := models.FindAppBySlug(config.EnvID(), name)
+		display.CommandErr(app.Stats(appModel, statsCmdFlags.watch, statsCmdFlags.metrics))
+	case "production":
+		fmt.Printf(`
+-------------------------------------------------
+Showing production stats is not yet implemented.
+-------------------------------------------------
+
+`)
+	}
+}
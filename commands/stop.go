@@ -17,13 +17,35 @@ var (
 		Long: `
 Stops the Nanobox virtual machine as well as
 any running local or dry-run environments.
+
+With --suspend the containers are paused and the
+state of the virtual machine is saved instead, so
+'nanobox start' picks up where it left off with the
+same IPs, data and evars, much faster than a boot.
+--keep-vm only pauses the containers.
 		`,
 		Run: stopFn,
 	}
+
+	// stopCmdFlags ...
+	stopCmdFlags = struct {
+		suspend bool
+		keepVM  bool
+	}{}
 )
 
+func init() {
+	StopCmd.Flags().BoolVarP(&stopCmdFlags.suspend, "suspend", "", false, "pause the containers and save the vm state instead of stopping")
+	StopCmd.Flags().BoolVarP(&stopCmdFlags.keepVM, "keep-vm", "", false, "with --suspend, leave the vm running")
+}
+
 // stopFn ...
 func stopFn(ccmd *cobra.Command, args []string) {
+	if stopCmdFlags.suspend {
+		display.CommandErr(processors.Suspend(stopCmdFlags.keepVM))
+		return
+	}
+
 	registry.Set("keep-share", true)
 	display.CommandErr(processors.Stop())
 }
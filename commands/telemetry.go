@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// TelemetryCmd ...
+	TelemetryCmd = &cobra.Command{
+		Use:   "telemetry",
+		Short: "Manage anonymous usage metrics.",
+		Long: `
+Nanobox can record which commands you run, how long they take and
+the class of the errors they fail with, to help the maintainers see
+what to work on. It's off unless you turn it on.
+
+The events are sent in batches to nanobox or, with --telemetry-endpoint,
+to a server of your own.
+		`,
+	}
+
+	// TelemetryOnCmd ...
+	TelemetryOnCmd = &cobra.Command{
+		Use:   "on",
+		Short: "Turn the usage metrics on.",
+		Long:  ``,
+		Run:   telemetryOnFn,
+	}
+
+	// TelemetryOffCmd ...
+	TelemetryOffCmd = &cobra.Command{
+		Use:   "off",
+		Short: "Turn the usage metrics off.",
+		Long:  ``,
+		Run:   telemetryOffFn,
+	}
+
+	// TelemetryStatusCmd ...
+	TelemetryStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the usage metrics are on and what they contain.",
+		Long:  ``,
+		Run:   telemetryStatusFn,
+	}
+
+	// telemetryEndpoint is where the events are sent
+	telemetryEndpoint string
+)
+
+func init() {
+	TelemetryCmd.AddCommand(TelemetryOnCmd)
+	TelemetryCmd.AddCommand(TelemetryOffCmd)
+	TelemetryCmd.AddCommand(TelemetryStatusCmd)
+
+	TelemetryOnCmd.Flags().StringVar(&telemetryEndpoint, "telemetry-endpoint", "", "send the events to your own server")
+}
+
+// telemetryOnFn ...
+func telemetryOnFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.TelemetryOn(telemetryEndpoint))
+}
+
+// telemetryOffFn ...
+func telemetryOffFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.TelemetryOff())
+}
+
+// telemetryStatusFn ...
+func telemetryStatusFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.TelemetryStatus())
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// TestCmd ...
+	TestCmd = &cobra.Command{
+		Use:   "test [-- <command>]",
+		Short: "Run the tests against a fresh copy of your app's services.",
+		Long: `
+Brings up a fresh copy of the app's data components, runs the
+test commands of the run.config node in the boxfile in the dev
+runtime, streams their output and tears everything down. The
+exit code is the one of the failing command, so CI can rely
+on it:
+
+  run.config:
+    test:
+      - bundle exec rake db:setup
+      - bundle exec rspec
+
+A command can be given instead, ie: nanobox test -- rspec spec/models
+		`,
+		PreRun: steps.Run("start", "build-runtime"),
+		Run:    testFn,
+	}
+)
+
+// testFn ...
+func testFn(ccmd *cobra.Command, args []string) {
+	envModel, _ := models.FindEnvByID(config.EnvID())
+	display.CommandErr(processors.Test(envModel, args))
+}
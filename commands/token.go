@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/token"
+)
+
+var (
+
+	// TokenCmd ...
+	TokenCmd = &cobra.Command{
+		Use:   "token",
+		Short: "Manage api tokens for ci systems.",
+		Long: `
+Manages api tokens with a limited scope. Give a deploy-only or read-only
+token to a ci system instead of your own login, it can't delete apps and
+can be revoked on its own.
+		`,
+	}
+)
+
+func init() {
+	TokenCmd.AddCommand(token.CreateCmd)
+	TokenCmd.AddCommand(token.ListCmd)
+	TokenCmd.AddCommand(token.RevokeCmd)
+}
@@ -0,0 +1,57 @@
+package token
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/token"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// CreateCmd mints a token
+	CreateCmd = &cobra.Command{
+		Use:   "create name",
+		Short: "Create an api token.",
+		Long: `
+Creates an api token with a limited scope:
+
+  deploy      build, deploy and read apps
+  read-only   read apps, their logs and evars
+
+The token is only shown once. Limit it to one app with --app, by remote
+alias or app name.
+		`,
+		PreRun: steps.Run("login"),
+		Run:    createFn,
+	}
+
+	// createCmdFlags ...
+	createCmdFlags = struct {
+		scope string
+		app   string
+	}{}
+)
+
+func init() {
+	CreateCmd.Flags().StringVarP(&createCmdFlags.scope, "scope", "s", "deploy", "deploy or read-only")
+	CreateCmd.Flags().StringVarP(&createCmdFlags.app, "app", "a", "", "limit the token to an app")
+}
+
+// createFn ...
+func createFn(ccmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Printf("\n! Please provide a name for the token\n\n")
+		ccmd.HelpFunc()(ccmd, args)
+		return
+	}
+
+	envModel, _ := models.FindEnvByID(config.EnvID())
+
+	display.CommandErr(token.Create(envModel, args[0], createCmdFlags.scope, createCmdFlags.app))
+}
@@ -0,0 +1,23 @@
+package token
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/processors/token"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// ListCmd lists the tokens
+var ListCmd = &cobra.Command{
+	Use:    "ls",
+	Short:  "List your api tokens.",
+	Long:   ``,
+	PreRun: steps.Run("login"),
+	Run:    listFn,
+}
+
+// listFn ...
+func listFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(token.List())
+}
@@ -0,0 +1,31 @@
+package token
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/commands/steps"
+	"github.com/nanobox-io/nanobox/processors/token"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// RevokeCmd revokes a token
+var RevokeCmd = &cobra.Command{
+	Use:    "revoke id|name",
+	Short:  "Revoke an api token.",
+	Long:   ``,
+	PreRun: steps.Run("login"),
+	Run:    revokeFn,
+}
+
+// revokeFn ...
+func revokeFn(ccmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Printf("\n! Please provide the id or name of the token to revoke\n\n")
+		ccmd.HelpFunc()(ccmd, args)
+		return
+	}
+
+	display.CommandErr(token.Revoke(args[0]))
+}
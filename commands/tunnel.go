@@ -11,6 +11,7 @@ import (
 	"github.com/nanobox-io/nanobox/helpers"
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/processors/app"
 	"github.com/nanobox-io/nanobox/util/config"
 	"github.com/nanobox-io/nanobox/util/display"
 )
@@ -18,14 +19,18 @@ import (
 var (
 	// TunnelCmd handles tunneling to components.
 	TunnelCmd = &cobra.Command{
-		Use:   "tunnel [dry-run|remote-alias] <component.id>",
+		Use:   "tunnel [local|dry-run|remote-alias] <component.id>",
 		Short: "Create a secure tunnel between your local machine & a live component.",
 		Long: `
 Creates a secure tunnel between your local machine &
 a live component. The tunnel allows you to manage
 live data using your local client of choice.
+
+For local and dry-run apps a port on 127.0.0.1 is
+forwarded to the component, ie: nanobox tunnel local db,
+and the credentials and connection strings are printed.
 `,
-		PreRun: steps.Run("login"),
+		PreRun: tunnelPreRun,
 		Run:    tunnelFn,
 	}
 
@@ -44,6 +49,14 @@ func init() {
 	TunnelCmd.Flags().StringVarP(&portMap, "port", "p", "", "Specify a local[:destination] port to listen on and connect to.")
 }
 
+// tunnelPreRun only logs in for remote tunnels
+func tunnelPreRun(ccmd *cobra.Command, args []string) {
+	env, _ := models.FindEnvByID(config.EnvID())
+	if _, location, _ := helpers.Endpoint(env, args, 2); location == "production" {
+		steps.Run("login")(ccmd, args)
+	}
+}
+
 // tunnelFn validates the ports and establishes the tunnel
 func tunnelFn(ccmd *cobra.Command, args []string) {
 	env, _ := models.FindEnvByID(config.EnvID())
@@ -123,8 +136,13 @@ Please specify a number between 1 and 65535 as a destination port. You specified
 
 	switch location {
 	case "local":
-		fmt.Println("tunneling is not required for local development")
-		return
+		appModel, _ := models.FindAppBySlug(config.EnvID(), name)
+		if appModel.Status != "up" {
+			fmt.Println("unable to continue until the app is up")
+			return
+		}
+
+		display.CommandErr(app.Tunnel(appModel, args[0], tunnelPorts.listenPort, tunnelPorts.destPort))
 	case "production":
 		// set the meta arguments to be used in the processor and run the processor
 		tunnelConfig := models.TunnelConfig{
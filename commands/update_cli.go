@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// UpdateCLICmd updates the nanobox binary
+	UpdateCLICmd = &cobra.Command{
+		Use:   "update-cli",
+		Short: "Updates nanobox to the latest release.",
+		Long: `
+Downloads the latest release of nanobox for this platform from a
+release channel, verifies its checksum and signature and replaces
+the running nanobox with it.
+
+The channel is 'stable' unless it's set with --channel or with
+'nanobox config set update-channel beta'. To be told when a new
+release is out, run 'nanobox config set update-check true'.
+`,
+		Run: updateCLIFn,
+	}
+
+	// updateChannel is the release channel to update from
+	updateChannel string
+
+	// updateInstall and updateDigest are the verified binary the
+	// administrator run of update-cli installs
+	updateInstall string
+	updateDigest  string
+)
+
+func init() {
+	UpdateCLICmd.Flags().StringVar(&updateChannel, "channel", "", "release channel to update from (stable | beta)")
+	UpdateCLICmd.Flags().StringVar(&updateInstall, "install", "", "")
+	UpdateCLICmd.Flags().StringVar(&updateDigest, "sha256", "", "")
+	UpdateCLICmd.Flags().MarkHidden("install")
+	UpdateCLICmd.Flags().MarkHidden("sha256")
+}
+
+// updateCLIFn ...
+func updateCLIFn(ccmd *cobra.Command, args []string) {
+	if updateInstall != "" {
+		display.CommandErr(processors.InstallCLI(updateInstall, updateDigest))
+		return
+	}
+
+	display.CommandErr(processors.UpdateCLI(updateChannel))
+}
@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// ValidateCmd ...
+	ValidateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Checks the boxfile.yml for errors.",
+		Long: `
+Checks the boxfile.yml against the nodes and keys nanobox knows,
+without touching docker. Errors, like a value of the wrong type or a
+missing engine, are printed with their line and fail the command.
+Keys nanobox doesn't know are only warned about.
+
+The boxfile is also validated at the start of every build and run.
+		`,
+		Run: validateFn,
+	}
+)
+
+// validateFn ...
+func validateFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.Validate())
+}
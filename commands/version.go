@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util/display"
 )
 
 var (
@@ -22,5 +23,11 @@ var (
 
 // versionFn does the actual printing
 func versionFn(ccmd *cobra.Command, args []string) {
+	// the persistent pre run is skipped, so the output isn't set up
+	if outputMode == "json" {
+		display.PrintJSON(models.VersionInfo())
+		return
+	}
+
 	fmt.Println(models.VersionString())
 }
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"strconv"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// VMCmd ...
+	VMCmd = &cobra.Command{
+		Use:   "vm",
+		Short: "Manage the resources of the nanobox VM.",
+		Long: `
+Manages the cpus, memory and disk of the VM the apps run in.
+		`,
+	}
+
+	// VMSetCmd ...
+	VMSetCmd = &cobra.Command{
+		Use:   "set",
+		Short: "Set the cpus, memory and disk of the VM.",
+		Long: `
+Sets the cpus, memory and disk of the VM, ie:
+
+  nanobox vm set --cpus 4 --memory 6G --disk 60G
+
+A running VM is stopped with its apps, resized and started
+again with the apps that were running. The disk can only
+grow, the filesystem of the VM grows onto it. Sizes take
+a unit, M or G, a bare number is in gigabytes.
+		`,
+		Run: vmSetFn,
+	}
+
+	// VMStatusCmd ...
+	VMStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show the resources of the VM and their usage.",
+		Long: `
+Shows the cpus, memory and disk the VM is given, and how
+much of them is in use.
+		`,
+		Run: vmStatusFn,
+	}
+
+	// vmSetCmdFlags ...
+	vmSetCmdFlags = struct {
+		cpus   int
+		memory string
+		disk   string
+	}{}
+)
+
+func init() {
+	VMSetCmd.Flags().IntVarP(&vmSetCmdFlags.cpus, "cpus", "", 0, "The number of cpus")
+	VMSetCmd.Flags().StringVarP(&vmSetCmdFlags.memory, "memory", "", "", "The memory, in whole gigabytes, ie: 6G")
+	VMSetCmd.Flags().StringVarP(&vmSetCmdFlags.disk, "disk", "", "", "The disk size, ie: 60G")
+
+	VMCmd.AddCommand(VMSetCmd)
+	VMCmd.AddCommand(VMStatusCmd)
+}
+
+// vmSetFn ...
+func vmSetFn(ccmd *cobra.Command, args []string) {
+	if vmSetCmdFlags.cpus == 0 && vmSetCmdFlags.memory == "" && vmSetCmdFlags.disk == "" {
+		ccmd.HelpFunc()(ccmd, args)
+		return
+	}
+
+	if vmSetCmdFlags.cpus < 0 {
+		display.CommandErr(util.Errorf("[USER] --cpus has to be at least 1"))
+		return
+	}
+
+	memory, err := vmSize(vmSetCmdFlags.memory)
+	if err != nil {
+		display.CommandErr(util.Errorf("[USER] --memory %s", err.Error()))
+		return
+	}
+	if memory%1024 != 0 {
+		display.CommandErr(util.Errorf("[USER] --memory is set in whole gigabytes, ie: 6G"))
+		return
+	}
+
+	disk, err := vmSize(vmSetCmdFlags.disk)
+	if err != nil {
+		display.CommandErr(util.Errorf("[USER] --disk %s", err.Error()))
+		return
+	}
+
+	display.CommandErr(processors.VMSet(processors.VMConfig{
+		CPUs: vmSetCmdFlags.cpus,
+		RAM:  memory / 1024,
+		Disk: disk,
+	}))
+}
+
+// vmStatusFn ...
+func vmStatusFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.VMStatus())
+}
+
+// vmSize returns a size like 6G or 512M in megabytes, a bare number is in
+// gigabytes. An empty size is zero.
+func vmSize(size string) (int, error) {
+	if size == "" {
+		return 0, nil
+	}
+
+	if gb, err := strconv.Atoi(size); err == nil {
+		size = strconv.Itoa(gb) + "G"
+	}
+
+	bytes, err := units.RAMInBytes(size)
+	if err != nil || bytes <= 0 {
+		return 0, util.Errorf("isn't a size, ie: 6G or 512M")
+	}
+
+	return int(bytes / units.MiB), nil
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// WebhookCmd ...
+	WebhookCmd = &cobra.Command{
+		Use:   "webhook",
+		Short: "Send lifecycle events to editors, chat bots or notifiers.",
+		Long: `
+Sends what nanobox does to webhooks, so editors, chat bots or
+desktop notifiers can react to it:
+
+  build.started   build.finished   build.failed
+  service.up      service.down     app.up     app.down
+  deploy.complete deploy.failed
+
+An event is posted as json to an http(s) url, or written as a line
+of json to a unix socket, ie: unix:///tmp/nanobox.sock. Its 'text'
+makes it a message for chat webhooks, like slack's.
+
+With 'nanobox config set webhook-secret <secret>' the posts are
+signed, the X-Nanobox-Signature header is the sha256 hmac of the
+body. 'webhook-events build,deploy' only sends those events.
+		`,
+	}
+
+	// WebhookAddCmd ...
+	WebhookAddCmd = &cobra.Command{
+		Use:   "add <url>",
+		Short: "Add a webhook.",
+		Long:  ``,
+		Run:   webhookAddFn,
+	}
+
+	// WebhookRemoveCmd ...
+	WebhookRemoveCmd = &cobra.Command{
+		Use:     "rm <url>",
+		Short:   "Remove a webhook.",
+		Long:    ``,
+		Aliases: []string{"remove"},
+		Run:     webhookRemoveFn,
+	}
+
+	// WebhookListCmd ...
+	WebhookListCmd = &cobra.Command{
+		Use:     "ls",
+		Short:   "List the webhooks.",
+		Long:    ``,
+		Aliases: []string{"list"},
+		Run:     webhookListFn,
+	}
+
+	// WebhookTestCmd ...
+	WebhookTestCmd = &cobra.Command{
+		Use:   "test",
+		Short: "Send a test event to every webhook.",
+		Long:  ``,
+		Run:   webhookTestFn,
+	}
+)
+
+func init() {
+	WebhookCmd.AddCommand(WebhookAddCmd)
+	WebhookCmd.AddCommand(WebhookRemoveCmd)
+	WebhookCmd.AddCommand(WebhookListCmd)
+	WebhookCmd.AddCommand(WebhookTestCmd)
+}
+
+// webhookAddFn ...
+func webhookAddFn(ccmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Printf("\n! Please provide the url of the webhook, ie: nanobox webhook add https://localhost:8080/nanobox\n\n")
+		return
+	}
+
+	display.CommandErr(processors.WebhookAdd(args[0]))
+}
+
+// webhookRemoveFn ...
+func webhookRemoveFn(ccmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Printf("\n! Please provide the url of the webhook to remove, see 'nanobox webhook ls'\n\n")
+		return
+	}
+
+	display.CommandErr(processors.WebhookRemove(args[0]))
+}
+
+// webhookListFn ...
+func webhookListFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.WebhookList())
+}
+
+// webhookTestFn ...
+func webhookTestFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.WebhookTest())
+}
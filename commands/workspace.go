@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nanobox-io/nanobox/processors"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+var (
+
+	// WorkspaceCmd ...
+	WorkspaceCmd = &cobra.Command{
+		Use:   "workspace",
+		Short: "List the data components apps share.",
+		Long: `
+Lists the shared data components of every workspace, with the
+apps that use them.
+
+A data component of the boxfile.yml is shared by naming its
+workspace, ie:
+
+  data.db:
+    image: nanobox/postgresql:9.6
+    shared: acme
+
+Every app with a data.db shared in acme uses the same container,
+with the same credentials. It keeps running while any of them
+does and is only destroyed with the last app that uses it.
+		`,
+		Run: workspaceFn,
+	}
+)
+
+// workspaceFn ...
+func workspaceFn(ccmd *cobra.Command, args []string) {
+	display.CommandErr(processors.Workspaces())
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/nanobox-io/golang-docker-client"
+	"github.com/nanobox-io/nanobox-boxfile"
 
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/util/config"
@@ -17,13 +18,18 @@ func BuildConfig(image string) docker.ContainerConfig {
 	// mounting from b2d "global zone" to container will be the same whether local engine specified or not
 	engine := fmt.Sprintf("%s%s/engine:/share/engine", provider.HostShareDir(), env)
 
-	if !provider.RequiresMount() {
-		code = fmt.Sprintf("%s:/app", config.LocalDir())
+	// the changed files are synced into this volume before the build
+	if BuildSync() {
+		code = fmt.Sprintf("%s:/app", BuildSyncVolume())
+	}
+
+	if !provider.RequiresMount() && RemoteHost == "" {
+		code = fmt.Sprintf("%s:/app", hostDir(config.LocalDir()))
 
 		// todo: test this (likely docker-native linux)
 		engineDir, _ := config.EngineDir()
 		if engineDir != "" {
-			engine = fmt.Sprintf("%s:/share/engine", engineDir)
+			engine = fmt.Sprintf("%s:/share/engine", hostDir(engineDir))
 		}
 	}
 
@@ -55,9 +61,61 @@ func BuildConfig(image string) docker.ContainerConfig {
 	// set http[s]_proxy and no_proxy vars
 	setProxyVars(&conf)
 
+	// pass the build args through
+	setEnvMap(&conf, BuildArgs)
+
+	// expose the host gpus if they were requested
+	setGPUVars(&conf, boxfile.NewFromPath(config.Boxfile()))
+
 	return conf
 }
 
+// RemoteHost is the docker host a remote build runs on. The host can't see
+// the local files, so the code is always synced for remote builds.
+var RemoteHost string
+
+// BuildSync returns true if the code is synced into a volume for builds
+// rather than read from the shared folder, with build-sync or the sync
+// mount-type. Without a shared folder the code
+// is mounted directly, so there is nothing to sync.
+func BuildSync() bool {
+	if RemoteHost != "" {
+		return true
+	}
+
+	configModel, _ := models.LoadConfig()
+	return (configModel.BuildSync || configModel.MountType == models.MountSync) && provider.RequiresMount()
+}
+
+// BuildSyncVolume returns the name of the volume the code is synced into
+func BuildSyncVolume() string {
+	return fmt.Sprintf("nanobox_%s_code", config.EnvID())
+}
+
+// ArtifactConfig generates the container configuration for the container
+// the build artifacts are copied through when they're moved between hosts,
+// and the build cache when it's saved for ci
+func ArtifactConfig(image string, cmd []string) docker.ContainerConfig {
+	env := config.EnvID()
+
+	return docker.ContainerConfig{
+		Name:  ArtifactName(),
+		Image: image,
+		Binds: []string{
+			fmt.Sprintf("nanobox_%s_build:/mnt/build", env),
+			fmt.Sprintf("nanobox_%s_deploy:/mnt/deploy", env),
+			fmt.Sprintf("nanobox_%s_cache:/mnt/cache", env),
+		},
+		Cmd:           cmd,
+		RestartPolicy: "no",
+	}
+}
+
+// ArtifactName returns the name of the artifact container
+func ArtifactName() string {
+	return fmt.Sprintf("nanobox_%s_artifact", config.EnvID())
+}
+
 // BuildName returns the name of the build container
 func BuildName() string {
 	return fmt.Sprintf("nanobox_%s_build", config.EnvID())
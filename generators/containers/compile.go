@@ -17,10 +17,10 @@ func CompileConfig(image string) docker.ContainerConfig {
 	engine := fmt.Sprintf("%s%s/engine:/share/engine", provider.HostShareDir(), env)
 
 	if !provider.RequiresMount() {
-		code = fmt.Sprintf("%s:/share/code", config.LocalDir())
+		code = fmt.Sprintf("%s:/share/code", hostDir(config.LocalDir()))
 		engineDir, _ := config.EngineDir()
 		if engineDir != "" {
-			engine = fmt.Sprintf("%s:/share/engine", engineDir)
+			engine = fmt.Sprintf("%s:/share/engine", hostDir(engineDir))
 		}
 	}
 
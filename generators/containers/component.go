@@ -15,16 +15,50 @@ func ComponentConfig(componentModel *models.Component) docker.ContainerConfig {
 		Image:         componentModel.Image,
 		Network:       "virt",
 		IP:            componentModel.IPAddr(),
-		RestartPolicy: "no",
+		RestartPolicy: RestartPolicy(componentModel),
 	}
 
 	// set http[s]_proxy and no_proxy vars
 	setProxyVars(&config)
 
+	// bind the host directories the boxfile mounts
+	setHostMounts(&config, componentModel.EnvID, componentModel.Mounts)
+
 	return config
 }
 
-// ComponentName returns the name of the component container
+// CodeConfig generates the container configuration for a code component,
+// with the app secrets in its environment
+func CodeConfig(appModel *models.App, componentModel *models.Component) docker.ContainerConfig {
+	config := ComponentConfig(componentModel)
+
+	setAppSecrets(&config, appModel)
+
+	return config
+}
+
+// ComponentName returns the name of the component container, a shared
+// component is named after its workspace
 func ComponentName(componentModel *models.Component) string {
+	if componentModel.Workspace != "" {
+		return fmt.Sprintf("nanobox_workspace_%s_%s", componentModel.Workspace, componentModel.Name)
+	}
+
 	return fmt.Sprintf("nanobox_%s_%s", componentModel.AppID, componentModel.Name)
 }
+
+// RestartPolicy returns the docker restart policy for a component. Data
+// components and sidecars are restarted when they crash unless the boxfile
+// says otherwise.
+func RestartPolicy(componentModel *models.Component) string {
+	switch componentModel.RestartPolicy {
+	case "no", "always", "on-failure", "unless-stopped":
+		return componentModel.RestartPolicy
+	}
+
+	if componentModel.Type == "data" || componentModel.Type == "sidecar" {
+		return "on-failure"
+	}
+
+	return "no"
+}
@@ -19,3 +19,29 @@ func TestSetProxyVars(t *testing.T) {
 		t.Errorf("Failed to preserve prior envs!")
 	}
 }
+
+func TestParseMemory(t *testing.T) {
+	sizes := map[interface{}]int64{
+		nil:     0,
+		512:     512 << 20,
+		"256":   256 << 20,
+		"512m":  512 << 20,
+		"1g":    1 << 30,
+		"1.5GB": 3 << 29,
+		"64k":   64 << 10,
+	}
+
+	for size, expected := range sizes {
+		bytes, err := parseMemory(size)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %s", size, err)
+		}
+		if bytes != expected {
+			t.Errorf("%v: expected %d got %d", size, expected, bytes)
+		}
+	}
+
+	if _, err := parseMemory("lots"); err == nil {
+		t.Errorf("expected an error for an invalid size")
+	}
+}
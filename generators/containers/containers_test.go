@@ -63,3 +63,20 @@ func TestDevConfig(t *testing.T) {
 	}
 	dhcp.ReturnIP(net.ParseIP(result.IP))
 }
+
+func TestComponentRestartPolicy(t *testing.T) {
+	policies := map[*models.Component]string{
+		&models.Component{Type: "data"}:                                  "on-failure",
+		&models.Component{Type: "code"}:                                  "no",
+		&models.Component{Type: "data", RestartPolicy: "always"}:         "always",
+		&models.Component{Type: "data", RestartPolicy: "no"}:             "no",
+		&models.Component{Type: "code", RestartPolicy: "unless-stopped"}: "unless-stopped",
+		&models.Component{Type: "data", RestartPolicy: "sometimes"}:      "on-failure",
+	}
+
+	for componentModel, policy := range policies {
+		if result := containers.ComponentConfig(componentModel); result.RestartPolicy != policy {
+			t.Errorf("%+v: expected restart policy '%s' got '%s'", componentModel, policy, result.RestartPolicy)
+		}
+	}
+}
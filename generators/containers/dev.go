@@ -10,6 +10,7 @@ import (
 
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/hostmount"
 	"github.com/nanobox-io/nanobox/util/provider"
 )
 
@@ -26,9 +27,17 @@ func DevConfig(appModel *models.App) docker.ContainerConfig {
 	code := fmt.Sprintf("%s%s/code:/app", provider.HostShareDir(), appModel.EnvID)
 
 	if !provider.RequiresMount() {
-		code = fmt.Sprintf("%s:/app", config.LocalDir())
+		code = fmt.Sprintf("%s:/app", hostDir(config.LocalDir()))
 	}
 
+	// the code is synced into this volume while the console is open, other
+	// apps, ie: a test run, read the shared folder
+	if CodeSync() && appModel.Name == "dev" {
+		code = fmt.Sprintf("%s:/app", CodeSyncVolume())
+	}
+
+	mounts, _ := hostmount.ParseAll(boxfile.Node("run.config").StringSliceValue("mounts"), config.LocalDir())
+
 	config := docker.ContainerConfig{
 		Name:    fmt.Sprintf("nanobox_%s", appModel.ID),
 		Image:   image, // this will need to be configurable some time
@@ -58,6 +67,18 @@ func DevConfig(appModel *models.App) docker.ContainerConfig {
 	// set http[s]_proxy and no_proxy vars
 	setProxyVars(&config)
 
+	// expose the host gpus if they were requested
+	setGPUVars(&config, boxfile)
+
+	// bind the host directories the boxfile mounts, they were checked when
+	// the env was mounted
+	for _, m := range mounts {
+		config.Binds = append(config.Binds, m.Bind(HostMountSource(appModel.EnvID, m)))
+	}
+
+	// the evars come through the dev hook, the secrets can't
+	setAppSecrets(&config, appModel)
+
 	// // add cache_dirs into the container binds
 	// libDirs := boxfile.Node("run.config").StringSliceValue("cache_dirs")
 
@@ -74,3 +95,16 @@ func DevConfig(appModel *models.App) docker.ContainerConfig {
 func DevName() string {
 	return fmt.Sprintf("nanobox_%s_dev", config.EnvID())
 }
+
+// CodeSync returns true if the code of the dev container is kept in a
+// volume and synced both ways, rather than read from the shared folder,
+// with code-sync or the sync mount-type. Without a shared folder the code is mounted directly.
+func CodeSync() bool {
+	configModel, _ := models.LoadConfig()
+	return (configModel.CodeSync || configModel.MountType == models.MountSync) && provider.RequiresMount()
+}
+
+// CodeSyncVolume returns the name of the volume the dev code is synced into
+func CodeSyncVolume() string {
+	return fmt.Sprintf("nanobox_%s_dev_code", config.EnvID())
+}
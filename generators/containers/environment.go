@@ -3,10 +3,64 @@ package containers
 import (
 	"fmt"
 	"os"
+	"sort"
 
+	"github.com/jcelliott/lumber"
 	"github.com/nanobox-io/golang-docker-client"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util/interpolate"
+	"github.com/nanobox-io/nanobox/util/secret"
+	"github.com/nanobox-io/nanobox/util/secretref"
 )
 
+// BuildArgs are build-time variables passed to the build container. They only
+// live in the container environment, so they're never written to the build
+// volumes or the evar store.
+var BuildArgs = map[string]string{}
+
+// setAppEvars adds the evars of the component to the container environment
+func setAppEvars(config *docker.ContainerConfig, appModel *models.App, component string) {
+	setEnvMap(config, interpolate.Expanded(secretref.Resolved(appModel.EvarsFor(component))))
+}
+
+// setAppSecrets adds the decrypted app secrets to the container environment.
+// They only live there, never in the app evars or the hook payloads.
+func setAppSecrets(config *docker.ContainerConfig, appModel *models.App) {
+	secrets, err := models.FindSecretsByApp(appModel.ID)
+	if err != nil {
+		lumber.Error("containers:setAppSecrets:models.FindSecretsByApp(%s): %s", appModel.ID, err.Error())
+		return
+	}
+
+	vars := map[string]string{}
+	for key, encrypted := range secrets.Values {
+		val, err := secret.Decrypt(encrypted)
+		if err != nil {
+			// the log names the key, never the value
+			lumber.Error("containers:setAppSecrets:secret.Decrypt(%s): %s", key, err.Error())
+			continue
+		}
+		vars[key] = val
+	}
+
+	setEnvMap(config, vars)
+}
+
+// setEnvMap adds the variables to the container environment, sorted so the
+// config is stable between runs
+func setEnvMap(config *docker.ContainerConfig, vars map[string]string) {
+	keys := []string{}
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		config.Env = append(config.Env, fmt.Sprintf("%s=%s", key, vars[key]))
+	}
+}
+
 func setProxyVars(config *docker.ContainerConfig) {
 	// set the proxy variables
 	httpProxyEvar := os.Getenv("HTTP_PROXY")
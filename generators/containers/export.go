@@ -0,0 +1,32 @@
+package containers
+
+import (
+	"fmt"
+
+	"github.com/nanobox-io/golang-docker-client"
+
+	"github.com/nanobox-io/nanobox/util/config"
+)
+
+// ExportConfig generates the container configuration for the export
+// container. It only holds the build and app volumes so they can be copied
+// out, the command exits right away.
+func ExportConfig(image string) docker.ContainerConfig {
+	env := config.EnvID()
+
+	return docker.ContainerConfig{
+		Name:  ExportName(),
+		Image: image,
+		Binds: []string{
+			fmt.Sprintf("nanobox_%s_build:/data", env),
+			fmt.Sprintf("nanobox_%s_app:/mnt/app", env),
+		},
+		Cmd:           []string{"true"},
+		RestartPolicy: "no",
+	}
+}
+
+// ExportName returns the name of the export container
+func ExportName() string {
+	return fmt.Sprintf("nanobox_%s_export", config.EnvID())
+}
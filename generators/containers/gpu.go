@@ -0,0 +1,34 @@
+package containers
+
+import (
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	"github.com/nanobox-io/nanobox/util/provider"
+)
+
+// GPURequested returns true if the boxfile asks for the host gpus with
+// run.config.gpu
+func GPURequested(box boxfile.Boxfile) bool {
+	return box.Node("run.config").BoolValue("gpu")
+}
+
+// setGPUVars exposes the host gpus to the container when the boxfile asks
+// for them and the provider can pass them through. The nvidia runtime only
+// mounts the devices and driver libraries listed in these variables.
+func setGPUVars(config *docker.ContainerConfig, box boxfile.Boxfile) {
+	if !GPURequested(box) {
+		return
+	}
+
+	if !provider.GPUSupported() {
+		lumber.Info("gpu requested but not supported by the %s provider", provider.Name())
+		return
+	}
+
+	config.Env = append(config.Env,
+		"NVIDIA_VISIBLE_DEVICES=all",
+		"NVIDIA_DRIVER_CAPABILITIES=compute,utility",
+	)
+}
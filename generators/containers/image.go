@@ -0,0 +1,26 @@
+package containers
+
+import (
+	"fmt"
+
+	"github.com/nanobox-io/golang-docker-client"
+
+	"github.com/nanobox-io/nanobox/util/config"
+)
+
+// ImageConfig generates the container configuration for the container the
+// app image is committed from. The compiled app is copied in before the
+// commit, the command exits right away.
+func ImageConfig(image string) docker.ContainerConfig {
+	return docker.ContainerConfig{
+		Name:          ImageName(),
+		Image:         image,
+		Cmd:           []string{"true"},
+		RestartPolicy: "no",
+	}
+}
+
+// ImageName returns the name of the image container
+func ImageName() string {
+	return fmt.Sprintf("nanobox_%s_image", config.EnvID())
+}
@@ -0,0 +1,42 @@
+package containers
+
+import (
+	"fmt"
+
+	"github.com/nanobox-io/golang-docker-client"
+
+	"github.com/nanobox-io/nanobox/models"
+)
+
+// the path of the dev runtime, the build hooks normally set this up
+const jobPath = "PATH=/data/sbin:/data/bin:/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// JobConfig generates the container configuration for a one-off job. Code
+// nodes run in the dev runtime with the code and build mounted, data
+// components run in their own image. The app evars are injected either way.
+func JobConfig(appModel *models.App, componentModel *models.Component, id, ip, command string) docker.ContainerConfig {
+	config := DevConfig(appModel)
+	shell := fmt.Sprintf("cd /app && %s", command)
+
+	if componentModel.Type == "data" {
+		config = ComponentConfig(componentModel)
+		shell = command
+	} else {
+		config.Env = append(config.Env, jobPath)
+	}
+
+	config.Name = JobName(appModel, id)
+	config.IP = ip
+	config.RestartPolicy = "no"
+	config.Cmd = []string{"/bin/sh", "-c", shell}
+
+	setAppEvars(&config, appModel, componentModel.Name)
+	setAppSecrets(&config, appModel)
+
+	return config
+}
+
+// JobName returns the name of a job container
+func JobName(appModel *models.App, id string) string {
+	return fmt.Sprintf("nanobox_%s_job_%s", appModel.ID, id)
+}
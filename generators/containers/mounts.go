@@ -0,0 +1,44 @@
+package containers
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+
+	"github.com/nanobox-io/nanobox/util/hostmount"
+	"github.com/nanobox-io/nanobox/util/hostpath"
+	"github.com/nanobox-io/nanobox/util/provider"
+)
+
+// HostMountSource returns where a host directory the boxfile mounts is bound
+// from: its share in the vm, or the directory itself when docker runs on
+// the host
+func HostMountSource(envID string, m hostmount.Mount) string {
+	if provider.RequiresMount() {
+		return fmt.Sprintf("%s%s/mounts/%s", provider.HostShareDir(), envID, m.ShareName())
+	}
+
+	return hostDir(m.Host)
+}
+
+// hostDir returns a directory of the host the way docker binds it when it
+// runs on the host, a windows drive or UNC path is written as docker takes it
+func hostDir(dir string) string {
+	return hostpath.Docker(dir, runtime.GOOS)
+}
+
+// setHostMounts binds the host directories the boxfile mounts into the
+// container
+func setHostMounts(config *docker.ContainerConfig, envID string, mounts []string) {
+	for _, spec := range mounts {
+		m, err := hostmount.Parse(spec, "")
+		if err != nil {
+			lumber.Error("containers:setHostMounts:hostmount.Parse(%s): %s", spec, err.Error())
+			continue
+		}
+
+		config.Binds = append(config.Binds, m.Bind(HostMountSource(envID, m)))
+	}
+}
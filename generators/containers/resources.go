@@ -0,0 +1,111 @@
+package containers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/engine-api/types/container"
+	"github.com/nanobox-io/golang-docker-client"
+	"github.com/nanobox-io/nanobox-boxfile"
+)
+
+// cpuPeriod is the cfs period used to express cpu limits as a quota
+const cpuPeriod = 100000
+
+// Resources generates the resource limits from the config node of a
+// component's boxfile node, ie:
+//
+//	data.db:
+//	  config:
+//	    cpu: 1.5      # cores
+//	    memory: 512m  # plain numbers are megabytes
+func Resources(box boxfile.Boxfile) (container.Resources, error) {
+	resources := container.Resources{}
+	config := box.Node("config")
+
+	cpu, err := parseCPU(config.Value("cpu"))
+	if err != nil {
+		return resources, err
+	}
+
+	if cpu > 0 {
+		resources.CPUPeriod = cpuPeriod
+		resources.CPUQuota = int64(cpu * cpuPeriod)
+	}
+
+	memory, err := parseMemory(config.Value("memory"))
+	if err != nil {
+		return resources, err
+	}
+
+	if memory > 0 {
+		resources.Memory = memory
+		// disable swap so the limit is a hard limit
+		resources.MemorySwap = memory
+	}
+
+	return resources, nil
+}
+
+// parseCPU parses a number of cores
+func parseCPU(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case string:
+		cpu, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid cpu limit '%s'", v)
+		}
+		return cpu, nil
+	}
+
+	return 0, fmt.Errorf("invalid cpu limit '%v'", value)
+}
+
+// parseMemory parses a memory size into bytes. Sizes can be suffixed with
+// k, m or g; plain numbers are megabytes
+func parseMemory(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return int64(v) << 20, nil
+	case float64:
+		return int64(v * (1 << 20)), nil
+	case string:
+		size := strings.ToLower(strings.TrimSpace(v))
+		size = strings.TrimSuffix(size, "b")
+
+		unit := int64(1 << 20)
+		switch {
+		case strings.HasSuffix(size, "k"):
+			unit = 1 << 10
+		case strings.HasSuffix(size, "m"):
+			unit = 1 << 20
+		case strings.HasSuffix(size, "g"):
+			unit = 1 << 30
+		}
+		size = strings.TrimRight(size, "kmg")
+
+		n, err := strconv.ParseFloat(size, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid memory limit '%s'", v)
+		}
+		return int64(n * float64(unit)), nil
+	}
+
+	return 0, fmt.Errorf("invalid memory limit '%v'", value)
+}
+
+// SetLimits puts the memory limit on the config a container is created with,
+// so it's never without it. The cpu limit is applied once it exists.
+func SetLimits(config *docker.ContainerConfig, resources container.Resources) {
+	config.Memory = resources.Memory
+	config.MemorySwap = resources.MemorySwap
+}
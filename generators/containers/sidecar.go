@@ -0,0 +1,24 @@
+package containers
+
+import (
+	"github.com/nanobox-io/golang-docker-client"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	"github.com/nanobox-io/nanobox/models"
+)
+
+// SidecarConfig generates the container configuration for a sidecar. The
+// sidecar shares the app evars with the web and worker components and runs
+// the node's start command, if any, in place of the image default.
+func SidecarConfig(appModel *models.App, componentModel *models.Component, node boxfile.Boxfile) docker.ContainerConfig {
+	config := ComponentConfig(componentModel)
+
+	setAppEvars(&config, appModel, componentModel.Name)
+	setAppSecrets(&config, appModel)
+
+	if start := node.StringValue("start"); start != "" {
+		config.Cmd = []string{"/bin/sh", "-c", start}
+	}
+
+	return config
+}
@@ -5,11 +5,13 @@ import (
 
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/util/dns"
+	"github.com/nanobox-io/nanobox/util/interpolate"
+	"github.com/nanobox-io/nanobox/util/secretref"
 )
 
 func DevPayload(appModel *models.App) string {
 	// create an APP_IP evar
-	evars := appModel.Evars
+	evars := interpolate.Expanded(secretref.Resolved(appModel.Evars))
 	evars["APP_IP"] = appModel.LocalIPs["env"]
 
 	rtn := map[string]interface{}{}
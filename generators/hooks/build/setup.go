@@ -2,18 +2,30 @@ package build
 
 import (
 	"encoding/json"
+
+	"github.com/nanobox-io/nanobox/util/config"
 )
 
 var ClearPkgCache bool
 
 // SetupPayload returns a string for the user hook payload
 func SetupPayload() string {
+	rtn := map[string]string{}
+
 	if ClearPkgCache {
-		rtn := map[string]string{}
 		rtn["clear_cache"] = "true"
-		bytes, _ := json.Marshal(rtn)
-		return string(bytes)
 	}
-	// currently, this payload is empty. This may change at some point
-	return emptyPayload()
+
+	// the engine_path or the fetched copy of a pinned engine is mounted
+	// into the build, it's used instead of fetching the engine
+	if engineDir, _ := config.EngineDir(); engineDir != "" {
+		rtn["engine_dir"] = "/share/engine"
+	}
+
+	if len(rtn) == 0 {
+		return emptyPayload()
+	}
+
+	bytes, _ := json.Marshal(rtn)
+	return string(bytes)
 }
@@ -6,6 +6,8 @@ import (
 	"github.com/nanobox-io/nanobox-boxfile"
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/util/dns"
+	"github.com/nanobox-io/nanobox/util/interpolate"
+	"github.com/nanobox-io/nanobox/util/secretref"
 )
 
 type (
@@ -63,7 +65,7 @@ func ConfigurePayload(appModel *models.App, componentModel *models.Component) st
 		Mounts:       mounts(appModel, componentModel),
 		WritableDirs: boxfile.Node(componentModel.Name).Value("writable_dirs"),
 		Transform:    boxfile.Node("deploy.config").Value("transform"),
-		Env:          appModel.Evars,
+		Env:          interpolate.Expanded(secretref.Resolved(appModel.EvarsFor(componentModel.Name))),
 		LogWatches:   boxfile.Node(componentModel.Name).Value("log_watch"),
 		Start:        boxfile.Node(componentModel.Name).Value("start"),
 		Cwd:          boxfile.Node(componentModel.Name).Value("cwd"),
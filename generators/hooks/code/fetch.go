@@ -18,7 +18,7 @@ type (
 )
 
 // Fetch payload
-func FetchPayload(componentModel *models.Component, warehouse string) string {
+func FetchPayload(componentModel *models.Component, build, warehouse string) string {
 
 	logvac, _ := models.FindComponentBySlug(componentModel.AppID, "logvac")
 
@@ -30,7 +30,7 @@ func FetchPayload(componentModel *models.Component, warehouse string) string {
 			ID:   componentModel.ID,
 		},
 		Member:         map[string]int{"uid": 1},
-		Build:          "1234",
+		Build:          build,
 		Warehouse:      warehouse,
 		WarehouseToken: "123",
 	}
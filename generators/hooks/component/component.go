@@ -21,6 +21,12 @@ func componentConfig(component *models.Component) (config map[string]interface{}
 	box := boxfile.New([]byte(env.BuiltBoxfile))
 	config = box.Node(component.Name).Node("config").Parsed
 
+	// resource limits, seeding and evars are handled by nanobox, not the hooks
+	delete(config, "cpu")
+	delete(config, "memory")
+	delete(config, "seed")
+	delete(config, "evars")
+
 	switch component.Name {
 	case "portal", "logvac", "hoarder", "mist":
 		config["token"] = "123"
@@ -38,7 +38,7 @@ func BuildRoutes(appModel *models.App) []portal.Route {
 
 		routes = append(routes, portal.Route{
 			Path:    "/",
-			Targets: []string{fmt.Sprintf("http://%s:%s", component.IPAddr(), "8080")},
+			Targets: []string{WebTarget(component)},
 		})
 	}
 
@@ -46,6 +46,90 @@ func BuildRoutes(appModel *models.App) []portal.Route {
 	return routes
 }
 
+// DrainPage is served by the router while the web components restart
+const DrainPage = "<html><body><h1>Restarting</h1><p>The app is restarting, refresh in a moment.</p></body></html>"
+
+// BuildDrainRoutes builds the same routes as BuildRoutes but serves the
+// drain page instead of sending traffic to the web components
+func BuildDrainRoutes(appModel *models.App) []portal.Route {
+	routes := BuildRoutes(appModel)
+
+	for i := range routes {
+		routes[i].Targets = nil
+		routes[i].Page = DrainPage
+	}
+
+	return routes
+}
+
+// canarySlots is how finely the traffic of a canary is split. The router
+// balances evenly over the targets of a route, so the share of the canary is
+// set by how often each target is listed.
+const canarySlots = 10
+
+// BuildCanaryRoutes builds the same routes as BuildRoutes but splits the
+// traffic between the web components and the stable components they
+// replace. stable holds the stable component of each web node.
+func BuildCanaryRoutes(appModel *models.App, stable map[string]*models.Component, percent int) []portal.Route {
+	routes := BuildRoutes(appModel)
+
+	// the stable target of each canary target
+	stableTargets := map[string]string{}
+	for name, stableModel := range stable {
+		component, err := models.FindComponentBySlug(appModel.ID, name)
+		if err != nil || component.ID == "" {
+			continue
+		}
+		stableTargets[WebTarget(component)] = WebTarget(stableModel)
+	}
+
+	canary := CanaryShare(percent)
+
+	for i := range routes {
+		targets := []string{}
+
+		for _, target := range routes[i].Targets {
+			stableTarget, ok := stableTargets[target]
+			if !ok {
+				targets = append(targets, target)
+				continue
+			}
+
+			for slot := 0; slot < canarySlots; slot++ {
+				if slot < canary {
+					targets = append(targets, target)
+				} else {
+					targets = append(targets, stableTarget)
+				}
+			}
+		}
+
+		routes[i].Targets = targets
+	}
+
+	return routes
+}
+
+// CanaryShare returns how many of the canary slots go to the canary. Both
+// the canary and the stable components always get some of the traffic.
+func CanaryShare(percent int) int {
+	slots := (percent*canarySlots + 50) / 100
+
+	switch {
+	case slots < 1:
+		return 1
+	case slots >= canarySlots:
+		return canarySlots - 1
+	}
+
+	return slots
+}
+
+// WebTarget returns the router target of a web component
+func WebTarget(component *models.Component) string {
+	return fmt.Sprintf("http://%s:%s", component.IPAddr(), "8080")
+}
+
 // buildRoutes ...
 //
 // Route struct {
@@ -85,7 +169,7 @@ func buildComponentRoutes(boxfile boxfile.Boxfile, component *models.Component)
 			Path:      path,
 		}
 
-		portalRoute.Targets = append(portalRoute.Targets, fmt.Sprintf("http://%s:%s", component.IPAddr(), "8080"))
+		portalRoute.Targets = append(portalRoute.Targets, WebTarget(component))
 		portalRoutes = append(portalRoutes, portalRoute)
 	}
 
@@ -11,7 +11,7 @@ func Endpoint(envModel *models.Env, args []string, maxArgs int) ([]string, strin
 	case "local":
 		return args[1:], "local", "dev"
 	case "dry-run":
-		return args[1:], "local", "sim"
+		return args[1:], "local", envModel.DryRunName()
 	default:
 		_, ok := envModel.Remotes[args[0]]
 		if ok {
@@ -12,7 +12,6 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"path/filepath"
 	"runtime"
 	"runtime/debug"
 	"strings"
@@ -24,22 +23,18 @@ import (
 	"github.com/nanobox-io/nanobox/processors"
 	proc_provider "github.com/nanobox-io/nanobox/processors/provider"
 	"github.com/nanobox-io/nanobox/util"
-	"github.com/nanobox-io/nanobox/util/config"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/logging"
 	"github.com/nanobox-io/nanobox/util/provider"
 )
 
 // main
 func main() {
-	// setup a file logger, this will be replaced in verbose mode.
-	fileLogger, err := lumber.NewTruncateLogger(filepath.ToSlash(filepath.Join(config.GlobalDir(), "nanobox.log")))
-	if err != nil {
+	// log every command to ~/.nanobox/logs, the internal commands continue
+	// the log of the command that ran them
+	if err := logging.Start(!internalCommand()); err != nil {
 		fmt.Println("logging error:", err)
 	}
-
-	//
-	lumber.SetLogger(fileLogger)
-	lumber.Level(lumber.INFO)
 	defer lumber.Close()
 
 	// if it is running the server just run it
@@ -54,7 +49,7 @@ func main() {
 		err := commands.NanoboxCmd.Execute()
 		if err != nil {
 			fmt.Println(err)
-			os.Exit(1)
+			os.Exit(util.ExitUser)
 		}
 		return
 	}
@@ -62,7 +57,7 @@ func main() {
 	// verify that we support the prompt they are using
 	if badTerminal() {
 		display.BadTerminal()
-		os.Exit(1)
+		os.Exit(util.ExitUser)
 	}
 
 	// do the commands configure check here
@@ -93,7 +88,7 @@ func main() {
 			if len(missingParts) > 0 {
 				display.MissingDependencies(providerName, missingParts)
 			}
-			os.Exit(1)
+			os.Exit(util.ExitProvider)
 		}
 	}
 
@@ -112,13 +107,26 @@ func main() {
 			lumber.Fatal(fmt.Sprintf("Cause of failure: %v", r))
 			lumber.Fatal(fmt.Sprintf("Error output:\n%v\n", string(stack)))
 			lumber.Close()
-			fmt.Println("Nanobox encountered an unexpected error. Please see ~/.nanobox/nanobox.log and submit the issue to us.")
-			os.Exit(1)
+			fmt.Println("Nanobox encountered an unexpected error. Please see ~/.nanobox/logs/nanobox.log and submit the issue to us.")
+			os.Exit(util.ExitInternal)
 		}
 	}()
 
-	//
-	commands.NanoboxCmd.Execute()
+	// a bad flag or argument
+	if err := commands.NanoboxCmd.Execute(); err != nil {
+		os.Exit(util.ExitUser)
+	}
+}
+
+// internalCommand returns true when nanobox runs itself, ie: with privileges
+func internalCommand() bool {
+	for _, arg := range os.Args {
+		if arg == "--internal" {
+			return true
+		}
+	}
+
+	return false
 }
 
 func badTerminal() bool {
@@ -134,7 +142,7 @@ LOOP:
 		case "run":
 			found = true
 			lastLocation = i
-		case "--debug", "--trace", "--verbose", "-t", "-v":
+		case "--debug", "--trace", "--verbose", "-t", "-v", "-vv":
 			// if we hit a argument of ours after 'found'
 			// we will reset the last location
 			if found == true {
@@ -1,11 +1,24 @@
 package models
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/nanobox-io/nanobox/util/config"
 )
 
+// TestAppName is the name of the app nanobox test brings up, and tears
+// down, for every run
+const TestAppName = "test"
+
+// PreviewPrefix starts the names of the preview environments of the git
+// branches, ie: preview-feature-login
+const PreviewPrefix = "preview-"
+
 // App ...
 type App struct {
 	EnvID string
@@ -16,6 +29,9 @@ type App struct {
 	Status string
 	// Appironment variables available to the environment
 	Evars map[string]string
+	// evars only a component sees, by component name. They take precedence
+	// over the evars of the app.
+	ComponentEvars map[string]map[string]string
 	// There are also certain platform service ips that need to 1) remain constant
 	// even if the component were repaired and 2) be available even before the
 	// component is. logvac and mist ips are examples. We'll store those here.
@@ -26,6 +42,10 @@ type App struct {
 	Key string
 	// the https cert used
 	Cert string
+	// the git branch of a preview environment, and when it was last
+	// previewed
+	Branch      string
+	PreviewedAt time.Time
 }
 
 // IsNew returns true if the App hasn't been created yet
@@ -33,8 +53,17 @@ func (a *App) IsNew() bool {
 	return a.ID == ""
 }
 
+// IsPreview returns true if the App is the preview environment of a branch
+func (a *App) IsPreview() bool {
+	return strings.HasPrefix(a.Name, PreviewPrefix)
+}
+
 // Save persists the App to the database
 func (a *App) Save() error {
+	if err := a.Validate(); err != nil {
+		return fmt.Errorf("invalid app: %s", err.Error())
+	}
+
 	if err := put(a.EnvID, a.ID, a); err != nil {
 		return fmt.Errorf("failed to save app: %s", err.Error())
 	}
@@ -58,8 +87,13 @@ func (a *App) DisplayName() string {
 		return "local"
 	case "sim":
 		return "dry-run"
+	case TestAppName:
+		return "test"
 	}
-	return a.Name
+	if a.IsPreview() {
+		return fmt.Sprintf("preview %s", strings.TrimPrefix(a.Name, PreviewPrefix))
+	}
+	return fmt.Sprintf("dry-run %s", a.Name)
 }
 
 // Generate populates an App with data and persists the record
@@ -79,8 +113,8 @@ func (a *App) Generate(env *Env, name string) error {
 	a.EnvID = env.ID
 	a.ID = fmt.Sprintf("%s_%s", env.ID, name)
 	a.Name = name
-	a.State = "initialized"
-	a.Status = "up"
+	a.State = StateInitialized
+	a.Status = StatusUp
 	a.LocalIPs = map[string]string{}
 	a.Evars = map[string]string{
 		"APP_NAME": name,
@@ -89,6 +123,62 @@ func (a *App) Generate(env *Env, name string) error {
 	return a.Save()
 }
 
+// GeneratedEvars returns the keys of the evars nanobox generates for the
+// app, like APP_NAME and the credentials of the data components
+func (a *App) GeneratedEvars() (map[string]bool, error) {
+	generated := map[string]bool{"APP_NAME": true}
+
+	components, err := a.Components()
+	if err != nil {
+		return generated, err
+	}
+
+	for key := range a.Evars {
+		for _, component := range components {
+			if component.Type == "data" && strings.HasPrefix(key, component.EvarPrefix()+"_") {
+				generated[key] = true
+			}
+		}
+	}
+
+	return generated, nil
+}
+
+// EvarsFor returns the evars a component runs with, the evars of the app
+// with the ones scoped to the component on top
+func (a *App) EvarsFor(component string) map[string]string {
+	evars := map[string]string{}
+
+	for key, val := range a.Evars {
+		evars[key] = val
+	}
+
+	for key, val := range a.ComponentEvars[component] {
+		evars[key] = val
+	}
+
+	return evars
+}
+
+// EvarsSum returns a checksum of the evars of a component, it changes
+// whenever one of them is added, changed or removed
+func (a *App) EvarsSum(component string) string {
+	evars := a.EvarsFor(component)
+
+	keys := []string{}
+	for key := range evars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := md5.New()
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%s=%s\x00", key, evars[key])
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
 // Env ...
 func (a *App) Env() (*Env, error) {
 	return FindEnvByID(a.EnvID)
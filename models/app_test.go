@@ -115,3 +115,45 @@ func TestAllAppsByEnv(t *testing.T) {
 		t.Errorf("did not load all apps, got %d", len(apps))
 	}
 }
+
+func TestAppEvarsFor(t *testing.T) {
+	app := App{
+		Evars: map[string]string{"APP_NAME": "dev", "LOG_LEVEL": "info"},
+		ComponentEvars: map[string]map[string]string{
+			"worker.jobs": {"LOG_LEVEL": "debug", "QUEUE": "default"},
+		},
+	}
+
+	worker := app.EvarsFor("worker.jobs")
+	if worker["LOG_LEVEL"] != "debug" || worker["QUEUE"] != "default" || worker["APP_NAME"] != "dev" {
+		t.Errorf("unexpected worker evars %v", worker)
+	}
+
+	web := app.EvarsFor("web.main")
+	if web["LOG_LEVEL"] != "info" || web["QUEUE"] != "" {
+		t.Errorf("unexpected web evars %v", web)
+	}
+
+	if app.EvarsSum("web.main") == app.EvarsSum("worker.jobs") {
+		t.Errorf("components with other evars should have other sums")
+	}
+}
+
+func TestAppDisplayName(t *testing.T) {
+	tests := map[string]string{
+		"dev":                   "local",
+		"sim":                   "dry-run",
+		"staging":               "dry-run staging",
+		PreviewPrefix + "login": "preview login",
+	}
+
+	for name, expected := range tests {
+		app := App{Name: name}
+		if app.DisplayName() != expected {
+			t.Errorf("%s: expected '%s' got '%s'", name, expected, app.DisplayName())
+		}
+		if app.IsPreview() != (name == PreviewPrefix+"login") {
+			t.Errorf("%s: wrong preview", name)
+		}
+	}
+}
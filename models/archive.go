@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Archive is a dormant app whose state and data have been packed into a
+// single file on the host
+type Archive struct {
+	ID        string // the id of the app that was archived
+	EnvID     string
+	AppName   string
+	Path      string // location of the archive on the host
+	Size      int64
+	CreatedAt time.Time
+}
+
+// IsNew returns true if the Archive hasn't been created yet
+func (a *Archive) IsNew() bool {
+	return a.ID == ""
+}
+
+// Save persists the Archive to the database
+func (a *Archive) Save() error {
+
+	if err := put("archives", a.ID, a); err != nil {
+		return fmt.Errorf("failed to save archive: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Delete deletes the archive record from the database
+func (a *Archive) Delete() error {
+
+	if err := destroy("archives", a.ID); err != nil {
+		return fmt.Errorf("failed to delete archive: %s", err.Error())
+	}
+
+	return nil
+}
+
+// FindArchiveByApp finds an archive by the id of the app it was created from
+func FindArchiveByApp(appID string) (*Archive, error) {
+
+	archive := &Archive{}
+
+	if err := get("archives", appID, &archive); err != nil {
+		return archive, fmt.Errorf("failed to load archive: %s", err.Error())
+	}
+
+	return archive, nil
+}
+
+// AllArchives loads all of the Archives in the database
+func AllArchives() ([]*Archive, error) {
+	// list of archives to return
+	archives := []*Archive{}
+
+	return archives, getAll("archives", &archives)
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestArchiveSave(t *testing.T) {
+	// clear the archives table when we're finished
+	defer truncate("archives")
+
+	archive := Archive{
+		ID:      "123_dev",
+		EnvID:   "123",
+		AppName: "dev",
+		Path:    "/tmp/123_dev.tar.gz",
+	}
+
+	if err := archive.Save(); err != nil {
+		t.Error(err)
+	}
+
+	archive2, err := FindArchiveByApp("123_dev")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if archive2.Path != "/tmp/123_dev.tar.gz" || archive2.AppName != "dev" {
+		t.Errorf("did not load the correct archive")
+	}
+}
+
+func TestArchiveDelete(t *testing.T) {
+	// clear the archives table when we're finished
+	defer truncate("archives")
+
+	archive := Archive{ID: "123_dev", EnvID: "123", AppName: "dev"}
+
+	if err := archive.Save(); err != nil {
+		t.Error(err)
+	}
+
+	if err := archive.Delete(); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := FindArchiveByApp("123_dev"); err == nil {
+		t.Errorf("archive was not deleted")
+	}
+}
@@ -2,12 +2,16 @@ package models
 
 import (
 	"fmt"
+
+	"github.com/nanobox-io/nanobox/util/keychain"
 )
 
 // Auth ...
 type Auth struct {
-	Endpoint string // nanobox, bonesalt, dev, sim
-	Key      string // api_token from dashboard
+	Endpoint   string // nanobox, bonesalt, dev, sim
+	Key        string // api_token from dashboard
+	RefreshKey string // renews the api_token once it expires, if the login provided one
+	Keychain   bool   // the keys are kept in the os keychain instead of the db
 }
 
 // determines if the auth record is new
@@ -18,8 +22,18 @@ func (a *Auth) IsNew() bool {
 // Save persists the Auth to the database
 func (a *Auth) Save() error {
 
+	// the keys go in the os keychain when there is one, the db only
+	// remembers where to find them. Without a keychain they stay in the db.
+	record := *a
+	record.Keychain = false
+	if a.Key != "" && a.storeKeys() == nil {
+		record.Key = ""
+		record.RefreshKey = ""
+		record.Keychain = true
+	}
+
 	// Since there is only ever a single auth value, we'll use the registry
-	if err := put("auths", a.Endpoint, a); err != nil {
+	if err := put("auths", a.Endpoint, record); err != nil {
 		return fmt.Errorf("failed to save auth: %s", err.Error())
 	}
 
@@ -32,6 +46,47 @@ func (a *Auth) Delete() error {
 	return DeleteAuth(a.Endpoint)
 }
 
+// storeKeys puts the keys in the keychain
+func (a *Auth) storeKeys() error {
+	if err := keychain.Set(a.Endpoint, a.Key); err != nil {
+		return err
+	}
+
+	if a.RefreshKey == "" {
+		return keychain.Delete(refreshAccount(a.Endpoint))
+	}
+
+	return keychain.Set(refreshAccount(a.Endpoint), a.RefreshKey)
+}
+
+// loadKey reads the keys from the keychain, keys still in the db are moved
+// to the keychain as they are loaded
+func (a *Auth) loadKey() error {
+	if !a.Keychain {
+		// best effort, the keys stay in the db if they can't be moved
+		if a.Key != "" && a.storeKeys() == nil {
+			put("auths", a.Endpoint, Auth{Endpoint: a.Endpoint, Keychain: true})
+		}
+		return nil
+	}
+
+	key, err := keychain.Get(a.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to load auth from keychain: %s", err.Error())
+	}
+	a.Key = key
+
+	// not every login comes with a refresh key
+	a.RefreshKey, _ = keychain.Get(refreshAccount(a.Endpoint))
+
+	return nil
+}
+
+// refreshAccount is the keychain account of the refresh key of an endpoint
+func refreshAccount(endpoint string) string {
+	return endpoint + "/refresh"
+}
+
 // LoadAuth loads the default (nanobox) auth entry
 func LoadAuth() (*Auth, error) {
 	auth := &Auth{
@@ -42,7 +97,7 @@ func LoadAuth() (*Auth, error) {
 		return auth, fmt.Errorf("failed to load auth: %s", err.Error())
 	}
 
-	return auth, nil
+	return auth, auth.loadKey()
 }
 
 // loads an auth by a specific endpoint
@@ -55,12 +110,16 @@ func LoadAuthByEndpoint(endpoint string) (*Auth, error) {
 		return auth, fmt.Errorf("failed to load auth: %s", err.Error())
 	}
 
-	return auth, nil
+	return auth, auth.loadKey()
 }
 
 // DeleteAuth ...
 func DeleteAuth(endpoint string) error {
 
+	// there may be nothing in the keychain, the db is what matters
+	keychain.Delete(endpoint)
+	keychain.Delete(refreshAccount(endpoint))
+
 	if err := destroy("auths", endpoint); err != nil {
 		return fmt.Errorf("failed to delete auth: %s", err.Error())
 	}
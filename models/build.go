@@ -0,0 +1,61 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Build is a build that is running for an env, it's removed once the build
+// finishes
+type Build struct {
+	EnvID   string
+	PID     int    // the nanobox process running the build
+	Remote  string // the docker host of a remote build
+	Started time.Time
+}
+
+// Save persists the Build to the database
+func (b *Build) Save() error {
+
+	if err := put("builds", b.EnvID, b); err != nil {
+		return fmt.Errorf("failed to save build: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Claim saves the build unless check refuses the build already recorded for
+// the env. The check and the save happen in a single transaction, so two
+// builds can't both find the env free and claim it.
+func (b *Build) Claim(check func(active *Build) error) error {
+
+	active := &Build{}
+	return swap("builds", b.EnvID, active, b, func(found bool) error {
+		if !found {
+			return nil
+		}
+		return check(active)
+	})
+}
+
+// Delete deletes the build record from the database
+func (b *Build) Delete() error {
+
+	if err := destroy("builds", b.EnvID); err != nil {
+		return fmt.Errorf("failed to delete build: %s", err.Error())
+	}
+
+	return nil
+}
+
+// FindBuildByEnv finds the running build of an env
+func FindBuildByEnv(envID string) (*Build, error) {
+
+	build := &Build{}
+
+	if err := get("builds", envID, &build); err != nil {
+		return build, fmt.Errorf("failed to load build: %s", err.Error())
+	}
+
+	return build, nil
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BuildRecord is a build in the build history of an env
+type BuildRecord struct {
+	EnvID       string
+	Number      int    // counts up per env, starting at 1
+	Status      string // see the Build* statuses
+	Remote      string // the docker host of a remote build
+	BuiltID     string
+	Fingerprint string
+	LogPath     string // the full output of the build
+	Started     time.Time
+	Finished    time.Time
+}
+
+// Duration returns how long the build ran
+func (b *BuildRecord) Duration() time.Duration {
+	if b.Finished.IsZero() {
+		return 0
+	}
+
+	return b.Finished.Sub(b.Started)
+}
+
+// Save persists the BuildRecord to the database
+func (b *BuildRecord) Save() error {
+
+	if err := put("build_history", buildRecordKey(b.EnvID, b.Number), b); err != nil {
+		return fmt.Errorf("failed to save build record: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Delete deletes the build record from the database
+func (b *BuildRecord) Delete() error {
+
+	if err := destroy("build_history", buildRecordKey(b.EnvID, b.Number)); err != nil {
+		return fmt.Errorf("failed to delete build record: %s", err.Error())
+	}
+
+	return nil
+}
+
+// FindBuildRecord finds a build of an env by its number
+func FindBuildRecord(envID string, number int) (*BuildRecord, error) {
+
+	record := &BuildRecord{}
+
+	if err := get("build_history", buildRecordKey(envID, number), &record); err != nil {
+		return record, fmt.Errorf("failed to load build record: %s", err.Error())
+	}
+
+	return record, nil
+}
+
+// AllBuildRecordsByEnv loads the build history of an env, oldest first
+func AllBuildRecordsByEnv(envID string) ([]*BuildRecord, error) {
+	all := []*BuildRecord{}
+
+	if err := getAll("build_history", &all); err != nil {
+		return all, fmt.Errorf("failed to load build records: %s", err.Error())
+	}
+
+	records := buildRecords{}
+	for _, record := range all {
+		if record.EnvID == envID {
+			records = append(records, record)
+		}
+	}
+	sort.Sort(records)
+
+	return records, nil
+}
+
+// buildRecordKey returns the database key of a build record
+func buildRecordKey(envID string, number int) string {
+	return fmt.Sprintf("%s-%d", envID, number)
+}
+
+// buildRecords sorts build records by number
+type buildRecords []*BuildRecord
+
+func (b buildRecords) Len() int           { return len(b) }
+func (b buildRecords) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b buildRecords) Less(i, j int) bool { return b[i].Number < b[j].Number }
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestAllBuildRecordsByEnv(t *testing.T) {
+	// clear the build history when we're finished
+	defer truncate("build_history")
+
+	records := []BuildRecord{
+		{EnvID: "123", Number: 10, Status: BuildFailed},
+		{EnvID: "123", Number: 9, Status: BuildComplete},
+		{EnvID: "456", Number: 1, Status: BuildComplete},
+	}
+
+	for i := range records {
+		if err := records[i].Save(); err != nil {
+			t.Error(err)
+		}
+	}
+
+	history, err := AllBuildRecordsByEnv("123")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// ordered by number, not by key
+	if len(history) != 2 || history[0].Number != 9 || history[1].Number != 10 {
+		t.Errorf("did not load the build history in order: %+v", history)
+	}
+
+	record, err := FindBuildRecord("123", 10)
+	if err != nil || record.Status != BuildFailed {
+		t.Errorf("did not load the correct build record")
+	}
+}
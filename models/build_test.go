@@ -0,0 +1,43 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBuildClaim(t *testing.T) {
+	// clear the running builds when we're finished
+	defer truncate("builds")
+
+	first := &Build{EnvID: "123", PID: 1}
+	if err := first.Claim(func(active *Build) error {
+		t.Errorf("checked a build that wasn't recorded: %+v", active)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a refused claim leaves the running build in place
+	second := &Build{EnvID: "123", PID: 2}
+	err := second.Claim(func(active *Build) error {
+		return fmt.Errorf("build %d is running", active.PID)
+	})
+	if err == nil || err.Error() != "build 1 is running" {
+		t.Errorf("expected the claim to be refused, got %v", err)
+	}
+
+	build, err := FindBuildByEnv("123")
+	if err != nil || build.PID != 1 {
+		t.Errorf("expected build 1 to be running, got %+v", build)
+	}
+
+	// an accepted claim takes over
+	if err := second.Claim(func(active *Build) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	build, err = FindBuildByEnv("123")
+	if err != nil || build.PID != 2 {
+		t.Errorf("expected build 2 to be running, got %+v", build)
+	}
+}
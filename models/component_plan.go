@@ -1,5 +1,7 @@
 package models
 
+import "encoding/json"
+
 // ComponentPlan ...
 type ComponentPlan struct {
 	IPs           []string            `json:"ips"`
@@ -7,13 +9,19 @@ type ComponentPlan struct {
 	MountProtocol string              `json:"mount_protocol"`
 	Behaviors     []string            `json:"behaviors"`
 	DefaultUser   string              `json:"user"`
+	// connection metadata used to build the {prefix}_URL evar
+	Scheme   string `json:"scheme"`
+	Port     int    `json:"port"`
+	Database string `json:"database"`
 }
 
 // ComponentPlanUser ...
 type ComponentPlanUser struct {
-	Username string                 `json:"username"`
-	Password string                 `json:"password"`
-	Meta     map[string]interface{} `json:"meta"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// whatever else the plan hook has to say about the user, nanobox passes
+	// it back to the hooks untouched
+	Meta json.RawMessage `json:"meta,omitempty"`
 }
 
 // BehaviorPresent ...
@@ -1,11 +1,73 @@
 package models
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+	"gopkg.in/yaml.v2"
+
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/creds"
+)
+
+// the layers of the configuration, each overrides the ones before it
+const (
+	ConfigDefault = "default"
+	ConfigGlobal  = "global"
+	ConfigApp     = "app"
+	ConfigEnv     = "env"
+	ConfigFlag    = "flag"
+)
+
+// configFlags are the keys set with --config for the command that runs
+var configFlags = map[string]string{}
+
+// configAliases are the other names the keys go by
+var configAliases = map[string]string{
+	"mount-options":      "netfs-mount-opts",
+	"use-encrypted-keys": "ssh-encrypted-keys",
+}
+
+// EndpointKeys are the keys that point nanobox at a server, to download the
+// vm, its packages, the images or the signing key from, or to send the logs,
+// the events and the metrics to. The app config is committed with the code,
+// so its values for these are only used once they're trusted on the machine,
+// see Env.TrustedEndpoints.
+var EndpointKeys = []string{
+	"boot2docker-url",
+	"tce-mirror",
+	"registry-mirror",
+	"telemetry-endpoint",
+	"log-forward",
+	"webhooks",
+	"cosign-key",
+}
+
+// the mount types, how the code of an app is shared into the vm
+const (
+	MountVboxsf = "vboxsf" // virtualbox shared folders
+	MountNFS    = "nfs"    // nfs, smb on windows
+	MountSync   = "sync"   // vboxsf, with the changed files synced into volumes
 )
 
+// MountTypes are the mount types mount-type takes
+var MountTypes = []string{MountVboxsf, MountNFS, MountSync}
+
+// the names the mount types had before, still accepted
+var mountTypeAliases = map[string]string{
+	"native": MountVboxsf,
+	"netfs":  MountNFS,
+}
+
 // Config ...
 type Config struct {
 	Provider      string `json:"provider"`
@@ -19,6 +81,21 @@ type Config struct {
 	RAM            int    `json:"ram"`
 	Disk           int    `json:"disk"`
 
+	// grow the vm disk when a build or a pull finds it nearly full, instead
+	// of failing with what to do about it
+	AutoGrowDisk bool `json:"auto-grow-disk"`
+
+	// sync only the changed files into a volume for builds instead of
+	// building from the shared folder
+	BuildSync bool `json:"build-sync"`
+
+	// sync the code both ways between the host and a volume of the dev
+	// container in the background, instead of mounting the shared folder
+	CodeSync bool `json:"code-sync"`
+
+	// how many builds of each app are kept in the build history
+	BuildHistory int `json:"build-history"`
+
 	// ip address spaces
 	ExternalNetworkSpace      string `json:"external-network-space"`
 	DockerMachineNetworkSpace string `json:"docker-machine-network-space"`
@@ -29,18 +106,96 @@ type Config struct {
 
 	Anonymous bool `json:"anonymous"`
 	LockPort  int  `json:"lock-port"`
+
+	// seconds to wait for in-flight requests when restarting web components
+	DrainTimeout int `json:"drain-timeout"`
+
+	// password policy for generated component credentials
+	PasswordLength  int    `json:"password-length"`
+	PasswordCharset string `json:"password-charset"`
+	PasswordSeed    string `json:"password-seed"`
+
+	// the release channel nanobox updates from, and whether to check it for
+	// new releases in the background
+	UpdateChannel string `json:"update-channel"`
+	UpdateCheck   bool   `json:"update-check"`
+
+	// where the VM image, the VM's packages and the docker images come
+	// from, for networks that can only reach a mirror. registry-mirror is a
+	// comma separated list, see images.Mirrors
+	Boot2DockerURL string `json:"boot2docker-url"`
+	TCEMirror      string `json:"tce-mirror"`
+	RegistryMirror string `json:"registry-mirror"`
+
+	// how many layers of an image are downloaded at once, and how many
+	// images 'nanobox fetch' pulls at once
+	PullConcurrency int `json:"pull-concurrency"`
+
+	// anonymous usage metrics, off unless the user turns them on, see
+	// 'nanobox telemetry'
+	Telemetry         bool   `json:"telemetry"`
+	TelemetryEndpoint string `json:"telemetry-endpoint"`
+
+	// the sinks the logs of the apps are also shipped to, urls separated by
+	// commas, ie: syslog://localhost:514,loki://localhost:3100
+	LogForward string `json:"log-forward"`
+
+	// how the logs of a dry-run are streamed from mist: tcp, websocket (for
+	// networks that block mist's tcp port) or file:<path> to replay a
+	// recording offline, and the file the messages are recorded to
+	MistTransport string `json:"mist-transport"`
+	MistRecord    string `json:"mist-record"`
+
+	// the webhooks the lifecycle events are sent to, urls or unix sockets
+	// separated by commas, the secret the posts are signed with and the
+	// types of events they get, all of them if it's empty. see util/events
+	Webhooks      string `json:"webhooks"`
+	WebhookSecret string `json:"webhook-secret"`
+	WebhookEvents string `json:"webhook-events"`
+
+	// the seconds a command has to take to show a desktop notification when
+	// it finishes, 0 never shows one
+	NotifyAfter int `json:"notify-after"`
+
+	// the minutes an app can go unused before its containers are suspended,
+	// and the vm once nothing runs, 0 never suspends them
+	IdleTimeout int `json:"idle-timeout"`
+
+	// verify rejects the images and the engines whose signatures don't check
+	// out: the images are checked with cosign against the public key at
+	// cosign-key, the engines with git against the keys gpg trusts
+	Verify    bool   `json:"verify"`
+	CosignKey string `json:"cosign-key"`
+
+	// the values as they were loaded and the layer each comes from
+	loaded  map[string]string
+	sources map[string]string
 }
 
-// Save persists the Config to the database
+// Save persists the values of the Config that changed since it was loaded
+// to the global configuration, ~/.nanobox/config.yml. The values that come
+// from the app, the environment or the flags are left out of it.
 func (c *Config) Save() error {
 	// make sure the information in is valid
 	c.makeValid()
 
-	// Since there is only ever a single Config value, we'll use the registry
-	if err := put("registry", "Config", c); err != nil {
+	global, err := loadGlobalConfig()
+	if err != nil {
+		global = map[string]string{}
+	}
+
+	for key, value := range configValues(c) {
+		if c.loaded == nil || c.loaded[key] != value {
+			global[key] = value
+		}
+	}
+
+	if err := writeGlobalConfig(global); err != nil {
 		return fmt.Errorf("failed to save Config: %s", err.Error())
 	}
 
+	c.loaded = configValues(c)
+
 	return nil
 }
 
@@ -50,8 +205,11 @@ func (c *Config) makeValid() {
 		c.Provider = "docker-machine"
 	}
 
-	if c.MountType != "native" && c.MountType != "netfs" {
-		c.MountType = "native"
+	if alias, ok := mountTypeAliases[c.MountType]; ok {
+		c.MountType = alias
+	}
+	if !validMountType(c.MountType) {
+		c.MountType = MountVboxsf
 	}
 
 	if c.CPUs == 0 {
@@ -90,12 +248,52 @@ func (c *Config) makeValid() {
 		c.LockPort = 12345
 	}
 
+	if c.BuildHistory <= 0 {
+		c.BuildHistory = 20
+	}
+
+	if c.DrainTimeout <= 0 {
+		c.DrainTimeout = 10
+	}
+
+	if c.PasswordLength <= 0 {
+		c.PasswordLength = creds.DefaultLength
+	}
+
+	if !creds.ValidCharset(c.PasswordCharset) {
+		c.PasswordCharset = "alnum"
+	}
+
+	if c.UpdateChannel != "stable" && c.UpdateChannel != "beta" {
+		c.UpdateChannel = "stable"
+	}
+
+	if c.Boot2DockerURL == "" {
+		c.Boot2DockerURL = "https://d1ormdui8qdvue.cloudfront.net/boot2docker/v1/boot2docker.iso"
+	}
+
+	if c.TCEMirror == "" {
+		c.TCEMirror = "http://repo.tinycorelinux.net/7.x/x86_64/tcz"
+	}
+
+	if c.MistTransport == "" {
+		c.MistTransport = "tcp"
+	}
+
+	if c.TelemetryEndpoint == "" {
+		c.TelemetryEndpoint = "https://telemetry.nanobox.io/v1/events"
+	}
+
 }
 
-// Delete deletes the Config record from the database
+// Delete deletes the global configuration
 func (c *Config) Delete() error {
 
-	// Since there is only ever a single Config value, we'll use the registry
+	if err := os.Remove(GlobalConfig()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete Config: %s", err.Error())
+	}
+
+	// older versions kept it in the database
 	if err := destroy("registry", "Config"); err != nil {
 		return fmt.Errorf("failed to delete Config: %s", err.Error())
 	}
@@ -106,15 +304,394 @@ func (c *Config) Delete() error {
 	return nil
 }
 
-// LoadConfig loads the Config entry
+// LoadConfig loads the Config, each layer overrides the ones before it:
+// the defaults, the global configuration, the config of the app in
+// .nanobox/config.yml, NANOBOX_<KEY> environment variables and --config
+// flags. It errors when nanobox hasn't been configured yet.
 func LoadConfig() (*Config, error) {
+	values := configValues(&Config{})
+	sources := map[string]string{}
+	for key := range values {
+		sources[key] = ConfigDefault
+	}
+
+	apply := func(layer map[string]string, source string) {
+		for key, value := range layer {
+			key, err := ConfigKey(key)
+			if err != nil {
+				continue
+			}
+			values[key] = value
+			sources[key] = source
+		}
+	}
+
+	global, err := loadGlobalConfig()
+	apply(global, ConfigGlobal)
+	apply(loadAppConfig(), ConfigApp)
+	apply(envConfig(), ConfigEnv)
+	apply(configFlags, ConfigFlag)
+
 	c := &Config{}
+	if convErr := c.setValues(values); convErr != nil {
+		lumber.Error("models:LoadConfig:setValues(): %s", convErr.Error())
+	}
 	c.makeValid()
+	c.loaded = configValues(c)
+	c.sources = sources
+
+	return c, err
+}
+
+// Source returns the layer the value of a key comes from
+func (c *Config) Source(key string) string {
+	if source, ok := c.sources[key]; ok {
+		return source
+	}
+
+	return ConfigDefault
+}
+
+// Values returns the values of the configuration by key
+func (c *Config) Values() map[string]string {
+	return configValues(c)
+}
+
+// Set sets the value of a key, converted to its type
+func (c *Config) Set(key, value string) error {
+	key, err := ConfigKey(key)
+	if err != nil {
+		return err
+	}
+
+	if key == "mount-type" && !validMountType(value) && mountTypeAliases[value] == "" {
+		return fmt.Errorf("'%s' isn't a mount type, use one of %s", value, strings.Join(MountTypes, ", "))
+	}
+
+	values := configValues(c)
+	values[key] = value
+
+	return c.setValues(values)
+}
+
+// validMountType returns true if the mount type is one of MountTypes
+func validMountType(mountType string) bool {
+	for _, known := range MountTypes {
+		if mountType == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetConfigFlag overrides a key for the command that runs, from a
+// --config key=value flag
+func SetConfigFlag(pair string) error {
+	parts := strings.SplitN(pair, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("'%s' isn't a key=value pair", pair)
+	}
+
+	key, err := ConfigKey(parts[0])
+	if err != nil {
+		return err
+	}
+
+	if err := (&Config{}).Set(key, parts[1]); err != nil {
+		return err
+	}
+
+	configFlags[key] = parts[1]
+
+	return nil
+}
+
+// SetAppConfig sets a key in the config of the app, the config section of
+// .nanobox/config.yml, which is committed with the code
+func SetAppConfig(key, value string) error {
+	key, err := ConfigKey(key)
+	if err != nil {
+		return err
+	}
+
+	// make sure the value has the right type
+	if err := (&Config{}).Set(key, value); err != nil {
+		return err
+	}
+
+	team := yaml.MapSlice{}
+	data, err := ioutil.ReadFile(config.TeamConfig())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := yaml.Unmarshal(data, &team); err != nil {
+		return fmt.Errorf("%s is invalid: %s", config.TeamConfig(), err.Error())
+	}
+
+	appConfig := map[string]string{}
+	for i, item := range team {
+		if item.Key != "config" {
+			continue
+		}
+		if values, ok := item.Value.(yaml.MapSlice); ok {
+			for _, value := range values {
+				appConfig[fmt.Sprint(value.Key)] = fmt.Sprint(value.Value)
+			}
+		}
+		team = append(team[:i], team[i+1:]...)
+		break
+	}
+	appConfig[key] = value
+	team = append(team, yaml.MapItem{Key: "config", Value: typedConfig(appConfig)})
+
+	out, err := yaml.Marshal(team)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(config.TeamConfig()), 0755); err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(config.TeamConfig(), out, 0644); err != nil {
+		return err
+	}
+
+	// the user set the endpoint, so this machine trusts it
+	if isEndpointKey(key) {
+		return trustEndpoint(key, value)
+	}
+
+	return nil
+}
+
+// trustEndpoint records an endpoint of the app's team config as trusted on
+// this machine
+func trustEndpoint(key, value string) error {
+	env, err := FindEnvByID(config.EnvID())
+	if err != nil {
+		// there is nothing to trust it for until the app is set up, the
+		// value is asked about when the team config is applied
+		return nil
+	}
+
+	if env.TrustedEndpoints == nil {
+		env.TrustedEndpoints = map[string]string{}
+	}
+	env.TrustedEndpoints[key] = value
+
+	return env.Save()
+}
+
+// ConfigKey returns the name of a key, the older names with underscores or
+// different spellings are accepted
+func ConfigKey(key string) (string, error) {
+	name := strings.Replace(strings.ToLower(key), "_", "-", -1)
+	if alias, ok := configAliases[name]; ok {
+		name = alias
+	}
+
+	if _, ok := configValues(&Config{})[name]; !ok {
+		return "", fmt.Errorf("'%s' is not a valid key", key)
+	}
+
+	return name, nil
+}
+
+// GlobalConfig is the path of the global configuration
+func GlobalConfig() string {
+	return filepath.ToSlash(filepath.Join(config.GlobalDir(), "config.yml"))
+}
+
+// configValues returns the values of a config as strings, by key
+func configValues(c *Config) map[string]string {
+	values := map[string]string{}
+	for key, value := range configRaw(c) {
+		values[key] = fmt.Sprint(value)
+	}
+
+	return values
+}
+
+// configRaw returns the values of a config as they are encoded, the numbers
+// are kept as they are written
+func configRaw(c *Config) map[string]interface{} {
+	data, _ := json.Marshal(c)
+	raw := map[string]interface{}{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	decoder.Decode(&raw)
+
+	return raw
+}
+
+// setValues sets the config from string values, converting each to the type
+// of its key
+func (c *Config) setValues(values map[string]string) error {
+	kinds := configRaw(c)
+
+	// a value that doesn't convert is left out, the others are still set
+	var err error
+	typed := map[string]interface{}{}
+	for key, value := range values {
+		switch kinds[key].(type) {
+		case bool:
+			typed[key] = value == "true" || value == "t" || value == "1"
+		case json.Number:
+			number, convErr := strconv.Atoi(value)
+			if convErr != nil {
+				err = fmt.Errorf("'%s' must be a number, not '%s'", key, value)
+				continue
+			}
+			typed[key] = number
+		default:
+			typed[key] = value
+		}
+	}
+
+	data, _ := json.Marshal(typed)
+	if jsonErr := json.Unmarshal(data, c); jsonErr != nil {
+		return jsonErr
+	}
+
+	return err
+}
+
+// loadGlobalConfig reads the global configuration
+func loadGlobalConfig() (map[string]string, error) {
+	values := map[string]string{}
+
+	data, err := ioutil.ReadFile(GlobalConfig())
+	if err == nil {
+		raw := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return values, fmt.Errorf("failed to load Config: %s is invalid: %s", GlobalConfig(), err.Error())
+		}
+		for key, value := range raw {
+			values[key] = fmt.Sprint(value)
+		}
+		return values, nil
+	}
+
+	// older versions kept it in the database
+	c := &Config{}
 	if err := get("registry", "Config", &c); err != nil {
-		return c, fmt.Errorf("failed to load Config: %s", err.Error())
+		return values, fmt.Errorf("failed to load Config: %s", err.Error())
+	}
+
+	return configValues(c), nil
+}
+
+// writeGlobalConfig writes the global configuration
+func writeGlobalConfig(values map[string]string) error {
+	out, err := yaml.Marshal(typedConfig(values))
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(config.GlobalDir(), 0755); err != nil {
+		return err
+	}
+
+	// write it next to the config and move it in place, so a command that
+	// reads it never sees half of it
+	tmp := GlobalConfig() + ".tmp"
+	if err := ioutil.WriteFile(tmp, out, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, GlobalConfig())
+}
+
+// typedConfig converts the values to the types of their keys
+func typedConfig(values map[string]string) map[string]interface{} {
+	kinds := configRaw(&Config{})
+
+	typed := map[string]interface{}{}
+	for key, value := range values {
+		switch kinds[key].(type) {
+		case bool:
+			typed[key] = value == "true" || value == "t" || value == "1"
+		case json.Number:
+			typed[key], _ = strconv.Atoi(value)
+		default:
+			typed[key] = value
+		}
+	}
+
+	return typed
+}
+
+// loadAppConfig reads the config section of the app's .nanobox/config.yml
+func loadAppConfig() map[string]string {
+	values := map[string]string{}
+
+	data, err := ioutil.ReadFile(config.TeamConfig())
+	if err != nil {
+		return values
+	}
+
+	team := struct {
+		Config map[string]interface{} `yaml:"config"`
+	}{}
+	if err := yaml.Unmarshal(data, &team); err != nil {
+		lumber.Error("models:loadAppConfig:yaml.Unmarshal(%s): %s", config.TeamConfig(), err.Error())
+		return values
 	}
 
-	return c, nil
+	var trusted map[string]string
+	for key, value := range team.Config {
+		if isEndpointKey(key) {
+			if trusted == nil {
+				trusted = trustedEndpoints()
+			}
+			if trusted[key] != fmt.Sprint(value) {
+				lumber.Info("models:loadAppConfig: %s from %s isn't trusted, it's ignored", key, config.TeamConfig())
+				continue
+			}
+		}
+		values[key] = fmt.Sprint(value)
+	}
+
+	return values
+}
+
+// isEndpointKey reports whether the key is one of the EndpointKeys
+func isEndpointKey(key string) bool {
+	for _, endpoint := range EndpointKeys {
+		if key == endpoint {
+			return true
+		}
+	}
+
+	return false
+}
+
+// trustedEndpoints returns the endpoints of the app's team config that are
+// trusted on this machine
+func trustedEndpoints() map[string]string {
+	env, err := FindEnvByID(config.EnvID())
+	if err != nil || env.TrustedEndpoints == nil {
+		return map[string]string{}
+	}
+
+	return env.TrustedEndpoints
+}
+
+// envConfig reads the keys set in the environment, ie: NANOBOX_CPUS=2
+func envConfig() map[string]string {
+	values := map[string]string{}
+
+	for key := range configValues(&Config{}) {
+		name := "NANOBOX_" + strings.ToUpper(strings.Replace(key, "-", "_", -1))
+		if value := os.Getenv(name); value != "" {
+			values[key] = value
+		}
+	}
+
+	return values
 }
 
 // HasRead returns true if the value is set. Used for prompting high sierra
@@ -137,3 +714,12 @@ func DoneRead() error {
 
 	return nil
 }
+
+// PasswordPolicy returns the policy used to generate component passwords
+func (c *Config) PasswordPolicy() creds.Policy {
+	return creds.Policy{
+		Length:  c.PasswordLength,
+		Charset: c.PasswordCharset,
+		Seed:    c.PasswordSeed,
+	}
+}
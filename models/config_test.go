@@ -0,0 +1,70 @@
+package models
+
+import (
+	"os"
+	"testing"
+)
+
+func TestConfigLayers(t *testing.T) {
+	os.Setenv("NANOBOX_CPUS", "3")
+	defer os.Unsetenv("NANOBOX_CPUS")
+
+	conf, _ := LoadConfig()
+	if conf.CPUs != 3 || conf.Source("cpus") != ConfigEnv {
+		t.Errorf("the environment didn't override cpus: %d from %s", conf.CPUs, conf.Source("cpus"))
+	}
+
+	if err := SetConfigFlag("cpus=4"); err != nil {
+		t.Errorf("failed to set a config flag: %s", err)
+	}
+	defer delete(configFlags, "cpus")
+
+	conf, _ = LoadConfig()
+	if conf.CPUs != 4 || conf.Source("cpus") != ConfigFlag {
+		t.Errorf("the flag didn't override cpus: %d from %s", conf.CPUs, conf.Source("cpus"))
+	}
+
+	if err := SetConfigFlag("cpus=lots"); err == nil {
+		t.Errorf("cpus was set to something other than a number")
+	}
+
+	if err := SetConfigFlag("bogus=1"); err == nil {
+		t.Errorf("a key that doesn't exist was set")
+	}
+}
+
+func TestConfigKey(t *testing.T) {
+	keys := map[string]string{
+		"CPUs":               "cpus",
+		"mount_type":         "mount-type",
+		"mount-options":      "netfs-mount-opts",
+		"use_encrypted_keys": "ssh-encrypted-keys",
+	}
+
+	for key, name := range keys {
+		if found, err := ConfigKey(key); err != nil || found != name {
+			t.Errorf("expected '%s' for '%s', got '%s' (%v)", name, key, found, err)
+		}
+	}
+}
+
+func TestConfigMountType(t *testing.T) {
+	conf := &Config{}
+
+	for _, value := range []string{"rsync", "virtiofs"} {
+		if err := conf.Set("mount-type", value); err == nil {
+			t.Errorf("mount-type was set to %s, which isn't a mount type", value)
+		}
+	}
+
+	for value, expected := range map[string]string{"nfs": MountNFS, "netfs": MountNFS, "native": MountVboxsf, "sync": MountSync} {
+		if err := conf.Set("mount-type", value); err != nil {
+			t.Errorf("failed to set mount-type to %s: %s", value, err)
+		}
+
+		conf.makeValid()
+		if conf.MountType != expected {
+			t.Errorf("expected mount-type %s to be %s, got %s", value, expected, conf.MountType)
+		}
+	}
+}
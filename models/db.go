@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/nanobox-io/nanobox/util/config"
@@ -13,21 +14,89 @@ import (
 var (
 	// DB is the path to the local nanobox database
 	DB = filepath.ToSlash(filepath.Join(config.GlobalDir(), "data.db"))
+
+	// how long to wait for another command to release the database
+	dbTimeout = 30 * time.Second
 )
 
 // db opens a boltDB connection
 func db() (*bolt.DB, error) {
 
-	boltDB, err := bolt.Open(DB, 0666, nil)
+	// bolt locks the file while it's open, a command that holds it longer
+	// than this is stuck rather than busy
+	boltDB, err := bolt.Open(DB, 0666, &bolt.Options{Timeout: dbTimeout})
+	if err == bolt.ErrTimeout {
+		return nil, fmt.Errorf("another nanobox command is using the database (%s), try again when it finishes", DB)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to open database file (%s): %s", DB, err.Error())
 	}
 
+	// bring the records up to the schema of this version of nanobox once
+	migrateOnce.Do(func() {
+		migrateErr = migrate(boltDB)
+	})
+	if migrateErr != nil {
+		boltDB.Close()
+		return nil, migrateErr
+	}
+
 	return boltDB, nil
 }
 
+// record is an element to write to the bolt database
+type record struct {
+	bucket string
+	id     string
+	value  interface{}
+}
+
 // put inserts or updates an element into the bolt database
 func put(bucket, id string, v interface{}) error {
+	return putAll(record{bucket, id, v})
+}
+
+// putAll inserts or updates elements into the bolt database in a single
+// transaction, either all of them are written or none is
+func putAll(records ...record) error {
+
+	// open the database
+	db, err := db()
+	if err != nil {
+		return fmt.Errorf("unable to initialize database driver: %s ", err.Error())
+	}
+
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		for _, r := range records {
+
+			// Create a bucket.
+			bucket, err := tx.CreateBucketIfNotExists([]byte(r.bucket))
+			if err != nil {
+				return fmt.Errorf("unable to create a database bucket: %s", err.Error())
+			}
+
+			// Marshal the value into a JSON blob
+			bytes, err := json.Marshal(r.value)
+			if err != nil {
+				return fmt.Errorf("failed to encode database record: %s", err.Error())
+			}
+
+			// Write the entry
+			if err := bucket.Put([]byte(r.id), bytes); err != nil {
+				return fmt.Errorf("failed to write entry: %s", err.Error())
+			}
+		}
+
+		return nil
+	})
+}
+
+// swap reads the element at id into old and replaces it with v in a single
+// transaction. check is told whether old was found, an error from it leaves
+// the element as it was and is returned as is.
+func swap(bucket, id string, old, v interface{}, check func(found bool) error) error {
 
 	// open the database
 	db, err := db()
@@ -45,6 +114,19 @@ func put(bucket, id string, v interface{}) error {
 			return fmt.Errorf("unable to create a database bucket: %s", err.Error())
 		}
 
+		// Fetch the current value
+		value := bucket.Get([]byte(id))
+		found := len(value) > 0
+		if found {
+			if err := json.Unmarshal(value, old); err != nil {
+				return fmt.Errorf("failed to decode database record: %s", err.Error())
+			}
+		}
+
+		if err := check(found); err != nil {
+			return err
+		}
+
 		// Marshal the value into a JSON blob
 		bytes, err := json.Marshal(v)
 		if err != nil {
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// the statuses of a deploy
+const (
+	DeployRunning    = "running"
+	DeployLive       = "live"
+	DeployFailed     = "failed"
+	DeployRolledBack = "rolled-back"
+)
+
+// DeployRecord is a deploy in the deploy history of an app. Local apps are
+// keyed by their app id, live apps by their id on nanobox.
+type DeployRecord struct {
+	AppID    string
+	Number   int               // counts up per app, starting at 1
+	Status   string            // see the Deploy* statuses
+	BuildID  string            // the build in the warehouse
+	Boxfile  string            // the boxfile the build was deployed with
+	Canary   int               // the share of web traffic the canary started with
+	Evars    map[string]string // the evars of a local app at the deploy
+	Message  string
+	Started  time.Time
+	Finished time.Time
+}
+
+// Save persists the DeployRecord to the database
+func (d *DeployRecord) Save() error {
+
+	if err := put("deploy_history", deployRecordKey(d.AppID, d.Number), d); err != nil {
+		return fmt.Errorf("failed to save deploy record: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Delete deletes the deploy record from the database
+func (d *DeployRecord) Delete() error {
+
+	if err := destroy("deploy_history", deployRecordKey(d.AppID, d.Number)); err != nil {
+		return fmt.Errorf("failed to delete deploy record: %s", err.Error())
+	}
+
+	return nil
+}
+
+// AllDeployRecordsByApp loads the deploy history of an app, oldest first
+func AllDeployRecordsByApp(appID string) ([]*DeployRecord, error) {
+	all := []*DeployRecord{}
+
+	if err := getAll("deploy_history", &all); err != nil {
+		return all, fmt.Errorf("failed to load deploy records: %s", err.Error())
+	}
+
+	records := deployRecords{}
+	for _, record := range all {
+		if record.AppID == appID {
+			records = append(records, record)
+		}
+	}
+	sort.Sort(records)
+
+	return records, nil
+}
+
+// LiveDeployRecords returns the deploys of an app that went live, newest
+// first. The first one is what the app is running.
+func LiveDeployRecords(appID string) ([]*DeployRecord, error) {
+	records, err := AllDeployRecordsByApp(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	live := []*DeployRecord{}
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].Status == DeployLive {
+			live = append(live, records[i])
+		}
+	}
+
+	return live, nil
+}
+
+// deployRecordKey returns the database key of a deploy record
+func deployRecordKey(appID string, number int) string {
+	return fmt.Sprintf("%s-%d", appID, number)
+}
+
+// deployRecords sorts deploy records by number
+type deployRecords []*DeployRecord
+
+func (d deployRecords) Len() int           { return len(d) }
+func (d deployRecords) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d deployRecords) Less(i, j int) bool { return d[i].Number < d[j].Number }
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestLiveDeployRecords(t *testing.T) {
+	// clear the deploy history when we're finished
+	defer truncate("deploy_history")
+
+	records := []DeployRecord{
+		{AppID: "123", Number: 1, Status: DeployLive, BuildID: "a"},
+		{AppID: "123", Number: 2, Status: DeployFailed, BuildID: "b"},
+		{AppID: "123", Number: 3, Status: DeployLive, BuildID: "c"},
+		{AppID: "456", Number: 4, Status: DeployLive, BuildID: "d"},
+	}
+
+	for i := range records {
+		if err := records[i].Save(); err != nil {
+			t.Error(err)
+		}
+	}
+
+	live, err := LiveDeployRecords("123")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// newest first, without the failed deploy
+	if len(live) != 2 || live[0].BuildID != "c" || live[1].BuildID != "a" {
+		t.Errorf("did not load the live deploys in order: %+v", live)
+	}
+}
@@ -15,6 +15,18 @@ type Env struct {
 
 	// Remotes map a local app to multiple production apps, by an alias
 	Remotes map[string]Remote
+	// the environment 'dry-run' points at, see 'nanobox env use'
+	DryRun string
+	// the dry-run environments known before they are created, and the
+	// checksum of the team config they were imported from
+	Environments []string
+	TeamConfig   string
+	// the endpoints of the team config the user trusts, the config keys and
+	// the remote.<alias> endpoints, by their value when they were trusted
+	TrustedEndpoints map[string]string
+	// the local ports the tunnels to the components listen on, from the
+	// ports of the team config
+	TunnelPorts map[string]int
 	// the boxfile from the most recent build
 	BuiltBoxfile  string
 	UserBoxfile   string
@@ -23,8 +35,25 @@ type Env struct {
 	LastBuild     time.Time
 	LastCompile   time.Time
 	BuildTriggers map[string]string
+	// the cache keys of the build phases from the most recent build
+	BuildCache map[string]string
+	// the outcome of the most recent build, see the Build* statuses
+	BuildStatus string
+	// the artifact checksum of the most recent reproducible build, and of
+	// each commit that was built reproducibly
+	BuildFingerprint string
+	Fingerprints     map[string]string
 }
 
+// the statuses of a build
+const (
+	BuildRunning  = "running"
+	BuildComplete = "complete"
+	BuildFailed   = "failed"
+	BuildCanceled = "canceled"
+	BuildTimedOut = "timed-out"
+)
+
 // Remote ...
 type Remote struct {
 	ID       string
@@ -39,6 +68,9 @@ func (e *Env) IsNew() bool {
 
 // Save persists the Env to the database
 func (e *Env) Save() error {
+	if err := e.Validate(); err != nil {
+		return fmt.Errorf("invalid env: %s", err.Error())
+	}
 
 	if err := put("envs", e.ID, e); err != nil {
 		return fmt.Errorf("failed to save env: %s", err.Error())
@@ -79,6 +111,15 @@ func (e *Env) Apps() ([]*App, error) {
 	return AllAppsByEnv(e.ID)
 }
 
+// DryRunName returns the name of the app 'dry-run' points at
+func (e *Env) DryRunName() string {
+	if e.DryRun == "" {
+		return "sim"
+	}
+
+	return e.DryRun
+}
+
 // FindEnvByID finds an app by an ID
 func FindEnvByID(ID string) (*Env, error) {
 
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/boltdb/bolt"
+)
+
+// migration moves the records from one schema version to the next
+type migration func(tx *bolt.Tx) error
+
+// migrations upgrade the database one schema version at a time, the schema
+// version of a database is the number of migrations it went through. Append a
+// migration whenever a model change needs the existing records to change,
+// never change or reorder the ones that were released.
+var migrations = []migration{
+	migrateComponentIPs,
+}
+
+var (
+	migrateOnce sync.Once
+	migrateErr  error
+)
+
+// SchemaVersion returns the schema version this version of nanobox uses
+func SchemaVersion() int {
+	return len(migrations)
+}
+
+// migrate brings the database up to the current schema version. Each
+// migration runs in its own transaction, so an interrupted upgrade starts
+// again from the last migration that completed.
+func migrate(boltDB *bolt.DB) error {
+	for {
+		done := false
+
+		err := boltDB.Update(func(tx *bolt.Tx) error {
+			version, err := schemaVersion(tx)
+			if err != nil {
+				return err
+			}
+
+			// an older nanobox would corrupt records it doesn't understand
+			if version > len(migrations) {
+				return fmt.Errorf("the database was upgraded by a newer version of nanobox (schema %d, this version uses %d), update nanobox to use it", version, len(migrations))
+			}
+
+			if version == len(migrations) {
+				done = true
+				return nil
+			}
+
+			if err := migrations[version](tx); err != nil {
+				return fmt.Errorf("failed to migrate the database to schema %d: %s", version+1, err.Error())
+			}
+
+			return setSchemaVersion(tx, version+1)
+		})
+
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// schemaVersion returns the schema version of the database, a database that
+// predates the versioning is at 0
+func schemaVersion(tx *bolt.Tx) (int, error) {
+	version := 0
+
+	bucket := tx.Bucket([]byte("registry"))
+	if bucket == nil {
+		return version, nil
+	}
+
+	value := bucket.Get([]byte("schema"))
+	if len(value) == 0 {
+		return version, nil
+	}
+
+	if err := json.Unmarshal(value, &version); err != nil {
+		return 0, fmt.Errorf("failed to decode the schema version: %s", err.Error())
+	}
+
+	return version, nil
+}
+
+// setSchemaVersion stores the schema version of the database
+func setSchemaVersion(tx *bolt.Tx, version int) error {
+	bucket, err := tx.CreateBucketIfNotExists([]byte("registry"))
+	if err != nil {
+		return fmt.Errorf("unable to create a database bucket: %s", err.Error())
+	}
+
+	bytes, _ := json.Marshal(version)
+	if err := bucket.Put([]byte("schema"), bytes); err != nil {
+		return fmt.Errorf("failed to write the schema version: %s", err.Error())
+	}
+
+	return nil
+}
+
+// bucketKeys returns the keys of a bucket within a transaction
+func bucketKeys(tx *bolt.Tx, name string) []string {
+	keys := []string{}
+
+	bucket := tx.Bucket([]byte(name))
+	if bucket == nil {
+		return keys
+	}
+
+	bucket.ForEach(func(k, v []byte) error {
+		keys = append(keys, string(k))
+		return nil
+	})
+
+	return keys
+}
+
+// rewrite passes each record of a bucket to fn, decoded as is, and writes
+// it back. Decoding into a map keeps the fields the migration doesn't know.
+func rewrite(tx *bolt.Tx, name string, fn func(record map[string]interface{})) error {
+	bucket := tx.Bucket([]byte(name))
+	if bucket == nil {
+		return nil
+	}
+
+	// a bucket can't change while it's iterated
+	for _, key := range bucketKeys(tx, name) {
+		record := map[string]interface{}{}
+		if err := json.Unmarshal(bucket.Get([]byte(key)), &record); err != nil {
+			return fmt.Errorf("failed to decode database record %s/%s: %s", name, key, err.Error())
+		}
+
+		fn(record)
+
+		bytes, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode database record %s/%s: %s", name, key, err.Error())
+		}
+
+		if err := bucket.Put([]byte(key), bytes); err != nil {
+			return fmt.Errorf("failed to write database record %s/%s: %s", name, key, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// migrateComponentIPs moves the components created before the single ip
+// system from internal_ip to ip. The components of an app are stored in a
+// bucket named after the app, the apps in a bucket named after their env.
+func migrateComponentIPs(tx *bolt.Tx) error {
+	for _, envID := range bucketKeys(tx, "envs") {
+		for _, appID := range bucketKeys(tx, envID) {
+			err := rewrite(tx, appID, func(record map[string]interface{}) {
+				internalIP, _ := record["internal_ip"].(string)
+				if ip, _ := record["ip"].(string); ip == "" && internalIP != "" {
+					record["ip"] = internalIP
+					record["internal_ip"] = ""
+				}
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/boltdb/bolt"
+)
+
+func TestMigrate(t *testing.T) {
+	// clear the tables when we're finished
+	defer truncate("envs")
+	defer truncate("migrate")
+	defer truncate("migrate_dev")
+
+	put("envs", "migrate", Env{ID: "migrate"})
+	put("migrate", "migrate_dev", App{EnvID: "migrate", ID: "migrate_dev"})
+	put("migrate_dev", "data.db", map[string]interface{}{"name": "data.db", "internal_ip": "1.2.3.4", "ip": ""})
+
+	boltDB, err := db()
+	if err != nil {
+		t.Fatalf("failed to open the database: %s", err.Error())
+	}
+
+	// pretend the database predates the versioning
+	boltDB.Update(func(tx *bolt.Tx) error {
+		return setSchemaVersion(tx, 0)
+	})
+
+	err = migrate(boltDB)
+	boltDB.Close()
+	if err != nil {
+		t.Fatalf("failed to migrate: %s", err.Error())
+	}
+
+	component, _ := FindComponentBySlug("migrate_dev", "data.db")
+	if component.IP != "1.2.3.4" || component.InternalIP != "" {
+		t.Errorf("did not move the internal ip, got %+v", component)
+	}
+}
+
+func TestMigrateNewerSchema(t *testing.T) {
+	boltDB, err := db()
+	if err != nil {
+		t.Fatalf("failed to open the database: %s", err.Error())
+	}
+	defer boltDB.Close()
+
+	boltDB.Update(func(tx *bolt.Tx) error {
+		return setSchemaVersion(tx, SchemaVersion()+1)
+	})
+	defer boltDB.Update(func(tx *bolt.Tx) error {
+		return setSchemaVersion(tx, SchemaVersion())
+	})
+
+	if err := migrate(boltDB); err == nil {
+		t.Errorf("expected a newer schema to fail")
+	}
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+)
+
+// RegistryAuth holds the credentials used to pull images from a private
+// docker registry
+type RegistryAuth struct {
+	Host     string // registry host, ie: quay.io, localhost:5000
+	Username string
+	Password string // encrypted, see util/secret
+}
+
+// IsNew returns true if the RegistryAuth hasn't been created yet
+func (r *RegistryAuth) IsNew() bool {
+	return r.Username == ""
+}
+
+// Save persists the RegistryAuth to the database
+func (r *RegistryAuth) Save() error {
+
+	if err := put("registries", r.Host, r); err != nil {
+		return fmt.Errorf("failed to save registry auth: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Delete deletes the registry auth record from the database
+func (r *RegistryAuth) Delete() error {
+
+	if err := destroy("registries", r.Host); err != nil {
+		return fmt.Errorf("failed to delete registry auth: %s", err.Error())
+	}
+
+	return nil
+}
+
+// FindRegistryAuthByHost finds the credentials for a registry host
+func FindRegistryAuthByHost(host string) (*RegistryAuth, error) {
+	auth := &RegistryAuth{}
+
+	if err := get("registries", host, &auth); err != nil {
+		return auth, fmt.Errorf("failed to load registry auth: %s", err.Error())
+	}
+
+	return auth, nil
+}
+
+// AllRegistryAuths loads the credentials of every registry
+func AllRegistryAuths() ([]*RegistryAuth, error) {
+	auths := []*RegistryAuth{}
+
+	if err := getAll("registries", &auths); err != nil {
+		return auths, fmt.Errorf("failed to load registry auths: %s", err.Error())
+	}
+
+	return auths, nil
+}
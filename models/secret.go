@@ -0,0 +1,57 @@
+package models
+
+import (
+	"fmt"
+)
+
+// Secrets are the secrets of an app. They are kept apart from the evars so
+// they never end up in the app record, the values are encrypted.
+type Secrets struct {
+	AppID  string
+	Values map[string]string // encrypted values by key
+}
+
+// Save persists the Secrets to the database
+func (s *Secrets) Save() error {
+
+	if err := put("secrets", s.AppID, s); err != nil {
+		return fmt.Errorf("failed to save secrets: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Delete deletes the secrets from the database
+func (s *Secrets) Delete() error {
+
+	if err := destroy("secrets", s.AppID); err != nil {
+		return fmt.Errorf("failed to delete secrets: %s", err.Error())
+	}
+
+	return nil
+}
+
+// FindSecretsByApp loads the secrets of an app, an app without secrets has
+// an empty set
+func FindSecretsByApp(appID string) (*Secrets, error) {
+	secrets := &Secrets{
+		AppID:  appID,
+		Values: map[string]string{},
+	}
+
+	if err := get("secrets", appID, &secrets); err != nil {
+
+		// don't return an error if the record doesn't exist
+		if err.Error() == "no record found" {
+			return secrets, nil
+		}
+
+		return secrets, fmt.Errorf("failed to load secrets: %s", err.Error())
+	}
+
+	if secrets.Values == nil {
+		secrets.Values = map[string]string{}
+	}
+
+	return secrets, nil
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestSecretsSave(t *testing.T) {
+	// clear the secrets table when we're finished
+	defer truncate("secrets")
+
+	secrets := Secrets{AppID: "1", Values: map[string]string{"STRIPE_KEY": "encrypted"}}
+	if err := secrets.Save(); err != nil {
+		t.Error(err)
+	}
+
+	loaded, err := FindSecretsByApp("1")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if loaded.Values["STRIPE_KEY"] != "encrypted" {
+		t.Errorf("secrets don't match")
+	}
+}
+
+func TestFindSecretsByAppMissing(t *testing.T) {
+	secrets, err := FindSecretsByApp("missing")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if secrets.Values == nil || len(secrets.Values) != 0 {
+		t.Errorf("expected no secrets, got %v", secrets.Values)
+	}
+}
+
+func TestSecretsDelete(t *testing.T) {
+	// clear the secrets table when we're finished
+	defer truncate("secrets")
+
+	secrets := Secrets{AppID: "1", Values: map[string]string{"STRIPE_KEY": "encrypted"}}
+	secrets.Save()
+
+	if err := secrets.Delete(); err != nil {
+		t.Error(err)
+	}
+
+	loaded, _ := FindSecretsByApp("1")
+	if len(loaded.Values) != 0 {
+		t.Errorf("secrets weren't deleted")
+	}
+}
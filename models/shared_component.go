@@ -0,0 +1,101 @@
+package models
+
+import (
+	"fmt"
+)
+
+// SharedComponent is a data component of a workspace, one container that
+// serves every app declaring it, ie: data.db with 'shared: acme'. It counts
+// the apps that use it so it is only destroyed with the last of them.
+type SharedComponent struct {
+	Workspace string
+	Name      string
+	// the container, ip and plan every app's component points at
+	Component Component
+	// the ids of the apps that use it
+	Apps []string
+}
+
+// IsNew returns true if the SharedComponent hasn't been created yet
+func (s *SharedComponent) IsNew() bool {
+	return s.Component.ID == ""
+}
+
+// Save persists the SharedComponent to the database
+func (s *SharedComponent) Save() error {
+
+	if err := put("shared_components", sharedKey(s.Workspace, s.Name), s); err != nil {
+		return fmt.Errorf("failed to save shared component: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Delete deletes the shared component record from the database
+func (s *SharedComponent) Delete() error {
+
+	if err := destroy("shared_components", sharedKey(s.Workspace, s.Name)); err != nil {
+		return fmt.Errorf("failed to delete shared component: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Use adds an app to the apps that use the component
+func (s *SharedComponent) Use(appID string) {
+	if !s.UsedBy(appID) {
+		s.Apps = append(s.Apps, appID)
+	}
+}
+
+// Release removes an app from the apps that use the component and returns
+// how many still do
+func (s *SharedComponent) Release(appID string) int {
+	apps := []string{}
+	for _, id := range s.Apps {
+		if id != appID {
+			apps = append(apps, id)
+		}
+	}
+	s.Apps = apps
+
+	return len(s.Apps)
+}
+
+// UsedBy returns true if an app uses the component
+func (s *SharedComponent) UsedBy(appID string) bool {
+	for _, id := range s.Apps {
+		if id == appID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindSharedComponent finds the component of a workspace by name
+func FindSharedComponent(workspace, name string) (*SharedComponent, error) {
+	shared := &SharedComponent{Workspace: workspace, Name: name}
+
+	if err := get("shared_components", sharedKey(workspace, name), &shared); err != nil {
+		return shared, fmt.Errorf("failed to load shared component: %s", err.Error())
+	}
+
+	return shared, nil
+}
+
+// AllSharedComponents loads the shared components of every workspace
+func AllSharedComponents() ([]*SharedComponent, error) {
+	shared := []*SharedComponent{}
+
+	if err := getAll("shared_components", &shared); err != nil {
+		return shared, fmt.Errorf("failed to load shared components: %s", err.Error())
+	}
+
+	return shared, nil
+}
+
+// sharedKey is the key of a shared component, ie: acme/data.db
+func sharedKey(workspace, name string) string {
+	return fmt.Sprintf("%s/%s", workspace, name)
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestSharedComponentSave(t *testing.T) {
+	// clear the shared components table when we're finished
+	defer truncate("shared_components")
+
+	shared := SharedComponent{
+		Workspace: "acme",
+		Name:      "data.db",
+		Component: Component{ID: "123", Name: "data.db"},
+	}
+	shared.Use("app1")
+
+	if err := shared.Save(); err != nil {
+		t.Error(err)
+	}
+
+	shared2, err := FindSharedComponent("acme", "data.db")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if shared2.Component.ID != "123" || !shared2.UsedBy("app1") {
+		t.Errorf("shared component doesn't match")
+	}
+}
+
+func TestSharedComponentRelease(t *testing.T) {
+	shared := SharedComponent{Workspace: "acme", Name: "data.db"}
+
+	shared.Use("app1")
+	shared.Use("app2")
+	shared.Use("app1")
+
+	if len(shared.Apps) != 2 {
+		t.Errorf("expected 2 apps, got %v", shared.Apps)
+	}
+
+	if remaining := shared.Release("app1"); remaining != 1 {
+		t.Errorf("expected 1 app left, got %d", remaining)
+	}
+
+	if remaining := shared.Release("app2"); remaining != 0 {
+		t.Errorf("expected no app left, got %d", remaining)
+	}
+}
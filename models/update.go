@@ -9,6 +9,10 @@ import (
 type Update struct {
 	LastCheckAt   time.Time
 	LastUpdatedAt time.Time
+
+	// the newest release found on the channel by the last check
+	Latest  string
+	Channel string
 }
 
 // LoadUpdate loads the update entry
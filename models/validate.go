@@ -0,0 +1,106 @@
+package models
+
+import (
+	"fmt"
+	"net"
+)
+
+// the states of an app or a component. A record is initialized when it's
+// generated and active once it's set up.
+const (
+	StateInitialized = "initialized"
+	StateActive      = "active"
+)
+
+// the statuses of an app. A suspended app's containers are paused, they
+// keep their IPs and memory until it's resumed.
+const (
+	StatusUp        = "up"
+	StatusDown      = "down"
+	StatusSuspended = "suspended"
+)
+
+// the types of a component, a stable component is a web component set aside
+// during a canary deploy
+const (
+	ComponentCode    = "code"
+	ComponentData    = "data"
+	ComponentSidecar = "sidecar"
+	ComponentStable  = "stable"
+)
+
+// Validate returns an error if the Env can't be saved
+func (e *Env) Validate() error {
+	if e.ID == "" {
+		return fmt.Errorf("the env has no id")
+	}
+
+	return nil
+}
+
+// Validate returns an error if the App can't be saved
+func (a *App) Validate() error {
+	if a.EnvID == "" || a.ID == "" {
+		return fmt.Errorf("the app has no id")
+	}
+
+	if !oneOf(a.State, "", StateInitialized, StateActive) {
+		return fmt.Errorf("'%s' isn't an app state", a.State)
+	}
+
+	if !oneOf(a.Status, "", StatusUp, StatusDown, StatusSuspended) {
+		return fmt.Errorf("'%s' isn't an app status", a.Status)
+	}
+
+	for name, ip := range a.LocalIPs {
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("the %s ip '%s' isn't an ip", name, ip)
+		}
+	}
+
+	// the ips are reserved before the app is activated
+	if a.State == StateActive && a.LocalIPs["env"] == "" {
+		return fmt.Errorf("the app is active without an ip")
+	}
+
+	return nil
+}
+
+// Validate returns an error if the Component can't be saved
+func (c *Component) Validate() error {
+	if c.AppID == "" || c.Name == "" {
+		return fmt.Errorf("the component has no app or name")
+	}
+
+	if !oneOf(c.State, "", StateInitialized, StateActive) {
+		return fmt.Errorf("'%s' isn't a component state", c.State)
+	}
+
+	if !oneOf(c.Type, "", ComponentCode, ComponentData, ComponentSidecar, ComponentStable) {
+		return fmt.Errorf("'%s' isn't a component type", c.Type)
+	}
+
+	for _, ip := range []string{c.IP, c.InternalIP} {
+		if ip != "" && net.ParseIP(ip) == nil {
+			return fmt.Errorf("'%s' isn't an ip", ip)
+		}
+	}
+
+	// the ip is reserved before the component is activated
+	if c.State == StateActive && c.IPAddr() == "" {
+		return fmt.Errorf("%s is active without an ip", c.Name)
+	}
+
+	return nil
+}
+
+// oneOf returns true if val is one of the options
+func oneOf(val string, options ...string) bool {
+	for _, option := range options {
+		if val == option {
+			return true
+		}
+	}
+
+	return false
+}
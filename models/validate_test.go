@@ -0,0 +1,58 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestComponentValidate(t *testing.T) {
+	valid := []Component{
+		{AppID: "1_dev", Name: "web.main"},
+		{AppID: "1_dev", Name: "data.db", Type: ComponentData, State: StateInitialized},
+		{AppID: "1_dev", Name: "data.db", Type: ComponentData, State: StateActive, IP: "192.168.0.5"},
+		{AppID: "1_dev", Name: "data.db", State: StateActive, InternalIP: "192.168.0.5"},
+	}
+
+	for _, component := range valid {
+		if err := component.Validate(); err != nil {
+			t.Errorf("expected %+v to be valid: %s", component, err.Error())
+		}
+	}
+
+	invalid := []Component{
+		{Name: "web.main"},
+		{AppID: "1_dev", Name: "web.main", State: "running"},
+		{AppID: "1_dev", Name: "web.main", Type: "worker"},
+		{AppID: "1_dev", Name: "web.main", IP: "192.168.0"},
+		{AppID: "1_dev", Name: "web.main", State: StateActive},
+	}
+
+	for _, component := range invalid {
+		if err := component.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", component)
+		}
+	}
+}
+
+func TestAppValidate(t *testing.T) {
+	app := App{EnvID: "1", ID: "1_dev", Name: "dev", State: StateActive, Status: StatusUp, LocalIPs: map[string]string{"env": "192.168.0.2"}}
+	if err := app.Validate(); err != nil {
+		t.Errorf("expected the app to be valid: %s", err.Error())
+	}
+
+	app.Status = "running"
+	if err := app.Validate(); err == nil {
+		t.Errorf("expected an unknown status to be invalid")
+	}
+
+	app.Status = StatusUp
+	app.LocalIPs = map[string]string{}
+	if err := app.Validate(); err == nil {
+		t.Errorf("expected an active app without an ip to be invalid")
+	}
+
+	// nothing invalid is written
+	defer truncate("1")
+	if err := app.Save(); err == nil {
+		t.Errorf("expected an invalid app not to be saved")
+	}
+}
@@ -12,3 +12,12 @@ var (
 func VersionString() string {
 	return fmt.Sprintf("Nanobox Version %s-%s (%s)", nanoVersion, nanoBuild, nanoCommit)
 }
+
+// VersionInfo returns the parts of the version, for scripts
+func VersionInfo() map[string]string {
+	return map[string]string{
+		"version": nanoVersion,
+		"build":   nanoBuild,
+		"commit":  nanoCommit,
+	}
+}
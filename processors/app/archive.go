@@ -0,0 +1,244 @@
+package app
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/component"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/locker"
+)
+
+// Archive stops the app, packs its state and component data into a single
+// file on the host and removes the live resources
+func Archive(appModel *models.App) error {
+	// init docker client
+	if err := provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	locker.LocalLock()
+	defer locker.LocalUnlock()
+
+	if appModel.IsNew() {
+		return util.Errorf("[USER] the app has not been created yet")
+	}
+
+	// load the env for the display context
+	envModel, err := appModel.Env()
+	if err != nil {
+		lumber.Error("app:Archive:models.App.Env()")
+		return util.ErrorAppend(err, "failed to load app env")
+	}
+
+	// stop the app so the data is consistent when we read it
+	if err := Stop(appModel); err != nil {
+		return util.ErrorAppend(err, "failed to stop the app")
+	}
+
+	display.OpenContext("Archiving %s (%s)", envModel.Name, appModel.DisplayName())
+
+	archiveModel := &models.Archive{
+		ID:        appModel.ID,
+		EnvID:     appModel.EnvID,
+		AppName:   appModel.Name,
+		Path:      ArchivePath(appModel),
+		CreatedAt: time.Now(),
+	}
+
+	if err := WriteArchive(appModel, archiveModel.Path, true); err != nil {
+		display.CloseContext()
+		os.Remove(archiveModel.Path)
+		return util.ErrorAppend(err, "failed to write the archive")
+	}
+
+	if fi, err := os.Stat(archiveModel.Path); err == nil {
+		archiveModel.Size = fi.Size()
+	}
+
+	display.CloseContext()
+
+	// record the archive before anything is removed, it's the only way back
+	if err := archiveModel.Save(); err != nil {
+		lumber.Error("app:Archive:models.Archive.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to persist the archive")
+	}
+
+	// remove the live resources
+	if err := Destroy(appModel); err != nil {
+		return util.ErrorAppend(err, "failed to remove the app")
+	}
+
+	return nil
+}
+
+// ArchivePath returns the location of the archive for an app
+func ArchivePath(appModel *models.App) string {
+	dir := filepath.ToSlash(filepath.Join(config.GlobalDir(), "archives"))
+	os.MkdirAll(dir, 0755)
+
+	return filepath.ToSlash(filepath.Join(dir, fmt.Sprintf("%s.tar.gz", appModel.ID)))
+}
+
+// WriteArchive writes the app model, the component models and, with data,
+// the data of every data component into a gzipped tarball
+func WriteArchive(appModel *models.App, path string, data bool) error {
+	display.StartTask("Packing app state")
+
+	file, err := os.Create(path)
+	if err != nil {
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to create archive file")
+	}
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+
+	if err := writeArchiveEntries(tw, appModel, data); err != nil {
+		tw.Close()
+		gz.Close()
+		file.Close()
+		return err
+	}
+
+	// the writers flush on close, failing to leaves a truncated archive
+	if err := tw.Close(); err != nil {
+		gz.Close()
+		file.Close()
+		return util.ErrorAppend(err, "failed to finish the archive")
+	}
+
+	if err := gz.Close(); err != nil {
+		file.Close()
+		return util.ErrorAppend(err, "failed to compress the archive")
+	}
+
+	if err := file.Close(); err != nil {
+		return util.ErrorAppend(err, "failed to write the archive file")
+	}
+
+	return nil
+}
+
+// writeArchiveEntries writes the models and, with data, the component data
+// into the archive
+func writeArchiveEntries(tw *tar.Writer, appModel *models.App, data bool) error {
+	componentModels, err := appModel.Components()
+	if err != nil {
+		display.ErrorTask()
+		lumber.Error("app:writeArchiveEntries:models.App{ID:%s}.Components(): %s", appModel.ID, err.Error())
+		return util.ErrorAppend(err, "unable to retrieve components")
+	}
+
+	// only data components are archived; code and platform components are
+	// recreated on the next deploy
+	dataModels := []*models.Component{}
+	for _, componentModel := range componentModels {
+		if componentModel.Type == "data" && componentModel.ID != "" {
+			dataModels = append(dataModels, componentModel)
+		}
+	}
+
+	if err := writeArchiveJSON(tw, "app.json", appModel); err != nil {
+		display.ErrorTask()
+		return err
+	}
+
+	if err := writeArchiveJSON(tw, "components.json", dataModels); err != nil {
+		display.ErrorTask()
+		return err
+	}
+
+	display.StopTask()
+
+	if !data {
+		return nil
+	}
+
+	for _, componentModel := range dataModels {
+		if err := writeArchiveData(tw, componentModel); err != nil {
+			return util.ErrorAppend(err, "failed to archive %s", componentModel.Name)
+		}
+	}
+
+	return nil
+}
+
+// writeArchiveJSON adds a json encoded entry to the archive
+func writeArchiveJSON(tw *tar.Writer, name string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return util.ErrorAppend(err, "failed to encode %s", name)
+	}
+
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(b)),
+		ModTime: time.Now(),
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return util.ErrorAppend(err, "failed to write archive header")
+	}
+
+	if _, err := tw.Write(b); err != nil {
+		return util.ErrorAppend(err, "failed to write %s", name)
+	}
+
+	return nil
+}
+
+// writeArchiveData adds the data of a component to the archive. The data is
+// buffered to a temp file first because tar needs the size upfront.
+func writeArchiveData(tw *tar.Writer, componentModel *models.Component) error {
+	tmp, err := ioutil.TempFile("", "nanobox-archive")
+	if err != nil {
+		return util.ErrorAppend(err, "failed to create temp file")
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := component.ExportData(componentModel, tmp); err != nil {
+		return err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to size component data")
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return util.ErrorAppend(err, "failed to rewind component data")
+	}
+
+	header := &tar.Header{
+		Name:    fmt.Sprintf("data/%s.tar", componentModel.Name),
+		Mode:    0644,
+		Size:    size,
+		ModTime: time.Now(),
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return util.ErrorAppend(err, "failed to write archive header")
+	}
+
+	if _, err := io.Copy(tw, tmp); err != nil {
+		return util.ErrorAppend(err, "failed to write component data")
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+)
+
+// FindComponent finds a component of the app by its full name, ie: data.db,
+// or the name after its prefix, ie: db
+func FindComponent(appModel *models.App, name string) (*models.Component, error) {
+	componentModels, err := appModel.Components()
+	if err != nil {
+		lumber.Error("app:FindComponent:models.App{ID:%s}.Components(): %s", appModel.ID, err.Error())
+		return nil, util.ErrorAppend(err, "unable to retrieve components")
+	}
+
+	names := []string{}
+	found := []*models.Component{}
+	for _, componentModel := range componentModels {
+		names = append(names, componentModel.Name)
+
+		if componentModel.Name == name {
+			return componentModel, nil
+		}
+		if componentMatches(componentModel.Name, name) {
+			found = append(found, componentModel)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, util.Errorf("[USER] the app has no component named %s, use one of: %s", name, strings.Join(names, ", "))
+	case 1:
+		return found[0], nil
+	}
+
+	return nil, util.Errorf("[USER] more than one component is named %s, use its full name", name)
+}
+
+// componentMatches returns true if a component goes by the name, ie: data.db
+// or db
+func componentMatches(name, wanted string) bool {
+	if name == wanted {
+		return true
+	}
+
+	parts := strings.SplitN(name, ".", 2)
+	return len(parts) == 2 && parts[1] == wanted
+}
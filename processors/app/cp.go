@@ -0,0 +1,115 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/transfer"
+)
+
+// CopyTo copies the file or directory at src on the host to dest inside the
+// component. Like cp, if dest is a directory src is copied into it,
+// otherwise src is copied as dest.
+func CopyTo(componentModel *models.Component, src, dest string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return util.Errorf("[USER] %s doesn't exist", src)
+	}
+
+	// find out where the archive is extracted and what its root is named
+	dir, name := path.Dir(dest), path.Base(dest)
+	stat, err := docker.Client.ContainerStatPath(context.Background(), componentModel.ID, dest)
+	if (err == nil && stat.Mode.IsDir()) || strings.HasSuffix(dest, "/") {
+		dir, name = dest, filepath.Base(src)
+	}
+
+	size, err := transfer.Size(src)
+	if err != nil {
+		lumber.Error("app:CopyTo:transfer.Size(%s): %s", src, err.Error())
+		return util.ErrorAppend(err, "failed to read %s", src)
+	}
+
+	fmt.Printf("\nCopying %s to %s:%s\n", src, componentModel.Name, path.Join(dir, name))
+	progress := display.DownloadPercent{Total: size}
+
+	r, w := io.Pipe()
+	go func() {
+		w.CloseWithError(transfer.Pack(src, name, w, progress.Add))
+	}()
+
+	err = docker.Client.CopyToContainer(context.Background(), componentModel.ID, dir, r, types.CopyToContainerOptions{})
+	r.Close()
+	fmt.Println()
+	if err != nil {
+		lumber.Error("app:CopyTo:docker.Client.CopyToContainer(%s, %s): %s", componentModel.ID, dir, err.Error())
+		return util.ErrorAppend(err, "failed to copy %s into %s", src, componentModel.Name)
+	}
+
+	if info.IsDir() {
+		fmt.Printf("Copied the directory %s\n\n", src)
+	} else {
+		fmt.Printf("Copied the file %s\n\n", src)
+	}
+
+	return nil
+}
+
+// CopyFrom copies the file or directory at src inside the component to dest
+// on the host. Like cp, if dest is a directory src is copied into it,
+// otherwise src is copied as dest.
+func CopyFrom(componentModel *models.Component, src, dest string) error {
+	rc, stat, err := docker.Client.CopyFromContainer(context.Background(), componentModel.ID, src)
+	if err != nil {
+		lumber.Error("app:CopyFrom:docker.Client.CopyFromContainer(%s, %s): %s", componentModel.ID, src, err.Error())
+		return util.ErrorAppend(err, "failed to copy %s from %s", src, componentModel.Name)
+	}
+	defer rc.Close()
+
+	if strings.HasSuffix(dest, string(os.PathSeparator)) || strings.HasSuffix(dest, "/") {
+		if err := os.MkdirAll(dest, 0755); err != nil {
+			return util.ErrorAppend(err, "failed to create %s", dest)
+		}
+	}
+
+	dir, name := filepath.Dir(dest), filepath.Base(dest)
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		dir, name = dest, ""
+	}
+
+	// the size of a directory isn't the size of its content, so only the
+	// copied bytes are shown
+	var size int64
+	if stat.Mode.IsRegular() {
+		size = stat.Size
+	}
+
+	fmt.Printf("\nCopying %s:%s to %s\n", componentModel.Name, src, dest)
+	progress := display.DownloadPercent{Total: size}
+
+	err = transfer.Unpack(rc, dir, name, progress.Add)
+	fmt.Println()
+	if err != nil {
+		lumber.Error("app:CopyFrom:transfer.Unpack(%s): %s", dest, err.Error())
+		return util.ErrorAppend(err, "failed to write %s", dest)
+	}
+
+	if stat.Mode.IsDir() {
+		fmt.Printf("Copied the directory %s\n\n", src)
+	} else {
+		fmt.Printf("Copied the file %s\n\n", src)
+	}
+
+	return nil
+}
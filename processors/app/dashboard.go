@@ -0,0 +1,403 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/docker/pkg/term"
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/console"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// the kinds of events the dashboard redraws on
+const (
+	eventStats   = "stats"
+	eventLog     = "log"
+	eventState   = "state"
+	eventKey     = "key"
+	eventMessage = "message"
+	eventTick    = "tick"
+)
+
+// dashboardLogLines is how many lines of log are kept per component
+const dashboardLogLines = 200
+
+// dashboardEvent is something that happened to a component or a key the user
+// pressed. The streams of every component feed a single channel, the
+// dashboard redraws as the events come in.
+type dashboardEvent struct {
+	kind   string
+	name   string
+	stats  *types.StatsJSON
+	line   string
+	state  string
+	health string
+	key    string
+}
+
+// dashboardRow is what the dashboard knows about a component
+type dashboardRow struct {
+	component *models.Component
+	state     string
+	health    string
+	stats     *types.StatsJSON
+	logs      []string
+}
+
+// Dashboard shows every component of the app with its state, IP, health and
+// resource usage, and the live log of the selected one. A component can be
+// restarted, or consoled into, which leaves the dashboard.
+func Dashboard(appModel *models.App) error {
+	// init docker client
+	if err := provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	if appModel.Status != "up" {
+		return util.Errorf("[USER] the app is not running")
+	}
+
+	if !display.Interactive {
+		return util.Errorf("[USER] the dashboard needs a terminal, use 'nanobox stats' instead")
+	}
+
+	componentModels, err := appModel.Components()
+	if err != nil {
+		lumber.Error("app:Dashboard:models.App{ID:%s}.Components(): %s", appModel.ID, err.Error())
+		return util.ErrorAppend(err, "unable to retrieve components")
+	}
+
+	rows := []*dashboardRow{}
+	for _, componentModel := range componentModels {
+		if componentModel.ID != "" {
+			rows = append(rows, &dashboardRow{component: componentModel, state: "-", health: "-"})
+		}
+	}
+	sort.Sort(dashboardRows(rows))
+
+	if len(rows) == 0 {
+		return util.Errorf("[USER] the app has no components to show")
+	}
+
+	events := make(chan dashboardEvent, 100)
+	done := make(chan struct{})
+	defer close(done)
+
+	for _, row := range rows {
+		go dashboardStats(row.component, events, done)
+		go dashboardLogs(row.component, events, done)
+		go dashboardState(row.component, events, done)
+	}
+	go dashboardTicks(events, done)
+
+	// read the keys one at a time, without echoing them
+	fd, _ := term.GetFdInfo(os.Stdin)
+	oldState, err := term.SetRawTerminal(fd)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to set the terminal to raw mode")
+	}
+	go dashboardKeys(os.Stdin, events)
+
+	consoleInto, err := runDashboard(appModel, rows, events, done)
+
+	term.RestoreTerminal(fd, oldState)
+
+	// clear the screen and show the cursor again
+	fmt.Print("\033[2J\033[H\033[?25h")
+
+	if err != nil || consoleInto == nil {
+		return err
+	}
+
+	return console.Run(consoleInto.ID, console.ConsoleConfig{})
+}
+
+// runDashboard handles the events until the user quits. It returns the
+// component to console into, if the user picked one.
+func runDashboard(appModel *models.App, rows []*dashboardRow, events chan dashboardEvent, done chan struct{}) (*models.Component, error) {
+	selected := 0
+	message := ""
+
+	byName := map[string]*dashboardRow{}
+	for _, row := range rows {
+		byName[row.component.Name] = row
+	}
+
+	for event := range events {
+		row := byName[event.name]
+
+		switch event.kind {
+		case eventStats:
+			row.stats = event.stats
+		case eventLog:
+			row.logs = append(row.logs, event.line)
+			if len(row.logs) > dashboardLogLines {
+				row.logs = row.logs[len(row.logs)-dashboardLogLines:]
+			}
+			// only the log of the selected component is on screen
+			if row != rows[selected] {
+				continue
+			}
+		case eventState:
+			row.state = event.state
+			row.health = event.health
+		case eventMessage:
+			message = event.line
+		case eventKey:
+			switch event.key {
+			case "q", "\x03":
+				return nil, nil
+			case "up", "k":
+				if selected > 0 {
+					selected--
+				}
+			case "down", "j":
+				if selected < len(rows)-1 {
+					selected++
+				}
+			case "c":
+				return rows[selected].component, nil
+			case "r":
+				message = fmt.Sprintf("Restarting %s...", rows[selected].component.Name)
+				go restartComponent(rows[selected].component, events, done)
+			}
+		}
+
+		drawDashboard(appModel, rows, selected, message)
+	}
+
+	return nil, nil
+}
+
+// drawDashboard redraws the whole screen
+func drawDashboard(appModel *models.App, rows []*dashboardRow, selected int, message string) {
+	height, width := util.GetTerminalSize()
+
+	lines := []string{
+		fmt.Sprintf("nanobox dashboard - %s", appModel.DisplayName()),
+		"up/down select   r restart   c console   q quit",
+		"",
+		fmt.Sprintf("  %-20s %-10s %-10s %-15s %-8s %s", "COMPONENT", "STATE", "HEALTH", "IP", "CPU %", "MEM USAGE / LIMIT"),
+	}
+
+	for i, row := range rows {
+		marker := " "
+		if i == selected {
+			marker = ">"
+		}
+
+		cpu, mem := "--", "--"
+		if row.stats != nil {
+			cpu = fmt.Sprintf("%.2f%%", cpuPercent(row.stats))
+			mem = fmt.Sprintf("%s / %s", byteSize(row.stats.MemoryStats.Usage), byteSize(row.stats.MemoryStats.Limit))
+		}
+
+		lines = append(lines, fmt.Sprintf("%s %-20s %-10s %-10s %-15s %-8s %s",
+			marker, row.component.Name, row.state, row.health, row.component.IPAddr(), cpu, mem))
+	}
+
+	lines = append(lines, "", message, fmt.Sprintf("Logs of %s", rows[selected].component.Name))
+
+	// the log fills the rest of the screen
+	logs := rows[selected].logs
+	if room := height - len(lines) - 1; room < len(logs) {
+		if room < 0 {
+			room = 0
+		}
+		logs = logs[len(logs)-room:]
+	}
+	lines = append(lines, logs...)
+
+	// the terminal is raw, a new line doesn't return the cursor
+	out := "\033[?25l\033[2J\033[H"
+	for _, line := range lines {
+		if len(line) > width {
+			line = line[:width]
+		}
+		out += line + "\r\n"
+	}
+	fmt.Print(out)
+}
+
+// dashboardStats streams the resource usage of a component
+func dashboardStats(componentModel *models.Component, events chan dashboardEvent, done chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-done
+		cancel()
+	}()
+
+	rc, err := docker.Client.ContainerStats(ctx, componentModel.ID, true)
+	if err != nil {
+		lumber.Error("app:dashboardStats:docker.Client.ContainerStats(%s): %s", componentModel.ID, err.Error())
+		return
+	}
+	defer rc.Close()
+
+	decoder := json.NewDecoder(rc)
+	for {
+		sample := &types.StatsJSON{}
+		if err := decoder.Decode(sample); err != nil {
+			return
+		}
+
+		if !sendEvent(events, done, dashboardEvent{kind: eventStats, name: componentModel.Name, stats: sample}) {
+			return
+		}
+	}
+}
+
+// dashboardLogs follows the output of a component
+func dashboardLogs(componentModel *models.Component, events chan dashboardEvent, done chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-done
+		cancel()
+	}()
+
+	opts := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Tail:       "50",
+	}
+
+	rc, err := docker.Client.ContainerLogs(ctx, componentModel.ID, opts)
+	if err != nil {
+		lumber.Error("app:dashboardLogs:docker.Client.ContainerLogs(%s): %s", componentModel.ID, err.Error())
+		return
+	}
+	defer rc.Close()
+
+	// docker multiplexes stdout and stderr into frames
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(pw, pw, rc)
+		pw.CloseWithError(err)
+	}()
+
+	scanner := bufio.NewScanner(pr)
+	for scanner.Scan() {
+		if !sendEvent(events, done, dashboardEvent{kind: eventLog, name: componentModel.Name, line: scanner.Text()}) {
+			return
+		}
+	}
+}
+
+// dashboardState polls the state and the health of a component's container
+func dashboardState(componentModel *models.Component, events chan dashboardEvent, done chan struct{}) {
+	for {
+		state, health := "missing", "-"
+
+		container, err := docker.GetContainer(componentModel.ID)
+		if err == nil {
+			state = container.State.Status
+			if container.State.Health != nil {
+				health = container.State.Health.Status
+			}
+		}
+
+		if !sendEvent(events, done, dashboardEvent{kind: eventState, name: componentModel.Name, state: state, health: health}) {
+			return
+		}
+
+		select {
+		case <-done:
+			return
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// dashboardTicks redraws the dashboard every so often, so the terminal size
+// is picked up even when nothing happens
+func dashboardTicks(events chan dashboardEvent, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-time.After(statsInterval):
+		}
+
+		if !sendEvent(events, done, dashboardEvent{kind: eventTick}) {
+			return
+		}
+	}
+}
+
+// dashboardKeys reads the keys the user presses. The arrows are escape
+// sequences, ie: ESC [ A.
+func dashboardKeys(in io.Reader, events chan dashboardEvent) {
+	buf := make([]byte, 8)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return
+		}
+
+		key := string(buf[:n])
+		switch key {
+		case "\x1b[A":
+			key = "up"
+		case "\x1b[B":
+			key = "down"
+		}
+
+		events <- dashboardEvent{kind: eventKey, key: key}
+
+		// stop reading once the dashboard is left, the console reads the
+		// keys from there on
+		if key == "q" || key == "\x03" || key == "c" {
+			return
+		}
+	}
+}
+
+// restartComponent stops and starts the container of a component
+func restartComponent(componentModel *models.Component, events chan dashboardEvent, done chan struct{}) {
+	message := fmt.Sprintf("Restarted %s", componentModel.Name)
+
+	if err := docker.ContainerStop(componentModel.ID); err != nil {
+		lumber.Error("app:restartComponent:docker.ContainerStop(%s): %s", componentModel.ID, err.Error())
+	}
+
+	if err := docker.ContainerStart(componentModel.ID); err != nil {
+		lumber.Error("app:restartComponent:docker.ContainerStart(%s): %s", componentModel.ID, err.Error())
+		message = fmt.Sprintf("! Failed to restart %s: %s", componentModel.Name, err.Error())
+	}
+
+	sendEvent(events, done, dashboardEvent{kind: eventMessage, line: message})
+}
+
+// sendEvent sends an event unless the dashboard is closed
+func sendEvent(events chan dashboardEvent, done chan struct{}, event dashboardEvent) bool {
+	select {
+	case <-done:
+		return false
+	case events <- event:
+		return true
+	}
+}
+
+// dashboardRows sorts the rows by component name
+type dashboardRows []*dashboardRow
+
+func (r dashboardRows) Len() int           { return len(r) }
+func (r dashboardRows) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r dashboardRows) Less(i, j int) bool { return r[i].component.Name < r[j].component.Name }
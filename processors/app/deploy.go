@@ -1,6 +1,9 @@
 package app
 
 import (
+	"time"
+
+	"github.com/jcelliott/lumber"
 	"github.com/nanobox-io/nanobox-boxfile"
 
 	generator "github.com/nanobox-io/nanobox/generators/hooks/code"
@@ -9,11 +12,23 @@ import (
 	"github.com/nanobox-io/nanobox/processors/component"
 	"github.com/nanobox-io/nanobox/processors/platform"
 	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/processors/sidecar"
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/events"
 	"github.com/nanobox-io/nanobox/util/hookit"
 )
 
+// Canary is the share of the web traffic, in percent, the new build serves
+// next to the previous one before it goes live. 0 goes live right away.
+var Canary int
+
+// how long the new build has to stay healthy before it's live
+const (
+	canaryWindow = 30 * time.Second
+	healthWindow = 10 * time.Second
+)
+
 // Deploy ...
 func Deploy(envModel *models.Env, appModel *models.App) error {
 
@@ -29,6 +44,10 @@ func Deploy(envModel *models.Env, appModel *models.App) error {
 
 	// if the app is a dev app then we should leave here
 	if appModel.Name == "dev" {
+		// there is no release on dev, the sidecars follow the components
+		if err := sidecar.Sync(appModel); err != nil {
+			return util.ErrorAppend(err, "failed to sync sidecars")
+		}
 		return nil
 	}
 
@@ -37,37 +56,149 @@ func Deploy(envModel *models.Env, appModel *models.App) error {
 		return util.ErrorAppend(err, "failed to setup platform services")
 	}
 
+	live, err := models.LiveDeployRecords(appModel.ID)
+	if err != nil {
+		lumber.Error("app:Deploy:models.LiveDeployRecords(%s): %s", appModel.ID, err.Error())
+		return util.ErrorAppend(err, "failed to load the deploy history")
+	}
+
+	record, err := startDeployRecord(appModel, envModel.BuiltID, appModel.DeployedBoxfile)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to record the deploy")
+	}
+
 	// create the warehouse config for child processes
-	hoarder, _ := models.FindComponentBySlug(appModel.ID, "hoarder")
+	warehouseConfig := simWarehouseConfig(appModel, envModel.BuiltID)
+	if len(live) > 0 {
+		warehouseConfig.PreviousBuild = live[0].BuildID
+	}
 
-	warehouseConfig := code.WarehouseConfig{
-		BuildID:        "1234",
-		WarehouseURL:   hoarder.IPAddr(),
-		WarehouseToken: "123",
+	// run the before_deploy hooks with the evars of this app
+	if err := code.BeforeDeploy(envModel, appModel.Evars); err != nil {
+		finishDeployRecord(record, models.DeployFailed)
+		deployEvent(appModel, err)
+		return util.ErrorAppend(err, "failed to run the before_deploy hooks")
 	}
 
 	// publish the code
 	if err := code.Publish(envModel, warehouseConfig); err != nil {
+		finishDeployRecord(record, models.DeployFailed)
+		deployEvent(appModel, err)
 		return util.ErrorAppend(err, "unable to publish code")
 	}
 
+	// the canary needs something to run next to
+	canary := Canary
+	if len(live) == 0 {
+		canary = 0
+	}
+
+	if err := release(appModel, warehouseConfig, canary); err != nil {
+		finishDeployRecord(record, models.DeployFailed)
+		deployEvent(appModel, err)
+
+		// the app was changed, put the last good deploy back
+		if len(live) > 0 {
+			display.Warn("The deploy failed, rolling back to deploy %d\n", live[0].Number)
+			if err2 := redeploy(appModel, live[0]); err2 != nil {
+				return util.ErrorAppend(err2, "failed to roll back after the deploy failed: %s", err.Error())
+			}
+		}
+
+		return util.ErrorAppend(err, "failed to release the deploy")
+	}
+
+	finishDeployRecord(record, models.DeployLive)
+	deployEvent(appModel, nil)
+
+	// give the user some helpful information
+	display.InfoSimDeploy(appModel.LocalIPs["env"])
+
+	// ship the logs to the log-forward sinks while they're shown
+	stopForward, err := StartForward(appModel)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to start forwarding the logs")
+	}
+	defer stopForward()
+
+	return platform.MistListen(appModel)
+}
+
+// deployEvent tells the webhooks how the deploy ended
+func deployEvent(appModel *models.App, err error) {
+	event := events.New(events.DeployComplete, appModel)
+	if err != nil {
+		event.Type, event.Error = events.DeployFailed, err.Error()
+	}
+
+	events.Emit(event)
+}
+
+// release replaces the code of the app with the build in the warehouse and
+// sends the traffic to it. With a canary the previous web components keep
+// serving the rest of the traffic until the new build is verified.
+func release(appModel *models.App, warehouseConfig code.WarehouseConfig, canary int) (err error) {
+	stable := map[string]*models.Component{}
+
+	if canary > 0 {
+		if stable, err = code.RetireWeb(appModel); err != nil {
+			return util.ErrorAppend(err, "failed to keep the stable web components")
+		}
+	} else {
+		// stop routing traffic to the old code before it is replaced
+		if err := platform.DrainPortal(appModel); err != nil {
+			return util.ErrorAppend(err, "failed to drain the router")
+		}
+
+		// point the router back at the app when the deploy fails drained
+		defer func() {
+			if err == nil {
+				return
+			}
+			if err := platform.UpdatePortal(appModel); err != nil {
+				lumber.Error("app:release:platform.UpdatePortal(): %s", err.Error())
+			}
+		}()
+	}
+
+	// the stable components are gone once the deploy is live or rolled back
+	defer func() {
+		if err := code.RemoveStable(appModel); err != nil {
+			lumber.Error("app:release:code.RemoveStable(): %s", err.Error())
+		}
+	}()
+
 	// start code
 	if err := code.Sync(appModel, warehouseConfig); err != nil {
 		return util.ErrorAppend(err, "failed to add code components")
 	}
 
-	if err := finalizeDeploy(appModel); err != nil {
+	// start the sidecars with the new evars
+	if err := sidecar.Sync(appModel); err != nil {
+		return util.ErrorAppend(err, "failed to sync sidecars")
+	}
+
+	if err := finalizeDeploy(appModel, stable, canary); err != nil {
 		return util.ErrorAppend(err, "failed to finalize deploy")
 	}
 
-	// give the user some helpful information
-	display.InfoSimDeploy(appModel.LocalIPs["env"])
+	return nil
+}
 
-	return platform.MistListen(appModel)
+// simWarehouseConfig returns the warehouse config of a build in the hoarder
+// of the app
+func simWarehouseConfig(appModel *models.App, buildID string) code.WarehouseConfig {
+	hoarder, _ := models.FindComponentBySlug(appModel.ID, "hoarder")
+
+	return code.WarehouseConfig{
+		BuildID:        buildID,
+		WarehouseURL:   hoarder.IPAddr(),
+		WarehouseToken: "123",
+	}
 }
 
 // update the router and run deploy hooks
-func finalizeDeploy(appModel *models.App) error {
+func finalizeDeploy(appModel *models.App, stable map[string]*models.Component, canary int) error {
 	display.OpenContext("Finalizing deploy")
 	defer display.CloseContext()
 
@@ -78,6 +209,30 @@ func finalizeDeploy(appModel *models.App) error {
 	}
 	display.StopTask()
 
+	// wait for the web components to come up before sending them traffic
+	display.StartTask("Waiting for web components")
+	if err := platform.WaitHealthy(appModel); err != nil {
+		display.ErrorTask()
+		return util.ErrorAppend(err, "web components failed to become healthy")
+	}
+	display.StopTask()
+
+	if len(stable) > 0 {
+		display.StartTask("Sending %d%% of the traffic to the canary", canary)
+		if err := platform.RouteCanary(appModel, stable, canary); err != nil {
+			display.ErrorTask()
+			return util.ErrorAppend(err, "failed to route traffic to the canary")
+		}
+		display.StopTask()
+
+		display.StartTask("Verifying canary")
+		if err := platform.VerifyHealthy(appModel, canaryWindow); err != nil {
+			display.ErrorTask()
+			return util.ErrorAppend(err, "the canary failed verification")
+		}
+		display.StopTask()
+	}
+
 	// update nanoagent portal
 	display.StartTask("Updating router")
 	if err := platform.UpdatePortal(appModel); err != nil {
@@ -86,6 +241,13 @@ func finalizeDeploy(appModel *models.App) error {
 	}
 	display.StopTask()
 
+	display.StartTask("Verifying web components")
+	if err := platform.VerifyHealthy(appModel, healthWindow); err != nil {
+		display.ErrorTask()
+		return util.ErrorAppend(err, "web components failed verification")
+	}
+	display.StopTask()
+
 	display.StartTask("Running after_live hooks")
 	if err := runDeployHook(appModel, "after_live"); err != nil {
 		display.ErrorTask()
@@ -3,6 +3,7 @@ package app
 import (
 	"fmt"
 	"net"
+	"os"
 	"strings"
 
 	"github.com/jcelliott/lumber"
@@ -16,6 +17,7 @@ import (
 	"github.com/nanobox-io/nanobox/util/dhcp"
 	"github.com/nanobox-io/nanobox/util/display"
 	"github.com/nanobox-io/nanobox/util/locker"
+	"github.com/nanobox-io/nanobox/util/logs"
 )
 
 // Destroy removes the app from the provider and the database
@@ -60,6 +62,18 @@ func Destroy(appModel *models.App) error {
 		return util.ErrorAppend(err, "failed to release IPs")
 	}
 
+	// the secrets go with the app
+	secrets, _ := models.FindSecretsByApp(appModel.ID)
+	if err := secrets.Delete(); err != nil {
+		lumber.Error("app:Destroy:models.Secrets{AppID:%s}.Delete(): %s", appModel.ID, err.Error())
+		return util.ErrorAppend(err, "failed to delete app secrets")
+	}
+
+	// the stored logs go with the app
+	if err := os.RemoveAll(logs.AppDir(appModel.ID)); err != nil {
+		lumber.Error("app:Destroy:os.RemoveAll(%s): %s", logs.AppDir(appModel.ID), err.Error())
+	}
+
 	// destroy the app model
 	if err := appModel.Delete(); err != nil {
 		lumber.Error("app:Destroy:models.App{ID:%s}.Destroy(): %s", appModel.ID, err.Error())
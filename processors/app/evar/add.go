@@ -9,15 +9,25 @@ import (
 	"github.com/nanobox-io/nanobox/util/display"
 )
 
-func Add(envModel *models.Env, appModel *models.App, evars map[string]string) error {
+// Add adds evars to the app, or only to a component of the app
+func Add(envModel *models.Env, appModel *models.App, component string, evars map[string]string) error {
 
 	if err := app.Setup(envModel, appModel, appModel.Name); err != nil {
 		return util.ErrorAppend(err, "failed to setup app")
 	}
 
+	scoped, err := scopedEvars(appModel, component)
+	if err != nil {
+		return err
+	}
+
 	// iterate through the evars and add them to the app
 	for key, val := range evars {
-		appModel.Evars[key] = val
+		scoped[key] = val
+	}
+
+	if err := checkTemplates(appModel); err != nil {
+		return err
 	}
 
 	// save the app
@@ -32,5 +42,5 @@ func Add(envModel *models.Env, appModel *models.App, evars map[string]string) er
 	}
 	fmt.Println()
 
-	return nil
+	return app.PropagateEvars(appModel)
 }
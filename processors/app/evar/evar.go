@@ -0,0 +1,48 @@
+package evar
+
+import (
+	"strings"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/interpolate"
+)
+
+// scopedEvars returns the evars to change, the evars of the app or, with a
+// component, the evars scoped to it
+func scopedEvars(appModel *models.App, component string) (map[string]string, error) {
+	if component == "" {
+		return appModel.Evars, nil
+	}
+
+	// components are named after their boxfile node, ie: web.main
+	if parts := strings.SplitN(component, ".", 2); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, util.Errorf("[USER] '%s' isn't a component, use the name of its boxfile node, ie: web.main", component)
+	}
+
+	if appModel.ComponentEvars == nil {
+		appModel.ComponentEvars = map[string]map[string]string{}
+	}
+
+	if appModel.ComponentEvars[component] == nil {
+		appModel.ComponentEvars[component] = map[string]string{}
+	}
+
+	return appModel.ComponentEvars[component], nil
+}
+
+// checkTemplates makes sure the evars of the app, and the evars of each
+// component with scoped evars, don't reference each other in a cycle
+func checkTemplates(appModel *models.App) error {
+	if _, err := interpolate.Evars(appModel.Evars); err != nil {
+		return util.Errorf("[USER] %s", err.Error())
+	}
+
+	for component := range appModel.ComponentEvars {
+		if _, err := interpolate.Evars(appModel.EvarsFor(component)); err != nil {
+			return util.Errorf("[USER] %s in %s", err.Error(), component)
+		}
+	}
+
+	return nil
+}
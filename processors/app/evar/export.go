@@ -0,0 +1,46 @@
+package evar
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+)
+
+// Export writes the evars of the app, or the evars a component runs with,
+// to w as a .env file. The evars nanobox generates are left out unless all
+// is set, the next app generates its own.
+func Export(appModel *models.App, component string, w io.Writer, all bool) error {
+	generated, err := appModel.GeneratedEvars()
+	if err != nil {
+		return util.ErrorAppend(err, "failed to load the generated evars")
+	}
+
+	evars := map[string]string{}
+	for key, val := range appModel.EvarsFor(component) {
+		if all || !generated[key] {
+			evars[key] = val
+		}
+	}
+
+	WriteDotenv(w, evars)
+
+	return nil
+}
+
+// WriteDotenv writes evars as KEY="value" lines, sorted by key. The values
+// are quoted so 'nanobox evar load' reads them back as they were.
+func WriteDotenv(w io.Writer, evars map[string]string) {
+	keys := []string{}
+	for key := range evars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s=%s\n", key, strconv.Quote(evars[key]))
+	}
+}
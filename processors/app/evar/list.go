@@ -2,21 +2,71 @@ package evar
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util/display"
 )
 
-func List(appModel *models.App) error {
+// List prints the evars of the app and the evars scoped to its components.
+// With a component it prints the evars that component runs with.
+func List(appModel *models.App, component string) error {
+
+	if display.JSON() {
+		if component != "" {
+			return display.PrintJSON(appModel.EvarsFor(component))
+		}
+
+		return display.PrintJSON(map[string]interface{}{
+			"evars":      appModel.Evars,
+			"components": appModel.ComponentEvars,
+		})
+	}
+
+	if component != "" {
+		fmt.Printf("\nEnvironment Variables of %s\n", component)
+		scoped := appModel.ComponentEvars[component]
+		printEvars(appModel.EvarsFor(component), func(key string) bool {
+			_, ok := scoped[key]
+			return ok
+		})
+		fmt.Println()
+		return nil
+	}
 
 	// print the header
 	fmt.Printf("\nEnvironment Variables\n")
+	printEvars(appModel.Evars, nil)
+
+	components := []string{}
+	for name := range appModel.ComponentEvars {
+		components = append(components, name)
+	}
+	sort.Strings(components)
 
-	// iterate
-	for key, val := range appModel.Evars {
-		fmt.Printf("  %s = %s\n", key, val)
+	for _, name := range components {
+		fmt.Printf("\nScoped to %s\n", name)
+		printEvars(appModel.ComponentEvars[name], nil)
 	}
 
 	fmt.Println()
 
 	return nil
 }
+
+// printEvars prints evars sorted by key, the scoped ones are marked
+func printEvars(evars map[string]string, scoped func(key string) bool) {
+	keys := []string{}
+	for key := range evars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		marker := ""
+		if scoped != nil && scoped(key) {
+			marker = " (scoped)"
+		}
+		fmt.Printf("  %s = %s%s\n", key, evars[key], marker)
+	}
+}
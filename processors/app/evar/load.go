@@ -0,0 +1,34 @@
+package evar
+
+import (
+	"fmt"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+)
+
+// Load merges evars from a file into the app. The evars nanobox generates,
+// like the credentials of the data components, are left alone.
+func Load(envModel *models.Env, appModel *models.App, component string, evars map[string]string) error {
+	generated, err := appModel.GeneratedEvars()
+	if err != nil {
+		return util.ErrorAppend(err, "failed to load the generated evars")
+	}
+
+	skipped := []string{}
+	for key := range evars {
+		if generated[key] {
+			skipped = append(skipped, key)
+			delete(evars, key)
+		}
+	}
+
+	if len(skipped) > 0 {
+		fmt.Println()
+		for _, key := range skipped {
+			fmt.Printf("! %s is generated by nanobox, skipped\n", key)
+		}
+	}
+
+	return Add(envModel, appModel, component, evars)
+}
@@ -4,15 +4,26 @@ import (
 	"fmt"
 
 	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/display"
 )
 
-func Remove(appModel *models.App, keys []string) error {
+// Remove removes evars from the app, or from a component of the app
+func Remove(appModel *models.App, component string, keys []string) error {
+
+	scoped, err := scopedEvars(appModel, component)
+	if err != nil {
+		return err
+	}
 
 	// delete the evars
 	for _, key := range keys {
-		delete(appModel.Evars, key)
+		delete(scoped, key)
+	}
+
+	if component != "" && len(scoped) == 0 {
+		delete(appModel.ComponentEvars, component)
 	}
 
 	// persist the app model
@@ -27,5 +38,5 @@ func Remove(appModel *models.App, keys []string) error {
 	}
 	fmt.Println()
 
-	return nil
+	return app.PropagateEvars(appModel)
 }
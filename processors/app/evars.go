@@ -0,0 +1,66 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/code"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	util_provider "github.com/nanobox-io/nanobox/util/provider"
+)
+
+// Restart restarts the components that run with stale evars without asking
+var Restart bool
+
+// PropagateEvars finds the running code components that still have the old
+// evars after a change and restarts them, once the user agrees
+func PropagateEvars(appModel *models.App) error {
+	// nothing runs while the vm is down
+	if !util_provider.IsReady() || provider.Init() != nil {
+		return nil
+	}
+
+	stale, err := code.StaleComponents(appModel)
+	if err != nil || len(stale) == 0 {
+		return err
+	}
+
+	names := []string{}
+	for _, componentModel := range stale {
+		names = append(names, componentModel.Name)
+	}
+
+	if !Restart {
+		if !display.CanPrompt {
+			fmt.Printf("! %s still run with the old evars, pass --restart to restart them\n\n", strings.Join(names, ", "))
+			return nil
+		}
+
+		answer, err := display.Ask(fmt.Sprintf("%s still run with the old evars, restart them now? [Y/n]", strings.Join(names, ", ")))
+		if err != nil {
+			return util.ErrorAppend(err, "failed to read the answer")
+		}
+
+		if answer = strings.ToLower(answer); answer != "" && answer != "y" && answer != "yes" {
+			fmt.Println()
+			return nil
+		}
+	}
+
+	display.OpenContext("Applying evars")
+	defer display.CloseContext()
+
+	for _, componentModel := range stale {
+		if err := code.Refresh(appModel, componentModel); err != nil {
+			lumber.Error("app:PropagateEvars:code.Refresh(%s): %s", componentModel.Name, err.Error())
+			return util.ErrorAppend(err, "failed to restart %s", componentModel.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"time"
+
+	"github.com/jcelliott/lumber"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/logs"
+)
+
+// StartForward ships the logs of the app's components to the sinks of the
+// log-forward config, ie: the syslog, fluentd or loki of a team's logging
+// pipeline, until it's stopped. It's a no-op without sinks.
+func StartForward(appModel *models.App) (func(), error) {
+	ship, stopShip, err := forwarder(appModel)
+	if err != nil || ship == nil {
+		return func() {}, err
+	}
+
+	sources, _, err := logSources(appModel, "", false)
+	if err != nil {
+		stopShip()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		filter := logs.Filter{Since: time.Now(), Follow: true}
+		if err := logs.Aggregate(ctx, sources, filter, ship); err != nil && ctx.Err() == nil {
+			lumber.Error("app:StartForward:logs.Aggregate(): %s", err.Error())
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+		stopShip()
+	}, nil
+}
+
+// forwarder returns a func that ships a line to the sinks of the log-forward
+// config and a func that closes them. The ship func is nil without sinks.
+func forwarder(appModel *models.App) (func(logs.Line), func(), error) {
+	config, _ := models.LoadConfig()
+
+	sinks, err := logs.ParseSinks(config.LogForward)
+	if err != nil {
+		return nil, nil, util.Errorf("[USER] the log-forward config is invalid: %s", err.Error())
+	}
+
+	if len(sinks) == 0 {
+		return nil, func() {}, nil
+	}
+
+	name := forwardName(appModel)
+
+	ship := func(line logs.Line) {
+		for _, sink := range sinks {
+			if err := sink.Send(name, line); err != nil {
+				lumber.Error("app:forwarder:logs.Sink.Send(): %s", err.Error())
+			}
+		}
+	}
+
+	stop := func() {
+		for _, sink := range sinks {
+			if err := sink.Close(); err != nil {
+				lumber.Error("app:forwarder:logs.Sink.Close(): %s", err.Error())
+			}
+		}
+	}
+
+	return ship, stop, nil
+}
+
+// forwardName returns the name the logs of the app are shipped under, the
+// name of the app and of the environment, ie: shop-local or shop-staging
+func forwardName(appModel *models.App) string {
+	name := appModel.ID
+	if envModel, err := appModel.Env(); err == nil && envModel.Name != "" {
+		name = envModel.Name
+	}
+
+	env := appModel.Name
+	switch env {
+	case "dev":
+		env = "local"
+	case "sim":
+		env = "dry-run"
+	}
+
+	return name + "-" + env
+}
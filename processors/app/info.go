@@ -2,17 +2,53 @@ package app
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/nanobox-io/nanobox-boxfile"
+
 	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
 	"github.com/nanobox-io/nanobox/util/dns"
+	util_provider "github.com/nanobox-io/nanobox/util/provider"
 )
 
-// Info ...
+// infoReport is everything there is to know about an environment of an app
+type infoReport struct {
+	App      string            `json:"app"`
+	Env      string            `json:"env"`
+	Status   string            `json:"status"`
+	Provider string            `json:"provider"`
+	Path     string            `json:"path"` // mounted into the code components at /app
+	IP       string            `json:"ip"`   // the env ip
+	URLs     []string          `json:"urls"` // where the app is served
+	DNS      []string          `json:"dns"`  // the dns aliases
+	Services []infoService     `json:"services"`
+	Evars    map[string]string `json:"evars"`
+}
+
+// infoService is a component of the report
+type infoService struct {
+	Name  string                     `json:"name"`
+	Label string                     `json:"label"`
+	Type  string                     `json:"type"`
+	IP    string                     `json:"ip"`
+	Ports []string                   `json:"ports"`
+	URL   string                     `json:"url,omitempty"` // the connection url of the default user
+	Users []models.ComponentPlanUser `json:"users"`
+}
+
+// Info prints the services, IPs, credentials, ports, mounted code and urls
+// of the app in one place
 func Info(env *models.Env, app *models.App) error {
 
 	if app.State != "active" {
+		if display.JSON() {
+			return util.Errorf("[USER] no environment has been setup for %s", config.LocalDir())
+		}
+
 		fmt.Printf("\n------------------------------------------------------\n")
 		fmt.Printf("Whoops, it doesn't look like a dev environment has\n")
 		fmt.Printf("been setup for the current working directory:\n")
@@ -22,33 +58,47 @@ func Info(env *models.Env, app *models.App) error {
 		return nil
 	}
 
+	report := infoFor(env, app)
+
+	if display.JSON() {
+		return display.PrintJSON(report)
+	}
+
 	// print header
 	line := strings.Repeat("-", len(env.Name)+32)
 	fmt.Printf("\n%s\n", line)
 	fmt.Printf("%s (%s)              Status: %s  \n", env.Name, app.Name, app.Status)
 	fmt.Printf("%s\n", line)
 
-	fmt.Printf("\nMount Path: %s\n", env.Directory)
-	fmt.Printf("Env IP: %s\n", app.LocalIPs["env"])
+	fmt.Printf("\nProvider: %s\n", report.Provider)
+	fmt.Printf("Mount Path: %s -> /app\n", report.Path)
+	fmt.Printf("Env IP: %s\n", report.IP)
+	fmt.Printf("URL: %s\n", strings.Join(report.URLs, ", "))
 
-	components, _ := app.Components()
-
-	for _, component := range components {
+	for _, service := range report.Services {
 
 		// print the component header
-		if component.Name != component.Label {
-			fmt.Printf("\n%s (%s)\n", component.Name, component.Label)
+		if service.Name != service.Label {
+			fmt.Printf("\n%s (%s)\n", service.Name, service.Label)
 		} else {
-			fmt.Printf("\n%s\n", component.Name)
+			fmt.Printf("\n%s\n", service.Name)
 		}
 
 		// print the IP
-		fmt.Printf("  IP      : %s\n", component.IPAddr())
+		fmt.Printf("  IP      : %s\n", service.IP)
+
+		if len(service.Ports) > 0 {
+			fmt.Printf("  Ports   : %s\n", strings.Join(service.Ports, ", "))
+		}
+
+		if service.URL != "" {
+			fmt.Printf("  URL     : %s\n", service.URL)
+		}
 
 		// print users
-		if len(component.Plan.Users) > 0 {
+		if len(service.Users) > 0 {
 			fmt.Printf("  User(s) :\n")
-			for _, user := range component.Plan.Users {
+			for _, user := range service.Users {
 				fmt.Printf("    %s - %s\n", user.Username, user.Password)
 			}
 		}
@@ -56,19 +106,23 @@ func Info(env *models.Env, app *models.App) error {
 
 	// print environment variables
 	fmt.Printf("\nEnvironment Variables\n")
-	for key, val := range app.Evars {
-		fmt.Printf("  %s = %s\n", key, val)
+	keys := []string{}
+	for key := range report.Evars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("  %s = %s\n", key, report.Evars[key])
 	}
 
 	// print aliases
 	fmt.Printf("\nDNS Aliases\n")
-	entries := dns.List(app.ID)
 
-	if len(entries) == 0 {
+	if len(report.DNS) == 0 {
 		fmt.Printf("  none\n")
 	} else {
-		for _, entry := range entries {
-			fmt.Printf("  %s\n", entry.Domain)
+		for _, domain := range report.DNS {
+			fmt.Printf("  %s\n", domain)
 		}
 	}
 
@@ -77,3 +131,67 @@ func Info(env *models.Env, app *models.App) error {
 
 	return nil
 }
+
+// infoFor gathers the report of an environment
+func infoFor(env *models.Env, app *models.App) infoReport {
+	report := infoReport{
+		App:      env.Name,
+		Env:      app.DisplayName(),
+		Status:   app.Status,
+		Provider: util_provider.Status(),
+		Path:     env.Directory,
+		IP:       app.LocalIPs["env"],
+		URLs:     []string{},
+		DNS:      []string{},
+		Services: []infoService{},
+		Evars:    app.Evars,
+	}
+
+	for _, entry := range dns.List(app.ID) {
+		report.DNS = append(report.DNS, entry.Domain)
+		report.URLs = append(report.URLs, "http://"+entry.Domain)
+	}
+	if report.IP != "" {
+		report.URLs = append(report.URLs, "http://"+report.IP)
+	}
+
+	box := boxfile.New([]byte(app.DeployedBoxfile))
+
+	components, _ := app.Components()
+	for _, component := range components {
+		service := infoService{
+			Name:  component.Name,
+			Label: component.Label,
+			Type:  component.Type,
+			IP:    component.IPAddr(),
+			Ports: componentPorts(box, component),
+			URL:   component.ConnectionURL(),
+			Users: component.Plan.Users,
+		}
+		if service.Users == nil {
+			service.Users = []models.ComponentPlanUser{}
+		}
+
+		report.Services = append(report.Services, service)
+	}
+
+	return report
+}
+
+// componentPorts returns the ports a component listens on, the port of its
+// plan or the ports of its boxfile node, ie: 'tcp:2222:22'
+func componentPorts(box boxfile.Boxfile, component *models.Component) []string {
+	ports := []string{}
+
+	if component.Plan.Port != 0 {
+		ports = append(ports, fmt.Sprintf("%d", component.Plan.Port))
+	}
+
+	if values, ok := box.Node(component.Name).Value("ports").([]interface{}); ok {
+		for _, value := range values {
+			ports = append(ports, fmt.Sprintf("%v", value))
+		}
+	}
+
+	return ports
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/logs"
+)
+
+// Logs prints the output of the containers of the app's components and of
+// the hooks run in them, the ones they had before included, in order, with a
+// prefix of a color of its own for each component. A component can be named
+// by its full name, ie: data.db, or the name after its prefix, ie: db. The
+// lines are also shipped to the sinks of the log-forward config if forward
+// is set.
+func Logs(appModel *models.App, component string, filter logs.Filter, timestamps, forward bool) error {
+	// init docker client
+	if err := provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	sources, names, err := logSources(appModel, component, true)
+	if err != nil {
+		return err
+	}
+
+	if len(sources) == 0 {
+		if component != "" {
+			return util.Errorf("[USER] the app has no component named %s, use one of: %s", component, strings.Join(names, ", "))
+		}
+		return util.Errorf("[USER] the app has no components yet")
+	}
+
+	print := func(line logs.Line) {
+		display.FormatLogLine(line.Component, line.Stream, line.Text, line.Time, timestamps)
+	}
+	// a line of json per line of log, so it can be followed
+	if display.JSON() {
+		encoder := json.NewEncoder(os.Stdout)
+		print = func(line logs.Line) {
+			encoder.Encode(line)
+		}
+	}
+
+	// the lines are also shipped to the sinks of the log-forward config
+	if forward {
+		ship, stop, err := forwarder(appModel)
+		if err != nil {
+			return err
+		}
+		if ship == nil {
+			return util.Errorf("[USER] there are no sinks to forward to, set them with 'nanobox config set log-forward syslog://localhost:514'")
+		}
+		defer stop()
+
+		show := print
+		print = func(line logs.Line) {
+			show(line)
+			ship(line)
+		}
+	}
+
+	if err := logs.Aggregate(context.Background(), sources, filter, print); err != nil {
+		lumber.Error("app:Logs:logs.Aggregate(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to read the logs")
+	}
+
+	return nil
+}
+
+// logSources returns the sources of the logs of the app's components, or of
+// the component, and the names of all of them. The stored logs of the
+// containers they had before are included if stored is set.
+func logSources(appModel *models.App, component string, stored bool) ([]logs.Source, []string, error) {
+	componentModels, err := appModel.Components()
+	if err != nil {
+		lumber.Error("app:logSources:models.App{ID:%s}.Components(): %s", appModel.ID, err.Error())
+		return nil, nil, util.ErrorAppend(err, "unable to retrieve components")
+	}
+
+	store := logs.NewStore(appModel.ID)
+
+	sources := []logs.Source{}
+	names := []string{}
+	for _, componentModel := range componentModels {
+		if componentModel.ID == "" {
+			continue
+		}
+		names = append(names, componentModel.Name)
+
+		if component != "" && !componentMatches(componentModel.Name, component) {
+			continue
+		}
+
+		// the logs of the containers it had before this one are stored
+		if stored {
+			sources = append(sources, store.Source(componentModel.Name))
+		}
+
+		sources = append(sources,
+			logs.ContainerSource{Component: componentModel.Name, Container: componentModel.ID},
+			logs.HookSource{Component: componentModel.Name, Container: componentModel.ID},
+		)
+	}
+
+	return sources, names, nil
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/component"
+	"github.com/nanobox-io/nanobox/util"
+)
+
+// Plan prints what a deploy of the last build would change on the app,
+// without changing anything
+func Plan(envModel *models.Env, appModel *models.App) error {
+	if envModel.BuiltBoxfile == "" {
+		return util.Errorf("[USER] there is no build to plan a deploy for, run 'nanobox build-runtime' first")
+	}
+
+	box, err := component.Boxfile(envModel, appModel)
+	if err != nil {
+		return err
+	}
+
+	built := boxfile.New([]byte(box))
+	deployed := boxfile.New([]byte(appModel.DeployedBoxfile))
+
+	// the evars only reach the code when it is deployed
+	running := map[string]string{}
+
+	live, err := models.LiveDeployRecords(appModel.ID)
+	if err != nil {
+		lumber.Error("app:Plan:models.LiveDeployRecords(%s): %s", appModel.ID, err.Error())
+		return util.ErrorAppend(err, "failed to load the deploy history")
+	}
+
+	if len(live) > 0 && live[0].Evars != nil {
+		running = live[0].Evars
+	}
+
+	fmt.Printf("\nDeploy plan for %s:\n", appModel.DisplayName())
+	component.PrintPlan(os.Stdout, component.PlanComponents(built, deployed), component.PlanEvars(running, appModel.Evars))
+
+	return nil
+}
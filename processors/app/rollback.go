@@ -0,0 +1,131 @@
+package app
+
+import (
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/platform"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// Rollback redeploys the build the app ran before its current deploy. The
+// data components are left as they are.
+func Rollback(appModel *models.App) error {
+
+	// init docker client
+	if err := provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	live, err := models.LiveDeployRecords(appModel.ID)
+	if err != nil {
+		lumber.Error("app:Rollback:models.LiveDeployRecords(%s): %s", appModel.ID, err.Error())
+		return util.ErrorAppend(err, "failed to load the deploy history")
+	}
+
+	if len(live) < 2 {
+		return util.Errorf("[USER] there is no previous deploy to roll back to")
+	}
+
+	current, previous := live[0], live[1]
+
+	display.Info("Rolling back to deploy %d\n", previous.Number)
+
+	if err := redeploy(appModel, previous); err != nil {
+		return util.ErrorAppend(err, "failed to roll back")
+	}
+
+	// the previous deploy is the newest live deploy again, it was started
+	// with the current evars
+	current.Status = models.DeployRolledBack
+	if err := current.Save(); err != nil {
+		lumber.Error("app:Rollback:models.DeployRecord.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to save the deploy record")
+	}
+
+	previous.Evars = copyEvars(appModel.Evars)
+	if err := previous.Save(); err != nil {
+		lumber.Error("app:Rollback:models.DeployRecord.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to save the deploy record")
+	}
+
+	// give the user some helpful information
+	display.InfoSimDeploy(appModel.LocalIPs["env"])
+
+	// ship the logs to the log-forward sinks while they're shown
+	stopForward, err := StartForward(appModel)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to start forwarding the logs")
+	}
+	defer stopForward()
+
+	return platform.MistListen(appModel)
+}
+
+// redeploy releases the build of a previous deploy, it is still in the
+// hoarder of the app
+func redeploy(appModel *models.App, record *models.DeployRecord) error {
+	appModel.DeployedBoxfile = record.Boxfile
+	if err := appModel.Save(); err != nil {
+		lumber.Error("app:redeploy:models.App.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to save the app")
+	}
+
+	return release(appModel, simWarehouseConfig(appModel, record.BuildID), 0)
+}
+
+// startDeployRecord adds the deploy to the deploy history of the app
+func startDeployRecord(appModel *models.App, buildID, boxfile string) (*models.DeployRecord, error) {
+	records, err := models.AllDeployRecordsByApp(appModel.ID)
+	if err != nil {
+		lumber.Error("app:startDeployRecord:models.AllDeployRecordsByApp(%s): %s", appModel.ID, err.Error())
+		return nil, err
+	}
+
+	number := 1
+	if len(records) > 0 {
+		number = records[len(records)-1].Number + 1
+	}
+
+	record := &models.DeployRecord{
+		AppID:   appModel.ID,
+		Number:  number,
+		Status:  models.DeployRunning,
+		BuildID: buildID,
+		Boxfile: boxfile,
+		Canary:  Canary,
+		Evars:   copyEvars(appModel.Evars),
+		Started: time.Now(),
+	}
+
+	if err := record.Save(); err != nil {
+		lumber.Error("app:startDeployRecord:models.DeployRecord.Save(): %s", err.Error())
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// finishDeployRecord records the outcome of the deploy
+func finishDeployRecord(record *models.DeployRecord, status string) {
+	record.Status = status
+	record.Finished = time.Now()
+
+	if err := record.Save(); err != nil {
+		lumber.Error("app:finishDeployRecord:models.DeployRecord.Save(): %s", err.Error())
+	}
+}
+
+// copyEvars copies the evars so the record doesn't follow later changes
+func copyEvars(evars map[string]string) map[string]string {
+	copied := map[string]string{}
+	for key, val := range evars {
+		copied[key] = val
+	}
+
+	return copied
+}
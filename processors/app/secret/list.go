@@ -0,0 +1,34 @@
+package secret
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+)
+
+// List prints the keys of the app secrets, the values are never shown
+func List(appModel *models.App) error {
+	secrets, err := models.FindSecretsByApp(appModel.ID)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to load secrets")
+	}
+
+	keys := []string{}
+	for key := range secrets.Values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// print the header
+	fmt.Printf("\nSecrets\n")
+
+	for _, key := range keys {
+		fmt.Printf("  %s = ********\n", key)
+	}
+
+	fmt.Println()
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package secret
+
+import (
+	"fmt"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// Remove deletes secrets from the app
+func Remove(appModel *models.App, keys []string) error {
+	secrets, err := models.FindSecretsByApp(appModel.ID)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to load secrets")
+	}
+
+	for _, key := range keys {
+		delete(secrets.Values, key)
+	}
+
+	if err := secrets.Save(); err != nil {
+		return util.ErrorAppend(err, "failed to delete secrets")
+	}
+
+	fmt.Println()
+	for _, key := range keys {
+		fmt.Printf("%s %s removed\n", display.TaskComplete, key)
+	}
+	fmt.Println()
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package secret
+
+import (
+	"fmt"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/secret"
+)
+
+// Set encrypts the secrets and stores them with the app. They reach the
+// containers the next time they start.
+func Set(envModel *models.Env, appModel *models.App, values map[string]string) error {
+
+	if err := app.Setup(envModel, appModel, appModel.Name); err != nil {
+		return util.ErrorAppend(err, "failed to setup app")
+	}
+
+	secrets, err := models.FindSecretsByApp(appModel.ID)
+	if err != nil {
+		lumber.Error("secret:Set:models.FindSecretsByApp(%s): %s", appModel.ID, err.Error())
+		return util.ErrorAppend(err, "failed to load secrets")
+	}
+
+	for key, val := range values {
+		encrypted, err := secret.Encrypt(val)
+		if err != nil {
+			// never log the value
+			lumber.Error("secret:Set:secret.Encrypt(%s): %s", key, err.Error())
+			return util.ErrorAppend(err, "failed to encrypt %s", key)
+		}
+		secrets.Values[key] = encrypted
+	}
+
+	if err := secrets.Save(); err != nil {
+		return util.ErrorAppend(err, "failed to persist secrets")
+	}
+
+	fmt.Println()
+	for key := range values {
+		fmt.Printf("%s %s set\n", display.TaskComplete, key)
+	}
+	fmt.Println()
+
+	return nil
+}
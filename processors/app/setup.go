@@ -71,7 +71,8 @@ func reserveIPs(appModel *models.App) error {
 
 	}
 
-	if appModel.Name == "sim" {
+	// every dry-run environment runs its own platform services
+	if appModel.Name != "dev" {
 		if appModel.LocalIPs["logvac"] == "" {
 			// reserve a logvac ip
 			logvacIP, err := dhcp.ReserveLocal()
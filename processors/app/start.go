@@ -7,6 +7,7 @@ import (
 	"github.com/nanobox-io/nanobox/processors/component"
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/events"
 	"github.com/nanobox-io/nanobox/util/locker"
 )
 
@@ -51,5 +52,7 @@ func Start(envModel *models.Env, appModel *models.App, name string) error {
 		return util.ErrorAppend(err, "failed to persist app status")
 	}
 
+	events.Emit(events.New(events.AppUp, appModel))
+
 	return nil
 }
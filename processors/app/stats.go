@@ -0,0 +1,300 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/metrics"
+)
+
+// statsInterval is how often the stats table is redrawn
+const statsInterval = 2 * time.Second
+
+// statsWait is how long a snapshot waits for the first sample of every
+// component
+const statsWait = 5 * time.Second
+
+// componentStats holds the latest sample streamed for a component
+type componentStats struct {
+	sync.Mutex
+	samples map[string]*types.StatsJSON
+}
+
+// Stats prints the cpu, memory and network usage and the restarts of every
+// component of the app. With watch the table is redrawn until the user
+// interrupts it, with a metrics address they're served in prometheus'
+// format at /metrics.
+func Stats(appModel *models.App, watch bool, metricsAddr string) error {
+	// init docker client
+	if err := provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	if appModel.Status != "up" {
+		return util.Errorf("[USER] the app is not running")
+	}
+
+	componentModels, err := appModel.Components()
+	if err != nil {
+		lumber.Error("app:Stats:models.App{ID:%s}.Components(): %s", appModel.ID, err.Error())
+		return util.ErrorAppend(err, "unable to retrieve components")
+	}
+
+	stats := &componentStats{samples: map[string]*types.StatsJSON{}}
+
+	components := []*models.Component{}
+	for _, componentModel := range componentModels {
+		if componentModel.ID == "" {
+			continue
+		}
+		components = append(components, componentModel)
+		go streamStats(componentModel, stats)
+	}
+	sort.Sort(componentsByName(components))
+
+	snapshot := func() []metrics.Sample {
+		return statsSamples(appModel, components, stats)
+	}
+
+	if metricsAddr != "" {
+		if err := serveMetrics(metricsAddr, snapshot); err != nil {
+			return err
+		}
+	}
+
+	// a single table, once every component has a sample
+	if !watch && metricsAddr == "" {
+		waitForSamples(len(components), stats)
+
+		if display.JSON() {
+			return display.PrintJSON(snapshot())
+		}
+
+		printStats(snapshot(), false)
+		return nil
+	}
+
+	// catch kill signals
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt)
+	defer signal.Stop(sigChan)
+
+	for {
+		select {
+		case <-sigChan:
+			return nil
+		case <-time.After(statsInterval):
+		}
+
+		if watch {
+			printStats(snapshot(), true)
+		}
+	}
+}
+
+// streamStats decodes the docker stats stream of a component, storing the
+// latest sample
+func streamStats(componentModel *models.Component, stats *componentStats) {
+	rc, err := docker.Client.ContainerStats(context.Background(), componentModel.ID, true)
+	if err != nil {
+		lumber.Error("app:streamStats:docker.Client.ContainerStats(%s): %s", componentModel.ID, err.Error())
+		return
+	}
+	defer rc.Close()
+
+	decoder := json.NewDecoder(rc)
+	for {
+		sample := &types.StatsJSON{}
+		if err := decoder.Decode(sample); err != nil {
+			if err != io.EOF {
+				lumber.Error("app:streamStats:json.Decode(%s): %s", componentModel.ID, err.Error())
+			}
+			return
+		}
+
+		stats.Lock()
+		stats.samples[componentModel.Name] = sample
+		stats.Unlock()
+	}
+}
+
+// waitForSamples waits until there is a sample of every component, the
+// cpu usage is only known from the second one docker sends
+func waitForSamples(count int, stats *componentStats) {
+	deadline := time.Now().Add(statsWait)
+
+	for time.Now().Before(deadline) {
+		stats.Lock()
+		ready := len(stats.samples) == count
+		for _, sample := range stats.samples {
+			if sample.PreCPUStats.SystemUsage == 0 {
+				ready = false
+			}
+		}
+		stats.Unlock()
+
+		if ready {
+			return
+		}
+
+		<-time.After(100 * time.Millisecond)
+	}
+}
+
+// statsSamples returns the latest sample of every component, with the times
+// its container was restarted
+func statsSamples(appModel *models.App, components []*models.Component, stats *componentStats) []metrics.Sample {
+	name := appModel.DisplayName()
+	if envModel, err := appModel.Env(); err == nil {
+		name = envModel.Name
+	}
+
+	samples := []metrics.Sample{}
+	for _, componentModel := range components {
+		sample := metrics.Sample{App: name, Component: componentModel.Name}
+
+		if container, err := docker.GetContainer(componentModel.ID); err == nil {
+			sample.Up = container.State.Running
+			sample.Restarts = container.RestartCount
+		}
+
+		stats.Lock()
+		if latest, ok := stats.samples[componentModel.Name]; ok {
+			sample.CPUPercent = cpuPercent(latest)
+			sample.CPUSeconds = float64(latest.CPUStats.CPUUsage.TotalUsage) / float64(time.Second)
+			sample.Memory = latest.MemoryStats.Usage
+			sample.MemLimit = latest.MemoryStats.Limit
+			sample.NetRx, sample.NetTx = networkIO(latest)
+		}
+		stats.Unlock()
+
+		samples = append(samples, sample)
+	}
+
+	return samples
+}
+
+// serveMetrics serves the samples in prometheus' format at /metrics
+func serveMetrics(addr string, snapshot func() []metrics.Sample) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return util.Errorf("[USER] failed to listen on %s: %s", addr, err.Error())
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := metrics.Write(w, snapshot()); err != nil {
+			lumber.Error("app:serveMetrics:metrics.Write(): %s", err.Error())
+		}
+	})
+
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			lumber.Error("app:serveMetrics:http.Serve(%s): %s", addr, err.Error())
+		}
+	}()
+
+	display.Info("Serving the metrics at http://%s/metrics, ctrl + c to quit\n", listener.Addr().String())
+
+	return nil
+}
+
+// printStats prints a table of the samples
+func printStats(samples []metrics.Sample, redraw bool) {
+	// clear the screen and move the cursor to the top, without a terminal
+	// the tables follow each other
+	if redraw && display.Interactive {
+		fmt.Print("\033[2J\033[H")
+	} else {
+		fmt.Println()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "COMPONENT\tCPU %\tMEM USAGE / LIMIT\tMEM %\tNET I/O\tRESTARTS")
+
+	for _, sample := range samples {
+		if sample.MemLimit == 0 {
+			fmt.Fprintf(w, "%s\t--\t--\t--\t--\t%d\n", sample.Component, sample.Restarts)
+			continue
+		}
+
+		fmt.Fprintf(w, "%s\t%.2f%%\t%s / %s\t%.2f%%\t%s / %s\t%d\n",
+			sample.Component,
+			sample.CPUPercent,
+			byteSize(sample.Memory),
+			byteSize(sample.MemLimit),
+			float64(sample.Memory)/float64(sample.MemLimit)*100.0,
+			byteSize(sample.NetRx),
+			byteSize(sample.NetTx),
+			sample.Restarts,
+		)
+	}
+
+	w.Flush()
+
+	if !redraw {
+		fmt.Println()
+	}
+}
+
+// cpuPercent calculates the cpu usage between the sample and the previous one
+func cpuPercent(sample *types.StatsJSON) float64 {
+	cpuDelta := float64(sample.CPUStats.CPUUsage.TotalUsage) - float64(sample.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(sample.CPUStats.SystemUsage) - float64(sample.PreCPUStats.SystemUsage)
+
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+
+	return cpuDelta / systemDelta * float64(len(sample.CPUStats.CPUUsage.PercpuUsage)) * 100.0
+}
+
+// networkIO sums the received and transmitted bytes of every interface
+func networkIO(sample *types.StatsJSON) (rx, tx uint64) {
+	for _, network := range sample.Networks {
+		rx += network.RxBytes
+		tx += network.TxBytes
+	}
+
+	return
+}
+
+// byteSize formats a number of bytes for display
+func byteSize(b uint64) string {
+	switch {
+	case b >= 1<<30:
+		return fmt.Sprintf("%.2fGiB", float64(b)/(1<<30))
+	case b >= 1<<20:
+		return fmt.Sprintf("%.2fMiB", float64(b)/(1<<20))
+	case b >= 1<<10:
+		return fmt.Sprintf("%.2fKiB", float64(b)/(1<<10))
+	}
+
+	return fmt.Sprintf("%dB", b)
+}
+
+// componentsByName sorts components by name
+type componentsByName []*models.Component
+
+func (c componentsByName) Len() int           { return len(c) }
+func (c componentsByName) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c componentsByName) Less(i, j int) bool { return c[i].Name < c[j].Name }
@@ -13,6 +13,7 @@ import (
 	"github.com/nanobox-io/nanobox/util"
 	// "github.com/nanobox-io/nanobox/util/dhcp"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/events"
 	"github.com/nanobox-io/nanobox/util/locker"
 )
 
@@ -23,7 +24,7 @@ func Stop(appModel *models.App) error {
 
 	// short-circuit if the app is already down
 	// TODO: also check if any containers are running
-	if appModel.Status != "up" {
+	if appModel.Status != "up" && appModel.Status != models.StatusSuspended {
 		return nil
 	}
 
@@ -60,6 +61,8 @@ func Stop(appModel *models.App) error {
 		return util.ErrorAppend(err, "failed to persist app status")
 	}
 
+	events.Emit(events.New(events.AppDown, appModel))
+
 	return nil
 }
 
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/component"
+	process_provider "github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/events"
+	"github.com/nanobox-io/nanobox/util/locker"
+)
+
+// Suspend pauses the components of an app instead of stopping them. Their
+// IPs, NAT rules, evars and memory stay as they are, so Start brings the app
+// back in the time it takes to unpause them.
+func Suspend(appModel *models.App) error {
+	locker.LocalLock()
+	defer locker.LocalUnlock()
+
+	// short-circuit if the app isn't running
+	if appModel.Status != models.StatusUp {
+		return nil
+	}
+
+	// load the env for the display context
+	envModel, err := appModel.Env()
+	if err != nil {
+		lumber.Error("app:Suspend:models.App.Env()")
+		return util.ErrorAppend(err, "failed to load app env")
+	}
+
+	display.OpenContext("%s (%s)", envModel.Name, appModel.DisplayName())
+	defer display.CloseContext()
+
+	// initialize docker for the provider
+	if err := process_provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to initialize docker environment")
+	}
+
+	if err := component.PauseAll(appModel); err != nil {
+		return util.ErrorAppend(err, "failed to pause the app components")
+	}
+
+	// the console is opened again by the next 'nanobox run'
+	stopDevContainer(appModel)
+
+	appModel.Status = models.StatusSuspended
+	if err := appModel.Save(); err != nil {
+		lumber.Error("app:Suspend:models.App.Save()")
+		return util.ErrorAppend(err, "failed to persist app status")
+	}
+
+	events.Emit(events.New(events.AppDown, appModel))
+
+	return nil
+}
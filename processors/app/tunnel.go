@@ -0,0 +1,186 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// the port a service listens on when its plan doesn't say, by image
+var servicePorts = map[string]int{
+	"postgresql":    5432,
+	"mysql":         3306,
+	"mariadb":       3306,
+	"percona":       3306,
+	"redis":         6379,
+	"mongodb":       27017,
+	"memcached":     11211,
+	"elasticsearch": 9200,
+	"rabbitmq":      5672,
+	"couchbase":     8091,
+}
+
+// Tunnel forwards a port on the host to a component of a local app, so
+// clients on the host can connect to it. The tunnel is open until ctrl + c.
+// Without a listen port the port of the team config is used, then the
+// component's port, or a free one.
+func Tunnel(appModel *models.App, name string, listenPort, destPort int) error {
+	componentModel, err := FindComponent(appModel, name)
+	if err != nil {
+		return err
+	}
+
+	if listenPort == 0 {
+		if envModel, err := appModel.Env(); err == nil {
+			listenPort = envModel.TunnelPorts[componentModel.Name]
+		}
+	}
+
+	if destPort == 0 {
+		destPort = componentPort(componentModel)
+	}
+	if destPort == 0 {
+		return util.Errorf("[USER] the port of %s isn't known, specify it with --port <local>:<port>", componentModel.Name)
+	}
+
+	listener, err := tunnelListen(listenPort, destPort)
+	if err != nil {
+		return util.Errorf("[USER] failed to listen on port %d: %s", listenPort, err.Error())
+	}
+	defer listener.Close()
+
+	dest := net.JoinHostPort(componentModel.IPAddr(), strconv.Itoa(destPort))
+	port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+
+	user, pass := componentCredentials(componentModel)
+	display.LocalTunnelEstablished(componentModel.Name, port, user, pass, tunnelConnect(componentModel, port, user, pass))
+
+	go func() {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt)
+		<-sigChan
+		signal.Stop(sigChan)
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			// closed on ctrl + c
+			return nil
+		}
+
+		go tunnelConn(conn, dest)
+	}
+}
+
+// tunnelListen listens on the port on the loopback. Without one the
+// component's port is tried first, then any free port.
+func tunnelListen(listenPort, destPort int) (net.Listener, error) {
+	if listenPort != 0 {
+		return net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", listenPort))
+	}
+
+	if listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", destPort)); err == nil {
+		return listener, nil
+	}
+
+	return net.Listen("tcp", "127.0.0.1:0")
+}
+
+// tunnelConn copies a connection to the component and back
+func tunnelConn(conn net.Conn, dest string) {
+	defer conn.Close()
+
+	remote, err := net.Dial("tcp", dest)
+	if err != nil {
+		lumber.Error("app:tunnelConn:net.Dial(%s): %s", dest, err.Error())
+		fmt.Fprintf(os.Stderr, "failed to connect to %s: %s\n", dest, err.Error())
+		return
+	}
+	defer remote.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(remote, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(conn, remote)
+		done <- struct{}{}
+	}()
+
+	// either side hanging up ends the connection
+	<-done
+}
+
+// componentPort returns the port the component listens on, from its plan or
+// its image, or 0 if it isn't known
+func componentPort(componentModel *models.Component) int {
+	if componentModel.Plan.Port != 0 {
+		return componentModel.Plan.Port
+	}
+
+	// ie: nanobox/postgresql:9.5
+	image := componentModel.Image
+	if i := strings.LastIndex(image, "/"); i >= 0 {
+		image = image[i+1:]
+	}
+	if i := strings.Index(image, ":"); i >= 0 {
+		image = image[:i]
+	}
+
+	return servicePorts[image]
+}
+
+// componentCredentials returns the default user of the component
+func componentCredentials(componentModel *models.Component) (string, string) {
+	for _, user := range componentModel.Plan.Users {
+		if user.Username == componentModel.Plan.DefaultUser {
+			return user.Username, user.Password
+		}
+	}
+
+	return "", ""
+}
+
+// tunnelConnect returns ready to paste ways to connect through the tunnel
+func tunnelConnect(componentModel *models.Component, port, user, pass string) []string {
+	connect := []string{}
+
+	scheme := componentModel.Plan.Scheme
+	if scheme != "" {
+		connURL := url.URL{Scheme: scheme, Host: "127.0.0.1:" + port}
+		if componentModel.Plan.Database != "" {
+			connURL.Path = "/" + componentModel.Plan.Database
+		}
+		if user != "" {
+			connURL.User = url.UserPassword(user, pass)
+		}
+		connect = append(connect, connURL.String())
+	}
+
+	switch {
+	case strings.HasPrefix(scheme, "postgres"):
+		connect = append(connect, strings.TrimSpace(fmt.Sprintf("psql -h 127.0.0.1 -p %s -U %s %s", port, user, componentModel.Plan.Database)))
+	case scheme == "mysql":
+		connect = append(connect, strings.TrimSpace(fmt.Sprintf("mysql -h 127.0.0.1 -P %s -u %s -p %s", port, user, componentModel.Plan.Database)))
+	case scheme == "redis":
+		connect = append(connect, fmt.Sprintf("redis-cli -h 127.0.0.1 -p %s", port))
+	case scheme == "mongodb":
+		connect = append(connect, fmt.Sprintf("mongo --host 127.0.0.1 --port %s", port))
+	}
+
+	return connect
+}
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/component"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// archiveContents is the unpacked content of an app archive
+type archiveContents struct {
+	app        models.App
+	components []*models.Component
+	dataDir    string // temp dir holding the component data tarballs
+}
+
+// Unarchive restores an archived app, recreating its components and loading
+// the archived data back into them
+func Unarchive(envModel *models.Env, archiveModel *models.Archive) error {
+	// init docker client
+	if err := provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	display.OpenContext("Restoring %s (%s)", envModel.Name, archiveModel.AppName)
+	defer display.CloseContext()
+
+	contents, err := readArchive(archiveModel.Path)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to read the archive")
+	}
+	defer os.RemoveAll(contents.dataDir)
+
+	// setup a fresh app, this reserves new IPs
+	appModel, _ := models.FindAppBySlug(envModel.ID, archiveModel.AppName)
+	if !appModel.IsNew() {
+		return util.Errorf("[USER] the app has been recreated since it was archived, destroy it before unarchiving")
+	}
+
+	if err := Setup(envModel, appModel, archiveModel.AppName); err != nil {
+		return util.ErrorAppend(err, "failed to setup the app")
+	}
+
+	// bring back the archived evars and boxfile
+	for key, val := range contents.app.Evars {
+		appModel.Evars[key] = val
+	}
+	appModel.ComponentEvars = contents.app.ComponentEvars
+	appModel.DeployedBoxfile = contents.app.DeployedBoxfile
+	appModel.Key = contents.app.Key
+	appModel.Cert = contents.app.Cert
+	if err := appModel.Save(); err != nil {
+		lumber.Error("app:Unarchive:models.App.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to persist app")
+	}
+
+	// provision each of the archived components and load its data
+	for _, archived := range contents.components {
+		componentModel := &models.Component{
+			Name:          archived.Name,
+			Label:         archived.Label,
+			Image:         archived.Image,
+			RestartPolicy: archived.RestartPolicy,
+			Evars:         archived.Evars,
+		}
+
+		if err := component.Setup(appModel, componentModel); err != nil {
+			return util.ErrorAppend(err, "failed to setup component (%s)", archived.Name)
+		}
+
+		if err := restoreComponent(appModel, componentModel, archived, contents.dataDir); err != nil {
+			return util.ErrorAppend(err, "failed to restore component (%s)", archived.Name)
+		}
+	}
+
+	appModel.Status = "up"
+	if err := appModel.Save(); err != nil {
+		lumber.Error("app:Unarchive:models.App.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to persist app status")
+	}
+
+	// the archive is no longer needed
+	if err := os.Remove(archiveModel.Path); err != nil {
+		lumber.Error("app:Unarchive:os.Remove(%s): %s", archiveModel.Path, err.Error())
+	}
+
+	if err := archiveModel.Delete(); err != nil {
+		return util.ErrorAppend(err, "failed to remove the archive record")
+	}
+
+	return nil
+}
+
+// restoreComponent feeds the archived data tarball into the component
+func restoreComponent(appModel *models.App, componentModel, archived *models.Component, dataDir string) error {
+	data, err := os.Open(filepath.Join(dataDir, archived.Name+".tar"))
+	if err != nil {
+		// the component had no data when it was archived
+		return component.Restore(appModel, componentModel, archived, nil)
+	}
+	defer data.Close()
+
+	return component.Restore(appModel, componentModel, archived, data)
+}
+
+// readArchive unpacks the archive, decoding the models and extracting the
+// component data into a temp dir
+func readArchive(path string) (contents *archiveContents, err error) {
+	display.StartTask("Unpacking app state")
+	defer display.StopTask()
+
+	file, err := os.Open(path)
+	if err != nil {
+		display.ErrorTask()
+		return nil, util.ErrorAppend(err, "failed to open archive")
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		display.ErrorTask()
+		return nil, util.ErrorAppend(err, "failed to decompress archive")
+	}
+	defer gz.Close()
+
+	dataDir, err := ioutil.TempDir("", "nanobox-unarchive")
+	if err != nil {
+		display.ErrorTask()
+		return nil, util.ErrorAppend(err, "failed to create temp dir")
+	}
+	// on success the caller removes the dir once the data is restored
+	defer func() {
+		if err != nil {
+			os.RemoveAll(dataDir)
+		}
+	}()
+
+	contents = &archiveContents{dataDir: dataDir}
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			display.ErrorTask()
+			return nil, util.ErrorAppend(err, "failed to read archive")
+		}
+
+		switch {
+		case header.Name == "app.json":
+			err = json.NewDecoder(tr).Decode(&contents.app)
+		case header.Name == "components.json":
+			err = json.NewDecoder(tr).Decode(&contents.components)
+		case strings.HasPrefix(header.Name, "data/"):
+			err = extractArchiveFile(tr, filepath.Join(dataDir, filepath.Base(header.Name)))
+		}
+
+		if err != nil {
+			display.ErrorTask()
+			return nil, util.ErrorAppend(err, "failed to unpack %s", header.Name)
+		}
+	}
+
+	return contents, nil
+}
+
+// extractArchiveFile writes the current archive entry to dest
+func extractArchiveFile(r io.Reader, dest string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, r)
+	return err
+}
@@ -0,0 +1,201 @@
+package processors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/engine-api/types"
+	"github.com/docker/go-units"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/env"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	util_provider "github.com/nanobox-io/nanobox/util/provider"
+)
+
+// appRow is an environment of an app in the list of apps
+type appRow struct {
+	App      string `json:"app"`
+	Env      string `json:"env"`
+	Status   string `json:"status"`
+	Services int    `json:"services"`
+	Disk     int64  `json:"disk"` // bytes, only known while the vm runs
+	IP       string `json:"ip"`
+	Path     string `json:"path"`
+
+	id string
+}
+
+// Apps prints every app nanobox knows about with the status, the number of
+// services, the disk used and the IP of each of its environments
+func Apps() error {
+	envs, err := models.AllEnvs()
+	if err != nil {
+		lumber.Error("apps:models.AllEnvs(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to load the apps")
+	}
+
+	if len(envs) == 0 && !display.JSON() {
+		fmt.Println("No apps yet. Use 'nanobox run' in a project to create one.")
+		return nil
+	}
+
+	// the disk usage lives in docker, which is only there while the vm runs
+	sizes := map[string]int64{}
+	if util_provider.IsReady() && provider.Init() == nil {
+		sizes = containerSizes()
+	}
+
+	rows := []appRow{}
+	for _, envModel := range envs {
+		apps, _ := envModel.Apps()
+		for _, appModel := range apps {
+			row := appRow{
+				App:    envModel.Name,
+				Env:    appModel.DisplayName(),
+				Status: appStatus(appModel),
+				IP:     appModel.LocalIPs["env"],
+				Path:   envModel.Directory,
+				id:     appModel.ID,
+			}
+
+			components, _ := appModel.Components()
+			for _, component := range components {
+				if component.Type == "data" {
+					row.Services++
+				}
+			}
+
+			if size, ok := sizes[appModel.ID]; ok {
+				row.Disk = size
+			}
+
+			rows = append(rows, row)
+		}
+	}
+
+	if display.JSON() {
+		return display.PrintJSON(rows)
+	}
+
+	fmt.Printf("\n%-20s %-18s %-8s %-8s %-10s %-15s %s\n", "App", "Env", "Status", "Services", "Disk", "IP", "Path")
+	fmt.Println(strings.Repeat("-", 100))
+
+	for _, row := range rows {
+		disk := "-"
+		if _, ok := sizes[row.id]; ok {
+			disk = units.HumanSize(float64(row.Disk))
+		}
+
+		ip := row.IP
+		if ip == "" {
+			ip = "-"
+		}
+
+		fmt.Printf("%-20s %-18s %-8s %-8d %-10s %-15s %s\n",
+			row.App, row.Env, row.Status, row.Services, disk, ip, row.Path)
+	}
+
+	fmt.Println()
+
+	return nil
+}
+
+// RenameApp changes the name an app is shown with
+func RenameApp(name, newName string) error {
+	envModel, err := findEnvByName(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := findEnvByName(newName); err == nil {
+		return util.Errorf("[USER] there already is an app named %s", newName)
+	}
+
+	envModel.Name = newName
+	if err := envModel.Save(); err != nil {
+		lumber.Error("apps:RenameApp:models.Env.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to save the app")
+	}
+
+	fmt.Printf("\n%s renamed to %s\n\n", name, newName)
+
+	return nil
+}
+
+// RemoveApp destroys an app and everything nanobox keeps for it, the code
+// in its directory is left alone
+func RemoveApp(name string) error {
+	envModel, err := findEnvByName(name)
+	if err != nil {
+		return err
+	}
+
+	return env.Destroy(envModel)
+}
+
+// findEnvByName finds an app by its name or its directory
+func findEnvByName(name string) (*models.Env, error) {
+	envs, err := models.AllEnvs()
+	if err != nil {
+		lumber.Error("apps:findEnvByName:models.AllEnvs(): %s", err.Error())
+		return nil, util.ErrorAppend(err, "failed to load the apps")
+	}
+
+	found := []*models.Env{}
+	for _, envModel := range envs {
+		if envModel.Name == name || envModel.Directory == name {
+			found = append(found, envModel)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, util.Errorf("[USER] there is no app named %s, see 'nanobox apps'", name)
+	case 1:
+		return found[0], nil
+	}
+
+	return nil, util.Errorf("[USER] more than one app is named %s, use the path of the app instead", name)
+}
+
+// appStatus returns running or stopped for an app
+func appStatus(appModel *models.App) string {
+	if appModel.Status == "up" {
+		return "running"
+	}
+
+	return "stopped"
+}
+
+// containerSizes returns the disk used by the containers of each app, by
+// app id. The containers of an app are named nanobox_<app id>_<component>.
+func containerSizes() map[string]int64 {
+	sizes := map[string]int64{}
+
+	containers, err := docker.Client.ContainerList(context.Background(), types.ContainerListOptions{All: true, Size: true})
+	if err != nil {
+		lumber.Error("apps:containerSizes:docker.Client.ContainerList(): %s", err.Error())
+		return sizes
+	}
+
+	apps, _ := models.AllApps()
+
+	for _, container := range containers {
+		for _, name := range container.Names {
+			name = strings.TrimPrefix(name, "/")
+			for _, appModel := range apps {
+				if name == "nanobox_"+appModel.ID || strings.HasPrefix(name, "nanobox_"+appModel.ID+"_") {
+					sizes[appModel.ID] += container.SizeRw
+				}
+			}
+		}
+	}
+
+	return sizes
+}
@@ -1,16 +1,41 @@
 package processors
 
 import (
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jcelliott/lumber"
+
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/processors/code"
 	"github.com/nanobox-io/nanobox/processors/env"
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/events"
 	"github.com/nanobox-io/nanobox/util/locker"
 )
 
+// BuildTimeout cancels a build that runs longer, 0 means no limit
+var BuildTimeout time.Duration
+
+// ForceBuild stops a build that is already running for the app, rather than
+// refusing to build
+var ForceBuild bool
+
 // Build sets up the environment and runs a code build
 func Build(envModel *models.Env) error {
+	// make sure no other build is running for this app
+	if err := claimBuild(envModel, ""); err != nil {
+		return err
+	}
+	defer releaseBuild(envModel)
+
+	// make room for the images and the build before anything is pulled
+	if err := ensureDiskSpace(); err != nil {
+		return err
+	}
+
 	// by aquiring a local lock we are only allowing
 	// one build to happen at a time
 	locker.LocalLock()
@@ -27,9 +52,149 @@ func Build(envModel *models.Env) error {
 	}
 
 	// build code
-	if err := code.Build(envModel); err != nil {
+	if err := runBuild(envModel, "", func() error { return code.Build(envModel) }); err != nil {
 		return util.ErrorAppend(err, "failed to build the code")
 	}
 
 	return nil
 }
+
+// claimBuild records the build as running. A second build for the same app
+// is refused, or stops the running one if it's forced.
+func claimBuild(envModel *models.Env, remote string) error {
+	build := &models.Build{
+		EnvID:   envModel.ID,
+		PID:     os.Getpid(),
+		Remote:  remote,
+		Started: time.Now(),
+	}
+
+	// the running build is only stopped once the claim is saved, it finds
+	// the record taken over when it exits and leaves it alone
+	running := 0
+	err := build.Claim(func(active *models.Build) error {
+		if active.PID == os.Getpid() || !util.ProcessAlive(active.PID) {
+			return nil
+		}
+
+		if !ForceBuild {
+			return util.Errorf("[USER] a build of this app is already running (pid %d, started %s ago), wait for it to finish or use --force to stop it",
+				active.PID, time.Since(active.Started)/time.Second*time.Second)
+		}
+
+		running = active.PID
+		return nil
+	})
+	if _, ok := err.(util.Err); ok {
+		return err
+	}
+	if err != nil {
+		lumber.Error("processors:claimBuild:models.Build.Claim(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to record the build")
+	}
+
+	if running != 0 {
+		display.Warn("Stopping the running build (pid %d)\n", running)
+		if err := util.StopProcess(running); err != nil {
+			lumber.Error("processors:claimBuild:util.StopProcess(%d): %s", running, err.Error())
+			return util.ErrorAppend(err, "failed to stop the running build")
+		}
+	}
+
+	return nil
+}
+
+// releaseBuild removes the running build record, unless another build has
+// taken over in the meantime
+func releaseBuild(envModel *models.Env) {
+	active, err := models.FindBuildByEnv(envModel.ID)
+	if err != nil || active.PID != os.Getpid() {
+		return
+	}
+
+	if err := active.Delete(); err != nil {
+		lumber.Error("processors:releaseBuild:models.Build.Delete(): %s", err.Error())
+	}
+}
+
+// runBuild runs the build, canceling it on ctrl + c or when the timeout is
+// reached. The outcome is recorded on the env and in the build history.
+func runBuild(envModel *models.Env, remote string, build func() error) error {
+	event := events.Event{Type: events.BuildStarted, App: envModel.Name}
+	events.Emit(event)
+
+	envModel.BuildStatus = models.BuildRunning
+	if err := envModel.Save(); err != nil {
+		lumber.Error("processors:runBuild:models.Env.Save(): %s", err.Error())
+	}
+
+	// keep the build in the history, the build goes on without it if it fails
+	record, logFile, err := startBuildRecord(envModel, remote)
+	if err != nil {
+		lumber.Error("processors:runBuild:startBuildRecord(): %s", err.Error())
+	} else {
+		defer finishBuildRecord(envModel, record, logFile)
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt)
+	defer signal.Stop(sigChan)
+
+	var timeout <-chan time.Time
+	if BuildTimeout > 0 {
+		timer := time.NewTimer(BuildTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan string, 1)
+	exited := make(chan struct{})
+
+	// cancel the build if the user bails out or it takes too long
+	go func() {
+		defer close(exited)
+
+		select {
+		case <-sigChan:
+			stopped <- models.BuildCanceled
+		case <-timeout:
+			stopped <- models.BuildTimedOut
+		case <-done:
+			return
+		}
+
+		code.Cancel()
+	}()
+
+	err = build()
+
+	close(done)
+	<-exited
+
+	select {
+	case status := <-stopped:
+		envModel.BuildStatus = status
+		err = util.Errorf("[CANCELED] the build was %s", status)
+		if status == models.BuildTimedOut {
+			err = util.Errorf("[BUILD] the build was canceled after running for %s", BuildTimeout)
+		}
+	default:
+		envModel.BuildStatus = models.BuildComplete
+		if err != nil {
+			envModel.BuildStatus = models.BuildFailed
+		}
+	}
+
+	if err := envModel.Save(); err != nil {
+		lumber.Error("processors:runBuild:models.Env.Save(): %s", err.Error())
+	}
+
+	event.Type = events.BuildFinished
+	if err != nil {
+		event.Type, event.Error = events.BuildFailed, err.Error()
+	}
+	events.Emit(event)
+
+	return err
+}
@@ -0,0 +1,173 @@
+package processors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/code"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// startBuildRecord adds the build to the build history and captures its
+// output into the build log
+func startBuildRecord(envModel *models.Env, remote string) (*models.BuildRecord, *os.File, error) {
+	records, err := models.AllBuildRecordsByEnv(envModel.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	number := 1
+	if len(records) > 0 {
+		number = records[len(records)-1].Number + 1
+	}
+
+	dir := filepath.ToSlash(filepath.Join(config.GlobalDir(), "builds", envModel.ID))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, err
+	}
+
+	record := &models.BuildRecord{
+		EnvID:   envModel.ID,
+		Number:  number,
+		Status:  models.BuildRunning,
+		Remote:  remote,
+		LogPath: filepath.ToSlash(filepath.Join(dir, fmt.Sprintf("%d.log", number))),
+		Started: time.Now(),
+	}
+
+	logFile, err := os.Create(record.LogPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := record.Save(); err != nil {
+		logFile.Close()
+		return nil, nil, err
+	}
+
+	display.Capture = logFile
+
+	return record, logFile, nil
+}
+
+// finishBuildRecord records the outcome of the build and drops the builds
+// that no longer fit in the history
+func finishBuildRecord(envModel *models.Env, record *models.BuildRecord, logFile *os.File) {
+	display.Capture = nil
+	logFile.Close()
+
+	record.Status = envModel.BuildStatus
+	record.BuiltID = envModel.BuiltID
+	if code.Reproducible {
+		record.Fingerprint = envModel.BuildFingerprint
+	}
+	record.Finished = time.Now()
+
+	if err := record.Save(); err != nil {
+		lumber.Error("processors:finishBuildRecord:models.BuildRecord.Save(): %s", err.Error())
+	}
+
+	pruneBuildHistory(envModel)
+}
+
+// pruneBuildHistory removes the oldest builds beyond the retention
+func pruneBuildHistory(envModel *models.Env) {
+	configModel, _ := models.LoadConfig()
+
+	records, err := models.AllBuildRecordsByEnv(envModel.ID)
+	if err != nil {
+		lumber.Error("processors:pruneBuildHistory:models.AllBuildRecordsByEnv(%s): %s", envModel.ID, err.Error())
+		return
+	}
+
+	for i := 0; i < len(records)-configModel.BuildHistory; i++ {
+		os.Remove(records[i].LogPath)
+		if err := records[i].Delete(); err != nil {
+			lumber.Error("processors:pruneBuildHistory:models.BuildRecord.Delete(): %s", err.Error())
+		}
+	}
+}
+
+// BuildList prints the build history of the env
+func BuildList(envModel *models.Env) error {
+	records, err := models.AllBuildRecordsByEnv(envModel.ID)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to load the build history")
+	}
+
+	if display.JSON() {
+		return display.PrintJSON(buildResults(records))
+	}
+
+	if len(records) == 0 {
+		fmt.Println("No builds yet. Use 'nanobox build' to build your app.")
+		return nil
+	}
+
+	fmt.Println()
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+
+		fmt.Printf("  #%-4d %-10s %s  %s", record.Number, record.Status, record.Started.Format("Jan 02 15:04:05"), record.Duration()/time.Second*time.Second)
+		if record.Remote != "" {
+			fmt.Printf("  on %s", record.Remote)
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// buildResults returns the builds, newest first, for scripts
+func buildResults(records []*models.BuildRecord) []map[string]interface{} {
+	results := []map[string]interface{}{}
+
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		results = append(results, map[string]interface{}{
+			"number":   record.Number,
+			"status":   record.Status,
+			"id":       record.BuiltID,
+			"remote":   record.Remote,
+			"started":  record.Started,
+			"duration": record.Duration().Seconds(),
+		})
+	}
+
+	return results
+}
+
+// BuildLogs writes the log of a build to w
+func BuildLogs(envModel *models.Env, number string, w io.Writer) error {
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return util.Errorf("[USER] '%s' is not a build number, see 'nanobox build ls'", number)
+	}
+
+	record, err := models.FindBuildRecord(envModel.ID, n)
+	if err != nil {
+		return util.Errorf("[USER] there is no build #%d, see 'nanobox build ls'", n)
+	}
+
+	logFile, err := os.Open(record.LogPath)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to open the log of build #%d", n)
+	}
+	defer logFile.Close()
+
+	if _, err := io.Copy(w, logFile); err != nil {
+		return util.ErrorAppend(err, "failed to read the log of build #%d", n)
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package processors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/commands/registry"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/processors/code"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// CIConfig is what nanobox ci runs after the build
+type CIConfig struct {
+	// fail on the lint warnings of the boxfile too
+	Strict bool
+	// skip the test stage
+	SkipTests bool
+	// where to export the compiled app, nothing is exported if it's empty
+	Export string
+	// dry-run or a remote alias to deploy to, nothing is deployed if it's
+	// empty
+	Deploy string
+	// where to write the json summary, besides the output with --json
+	Summary string
+	// where the build cache is restored from and saved to, so a ci provider
+	// can keep it between runs
+	CacheDir string
+}
+
+// ciStage is the outcome of a stage, as the summary shows it
+type ciStage struct {
+	Name     string  `json:"name"`
+	Status   string  `json:"status"`
+	Duration float64 `json:"duration"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// ciSummary is the machine readable outcome of a ci run
+type ciSummary struct {
+	Status   string    `json:"status"`
+	ExitCode int       `json:"exit_code"`
+	Build    string    `json:"build,omitempty"`
+	Export   string    `json:"export,omitempty"`
+	Stages   []ciStage `json:"stages"`
+}
+
+// CI validates the boxfile, builds and compiles the app, runs the tests,
+// then optionally exports and deploys it. The first stage to fail stops the
+// run, and its exit code is nanobox's.
+func CI(envModel *models.Env, ciConfig CIConfig) error {
+	summary := &ciSummary{Stages: []ciStage{}}
+
+	// every stage updates the env, the next one loads it again like the
+	// commands do
+	current := func() *models.Env {
+		if reloaded, err := models.FindEnvByID(envModel.ID); err == nil {
+			return reloaded
+		}
+		return envModel
+	}
+
+	stages := []struct {
+		name string
+		skip bool
+		run  func() error
+	}{
+		{"validate", false, func() error {
+			if ciConfig.Strict {
+				return Lint(true)
+			}
+			return Validate()
+		}},
+		{"build", false, func() error {
+			if err := Start(); err != nil {
+				return err
+			}
+			if ciConfig.CacheDir != "" {
+				if err := code.ImportCache(ciConfig.CacheDir); err != nil {
+					return err
+				}
+			}
+			return Build(current())
+		}},
+		{"compile", false, func() error {
+			if err := Compile(current()); err != nil {
+				return err
+			}
+			// the compile fills the package caches too
+			if ciConfig.CacheDir != "" {
+				return code.ExportCache(ciConfig.CacheDir)
+			}
+			return nil
+		}},
+		{"test", ciConfig.SkipTests, func() error {
+			return ciTest(current())
+		}},
+		{"export", ciConfig.Export == "", func() error {
+			return Export(current(), ciConfig.Export)
+		}},
+		{"deploy", ciConfig.Deploy == "", func() error {
+			return ciDeploy(current(), ciConfig.Deploy)
+		}},
+	}
+
+	var failure error
+	for _, stage := range stages {
+		if stage.skip || failure != nil {
+			summary.Stages = append(summary.Stages, ciStage{Name: stage.name, Status: "skipped"})
+			continue
+		}
+
+		display.Info("\n--- %s\n", stage.name)
+
+		start := time.Now()
+		err := stage.run()
+		result := ciStage{Name: stage.name, Status: "passed", Duration: time.Since(start).Seconds()}
+
+		if err != nil {
+			failure = err
+			result.Status = "failed"
+			result.Error = err.Error()
+		}
+		summary.Stages = append(summary.Stages, result)
+	}
+
+	summary.Build = current().BuiltID
+	summary.Export = ciConfig.Export
+
+	summary.Status = "passed"
+	if failure != nil {
+		summary.Status = "failed"
+		summary.ExitCode = util.ExitCode(failure)
+		if code := registry.GetInt("exit_code"); code != 0 {
+			summary.ExitCode = code
+		}
+	}
+
+	if err := ciReport(summary, ciConfig.Summary); err != nil {
+		return err
+	}
+
+	return failure
+}
+
+// ciTest runs the tests, a failing test is an error so the run stops. Its
+// exit code stays in the registry, and becomes nanobox's.
+func ciTest(envModel *models.Env) error {
+	if err := Test(envModel, nil); err != nil {
+		return err
+	}
+
+	if code := registry.GetInt("exit_code"); code != 0 {
+		return util.Errorf("[USER] the tests failed with exit code %d", code)
+	}
+
+	return nil
+}
+
+// ciDeploy deploys the build to the dry-run environment or to a remote
+func ciDeploy(envModel *models.Env, target string) error {
+	if target != "dry-run" {
+		return Deploy(envModel, DeployConfig{App: target})
+	}
+
+	appModel, _ := models.FindAppBySlug(envModel.ID, envModel.DryRunName())
+	if err := app.Start(envModel, appModel, envModel.DryRunName()); err != nil {
+		return util.ErrorAppend(err, "failed to start the dry-run app")
+	}
+
+	return app.Deploy(envModel, appModel)
+}
+
+// ciReport writes the summary to its file, and prints it with --json or as
+// a table
+func ciReport(summary *ciSummary, path string) error {
+	if path != "" {
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			lumber.Error("processors:ciReport:ioutil.WriteFile(%s): %s", path, err.Error())
+			return util.ErrorAppend(err, "failed to write the summary")
+		}
+	}
+
+	if display.JSON() {
+		return display.PrintJSON(summary)
+	}
+
+	fmt.Println()
+	fmt.Printf("  %-10s %-8s %s\n", "STAGE", "STATUS", "TIME")
+	for _, stage := range summary.Stages {
+		took := ""
+		if stage.Status != "skipped" {
+			took = fmt.Sprintf("%.1fs", stage.Duration)
+		}
+		fmt.Printf("  %-10s %-8s %s\n", stage.Name, stage.Status, took)
+	}
+	fmt.Println()
+
+	return nil
+}
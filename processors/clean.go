@@ -1,38 +1,433 @@
 package processors
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/filters"
+	"github.com/docker/go-units"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/processors/env"
+	"github.com/nanobox-io/nanobox/processors/provider"
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/display"
 	"github.com/nanobox-io/nanobox/util/locker"
+	"github.com/nanobox-io/nanobox/util/logs"
+)
+
+// CleanConfig is what nanobox clean removes, without any of them it only
+// reports the disk usage
+type CleanConfig struct {
+	Images  bool // images no tag points at
+	Volumes bool // volumes of apps nanobox no longer knows about
+	Stale   bool // apps whose directory no longer exists
+}
+
+// the volumes nanobox keeps for an app, by what they hold
+var (
+	buildVolumes = []string{"build", "cache", "code"}
+	dataVolumes  = []string{"app", "deploy", "mount", "dev_code"}
 )
 
-//
-func Clean(envModels []*models.Env) error {
+// cleanRow is the disk an app uses
+type cleanRow struct {
+	App        string `json:"app"`
+	Path       string `json:"path"`
+	Stale      bool   `json:"stale"`
+	Images     int64  `json:"images"`      // bytes of the images of its components
+	BuildCache int64  `json:"build_cache"` // bytes of the build and cache volumes
+	Volumes    int64  `json:"volumes"`     // bytes of the other volumes and of the containers
+	Logs       int64  `json:"logs"`        // bytes of the kept logs
+
+	env *models.Env
+}
+
+// cleanReport is the disk nanobox uses, and what can be removed
+type cleanReport struct {
+	Apps            []cleanRow `json:"apps"`
+	Images          int64      `json:"images"` // bytes of the images the apps use
+	DanglingImages  []string   `json:"dangling_images"`
+	DanglingSize    int64      `json:"dangling_size"`
+	OrphanedVolumes []string   `json:"orphaned_volumes"`
+	OrphanedSize    int64      `json:"orphaned_size"`
+	Freed           int64      `json:"freed"`
+}
+
+// Clean reports the disk used by the images, the build cache, the volumes and
+// the logs of each app, and removes the dangling images, the orphaned volumes
+// and the stale apps it's asked to. Nothing an app nanobox knows about uses is
+// removed.
+func Clean(config CleanConfig) error {
 	locker.GlobalLock()
 	defer locker.GlobalUnlock()
 
-	display.OpenContext("Cleaning stale environments")
-	defer display.CloseContext()
+	// init docker client
+	if err := provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	report, err := cleanUsage()
+	if err != nil {
+		return err
+	}
+
+	if config.Images {
+		if err := pruneImages(&report); err != nil {
+			return err
+		}
+	}
 
-	// if any of the apps are stale, we'll mark this to true
-	stale := false
+	if config.Volumes {
+		if err := pruneVolumes(&report); err != nil {
+			return err
+		}
+	}
+
+	if config.Stale {
+		if err := pruneStale(&report); err != nil {
+			return err
+		}
+	}
 
-	for _, envModel := range envModels {
-		// check to see if the app folder still exists
-		if !util.FolderExists(envModel.Directory) {
+	if display.JSON() {
+		return display.PrintJSON(report)
+	}
+
+	printCleanReport(report, config)
+
+	return nil
+}
+
+// cleanUsage measures the disk each app uses, and finds what no app uses
+func cleanUsage() (cleanReport, error) {
+	report := cleanReport{Apps: []cleanRow{}, DanglingImages: []string{}, OrphanedVolumes: []string{}}
+
+	envs, err := models.AllEnvs()
+	if err != nil {
+		lumber.Error("processors:cleanUsage:models.AllEnvs(): %s", err.Error())
+		return report, util.ErrorAppend(err, "failed to load the apps")
+	}
+
+	images, err := docker.ImageList()
+	if err != nil {
+		lumber.Error("processors:cleanUsage:docker.ImageList(): %s", err.Error())
+		return report, util.ErrorAppend(err, "failed to list the images")
+	}
+
+	imageSizes := map[string]int64{}
+	for _, image := range images {
+		if dangling(image) {
+			report.DanglingImages = append(report.DanglingImages, image.ID)
+			report.DanglingSize += image.Size
+			continue
+		}
+		for _, tag := range image.RepoTags {
+			imageSizes[tag] = image.Size
+		}
+	}
 
-			if err := env.Destroy(envModel); err != nil {
-				return util.ErrorAppend(err, "unable to destroy environment(%s)", envModel.Name)
+	list, err := docker.Client.VolumeList(context.Background(), filters.NewArgs())
+	if err != nil {
+		lumber.Error("processors:cleanUsage:docker.Client.VolumeList(): %s", err.Error())
+		return report, util.ErrorAppend(err, "failed to list the volumes")
+	}
+
+	known := map[string]bool{}
+	for _, envModel := range envs {
+		known[envModel.ID] = true
+	}
+
+	volumes := []string{}
+	for _, volume := range list.Volumes {
+		envID, ok := volumeEnv(volume.Name)
+		if !ok {
+			continue
+		}
+		volumes = append(volumes, volume.Name)
+
+		if !known[envID] {
+			report.OrphanedVolumes = append(report.OrphanedVolumes, volume.Name)
+		}
+	}
+
+	display.StartTask("Measuring the volumes")
+	volumeSizes := env.VolumeSizes(volumes)
+	display.StopTask()
+
+	for _, volume := range report.OrphanedVolumes {
+		report.OrphanedSize += volumeSizes[volume]
+	}
+
+	containers := containerSizes()
+	used := map[string]bool{}
+
+	for _, envModel := range envs {
+		row := cleanRow{
+			App:   envModel.Name,
+			Path:  envModel.Directory,
+			Stale: !util.FolderExists(envModel.Directory),
+			env:   envModel,
+		}
+
+		for _, name := range buildVolumes {
+			row.BuildCache += volumeSizes[fmt.Sprintf("nanobox_%s_%s", envModel.ID, name)]
+		}
+		for _, name := range dataVolumes {
+			row.Volumes += volumeSizes[fmt.Sprintf("nanobox_%s_%s", envModel.ID, name)]
+		}
+
+		// an image is counted once per app, however many components use it
+		counted := map[string]bool{}
+
+		apps, _ := envModel.Apps()
+		for _, appModel := range apps {
+			row.Volumes += containers[appModel.ID]
+			row.Logs += dirSize(logs.AppDir(appModel.ID))
+
+			components, _ := appModel.Components()
+			for _, component := range components {
+				tag := imageTag(component.Image)
+				if counted[tag] {
+					continue
+				}
+				counted[tag] = true
+				row.Images += imageSizes[tag]
+
+				if !used[tag] {
+					used[tag] = true
+					report.Images += imageSizes[tag]
+				}
 			}
 		}
+
+		report.Apps = append(report.Apps, row)
 	}
 
-	if !stale {
+	return report, nil
+}
+
+// pruneImages removes the images no tag points at. An image a container
+// still uses is left alone.
+func pruneImages(report *cleanReport) error {
+	display.OpenContext("Removing dangling images")
+	defer display.CloseContext()
+
+	if len(report.DanglingImages) == 0 {
 		display.StartTask("Skipping (none detected)")
 		display.StopTask()
+		return nil
+	}
+
+	images, _ := docker.ImageList()
+	sizes := map[string]int64{}
+	for _, image := range images {
+		sizes[image.ID] = image.Size
+	}
+
+	kept := []string{}
+	for _, id := range report.DanglingImages {
+		display.StartTask("Removing %s", shortID(id))
+		if err := docker.ImageRemove(id, false); err != nil {
+			// a stopped container may still be using it
+			lumber.Debug("processors:pruneImages:docker.ImageRemove(%s): %s", id, err.Error())
+			display.StopTask()
+			kept = append(kept, id)
+			continue
+		}
+		display.StopTask()
+
+		report.Freed += sizes[id]
+		report.DanglingSize -= sizes[id]
 	}
+	report.DanglingImages = kept
 
 	return nil
 }
+
+// pruneVolumes removes the volumes of the apps nanobox no longer knows about
+func pruneVolumes(report *cleanReport) error {
+	display.OpenContext("Removing orphaned volumes")
+	defer display.CloseContext()
+
+	if len(report.OrphanedVolumes) == 0 {
+		display.StartTask("Skipping (none detected)")
+		display.StopTask()
+		return nil
+	}
+
+	for _, volume := range report.OrphanedVolumes {
+		display.StartTask("Removing %s", volume)
+		if err := docker.VolumeRemove(volume); err != nil {
+			display.ErrorTask()
+			lumber.Error("processors:pruneVolumes:docker.VolumeRemove(%s): %s", volume, err.Error())
+			return util.ErrorAppend(err, "failed to remove %s", volume)
+		}
+		display.StopTask()
+	}
+	report.Freed += report.OrphanedSize
+	report.OrphanedVolumes = []string{}
+	report.OrphanedSize = 0
+
+	return nil
+}
+
+// pruneStale destroys the apps whose directory no longer exists
+func pruneStale(report *cleanReport) error {
+	display.OpenContext("Cleaning stale environments")
+	defer display.CloseContext()
+
+	rows := []cleanRow{}
+	for _, row := range report.Apps {
+		if !row.Stale {
+			rows = append(rows, row)
+			continue
+		}
+
+		if err := env.Destroy(row.env); err != nil {
+			return util.ErrorAppend(err, "unable to destroy environment(%s)", row.App)
+		}
+
+		report.Freed += row.BuildCache + row.Volumes + row.Logs
+	}
+
+	if len(rows) == len(report.Apps) {
+		display.StartTask("Skipping (none detected)")
+		display.StopTask()
+	}
+	report.Apps = rows
+
+	return nil
+}
+
+// printCleanReport prints the disk usage of each app and what's left to
+// remove
+func printCleanReport(report cleanReport, config CleanConfig) {
+	fmt.Printf("\n%-20s %-10s %-12s %-10s %-10s %s\n", "App", "Images", "Build Cache", "Volumes", "Logs", "Path")
+	fmt.Println(strings.Repeat("-", 90))
+
+	var buildCache, volumes, logSize int64
+	stale := 0
+
+	for _, row := range report.Apps {
+		path := row.Path
+		if row.Stale {
+			path += " (missing)"
+			stale++
+		}
+
+		fmt.Printf("%-20s %-10s %-12s %-10s %-10s %s\n", row.App, humanSize(row.Images),
+			humanSize(row.BuildCache), humanSize(row.Volumes), humanSize(row.Logs), path)
+
+		buildCache += row.BuildCache
+		volumes += row.Volumes
+		logSize += row.Logs
+	}
+
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-20s %-10s %-12s %-10s %-10s\n\n", "Total", humanSize(report.Images),
+		humanSize(buildCache), humanSize(volumes), humanSize(logSize))
+
+	if len(report.DanglingImages) > 0 {
+		fmt.Printf("%d dangling images use %s", len(report.DanglingImages), humanSize(report.DanglingSize))
+		if !config.Images {
+			fmt.Print(", remove them with 'nanobox clean --images'")
+		}
+		fmt.Println()
+	}
+
+	if len(report.OrphanedVolumes) > 0 {
+		fmt.Printf("%d volumes of removed apps use %s, remove them with 'nanobox clean --volumes'\n",
+			len(report.OrphanedVolumes), humanSize(report.OrphanedSize))
+	}
+
+	if stale > 0 {
+		fmt.Printf("%d apps no longer have a directory, remove them with 'nanobox clean --stale'\n", stale)
+	}
+
+	if report.Freed > 0 {
+		fmt.Printf("Freed %s\n", humanSize(report.Freed))
+	}
+
+	fmt.Println()
+}
+
+// volumeEnv returns the env a nanobox volume belongs to, a volume is named
+// nanobox_<env id>_<what it holds>
+func volumeEnv(name string) (string, bool) {
+	if !strings.HasPrefix(name, "nanobox_") {
+		return "", false
+	}
+
+	// the env id has no underscores, what follows it may, ie: dev_code
+	parts := strings.SplitN(strings.TrimPrefix(name, "nanobox_"), "_", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", false
+	}
+
+	for _, suffix := range append(buildVolumes, dataVolumes...) {
+		if parts[1] == suffix {
+			return parts[0], true
+		}
+	}
+
+	return "", false
+}
+
+// dangling returns true if no tag points at the image
+func dangling(image types.Image) bool {
+	for _, tag := range image.RepoTags {
+		if tag != "<none>:<none>" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// imageTag adds the latest tag to an image without one
+func imageTag(image string) string {
+	if strings.Contains(image[strings.LastIndex(image, "/")+1:], ":") {
+		return image
+	}
+
+	return image + ":latest"
+}
+
+// dirSize returns the bytes of the files under dir
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+
+	return size
+}
+
+// humanSize prints a size in bytes, or a dash for nothing
+func humanSize(size int64) string {
+	if size == 0 {
+		return "-"
+	}
+
+	return units.HumanSize(float64(size))
+}
+
+// shortID returns the short form docker shows of an image id
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+
+	return id
+}
@@ -2,6 +2,7 @@ package code
 
 import (
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/jcelliott/lumber"
@@ -28,36 +29,87 @@ func Build(envModel *models.Env) error {
 		return util.ErrorAppend(err, "failed to pull the build image")
 	}
 
+	// pin the build environment before the container is created
+	commit := ""
+	if Reproducible {
+		commit, err = prepareReproducible(boxfile.NewFromPath(config.Boxfile()))
+		if err != nil {
+			return err
+		}
+	}
+
 	// if a build container was leftover from a previous build, let's remove it
 	docker.ContainerRemove(container_generator.BuildName())
 
+	// the sync needs to know if the code volume has to be filled from scratch
+	volumeExisted := syncVolumeExists()
+
 	display.StartTask("Starting docker container")
 
 	// start the container
 	contConfig := container_generator.BuildConfig(buildImage)
 	container, err := docker.CreateContainer(contConfig)
 	if err != nil {
-		lumber.Error("code:Build:docker.CreateContainer(%+v): %s", contConfig, err.Error())
+		lumber.Error("code:Build:docker.CreateContainer(%s): %s", contConfig.Name, err.Error())
 		return util.ErrorAppend(err, "failed to start docker container")
 	}
 
 	display.StopTask()
 
+	// a build canceled before the container existed has nothing to kill it
+	if atomic.LoadInt32(&canceled) == 1 {
+		docker.ContainerRemove(container.ID)
+		return util.Errorf("[CANCELED] the build was canceled")
+	}
+
+	if err := syncCode(envModel, container.ID, volumeExisted); err != nil {
+		return err
+	}
+
 	if err := prepareBuildEnvironment(container.ID); err != nil {
 		return err
 	}
 
-	if err := gatherRequirements(envModel, container.ID); err != nil {
+	if err := runScriptHooks(container.ID, hookBeforeBuild, hookEvars()); err != nil {
 		return err
 	}
 
+	// the cache keys are computed upfront and only saved once the build
+	// succeeds, so a failed build is never cached
+	box := boxfile.NewFromPath(config.Boxfile())
+	keys := cacheKeys(box)
+
+	if cached(envModel.BuildCache, keys, phaseRequirements) && envModel.BuiltBoxfile != "" {
+		display.StartTask("Gathering requirements (cached)")
+		display.StopTask()
+		envModel.UserBoxfile = box.String()
+		envModel.BuiltID = util.RandomString(30)
+	} else {
+		if err := gatherRequirements(envModel, container.ID); err != nil {
+			return err
+		}
+	}
+
 	populateBuildTriggers(envModel)
 
 	if err := setupBuildMounts(container.ID); err != nil {
 		return err
 	}
 
-	if err := installRuntimes(container.ID); err != nil {
+	if cached(envModel.BuildCache, keys, phaseRuntimes) {
+		display.StartTask("Installing binaries and runtimes (cached)")
+		display.StopTask()
+	} else {
+		if err := installRuntimes(container.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := runParallelSteps(envModel, container.ID); err != nil {
+		return err
+	}
+
+	if err := runScriptHooks(container.ID, hookAfterBuild, hookEvars()); err != nil {
 		return err
 	}
 
@@ -65,7 +117,14 @@ func Build(envModel *models.Env) error {
 		return err
 	}
 
+	if Reproducible {
+		if err := fingerprintBuild(envModel, container.ID, commit); err != nil {
+			return err
+		}
+	}
+
 	envModel.LastBuild = time.Now()
+	envModel.BuildCache = keys
 	envModel.Save()
 
 	// ensure we stop the container when we're done
@@ -76,6 +135,17 @@ func Build(envModel *models.Env) error {
 	return nil
 }
 
+// Cancel stops the running build. The build container is removed, which
+// kills the running hook, and a build that hasn't started its container yet
+// stops as soon as it does.
+func Cancel() {
+	atomic.StoreInt32(&canceled, 1)
+	docker.ContainerRemove(container_generator.BuildName())
+}
+
+// canceled is set once the build is canceled
+var canceled int32
+
 // prepareBuildEnvironment runs hooks to prepare the build environment
 func prepareBuildEnvironment(containerID string) error {
 	display.StartTask("Preparing environment for build")
@@ -0,0 +1,113 @@
+package code
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+)
+
+// NoCache forces every build phase to run, ignoring the build cache
+var NoCache bool
+
+// the cacheable build phases
+const (
+	// the boxfile hook, which generates the built boxfile
+	phaseRequirements = "requirements"
+	// the build hook, which installs the binaries and runtimes
+	phaseRuntimes = "runtimes"
+)
+
+// dependency manifests of the common languages, a change in any of these
+// means the runtimes have to be installed again
+var manifests = []string{
+	"Gemfile", "Gemfile.lock",
+	"package.json", "package-lock.json", "yarn.lock", "npm-shrinkwrap.json",
+	"requirements.txt", "Pipfile", "Pipfile.lock", "setup.py",
+	"composer.json", "composer.lock",
+	"glide.yaml", "glide.lock", "Godeps/Godeps.json", "vendor/vendor.json",
+	"mix.exs", "mix.lock",
+	"pom.xml", "build.gradle",
+	"Cargo.toml", "Cargo.lock",
+}
+
+// BuildInput returns true if a change to the file, relative to the app
+// root, needs a new build. Any other file is used as is by the dev container.
+func BuildInput(file string) bool {
+	if file == "boxfile.yml" {
+		return true
+	}
+
+	box := boxfile.NewFromPath(config.Boxfile())
+	inputs := append([]string{}, manifests...)
+	inputs = append(inputs, box.Node("run.config").StringSliceValue("build_triggers")...)
+
+	for _, input := range inputs {
+		if file == input {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cacheKeys returns the cache key of each cacheable phase. The key of a phase
+// covers its own inputs and the inputs of the phases before it.
+func cacheKeys(box boxfile.Boxfile) map[string]string {
+	requirements := sha256.New()
+	fmt.Fprintf(requirements, "boxfile:%s\n", box.String())
+	fmt.Fprintf(requirements, "engine:%s\n", box.Node("run.config").StringValue("engine"))
+
+	// a local engine can change without its name changing
+	if engineDir, _ := config.EngineDir(); engineDir != "" {
+		hashDir(requirements, engineDir)
+	}
+
+	requirementsKey := fmt.Sprintf("%x", requirements.Sum(nil))
+
+	runtimes := sha256.New()
+	fmt.Fprintf(runtimes, "requirements:%s\n", requirementsKey)
+
+	files := append([]string{}, manifests...)
+	files = append(files, box.Node("run.config").StringSliceValue("build_triggers")...)
+	sort.Strings(files)
+
+	for _, file := range files {
+		fmt.Fprintf(runtimes, "%s:%s\n", file, util.FileMD5(file))
+	}
+
+	return map[string]string{
+		phaseRequirements: requirementsKey,
+		phaseRuntimes:     fmt.Sprintf("%x", runtimes.Sum(nil)),
+	}
+}
+
+// hashDir writes the path and content of every file in dir to w
+func hashDir(w io.Writer, dir string) {
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		fmt.Fprintf(w, "%s:%s\n", filepath.ToSlash(rel), util.FileMD5(path))
+
+		return nil
+	})
+}
+
+// cached returns true if the phase can be skipped
+func cached(cache map[string]string, keys map[string]string, phase string) bool {
+	if NoCache || cache == nil {
+		return false
+	}
+
+	return cache[phase] != "" && cache[phase] == keys[phase]
+}
@@ -0,0 +1,113 @@
+package code
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// the file in the cache dir the build cache is kept in
+const cacheArchive = "build-cache.tar"
+
+// ImportCache fills the build cache volume from the archive in dir, ie: a
+// dir a ci provider restores between runs. There's nothing to import on the
+// first run.
+func ImportCache(dir string) error {
+	path := filepath.Join(dir, cacheArchive)
+
+	archive, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return util.ErrorAppend(err, "failed to open %s", path)
+	}
+	defer archive.Close()
+
+	display.StartTask("Restoring build cache")
+	defer display.StopTask()
+
+	// the volume is replaced, not merged
+	containerID, err := startArtifactContainer([]string{"find", "/mnt/cache", "-mindepth", "1", "-delete"})
+	if err != nil {
+		display.ErrorTask()
+		return err
+	}
+	defer docker.ContainerRemove(containerID)
+
+	if _, err := docker.Client.ContainerWait(context.Background(), containerID); err != nil {
+		display.ErrorTask()
+		lumber.Error("code:ImportCache:docker.Client.ContainerWait(%s): %s", containerID, err.Error())
+		return util.ErrorAppend(err, "failed to clear the build cache")
+	}
+
+	// the entries are named cache/, so they land in the volume
+	err = docker.Client.CopyToContainer(context.Background(), containerID, "/mnt", archive, types.CopyToContainerOptions{})
+	if err != nil {
+		display.ErrorTask()
+		lumber.Error("code:ImportCache:docker.Client.CopyToContainer(%s): %s", containerID, err.Error())
+		return util.ErrorAppend(err, "failed to copy the build cache into the container")
+	}
+
+	return nil
+}
+
+// ExportCache writes the build cache volume to the archive in dir, for
+// ImportCache to restore on the next run
+func ExportCache(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return util.ErrorAppend(err, "failed to create %s", dir)
+	}
+
+	display.StartTask("Saving build cache")
+	defer display.StopTask()
+
+	containerID, err := startArtifactContainer([]string{"true"})
+	if err != nil {
+		display.ErrorTask()
+		return err
+	}
+	defer docker.ContainerRemove(containerID)
+
+	rc, _, err := docker.Client.CopyFromContainer(context.Background(), containerID, "/mnt/cache")
+	if err != nil {
+		display.ErrorTask()
+		lumber.Error("code:ExportCache:docker.Client.CopyFromContainer(%s): %s", containerID, err.Error())
+		return util.ErrorAppend(err, "failed to copy the build cache from the container")
+	}
+	defer rc.Close()
+
+	// a run cancelled half way mustn't leave a broken archive behind
+	path := filepath.Join(dir, cacheArchive)
+	tmp := path + ".tmp"
+
+	archive, err := os.Create(tmp)
+	if err != nil {
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to create %s", tmp)
+	}
+
+	_, err = io.Copy(archive, rc)
+	archive.Close()
+	if err != nil {
+		os.Remove(tmp)
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to write the build cache")
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to write the build cache")
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package code
+
+import (
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"github.com/nanobox-io/nanobox-boxfile"
+	"golang.org/x/net/context"
+
+	container_generator "github.com/nanobox-io/nanobox/generators/containers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// the stable components aren't code components anymore, so a sync leaves
+// them running
+const (
+	stableType   = "stable"
+	stableSuffix = "-stable"
+)
+
+// RetireWeb sets the web components aside so they keep serving the previous
+// build while the canary runs. The components and their containers are
+// renamed so the new build can take their place.
+func RetireWeb(appModel *models.App) (map[string]*models.Component, error) {
+	display.StartTask("Keeping stable web components")
+	defer display.StopTask()
+
+	// anything left over from an interrupted canary has to go first
+	if err := RemoveStable(appModel); err != nil {
+		display.ErrorTask()
+		return nil, err
+	}
+
+	stable := map[string]*models.Component{}
+
+	box := boxfile.New([]byte(appModel.DeployedBoxfile))
+	for _, name := range box.Nodes("web") {
+		componentModel, err := models.FindComponentBySlug(appModel.ID, name)
+		if err != nil || componentModel.ID == "" {
+			continue
+		}
+
+		if err := componentModel.Delete(); err != nil {
+			display.ErrorTask()
+			lumber.Error("code:RetireWeb:models.Component.Delete(): %s", err.Error())
+			return nil, util.ErrorAppend(err, "unable to delete database model")
+		}
+
+		componentModel.Name = name + stableSuffix
+		componentModel.Type = stableType
+
+		if err := docker.Client.ContainerRename(context.Background(), componentModel.ID, container_generator.ComponentName(componentModel)); err != nil {
+			display.ErrorTask()
+			lumber.Error("code:RetireWeb:docker.Client.ContainerRename(%s): %s", componentModel.ID, err.Error())
+			return nil, util.ErrorAppend(err, "failed to rename docker container")
+		}
+
+		if err := componentModel.Save(); err != nil {
+			display.ErrorTask()
+			lumber.Error("code:RetireWeb:models.Component.Save(): %s", err.Error())
+			return nil, util.ErrorAppend(err, "unable to save component model")
+		}
+
+		stable[name] = componentModel
+	}
+
+	return stable, nil
+}
+
+// RemoveStable destroys the web components that served the previous build
+func RemoveStable(appModel *models.App) error {
+	componentModels, err := appModel.Components()
+	if err != nil {
+		lumber.Error("code:RemoveStable:models.App{ID:%s}.Components(): %s", appModel.ID, err.Error())
+		return util.ErrorAppend(err, "unable to retrieve components")
+	}
+
+	for _, componentModel := range componentModels {
+		if componentModel.Type != stableType {
+			continue
+		}
+
+		if err := Destroy(componentModel); err != nil {
+			return util.ErrorAppend(err, "failed to destroy stable component")
+		}
+	}
+
+	return nil
+}
@@ -2,8 +2,6 @@
 package code
 
 import (
-	"time"
-
 	"github.com/jcelliott/lumber"
 	"github.com/nanobox-io/golang-docker-client"
 	"github.com/nanobox-io/nanobox-boxfile"
@@ -11,6 +9,7 @@ import (
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/config"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/images"
 )
 
 // these constants represent different potential names a service can have
@@ -41,12 +40,8 @@ func pullBuildImage() (string, error) {
 	}
 
 	// pull the build image
-	imagePull := func() error {
-		_, err := docker.ImagePull(buildImage, dockerPercent)
-		return err
-	}
-	if err := util.Retry(imagePull, 5, time.Second); err != nil {
-		lumber.Error("code:pullBuildImage:docker.ImagePull(%s, nil): %s", buildImage, err.Error())
+	if err := images.Download(buildImage, dockerPercent); err != nil {
+		lumber.Error("code:pullBuildImage:images.Download(%s): %s", buildImage, err.Error())
 		display.ErrorTask()
 		return "", util.ErrorAppend(err, "failed to pull docker image (%s)", buildImage)
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/dhcp"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/logs"
 )
 
 // Destroy destroys a code component from the app
@@ -17,6 +18,11 @@ func Destroy(componentModel *models.Component) error {
 	display.OpenContext(componentModel.Label)
 	defer display.CloseContext()
 
+	// keep its logs for 'nanobox logs'
+	if err := logs.Persist(componentModel.AppID, componentModel.Name, componentModel.ID); err != nil {
+		lumber.Error("code:Destroy:logs.Persist(%s): %s", componentModel.ID, err.Error())
+	}
+
 	// remove the docker container
 	if err := destroyContainer(componentModel.ID); err != nil {
 		return err
@@ -52,6 +58,9 @@ func destroyContainer(id string) error {
 		return util.ErrorAppend(err, "failed to remove docker container")
 	}
 
+	// the output of its hooks goes with it
+	logs.RemoveHookLog(id)
+
 	return nil
 }
 
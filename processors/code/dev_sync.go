@@ -0,0 +1,373 @@
+package code
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	container_generator "github.com/nanobox-io/nanobox/generators/containers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/filesync"
+	"github.com/nanobox-io/nanobox/util/hostpath"
+	"github.com/nanobox-io/nanobox/util/transfer"
+)
+
+// how often both sides are compared while the console is open
+const devSyncInterval = 2 * time.Second
+
+// the files the ignore rules are read from, in the root of the code
+var devSyncIgnoreFiles = []string{".gitignore", ".nanoignore", ".nanoboxignore"}
+
+// devSyncer keeps the code on the host and in the dev container in sync
+type devSyncer struct {
+	dir         string
+	containerID string
+	basePath    string
+	conflictDir string
+	base        filesync.Base
+
+	// true while the syncs fail, so the failure is only reported once
+	failing bool
+
+	// the files left in the container because their name only differs by
+	// case from another, so each is only reported once
+	collisions map[string]bool
+}
+
+// StartDevSync copies the code into the dev container, then keeps the host
+// and the container in sync both ways in the background. A file changed on
+// both sides is a conflict: the host's copy wins and the container's is
+// kept in the global dir. The returned func does a last sync and stops.
+func StartDevSync(envModel *models.Env, containerID string) (func(), error) {
+	if !container_generator.CodeSync() {
+		return func() {}, nil
+	}
+
+	dir := filepath.ToSlash(filepath.Join(config.GlobalDir(), "sync"))
+	os.MkdirAll(dir, 0755)
+
+	syncer := &devSyncer{
+		dir:         config.LocalDir(),
+		containerID: containerID,
+		basePath:    filepath.Join(dir, fmt.Sprintf("%s_dev.json", envModel.ID)),
+		conflictDir: filepath.Join(dir, "conflicts", envModel.ID),
+	}
+
+	base, err := filesync.LoadBase(syncer.basePath)
+	if err != nil {
+		lumber.Error("code:StartDevSync:filesync.LoadBase(%s): %s", syncer.basePath, err.Error())
+	}
+	syncer.base = base
+
+	display.StartTask("Syncing code")
+	if err := syncer.sync(); err != nil {
+		display.ErrorTask()
+		return nil, util.ErrorAppend(err, "failed to sync the code into the dev container")
+	}
+	display.StopTask()
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+
+		for {
+			select {
+			case <-time.After(devSyncInterval):
+				syncer.syncInBackground()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-finished
+
+			// bring the last changes of the container home
+			syncer.syncInBackground()
+		})
+	}, nil
+}
+
+// syncInBackground syncs, reporting a failure once until a sync succeeds
+func (s *devSyncer) syncInBackground() {
+	err := s.sync()
+	if err == nil {
+		s.failing = false
+		return
+	}
+
+	lumber.Error("code:devSyncer.sync(): %s", err.Error())
+	if !s.failing {
+		s.report("the code sync failed, it will be retried: %s", err.Error())
+	}
+	s.failing = true
+}
+
+// sync compares both sides to the last sync and copies the changes across
+func (s *devSyncer) sync() error {
+	ignore, err := filesync.LoadIgnore(s.dir, devSyncIgnoreFiles...)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to read the ignore rules")
+	}
+	for _, vcs := range []string{".git/", ".hg/", ".svn/", ".bzr/"} {
+		ignore.Add(vcs)
+	}
+
+	host, err := filesync.ScanIgnoring(s.dir, ignore)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to scan the code")
+	}
+
+	container, err := s.scanContainer()
+	if err != nil {
+		return util.ErrorAppend(err, "failed to scan the code in the container")
+	}
+	container = container.Filter(ignore)
+
+	// a case-insensitive host holds names that only differ by case as one
+	// file, so the container's are left out rather than overwrite each other
+	if hostpath.CaseInsensitive(runtime.GOOS) {
+		s.skipCollisions(host, container)
+	}
+
+	// a side with no files at all was recreated, ie: a new volume, rather
+	// than emptied by hand, so it's filled up instead of emptying the other
+	if (len(host) == 0 && len(s.base.Host) > 0) || (len(container) == 0 && len(s.base.Container) > 0) {
+		s.base = filesync.Base{}
+	}
+
+	plan := filesync.Reconcile(s.base, host, container)
+	if plan.Empty() {
+		s.base = filesync.Base{Host: host, Container: container}
+		s.save()
+		return nil
+	}
+
+	lumber.Info("code:devSyncer.sync(): %d pushed, %d pulled, %d removed from the host, %d removed from the container, %d conflicts",
+		len(plan.Push), len(plan.Pull), len(plan.RemoveHost), len(plan.RemoveContainer), len(plan.Conflicts))
+
+	for _, file := range plan.Conflicts {
+		kept := s.keepConflict(file)
+		s.report("%s changed on both sides, kept your machine's copy, the container's is in %s", file, kept)
+	}
+
+	if err := s.push(plan.Push); err != nil {
+		return err
+	}
+	if err := s.pull(plan.Pull); err != nil {
+		return err
+	}
+	if err := removeFiles(s.containerID, plan.RemoveContainer); err != nil {
+		return util.ErrorAppend(err, "failed to remove the deleted files from the container")
+	}
+	for _, file := range plan.RemoveHost {
+		if err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(file))); err != nil && !os.IsNotExist(err) {
+			return util.ErrorAppend(err, "failed to remove %s", file)
+		}
+	}
+
+	// the source of a copy is remembered as it was scanned, so a change
+	// made while it was copied is picked up next time. The destination is
+	// remembered as it was written.
+	next := filesync.Base{Host: host, Container: container}
+
+	if len(plan.Push) > 0 || len(plan.RemoveContainer) > 0 {
+		written, err := s.scanContainer()
+		if err != nil {
+			return util.ErrorAppend(err, "failed to scan the code in the container")
+		}
+		for _, file := range plan.Push {
+			if state, ok := written[file]; ok {
+				next.Container[file] = state
+			} else {
+				delete(next.Container, file)
+			}
+		}
+		for _, file := range plan.RemoveContainer {
+			delete(next.Container, file)
+		}
+	}
+
+	for _, file := range plan.Pull {
+		info, err := os.Lstat(filepath.Join(s.dir, filepath.FromSlash(file)))
+		if err != nil {
+			continue
+		}
+		next.Host[file] = filesync.FileState{Size: info.Size(), ModTime: info.ModTime().UnixNano(), Mode: info.Mode()}
+	}
+	for _, file := range plan.RemoveHost {
+		delete(next.Host, file)
+	}
+
+	s.base = next
+	s.save()
+
+	return nil
+}
+
+// scanContainer builds the manifest of the code in the container
+func (s *devSyncer) scanContainer() (filesync.Manifest, error) {
+	args := []string{"/app", "-mindepth", "1", "(", "-type", "f", "-o", "-type", "l", ")", "-printf", `%P\t%s\t%T@\t%m\t%y\n`}
+
+	out, err := util.DockerExec(s.containerID, "root", "find", args, nil)
+	if err != nil {
+		lumber.Error("code:devSyncer.scanContainer:util.DockerExec(find): %s: %s", out, err.Error())
+		return nil, err
+	}
+
+	return parseFind(out), nil
+}
+
+// parseFind reads the output of find -printf '%P\t%s\t%T@\t%m\t%y\n'
+func parseFind(out string) filesync.Manifest {
+	manifest := filesync.Manifest{}
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 5 {
+			continue
+		}
+
+		size, _ := strconv.ParseInt(fields[1], 10, 64)
+		perm, _ := strconv.ParseUint(fields[3], 8, 32)
+
+		mode := os.FileMode(perm)
+		if fields[4] == "l" {
+			mode |= os.ModeSymlink
+		}
+
+		manifest[fields[0]] = filesync.FileState{Size: size, ModTime: parseFindTime(fields[2]), Mode: mode}
+	}
+
+	return manifest
+}
+
+// parseFindTime converts seconds with a fraction, ie: 1476306354.1234567890,
+// to nanoseconds
+func parseFindTime(value string) int64 {
+	parts := strings.SplitN(value, ".", 2)
+	seconds, _ := strconv.ParseInt(parts[0], 10, 64)
+
+	var nanos int64
+	if len(parts) == 2 {
+		fraction := (parts[1] + "000000000")[:9]
+		nanos, _ = strconv.ParseInt(fraction, 10, 64)
+	}
+
+	return seconds*1e9 + nanos
+}
+
+// push copies the files from the host into the container
+func (s *devSyncer) push(files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(filesync.Tar(s.dir, files, pw))
+	}()
+
+	err := docker.Client.CopyToContainer(context.Background(), s.containerID, "/app", pr, types.CopyToContainerOptions{AllowOverwriteDirWithFile: true})
+	pr.Close()
+	if err != nil {
+		lumber.Error("code:devSyncer.push:docker.Client.CopyToContainer(%s): %s", s.containerID, err.Error())
+		return util.ErrorAppend(err, "failed to copy the changed files into the container")
+	}
+
+	return nil
+}
+
+// pull copies the files from the container to the host
+func (s *devSyncer) pull(files []string) error {
+	for _, file := range files {
+		if err := s.copyOut(file, filepath.Join(s.dir, filepath.FromSlash(file))); err != nil {
+			return util.ErrorAppend(err, "failed to copy %s from the container", file)
+		}
+	}
+
+	return nil
+}
+
+// keepConflict saves the container's copy of a conflicting file in the
+// conflict dir and returns where it went
+func (s *devSyncer) keepConflict(file string) string {
+	kept := filepath.Join(s.conflictDir, filepath.FromSlash(file))
+
+	if err := s.copyOut(file, kept); err != nil {
+		lumber.Error("code:devSyncer.keepConflict(%s): %s", file, err.Error())
+	}
+
+	return kept
+}
+
+// copyOut copies a file from the container to the host path
+func (s *devSyncer) copyOut(file, dest string) error {
+	rc, _, err := docker.Client.CopyFromContainer(context.Background(), s.containerID, "/app/"+file)
+	if err != nil {
+		lumber.Error("code:devSyncer.copyOut:docker.Client.CopyFromContainer(%s, %s): %s", s.containerID, file, err.Error())
+		return err
+	}
+	defer rc.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	return transfer.Unpack(rc, filepath.Dir(dest), filepath.Base(dest), nil)
+}
+
+// skipCollisions removes from the container manifest the files whose name
+// only differs by case from another file and isn't the host's spelling
+func (s *devSyncer) skipCollisions(host, container filesync.Manifest) {
+	for _, file := range hostpath.Collisions(host.Paths(), container.Paths()) {
+		if _, ok := host[file]; ok {
+			continue
+		}
+		if _, ok := container[file]; !ok {
+			continue
+		}
+		delete(container, file)
+
+		if s.collisions == nil {
+			s.collisions = map[string]bool{}
+		}
+		if !s.collisions[file] {
+			s.collisions[file] = true
+			s.report("%s only differs by case from another file, it's left in the container", file)
+		}
+	}
+}
+
+// save remembers the state of the last sync. Failing to is only logged, the
+// next sync compares the sides again.
+func (s *devSyncer) save() {
+	if err := s.base.Save(s.basePath); err != nil {
+		lumber.Error("code:devSyncer.save:filesync.Base.Save(%s): %s", s.basePath, err.Error())
+	}
+}
+
+// report shows a message above the console, which is in raw mode
+func (s *devSyncer) report(format string, args ...interface{}) {
+	os.Stderr.WriteString("\r\nnanobox: " + fmt.Sprintf(format, args...) + "\r\n")
+}
@@ -0,0 +1,198 @@
+package code
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	container_generator "github.com/nanobox-io/nanobox/generators/containers"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// the directories of the compiled app, they are exported as app/ and data/
+var exportPaths = []string{"/mnt/app", "/data"}
+
+// Export copies the compiled app and its runtime out of the docker volumes.
+// A destination ending in .tar, .tar.gz or .tgz is written as an archive,
+// anything else is treated as a directory.
+func Export(dest string) error {
+	display.OpenContext("Exporting application")
+	defer display.CloseContext()
+
+	buildImage, err := pullBuildImage()
+	if err != nil {
+		return util.ErrorAppend(err, "failed to pull the build image")
+	}
+
+	// if an export container was leftover from a previous export, let's remove it
+	docker.ContainerRemove(container_generator.ExportName())
+
+	config := container_generator.ExportConfig(buildImage)
+	container, err := docker.CreateContainer(config)
+	if err != nil {
+		lumber.Error("code:Export:docker.CreateContainer(%+v): %s", config, err.Error())
+		return util.ErrorAppend(err, "failed to start docker container")
+	}
+	defer docker.ContainerRemove(container.ID)
+
+	display.StartTask("Copying to %s", dest)
+	defer display.StopTask()
+
+	if err := exportTo(container.ID, dest); err != nil {
+		display.ErrorTask()
+		return err
+	}
+
+	return nil
+}
+
+// exportTo writes the export paths of the container to dest
+func exportTo(containerID, dest string) error {
+	switch {
+	case strings.HasSuffix(dest, ".tar"):
+		return exportArchive(containerID, dest, false)
+	case strings.HasSuffix(dest, ".tar.gz"), strings.HasSuffix(dest, ".tgz"):
+		return exportArchive(containerID, dest, true)
+	default:
+		return exportDir(containerID, dest)
+	}
+}
+
+// exportArchive writes every export path into a single archive
+func exportArchive(containerID, dest string, compress bool) error {
+	file, err := os.Create(dest)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to create %s", dest)
+	}
+
+	if err := writeExportArchive(containerID, file, compress); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return util.ErrorAppend(err, "failed to write %s", dest)
+	}
+
+	return nil
+}
+
+// writeExportArchive writes the export tar to the file, compressed if asked
+func writeExportArchive(containerID string, file io.Writer, compress bool) error {
+	if !compress {
+		return writeExportTar(containerID, file)
+	}
+
+	gz := gzip.NewWriter(file)
+	if err := writeExportTar(containerID, gz); err != nil {
+		gz.Close()
+		return err
+	}
+
+	// the gzip footer is only written on close
+	if err := gz.Close(); err != nil {
+		return util.ErrorAppend(err, "failed to compress the export")
+	}
+
+	return nil
+}
+
+// writeExportTar writes every export path into a single tar stream on w
+func writeExportTar(containerID string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+
+	err := eachExportEntry(containerID, func(header *tar.Header, r io.Reader) error {
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := io.Copy(tw, r)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// exportDir extracts every export path into a directory
+func exportDir(containerID, dest string) error {
+	dest, err := filepath.Abs(dest)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to resolve %s", dest)
+	}
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return util.ErrorAppend(err, "failed to create %s", dest)
+	}
+
+	return eachExportEntry(containerID, func(header *tar.Header, r io.Reader) error {
+		target := filepath.Join(dest, filepath.FromSlash(header.Name))
+
+		// refuse anything that would land outside of the destination
+		if !strings.HasPrefix(target, dest+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid path in export: %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			return os.MkdirAll(target, os.FileMode(header.Mode))
+		case tar.TypeSymlink:
+			os.Remove(target)
+			return os.Symlink(header.Linkname, target)
+		case tar.TypeReg, tar.TypeRegA:
+			os.MkdirAll(filepath.Dir(target), 0755)
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(f, r)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// eachExportEntry calls fn for every entry of the export paths. Docker names
+// the entries after the last element of the path, so /mnt/app becomes app/.
+func eachExportEntry(containerID string, fn func(header *tar.Header, r io.Reader) error) error {
+	for _, path := range exportPaths {
+		rc, _, err := docker.Client.CopyFromContainer(context.Background(), containerID, path)
+		if err != nil {
+			lumber.Error("code:eachExportEntry:docker.Client.CopyFromContainer(%s, %s): %s", containerID, path, err.Error())
+			return util.ErrorAppend(err, "failed to copy %s from the container", path)
+		}
+
+		tr := tar.NewReader(rc)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				rc.Close()
+				return util.ErrorAppend(err, "failed to read %s", path)
+			}
+
+			if err := fn(header, tr); err != nil {
+				rc.Close()
+				return util.ErrorAppend(err, "failed to export %s", header.Name)
+			}
+		}
+
+		rc.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package code
+
+import (
+	"crypto/md5"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	container_generator "github.com/nanobox-io/nanobox/generators/containers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/filesync"
+)
+
+// the number of files removed per exec, to stay under the argument limit
+const removeBatch = 500
+
+// syncCode transfers the files that changed since the last build into the
+// code volume of the build container
+func syncCode(envModel *models.Env, containerID string, volumeExisted bool) error {
+	if !container_generator.BuildSync() {
+		return nil
+	}
+
+	display.StartTask("Syncing code")
+	defer display.StopTask()
+
+	manifestPath := syncManifestPath(envModel)
+
+	current, err := filesync.Scan(config.LocalDir())
+	if err != nil {
+		display.ErrorTask()
+		lumber.Error("code:syncCode:filesync.Scan(%s): %s", config.LocalDir(), err.Error())
+		return util.ErrorAppend(err, "failed to scan the code")
+	}
+
+	// a new volume is empty, so everything has to be transferred
+	old := filesync.Manifest{}
+	if volumeExisted {
+		if old, err = filesync.Load(manifestPath); err != nil {
+			lumber.Error("code:syncCode:filesync.Load(%s): %s", manifestPath, err.Error())
+			old = filesync.Manifest{}
+		}
+	}
+
+	changed, removed := filesync.Diff(old, current)
+	lumber.Info("code:syncCode: %d changed, %d removed", len(changed), len(removed))
+
+	if len(changed) > 0 {
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(filesync.Tar(config.LocalDir(), changed, pw))
+		}()
+
+		if err := docker.Client.CopyToContainer(context.Background(), containerID, "/app", pr, types.CopyToContainerOptions{AllowOverwriteDirWithFile: true}); err != nil {
+			display.ErrorTask()
+			lumber.Error("code:syncCode:docker.Client.CopyToContainer(%s): %s", containerID, err.Error())
+			return util.ErrorAppend(err, "failed to copy the changed files")
+		}
+	}
+
+	if err := removeFiles(containerID, removed); err != nil {
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to remove the deleted files")
+	}
+
+	// only remember what was synced once everything made it across
+	if err := current.Save(manifestPath); err != nil {
+		lumber.Error("code:syncCode:filesync.Manifest.Save(%s): %s", manifestPath, err.Error())
+	}
+
+	return nil
+}
+
+// removeFiles removes the files, relative to /app, from the container
+func removeFiles(containerID string, files []string) error {
+	for i := 0; i < len(files); i += removeBatch {
+		end := i + removeBatch
+		if end > len(files) {
+			end = len(files)
+		}
+
+		args := []string{"-f", "--"}
+		for _, file := range files[i:end] {
+			args = append(args, "/app/"+file)
+		}
+
+		if out, err := util.DockerExec(containerID, "root", "rm", args, nil); err != nil {
+			lumber.Error("code:removeFiles:util.DockerExec(rm): %s: %s", out, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncVolumeExists returns true if the code volume survived since the last
+// build. It has to be checked before the build container creates it.
+func syncVolumeExists() bool {
+	if !container_generator.BuildSync() {
+		return false
+	}
+
+	_, err := docker.Client.VolumeInspect(context.Background(), container_generator.BuildSyncVolume())
+	return err == nil
+}
+
+// syncManifestPath returns the location of the manifest of the last sync
+func syncManifestPath(envModel *models.Env) string {
+	dir := filepath.ToSlash(filepath.Join(config.GlobalDir(), "sync"))
+	os.MkdirAll(dir, 0755)
+
+	// each remote host has its own volume to keep track of
+	name := envModel.ID
+	if container_generator.RemoteHost != "" {
+		name = fmt.Sprintf("%s_%x", envModel.ID, md5.Sum([]byte(container_generator.RemoteHost)))
+	}
+
+	return filepath.ToSlash(filepath.Join(dir, fmt.Sprintf("%s.json", name)))
+}
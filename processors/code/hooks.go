@@ -0,0 +1,140 @@
+package code
+
+import (
+	"fmt"
+
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	container_generator "github.com/nanobox-io/nanobox/generators/containers"
+	hook_generator "github.com/nanobox-io/nanobox/generators/hooks/build"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/hookit"
+	"github.com/nanobox-io/nanobox/util/interpolate"
+	"github.com/nanobox-io/nanobox/util/secretref"
+)
+
+// the script hooks a boxfile can declare, each is a command or a list of
+// commands, ie:
+//
+//	run.config:
+//	  before_build: 'npm config set registry https://npm.example.com'
+//	  after_build:
+//	    - 'rm -rf tmp/cache'
+//	  before_deploy: './bin/check-migrations'
+const (
+	hookBeforeBuild  = "before_build"
+	hookAfterBuild   = "after_build"
+	hookBeforeDeploy = "before_deploy"
+)
+
+// scriptHooks returns the commands of a script hook from the boxfile
+func scriptHooks(box boxfile.Boxfile, hook string) []string {
+	cmds := []string{}
+
+	switch val := box.Node("run.config").Value(hook).(type) {
+	case string:
+		cmds = append(cmds, val)
+	case []interface{}:
+		for _, cmd := range val {
+			cmds = append(cmds, fmt.Sprintf("%v", cmd))
+		}
+	}
+
+	return cmds
+}
+
+// runScriptHooks runs the commands of a script hook in the container with
+// the evars set. The first failing command stops the hook.
+func runScriptHooks(containerID, hook string, evars map[string]string) error {
+	cmds := scriptHooks(boxfile.NewFromPath(config.Boxfile()), hook)
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	display.StartTask("Running %s hooks", hook)
+	defer display.StopTask()
+
+	// the evars are set on the exec, so they don't show up in the process list
+	env := []string{}
+	for key, val := range evars {
+		env = append(env, fmt.Sprintf("%s=%s", key, val))
+	}
+
+	for _, cmd := range cmds {
+		stream := display.NewStreamer("info")
+
+		run := util.DockerCommand(containerID, "gonano", "bash", []string{"-lc", cmd})
+		run.Env = env
+		run.Stdout = stream
+		run.Stderr = stream
+
+		if err := run.Run(); err != nil {
+			display.ErrorTask()
+			lumber.Error("code:runScriptHooks:util.Cmd.Run(%s): %s", cmd, err.Error())
+			return util.Errorf("[BUILD] the %s hook '%s' failed: %s", hook, cmd, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// hookEvars returns the evars of the local dev app, the build hooks run with
+// the same environment as the code
+func hookEvars() map[string]string {
+	appModel, _ := models.FindAppBySlug(config.EnvID(), "dev")
+	if appModel.Evars == nil {
+		return map[string]string{}
+	}
+
+	return interpolate.Expanded(secretref.Resolved(appModel.Evars))
+}
+
+// BeforeDeploy runs the before_deploy hooks of the boxfile in a build
+// container with the evars of the app being deployed to
+func BeforeDeploy(envModel *models.Env, evars map[string]string) error {
+	if len(scriptHooks(boxfile.NewFromPath(config.Boxfile()), hookBeforeDeploy)) == 0 {
+		return nil
+	}
+
+	display.OpenContext("Preparing deploy")
+	defer display.CloseContext()
+
+	buildImage, err := pullBuildImage()
+	if err != nil {
+		return util.ErrorAppend(err, "failed to pull the build image")
+	}
+
+	// if a build container was leftover from a previous build, let's remove it
+	docker.ContainerRemove(container_generator.BuildName())
+
+	display.StartTask("Starting docker container")
+	contConfig := container_generator.BuildConfig(buildImage)
+	container, err := docker.CreateContainer(contConfig)
+	if err != nil {
+		display.ErrorTask()
+		lumber.Error("code:BeforeDeploy:docker.CreateContainer(%s): %s", contConfig.Name, err.Error())
+		return util.ErrorAppend(err, "failed to start docker container")
+	}
+	display.StopTask()
+
+	defer docker.ContainerRemove(container.ID)
+
+	// the hooks run as gonano, so the user has to exist
+	display.StartTask("Preparing environment")
+	if out, err := hookit.DebugExec(container.ID, "user", hook_generator.UserPayload(), "info"); err != nil {
+		display.ErrorTask()
+		if err2, ok := err.(util.Err); ok {
+			err2.Output = out
+			return util.ErrorAppend(err2, "failed to run the (build)user hook")
+		}
+		return util.ErrorAppend(err, "failed to run the (build)user hook")
+	}
+	display.StopTask()
+
+	return runScriptHooks(container.ID, hookBeforeDeploy, evars)
+}
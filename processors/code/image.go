@@ -0,0 +1,141 @@
+package code
+
+import (
+	"io"
+	"sort"
+
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/container"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"github.com/nanobox-io/nanobox-boxfile"
+	"golang.org/x/net/context"
+
+	container_generator "github.com/nanobox-io/nanobox/generators/containers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/images"
+)
+
+// the base of the app image, the same image the code components run in
+const imageBase = "nanobox/code"
+
+// Image packages the compiled app and its runtime into a docker image that
+// runs the start command of the first web component
+func Image(envModel *models.Env, tag string) error {
+	display.OpenContext("Packaging image %s", tag)
+	defer display.CloseContext()
+
+	if !docker.ImageExists(imageBase) {
+		display.StartTask("Pulling %s image", imageBase)
+		dockerPercent := &display.DockerPercentDisplay{
+			Output: display.NewStreamer("info"),
+		}
+		if err := images.Download(imageBase, dockerPercent); err != nil {
+			display.ErrorTask()
+			lumber.Error("code:Image:images.Download(%s): %s", imageBase, err.Error())
+			return util.ErrorAppend(err, "failed to pull docker image (%s)", imageBase)
+		}
+		display.StopTask()
+	}
+
+	buildImage, err := pullBuildImage()
+	if err != nil {
+		return util.ErrorAppend(err, "failed to pull the build image")
+	}
+
+	// the export container holds the build and app volumes
+	docker.ContainerRemove(container_generator.ExportName())
+	exportConfig := container_generator.ExportConfig(buildImage)
+	export, err := docker.CreateContainer(exportConfig)
+	if err != nil {
+		lumber.Error("code:Image:docker.CreateContainer(%+v): %s", exportConfig, err.Error())
+		return util.ErrorAppend(err, "failed to start docker container")
+	}
+	defer docker.ContainerRemove(export.ID)
+
+	// the image container is committed once the app is copied in
+	docker.ContainerRemove(container_generator.ImageName())
+	imageConfig := container_generator.ImageConfig(imageBase)
+	image, err := docker.CreateContainer(imageConfig)
+	if err != nil {
+		lumber.Error("code:Image:docker.CreateContainer(%+v): %s", imageConfig, err.Error())
+		return util.ErrorAppend(err, "failed to start docker container")
+	}
+	defer docker.ContainerRemove(image.ID)
+
+	display.StartTask("Copying the app")
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeExportTar(export.ID, pw))
+	}()
+
+	// the export holds app/ and data/, which land at /app and /data
+	if err := docker.Client.CopyToContainer(context.Background(), image.ID, "/", pr, types.CopyToContainerOptions{}); err != nil {
+		display.ErrorTask()
+		lumber.Error("code:Image:docker.Client.CopyToContainer(%s): %s", image.ID, err.Error())
+		return util.ErrorAppend(err, "failed to copy the app into the image")
+	}
+	display.StopTask()
+
+	display.StartTask("Committing image")
+	options := types.ContainerCommitOptions{
+		Reference: tag,
+		Config:    imageRuntimeConfig(envModel),
+	}
+	if _, err := docker.Client.ContainerCommit(context.Background(), image.ID, options); err != nil {
+		display.ErrorTask()
+		lumber.Error("code:Image:docker.Client.ContainerCommit(%s, %s): %s", image.ID, tag, err.Error())
+		return util.ErrorAppend(err, "failed to commit the image")
+	}
+	display.StopTask()
+
+	return nil
+}
+
+// imageRuntimeConfig returns the config the image runs with
+func imageRuntimeConfig(envModel *models.Env) *container.Config {
+	config := &container.Config{
+		WorkingDir: "/app",
+		Env:        []string{"PATH=/data/sbin:/data/bin:/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"},
+		Labels:     map[string]string{"io.nanobox.build-id": envModel.BuiltID},
+	}
+
+	if start := imageStart(boxfile.New([]byte(envModel.BuiltBoxfile))); start != "" {
+		config.Cmd = []string{"/bin/sh", "-c", start}
+	} else {
+		display.Warn("No start command found on a web component, the image has no default command\n")
+	}
+
+	return config
+}
+
+// imageStart returns the start command of the first web component. When
+// the component starts several processes only the first one is used.
+func imageStart(box boxfile.Boxfile) string {
+	webs := box.Nodes("web")
+	if len(webs) == 0 {
+		return ""
+	}
+	sort.Strings(webs)
+
+	node := box.Node(webs[0])
+	if start := node.StringValue("start"); start != "" {
+		return start
+	}
+
+	starts := node.Node("start").Parsed
+	names := []string{}
+	for name := range starts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		return ""
+	}
+
+	start, _ := starts[names[0]].(string)
+	return start
+}
@@ -0,0 +1,68 @@
+package code
+
+import (
+	"github.com/jcelliott/lumber"
+
+	hook_generator "github.com/nanobox-io/nanobox/generators/hooks/code"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/hookit"
+	"github.com/nanobox-io/nanobox/util/secretref"
+)
+
+// StaleComponents returns the running code components that were configured
+// with other evars than the app has now
+func StaleComponents(appModel *models.App) ([]*models.Component, error) {
+	stale := []*models.Component{}
+
+	if appModel.Status != "up" {
+		return stale, nil
+	}
+
+	components, err := appModel.Components()
+	if err != nil {
+		lumber.Error("code:StaleComponents:models.App{ID:%s}.Components(): %s", appModel.ID, err.Error())
+		return stale, util.ErrorAppend(err, "unable to retrieve components")
+	}
+
+	for _, componentModel := range components {
+		if (componentModel.Type == "code" || componentModel.Type == "stable") &&
+			componentModel.State == ACTIVE && componentModel.EvarsSum != appModel.EvarsSum(componentModel.Name) {
+			stale = append(stale, componentModel)
+		}
+	}
+
+	return stale, nil
+}
+
+// Refresh reconfigures a running code component with the current evars and
+// restarts its processes, the container and the build are left alone
+func Refresh(appModel *models.App, componentModel *models.Component) error {
+	// the evars may reference a secret backend, it has to answer first
+	if _, err := secretref.ResolveAll(appModel.EvarsFor(componentModel.Name)); err != nil {
+		return util.Errorf("[USER] %s", err.Error())
+	}
+
+	display.StartTask("Restarting %s", componentModel.Label)
+
+	// the configure hook rewrites the environment of the processes
+	payload := hook_generator.ConfigurePayload(appModel, componentModel)
+	for _, hook := range []string{"configure", "stop", "start"} {
+		if _, err := hookit.DebugExec(componentModel.ID, hook, payload, "info"); err != nil {
+			display.ErrorTask()
+			return util.ErrorAppend(err, "failed to %s %s", hook, componentModel.Name)
+		}
+	}
+
+	componentModel.EvarsSum = appModel.EvarsSum(componentModel.Name)
+	if err := componentModel.Save(); err != nil {
+		lumber.Error("code:Refresh:models.Component.Save(): %s", err.Error())
+		display.ErrorTask()
+		return util.ErrorAppend(err, "unable to save component model")
+	}
+
+	display.StopTask()
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package code
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/mitchellh/go-homedir"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	container_generator "github.com/nanobox-io/nanobox/generators/containers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// the volumes a build leaves behind, they are copied back from the remote
+var artifactPaths = []string{"/mnt/build", "/mnt/deploy"}
+
+// the docker variables set by the provider, they don't apply to a remote host
+var remoteEnvVars = []string{"DOCKER_HOST", "DOCKER_TLS_VERIFY", "DOCKER_CERT_PATH", "DOCKER_MACHINE_NAME"}
+
+// RemoteInsecure talks to the remote docker host without tls. The build and
+// its artifacts, build args included, cross the network in the clear.
+var RemoteInsecure bool
+
+// BuildRemote runs the build on a remote docker host. The code is synced up
+// to the host, and once the build is done the build artifacts are copied
+// back into the local volumes so the app can be compiled and run locally.
+func BuildRemote(envModel *models.Env, host string) error {
+	// the remote host can't see a local engine, it fetches a pinned one itself
+	if config.LocalEngine() {
+		return util.Errorf("[USER] a local engine can't be used with a remote build")
+	}
+
+	artifacts, err := ioutil.TempFile("", "nanobox-artifacts")
+	if err != nil {
+		return util.ErrorAppend(err, "failed to create temp file")
+	}
+	defer os.Remove(artifacts.Name())
+	defer artifacts.Close()
+
+	if err := buildOnRemote(envModel, host, artifacts); err != nil {
+		return err
+	}
+
+	if _, err := artifacts.Seek(0, io.SeekStart); err != nil {
+		return util.ErrorAppend(err, "failed to rewind the build artifacts")
+	}
+
+	if err := importArtifacts(artifacts); err != nil {
+		return util.ErrorAppend(err, "failed to import the build artifacts")
+	}
+
+	return nil
+}
+
+// buildOnRemote points the docker client at the remote host, runs the build
+// and writes the build artifacts to w
+func buildOnRemote(envModel *models.Env, host string, w io.Writer) error {
+	if err := useRemote(host); err != nil {
+		return err
+	}
+
+	// whatever happens, we're back on the local docker once we're done
+	defer func() {
+		container_generator.RemoteHost = ""
+		if err := provider.Init(); err != nil {
+			lumber.Error("code:buildOnRemote:provider.Init(): %s", err.Error())
+		}
+	}()
+
+	if err := Build(envModel); err != nil {
+		return err
+	}
+
+	display.OpenContext("Fetching build from %s", host)
+	defer display.CloseContext()
+
+	if err := exportArtifacts(w); err != nil {
+		return util.ErrorAppend(err, "failed to export the build artifacts")
+	}
+
+	return nil
+}
+
+// useRemote initializes the docker client for the remote host. The host is
+// verified with the client certs in ~/.docker unless RemoteInsecure is set.
+func useRemote(host string) error {
+	for _, key := range remoteEnvVars {
+		os.Unsetenv(key)
+	}
+
+	// a plain host:port gets the port docker listens on for the scheme
+	if !strings.Contains(host, "://") {
+		if !strings.Contains(host, ":") {
+			host = host + remotePort()
+		}
+		host = "tcp://" + host
+	}
+	os.Setenv("DOCKER_HOST", host)
+
+	if !RemoteInsecure {
+		home, err := homedir.Dir()
+		if err != nil {
+			return util.ErrorAppend(err, "failed to find the docker client certs")
+		}
+		os.Setenv("DOCKER_TLS_VERIFY", "1")
+		os.Setenv("DOCKER_CERT_PATH", filepath.Join(home, ".docker"))
+	}
+
+	if err := docker.Initialize("env"); err != nil {
+		lumber.Error("code:useRemote:docker.Initialize(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to initialize the docker client for %s", host)
+	}
+
+	if _, err := docker.ContainerList(); err != nil {
+		lumber.Error("code:useRemote:docker.ContainerList(): %s", err.Error())
+		if RemoteInsecure {
+			return util.Errorf("[USER] unable to communicate with docker on %s", host)
+		}
+		return util.Errorf("[USER] unable to communicate with docker on %s over tls, the client certs are read from ~/.docker", host)
+	}
+
+	container_generator.RemoteHost = host
+
+	return nil
+}
+
+// remotePort returns the default docker port for the scheme of the remote
+func remotePort() string {
+	if RemoteInsecure {
+		return ":2375"
+	}
+	return ":2376"
+}
+
+// exportArtifacts writes the build and deploy volumes into a tar stream on w
+func exportArtifacts(w io.Writer) error {
+	display.StartTask("Copying build artifacts")
+	defer display.StopTask()
+
+	containerID, err := startArtifactContainer([]string{"true"})
+	if err != nil {
+		display.ErrorTask()
+		return err
+	}
+	defer docker.ContainerRemove(containerID)
+
+	tw := tar.NewWriter(w)
+
+	for _, path := range artifactPaths {
+		rc, _, err := docker.Client.CopyFromContainer(context.Background(), containerID, path)
+		if err != nil {
+			display.ErrorTask()
+			lumber.Error("code:exportArtifacts:docker.Client.CopyFromContainer(%s, %s): %s", containerID, path, err.Error())
+			return util.ErrorAppend(err, "failed to copy %s from the container", path)
+		}
+
+		err = copyTar(tw, tar.NewReader(rc))
+		rc.Close()
+		if err != nil {
+			display.ErrorTask()
+			return util.ErrorAppend(err, "failed to read %s", path)
+		}
+	}
+
+	return tw.Close()
+}
+
+// importArtifacts replaces the contents of the local build and deploy
+// volumes with the artifacts
+func importArtifacts(r io.Reader) error {
+	display.OpenContext("Importing build")
+	defer display.CloseContext()
+
+	display.StartTask("Copying build artifacts")
+	defer display.StopTask()
+
+	// the old build has to go first, otherwise removed files would linger
+	containerID, err := startArtifactContainer([]string{"find", "/mnt/build", "/mnt/deploy", "-mindepth", "1", "-delete"})
+	if err != nil {
+		display.ErrorTask()
+		return err
+	}
+	defer docker.ContainerRemove(containerID)
+
+	if _, err := docker.Client.ContainerWait(context.Background(), containerID); err != nil {
+		display.ErrorTask()
+		lumber.Error("code:importArtifacts:docker.Client.ContainerWait(%s): %s", containerID, err.Error())
+		return util.ErrorAppend(err, "failed to clear the previous build")
+	}
+
+	// the entries are named build/ and deploy/, so they land in the volumes
+	err = docker.Client.CopyToContainer(context.Background(), containerID, "/mnt", r, types.CopyToContainerOptions{})
+	if err != nil {
+		display.ErrorTask()
+		lumber.Error("code:importArtifacts:docker.Client.CopyToContainer(%s): %s", containerID, err.Error())
+		return util.ErrorAppend(err, "failed to copy the build into the container")
+	}
+
+	return nil
+}
+
+// startArtifactContainer starts a container with the build volumes mounted
+func startArtifactContainer(cmd []string) (string, error) {
+	buildImage, err := pullBuildImage()
+	if err != nil {
+		return "", util.ErrorAppend(err, "failed to pull the build image")
+	}
+
+	// if an artifact container was leftover, let's remove it
+	docker.ContainerRemove(container_generator.ArtifactName())
+
+	config := container_generator.ArtifactConfig(buildImage, cmd)
+	container, err := docker.CreateContainer(config)
+	if err != nil {
+		lumber.Error("code:startArtifactContainer:docker.CreateContainer(%+v): %s", config, err.Error())
+		return "", util.ErrorAppend(err, "failed to start docker container")
+	}
+
+	return container.ID, nil
+}
+
+// copyTar copies every entry of tr onto tw
+func copyTar(tw *tar.Writer, tr *tar.Reader) error {
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package code
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	container_generator "github.com/nanobox-io/nanobox/generators/containers"
+	hook_generator "github.com/nanobox-io/nanobox/generators/hooks/build"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// Reproducible builds the code so that two builds of the same commit yield
+// identical artifacts. The engine has to be pinned, every phase runs without
+// the caches, the package mirrors come from the boxfile and the timestamps
+// are set to the commit time, ie:
+//
+//	run.config:
+//	  engine: ruby#v1.2.0
+//	  mirrors:
+//	    NPM_CONFIG_REGISTRY: https://npm.example.com
+var Reproducible bool
+
+// the directories the fingerprint covers
+var fingerprintPaths = []string{"/mnt/build", "/mnt/deploy"}
+
+// prepareReproducible checks the boxfile can be built reproducibly and pins
+// the build environment
+func prepareReproducible(box boxfile.Boxfile) (string, error) {
+	engine := box.Node("run.config").StringValue("engine")
+	engineDir, _ := config.EngineDir()
+
+	// a local engine is as pinned as the working tree it lives in
+	if engineDir == "" && !strings.Contains(engine, "#") {
+		return "", util.Errorf("[USER] reproducible builds need a pinned engine, ie: engine: %s#v1.0.0", engine)
+	}
+
+	commit, epoch := sourceCommit()
+
+	// uncommitted changes aren't part of the commit, so the build can't be
+	// compared with other builds of it
+	if commit != "" && dirtyTree() {
+		display.Warn("The working tree has uncommitted changes, this build isn't reproducible from %s\n", commit)
+		commit = ""
+	}
+
+	// the caches hold whatever the previous builds left behind
+	NoCache = true
+	hook_generator.ClearPkgCache = true
+
+	if container_generator.BuildArgs == nil {
+		container_generator.BuildArgs = map[string]string{}
+	}
+
+	for key, val := range box.Node("run.config").Node("mirrors").Parsed {
+		container_generator.BuildArgs[key] = fmt.Sprintf("%v", val)
+	}
+
+	container_generator.BuildArgs["SOURCE_DATE_EPOCH"] = strconv.FormatInt(epoch, 10)
+	container_generator.BuildArgs["TZ"] = "UTC"
+	container_generator.BuildArgs["LC_ALL"] = "C"
+
+	return commit, nil
+}
+
+// sourceCommit returns the commit being built and its timestamp. Outside of a
+// git repo there is no commit and the timestamps are set to 0.
+func sourceCommit() (string, int64) {
+	out, err := exec.Command("git", "-C", config.LocalDir(), "log", "-1", "--format=%H %ct").Output()
+	if err != nil {
+		lumber.Debug("code:sourceCommit:git log: %s", err.Error())
+		return "", 0
+	}
+
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return "", 0
+	}
+
+	epoch, _ := strconv.ParseInt(fields[1], 10, 64)
+
+	return fields[0], epoch
+}
+
+// dirtyTree reports whether the working tree has changes that aren't
+// committed, untracked files included as they end up in the build too
+func dirtyTree() bool {
+	out, err := exec.Command("git", "-C", config.LocalDir(), "status", "--porcelain").Output()
+	if err != nil {
+		lumber.Debug("code:dirtyTree:git status: %s", err.Error())
+		return true
+	}
+
+	return len(strings.TrimSpace(string(out))) > 0
+}
+
+// fingerprintBuild normalizes the timestamps of the build, then records a
+// checksum of the build artifacts. A different fingerprint for a commit that
+// was built before means the build isn't reproducible.
+func fingerprintBuild(envModel *models.Env, containerID, commit string) error {
+	display.StartTask("Fingerprinting build")
+
+	epoch := container_generator.BuildArgs["SOURCE_DATE_EPOCH"]
+	paths := strings.Join(fingerprintPaths, " ")
+
+	script := fmt.Sprintf(
+		"find %s -exec touch -h -d @%s {} + && cd / && find %s -type f -print0 | LC_ALL=C sort -z | xargs -0 -r sha256sum | sha256sum | cut -d' ' -f1",
+		paths, epoch, strings.Replace(paths, "/mnt/", "mnt/", -1))
+
+	out, err := util.DockerExec(containerID, "root", "bash", []string{"-c", script}, display.NewStreamer("info"))
+	if err != nil {
+		display.ErrorTask()
+		lumber.Error("code:fingerprintBuild:util.DockerExec(%s): %s", containerID, err.Error())
+		return util.ErrorAppend(err, "failed to fingerprint the build")
+	}
+
+	fingerprint := strings.TrimSpace(out)
+	mismatch := ""
+
+	if envModel.Fingerprints == nil {
+		envModel.Fingerprints = map[string]string{}
+	}
+
+	if commit != "" {
+		if previous, ok := envModel.Fingerprints[commit]; ok && previous != fingerprint {
+			lumber.Error("code:fingerprintBuild: %s built as %s, previously %s", commit, fingerprint, previous)
+			mismatch = previous
+		}
+		envModel.Fingerprints[commit] = fingerprint
+	}
+
+	envModel.BuildFingerprint = fingerprint
+
+	display.StopTask()
+	display.Info("Build fingerprint: %s\n", fingerprint)
+
+	if mismatch != "" {
+		display.Warn("The build of %s doesn't match its previous build (%s), it isn't reproducible\n", commit, mismatch)
+	}
+
+	return nil
+}
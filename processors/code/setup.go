@@ -1,18 +1,19 @@
 package code
 
 import (
-	"time"
-
 	"github.com/jcelliott/lumber"
 	"github.com/nanobox-io/golang-docker-client"
 
 	container_generator "github.com/nanobox-io/nanobox/generators/containers"
 	hook_generator "github.com/nanobox-io/nanobox/generators/hooks/code"
 	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/component"
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/dhcp"
 	"github.com/nanobox-io/nanobox/util/display"
 	"github.com/nanobox-io/nanobox/util/hookit"
+	"github.com/nanobox-io/nanobox/util/images"
+	"github.com/nanobox-io/nanobox/util/secretref"
 )
 
 //
@@ -40,12 +41,8 @@ func Setup(appModel *models.App, componentModel *models.Component, warehouseConf
 	if !docker.ImageExists(componentModel.Image) {
 		// pull the component image
 		display.StartTask("Pulling %s image", componentModel.Image)
-		imagePull := func() error {
-			_, err := docker.ImagePull(componentModel.Image, dockerPercent)
-			return err
-		}
-		if err := util.Retry(imagePull, 5, time.Second); err != nil {
-			lumber.Error("component:Setup:docker.ImagePull(%s, nil): %s", componentModel.Image, err.Error())
+		if err := images.Download(componentModel.Image, dockerPercent); err != nil {
+			lumber.Error("component:Setup:images.Download(%s): %s", componentModel.Image, err.Error())
 			display.ErrorTask()
 			return util.ErrorAppend(err, "failed to pull docker image (%s)", componentModel.Image)
 		}
@@ -61,7 +58,16 @@ func Setup(appModel *models.App, componentModel *models.Component, warehouseConf
 	}
 
 	// create docker container
-	config := container_generator.ComponentConfig(componentModel)
+	config := container_generator.CodeConfig(appModel, componentModel)
+
+	// the resource limits from the boxfile
+	resources, err := component.Limits(appModel, componentModel)
+	if err != nil {
+		display.ErrorTask()
+		return err
+	}
+	container_generator.SetLimits(&config, resources)
+
 	// remove any container that may have been created with this name befor
 	// this can happen if the process is killed after the
 	// container was created but before our db model was saved
@@ -69,7 +75,8 @@ func Setup(appModel *models.App, componentModel *models.Component, warehouseConf
 
 	container, err := docker.CreateContainer(config)
 	if err != nil {
-		lumber.Error("code:Setup:createContainer:docker.CreateContainer(%+v)", config)
+		// the config has the secrets in it, only log the name
+		lumber.Error("code:Setup:createContainer:docker.CreateContainer(%s): %s", config.Name, err.Error())
 		display.ErrorTask()
 		return util.ErrorAppend(err, "unable to create container")
 	}
@@ -77,16 +84,21 @@ func Setup(appModel *models.App, componentModel *models.Component, warehouseConf
 
 	// save the component
 	componentModel.ID = container.ID
+	componentModel.ImageDigest = images.Digest(componentModel.Image)
 	if err := componentModel.Save(); err != nil {
 		lumber.Error("code:Setup:Component.Save()")
 		return err
 	}
 
+	if err := component.SetCPULimit(componentModel.ID, resources); err != nil {
+		return err
+	}
+
 	lumber.Prefix("code:Setup")
 	defer lumber.Prefix("")
 
 	// run fetch build command
-	fetchPayload := hook_generator.FetchPayload(componentModel, warehouseConfig.WarehouseURL)
+	fetchPayload := hook_generator.FetchPayload(componentModel, warehouseConfig.BuildID, warehouseConfig.WarehouseURL)
 
 	display.StartTask("Fetching build from warehouse")
 	if _, err := hookit.DebugExec(componentModel.ID, "fetch", fetchPayload, "info"); err != nil {
@@ -95,6 +107,11 @@ func Setup(appModel *models.App, componentModel *models.Component, warehouseConf
 	}
 	display.StopTask()
 
+	// the evars may reference a secret backend, it has to answer first
+	if _, err := secretref.ResolveAll(appModel.EvarsFor(componentModel.Name)); err != nil {
+		return util.Errorf("[USER] %s", err.Error())
+	}
+
 	// run configure command
 	payload := hook_generator.ConfigurePayload(appModel, componentModel)
 
@@ -114,6 +131,7 @@ func Setup(appModel *models.App, componentModel *models.Component, warehouseConf
 
 	//
 	componentModel.State = ACTIVE
+	componentModel.EvarsSum = appModel.EvarsSum(componentModel.Name)
 	if err := componentModel.Save(); err != nil {
 		lumber.Error("code:Configure:Component.Save()")
 		return util.ErrorAppend(err, "unable to save component model")
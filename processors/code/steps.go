@@ -0,0 +1,157 @@
+package code
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// buildStep is an independent build step declared by the engine in the
+// built boxfile, ie:
+//
+//	run.config:
+//	  parallel_steps:
+//	    assets: 'npm run build'
+//	    deps: 'bundle install'
+type buildStep struct {
+	Name    string
+	Command string
+}
+
+// parallelSteps returns the independent build steps of the built boxfile
+func parallelSteps(box boxfile.Boxfile) []buildStep {
+	parsed := box.Node("run.config").Node("parallel_steps").Parsed
+
+	names := []string{}
+	for name := range parsed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	steps := []buildStep{}
+	for _, name := range names {
+		steps = append(steps, buildStep{Name: name, Command: fmt.Sprintf("%v", parsed[name])})
+	}
+
+	return steps
+}
+
+// runParallelSteps runs the independent build steps concurrently, at most
+// one per cpu. Each step has a line of its own in the progress.
+func runParallelSteps(envModel *models.Env, containerID string) error {
+	steps := parallelSteps(boxfile.New([]byte(envModel.BuiltBoxfile)))
+	if len(steps) == 0 {
+		return nil
+	}
+
+	progress := display.StartProgress("Running build steps")
+	defer progress.Stop()
+
+	lines := make([]*display.ProgressLine, len(steps))
+	for i, step := range steps {
+		lines[i] = progress.Line(step.Name)
+		lines[i].Set("waiting")
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	errs := make([]error, len(steps))
+	slots := make(chan struct{}, runtime.NumCPU())
+
+	for i, step := range steps {
+		wg.Add(1)
+		go func(i int, step buildStep) {
+			defer wg.Done()
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			lines[i].Set("running")
+
+			// the line shows the last of the output, in verbose mode the whole
+			// output of every step is shown
+			var out io.Writer = lines[i]
+			if !display.Summary {
+				w := &lineWriter{mu: &mu, w: display.NewStreamer("info"), prefix: fmt.Sprintf("[%s] ", step.Name)}
+				defer w.Flush()
+				out = w
+			}
+
+			cmd := util.DockerCommand(containerID, "gonano", "bash", []string{"-lc", step.Command})
+			cmd.Stdout = out
+			cmd.Stderr = out
+
+			if err := cmd.Run(); err != nil {
+				lumber.Error("code:runParallelSteps:util.Cmd.Run(%s): %s", step.Command, err.Error())
+				errs[i] = util.ErrorAppend(err, "build step '%s' failed", step.Name)
+				lines[i].Fail(err)
+				return
+			}
+
+			lines[i].Done()
+		}(i, step)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lineWriter prefixes each line and writes whole lines only, so the output
+// of concurrent steps is interleaved by line rather than mid-line
+type lineWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    bytes.Buffer
+}
+
+// Write implements io.Writer
+func (l *lineWriter) Write(p []byte) (int, error) {
+	l.buf.Write(p)
+
+	for {
+		i := bytes.IndexByte(l.buf.Bytes(), '\n')
+		if i == -1 {
+			break
+		}
+
+		line := l.buf.Next(i + 1)
+		l.mu.Lock()
+		_, err := fmt.Fprintf(l.w, "%s%s", l.prefix, line)
+		l.mu.Unlock()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes out a trailing partial line
+func (l *lineWriter) Flush() {
+	if l.buf.Len() == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	fmt.Fprintf(l.w, "%s%s\n", l.prefix, l.buf.String())
+	l.mu.Unlock()
+	l.buf.Reset()
+}
@@ -6,7 +6,9 @@ import (
 
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/hostmount"
 	"github.com/nanobox-io/nanobox/util/locker"
 )
 
@@ -98,9 +100,11 @@ func codeComponentModels(appModel *models.App) []*models.Component {
 		}
 
 		componentModel := &models.Component{
-			Name:  componentName,
-			Label: componentName,
-			Image: image,
+			Name:          componentName,
+			Label:         componentName,
+			Image:         image,
+			RestartPolicy: box.Node(componentName).StringValue("restart"),
+			Mounts:        hostmount.Resolve(box.Node(componentName).StringSliceValue("mounts"), config.LocalDir()),
 		}
 
 		componentModels = append(componentModels, componentModel)
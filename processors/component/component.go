@@ -4,29 +4,107 @@ package component
 import (
 	"fmt"
 
+	"github.com/jcelliott/lumber"
 	"github.com/nanobox-io/golang-docker-client"
 	"github.com/nanobox-io/nanobox-boxfile"
 
 	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/interpolate"
+	"github.com/nanobox-io/nanobox/util/overlay"
+	"github.com/nanobox-io/nanobox/util/procfile"
+)
+
+// Boxfile returns the boxfile the env was built with, with the overlay of
+// the app's environment merged onto it, ie: boxfile.dev.yml for dev, and its
+// variables resolved from the evars of the app
+func Boxfile(envModel *models.Env, appModel *models.App) (string, error) {
+	return BoxfileFor(envModel, overlay.Name(appModel.Name), interpolate.Expanded(appModel.Evars))
+}
+
+// BoxfileFor returns the boxfile an environment is deployed with, the
+// boxfile the env was built with resolved for the environment
+func BoxfileFor(envModel *models.Env, env string, evars map[string]string) (string, error) {
+	box, missing, err := Resolve(envModel.BuiltBoxfile, env, evars)
+	if err != nil {
+		return envModel.BuiltBoxfile, err
+	}
+
+	// they may be for the shell of a component
+	if len(missing) > 0 {
+		lumber.Debug("component:BoxfileFor: unresolved variables %v", missing)
+	}
+
+	return box, nil
+}
+
+// Resolve adds the process types of the Procfile to a boxfile, merges the
+// overlay of the environment onto it and resolves its variables. The
+// variables that couldn't be resolved are returned.
+func Resolve(box, env string, evars map[string]string) (string, []string, error) {
+	box, err := procfile.Apply(box)
+	if err != nil {
+		return "", nil, err
+	}
+
+	box, err = overlay.Apply(box, env)
+	if err != nil {
+		return "", nil, err
+	}
+
+	resolved, missing, err := interpolate.Boxfile(box, evars)
+	if err != nil {
+		return "", nil, util.ErrorAppend(err, "failed to resolve the variables of the boxfile")
+	}
+
+	return resolved, missing, nil
+}
+
+// appBoxfile returns the boxfile an app is being deployed with. The overlay
+// was checked when the sync started.
+func appBoxfile(appModel *models.App) (boxfile.Boxfile, error) {
+	envModel, err := appModel.Env()
+	if err != nil {
+		return boxfile.New([]byte{}), fmt.Errorf("failed to load env model: %s", err.Error())
+	}
+
+	box, _ := Boxfile(envModel, appModel)
+
+	return boxfile.New([]byte(box)), nil
+}
+
+// the docker calls that bring a container up, variables so the tests can
+// fake the containers
+var (
+	containerStatus  = dockerContainerStatus
+	containerStart   = docker.ContainerStart
+	containerPause   = dockerContainerPause
+	containerUnpause = dockerContainerUnpause
 )
 
 // isComponentRunning returns true if a service is already running
 func isComponentRunning(containerID string) bool {
+	return containerStatus(containerID) == "running"
+}
+
+// dockerContainerStatus returns the status of a container, ie: running or
+// paused, empty if the container doesn't exist
+func dockerContainerStatus(containerID string) string {
 	container, err := docker.GetContainer(containerID)
+	if err != nil {
+		return ""
+	}
 
-	// if the container doesn't exist then just return false
-	return err == nil && container.State.Status == "running"
+	return container.State.Status
 }
 
 // componentImage returns the image for the component
-func componentImage(component *models.Component) (string, error) {
-	// fetch the env
-	env, err := models.FindEnvByID(component.EnvID)
+func componentImage(appModel *models.App, component *models.Component) (string, error) {
+	box, err := appBoxfile(appModel)
 	if err != nil {
-		return "", fmt.Errorf("failed to load env model: %s", err.Error())
+		return "", err
 	}
 
-	box := boxfile.New([]byte(env.BuiltBoxfile))
 	image := box.Node(component.Name).StringValue("image")
 
 	// the only way image can be empty is if it's a platform service
@@ -0,0 +1,66 @@
+package component
+
+import (
+	"io"
+
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// DataDir is the directory inside a data component where the service keeps
+// its persistent data
+const DataDir = "/data/var/db"
+
+// ExportData streams a tarball of the component's data directory to w
+func ExportData(componentModel *models.Component, w io.Writer) error {
+	display.StartTask("Exporting %s data", componentModel.Label)
+	defer display.StopTask()
+
+	reader, _, err := docker.Client.CopyFromContainer(context.Background(), componentModel.ID, DataDir)
+	if err != nil {
+		lumber.Error("component:ExportData:docker.Client.CopyFromContainer(%s, %s): %s", componentModel.ID, DataDir, err.Error())
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to read component data")
+	}
+	defer reader.Close()
+
+	if _, err := io.Copy(w, reader); err != nil {
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to write component data")
+	}
+
+	return nil
+}
+
+// ImportData replaces the component's data directory with the tarball in r.
+// The container is stopped while the data is copied and started afterwards.
+func ImportData(componentModel *models.Component, r io.Reader) error {
+	if err := Stop(componentModel); err != nil {
+		return util.ErrorAppend(err, "failed to stop component")
+	}
+
+	display.StartTask("Importing %s data", componentModel.Label)
+
+	// the exported tarball contains the data directory itself, so we extract
+	// into its parent
+	options := types.CopyToContainerOptions{AllowOverwriteDirWithFile: false}
+	if err := docker.Client.CopyToContainer(context.Background(), componentModel.ID, "/data/var", r, options); err != nil {
+		lumber.Error("component:ImportData:docker.Client.CopyToContainer(%s): %s", componentModel.ID, err.Error())
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to write component data")
+	}
+
+	display.StopTask()
+
+	if err := Start(componentModel); err != nil {
+		return util.ErrorAppend(err, "failed to start component")
+	}
+
+	return nil
+}
@@ -1,7 +1,9 @@
 package component
 
 import (
+	"fmt"
 	"net"
+	"strings"
 
 	"github.com/jcelliott/lumber"
 	"github.com/nanobox-io/golang-docker-client"
@@ -10,6 +12,8 @@ import (
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/dhcp"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/logs"
+	"github.com/nanobox-io/nanobox/util/provider"
 )
 
 // Destroy destroys a component from the provider and database
@@ -17,6 +21,20 @@ func Destroy(appModel *models.App, componentModel *models.Component) error {
 	display.OpenContext(componentModel.Label)
 	defer display.CloseContext()
 
+	// a shared component is only destroyed with the last app that uses it
+	inUse, err := releaseShared(appModel, componentModel)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return forgetComponent(appModel, componentModel)
+	}
+
+	// keep its logs for 'nanobox logs'
+	if err := logs.Persist(componentModel.AppID, componentModel.Name, componentModel.ID); err != nil {
+		lumber.Error("component:Destroy:logs.Persist(%s): %s", componentModel.ID, err.Error())
+	}
+
 	// remove the docker container
 	if err := destroyContainer(componentModel.ID); err != nil {
 		// report the error but continue on
@@ -29,6 +47,22 @@ func Destroy(appModel *models.App, componentModel *models.Component) error {
 		return util.ErrorAppend(err, "failed to detach container from the host network")
 	}
 
+	if err := forgetComponent(appModel, componentModel); err != nil {
+		return err
+	}
+
+	// make sure nothing was left behind
+	for _, leak := range verifyDestroyed(appModel, componentModel) {
+		lumber.Error("component:Destroy:verifyDestroyed(%s): %s", componentModel.Name, leak)
+		display.Warn("%s was not cleaned up: %s\n", componentModel.Name, leak)
+	}
+
+	return nil
+}
+
+// forgetComponent removes the component from the app, its evars and its
+// record
+func forgetComponent(appModel *models.App, componentModel *models.Component) error {
 	// purge evars
 	if err := componentModel.PurgeEvars(appModel); err != nil {
 		lumber.Error("component:Destroy:models.Component.PurgeEvars(%+v): %s", appModel, err.Error())
@@ -44,6 +78,57 @@ func Destroy(appModel *models.App, componentModel *models.Component) error {
 	return nil
 }
 
+// verifyDestroyed checks that every resource of a destroyed component is
+// gone and returns a description of each one that leaked
+func verifyDestroyed(appModel *models.App, componentModel *models.Component) []string {
+	leaks := []string{}
+
+	// the container
+	if componentModel.ID != "" {
+		if _, err := docker.GetContainer(componentModel.ID); err == nil {
+			leaks = append(leaks, fmt.Sprintf("docker container %s still exists", componentModel.ID))
+		}
+	}
+
+	// the ip, unless it belongs to the app
+	ip := componentModel.IPAddr()
+	if ip != "" && !ownsIP(appModel, componentModel) {
+		if dhcp.Reserved(net.ParseIP(ip)) {
+			leaks = append(leaks, fmt.Sprintf("ip %s is still reserved", ip))
+		}
+	}
+
+	// the nat rules of the provider that made it reachable
+	if ip != "" {
+		rules, err := provider.NatRules()
+		if err != nil {
+			lumber.Error("component:verifyDestroyed:provider.NatRules(): %s", err.Error())
+		}
+		for _, rule := range rules {
+			if rule.Container == ip || rule.Container == componentModel.InternalIP {
+				leaks = append(leaks, fmt.Sprintf("%s nat rule from %s to %s still exists", rule.Chain, rule.Host, rule.Container))
+			}
+		}
+	}
+
+	// the evars, reloaded to make sure the purge was persisted. DATA_DB_ is
+	// the prefix, DATA_DB2_ belongs to another component.
+	prefix := componentModel.EvarPrefix() + "_"
+	savedApp, _ := models.FindAppBySlug(appModel.EnvID, appModel.Name)
+	for key := range savedApp.Evars {
+		if strings.HasPrefix(key, prefix) {
+			leaks = append(leaks, fmt.Sprintf("evar %s still exists", key))
+		}
+	}
+
+	// the model
+	if _, err := models.FindComponentBySlug(componentModel.AppID, componentModel.Name); err == nil {
+		leaks = append(leaks, "component record still exists")
+	}
+
+	return leaks
+}
+
 // destroyContainer destroys a docker container associated with this component
 func destroyContainer(id string) error {
 	display.StartTask("Destroying docker container")
@@ -60,6 +145,9 @@ func destroyContainer(id string) error {
 		// return util.ErrorAppend(err, "failed to remove docker container")
 	}
 
+	// the output of its hooks goes with it
+	logs.RemoveHookLog(id)
+
 	return nil
 }
 
@@ -74,7 +162,7 @@ func detachNetwork(appModel *models.App, componentModel *models.Component) error
 
 	// return the external IP
 	// don't return the external IP if this is portal
-	if componentModel.Name != "portal" && appModel.LocalIPs[componentModel.Name] == "" {
+	if !ownsIP(appModel, componentModel) {
 		ip := net.ParseIP(componentModel.IPAddr())
 		if err := dhcp.ReturnIP(ip); err != nil {
 			lumber.Error("component:detachNetwork:dhcp.ReturnIP(%s): %s", ip, err.Error())
@@ -0,0 +1,45 @@
+package component
+
+import (
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/imagelock"
+	"github.com/nanobox-io/nanobox/util/images"
+)
+
+// checkDigest warns when the container runs another image than the one
+// nanobox.lock pins or, without a pin, than the one its tag points at now
+func checkDigest(componentModel *models.Component) {
+	if componentModel.ImageDigest == "" {
+		return
+	}
+
+	lock, _ := imagelock.Load(config.LocalDir())
+	expected, pinned := lock.Digest(componentModel.Image)
+	if !pinned {
+		expected = images.Digest(componentModel.Image)
+	}
+
+	if expected == "" || expected == componentModel.ImageDigest {
+		return
+	}
+
+	if pinned {
+		display.Warn("%s runs %s, %s pins %s, recreate it with 'nanobox destroy' to run the pinned image\n",
+			componentModel.Image, shortDigest(componentModel.ImageDigest), imagelock.File, shortDigest(expected))
+		return
+	}
+
+	display.Warn("%s moved from %s to %s since %s was created, pin it with 'nanobox fetch --pin'\n",
+		componentModel.Image, shortDigest(componentModel.ImageDigest), shortDigest(expected), componentModel.Name)
+}
+
+// shortDigest returns the start of a digest, enough to tell them apart
+func shortDigest(digest string) string {
+	if len(digest) > 19 {
+		return digest[:19]
+	}
+
+	return digest
+}
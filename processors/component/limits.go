@@ -0,0 +1,49 @@
+package component
+
+import (
+	"github.com/docker/engine-api/types/container"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	container_generator "github.com/nanobox-io/nanobox/generators/containers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+)
+
+// Limits returns the cpu and memory limits of a component, from its node in
+// the boxfile the app is deployed with
+func Limits(appModel *models.App, componentModel *models.Component) (container.Resources, error) {
+	box, err := appBoxfile(appModel)
+	if err != nil {
+		return container.Resources{}, util.ErrorAppend(err, "failed to load the boxfile")
+	}
+
+	resources, err := container_generator.Resources(box.Node(componentModel.Name))
+	if err != nil {
+		return resources, util.Errorf("[USER] invalid resource limits for %s: %s", componentModel.Name, err.Error())
+	}
+
+	return resources, nil
+}
+
+// SetCPULimit applies the cpu limit to a container as soon as it's created.
+// The memory limit is part of the config the container is created with, the
+// docker client's config has no cpu quota.
+func SetCPULimit(containerID string, resources container.Resources) error {
+	// no limit configured
+	if resources.CPUQuota == 0 {
+		return nil
+	}
+
+	updateConfig := container.UpdateConfig{Resources: container.Resources{
+		CPUPeriod: resources.CPUPeriod,
+		CPUQuota:  resources.CPUQuota,
+	}}
+	if err := docker.Client.ContainerUpdate(context.Background(), containerID, updateConfig); err != nil {
+		lumber.Error("component:SetCPULimit:docker.Client.ContainerUpdate(%s, %+v): %s", containerID, updateConfig, err.Error())
+		return util.ErrorAppend(err, "failed to set the cpu limit")
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package component
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nanobox-io/golang-docker-client"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// healthTimeout is how long we wait for a dependency to become healthy
+const healthTimeout = 2 * time.Minute
+
+// dependencies reads the depends_on of each component from the boxfile
+func dependencies(box boxfile.Boxfile, names []string) map[string][]string {
+	deps := map[string][]string{}
+
+	for _, name := range names {
+		deps[name] = box.Node(name).StringSliceValue("depends_on")
+	}
+
+	return deps
+}
+
+// startOrder groups the components into levels where every component only
+// depends on components of earlier levels. The components of a level can be
+// started in parallel.
+func startOrder(names []string, deps map[string][]string) ([][]string, error) {
+	known := map[string]bool{}
+	for _, name := range names {
+		known[name] = true
+	}
+
+	// count the unmet dependencies of each component
+	pending := map[string]int{}
+	dependents := map[string][]string{}
+	for _, name := range names {
+		pending[name] = 0
+		for _, dep := range deps[name] {
+			if !known[dep] {
+				return nil, util.Errorf("[USER] %s depends on %s, which is not a component in the boxfile", name, dep)
+			}
+			if dep == name {
+				return nil, util.Errorf("[USER] %s depends on itself", name)
+			}
+			pending[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	levels := [][]string{}
+	for len(pending) > 0 {
+		level := []string{}
+		for name, count := range pending {
+			if count == 0 {
+				level = append(level, name)
+			}
+		}
+
+		// everything left depends on something else that is left
+		if len(level) == 0 {
+			cycle := []string{}
+			for name := range pending {
+				cycle = append(cycle, name)
+			}
+			sort.Strings(cycle)
+			return nil, util.Errorf("[USER] circular depends_on between %s", strings.Join(cycle, ", "))
+		}
+
+		sort.Strings(level)
+		for _, name := range level {
+			delete(pending, name)
+			for _, dependent := range dependents[name] {
+				pending[dependent]--
+			}
+		}
+
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+// flatten returns the components of all levels in start order
+func flatten(levels [][]string) []string {
+	names := []string{}
+	for _, level := range levels {
+		names = append(names, level...)
+	}
+
+	return names
+}
+
+// waitDependencies waits for the dependencies of a component to be healthy
+func waitDependencies(appModel *models.App, name string, deps []string) error {
+	for _, dep := range deps {
+		depModel, err := models.FindComponentBySlug(appModel.ID, dep)
+		if err != nil || depModel.ID == "" {
+			return util.Errorf("%s depends on %s, which has not been created", name, dep)
+		}
+
+		display.StartTask("Waiting for %s", dep)
+		if err := waitHealthy(dep, depModel.ID); err != nil {
+			display.ErrorTask()
+			return util.ErrorAppend(err, "dependency of %s is not healthy", name)
+		}
+		display.StopTask()
+	}
+
+	return nil
+}
+
+// waitHealthy waits for the container to be running and, if the image
+// defines a docker HEALTHCHECK, for it to report healthy
+func waitHealthy(name, containerID string) error {
+	deadline := time.Now().Add(healthTimeout)
+
+	for {
+		container, err := docker.GetContainer(containerID)
+		if err == nil && container.State.Status == "running" {
+			if container.State.Health == nil || container.State.Health.Status == "healthy" {
+				return nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s did not become healthy within %s", name, healthTimeout)
+		}
+
+		<-time.After(time.Second)
+	}
+}
@@ -0,0 +1,48 @@
+package component
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStartOrder(t *testing.T) {
+	names := []string{"data.db", "data.cache", "data.queue", "data.search"}
+	deps := map[string][]string{
+		"data.queue":  {"data.db", "data.cache"},
+		"data.search": {"data.queue"},
+	}
+
+	levels, err := startOrder(names, deps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{
+		{"data.cache", "data.db"},
+		{"data.queue"},
+		{"data.search"},
+	}
+
+	if !reflect.DeepEqual(levels, expected) {
+		t.Errorf("expected %v got %v", expected, levels)
+	}
+}
+
+func TestStartOrderErrors(t *testing.T) {
+	names := []string{"data.db", "data.cache"}
+
+	cycle := map[string][]string{
+		"data.db":    {"data.cache"},
+		"data.cache": {"data.db"},
+	}
+	if _, err := startOrder(names, cycle); err == nil {
+		t.Errorf("expected an error for a circular dependency")
+	}
+
+	unknown := map[string][]string{
+		"data.db": {"data.missing"},
+	}
+	if _, err := startOrder(names, unknown); err == nil {
+		t.Errorf("expected an error for an unknown dependency")
+	}
+}
@@ -0,0 +1,73 @@
+package component
+
+import (
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// PauseAll freezes the containers of the app's components. They keep their
+// IPs, memory and connections, and pick up where they left off when they're
+// started again.
+func PauseAll(appModel *models.App) error {
+	componentModels, err := appModel.Components()
+	if err != nil {
+		lumber.Error("component:PauseAll:models.App{ID:%s}.Components() %s", appModel.ID, err.Error())
+		return util.ErrorAppend(err, "unable to retrieve components")
+	}
+
+	if len(componentModels) == 0 {
+		return nil
+	}
+
+	display.StartTask("Pausing components")
+	defer display.StopTask()
+
+	for _, componentModel := range componentModels {
+		// the other apps of the workspace still need it
+		if sharedInUse(appModel, componentModel) {
+			continue
+		}
+
+		if !isComponentRunning(componentModel.ID) {
+			continue
+		}
+
+		if err := containerPause(componentModel.ID); err != nil {
+			display.ErrorTask()
+			lumber.Error("component:PauseAll:docker.Client.ContainerPause(%s): %s", componentModel.ID, err.Error())
+			return util.ErrorAppend(err, "unable to pause component(%s)", componentModel.Name)
+		}
+	}
+
+	return nil
+}
+
+// isComponentPaused returns true if the container of the component is paused
+func isComponentPaused(containerID string) bool {
+	return containerStatus(containerID) == "paused"
+}
+
+// unpauseContainer lets a paused container run again
+func unpauseContainer(id string) error {
+	if err := containerUnpause(id); err != nil {
+		lumber.Error("component:unpauseContainer:docker.Client.ContainerUnpause(%s): %s", id, err.Error())
+		return util.ErrorAppend(err, "failed to resume docker container")
+	}
+
+	return nil
+}
+
+// dockerContainerPause pauses a container
+func dockerContainerPause(id string) error {
+	return docker.Client.ContainerPause(context.Background(), id)
+}
+
+// dockerContainerUnpause unpauses a container
+func dockerContainerUnpause(id string) error {
+	return docker.Client.ContainerUnpause(context.Background(), id)
+}
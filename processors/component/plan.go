@@ -0,0 +1,141 @@
+package component
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/nanobox-io/nanobox-boxfile"
+)
+
+// the actions of a deploy plan
+const (
+	PlanCreate  = "create"
+	PlanUpdate  = "update"
+	PlanReplace = "replace"
+	PlanDestroy = "destroy"
+)
+
+// Change is something a deploy does to a component or an evar
+type Change struct {
+	Action string // see the Plan* actions
+	Name   string
+	Detail string
+}
+
+// PlanComponents compares the boxfile that is about to be deployed with the
+// deployed boxfile and returns what a deploy would do to the components.
+// Data components that changed are replaced, code components always run the
+// new build.
+func PlanComponents(built, deployed boxfile.Boxfile) []Change {
+	changes := []Change{}
+
+	for _, name := range sortedNodes(built, "data") {
+		newNode := built.Node(name)
+		oldNode := deployed.Node(name)
+
+		switch {
+		case !oldNode.Valid:
+			changes = append(changes, Change{PlanCreate, name, newNode.StringValue("image")})
+		case !newNode.Equal(oldNode):
+			changes = append(changes, Change{PlanReplace, name, "the config changed"})
+		}
+	}
+
+	for _, name := range sortedNodes(built, "code") {
+		if deployed.Node(name).Valid {
+			changes = append(changes, Change{PlanUpdate, name, "new build"})
+		} else {
+			changes = append(changes, Change{PlanCreate, name, "new build"})
+		}
+	}
+
+	for _, group := range []string{"data", "code"} {
+		for _, name := range sortedNodes(deployed, group) {
+			if !built.Node(name).Valid {
+				changes = append(changes, Change{PlanDestroy, name, ""})
+			}
+		}
+	}
+
+	return changes
+}
+
+// PlanEvars compares the evars the app is running with against the evars a
+// deploy would start it with. The values are left out of the changes.
+func PlanEvars(running, next map[string]string) []Change {
+	changes := []Change{}
+
+	keys := []string{}
+	for key := range next {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		val, ok := running[key]
+		switch {
+		case !ok:
+			changes = append(changes, Change{PlanCreate, key, ""})
+		case val != next[key]:
+			changes = append(changes, Change{PlanUpdate, key, ""})
+		}
+	}
+
+	keys = []string{}
+	for key := range running {
+		if _, ok := next[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		changes = append(changes, Change{PlanDestroy, key, ""})
+	}
+
+	return changes
+}
+
+// the markers of the actions, as terraform prints them
+var planMarkers = map[string]string{
+	PlanCreate:  "+",
+	PlanUpdate:  "~",
+	PlanReplace: "-/+",
+	PlanDestroy: "-",
+}
+
+// PrintPlan writes the changes of a deploy plan to w
+func PrintPlan(w io.Writer, components, evars []Change) {
+	counts := map[string]int{}
+
+	fmt.Fprintln(w)
+
+	if len(components) == 0 && len(evars) == 0 {
+		fmt.Fprintf(w, "No changes, the app is up-to-date.\n\n")
+		return
+	}
+
+	for _, change := range components {
+		fmt.Fprintf(w, "  %3s %-20s %s\n", planMarkers[change.Action], change.Name, change.Detail)
+		counts[change.Action]++
+	}
+
+	if len(evars) > 0 {
+		fmt.Fprintf(w, "\nEvars:\n")
+		for _, change := range evars {
+			fmt.Fprintf(w, "  %3s %s\n", planMarkers[change.Action], change.Name)
+		}
+	}
+
+	fmt.Fprintf(w, "\nPlan: %d to create, %d to update, %d to replace, %d to destroy.\n\n",
+		counts[PlanCreate], counts[PlanUpdate], counts[PlanReplace], counts[PlanDestroy])
+}
+
+// sortedNodes returns the nodes of a group in the boxfile in order
+func sortedNodes(box boxfile.Boxfile, group string) []string {
+	nodes := box.Nodes(group)
+	sort.Strings(nodes)
+
+	return nodes
+}
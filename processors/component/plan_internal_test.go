@@ -0,0 +1,57 @@
+package component
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nanobox-io/nanobox-boxfile"
+)
+
+func TestPlanComponents(t *testing.T) {
+	deployed := boxfile.New([]byte(`
+web.main:
+  start: node server.js
+worker.jobs:
+  start: node jobs.js
+data.db:
+  image: nanobox/postgresql:9.5
+data.old:
+  image: nanobox/redis:3.0
+`))
+
+	built := boxfile.New([]byte(`
+web.main:
+  start: node server.js
+data.db:
+  image: nanobox/postgresql:9.6
+data.cache:
+  image: nanobox/redis:3.0
+`))
+
+	expected := []Change{
+		{PlanCreate, "data.cache", "nanobox/redis:3.0"},
+		{PlanReplace, "data.db", "the config changed"},
+		{PlanUpdate, "web.main", "new build"},
+		{PlanDestroy, "data.old", ""},
+		{PlanDestroy, "worker.jobs", ""},
+	}
+
+	if changes := PlanComponents(built, deployed); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %v got %v", expected, changes)
+	}
+}
+
+func TestPlanEvars(t *testing.T) {
+	running := map[string]string{"KEEP": "1", "CHANGE": "1", "DROP": "1"}
+	next := map[string]string{"KEEP": "1", "CHANGE": "2", "ADD": "1"}
+
+	expected := []Change{
+		{PlanCreate, "ADD", ""},
+		{PlanUpdate, "CHANGE", ""},
+		{PlanDestroy, "DROP", ""},
+	}
+
+	if changes := PlanEvars(running, next); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %v got %v", expected, changes)
+	}
+}
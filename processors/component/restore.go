@@ -0,0 +1,43 @@
+package component
+
+import (
+	"io"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// Restore re-applies the data and users of an archived component onto a
+// freshly provisioned component
+func Restore(appModel *models.App, componentModel, archived *models.Component, data io.Reader) error {
+	display.OpenContext("Restoring %s", componentModel.Label)
+	defer display.CloseContext()
+
+	// copy the archived data back into the container
+	if data != nil {
+		if err := ImportData(componentModel, data); err != nil {
+			return util.ErrorAppend(err, "failed to import component data")
+		}
+	}
+
+	// the restored data was written with the archived credentials, so we
+	// bring those back rather than the ones generated during setup
+	componentModel.Plan.Users = archived.Plan.Users
+	componentModel.Plan.DefaultUser = archived.Plan.DefaultUser
+	componentModel.CredentialVersion = archived.CredentialVersion
+	// the component is saved with its evars
+	if err := componentModel.GenerateEvars(appModel); err != nil {
+		lumber.Error("component:Restore:models.Component.GenerateEvars(%+v): %s", appModel, err.Error())
+		return util.ErrorAppend(err, "failed to generate the component evars")
+	}
+
+	// re-run the configure hooks so the service picks up the users
+	if err := configureComponent(appModel, componentModel); err != nil {
+		return util.ErrorAppend(err, "failed to configure component")
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package component
+
+import (
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/locker"
+)
+
+// RotateCredentials generates new passwords for the users of a component,
+// updates the app evars and reconfigures the service with them
+func RotateCredentials(appModel *models.App, componentModel *models.Component) error {
+	locker.LocalLock()
+	defer locker.LocalUnlock()
+
+	if componentModel.State != "active" {
+		return util.Errorf("[USER] %s is not active", componentModel.Name)
+	}
+
+	if len(componentModel.Plan.Users) == 0 {
+		return util.Errorf("[USER] %s has no users to rotate", componentModel.Name)
+	}
+
+	display.OpenContext("Rotating %s credentials", componentModel.Label)
+	defer display.CloseContext()
+
+	// the container must be running for the hooks to apply the new passwords
+	if err := Start(componentModel); err != nil {
+		return util.ErrorAppend(err, "failed to start component")
+	}
+
+	// bump the version so deterministic policies produce new passwords too
+	componentModel.CredentialVersion++
+	if err := componentModel.GeneratePasswords(); err != nil {
+		lumber.Error("component:RotateCredentials:models.Component.GeneratePasswords(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to generate the passwords")
+	}
+
+	// the configure hook sets the passwords of the plan users. Nothing is
+	// saved until it did, so the stored credentials keep matching the
+	// service when it fails.
+	if err := configureComponent(appModel, componentModel); err != nil {
+		return util.ErrorAppend(err, "failed to configure component")
+	}
+
+	// the component is saved with its evars
+	if err := componentModel.GenerateEvars(appModel); err != nil {
+		lumber.Error("component:RotateCredentials:models.Component.GenerateEvars(%+v): %s", appModel, err.Error())
+		return util.ErrorAppend(err, "failed to generate the component evars")
+	}
+
+	return nil
+}
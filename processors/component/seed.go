@@ -0,0 +1,139 @@
+package component
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"github.com/nanobox-io/nanobox-boxfile"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// seedDir is where the seed file is copied to inside the component
+const seedDir = "/tmp/nanobox-seed"
+
+// seedComponent loads the seed file declared in the component's boxfile
+// config into the freshly configured component, ie:
+//
+//	data.db:
+//	  config:
+//	    seed: db/fixtures.sql
+//
+// SQL files are loaded with the database client of the component, any other
+// file is run as a shell script.
+func seedComponent(appModel *models.App, componentModel *models.Component) error {
+	envModel, err := models.FindEnvByID(componentModel.EnvID)
+	if err != nil {
+		lumber.Error("component:seedComponent:models.FindEnvByID(%s): %s", componentModel.EnvID, err.Error())
+		return util.ErrorAppend(err, "failed to load env")
+	}
+
+	built, _ := Boxfile(envModel, appModel)
+	box := boxfile.New([]byte(built))
+	seed := box.Node(componentModel.Name).Node("config").StringValue("seed")
+
+	// nothing to seed
+	if seed == "" {
+		return nil
+	}
+
+	display.StartTask("Seeding data")
+	defer display.StopTask()
+
+	data, err := ioutil.ReadFile(filepath.Join(envModel.Directory, seed))
+	if err != nil {
+		display.ErrorTask()
+		return util.Errorf("[USER] failed to read seed file (%s): %s", seed, err.Error())
+	}
+
+	if err := copySeed(componentModel.ID, filepath.Base(seed), data); err != nil {
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to copy seed file into component")
+	}
+
+	command, err := seedCommand(componentModel, filepath.Base(seed))
+	if err != nil {
+		display.ErrorTask()
+		return err
+	}
+
+	stream := display.NewStreamer("info")
+	if out, err := util.DockerExec(componentModel.ID, "root", "sh", []string{"-c", command}, stream); err != nil {
+		lumber.Error("component:seedComponent:util.DockerExec(%s, %s): %s: %s", componentModel.ID, command, out, err.Error())
+		display.ErrorTask()
+		return util.Errorf("[USER] failed to seed %s from %s: %s", componentModel.Name, seed, err.Error())
+	}
+
+	return nil
+}
+
+// copySeed copies the seed file into the component
+func copySeed(containerID, name string, data []byte) error {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+
+	headers := []*tar.Header{
+		{Name: filepath.Base(seedDir) + "/", Mode: 0755, Typeflag: tar.TypeDir, ModTime: time.Now()},
+		{Name: filepath.Base(seedDir) + "/" + name, Mode: 0755, Size: int64(len(data)), ModTime: time.Now()},
+	}
+
+	if err := tw.WriteHeader(headers[0]); err != nil {
+		return err
+	}
+
+	if err := tw.WriteHeader(headers[1]); err != nil {
+		return err
+	}
+
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	err := docker.Client.CopyToContainer(context.Background(), containerID, filepath.Dir(seedDir), buf, types.CopyToContainerOptions{})
+	if err != nil {
+		lumber.Error("component:copySeed:docker.Client.CopyToContainer(%s): %s", containerID, err.Error())
+	}
+
+	return err
+}
+
+// seedCommand returns the command that loads the seed file
+func seedCommand(componentModel *models.Component, name string) (string, error) {
+	path := seedDir + "/" + name
+
+	if !strings.HasSuffix(name, ".sql") {
+		return fmt.Sprintf("sh %s", path), nil
+	}
+
+	user := componentModel.Plan.DefaultUser
+	password := ""
+	for _, planUser := range componentModel.Plan.Users {
+		if planUser.Username == user {
+			password = planUser.Password
+		}
+	}
+
+	switch {
+	case strings.Contains(componentModel.Image, "postgresql"):
+		return fmt.Sprintf("PGPASSWORD='%s' /data/bin/psql -h 127.0.0.1 -U %s -d gonano -v ON_ERROR_STOP=1 -f %s", password, user, path), nil
+	case strings.Contains(componentModel.Image, "mysql"), strings.Contains(componentModel.Image, "mariadb"):
+		return fmt.Sprintf("/data/bin/mysql -h 127.0.0.1 -u %s -p'%s' gonano < %s", user, password, path), nil
+	}
+
+	return "", util.Errorf("[USER] sql seed files are not supported for %s, use a shell script instead", componentModel.Image)
+}
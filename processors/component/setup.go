@@ -1,8 +1,6 @@
 package component
 
 import (
-	"time"
-
 	"github.com/jcelliott/lumber"
 	"github.com/nanobox-io/golang-docker-client"
 
@@ -13,6 +11,7 @@ import (
 	"github.com/nanobox-io/nanobox/util/dhcp"
 	"github.com/nanobox-io/nanobox/util/display"
 	"github.com/nanobox-io/nanobox/util/hookit"
+	"github.com/nanobox-io/nanobox/util/images"
 )
 
 // Setup sets up the component container and model data
@@ -29,13 +28,20 @@ func Setup(appModel *models.App, componentModel *models.Component) error {
 		return nil
 	}
 
+	// another app of the workspace may have set up the shared component
+	if componentModel.Workspace != "" {
+		if shared, _ := models.FindSharedComponent(componentModel.Workspace, componentModel.Name); !shared.IsNew() {
+			return attachShared(appModel, componentModel, shared)
+		}
+	}
+
 	display.OpenContext(componentModel.Label)
 	defer display.CloseContext()
 
 	// if the image was not provided
 	if componentModel.Image == "" {
 		// extract the image from the boxfile node
-		image, err := componentImage(componentModel)
+		image, err := componentImage(appModel, componentModel)
 		if err != nil {
 			lumber.Error("component:Setup:boxfile.ComponentImage(%+v): %s", componentModel, err.Error())
 			return util.ErrorAppend(err, "unable to retrieve component image")
@@ -53,12 +59,8 @@ func Setup(appModel *models.App, componentModel *models.Component) error {
 
 		// pull the component image
 		display.StartTask("Pulling %s image", componentModel.Image)
-		imagePull := func() error {
-			_, err := docker.ImagePull(componentModel.Image, dockerPercent)
-			return err
-		}
-		if err := util.Retry(imagePull, 5, time.Second); err != nil {
-			lumber.Error("component:Setup:docker.ImagePull(%s, nil): %s", componentModel.Image, err.Error())
+		if err := images.Download(componentModel.Image, dockerPercent); err != nil {
+			lumber.Error("component:Setup:images.Download(%s): %s", componentModel.Image, err.Error())
 			// remove the component because it doesnt need to be cleaned up at this point
 			componentModel.Delete()
 			display.ErrorTask()
@@ -75,6 +77,14 @@ func Setup(appModel *models.App, componentModel *models.Component) error {
 	display.StartTask("Starting docker container")
 	config := container_generator.ComponentConfig(componentModel)
 
+	// the resource limits from the boxfile
+	resources, err := Limits(appModel, componentModel)
+	if err != nil {
+		display.ErrorTask()
+		return err
+	}
+	container_generator.SetLimits(&config, resources)
+
 	// remove any container that may have been created with this name befor
 	// this can happen if the process is killed after the
 	// container was created but before our db model was saved
@@ -88,13 +98,18 @@ func Setup(appModel *models.App, componentModel *models.Component) error {
 	}
 	display.StopTask()
 
-	// persist the container ID
+	// persist the container ID, and the digest of the image it runs
 	componentModel.ID = container.ID
+	componentModel.ImageDigest = images.Digest(componentModel.Image)
 	if err := componentModel.Save(); err != nil {
 		lumber.Error("component:Setup:models.Component.Save()")
 		return util.ErrorAppend(err, "failed to persist container ID")
 	}
 
+	if err := SetCPULimit(componentModel.ID, resources); err != nil {
+		return err
+	}
+
 	// plan the component
 	if err := planComponent(appModel, componentModel); err != nil {
 		return err
@@ -104,6 +119,11 @@ func Setup(appModel *models.App, componentModel *models.Component) error {
 		return err
 	}
 
+	// load the fixture data, if any
+	if err := seedComponent(appModel, componentModel); err != nil {
+		return err
+	}
+
 	// set state as active
 	componentModel.State = "active"
 	if err := componentModel.Save(); err != nil {
@@ -111,6 +131,11 @@ func Setup(appModel *models.App, componentModel *models.Component) error {
 		return util.ErrorAppend(err, "failed to set component state")
 	}
 
+	// the other apps of the workspace use this one
+	if componentModel.Workspace != "" {
+		return shareComponent(appModel, componentModel)
+	}
+
 	return nil
 }
 
@@ -124,7 +149,7 @@ func reserveIP(appModel *models.App, componentModel *models.Component) error {
 		// first let's see if our local IP was reserved during app creation
 		if componentModel.Name == "portal" {
 			componentModel.IP = appModel.LocalIPs["env"]
-		} else if appModel.LocalIPs[componentModel.Name] != "" {
+		} else if ownsIP(appModel, componentModel) {
 
 			// assign the localIP from the pre-generated app cache
 			componentModel.IP = appModel.LocalIPs[componentModel.Name]
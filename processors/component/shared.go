@@ -0,0 +1,137 @@
+package component
+
+import (
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// attachShared points the component of an app at the container of a shared
+// component that another app already set up, with its credentials in the
+// app's evars
+func attachShared(appModel *models.App, componentModel *models.Component, shared *models.SharedComponent) error {
+	display.OpenContext(componentModel.Label)
+	defer display.CloseContext()
+
+	display.StartTask("Using the %s of workspace %s", componentModel.Name, componentModel.Workspace)
+	defer display.StopTask()
+
+	if componentModel.Image != "" && componentModel.Image != shared.Component.Image {
+		display.ErrorTask()
+		return util.Errorf("[USER] %s of workspace %s runs %s, not %s", componentModel.Name, componentModel.Workspace, shared.Component.Image, componentModel.Image)
+	}
+
+	componentModel.ID = shared.Component.ID
+	componentModel.Image = shared.Component.Image
+	componentModel.IP = shared.Component.IP
+	componentModel.InternalIP = shared.Component.InternalIP
+	componentModel.Plan = shared.Component.Plan
+	componentModel.CredentialVersion = shared.Component.CredentialVersion
+
+	if err := componentModel.GenerateEvars(appModel); err != nil {
+		display.ErrorTask()
+		lumber.Error("component:attachShared:models.Component.GenerateEvars(%+v): %s", appModel, err.Error())
+		return util.ErrorAppend(err, "failed to generate the component evars")
+	}
+
+	componentModel.State = "active"
+	if err := componentModel.Save(); err != nil {
+		display.ErrorTask()
+		lumber.Error("component:attachShared:models.Component.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to set component state")
+	}
+
+	shared.Use(appModel.ID)
+	if err := shared.Save(); err != nil {
+		display.ErrorTask()
+		lumber.Error("component:attachShared:models.SharedComponent.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to save the shared component")
+	}
+
+	return nil
+}
+
+// shareComponent records a component an app set up as the shared component
+// of its workspace
+func shareComponent(appModel *models.App, componentModel *models.Component) error {
+	shared := &models.SharedComponent{
+		Workspace: componentModel.Workspace,
+		Name:      componentModel.Name,
+		Component: *componentModel,
+	}
+	shared.Use(appModel.ID)
+
+	if err := shared.Save(); err != nil {
+		lumber.Error("component:shareComponent:models.SharedComponent.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to save the shared component")
+	}
+
+	return nil
+}
+
+// releaseShared releases the shared component an app uses. It returns true
+// when other apps still use it, the container is left alone then.
+func releaseShared(appModel *models.App, componentModel *models.Component) (bool, error) {
+	if componentModel.Workspace == "" {
+		return false, nil
+	}
+
+	shared, _ := models.FindSharedComponent(componentModel.Workspace, componentModel.Name)
+
+	// the container of a component that failed before it was shared only
+	// belongs to this app
+	if shared.IsNew() || shared.Component.ID != componentModel.ID {
+		return false, nil
+	}
+
+	if remaining := shared.Release(appModel.ID); remaining > 0 {
+		if err := shared.Save(); err != nil {
+			lumber.Error("component:releaseShared:models.SharedComponent.Save(): %s", err.Error())
+			return true, util.ErrorAppend(err, "failed to save the shared component")
+		}
+
+		display.StartTask("Leaving it to %d other app(s) of workspace %s", remaining, componentModel.Workspace)
+		display.StopTask()
+
+		return true, nil
+	}
+
+	if err := shared.Delete(); err != nil {
+		lumber.Error("component:releaseShared:models.SharedComponent.Delete(): %s", err.Error())
+		return false, util.ErrorAppend(err, "failed to delete the shared component")
+	}
+
+	return false, nil
+}
+
+// sharedInUse returns true if an app other than this one uses the shared
+// component and is running
+func sharedInUse(appModel *models.App, componentModel *models.Component) bool {
+	if componentModel.Workspace == "" {
+		return false
+	}
+
+	shared, _ := models.FindSharedComponent(componentModel.Workspace, componentModel.Name)
+
+	apps, _ := models.AllAppsByStatus("up")
+	for _, app := range apps {
+		if app.ID != appModel.ID && shared.UsedBy(app.ID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ownsIP returns true if the ip of the component was reserved with the app,
+// it's released with the app instead of the component. A shared component
+// outlives the app, so it always reserves its own.
+func ownsIP(appModel *models.App, componentModel *models.Component) bool {
+	if componentModel.Name == "portal" {
+		return true
+	}
+
+	return componentModel.Workspace == "" && appModel.LocalIPs[componentModel.Name] != ""
+}
@@ -2,7 +2,6 @@ package component
 
 import (
 	"github.com/jcelliott/lumber"
-	"github.com/nanobox-io/golang-docker-client"
 
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/util"
@@ -20,26 +19,35 @@ func Start(componentModel *models.Component) error {
 	display.OpenContext(componentModel.Label)
 	defer display.CloseContext()
 
-	// make sure the component is active
-	if componentModel.State != "active" {
-		return util.Errorf("tried to start an inactive component")
-	}
+	// the image may have moved on since the container was created
+	checkDigest(componentModel)
 
-	// start the container
-	if err := startContainer(componentModel.ID); err != nil {
+	display.StartTask("Start docker container")
+	defer display.StopTask()
+
+	if err := start(componentModel); err != nil {
+		display.ErrorTask()
 		return err
 	}
 
 	return nil
 }
 
-// startContainer starts the container for this component
-func startContainer(id string) error {
-	display.StartTask("Start docker container")
-	defer display.StopTask()
+// start brings the container of a component up, a suspended one is resumed
+// rather than started. StartAll starts every component through it too.
+func start(componentModel *models.Component) error {
+	// make sure the component is active
+	if componentModel.State != "active" {
+		return util.Errorf("tried to start an inactive component (%s)", componentModel.Name)
+	}
+
+	// a suspended component is resumed rather than started
+	if isComponentPaused(componentModel.ID) {
+		return unpauseContainer(componentModel.ID)
+	}
 
-	if err := docker.ContainerStart(id); err != nil {
-		lumber.Error("component:Start:docker.ContainerStart(%s): %s", id, err.Error())
+	if err := containerStart(componentModel.ID); err != nil {
+		lumber.Error("component:start:docker.ContainerStart(%s): %s", componentModel.ID, err.Error())
 		return util.ErrorAppend(err, "failed to start docker container")
 	}
 
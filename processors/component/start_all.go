@@ -1,14 +1,21 @@
 package component
 
 import (
+	"strings"
+	"sync"
+
 	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/nanobox-boxfile"
 
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/events"
 )
 
-// StartAll starts all app components
+// StartAll starts all app components. Components are started in the order
+// of their depends_on, components without dependencies between them are
+// started in parallel.
 func StartAll(a *models.App) error {
 
 	// get all the components that belong to this app
@@ -25,10 +32,91 @@ func StartAll(a *models.App) error {
 	display.OpenContext("Starting components")
 	defer display.CloseContext()
 
-	// start each component
+	byName := map[string]*models.Component{}
+	names := []string{}
 	for _, component := range components {
-		if err := Start(component); err != nil {
-			return util.ErrorAppend(err, "unable to start component(%s)", component.Name)
+		// make sure the component is active
+		if component.State != "active" {
+			return util.Errorf("tried to start an inactive component (%s)", component.Name)
+		}
+		byName[component.Name] = component
+		names = append(names, component.Name)
+	}
+
+	box := boxfile.New([]byte(a.DeployedBoxfile))
+	levels, err := startOrder(names, dependencies(box, names))
+	if err != nil {
+		return err
+	}
+
+	for i, level := range levels {
+		if err := startLevel(a, level, byName); err != nil {
+			return err
+		}
+
+		// the last level has no dependents to wait for
+		if i == len(levels)-1 {
+			continue
+		}
+
+		for _, name := range level {
+			if err := waitHealthy(name, byName[name].ID); err != nil {
+				return util.ErrorAppend(err, "unable to start component(%s)", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// startLevel starts the components of a level in parallel
+func startLevel(a *models.App, level []string, byName map[string]*models.Component) error {
+	progress := display.StartProgress("Starting %s", strings.Join(level, ", "))
+	defer progress.Stop()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(level))
+
+	for i, name := range level {
+		component := byName[name]
+
+		// short-circuit if the container is already running
+		if isComponentRunning(component.ID) {
+			continue
+		}
+
+		// the image may have moved on since the container was created, it's
+		// checked before the parallel starts so the warnings aren't mixed up
+		checkDigest(component)
+
+		line := progress.Line(name)
+		line.Set("starting")
+
+		wg.Add(1)
+		go func(i int, component *models.Component) {
+			defer wg.Done()
+
+			// the same start as a single component, a suspended one is resumed
+			if err := start(component); err != nil {
+				errs[i] = util.ErrorAppend(err, "unable to start component(%s)", component.Name)
+				line.Fail(err)
+				return
+			}
+
+			line.Set("started")
+			line.Done()
+
+			event := events.New(events.ServiceUp, a)
+			event.Component = component.Name
+			events.Emit(event)
+		}(i, component)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
 		}
 	}
 
@@ -0,0 +1,135 @@
+package component
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/models"
+)
+
+// fakeDocker stands in for the containers of the components
+type fakeDocker struct {
+	sync.Mutex
+	statuses map[string]string
+	started  []string
+	unpaused []string
+}
+
+// install replaces the docker calls with the fake, the returned func puts
+// them back
+func (f *fakeDocker) install() func() {
+	status, start, pause, unpause := containerStatus, containerStart, containerPause, containerUnpause
+
+	containerStatus = func(id string) string {
+		f.Lock()
+		defer f.Unlock()
+		return f.statuses[id]
+	}
+	containerStart = func(id string) error {
+		f.Lock()
+		defer f.Unlock()
+		if f.statuses[id] == "paused" {
+			return fmt.Errorf("cannot start a paused container, try unpause instead")
+		}
+		f.statuses[id] = "running"
+		f.started = append(f.started, id)
+		return nil
+	}
+	containerPause = func(id string) error {
+		f.Lock()
+		defer f.Unlock()
+		f.statuses[id] = "paused"
+		return nil
+	}
+	containerUnpause = func(id string) error {
+		f.Lock()
+		defer f.Unlock()
+		f.statuses[id] = "running"
+		f.unpaused = append(f.unpaused, id)
+		return nil
+	}
+
+	return func() {
+		containerStatus, containerStart, containerPause, containerUnpause = status, start, pause, unpause
+	}
+}
+
+func TestStartLevelResumesPaused(t *testing.T) {
+	fake := &fakeDocker{statuses: map[string]string{
+		"db":    "paused",
+		"cache": "exited",
+		"queue": "running",
+	}}
+	defer fake.install()()
+
+	byName := map[string]*models.Component{
+		"data.db":    {ID: "db", Name: "data.db", State: "active"},
+		"data.cache": {ID: "cache", Name: "data.cache", State: "active"},
+		"data.queue": {ID: "queue", Name: "data.queue", State: "active"},
+	}
+
+	if err := startLevel(&models.App{ID: "resume_dev"}, []string{"data.cache", "data.db", "data.queue"}, byName); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(fake.started)
+	if fmt.Sprint(fake.unpaused) != "[db]" || fmt.Sprint(fake.started) != "[cache]" {
+		t.Errorf("expected db unpaused and cache started, got unpaused %v started %v", fake.unpaused, fake.started)
+	}
+
+	for id, status := range fake.statuses {
+		if status != "running" {
+			t.Errorf("expected %s to be running, it's %s", id, status)
+		}
+	}
+}
+
+// the idle watcher suspends an app by pausing its components, the next
+// command starts the app, which has to resume them
+func TestStartAllResumesIdleSuspended(t *testing.T) {
+	fake := &fakeDocker{statuses: map[string]string{
+		"idle-db":    "running",
+		"idle-cache": "running",
+	}}
+	defer fake.install()()
+
+	appModel := &models.App{ID: "idle-test_dev", EnvID: "idle-test", Name: "dev"}
+	for i, name := range []string{"db", "cache"} {
+		componentModel := &models.Component{
+			ID:    "idle-" + name,
+			AppID: appModel.ID,
+			Name:  "data." + name,
+			Type:  "data",
+			State: "active",
+			IP:    fmt.Sprintf("192.168.0.%d", i+2),
+		}
+		if err := componentModel.Save(); err != nil {
+			t.Fatal(err)
+		}
+		defer componentModel.Delete()
+	}
+
+	if err := PauseAll(appModel); err != nil {
+		t.Fatal(err)
+	}
+	for id, status := range fake.statuses {
+		if status != "paused" {
+			t.Fatalf("expected %s to be paused, it's %s", id, status)
+		}
+	}
+
+	if err := StartAll(appModel); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.started) != 0 || len(fake.unpaused) != 2 {
+		t.Errorf("expected both components unpaused, got unpaused %v started %v", fake.unpaused, fake.started)
+	}
+	for id, status := range fake.statuses {
+		if status != "running" {
+			t.Errorf("expected %s to be running, it's %s", id, status)
+		}
+	}
+}
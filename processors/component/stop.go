@@ -11,14 +11,26 @@ import (
 
 // Stop stops the component's docker container
 func Stop(componentModel *models.Component) error {
+	paused := isComponentPaused(componentModel.ID)
+
 	// short-circuit if the process is already stopped
-	if !isComponentRunning(componentModel.ID) {
+	if !paused && !isComponentRunning(componentModel.ID) {
 		return nil
 	}
 
 	display.OpenContext(componentModel.Label)
 	defer display.CloseContext()
 
+	// a paused process can't handle the signal to stop
+	if paused {
+		display.StartTask("Resume docker container")
+		if err := unpauseContainer(componentModel.ID); err != nil {
+			display.ErrorTask()
+			return err
+		}
+		display.StopTask()
+	}
+
 	// stop the docker container
 	if err := stopContainer(componentModel.ID); err != nil {
 		return err
@@ -6,6 +6,7 @@ import (
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/events"
 )
 
 // StopAll stops all app components
@@ -27,9 +28,22 @@ func StopAll(appModel *models.App) error {
 
 	// stop each component
 	for _, componentModel := range componentModels {
+		// the other apps of the workspace still need it
+		if sharedInUse(appModel, componentModel) {
+			continue
+		}
+
+		running := isComponentRunning(componentModel.ID)
+
 		if err := Stop(componentModel); err != nil {
 			return util.ErrorAppend(err, "unable to stop component(%s)", componentModel.Name)
 		}
+
+		if running {
+			event := events.New(events.ServiceDown, appModel)
+			event.Component = componentModel.Name
+			events.Emit(event)
+		}
 	}
 
 	return nil
@@ -1,12 +1,16 @@
 package component
 
 import (
+	"fmt"
+
 	"github.com/jcelliott/lumber"
 	"github.com/nanobox-io/nanobox-boxfile"
 
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/hostmount"
 )
 
 // Sync syncronizes an app's components with the boxfile config
@@ -14,6 +18,12 @@ func Sync(envModel *models.Env, appModel *models.App) error {
 	display.OpenContext("Syncing data components")
 	defer display.CloseContext()
 
+	// the boxfile of the app's environment
+	built, err := Boxfile(envModel, appModel)
+	if err != nil {
+		return err
+	}
+
 	// clean any crufty that may have been left by a failed
 	// sync
 	if err := Clean(appModel); err != nil {
@@ -21,17 +31,17 @@ func Sync(envModel *models.Env, appModel *models.App) error {
 	}
 
 	// purge delta components
-	if err := purgeDeltaComponents(envModel, appModel); err != nil {
+	if err := purgeDeltaComponents(built, appModel); err != nil {
 		return util.ErrorAppend(err, "failed to purge delta components")
 	}
 
 	// provision components
-	if err := provisionComponents(envModel, appModel); err != nil {
+	if err := provisionComponents(built, appModel); err != nil {
 		return util.ErrorAppend(err, "failed to provision components")
 	}
 
 	// update deployed boxfile
-	appModel.DeployedBoxfile = envModel.BuiltBoxfile
+	appModel.DeployedBoxfile = built
 	if err := appModel.Save(); err != nil {
 		lumber.Error("component:Sync:models.App.Save()")
 		return util.ErrorAppend(err, "failed to update deployed boxfile on app")
@@ -41,7 +51,7 @@ func Sync(envModel *models.Env, appModel *models.App) error {
 }
 
 // purgeDeltaComponents purges components that have changed in the boxfile
-func purgeDeltaComponents(envModel *models.Env, appModel *models.App) error {
+func purgeDeltaComponents(built string, appModel *models.App) error {
 
 	display.OpenContext("Removing old")
 	defer display.CloseContext()
@@ -50,7 +60,7 @@ func purgeDeltaComponents(envModel *models.Env, appModel *models.App) error {
 	upToDate := true
 
 	// parse the boxfiles
-	builtBoxfile := boxfile.New([]byte(envModel.BuiltBoxfile))
+	builtBoxfile := boxfile.New([]byte(built))
 	deployedBoxfile := boxfile.New([]byte(appModel.DeployedBoxfile))
 
 	components, err := models.AllComponentsByApp(appModel.ID)
@@ -92,7 +102,7 @@ func purgeDeltaComponents(envModel *models.Env, appModel *models.App) error {
 }
 
 // provisionComponents will provision components from the boxfile
-func provisionComponents(envModel *models.Env, appModel *models.App) error {
+func provisionComponents(built string, appModel *models.App) error {
 	display.OpenContext("Launching new")
 	defer display.CloseContext()
 
@@ -100,12 +110,18 @@ func provisionComponents(envModel *models.Env, appModel *models.App) error {
 	upToDate := true
 
 	// parse the boxfile
-	builtBoxfile := boxfile.New([]byte(envModel.BuiltBoxfile))
+	builtBoxfile := boxfile.New([]byte(built))
 
-	// grab all of the data nodes
+	// grab all of the data nodes, ordered so dependencies come first
 	dataServices := builtBoxfile.Nodes("data")
+	deps := dependencies(builtBoxfile, dataServices)
 
-	for _, name := range dataServices {
+	levels, err := startOrder(dataServices, deps)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range flatten(levels) {
 		// check to see if this component is already active
 		componentModel, _ := models.FindComponentBySlug(appModel.ID, name)
 		if componentModel.State == "active" {
@@ -114,9 +130,24 @@ func provisionComponents(envModel *models.Env, appModel *models.App) error {
 
 		upToDate = false
 
+		// the dependencies need to be up before this component is configured
+		if err := waitDependencies(appModel, name, deps[name]); err != nil {
+			return err
+		}
+
 		componentModel.Name = name
 		componentModel.Label = name
 		componentModel.Image = builtBoxfile.Node(name).StringValue("image")
+		componentModel.RestartPolicy = builtBoxfile.Node(name).StringValue("restart")
+		componentModel.Evars = componentEvars(builtBoxfile.Node(name))
+		componentModel.Workspace = builtBoxfile.Node(name).StringValue("shared")
+		componentModel.Mounts = hostmount.Resolve(builtBoxfile.Node(name).StringSliceValue("mounts"), config.LocalDir())
+
+		// a test run starts from empty data and a preview keeps its own, they
+		// never share a component
+		if appModel.Name == models.TestAppName || appModel.IsPreview() {
+			componentModel.Workspace = ""
+		}
 
 		// setup
 		if err := Setup(appModel, componentModel); err != nil {
@@ -134,6 +165,18 @@ func provisionComponents(envModel *models.Env, appModel *models.App) error {
 	return nil
 }
 
+// componentEvars returns the user defined evars from the config node of a
+// component, ie: data.db.config.evars
+func componentEvars(node boxfile.Boxfile) map[string]string {
+	evars := map[string]string{}
+
+	for key, val := range node.Node("config").Node("evars").Parsed {
+		evars[key] = fmt.Sprintf("%v", val)
+	}
+
+	return evars
+}
+
 // isPlatform will return true if the uid matches a platform service
 func isPlatformUID(uid string) bool {
 	return uid == "portal" || uid == "hoarder" || uid == "mist" || uid == "logvac"
@@ -52,7 +52,7 @@ Have you already read the guide? y/n`, map[string]string{"y": "yes", "n": "no"})
 
 	config := &models.Config{
 		Provider:  "docker-machine",
-		MountType: "native",
+		MountType: models.MountVboxsf,
 		CPUs:      1,
 		RAM:       1,
 	}
@@ -120,7 +120,7 @@ Would you like to enable netfs for faster filesystem access (y/n)?
 -------------------------------------------------------------------
   Note : We HIGHLY recommend (y). Using this option may prompt for password
 
-Answer: `, map[string]string{"y": "netfs", "n": "native"})
+Answer: `, map[string]string{"y": models.MountNFS, "n": models.MountVboxsf})
 	}
 
 	config.Save()
@@ -2,56 +2,102 @@ package processors
 
 import (
 	"fmt"
-	"strconv"
+	"sort"
 
 	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
 )
 
-func ConfigureSet(key, val string) error {
-	config, _ := models.LoadConfig()
-
-	switch key {
-	case "provider":
-		config.Provider = val
-	case "mount-type", "mount_type":
-		config.MountType = val
-	case "netfs_mount_opts", "netfs-mount-opts", "mount_options", "mount-options":
-		config.NetfsMountOpts = val
-	case "cpus", "CPUs":
-		config.CPUs, _ = strconv.Atoi(val)
-	case "ram", "RAM":
-		config.RAM, _ = strconv.Atoi(val)
-	case "disk":
-		config.Disk, _ = strconv.Atoi(val)
-	case "external_network_space", "external-network-space":
-		config.ExternalNetworkSpace = val
-	case "docker_machine_network_space", "docker-machine-network-space":
-		config.DockerMachineNetworkSpace = val
-	case "native_network_space", "native-network-space":
-		config.NativeNetworkSpace = val
-	case "ssh_key", "ssh-key":
-		config.SshKey = val
-	case "ssh_encrypted_keys", "ssh-encrypted-keys", "use_encrypted_keys", "use-encrypted-keys":
-		config.SshEncryptedKeys = val == "true" || val == "t" || val == "1"
-	case "lock_port", "lock-port":
-		config.LockPort, _ = strconv.Atoi(val)
-	case "ci-mode", "ci_mode":
-		config.CIMode = val == "true" || val == "t" || val == "1"
-	case "ci-sync-verbose", "ci_sync_verbose":
-		config.CISyncVerbose = val == "true" || val == "t" || val == "1"
-	case "anonymous":
-		config.Anonymous = val == "true" || val == "t" || val == "1"
-	default:
-		fmt.Printf("'%s' is not a valid key.\n", key)
+// configEntry is a key of the configuration, as it's shown
+type configEntry struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// ConfigureSet sets a key in the global configuration, or in the config of
+// the app in the current directory
+func ConfigureSet(key, val string, app bool) error {
+	if app {
+		if err := models.SetAppConfig(key, val); err != nil {
+			return util.Errorf("[USER] failed to set '%s' in %s: %s", key, config.TeamConfig(), err.Error())
+		}
+
+		fmt.Printf("Successfully set '%s' for the app, commit %s to share it\n", key, config.TeamConfig())
 		return nil
 	}
 
-	err := config.Save()
-	if err == nil {
-		fmt.Printf("Successfully set '%s'\n", key)
-	} else {
+	conf, _ := models.LoadConfig()
+
+	if err := conf.Set(key, val); err != nil {
+		return util.Errorf("[USER] %s", err.Error())
+	}
+
+	if err := conf.Save(); err != nil {
 		fmt.Printf("Failed to set '%s'\n", key)
+		return err
+	}
+
+	fmt.Printf("Successfully set '%s'\n", key)
+
+	// a value set in a layer above the global one still wins
+	name, _ := models.ConfigKey(key)
+	if source := conf.Source(name); source != models.ConfigGlobal && source != models.ConfigDefault {
+		fmt.Printf("Note: '%s' is also set by the %s configuration, which takes precedence\n", name, source)
+	}
+
+	return nil
+}
+
+// ConfigureGet prints the value of a key
+func ConfigureGet(key string) error {
+	conf, _ := models.LoadConfig()
+
+	name, err := models.ConfigKey(key)
+	if err != nil {
+		return util.Errorf("[USER] %s", err.Error())
+	}
+
+	entry := configEntry{Key: name, Value: conf.Values()[name], Source: conf.Source(name)}
+
+	if display.JSON() {
+		return display.PrintJSON(entry)
+	}
+
+	fmt.Println(entry.Value)
+
+	return nil
+}
+
+// ConfigureList prints every key of the configuration, with the layer its
+// value comes from
+func ConfigureList() error {
+	conf, _ := models.LoadConfig()
+
+	values := conf.Values()
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := []configEntry{}
+	for _, key := range keys {
+		entries = append(entries, configEntry{Key: key, Value: values[key], Source: conf.Source(key)})
+	}
+
+	if display.JSON() {
+		return display.PrintJSON(entries)
+	}
+
+	fmt.Println()
+	fmt.Printf("  %-30s %-10s %s\n", "KEY", "SOURCE", "VALUE")
+	for _, entry := range entries {
+		fmt.Printf("  %-30s %-10s %s\n", entry.Key, entry.Source, entry.Value)
 	}
+	fmt.Println()
 
-	return err
+	return nil
 }
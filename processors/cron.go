@@ -0,0 +1,166 @@
+package processors
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	container_generator "github.com/nanobox-io/nanobox/generators/containers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/cron"
+)
+
+// cronJob is a cron entry from a code node in the boxfile, ie:
+//
+//	web.site:
+//	  cron:
+//	    - id: flush_cache
+//	      schedule: '*/5 * * * *'
+//	      command: 'php artisan cache:clear'
+type cronJob struct {
+	ID       string
+	Node     string
+	Command  string
+	Schedule *cron.Schedule
+}
+
+// cronJobs returns the cron jobs declared on the code nodes of the boxfile
+func cronJobs(box boxfile.Boxfile) ([]cronJob, error) {
+	jobs := []cronJob{}
+
+	for _, node := range box.Nodes("code") {
+		entries, _ := box.Node(node).Value("cron").([]interface{})
+
+		for i, entry := range entries {
+			fields := stringMap(entry)
+
+			id := fields["id"]
+			if id == "" {
+				id = fmt.Sprintf("%s.%d", node, i)
+			}
+
+			if fields["command"] == "" {
+				return nil, util.Errorf("[USER] cron job '%s' on %s is missing a command", id, node)
+			}
+
+			schedule, err := cron.Parse(fields["schedule"])
+			if err != nil {
+				return nil, util.Errorf("[USER] cron job '%s' on %s has an invalid schedule: %s", id, node, err.Error())
+			}
+
+			jobs = append(jobs, cronJob{ID: id, Node: node, Command: fields["command"], Schedule: schedule})
+		}
+	}
+
+	return jobs, nil
+}
+
+// stringMap converts a parsed yaml map into a map of strings
+func stringMap(v interface{}) map[string]string {
+	rtn := map[string]string{}
+
+	switch m := v.(type) {
+	case map[string]interface{}:
+		for key, val := range m {
+			rtn[key] = fmt.Sprintf("%v", val)
+		}
+	case map[interface{}]interface{}:
+		for key, val := range m {
+			rtn[fmt.Sprintf("%v", key)] = fmt.Sprintf("%v", val)
+		}
+	}
+
+	return rtn
+}
+
+// startCron runs the boxfile cron jobs inside the dev container until the
+// returned stop function is called. The output of each job is written to a
+// log file, tagged with the job id.
+func startCron(appModel *models.App) (func(), error) {
+	jobs, err := cronJobs(boxfile.New([]byte(appModel.DeployedBoxfile)))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(jobs) == 0 {
+		return func() {}, nil
+	}
+
+	logPath := CronLogPath(appModel)
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, util.ErrorAppend(err, "failed to open the cron log")
+	}
+
+	fmt.Printf("Running %d cron job(s), output is logged to %s\n", len(jobs), logPath)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	go func() {
+		for {
+			// wake up at the start of every minute
+			now := time.Now()
+			next := now.Truncate(time.Minute).Add(time.Minute)
+
+			select {
+			case <-done:
+				return
+			case tick := <-time.After(next.Sub(now)):
+				for _, job := range jobs {
+					if job.Schedule.Matches(tick) {
+						wg.Add(1)
+						go func(job cronJob) {
+							defer wg.Done()
+							runCronJob(job, logFile)
+						}(job)
+					}
+				}
+			}
+		}
+	}()
+
+	stop := func() {
+		close(done)
+		wg.Wait()
+		logFile.Close()
+	}
+
+	return stop, nil
+}
+
+// runCronJob runs a single job in the dev container and logs its output
+func runCronJob(job cronJob, logFile *os.File) {
+	tag := fmt.Sprintf("%s cron[%s]", time.Now().Format(time.RFC3339), job.ID)
+
+	stderr := &bytes.Buffer{}
+	out, err := util.DockerExec(container_generator.DevName(), "gonano", "bash", []string{"-lc", job.Command}, stderr)
+
+	for _, line := range strings.Split(out+stderr.String(), "\n") {
+		if line != "" {
+			fmt.Fprintf(logFile, "%s :: %s\n", tag, line)
+		}
+	}
+
+	if err != nil {
+		lumber.Error("processors:runCronJob:util.DockerExec(%s): %s", job.Command, err.Error())
+		fmt.Fprintf(logFile, "%s :: job failed: %s\n", tag, err.Error())
+	}
+}
+
+// CronLogPath returns the location of the cron log of an app
+func CronLogPath(appModel *models.App) string {
+	dir := filepath.ToSlash(filepath.Join(config.GlobalDir(), "cron"))
+	os.MkdirAll(dir, 0755)
+
+	return filepath.ToSlash(filepath.Join(dir, fmt.Sprintf("%s.log", appModel.ID)))
+}
@@ -6,9 +6,11 @@ import (
 	"github.com/nanobox-io/nanobox/helpers"
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/processors/code"
+	"github.com/nanobox-io/nanobox/processors/component"
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/config"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/events"
 	"github.com/nanobox-io/nanobox/util/odin"
 )
 
@@ -17,6 +19,11 @@ func Deploy(envModel *models.Env, deployConfig DeployConfig) error {
 
 	appID := deployConfig.App
 
+	// the platform doesn't split traffic between builds
+	if deployConfig.Canary > 0 {
+		return util.Errorf("[USER] canary deploys are only available on dry-run")
+	}
+
 	// fetch the remote
 	remote, ok := envModel.Remotes[appID]
 	if ok {
@@ -46,17 +53,38 @@ func Deploy(envModel *models.Env, deployConfig DeployConfig) error {
 		display.FirstDeploy()
 	}
 
+	// the boxfile of the remote's environment
+	box, err := remoteBoxfile(envModel, deployConfig.App)
+	if err != nil {
+		return err
+	}
+
+	// run the before_deploy hooks, the evars of a live app stay on the
+	// platform so only the build environment is available here
+	if err := code.BeforeDeploy(envModel, nil); err != nil {
+		return util.ErrorAppend(err, "failed to run the before_deploy hooks")
+	}
+
+	// the webhooks are told how the deploy ended
+	event := events.Event{Type: events.DeployComplete, App: envModel.Name, Env: deployConfig.App}
+
 	// publish to remote warehouse
 	if err := code.Publish(envModel, warehouseConfig); err != nil {
+		event.Type, event.Error = events.DeployFailed, err.Error()
+		events.Emit(event)
 		return util.ErrorAppend(err, "failed to publish build to app's warehouse")
 	}
 
 	// tell odin what happened
-	if err := odin.Deploy(appID, warehouseConfig.BuildID, envModel.BuiltBoxfile, deployConfig.Message); err != nil {
-		lumber.Error("deploy:odin.Deploy(%s,%s,%s,%s): %s", appID, warehouseConfig.BuildID, envModel.BuiltBoxfile, deployConfig.Message, err.Error())
+	if err := odin.Deploy(appID, warehouseConfig.BuildID, box, deployConfig.Message); err != nil {
+		lumber.Error("deploy:odin.Deploy(%s,%s,%s,%s): %s", appID, warehouseConfig.BuildID, box, deployConfig.Message, err.Error())
+		event.Type, event.Error = events.DeployFailed, err.Error()
+		events.Emit(event)
 		return util.ErrorAppend(err, "failed to deploy code to app")
 	}
 
+	recordDeploy(appID, warehouseConfig.BuildID, box, deployConfig.Message)
+
 	envModel.DeployedID = envModel.BuiltID
 	if err := envModel.Save(); err != nil {
 		lumber.Error("deploy:models:Env:Save(): %s", err.Error())
@@ -65,6 +93,8 @@ func Deploy(envModel *models.Env, deployConfig DeployConfig) error {
 
 	display.DeployComplete()
 
+	events.Emit(event)
+
 	return nil
 }
 
@@ -93,3 +123,20 @@ func getWarehouseConfig(envModel *models.Env, appID string) (warehouseConfig cod
 
 	return
 }
+
+// remoteBoxfile returns the boxfile a remote is deployed with, the overlay
+// of 'nanobox deploy staging' is boxfile.staging.yml. The default remote is
+// production. The evars of a remote stay on the platform, its variables are
+// resolved from the environment of the host.
+func remoteBoxfile(envModel *models.Env, alias string) (string, error) {
+	return component.BoxfileFor(envModel, remoteEnv(alias), nil)
+}
+
+// remoteEnv returns the environment a remote is deployed as
+func remoteEnv(alias string) string {
+	if alias == "default" {
+		return "production"
+	}
+
+	return alias
+}
@@ -0,0 +1,315 @@
+package processors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/code"
+	"github.com/nanobox-io/nanobox/processors/component"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/dhcp"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/locker"
+	util_provider "github.com/nanobox-io/nanobox/util/provider"
+)
+
+// problem is something in the database that doesn't match the vm
+type problem struct {
+	detail string
+	fix    func() error
+}
+
+// Doctor cross-checks the database against the containers, the nat rules of
+// the provider and the reserved IPs, and reports what's out of sync. With fix
+// the problems are repaired: containers without a record are removed,
+// records without a container are destroyed so they're created again, nat
+// rules without a component or without their other half are removed, and
+// leaked IPs are released.
+func Doctor(fix bool) error {
+	// init docker client
+	if err := provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	locker.GlobalLock()
+	defer locker.GlobalUnlock()
+
+	containers, err := docker.Client.ContainerList(context.Background(), types.ContainerListOptions{All: true})
+	if err != nil {
+		lumber.Error("doctor:docker.Client.ContainerList(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to list the containers")
+	}
+
+	envs, err := models.AllEnvs()
+	if err != nil {
+		lumber.Error("doctor:models.AllEnvs(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to load the apps")
+	}
+
+	apps := []*models.App{}
+	for _, envModel := range envs {
+		envApps, err := envModel.Apps()
+		if err != nil {
+			lumber.Error("doctor:models.Env{ID:%s}.Apps(): %s", envModel.ID, err.Error())
+			return util.ErrorAppend(err, "failed to load the apps of %s", envModel.Name)
+		}
+		apps = append(apps, envApps...)
+	}
+
+	problems := orphanContainers(containers, envs, apps)
+	problems = append(problems, missingContainers(containers, apps)...)
+	problems = append(problems, leakedIPs(containers, apps)...)
+
+	natProblems, err := natRules(apps)
+	if err != nil {
+		return err
+	}
+	problems = append(problems, natProblems...)
+
+	fmt.Println()
+
+	if len(problems) == 0 {
+		fmt.Printf("%s No problems found\n\n", display.TaskComplete)
+		return nil
+	}
+
+	for _, p := range problems {
+		fmt.Printf("! %s\n", p.detail)
+	}
+	fmt.Println()
+
+	if !fix {
+		fmt.Printf("%d problems found, run 'nanobox doctor --fix' to repair them\n\n", len(problems))
+		return nil
+	}
+
+	for _, p := range problems {
+		if err := p.fix(); err != nil {
+			return util.ErrorAppend(err, "failed to repair: %s", p.detail)
+		}
+	}
+
+	fmt.Printf("\n%s Repaired %d problems\n\n", display.TaskComplete, len(problems))
+
+	return nil
+}
+
+// orphanContainers finds the containers nanobox created for an app or a
+// component that no longer exists. The containers of an env are named
+// nanobox_<env id>_..., the components nanobox_<app id>_<group>.<name>.
+func orphanContainers(containers []types.Container, envs []*models.Env, apps []*models.App) []problem {
+	problems := []problem{}
+
+	envIDs := map[string]bool{}
+	for _, envModel := range envs {
+		envIDs[envModel.ID] = true
+	}
+
+	components := map[string]bool{}
+	for _, appModel := range apps {
+		appComponents, _ := appModel.Components()
+		for _, componentModel := range appComponents {
+			components[fmt.Sprintf("nanobox_%s_%s", appModel.ID, componentModel.Name)] = true
+		}
+	}
+
+	for _, container := range containers {
+		name := containerName(container)
+		parts := strings.SplitN(strings.TrimPrefix(name, "nanobox_"), "_", 3)
+
+		// the containers nanobox runs for itself, ie: nanobox_bridge
+		if !strings.HasPrefix(name, "nanobox_") || len(parts) < 2 || len(parts[0]) != 32 {
+			continue
+		}
+
+		orphan := !envIDs[parts[0]]
+
+		// a component container, the app exists but the component doesn't
+		if !orphan && len(parts) == 3 && strings.Contains(parts[2], ".") {
+			orphan = !components[name]
+		}
+
+		if !orphan {
+			continue
+		}
+
+		id := container.ID
+		problems = append(problems, problem{
+			detail: fmt.Sprintf("container %s has no app or component", name),
+			fix: func() error {
+				display.StartTask("Removing container %s", name)
+				defer display.StopTask()
+
+				if err := docker.ContainerRemove(id); err != nil {
+					lumber.Error("doctor:docker.ContainerRemove(%s): %s", id, err.Error())
+					display.ErrorTask()
+					return util.ErrorAppend(err, "failed to remove the container")
+				}
+				return nil
+			},
+		})
+	}
+
+	return problems
+}
+
+// missingContainers finds the components whose container is gone. They're
+// destroyed, the next run or deploy creates them again.
+func missingContainers(containers []types.Container, apps []*models.App) []problem {
+	problems := []problem{}
+
+	ids := map[string]bool{}
+	for _, container := range containers {
+		ids[container.ID] = true
+	}
+
+	for _, appModel := range apps {
+		appComponents, _ := appModel.Components()
+		for _, componentModel := range appComponents {
+			if componentModel.ID == "" || ids[componentModel.ID] {
+				continue
+			}
+
+			appModel, componentModel := appModel, componentModel
+			problems = append(problems, problem{
+				detail: fmt.Sprintf("%s of app %s has no container", componentModel.Name, appModel.ID),
+				fix: func() error {
+					if componentModel.Type == "code" {
+						return code.Destroy(componentModel)
+					}
+					return component.Destroy(appModel, componentModel)
+				},
+			})
+		}
+	}
+
+	return problems
+}
+
+// leakedIPs finds the reserved IPs nothing uses, neither the provider, an
+// app, a component nor a container
+func leakedIPs(containers []types.Container, apps []*models.App) []problem {
+	problems := []problem{}
+
+	used := map[string]bool{}
+
+	if providerModel, err := models.LoadProvider(); err == nil {
+		used[providerModel.MountIP] = true
+		used[providerModel.HostIP] = true
+	}
+
+	for _, appModel := range apps {
+		for _, ip := range appModel.LocalIPs {
+			used[ip] = true
+		}
+
+		appComponents, _ := appModel.Components()
+		for _, componentModel := range appComponents {
+			used[componentModel.IPAddr()] = true
+		}
+	}
+
+	for _, container := range containers {
+		if container.NetworkSettings == nil {
+			continue
+		}
+		for _, network := range container.NetworkSettings.Networks {
+			used[network.IPAddress] = true
+		}
+	}
+
+	reserved, _ := models.LoadIPs()
+	for _, ip := range reserved {
+		if used[ip.String()] {
+			continue
+		}
+
+		ip := ip
+		problems = append(problems, problem{
+			detail: fmt.Sprintf("ip %s is reserved but unused", ip),
+			fix: func() error {
+				return dhcp.ReturnIP(ip)
+			},
+		})
+	}
+
+	return problems
+}
+
+// natRules finds the nat rules of the provider that point at an ip no
+// component has, and the nats that have only one of their two rules. Both
+// are removed, the components that need a nat get it when they're set up.
+func natRules(apps []*models.App) ([]problem, error) {
+	rules, err := util_provider.NatRules()
+	if err != nil {
+		lumber.Error("doctor:provider.NatRules(): %s", err.Error())
+		return nil, util.ErrorAppend(err, "failed to list the nat rules")
+	}
+
+	used := map[string]bool{}
+	for _, appModel := range apps {
+		appComponents, _ := appModel.Components()
+		for _, componentModel := range appComponents {
+			used[componentModel.IPAddr()] = true
+		}
+	}
+
+	// the chains each nat has a rule in
+	type nat struct{ host, container string }
+	chains := map[nat][]string{}
+	nats := []nat{}
+	for _, rule := range rules {
+		n := nat{rule.Host, rule.Container}
+		if _, ok := chains[n]; !ok {
+			nats = append(nats, n)
+		}
+		chains[n] = append(chains[n], rule.Chain)
+	}
+
+	problems := []problem{}
+	for _, n := range nats {
+		var detail string
+		switch {
+		case !used[n.container]:
+			detail = fmt.Sprintf("nat of %s to %s has no component", n.host, n.container)
+		case len(chains[n]) == 1:
+			detail = fmt.Sprintf("nat of %s to %s is missing its other rule, it only has %s", n.host, n.container, chains[n][0])
+		default:
+			continue
+		}
+
+		n := n
+		problems = append(problems, problem{
+			detail: detail,
+			fix: func() error {
+				display.StartTask("Removing nat of %s to %s", n.host, n.container)
+				defer display.StopTask()
+
+				if err := util_provider.RemoveNat(n.host, n.container); err != nil {
+					lumber.Error("doctor:provider.RemoveNat(%s, %s): %s", n.host, n.container, err.Error())
+					display.ErrorTask()
+					return util.ErrorAppend(err, "failed to remove the nat")
+				}
+				return nil
+			},
+		})
+	}
+
+	return problems, nil
+}
+
+// containerName returns the name of a container without the leading slash
+func containerName(container types.Container) string {
+	if len(container.Names) == 0 {
+		return ""
+	}
+
+	return strings.TrimPrefix(container.Names[0], "/")
+}
@@ -0,0 +1,141 @@
+package env
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/filters"
+	"github.com/docker/go-units"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/locker"
+)
+
+// CacheClean removes the build and package caches of the env and reports
+// how much space was freed. The next build runs every phase.
+func CacheClean(envModel *models.Env) error {
+	locker.LocalLock()
+	defer locker.LocalUnlock()
+
+	// init docker client
+	if err := provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	display.OpenContext("Cleaning build cache")
+	defer display.CloseContext()
+
+	var freed int64
+
+	volumes := []string{
+		fmt.Sprintf("nanobox_%s_build", envModel.ID),
+		fmt.Sprintf("nanobox_%s_cache", envModel.ID),
+	}
+	sizes := VolumeSizes(volumes)
+
+	for _, volume := range volumes {
+		display.StartTask("Removing %s", volume)
+		size := sizes[volume]
+
+		if err := docker.VolumeRemove(volume); err != nil {
+			display.ErrorTask()
+			lumber.Error("env:CacheClean:docker.VolumeRemove(%s): %s", volume, err.Error())
+			return util.Errorf("[USER] failed to remove %s, stop any running app with `nanobox dev stop` and try again", volume)
+		}
+		display.StopTask()
+
+		freed += size
+	}
+
+	// forget the cached phases and the build itself, the build volume is gone
+	envModel.BuildCache = nil
+	envModel.UserBoxfile = ""
+	if err := envModel.Save(); err != nil {
+		lumber.Error("env:CacheClean:models.Env.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to persist env")
+	}
+
+	display.Info("Freed %s\n", units.HumanSize(float64(freed)))
+
+	return nil
+}
+
+// VolumeSizes returns the size in bytes of each docker volume, measured with
+// du in a short-lived container. A volume that isn't there is left out.
+func VolumeSizes(volumes []string) map[string]int64 {
+	sizes := map[string]int64{}
+	if len(volumes) == 0 {
+		return sizes
+	}
+
+	// binding a volume that isn't there would create it
+	existing := map[string]bool{}
+	list, err := docker.Client.VolumeList(context.Background(), filters.NewArgs())
+	if err != nil {
+		lumber.Error("env:VolumeSizes:docker.Client.VolumeList(): %s", err.Error())
+		return sizes
+	}
+	for _, volume := range list.Volumes {
+		existing[volume.Name] = true
+	}
+
+	config := docker.ContainerConfig{
+		Name:          "nanobox_du",
+		Image:         "nanobox/build",
+		Cmd:           []string{"du", "-sk"},
+		RestartPolicy: "no",
+	}
+	for _, volume := range volumes {
+		if existing[volume] {
+			config.Binds = append(config.Binds, fmt.Sprintf("%s:/mnt/volumes/%s", volume, volume))
+			config.Cmd = append(config.Cmd, "/mnt/volumes/"+volume)
+		}
+	}
+	if len(config.Binds) == 0 {
+		return sizes
+	}
+
+	docker.ContainerRemove(config.Name)
+	container, err := docker.CreateContainer(config)
+	if err != nil {
+		lumber.Error("env:VolumeSizes:docker.CreateContainer(%+v): %s", config, err.Error())
+		return sizes
+	}
+	defer docker.ContainerRemove(container.ID)
+
+	if _, err := docker.Client.ContainerWait(context.Background(), container.ID); err != nil {
+		return sizes
+	}
+
+	rc, err := docker.Client.ContainerLogs(context.Background(), container.ID, types.ContainerLogsOptions{ShowStdout: true})
+	if err != nil {
+		return sizes
+	}
+	defer rc.Close()
+
+	out := &bytes.Buffer{}
+	stdcopy.StdCopy(out, out, rc)
+
+	// du prints the size in kilobytes followed by the path, a line per volume
+	for _, line := range strings.Split(out.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		kb, _ := strconv.ParseInt(fields[0], 10, 64)
+		sizes[strings.TrimPrefix(fields[1], "/mnt/volumes/")] = kb * 1024
+	}
+
+	return sizes
+}
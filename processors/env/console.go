@@ -8,6 +8,7 @@ import (
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/console"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/notify"
 )
 
 // Console ...
@@ -27,11 +28,16 @@ func Console(componentModel *models.Component, consoleConfig console.ConsoleConf
 	}
 
 	switch {
+	case consoleConfig.Command != "" && consoleConfig.DevIP == "":
+		// a command in a component, scripts only want its output
 	case consoleConfig.Command != "":
 		display.InfoDevRunContainer(consoleConfig.Command, consoleConfig.DevIP)
 	default:
 		display.MOTD()
 		display.InfoDevContainer(consoleConfig.DevIP)
+
+		// the shell is ready, the user ends it
+		notify.Finish(false)
 	}
 	<-time.After(100 * time.Millisecond)
 
@@ -57,6 +57,8 @@ func Destroy(env *models.Env) error {
 	docker.VolumeRemove(fmt.Sprintf("nanobox_%s_mount", env.ID))
 	docker.VolumeRemove(fmt.Sprintf("nanobox_%s_deploy", env.ID))
 	docker.VolumeRemove(fmt.Sprintf("nanobox_%s_build", env.ID))
+	docker.VolumeRemove(fmt.Sprintf("nanobox_%s_dev_code", env.ID))
+	docker.VolumeRemove(fmt.Sprintf("nanobox_%s_code", env.ID))
 
 	// remove the environment
 	if err := env.Delete(); err != nil {
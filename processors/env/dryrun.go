@@ -0,0 +1,114 @@
+package env
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// the names of the dry-run environments are part of the container names
+var envNameRegex = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// dryRunName returns the app name of a dry-run environment
+func dryRunName(name string) (string, error) {
+	switch name {
+	case "sim", "dry-run", "default":
+		return "sim", nil
+	case "dev", "local":
+		return "", util.Errorf("[USER] '%s' is the local environment, use 'nanobox run' instead", name)
+	}
+
+	if !envNameRegex.MatchString(name) {
+		return "", util.Errorf("[USER] '%s' isn't a valid environment name, use lowercase letters, numbers and dashes", name)
+	}
+
+	return name, nil
+}
+
+// UseDryRun points 'dry-run' at an environment. Each environment has its own
+// components, IPs, containers and evars, so they can run side by side. An
+// environment is created the first time it is started.
+func UseDryRun(envModel *models.Env, name string) error {
+	name, err := dryRunName(name)
+	if err != nil {
+		return err
+	}
+
+	envModel.DryRun = name
+	if name == "sim" {
+		envModel.DryRun = ""
+	}
+
+	if err := envModel.Save(); err != nil {
+		lumber.Error("env:UseDryRun:models.Env.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to save the env")
+	}
+
+	display.Info("\n%s 'dry-run' now points at %s\n\n", display.TaskComplete, name)
+
+	return nil
+}
+
+// ListDryRuns prints the dry-run environments of the app
+func ListDryRuns(envModel *models.Env) error {
+	apps, err := envModel.Apps()
+	if err != nil {
+		lumber.Error("env:ListDryRuns:models.Env{ID:%s}.Apps(): %s", envModel.ID, err.Error())
+		return util.ErrorAppend(err, "failed to load app collection")
+	}
+
+	names := map[string]string{envModel.DryRunName(): "not created"}
+	for _, name := range envModel.Environments {
+		names[name] = "not created"
+	}
+	for _, appModel := range apps {
+		if appModel.Name != "dev" {
+			names[appModel.Name] = appModel.Status
+		}
+	}
+
+	keys := []string{}
+	for name := range names {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+
+	fmt.Println()
+	for _, name := range keys {
+		marker := " "
+		if name == envModel.DryRunName() {
+			marker = "*"
+		}
+		fmt.Printf("  %s %-20s %s\n", marker, name, names[name])
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// RemoveDryRun destroys a dry-run environment. The environment 'dry-run'
+// points at can't be removed.
+func RemoveDryRun(envModel *models.Env, name string) error {
+	name, err := dryRunName(name)
+	if err != nil {
+		return err
+	}
+
+	if name == envModel.DryRunName() {
+		return util.Errorf("[USER] 'dry-run' points at %s, switch to another environment first", name)
+	}
+
+	appModel, _ := models.FindAppBySlug(envModel.ID, name)
+	if appModel.IsNew() {
+		return util.Errorf("[USER] there is no %s environment", name)
+	}
+
+	return app.Destroy(appModel)
+}
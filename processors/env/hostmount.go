@@ -0,0 +1,102 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	container_generator "github.com/nanobox-io/nanobox/generators/containers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/hostmount"
+	"github.com/nanobox-io/nanobox/util/provider"
+)
+
+// hostMounts returns the host directories the boxfile mounts into the dev
+// container and the components, each directory once
+func hostMounts(env *models.Env) ([]hostmount.Mount, error) {
+	box := boxfile.NewFromPath(filepath.Join(env.Directory, "boxfile.yml"))
+
+	nodes := append([]string{"run.config"}, box.Nodes("code")...)
+	nodes = append(nodes, box.Nodes("data")...)
+	for key := range box.Parsed {
+		if strings.HasPrefix(key, "sidecar.") {
+			nodes = append(nodes, key)
+		}
+	}
+
+	mounts := []hostmount.Mount{}
+	seen := map[string]bool{}
+
+	for _, name := range nodes {
+		parsed, err := hostmount.ParseAll(box.Node(name).StringSliceValue("mounts"), env.Directory)
+		if err != nil {
+			return nil, util.Errorf("[USER] %s.mounts: %s", name, err.Error())
+		}
+
+		for _, m := range parsed {
+			if seen[m.Host] {
+				continue
+			}
+			seen[m.Host] = true
+
+			if _, err := os.Stat(m.Host); err != nil {
+				return nil, util.Errorf("[USER] %s.mounts: %s doesn't exist", name, m.Host)
+			}
+
+			mounts = append(mounts, m)
+		}
+	}
+
+	return mounts, nil
+}
+
+// mountHostDirs shares the host directories the boxfile mounts into the vm,
+// the containers bind them from there. Without a vm they're bound directly.
+func mountHostDirs(env *models.Env) error {
+	if !provider.RequiresMount() {
+		return nil
+	}
+
+	mounts, err := hostMounts(env)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range mounts {
+		if err := provider.AddMount(m.Host, container_generator.HostMountSource(env.ID, m)); err != nil {
+			lumber.Error("env:mountHostDirs:provider.AddMount(%s): %s", m.Host, err.Error())
+			return util.ErrorAppend(err, "failed to mount %s on the provider", m.Host)
+		}
+	}
+
+	return nil
+}
+
+// unmountHostDirs removes the shares of the host directories the boxfile
+// mounts from the vm
+func unmountHostDirs(env *models.Env) error {
+	// without a vm the source of the bind is the directory itself
+	if !provider.RequiresMount() {
+		return nil
+	}
+
+	mounts, err := hostMounts(env)
+	if err != nil {
+		// what can't be parsed was never mounted
+		lumber.Info("env:unmountHostDirs:hostMounts(): %s", err.Error())
+		return nil
+	}
+
+	for _, m := range mounts {
+		if err := provider.RemoveMount(m.Host, container_generator.HostMountSource(env.ID, m)); err != nil {
+			lumber.Error("env:unmountHostDirs:provider.RemoveMount(%s): %s", m.Host, err.Error())
+			return util.ErrorAppend(err, "failed to remove the mount of %s", m.Host)
+		}
+	}
+
+	return nil
+}
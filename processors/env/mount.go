@@ -46,6 +46,12 @@ func Mount(env *models.Env) error {
 		return util.ErrorAppend(err, "failed to mount the code share on the provider")
 	}
 
+	// mount the host directories the boxfile mounts into containers
+	if err := mountHostDirs(env); err != nil {
+		display.ErrorTask()
+		return err
+	}
+
 	// // setup mount directories
 	// provider.Run([]string{"mkdir", "-p", fmt.Sprintf("%s%s/build", provider.HostMntDir(), env.ID)})
 	// provider.Run([]string{"mkdir", "-p", fmt.Sprintf("%s%s/deploy", provider.HostMntDir(), env.ID)})
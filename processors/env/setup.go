@@ -2,8 +2,6 @@ package env
 
 import (
 	"fmt"
-	"path/filepath"
-	"regexp"
 	"time"
 
 	"github.com/jcelliott/lumber"
@@ -14,7 +12,9 @@ import (
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/config"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/engine"
 	util_provider "github.com/nanobox-io/nanobox/util/provider"
+	"github.com/nanobox-io/nanobox/util/schema"
 )
 
 // Setup sets up the provider and the env mounts
@@ -46,7 +46,36 @@ func Setup(envModel *models.Env) error {
 		return err
 	}
 
-	// todo: validate boxfile nodes
+	// check the nodes and keys of the boxfile before anything is built
+	problems, _ := schema.ValidateFile(config.Boxfile())
+	for _, problem := range problems {
+		display.Warn("boxfile.yml:%s\n", problem)
+	}
+	if schema.HasErrors(problems) {
+		err = util.ErrorfQuiet("[USER] the boxfile is invalid")
+		if err2, ok := err.(util.Err); ok {
+			err2.Suggest = "Run 'nanobox validate' to see every problem of your boxfile.yml"
+			return err2
+		}
+		return err
+	}
+
+	// fetch the version of a pinned engine, unless a local copy overrides it
+	runConfig := box.Node("run.config")
+	engineName := runConfig.StringValue("engine")
+	if !config.LocalEngine() {
+		if err := engine.Check(engineName); err != nil {
+			return err
+		}
+	}
+	if runConfig.StringValue("engine_path") == "" && engine.Pinned(engineName) && !engine.Current(engineName) {
+		display.StartTask("Fetching engine %s", engineName)
+		if err := engine.Fetch(engineName); err != nil {
+			display.ErrorTask()
+			return err
+		}
+		display.StopTask()
+	}
 
 	// ensure local engine exists
 	engineDir, err := config.EngineDir()
@@ -71,21 +100,16 @@ func Setup(envModel *models.Env) error {
 		return util.ErrorAppend(err, "failed to initialize the env data")
 	}
 
-	// if switch from local engine, ensure old local engine gets unmounted
-	oldBox := boxfile.New([]byte(envModel.UserBoxfile))
-	newBox, _ := boxfile.NewFromFile(config.Boxfile()) // can ignore error, we made sure it exists before this point
-	oldEngineName := oldBox.Node("run.config").StringValue("engine")
-	newEngineName := newBox.Node("run.config").StringValue("engine")
-	var validLocal = regexp.MustCompile(`^[~|\.|\/|\\]`)
-	if (oldEngineName != newEngineName) && newEngineName != "" && validLocal.MatchString(oldEngineName) {
-		oldEnginePath, err := filepath.Abs(oldEngineName)
-		if err != nil {
-			// todo: ignore here so if they delete their engine it won't break until they restore it
-			return fmt.Errorf("Failed to resolve old engine location - %s", err.Error())
-		}
+	// pick up the config the team shares
+	if err := syncTeamConfig(envModel); err != nil {
+		return util.ErrorAppend(err, "failed to apply the team config")
+	}
 
-		err = UnmountEngine(envModel, oldEnginePath)
-		if err != nil {
+	// if the engine changed, ensure the old local engine gets unmounted. An
+	// old engine that was removed has nothing left to unmount.
+	oldBox := boxfile.New([]byte(envModel.UserBoxfile))
+	if oldEngineDir, _ := config.EngineDirFor(oldBox.Node("run.config")); oldEngineDir != "" && oldEngineDir != engineDir {
+		if err := UnmountEngine(envModel, oldEngineDir); err != nil {
 			return fmt.Errorf("Failed to unmount engine - %s", err.Error())
 		}
 	}
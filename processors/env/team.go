@@ -0,0 +1,341 @@
+package env
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jcelliott/lumber"
+	"gopkg.in/yaml.v2"
+
+	"github.com/nanobox-io/nanobox/models"
+	app_dns "github.com/nanobox-io/nanobox/processors/app/dns"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/dns"
+)
+
+// teamConfig is the configuration of an app a team commits with the code,
+// in .nanobox/config.yml. Secrets, like evars, are never part of it. The
+// boxfile section is merged onto the boxfile of each environment, see
+// util/overlay, and ports sets the local port of the tunnels, ie:
+//
+//	name: shop
+//	dry-run: staging
+//	environments:
+//	  - staging
+//	remotes:
+//	  default:
+//	    id: 2f1b...
+//	    name: shop
+//	dns:
+//	  local:
+//	    - shop.local
+//	  staging:
+//	    - shop.staging
+//	boxfile:
+//	  local:
+//	    data.mail:
+//	      image: nanobox/mailcatcher
+//	ports:
+//	  data.db: 5433
+//	config:
+//	  cpus: 2
+//
+// The endpoints in it, of the remotes and of the config, are only used once
+// the user trusts them on the machine.
+type teamConfig struct {
+	Name         string                   `yaml:"name,omitempty"`
+	DryRun       string                   `yaml:"dry-run,omitempty"`
+	Environments []string                 `yaml:"environments,omitempty"`
+	Remotes      map[string]teamRemote    `yaml:"remotes,omitempty"`
+	DNS          map[string][]string      `yaml:"dns,omitempty"`
+	Boxfile      map[string]yaml.MapSlice `yaml:"boxfile,omitempty"` // see overlay.Apply
+	Ports        map[string]int           `yaml:"ports,omitempty"`
+	Config       map[string]interface{}   `yaml:"config,omitempty"` // see models.LoadConfig
+}
+
+// teamRemote is a remote of the team config
+type teamRemote struct {
+	ID       string `yaml:"id"`
+	Name     string `yaml:"name"`
+	Endpoint string `yaml:"endpoint,omitempty"`
+}
+
+// ExportTeamConfig writes the shareable configuration of the app to
+// .nanobox/config.yml
+func ExportTeamConfig(envModel *models.Env) error {
+	team := teamConfig{
+		Name:    envModel.Name,
+		DryRun:  envModel.DryRun,
+		Remotes: map[string]teamRemote{},
+		DNS:     map[string][]string{},
+	}
+
+	// the configuration keys, boxfile overrides and ports are only ever set
+	// in the file
+	if existing, _, _ := readTeamConfig(); existing != nil {
+		team.Config = existing.Config
+		team.Boxfile = existing.Boxfile
+		team.Ports = existing.Ports
+	}
+
+	for alias, remote := range envModel.Remotes {
+		team.Remotes[alias] = teamRemote{ID: remote.ID, Name: remote.Name, Endpoint: remote.Endpoint}
+	}
+
+	environments := map[string]bool{}
+	for _, name := range envModel.Environments {
+		environments[name] = true
+	}
+
+	apps, err := envModel.Apps()
+	if err != nil {
+		lumber.Error("env:ExportTeamConfig:models.Env{ID:%s}.Apps(): %s", envModel.ID, err.Error())
+		return util.ErrorAppend(err, "failed to load app collection")
+	}
+
+	for _, appModel := range apps {
+		if appModel.Name != "dev" && appModel.Name != "sim" {
+			environments[appModel.Name] = true
+		}
+
+		for _, domain := range dns.List(appModel.ID) {
+			team.DNS[teamEnvName(appModel.Name)] = append(team.DNS[teamEnvName(appModel.Name)], domain.Domain)
+		}
+	}
+
+	for name := range environments {
+		team.Environments = append(team.Environments, name)
+	}
+	sort.Strings(team.Environments)
+
+	out, err := yaml.Marshal(team)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to encode the team config")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(config.TeamConfig()), 0755); err != nil {
+		return util.ErrorAppend(err, "failed to create the .nanobox directory")
+	}
+
+	if err := ioutil.WriteFile(config.TeamConfig(), out, 0644); err != nil {
+		lumber.Error("env:ExportTeamConfig:ioutil.WriteFile(%s): %s", config.TeamConfig(), err.Error())
+		return util.ErrorAppend(err, "failed to write the team config")
+	}
+
+	// this machine already has everything in it, the remotes came from it
+	// but the config keys kept from the file are as trusted as they were
+	envModel.TeamConfig = util.FileMD5(config.TeamConfig())
+	if envModel.TrustedEndpoints == nil {
+		envModel.TrustedEndpoints = map[string]string{}
+	}
+	for alias, remote := range team.Remotes {
+		if remote.Endpoint != "" {
+			envModel.TrustedEndpoints["remote."+alias] = remote.Endpoint
+		}
+	}
+	if err := envModel.Save(); err != nil {
+		lumber.Error("env:ExportTeamConfig:models.Env.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to save the env")
+	}
+
+	display.Info("\n%s Exported to %s, commit it to share it with your team\n\n", display.TaskComplete, config.TeamConfig())
+
+	return nil
+}
+
+// ImportTeamConfig applies .nanobox/config.yml to the app, including the
+// dns aliases which need administrative privileges
+func ImportTeamConfig(envModel *models.Env) error {
+	team, sum, err := readTeamConfig()
+	if err != nil {
+		return err
+	}
+
+	if team == nil {
+		return util.Errorf("[USER] there is no %s to import", config.TeamConfig())
+	}
+
+	if err := applyTeamConfig(envModel, team, sum); err != nil {
+		return err
+	}
+
+	for name, domains := range team.DNS {
+		appName := "dev"
+		if name != "local" {
+			appName, err = dryRunName(name)
+			if err != nil {
+				return err
+			}
+		}
+
+		appModel, _ := models.FindAppBySlug(envModel.ID, appName)
+
+		for _, domain := range domains {
+			if err := app_dns.Add(envModel, appModel, domain); err != nil {
+				return util.ErrorAppend(err, "failed to add the dns alias %s", domain)
+			}
+		}
+	}
+
+	display.Info("\n%s Imported %s\n\n", display.TaskComplete, config.TeamConfig())
+
+	return nil
+}
+
+// syncTeamConfig applies the team config when it changed since it was last
+// imported. The dns aliases are left for 'nanobox env import'.
+func syncTeamConfig(envModel *models.Env) error {
+	team, sum, err := readTeamConfig()
+	if err != nil || team == nil || sum == envModel.TeamConfig {
+		return err
+	}
+
+	display.StartTask("Applying team config")
+	defer display.StopTask()
+
+	return applyTeamConfig(envModel, team, sum)
+}
+
+// readTeamConfig reads the team config and its checksum, there may not be
+// one
+func readTeamConfig() (*teamConfig, string, error) {
+	data, err := ioutil.ReadFile(config.TeamConfig())
+	if os.IsNotExist(err) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", util.ErrorAppend(err, "failed to read the team config")
+	}
+
+	team := &teamConfig{}
+	if err := yaml.Unmarshal(data, team); err != nil {
+		return nil, "", util.Errorf("[USER] %s is invalid: %s", config.TeamConfig(), err.Error())
+	}
+
+	return team, util.FileMD5(config.TeamConfig()), nil
+}
+
+// applyTeamConfig sets the name, environments, remotes and tunnel ports of
+// the app. The endpoints that changed are applied once the user trusts them.
+func applyTeamConfig(envModel *models.Env, team *teamConfig, sum string) error {
+	for name, port := range team.Ports {
+		if port < 1024 || port > 65535 {
+			return util.Errorf("[USER] the port of %s in %s has to be between 1024 and 65535", name, config.TeamConfig())
+		}
+	}
+
+	trusted := trustEndpoints(envModel, team)
+
+	if team.Name != "" {
+		envModel.Name = team.Name
+	}
+
+	if team.DryRun != "" {
+		name, err := dryRunName(team.DryRun)
+		if err != nil {
+			return err
+		}
+		envModel.DryRun = name
+		if name == "sim" {
+			envModel.DryRun = ""
+		}
+	}
+
+	for _, name := range team.Environments {
+		if _, err := dryRunName(name); err != nil {
+			return err
+		}
+	}
+	envModel.Environments = team.Environments
+
+	if envModel.Remotes == nil {
+		envModel.Remotes = map[string]models.Remote{}
+	}
+	for alias, remote := range team.Remotes {
+		// an untrusted remote would send the code and credentials elsewhere
+		if remote.Endpoint != "" && !trusted {
+			continue
+		}
+		envModel.Remotes[alias] = models.Remote{ID: remote.ID, Name: remote.Name, Endpoint: remote.Endpoint}
+	}
+
+	envModel.TunnelPorts = team.Ports
+	envModel.TeamConfig = sum
+
+	if err := envModel.Save(); err != nil {
+		lumber.Error("env:applyTeamConfig:models.Env.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to save the env")
+	}
+
+	return nil
+}
+
+// teamEndpoints returns the endpoints of the team config, the config keys
+// and the remote.<alias> endpoints
+func teamEndpoints(team *teamConfig) map[string]string {
+	endpoints := map[string]string{}
+
+	for alias, remote := range team.Remotes {
+		if remote.Endpoint != "" {
+			endpoints["remote."+alias] = remote.Endpoint
+		}
+	}
+
+	for _, key := range models.EndpointKeys {
+		if value, ok := team.Config[key]; ok {
+			endpoints[key] = fmt.Sprint(value)
+		}
+	}
+
+	return endpoints
+}
+
+// trustEndpoints asks the user to trust the endpoints of the team config
+// that changed since they were last trusted, whoever can commit to the app
+// sets them. The untrusted endpoints are left out.
+func trustEndpoints(envModel *models.Env, team *teamConfig) bool {
+	endpoints := teamEndpoints(team)
+
+	changed := []string{}
+	for key, value := range endpoints {
+		if envModel.TrustedEndpoints[key] != value {
+			changed = append(changed, key)
+		}
+	}
+	if len(changed) == 0 {
+		return true
+	}
+	sort.Strings(changed)
+
+	display.Warn("%s points nanobox at these servers:\n", config.TeamConfig())
+	for _, key := range changed {
+		display.Warn("  %s: %s\n", key, endpoints[key])
+	}
+
+	answer, err := display.Ask("Trust them? [y/N]")
+	if err != nil || (answer != "y" && answer != "Y") {
+		display.Warn("The endpoints are left out, run 'nanobox env import' to be asked again\n")
+		return false
+	}
+
+	envModel.TrustedEndpoints = endpoints
+
+	return true
+}
+
+// teamEnvName returns the name an app goes by in the team config
+func teamEnvName(appName string) string {
+	switch appName {
+	case "dev":
+		return "local"
+	case "sim":
+		return "dry-run"
+	}
+
+	return appName
+}
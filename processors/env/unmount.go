@@ -44,6 +44,12 @@ func Unmount(env *models.Env) error {
 		return util.ErrorAppend(err, "failed to remove code mount")
 	}
 
+	// unmount the host directories the boxfile mounts into containers
+	if err := unmountHostDirs(env); err != nil {
+		display.ErrorTask()
+		return err
+	}
+
 	return nil
 }
 
@@ -64,9 +70,14 @@ func UnmountEngine(env *models.Env, engineDir string) error {
 
 // mountsInUse returns true if any of the env's apps are running
 func mountsInUse(env *models.Env) bool {
-	devApp, _ := models.FindAppBySlug(env.ID, "dev")
-	simApp, _ := models.FindAppBySlug(env.ID, "sim")
-	return devApp.Status == "up" || simApp.Status == "up"
+	apps, _ := env.Apps()
+	for _, appModel := range apps {
+		if appModel.Status == "up" {
+			return true
+		}
+	}
+
+	return false
 }
 
 // returns true if the app or engine is mounted
@@ -0,0 +1,41 @@
+package evar
+
+import (
+	"io"
+
+	"github.com/nanobox-io/nanobox/commands/registry"
+	"github.com/nanobox-io/nanobox/models"
+	app_evar "github.com/nanobox-io/nanobox/processors/app/evar"
+	"github.com/nanobox-io/nanobox/util/odin"
+)
+
+// Export writes the evars of a live app to w as a .env file
+func Export(envModel *models.Env, appID string, w io.Writer) error {
+	// fetch the remote
+	remote, ok := envModel.Remotes[appID]
+	if ok {
+		// set the odin endpoint
+		odin.SetEndpoint(remote.Endpoint)
+		// set the app id
+		appID = remote.ID
+	}
+
+	// set odins endpoint if the arguement is passed
+	if endpoint := registry.GetString("endpoint"); endpoint != "" {
+		odin.SetEndpoint(endpoint)
+	}
+
+	evars, err := odin.ListEvars(appID)
+	if err != nil {
+		return err
+	}
+
+	vars := map[string]string{}
+	for _, evar := range evars {
+		vars[evar.Key] = evar.Value
+	}
+
+	app_evar.WriteDotenv(w, vars)
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/nanobox-io/nanobox/commands/registry"
 	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util/display"
 	"github.com/nanobox-io/nanobox/util/odin"
 )
 
@@ -28,6 +29,14 @@ func List(envModel *models.Env, appID string) error {
 		return err
 	}
 
+	if display.JSON() {
+		result := map[string]string{}
+		for _, evar := range evars {
+			result[evar.Key] = evar.Value
+		}
+		return display.PrintJSON(result)
+	}
+
 	// print the header
 	fmt.Printf("\nEnvironment Variables\n")
 
@@ -0,0 +1,299 @@
+package processors
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/platform"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/bundle"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/engine"
+	"github.com/nanobox-io/nanobox/util/imagelock"
+	"github.com/nanobox-io/nanobox/util/images"
+)
+
+// Fetch pulls every image the app needs and fetches its pinned engine, so
+// the app can be set up without a network. With pin the images are pulled
+// fresh and their digests written to nanobox.lock. With export they're also
+// written to a bundle that 'nanobox fetch --import' loads on another machine.
+func Fetch(envModel *models.Env, export string, pin bool) error {
+	box := boxfile.NewFromPath(config.Boxfile())
+	if !box.Valid {
+		return util.Errorf("[USER] the boxfile.yml is invalid, see 'nanobox validate'")
+	}
+
+	// init docker client
+	if err := provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	// the app may not have been set up yet
+	if envModel.Name == "" {
+		envModel.Name = filepath.Base(config.LocalDir())
+	}
+
+	// make room for the images before anything is pulled
+	if err := ensureDiskSpace(); err != nil {
+		return err
+	}
+
+	appImages := boxfileImages(box)
+
+	if pin {
+		if err := pinImages(appImages); err != nil {
+			return err
+		}
+	}
+
+	if err := fetchImages(appImages); err != nil {
+		return err
+	}
+
+	engines, err := fetchEngine(box.Node("run.config"))
+	if err != nil {
+		return err
+	}
+
+	if export == "" {
+		display.Info("\n%s %s can be set up without a network\n\n", display.TaskComplete, envModel.Name)
+		return nil
+	}
+
+	return exportBundle(envModel, appImages, engines, export)
+}
+
+// FetchImport loads the images and the engines of a bundle
+func FetchImport(path string) error {
+	// init docker client
+	if err := provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	display.StartTask("Importing %s", path)
+	manifest, err := bundle.Read(path, config.EnginesDir(), images.Load)
+	if err != nil {
+		display.ErrorTask()
+		lumber.Error("processors:FetchImport:bundle.Read(%s): %s", path, err.Error())
+		return util.Errorf("[USER] failed to import %s - %s", path, err.Error())
+	}
+	display.StopTask()
+
+	display.Info("\n%s Imported %d images and %d engines for %s, bundled %s\n\n", display.TaskComplete,
+		len(manifest.Images), len(manifest.Engines), manifest.App, manifest.Created.Format("2006-01-02"))
+
+	return nil
+}
+
+// boxfileImages returns the images the app of the boxfile runs: the build
+// image, the images of its code, data and sidecar components, and the images
+// of the platform components a dry-run deploy starts
+func boxfileImages(box boxfile.Boxfile) []string {
+	found := map[string]bool{}
+
+	add := func(image, fallback string) {
+		if image == "" {
+			image = fallback
+		}
+		if image != "" {
+			found[image] = true
+		}
+	}
+
+	add(box.Node("run.config").StringValue("image"), "nanobox/build")
+
+	for _, name := range box.Nodes("code") {
+		add(box.Node(name).StringValue("image"), "nanobox/code")
+	}
+	for _, name := range box.Nodes("data") {
+		add(box.Node(name).StringValue("image"), "")
+	}
+	for key := range box.Parsed {
+		if strings.HasPrefix(key, "sidecar.") {
+			add(box.Node(key).StringValue("image"), "")
+		}
+	}
+	for _, image := range platform.Images() {
+		add(image, "")
+	}
+
+	appImages := []string{}
+	for image := range found {
+		appImages = append(appImages, image)
+	}
+	sort.Strings(appImages)
+
+	return appImages
+}
+
+// fetchImages pulls the images that aren't there, several at once
+func fetchImages(appImages []string) error {
+	display.OpenContext("Fetching images")
+	defer display.CloseContext()
+
+	missing := []string{}
+	for _, image := range appImages {
+		if images.Current(image) {
+			display.StartTask("Found %s", image)
+			display.StopTask()
+			continue
+		}
+		missing = append(missing, image)
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	display.StartTask("Pulling %s", strings.Join(missing, ", "))
+
+	// the progress of several pulls at once would be a mess, it is left out
+	progress := func(image string) io.Writer {
+		return nil
+	}
+
+	if err := images.DownloadAll(missing, progress); err != nil {
+		display.ErrorTask()
+		lumber.Error("processors:fetchImages:images.DownloadAll(%v): %s", missing, err.Error())
+		return util.ErrorAppend(err, "failed to pull the images")
+	}
+	display.StopTask()
+
+	return nil
+}
+
+// pinImages pulls the images fresh and pins them to their digests in
+// nanobox.lock, telling which tags moved since they were pinned
+func pinImages(appImages []string) error {
+	display.OpenContext("Pinning images")
+	defer display.CloseContext()
+
+	lock, err := imagelock.Load(config.LocalDir())
+	if err != nil {
+		return util.Errorf("[USER] %s is invalid - %s", imagelock.File, err.Error())
+	}
+
+	moved := []string{}
+	for _, image := range appImages {
+		display.StartTask("Pinning %s", image)
+		if err := images.Refresh(image, nil); err != nil {
+			display.ErrorTask()
+			lumber.Error("processors:pinImages:images.Refresh(%s): %s", image, err.Error())
+			return util.ErrorAppend(err, "failed to pull docker image (%s)", image)
+		}
+
+		digest := images.Digest(image)
+		if digest == "" {
+			// a local image has no digest to pin it to
+			display.StopTask()
+			continue
+		}
+
+		if before := lock.Pin(image, digest); before != "" {
+			moved = append(moved, fmt.Sprintf("%s moved from %s to %s", image, before, digest))
+		}
+		display.StopTask()
+	}
+
+	if err := lock.Save(config.LocalDir()); err != nil {
+		lumber.Error("processors:pinImages:imagelock.Lock.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to write %s", imagelock.File)
+	}
+
+	for _, message := range moved {
+		display.Warn("%s\n", message)
+	}
+
+	return nil
+}
+
+// fetchEngine fetches the engine of the run.config node if it's pinned, and
+// returns it relative to the engine cache. An engine that isn't pinned is
+// fetched by the build itself, which needs a network.
+func fetchEngine(runConfig boxfile.Boxfile) ([]string, error) {
+	engineName := runConfig.StringValue("engine")
+	if engineName == "" || runConfig.StringValue("engine_path") != "" || config.LocalEngine() {
+		return []string{}, nil
+	}
+
+	if err := engine.Check(engineName); err != nil {
+		return nil, err
+	}
+
+	if !engine.Pinned(engineName) {
+		display.Info("\nThe engine %s isn't pinned, the build fetches it over the network.\n", engineName)
+		display.Info("Pin it to a version to fetch it now, ie: engine: %s#v1.0.0\n", engineName)
+		return []string{}, nil
+	}
+
+	display.StartTask("Fetching engine %s", engineName)
+	if err := engine.Fetch(engineName); err != nil {
+		display.ErrorTask()
+		return nil, err
+	}
+	display.StopTask()
+
+	rel, err := filepath.Rel(config.EnginesDir(), filepath.FromSlash(config.EngineCacheDir(engineName)))
+	if err != nil {
+		return nil, util.ErrorAppend(err, "failed to find the engine %s in the cache", engineName)
+	}
+
+	return []string{filepath.ToSlash(rel)}, nil
+}
+
+// exportBundle writes the images and the engines to a bundle at path
+func exportBundle(envModel *models.Env, appImages, engines []string, path string) error {
+	display.StartTask("Writing %s", path)
+
+	tmp, err := ioutil.TempFile("", "nanobox-images")
+	if err != nil {
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to create a temp file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := images.Save(appImages, tmp); err != nil {
+		tmp.Close()
+		display.ErrorTask()
+		lumber.Error("processors:exportBundle:images.Save(%v): %s", appImages, err.Error())
+		return util.ErrorAppend(err, "failed to save the images")
+	}
+	tmp.Close()
+
+	manifest := bundle.Manifest{
+		App:     envModel.Name,
+		Images:  appImages,
+		Engines: engines,
+		Created: time.Now().UTC(),
+	}
+
+	if err := bundle.Write(path, manifest, tmp.Name(), config.EnginesDir()); err != nil {
+		display.ErrorTask()
+		lumber.Error("processors:exportBundle:bundle.Write(%s): %s", path, err.Error())
+		return util.ErrorAppend(err, "failed to write the bundle")
+	}
+	display.StopTask()
+
+	size := "?"
+	if info, err := os.Stat(path); err == nil {
+		size = units.HumanSize(float64(info.Size()))
+	}
+
+	display.Info("\n%s Bundled %d images and %d engines in %s (%s)\n", display.TaskComplete, len(appImages), len(engines), path, size)
+	display.Info("  run 'nanobox fetch --import %s' to load them on a machine without a network\n\n", path)
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package processors
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/ciconfig"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/scaffold"
+)
+
+// GenerateCI writes the pipeline of a ci provider into the app in dir, which
+// runs nanobox ci and keeps the build cache between the runs. An existing
+// pipeline is only replaced when overwrite is set. The cache and the summary
+// of the runs are added to the .gitignore.
+func GenerateCI(dir, provider string, opts ciconfig.Options, overwrite bool) error {
+	p, ok := ciconfig.Providers[provider]
+	if !ok {
+		return util.Errorf("[USER] there is no '%s' ci provider, use one of: %s", provider, strings.Join(ciconfig.Names(), ", "))
+	}
+
+	path := filepath.Join(dir, filepath.FromSlash(p.Path))
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return util.Errorf("[USER] %s already exists, replace it with --overwrite", p.Path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return util.ErrorAppend(err, "failed to create %s", filepath.Dir(path))
+	}
+
+	if err := ioutil.WriteFile(path, []byte(p.Render(opts)), 0644); err != nil {
+		return util.ErrorAppend(err, "failed to write %s", p.Path)
+	}
+
+	added, err := scaffold.AddGitignore(filepath.Join(dir, ".gitignore"), []string{ciconfig.CacheDir + "/", ciconfig.SummaryFile})
+	if err != nil {
+		return util.ErrorAppend(err, "failed to update .gitignore")
+	}
+
+	display.Info("\n%s Generated %s\n", display.TaskComplete, p.Path)
+	if opts.Deploy != "" {
+		display.Info("  the pushes to %s are deployed to %s, set NANOBOX_TOKEN in the provider's secrets\n", opts.Branch, opts.Deploy)
+	}
+	if len(added) > 0 {
+		display.Info("Added %s to .gitignore\n", strings.Join(added, ", "))
+	}
+	display.Info("\n")
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package processors
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/processors/code"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/githooks"
+)
+
+// GithooksInstall installs the post-checkout and post-merge hooks into the
+// git repo of the app. With auto the runtime is rebuilt without asking.
+func GithooksInstall(auto bool) error {
+	dir, err := hooksDir()
+	if err != nil {
+		return err
+	}
+
+	// the hooks run in the root of the repo, the app may be in a sub dir
+	prefix, err := exec.Command("git", "-C", config.LocalDir(), "rev-parse", "--show-prefix").Output()
+	if err != nil {
+		lumber.Error("processors:GithooksInstall:git rev-parse --show-prefix: %s", err.Error())
+		return util.ErrorAppend(err, "failed to find the app in the git repo")
+	}
+
+	if err := githooks.Install(dir, strings.TrimSpace(string(prefix)), auto); err != nil {
+		lumber.Error("processors:GithooksInstall:githooks.Install(%s): %s", dir, err.Error())
+		return util.ErrorAppend(err, "failed to install the git hooks")
+	}
+
+	display.Info("\n%s Installed the %s hooks\n", display.TaskComplete, strings.Join(githooks.Hooks, " and "))
+	if auto {
+		display.Info("  a checkout or a merge that changes the boxfile or the dependencies rebuilds the runtime\n\n")
+	} else {
+		display.Info("  a checkout or a merge that changes the boxfile or the dependencies asks to rebuild the runtime\n\n")
+	}
+
+	return nil
+}
+
+// GithooksUninstall removes the hooks, and puts back the ones they replaced
+func GithooksUninstall() error {
+	dir, err := hooksDir()
+	if err != nil {
+		return err
+	}
+
+	if err := githooks.Uninstall(dir); err != nil {
+		lumber.Error("processors:GithooksUninstall:githooks.Uninstall(%s): %s", dir, err.Error())
+		return util.ErrorAppend(err, "failed to remove the git hooks")
+	}
+
+	display.Info("\n%s Removed the git hooks\n\n", display.TaskComplete)
+
+	return nil
+}
+
+// GithooksRun is run by the hooks. When the checkout or the merge changed the
+// build inputs the runtime is rebuilt, and a running dev environment gets the
+// data components of the new boxfile. It asks first unless auto is set, and
+// only tells what to run when it can't ask.
+func GithooksRun(envModel *models.Env, hook string, args []string, auto bool) error {
+	from, to := hookRange(hook, args)
+	if from == "" || from == to {
+		return nil
+	}
+
+	changed, err := gitChanges(from, to)
+	if err != nil {
+		// a hook never gets in the way of git
+		lumber.Error("processors:GithooksRun:gitChanges(%s, %s): %s", from, to, err.Error())
+		return nil
+	}
+
+	inputs := []string{}
+	for _, file := range changed {
+		if code.BuildInput(file) {
+			inputs = append(inputs, file)
+		}
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	services := []string{}
+	for _, file := range inputs {
+		if file == "boxfile.yml" {
+			services = changedServices(from, to)
+			break
+		}
+	}
+
+	display.Info("\nnanobox: %s changed, the runtime needs a rebuild\n", describeChanges(inputs))
+	for _, service := range services {
+		display.Info("  %s\n", service)
+	}
+
+	if !auto {
+		if !display.CanPrompt {
+			display.Info("  run 'nanobox build-runtime' to rebuild it\n\n")
+			return nil
+		}
+
+		answer, err := display.Ask("Rebuild now? [Y/n]")
+		if err != nil || strings.HasPrefix(strings.ToLower(answer), "n") {
+			display.Info("  run 'nanobox build-runtime' to rebuild it\n\n")
+			return nil
+		}
+	}
+
+	if err := Start(); err != nil {
+		return util.ErrorAppend(err, "failed to start the provider")
+	}
+
+	if err := Build(envModel); err != nil {
+		return err
+	}
+
+	// the dev environment is set up again by the next 'nanobox run'
+	appModel, _ := models.FindAppBySlug(envModel.ID, "dev")
+	if len(services) == 0 || appModel.IsNew() || appModel.Status != "up" {
+		return nil
+	}
+
+	envModel, err = models.FindEnvByID(envModel.ID)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to reload the env")
+	}
+
+	return app.Deploy(envModel, appModel)
+}
+
+// hookRange returns the commits before and after the checkout or the merge.
+// A checkout of files, rather than a branch, changes nothing.
+func hookRange(hook string, args []string) (string, string) {
+	switch hook {
+	case "post-checkout":
+		// <previous head> <new head> <1 for a branch checkout>
+		if len(args) == 3 && args[2] == "1" {
+			return args[0], args[1]
+		}
+	case "post-merge":
+		return "ORIG_HEAD", "HEAD"
+	}
+
+	return "", ""
+}
+
+// gitChanges returns the files changed between two commits, relative to the
+// app
+func gitChanges(from, to string) ([]string, error) {
+	out, err := exec.Command("git", "-C", config.LocalDir(), "diff", "--name-only", "--relative", from, to).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(out)), nil
+}
+
+// changedServices describes the data components the boxfile adds, removes or
+// changes between two commits
+func changedServices(from, to string) []string {
+	before := boxfile.New(gitShow(from, "boxfile.yml"))
+	after := boxfile.New(gitShow(to, "boxfile.yml"))
+
+	changes := []string{}
+	for _, name := range after.Nodes("data") {
+		switch {
+		case !before.Node(name).Valid:
+			changes = append(changes, fmt.Sprintf("%s was added", name))
+		case !after.Node(name).Equal(before.Node(name)):
+			changes = append(changes, fmt.Sprintf("%s changed", name))
+		}
+	}
+	for _, name := range before.Nodes("data") {
+		if !after.Node(name).Valid {
+			changes = append(changes, fmt.Sprintf("%s was removed", name))
+		}
+	}
+
+	return changes
+}
+
+// gitShow returns a file of the app at a commit, empty if it isn't there
+func gitShow(commit, file string) []byte {
+	out, err := exec.Command("git", "-C", config.LocalDir(), "show", fmt.Sprintf("%s:./%s", commit, file)).Output()
+	if err != nil {
+		lumber.Debug("processors:gitShow(%s, %s): %s", commit, file, err.Error())
+		return nil
+	}
+
+	return out
+}
+
+// hooksDir returns the hooks dir of the git repo of the app, which may have
+// been moved with core.hooksPath
+func hooksDir() (string, error) {
+	out, err := exec.Command("git", "-C", config.LocalDir(), "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return "", util.Errorf("[USER] %s isn't a git repo", config.LocalDir())
+	}
+
+	dir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(config.LocalDir(), dir)
+	}
+
+	return dir, nil
+}
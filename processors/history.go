@@ -0,0 +1,67 @@
+package processors
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/audit"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// History prints the audit log, the most recent number of commands that ran
+// against the app, or against every app when all is set
+func History(all bool, number int) error {
+	entries, err := audit.Read()
+	if err != nil {
+		lumber.Error("history:audit.Read(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to read the audit log")
+	}
+
+	found := []audit.Entry{}
+	for _, entry := range entries {
+		if all || entry.Dir == config.LocalDir() {
+			found = append(found, entry)
+		}
+	}
+
+	if number > 0 && len(found) > number {
+		found = found[len(found)-number:]
+	}
+
+	if display.JSON() {
+		return display.PrintJSON(found)
+	}
+
+	if len(found) == 0 {
+		fmt.Println("No commands have changed this app yet.")
+		return nil
+	}
+
+	fmt.Printf("\n%-20s %-10s %-30s %-7s %-9s %s\n", "Time", "User", "Command", "Result", "Duration", "Error")
+	fmt.Println(strings.Repeat("-", 100))
+
+	for _, entry := range found {
+		command := strings.TrimSpace(entry.Command + " " + strings.Join(entry.Args, " "))
+		if all {
+			command = fmt.Sprintf("%s (%s)", command, entry.Dir)
+		}
+
+		fmt.Printf("%-20s %-10s %-30s %-7s %-9s %s\n",
+			entry.Time.Local().Format("2006-01-02 15:04:05"), entry.User, command, entry.Result,
+			entry.Duration-entry.Duration%time.Second, firstLine(entry.Error))
+	}
+
+	fmt.Println()
+
+	return nil
+}
+
+// firstLine returns the first line of an error, the rest is in the audit log
+func firstLine(msg string) string {
+	return strings.SplitN(strings.TrimSpace(msg), "\n", 2)[0]
+}
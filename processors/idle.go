@@ -0,0 +1,122 @@
+package processors
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/idle"
+	"github.com/nanobox-io/nanobox/util/notify"
+	util_provider "github.com/nanobox-io/nanobox/util/provider"
+)
+
+// how often the watcher looks for idle apps
+var idleInterval = time.Minute
+
+// IdleWatch suspends the apps that weren't used for the idle-timeout, and the
+// vm once nothing runs, with a desktop notification. The next nanobox command
+// resumes them. It runs in the background until there's nothing left to
+// suspend, or the idle-timeout is turned off.
+func IdleWatch() error {
+	listener, err := idle.Listen()
+	if err != nil {
+		// another watcher is on it
+		return nil
+	}
+	defer listener.Close()
+
+	// nobody is watching the output
+	display.Summary = false
+
+	for {
+		conf, _ := models.LoadConfig()
+		if conf.IdleTimeout <= 0 || conf.CIMode || !util_provider.IsReady() {
+			return nil
+		}
+		timeout := time.Duration(conf.IdleTimeout) * time.Minute
+
+		if err := provider.Init(); err != nil {
+			lumber.Error("processors:IdleWatch:provider.Init(): %s", err.Error())
+			return err
+		}
+
+		running, err := suspendIdleApps(timeout)
+		if err != nil {
+			lumber.Error("processors:IdleWatch:suspendIdleApps(): %s", err.Error())
+		}
+
+		if err == nil && !running && idleSince(timeout) {
+			if err := provider.Suspend(); err != nil {
+				lumber.Error("processors:IdleWatch:provider.Suspend(): %s", err.Error())
+				return err
+			}
+			idleNotify("nanobox suspended the vm, the next nanobox command resumes it")
+			return nil
+		}
+
+		<-time.After(idleInterval)
+	}
+}
+
+// suspendIdleApps suspends the apps that weren't used for timeout, and
+// returns true if any is left running
+func suspendIdleApps(timeout time.Duration) (bool, error) {
+	apps, err := models.AllAppsByStatus(models.StatusUp)
+	if err != nil {
+		return true, err
+	}
+
+	running := false
+	for _, appModel := range apps {
+		// an open console is in use, even if nothing is typed
+		if time.Since(idle.LastActive(appModel.EnvID)) < timeout || consoleOpen(appModel) {
+			running = true
+			continue
+		}
+
+		if err := app.Suspend(appModel); err != nil {
+			return true, err
+		}
+
+		name := appModel.DisplayName()
+		if envModel, err := appModel.Env(); err == nil {
+			name = fmt.Sprintf("%s (%s)", envModel.Name, appModel.DisplayName())
+		}
+		idleNotify(fmt.Sprintf("nanobox suspended %s after %s idle, the next nanobox command resumes it", name, timeout))
+	}
+
+	return running, nil
+}
+
+// idleSince returns true if no app was used for timeout
+func idleSince(timeout time.Duration) bool {
+	envs, _ := models.AllEnvs()
+	for _, envModel := range envs {
+		if time.Since(idle.LastActive(envModel.ID)) < timeout {
+			return false
+		}
+	}
+
+	return true
+}
+
+// consoleOpen returns true if the app's console container is there
+func consoleOpen(appModel *models.App) bool {
+	_, err := docker.GetContainer(fmt.Sprintf("nanobox_%s", appModel.ID))
+	return err == nil
+}
+
+// idleNotify tells the user what was suspended
+func idleNotify(message string) {
+	lumber.Info("processors:idleNotify(): %s", message)
+
+	if err := notify.Send(notify.Title, message); err != nil {
+		lumber.Debug("processors:idleNotify:notify.Send(): %s", err.Error())
+	}
+}
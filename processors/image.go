@@ -0,0 +1,27 @@
+package processors
+
+import (
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/code"
+	"github.com/nanobox-io/nanobox/processors/env"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/locker"
+)
+
+// Image sets up the environment and packages the compiled app as an image
+func Image(envModel *models.Env, tag string) error {
+	// the volumes can't change while we copy them
+	locker.LocalLock()
+	defer locker.LocalUnlock()
+
+	// init docker client and env mounts
+	if err := env.Setup(envModel); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	if err := code.Image(envModel, tag); err != nil {
+		return util.ErrorAppend(err, "failed to package the image")
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package processors
+
+import (
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"github.com/nanobox-io/nanobox-boxfile"
+	"golang.org/x/net/context"
+
+	container_generator "github.com/nanobox-io/nanobox/generators/containers"
+	"github.com/nanobox-io/nanobox/models"
+	process_provider "github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/dhcp"
+	"github.com/nanobox-io/nanobox/util/secretref"
+)
+
+// RunJob runs a command in a short-lived container for a component of the
+// app, streams its output and removes the container when it exits
+func RunJob(appModel *models.App, name string, command []string) error {
+	// init docker client
+	if err := process_provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	componentModel, err := jobComponent(appModel, name)
+	if err != nil {
+		return err
+	}
+
+	code, err := runJobContainer(appModel, componentModel, shellJoin(command))
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return util.Errorf("[USER] the job exited with code %d", code)
+	}
+
+	return nil
+}
+
+// shellJoin joins the arguments into a command for /bin/sh, each argument is
+// quoted so the shell sees the same argv the user typed
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote single quotes an argument unless it's made of characters the
+// shell leaves alone
+func shellQuote(arg string) string {
+	if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:,@%+") == "" {
+		return arg
+	}
+
+	return "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+}
+
+// runJobContainer runs the command in a job container, streams its output
+// and returns its exit code once the container is removed
+func runJobContainer(appModel *models.App, componentModel *models.Component, command string) (int, error) {
+	// the evars may reference a secret backend, it has to answer first
+	if _, err := secretref.ResolveAll(appModel.EvarsFor(componentModel.Name)); err != nil {
+		return 0, util.Errorf("[USER] %s", err.Error())
+	}
+
+	ip, err := dhcp.ReserveLocal()
+	if err != nil {
+		lumber.Error("processors:runJobContainer:dhcp.ReserveLocal(): %s", err.Error())
+		return 0, util.ErrorAppend(err, "failed to reserve an ip for the job")
+	}
+	defer dhcp.ReturnIP(ip)
+
+	id := strconv.FormatInt(time.Now().UnixNano(), 10)
+	config := container_generator.JobConfig(appModel, componentModel, id, ip.String(), command)
+
+	if err := downloadImage(config.Image); err != nil {
+		return 0, err
+	}
+
+	container, err := docker.CreateContainer(config)
+	if err != nil {
+		lumber.Error("processors:runJobContainer:docker.CreateContainer(%s): %s", config.Name, err.Error())
+		return 0, util.ErrorAppend(err, "failed to start the job container")
+	}
+	defer docker.ContainerRemove(container.ID)
+
+	// remove the container if the user bails out, which ends the wait below
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt)
+	defer signal.Stop(sigChan)
+	go func() {
+		if _, ok := <-sigChan; ok {
+			docker.ContainerRemove(container.ID)
+		}
+	}()
+
+	return waitJob(container.ID)
+}
+
+// jobComponent finds the component a job runs for. Code nodes don't have a
+// component in dev, so a stub is returned for them.
+func jobComponent(appModel *models.App, name string) (*models.Component, error) {
+	box := boxfile.New([]byte(appModel.DeployedBoxfile))
+	for _, node := range box.Nodes("code") {
+		if node == name {
+			return &models.Component{AppID: appModel.ID, Name: name, Type: "code"}, nil
+		}
+	}
+
+	componentModel, err := models.FindComponentBySlug(appModel.ID, name)
+	if err != nil || componentModel.Type != "data" {
+		return nil, util.Errorf("[USER] '%s' is not a code or data component of the app", name)
+	}
+
+	return componentModel, nil
+}
+
+// waitJob streams the output of the job until it exits, and returns its
+// exit code
+func waitJob(id string) (int, error) {
+	opts := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	}
+
+	rc, err := docker.Client.ContainerLogs(context.Background(), id, opts)
+	if err != nil {
+		lumber.Error("processors:waitJob:docker.Client.ContainerLogs(%s): %s", id, err.Error())
+		return 0, util.ErrorAppend(err, "failed to stream the job output")
+	}
+	defer rc.Close()
+
+	if _, err := stdcopy.StdCopy(os.Stdout, os.Stderr, rc); err != nil {
+		lumber.Error("processors:waitJob:stdcopy.StdCopy(): %s", err.Error())
+	}
+
+	code, err := docker.Client.ContainerWait(context.Background(), id)
+	if err != nil {
+		lumber.Error("processors:waitJob:docker.Client.ContainerWait(%s): %s", id, err.Error())
+		return 0, util.ErrorAppend(err, "failed to wait for the job")
+	}
+
+	return code, nil
+}
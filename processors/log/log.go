@@ -2,20 +2,18 @@
 package log
 
 import (
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
+	"sort"
 	"time"
 
 	"github.com/jcelliott/lumber"
 	"github.com/nanopack/logvac/core"
 	"github.com/nanopack/mist/core"
-	"golang.org/x/net/websocket"
 
 	"github.com/nanobox-io/nanobox/commands/registry"
 	"github.com/nanobox-io/nanobox/helpers"
@@ -24,6 +22,7 @@ import (
 	"github.com/nanobox-io/nanobox/util/config"
 	"github.com/nanobox-io/nanobox/util/display"
 	"github.com/nanobox-io/nanobox/util/odin"
+	"github.com/nanobox-io/nanobox/util/stream"
 )
 
 // Tail tails production logs for an app.
@@ -60,8 +59,18 @@ func Tail(envModel *models.Env, app string, logOpts models.LogOpts) error {
 		return util.ErrorAppend(err, "unable to generate mist config")
 	}
 
+	// the entries missed while the stream was down are fetched from logvac
+	replay := func(since int64) (int64, error) {
+		token, url, err := odin.GetComponent(appID, "logger")
+		if err != nil {
+			lumber.Error("log:Tail:odin.GetComponent(%s, logger): %s", appID, err.Error())
+			return since, err
+		}
+		return replayLogs(token, url, since, logOpts)
+	}
+
 	// fmt.Println("mistConfig", mistConfig)
-	err = mistListen(mistConfig.Token, mistConfig.URL, logOpts)
+	err = mistListen(mistConfig.Token, mistConfig.URL, logOpts, replay)
 	if err != nil {
 		return util.ErrorAppend(err, "failed to subscribe to logs")
 	}
@@ -130,23 +139,28 @@ func getMistConfig(envModel *models.Env, appID string) (*MistConfig, error) {
 	return &MistConfig{url, token}, nil
 }
 
-// mistListen will subscribe to mist and print incoming logs.
-func mistListen(token, url string, logOpts models.LogOpts) error {
+// mistListen will subscribe to mist and print incoming logs. A dropped
+// connection is re-established and the entries missed meanwhile are replayed
+// so the output stays complete.
+func mistListen(token, url string, logOpts models.LogOpts, replay func(since int64) (int64, error)) error {
 	logFollow := logOpts.Follow
-	// connect to the mist server
-	var wsConn *websocket.Conn
+	// connect to the mist server and subscribe to all logs
+	var client stream.MessageStream
 	clientConnect := func() (err error) {
-		wsConn, err = newMistClient(token, url)
-		return err
+		client, err = stream.NewWebSocket(stream.WebSocketURL(url, token))
+		if err != nil {
+			return err
+		}
+		return client.Subscribe([]string{"log"})
 	}
 	if err := util.Retry(clientConnect, 3, time.Second); err != nil {
 		return err
 	}
-
-	// subscribe to all logs
-	if err := subscribe(wsConn); err != nil {
-		return err
-	}
+	defer func() {
+		if client != nil {
+			client.Close()
+		}
+	}()
 
 	// catch kill signals
 	sigChan := make(chan os.Signal, 1)
@@ -166,83 +180,96 @@ waiting for output...
 
 	// loop waiting for messages or signals if we recieve a kill signal quit
 	// messages will be displayed
-	// msgChan := client.Messages()
+	// the time of the newest entry shown, entries up to it are skipped after
+	// a replay so nothing is printed twice
+	var lastSeen, replayed int64
+
 	for {
 		select {
-		case msg := <-messageChan:
+		case msg := <-client.Messages():
+			t := entryTime(msg)
+			if t != 0 && t <= replayed {
+				continue
+			}
+			if t > lastSeen {
+				lastSeen = t
+			}
 			display.FormatLogMessage(msg, logOpts.Raw)
+		case <-client.Done():
+			client.Close()
+			display.Warn("Lost the connection to the log stream, reconnecting...\n")
+
+			if err := util.Retry(clientConnect, 30, 2*time.Second); err != nil {
+				return util.ErrorAppend(err, "failed to reconnect to the log stream")
+			}
+
+			// nothing was seen yet, so there is nothing to catch up from
+			if lastSeen == 0 || replay == nil {
+				continue
+			}
+
+			newest, err := replay(lastSeen)
+			if err != nil {
+				lumber.Error("log:mistListen:replay(%d): %s", lastSeen, err.Error())
+				display.Warn("Some log entries may be missing, failed to fetch them: %s\n", err.Error())
+				continue
+			}
+			lastSeen, replayed = newest, newest
 		case <-sigChan:
 			return nil
 		}
 	}
 }
 
-// todo: make part of mist wsclient
+// entryTime returns the time of a log entry in nanoseconds, or 0 if the
+// entry has no time
+func entryTime(msg mist.Message) int64 {
+	entry := display.Entry{}
+	if err := json.Unmarshal([]byte(msg.Data), &entry); err != nil || entry.Time.IsZero() {
+		return 0
+	}
 
-var messageChan chan mist.Message
+	return entry.Time.UnixNano()
+}
 
-func newMistClient(token, address string) (*websocket.Conn, error) {
-	origin := "https://nanoapp.localhost"
-	url := "wss://" + address + ":1446/subscribe/websocket?X-AUTH-TOKEN=" + token
+// logvacMessages sorts logvac messages oldest first
+type logvacMessages []logvac.Message
 
-	config, err := websocket.NewConfig(url, origin)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create config - %s", err.Error())
-	}
+func (m logvacMessages) Len() int           { return len(m) }
+func (m logvacMessages) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m logvacMessages) Less(i, j int) bool { return m[i].Time.Before(m[j].Time) }
 
-	config.TlsConfig = &tls.Config{InsecureSkipVerify: true}
-
-	ws, err := websocket.DialConfig(config)
+// replayLogs prints the logs newer than since and returns the time of the
+// newest entry printed
+func replayLogs(token, url string, since int64, logOpts models.LogOpts) (int64, error) {
+	body, err := rest(url, "GET", fmt.Sprintf("/logs?type=app&id=&start=0&end=%d&limit=%d", since+1, replayLimit), token, token)
 	if err != nil {
-		return nil, fmt.Errorf("failed to dial mist - %s", err.Error())
+		return since, fmt.Errorf("failed to get logs - %s", err.Error())
 	}
 
-	messageChan = make(chan mist.Message, 1)
-	// connection loop (blocking); continually read off the connection. Once something
-	// is read, check to see if it's a message the client understands to be one of
-	// its commands. If so attempt to execute the command.
-	go func() {
-		decoder := json.NewDecoder(ws)
-
-		for decoder.More() {
-			msg := mist.Message{}
-
-			// decode an array value (Message)
-			if err := decoder.Decode(&msg); err != nil {
-				// invalid character '\x15' looking for beginning of value
-				if strings.Contains(err.Error(), "invalid character '\\x15'") {
-					fmt.Printf("Must dial TLS - %s\n", err.Error())
-					return
-				}
-
-				// an error decoding should be sent to the user
-				reader := decoder.Buffered()
-				bytes, _ := ioutil.ReadAll(reader)
-				msg.Error = string(bytes)
-			}
+	msgs := logvacMessages{}
+	if err := json.Unmarshal(body, &msgs); err != nil {
+		return since, fmt.Errorf("failed to process logs - %s", err.Error())
+	}
+	sort.Sort(msgs)
 
-			messageChan <- msg
+	newest := since
+	for i := range msgs {
+		t := msgs[i].Time.UnixNano()
+		if t <= since {
+			continue
 		}
-	}()
-
-	return ws, nil
-}
-
-type mistCommand struct {
-	Command string   `json:"command"`
-	Tags    []string `json:"tags"`
-}
 
-func subscribe(ws *websocket.Conn) error {
-	b, err := json.Marshal(mistCommand{"subscribe", []string{"log"}})
-	if err != nil {
-		return err
+		display.FormatLogvacMessage(msgs[i], logOpts.Raw)
+		newest = t
 	}
 
-	_, err = ws.Write(b)
-	return err
+	return newest, nil
 }
 
+// the most entries fetched to fill the gap of a dropped connection
+const replayLimit = 1000
+
 // fetchLogs fetches and prints historic logs
 func fetchLogs(token, url string, logOpts models.LogOpts) error {
 	numLogs := logOpts.Number
@@ -10,6 +10,10 @@ import (
 	"github.com/nanobox-io/nanobox/util/odin"
 )
 
+func init() {
+	odin.Reauthenticate = Reauthenticate
+}
+
 // Process ...
 func Login(username, password, endpoint string) error {
 
@@ -18,6 +22,11 @@ func Login(username, password, endpoint string) error {
 		username = os.Getenv("NANOBOX_USERNAME")
 	}
 
+	// without a terminal there is no one to answer the prompts
+	if (username == "" || password == "") && !display.CanPrompt {
+		return util.Errorf("[AUTH] no credentials to log in with, set NANOBOX_TOKEN (or NANOBOX_USERNAME and NANOBOX_PASSWORD) or pass --token")
+	}
+
 	if username == "" {
 		user, err := display.ReadUsername()
 		if err != nil {
@@ -39,13 +48,7 @@ func Login(username, password, endpoint string) error {
 		password = pass
 	}
 
-	if endpoint == "" && os.Getenv("NANOBOX_ENDPOINT") != "" {
-		endpoint = os.Getenv("NANOBOX_ENDPOINT")
-	}
-
-	if endpoint == "" {
-		endpoint = "nanobox"
-	}
+	endpoint = loginEndpoint(endpoint)
 
 	// set the odin endpoint
 	odin.SetEndpoint(endpoint)
@@ -72,3 +75,29 @@ func Login(username, password, endpoint string) error {
 
 	return nil
 }
+
+// Reauthenticate has the user log in again when their session expired in
+// the middle of a command. Without a terminal there is no one to ask.
+func Reauthenticate(endpoint string) error {
+	if !display.CanPrompt {
+		return odin.SessionExpired()
+	}
+
+	fmt.Printf("\n! Your nanobox session expired or was revoked, log in again to continue\n\n")
+
+	return Login("", "", endpoint)
+}
+
+// loginEndpoint returns the endpoint to log into, nanobox unless another is
+// given or set in NANOBOX_ENDPOINT
+func loginEndpoint(endpoint string) string {
+	if endpoint == "" && os.Getenv("NANOBOX_ENDPOINT") != "" {
+		endpoint = os.Getenv("NANOBOX_ENDPOINT")
+	}
+
+	if endpoint == "" {
+		endpoint = "nanobox"
+	}
+
+	return endpoint
+}
@@ -0,0 +1,114 @@
+package processors
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/odin"
+)
+
+// how long the browser has to send the token back
+const ssoTimeout = 5 * time.Minute
+
+// the page the browser lands on once the token was received
+const ssoCompletePage = `<html><body style="font-family: sans-serif; text-align: center; margin-top: 80px;">
+<h2>Nanobox is now logged in</h2>
+<p>You can close this window and return to your terminal.</p>
+</body></html>`
+
+// LoginSSO logs in through the dashboard in the browser, so orgs that
+// require single sign-on or two-factor authentication can use the cli. The
+// dashboard sends the token to a server listening on localhost.
+func LoginSSO(endpoint string) error {
+	endpoint = loginEndpoint(endpoint)
+
+	// set the odin endpoint
+	odin.SetEndpoint(endpoint)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		lumber.Error("login:LoginSSO:net.Listen(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to start the login callback server")
+	}
+	defer listener.Close()
+
+	// the state makes sure the token comes from the login we started
+	state, err := ssoState()
+	if err != nil {
+		lumber.Error("login:LoginSSO:ssoState(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to generate the login state")
+	}
+	logins := make(chan models.Auth, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(rw http.ResponseWriter, req *http.Request) {
+		token := req.URL.Query().Get("token")
+		if req.URL.Query().Get("state") != state || token == "" {
+			http.Error(rw, "invalid login request", http.StatusBadRequest)
+			return
+		}
+
+		rw.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(rw, ssoCompletePage)
+
+		// orgs with short lived tokens also send a refresh token
+		auth := models.Auth{
+			Endpoint:   endpoint,
+			Key:        token,
+			RefreshKey: req.URL.Query().Get("refresh_token"),
+		}
+
+		select {
+		case logins <- auth:
+		default:
+		}
+	})
+	go http.Serve(listener, mux)
+
+	callback := fmt.Sprintf("http://%s/callback", listener.Addr().String())
+	authURL := odin.AuthURL(callback, state)
+
+	fmt.Printf("\nOpening your browser to log in, if it doesn't open visit:\n\n  %s\n\n", authURL)
+	if err := util.OpenBrowser(authURL); err != nil {
+		lumber.Debug("login:LoginSSO:util.OpenBrowser(): %s", err.Error())
+	}
+
+	display.StartTask("Waiting for the browser")
+
+	var auth models.Auth
+	select {
+	case auth = <-logins:
+		display.StopTask()
+	case <-time.After(ssoTimeout):
+		display.ErrorTask()
+		return util.Errorf("[AUTH] the login wasn't completed in the browser within %s, run 'nanobox login --sso' to try again", ssoTimeout)
+	}
+
+	// store the user token
+	if auth.Save() != nil {
+		return util.Errorf("unable to save user authentication")
+	}
+
+	display.LoginComplete()
+
+	return nil
+}
+
+// ssoState returns a state for the login that can't be guessed
+func ssoState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,111 @@
+package processors
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/migrate"
+)
+
+// migrationSources are the files we know how to migrate, in order of preference
+var migrationSources = []string{"docker-compose.yml", "docker-compose.yaml", "Vagrantfile"}
+
+// MigrationReport is the file the migration report is written to
+const MigrationReport = "nanobox-migration.txt"
+
+// MigrationEvars is the file the environment of the migrated services is
+// written to
+const MigrationEvars = "nanobox.env"
+
+// Migrate generates a boxfile from source, a Vagrantfile or docker-compose
+// file, and writes a report of anything it couldn't translate. The source is
+// looked for in dir if it's empty.
+func Migrate(dir, source string) error {
+	boxfilePath := filepath.Join(dir, "boxfile.yml")
+	if _, err := os.Stat(boxfilePath); err == nil {
+		return util.Errorf("[USER] a boxfile.yml already exists in %s", dir)
+	}
+
+	if source == "" {
+		for _, name := range migrationSources {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				source = name
+				break
+			}
+		}
+	}
+
+	if source == "" {
+		return util.Errorf("[USER] no Vagrantfile or docker-compose.yml found in %s, start a new app with 'nanobox init --template'", dir)
+	}
+
+	sourcePath := source
+	if !filepath.IsAbs(sourcePath) {
+		sourcePath = filepath.Join(dir, source)
+	}
+
+	if _, err := os.Stat(sourcePath); err != nil {
+		return util.Errorf("[USER] %s doesn't exist", source)
+	}
+
+	display.StartTask("Migrating %s", source)
+	defer display.StopTask()
+
+	data, err := ioutil.ReadFile(sourcePath)
+	if err != nil {
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to read %s", source)
+	}
+
+	var result *migrate.Result
+	if filepath.Base(source) == "Vagrantfile" {
+		result, err = migrate.FromVagrantfile(source, data)
+	} else {
+		result, err = migrate.FromCompose(source, data)
+	}
+	if err != nil {
+		display.ErrorTask()
+		lumber.Error("Migrate:migrate.From(%s): %s", source, err.Error())
+		return util.Errorf("[USER] %s", err.Error())
+	}
+
+	result.Engine = migrate.DetectEngine(dir)
+	if result.Engine == "" {
+		result.Untranslated = append(result.Untranslated, "could not detect the language of the project, set the engine in boxfile.yml")
+	}
+
+	if err := ioutil.WriteFile(boxfilePath, []byte(result.Boxfile()), 0644); err != nil {
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to write boxfile.yml")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, MigrationReport), []byte(result.Report()), 0644); err != nil {
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to write the migration report")
+	}
+
+	if len(result.Evars) > 0 {
+		if err := ioutil.WriteFile(filepath.Join(dir, MigrationEvars), []byte(result.EnvFile()), 0600); err != nil {
+			display.ErrorTask()
+			return util.ErrorAppend(err, "failed to write the evars")
+		}
+	}
+
+	display.StopTask()
+
+	fmt.Printf("\nGenerated boxfile.yml from %s.\n", source)
+	if len(result.Evars) > 0 {
+		fmt.Printf("The environment of the app is in %s, load it with 'nanobox evar load local %s'\n", MigrationEvars, MigrationEvars)
+	}
+	if len(result.Untranslated) > 0 {
+		fmt.Printf("%d item(s) need your attention, see %s\n\n", len(result.Untranslated), MigrationReport)
+	}
+
+	return nil
+}
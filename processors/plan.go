@@ -0,0 +1,79 @@
+package processors
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	"github.com/nanobox-io/nanobox/helpers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/component"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/odin"
+)
+
+// Plan prints what a deploy of the last build would change on a live app.
+// The running boxfile is the one of the last deploy from this machine, the
+// evars of a live app are set on the platform and aren't part of a deploy.
+func Plan(envModel *models.Env, deployConfig DeployConfig) error {
+
+	appID := deployConfig.App
+
+	// fetch the remote
+	remote, ok := envModel.Remotes[appID]
+	if ok {
+		// set the odin endpoint
+		odin.SetEndpoint(remote.Endpoint)
+		// set the app id
+		appID = remote.ID
+	}
+
+	// set the app id to the directory name if it's default
+	if appID == "default" {
+		appID = config.AppName()
+	}
+
+	// validate access to the app
+	if err := helpers.ValidateOdinApp(appID); err != nil {
+		return util.ErrorAppend(err, "unable to validate app")
+	}
+
+	if envModel.BuiltBoxfile == "" {
+		return util.Errorf("[USER] there is no build to plan a deploy for, run 'nanobox build-runtime' first")
+	}
+
+	box, err := remoteBoxfile(envModel, deployConfig.App)
+	if err != nil {
+		return err
+	}
+
+	live, err := models.LiveDeployRecords(appID)
+	if err != nil {
+		lumber.Error("plan:models.LiveDeployRecords(%s): %s", appID, err.Error())
+		return util.ErrorAppend(err, "failed to load the deploy history")
+	}
+
+	deployed := boxfile.New([]byte{})
+	if len(live) > 0 {
+		deployed = boxfile.New([]byte(live[0].Boxfile))
+	}
+
+	// someone else may have deployed since
+	previous, err := odin.GetPreviousBuild(appID)
+	if err != nil {
+		lumber.Error("plan:odin.GetPreviousBuild(%s): %s", appID, err.Error())
+		return util.ErrorAppend(err, "failed to query previous deploys from nanobox")
+	}
+
+	if previous != "" && (len(live) == 0 || live[0].BuildID != previous) {
+		fmt.Printf("\n! The last deploy to %s wasn't made from this machine, the plan may be incomplete\n", deployConfig.App)
+	}
+
+	fmt.Printf("\nDeploy plan for %s:\n", deployConfig.App)
+	component.PrintPlan(os.Stdout, component.PlanComponents(boxfile.New([]byte(box)), deployed), nil)
+
+	return nil
+}
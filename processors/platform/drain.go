@@ -0,0 +1,193 @@
+package platform
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	generator "github.com/nanobox-io/nanobox/generators/router"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// webPort is the port the router forwards web traffic to
+const webPort = 8080
+
+// healthTimeout is how long we wait for a restarted web component to respond
+const healthTimeout = 60 * time.Second
+
+// DrainPortal stops the router from sending traffic to the web components
+// and waits for their in-flight requests to finish, up to the configured
+// drain timeout
+func DrainPortal(appModel *models.App) error {
+	webModels := webComponents(appModel)
+
+	// nothing is serving traffic yet
+	if len(webModels) == 0 {
+		return nil
+	}
+
+	display.StartTask("Draining router")
+	defer display.StopTask()
+
+	client := portalClient(appModel)
+	routes := generator.BuildDrainRoutes(appModel)
+
+	updateRoute := func() error {
+		return client.UpdateRoutes(routes)
+	}
+
+	// use the retry method here because there is a chance the portal server isnt responding yet
+	if err := util.Retry(updateRoute, 2, time.Second); err != nil {
+		lumber.Error("platform:DrainPortal:UpdateRoutes(%+v): %s", routes, err.Error())
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to send drain routes to the router")
+	}
+
+	config, _ := models.LoadConfig()
+	deadline := time.Now().Add(time.Duration(config.DrainTimeout) * time.Second)
+
+	for _, componentModel := range webModels {
+		for activeConnections(componentModel) > 0 {
+			if time.Now().After(deadline) {
+				lumber.Info("platform:DrainPortal: drain timeout reached with open connections on %s", componentModel.Name)
+				return nil
+			}
+			<-time.After(250 * time.Millisecond)
+		}
+	}
+
+	return nil
+}
+
+// WaitHealthy waits for every web component to answer its healthcheck with a
+// 2xx or 3xx, so the router isn't pointed at a component that is still
+// booting
+func WaitHealthy(appModel *models.App) error {
+	client := &http.Client{Timeout: 2 * time.Second}
+	deadline := time.Now().Add(healthTimeout)
+
+	for _, componentModel := range webComponents(appModel) {
+		url := healthURL(appModel, componentModel)
+
+		for {
+			res, err := client.Get(url)
+			if err == nil {
+				res.Body.Close()
+
+				// a booting app may answer with a server error first
+				if res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusBadRequest {
+					break
+				}
+				err = fmt.Errorf("responded with %s", res.Status)
+			}
+
+			if time.Now().After(deadline) {
+				lumber.Error("platform:WaitHealthy:http.Get(%s): %s", url, err.Error())
+				return util.Errorf("[USER] %s did not become healthy on port %d within %s", componentModel.Name, webPort, healthTimeout)
+			}
+
+			<-time.After(500 * time.Millisecond)
+		}
+	}
+
+	return nil
+}
+
+// VerifyHealthy keeps checking the web components for the length of the
+// window. A component that stops responding or answers with a server error
+// fails the verification.
+func VerifyHealthy(appModel *models.App, window time.Duration) error {
+	client := &http.Client{Timeout: 2 * time.Second}
+	deadline := time.Now().Add(window)
+
+	for time.Now().Before(deadline) {
+		for _, componentModel := range webComponents(appModel) {
+			url := healthURL(appModel, componentModel)
+
+			res, err := client.Get(url)
+			if err != nil {
+				lumber.Error("platform:VerifyHealthy:http.Get(%s): %s", url, err.Error())
+				return util.Errorf("[USER] %s stopped responding on port %d", componentModel.Name, webPort)
+			}
+			res.Body.Close()
+
+			if res.StatusCode >= http.StatusInternalServerError {
+				lumber.Error("platform:VerifyHealthy:http.Get(%s): %s", url, res.Status)
+				return util.Errorf("[USER] %s responded with %s", componentModel.Name, res.Status)
+			}
+		}
+
+		<-time.After(time.Second)
+	}
+
+	return nil
+}
+
+// healthURL returns the url a web component is checked on, the path of its
+// healthcheck or /
+func healthURL(appModel *models.App, componentModel *models.Component) string {
+	box := boxfile.New([]byte(appModel.DeployedBoxfile))
+
+	path := box.Node(componentModel.Name).StringValue("healthcheck")
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return fmt.Sprintf("http://%s:%d%s", componentModel.IPAddr(), webPort, path)
+}
+
+// webComponents returns the web components that currently exist
+func webComponents(appModel *models.App) []*models.Component {
+	box := boxfile.New([]byte(appModel.DeployedBoxfile))
+	componentModels := []*models.Component{}
+
+	for _, name := range box.Nodes("web") {
+		componentModel, err := models.FindComponentBySlug(appModel.ID, name)
+		if err != nil || componentModel.ID == "" {
+			continue
+		}
+		componentModels = append(componentModels, componentModel)
+	}
+
+	return componentModels
+}
+
+// activeConnections counts the established connections to the web port
+// inside the component
+func activeConnections(componentModel *models.Component) int {
+	out, err := util.DockerExec(componentModel.ID, "root", "cat", []string{"/proc/net/tcp", "/proc/net/tcp6"}, nil)
+	if err != nil {
+		// if we can't tell, don't hold up the restart
+		lumber.Debug("platform:activeConnections:util.DockerExec(%s): %s", componentModel.ID, err.Error())
+		return 0
+	}
+
+	return countConnections(out, webPort)
+}
+
+// countConnections counts the established connections on a local port in
+// the contents of /proc/net/tcp
+func countConnections(procNetTCP string, port int) int {
+	suffix := fmt.Sprintf(":%04X", port)
+	count := 0
+
+	for _, line := range strings.Split(procNetTCP, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+
+		// fields[1] is the local address, fields[3] the state. 01 is ESTABLISHED
+		if strings.HasSuffix(fields[1], suffix) && fields[3] == "01" {
+			count++
+		}
+	}
+
+	return count
+}
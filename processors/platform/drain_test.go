@@ -0,0 +1,19 @@
+package platform
+
+import (
+	"testing"
+)
+
+func TestCountConnections(t *testing.T) {
+	procNetTCP := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1 0000000000000000 100 0 0 10 0
+   1: 0B0011AC:1F90 0100A8C0:D2F4 01 00000000:00000000 00:00000000 00000000     0        0 0 1 0000000000000000 20 4 30 10 -1
+   2: 0B0011AC:1F90 0100A8C0:D2F6 01 00000000:00000000 00:00000000 00000000     0        0 0 1 0000000000000000 20 4 30 10 -1
+   3: 0B0011AC:1F90 0100A8C0:D2F8 06 00000000:00000000 03:00000000 00000000     0        0 0 3 0000000000000000
+   4: 0B0011AC:1538 0100A8C0:D2FA 01 00000000:00000000 00:00000000 00000000     0        0 0 1 0000000000000000 20 4 30 10 -1
+`
+
+	if count := countConnections(procNetTCP, 8080); count != 2 {
+		t.Errorf("expected 2 established connections, got %d", count)
+	}
+}
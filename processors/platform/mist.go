@@ -1,40 +1,74 @@
 package platform
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
 	"time"
 
-	"github.com/nanopack/mist/clients"
+	"github.com/jcelliott/lumber"
 
 	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/sidecar"
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/notify"
+	"github.com/nanobox-io/nanobox/util/stream"
 )
 
-// MistListen ...
+// MistListen prints the logs published on the app's mist until the user
+// quits, over the transport of the mist-transport config
 func MistListen(appModel *models.App) error {
 	mist, err := models.FindComponentBySlug(appModel.ID, "mist")
 	if err != nil {
 		return err
 	}
 
+	config, _ := models.LoadConfig()
+
 	// connect to the mist server
-	var client *clients.TCP
+	var client stream.MessageStream
 	clientConnect := func() (err error) {
-		client, err = clients.New(mist.IPAddr()+":1445", "123")
+		client, err = stream.Dial(config.MistTransport, mist.IPAddr(), "123")
 		return err
 	}
 	if err := util.Retry(clientConnect, 3, time.Second); err != nil {
-		return err
+		return util.ErrorAppend(err, "failed to connect to mist over %s", config.MistTransport)
 	}
 
+	// keep a recording to replay with the file transport
+	if config.MistRecord != "" {
+		recorder, err := stream.Record(client, config.MistRecord)
+		if err != nil {
+			client.Close()
+			return util.ErrorAppend(err, "failed to record the log stream")
+		}
+		client = recorder
+	}
+	defer client.Close()
+
 	// subscribe to all logs
 	if err := client.Subscribe([]string{"log"}); err != nil {
 		return err
 	}
 
+	publish := func(entry display.Entry) {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return
+		}
+		if err := client.Publish([]string{"log", "app"}, string(data)); err != nil {
+			lumber.Error("platform:MistListen:client.Publish(): %s", err.Error())
+		}
+	}
+
+	// report component crashes and restarts in the log stream
+	go WatchCrashes(appModel, publish)
+
+	// sidecars have no log shipper, so their output is published from here
+	sidecar.StreamLogs(appModel, publish)
+
 	// catch kill signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt)
@@ -48,12 +82,19 @@ waiting for output...
 
 `)
 
+	// the deploy is done, the user ends the stream
+	notify.Finish(false)
+
 	// loop waiting for messages or signals if we recieve a kill signal quit
 	// messages will be displayed
 	for {
 		select {
 		case msg := <-client.Messages():
 			display.FormatLogMessage(msg, false)
+		case <-client.Done():
+			// the connection dropped, or the recording was replayed
+			display.Info("\nThe log stream ended\n\n")
+			return nil
 		case <-sigChan:
 			return nil
 		}
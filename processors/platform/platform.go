@@ -44,3 +44,13 @@ var setupComponents = []PlatformComponent{
 		image: "nanobox/hoarder",
 	},
 }
+
+// Images returns the images of the platform components
+func Images() []string {
+	images := []string{}
+	for _, component := range setupComponents {
+		images = append(images, component.image)
+	}
+
+	return images
+}
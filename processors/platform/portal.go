@@ -53,6 +53,25 @@ func UpdatePortal(appModel *models.App) error {
 	return nil
 }
 
+// RouteCanary sends the canary share of the web traffic to the web
+// components and the rest to the stable components they replace
+func RouteCanary(appModel *models.App, stable map[string]*models.Component, percent int) error {
+	client := portalClient(appModel)
+	routes := generator.BuildCanaryRoutes(appModel, stable, percent)
+
+	updateRoute := func() error {
+		return client.UpdateRoutes(routes)
+	}
+
+	// use the retry method here because there is a chance the portal server isnt responding yet
+	if err := util.Retry(updateRoute, 2, time.Second); err != nil {
+		lumber.Error("platform:RouteCanary:UpdateRoutes(%+v): %s", routes, err.Error())
+		return util.ErrorAppend(err, "failed to send canary routes to the router")
+	}
+
+	return nil
+}
+
 //
 func portalClient(appModel *models.App) portal.PortalClient {
 	return portal.New(appModel.LocalIPs["env"]+":8443", "123")
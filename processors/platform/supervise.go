@@ -0,0 +1,98 @@
+package platform
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/filters"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// containerEvent is the part of a docker event the supervisor reads
+type containerEvent struct {
+	Action string
+	Actor  struct {
+		Attributes map[string]string
+	}
+}
+
+// WatchCrashes follows the docker events of the app's containers and calls
+// publish with a log entry whenever a component dies or is restarted by its
+// restart policy. It returns when the event stream closes.
+func WatchCrashes(appModel *models.App, publish func(entry display.Entry)) {
+	args := filters.NewArgs()
+	args.Add("type", "container")
+	args.Add("event", "die")
+	args.Add("event", "restart")
+	args.Add("event", "start")
+
+	rc, err := docker.Client.Events(context.Background(), types.EventsOptions{Filters: args})
+	if err != nil {
+		lumber.Error("platform:WatchCrashes:docker.Client.Events(): %s", err.Error())
+		return
+	}
+	defer rc.Close()
+
+	prefix := fmt.Sprintf("nanobox_%s_", appModel.ID)
+
+	// components that died and are waiting on their restart policy
+	crashed := map[string]bool{}
+
+	decoder := json.NewDecoder(rc)
+	for {
+		event := containerEvent{}
+		if err := decoder.Decode(&event); err != nil {
+			if err != io.EOF {
+				lumber.Error("platform:WatchCrashes:json.Decode(): %s", err.Error())
+			}
+			return
+		}
+
+		name := event.Actor.Attributes["name"]
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		component := strings.TrimPrefix(name, prefix)
+
+		switch event.Action {
+		case "die":
+			// a zero exit code is a clean stop, not a crash
+			exitCode := event.Actor.Attributes["exitCode"]
+			if exitCode == "0" {
+				continue
+			}
+			crashed[component] = true
+			publish(crashEntry(component, fmt.Sprintf("%s crashed (exit code %s)", component, exitCode), 3))
+		case "start", "restart":
+			if !crashed[component] {
+				continue
+			}
+			delete(crashed, component)
+			publish(crashEntry(component, fmt.Sprintf("%s was restarted", component), 5))
+		}
+	}
+}
+
+// crashEntry builds a log entry for the app log stream
+func crashEntry(component, message string, priority int) display.Entry {
+	now := time.Now()
+
+	return display.Entry{
+		Time:     now,
+		UTime:    int(now.UnixNano()),
+		ID:       "nanobox",
+		Tag:      []string{component + "[supervisor]", component},
+		Type:     "app",
+		Priority: priority,
+		Message:  message,
+	}
+}
@@ -0,0 +1,211 @@
+package processors
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/processors/server"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/dns"
+)
+
+const (
+	// the domain the previews are served under, ie: feature-login.blog.nanobox.dev
+	previewDomain = "nanobox.dev"
+	// the longest branch part of a preview name
+	maxPreviewName = 40
+)
+
+// the names end up in container names and hostnames
+var unsafePreviewChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Preview deploys the last build to the preview environment of a git branch,
+// the current one unless a branch is given. Every branch gets its own
+// environment, with its own IPs, data and hostname, and it keeps running
+// until it's cleaned up.
+func Preview(envModel *models.Env, branch string) error {
+	if branch == "" {
+		current, err := currentBranch()
+		if err != nil {
+			return err
+		}
+		branch = current
+	}
+
+	name := previewName(branch)
+	if name == models.PreviewPrefix {
+		return util.Errorf("[USER] '%s' can't be turned into an environment name, give another with --branch", branch)
+	}
+
+	appModel, _ := models.FindAppBySlug(envModel.ID, name)
+	if !appModel.IsNew() && appModel.Branch != "" && appModel.Branch != branch {
+		return util.Errorf("[USER] the %s environment is the preview of the %s branch, give another with --branch", name, appModel.Branch)
+	}
+
+	if err := app.Start(envModel, appModel, name); err != nil {
+		return util.ErrorAppend(err, "failed to start the preview environment")
+	}
+
+	if err := app.Deploy(envModel, appModel); err != nil {
+		return util.ErrorAppend(err, "failed to deploy the preview")
+	}
+
+	hostname := previewHostname(envModel, branch)
+	if err := previewDNS(appModel, hostname); err != nil {
+		return err
+	}
+
+	appModel.Branch = branch
+	appModel.PreviewedAt = time.Now()
+	if err := appModel.Save(); err != nil {
+		lumber.Error("processors:Preview:models.App.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to persist the preview")
+	}
+
+	display.Info("\n%s The %s branch is previewed at http://%s\n", display.TaskComplete, branch, hostname)
+	display.Info("  the other commands reach it with 'nanobox env use %s'\n\n", name)
+
+	return nil
+}
+
+// PreviewClean destroys the preview environments of the branches that are
+// gone, and the ones that weren't previewed for longer than olderThan. All of
+// them go with all.
+func PreviewClean(envModel *models.Env, olderThan time.Duration, all bool) error {
+	apps, err := envModel.Apps()
+	if err != nil {
+		lumber.Error("processors:PreviewClean:models.Env{ID:%s}.Apps(): %s", envModel.ID, err.Error())
+		return util.ErrorAppend(err, "failed to load app collection")
+	}
+
+	// outside of a repo the branches aren't known, only the age counts
+	branches, err := gitBranches()
+	if err != nil {
+		lumber.Debug("processors:PreviewClean:gitBranches(): %s", err.Error())
+	}
+
+	removed := 0
+	for _, appModel := range apps {
+		if !appModel.IsPreview() {
+			continue
+		}
+
+		reason := ""
+		switch {
+		case all:
+			reason = "removed"
+		case branches != nil && !branches[previewName(appModel.Branch)]:
+			reason = "the branch is gone"
+		case olderThan > 0 && !appModel.PreviewedAt.IsZero() && time.Since(appModel.PreviewedAt) > olderThan:
+			reason = fmt.Sprintf("last previewed %d days ago", int(time.Since(appModel.PreviewedAt).Hours()/24))
+		default:
+			continue
+		}
+
+		// 'dry-run' goes back to the default environment
+		if envModel.DryRun == appModel.Name {
+			envModel.DryRun = ""
+			if err := envModel.Save(); err != nil {
+				lumber.Error("processors:PreviewClean:models.Env.Save(): %s", err.Error())
+				return util.ErrorAppend(err, "failed to save the env")
+			}
+		}
+
+		if err := app.Destroy(appModel); err != nil {
+			return util.ErrorAppend(err, "failed to destroy the %s environment", appModel.Name)
+		}
+
+		display.Info("%s %s (%s)\n", display.TaskComplete, appModel.Name, reason)
+		removed++
+	}
+
+	if removed == 0 {
+		display.Info("\nThere are no stale preview environments\n\n")
+	}
+
+	return nil
+}
+
+// previewName returns the name of the preview environment of a branch, ie:
+// preview-feature-login for feature/Login
+func previewName(branch string) string {
+	slug := strings.Trim(unsafePreviewChars.ReplaceAllString(strings.ToLower(branch), "-"), "-")
+	if len(slug) > maxPreviewName {
+		slug = strings.TrimRight(slug[:maxPreviewName], "-")
+	}
+
+	return models.PreviewPrefix + slug
+}
+
+// previewHostname returns the hostname of a branch, ie:
+// feature-login.blog.nanobox.dev
+func previewHostname(envModel *models.Env, branch string) string {
+	appName := strings.Trim(unsafePreviewChars.ReplaceAllString(strings.ToLower(envModel.Name), "-"), "-")
+
+	return fmt.Sprintf("%s.%s.%s", strings.TrimPrefix(previewName(branch), models.PreviewPrefix), appName, previewDomain)
+}
+
+// previewDNS points the hostname at the preview environment. It's removed
+// with the environment.
+func previewDNS(appModel *models.App, hostname string) error {
+	entry := dns.Entry(appModel.LocalIPs["env"], hostname, appModel.ID)
+	if dns.Exists(entry) {
+		return nil
+	}
+
+	// the server adds the entry to the hosts file
+	if err := server.Setup(); err != nil {
+		return util.ErrorAppend(err, "failed to setup server")
+	}
+
+	if err := dns.Add(entry); err != nil {
+		lumber.Error("processors:previewDNS:dns.Add(%s): %s", entry, err.Error())
+		return util.ErrorAppend(err, "unable to add dns entry")
+	}
+
+	return nil
+}
+
+// currentBranch returns the git branch checked out in the app
+func currentBranch() (string, error) {
+	out, err := exec.Command("git", "-C", config.LocalDir(), "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		lumber.Debug("processors:currentBranch:git rev-parse: %s", err.Error())
+		return "", util.Errorf("[USER] the app isn't a git repo, give the branch to preview with --branch")
+	}
+
+	branch := strings.TrimSpace(string(out))
+	if branch == "HEAD" {
+		return "", util.Errorf("[USER] no branch is checked out, give the branch to preview with --branch")
+	}
+
+	return branch, nil
+}
+
+// gitBranches returns the preview names of the local and remote branches
+func gitBranches() (map[string]bool, error) {
+	branches := map[string]bool{}
+
+	// refs/heads/<branch> and refs/remotes/<remote>/<branch>
+	for ref, strip := range map[string]string{"refs/heads": "2", "refs/remotes": "3"} {
+		out, err := exec.Command("git", "-C", config.LocalDir(), "for-each-ref", "--format=%(refname:lstrip="+strip+")", ref).Output()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, branch := range strings.Fields(string(out)) {
+			branches[previewName(branch)] = true
+		}
+	}
+
+	return branches, nil
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"path/filepath"
-	"time"
 
 	"github.com/jcelliott/lumber"
 	"github.com/nanobox-io/golang-docker-client"
@@ -17,6 +16,7 @@ import (
 	// "github.com/nanobox-io/nanobox/util/fileutil"
 	"github.com/nanobox-io/nanobox/util/config"
 	"github.com/nanobox-io/nanobox/util/hookit"
+	"github.com/nanobox-io/nanobox/util/images"
 	"github.com/nanobox-io/nanobox/util/locker"
 )
 
@@ -129,14 +129,9 @@ func downloadImage(image string) error {
 		// Prefix: image,
 	}
 
-	imagePull := func() error {
-		_, err := docker.ImagePull(image, dockerPercent)
-		return err
-	}
-
-	if err := util.Retry(imagePull, 5, time.Second); err != nil {
+	if err := images.Download(image, dockerPercent); err != nil {
 		display.ErrorTask()
-		lumber.Error("dev:Setup:downloadImage.ImagePull(%s, nil): %s", image, err.Error())
+		lumber.Error("dev:Setup:downloadImage:images.Download(%s): %s", image, err.Error())
 		return util.ErrorAppend(err, "failed to pull docker image (%s)", image)
 	}
 
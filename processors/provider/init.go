@@ -16,13 +16,13 @@ func Init() error {
 	// load the docker environment
 	if err := provider.DockerEnv(); err != nil {
 		lumber.Error("provider:Init:provider.DockerEnv(): %s", err.Error())
-		return util.ErrorAppend(util.ErrorQuiet(err), "failed to load the docker environment")
+		return util.ErrorAppend(util.ErrorfQuiet("[PROVIDER] %s", err.Error()), "failed to load the docker environment")
 	}
 
 	// initialize the docker client
 	if err := docker.Initialize("env"); err != nil {
 		lumber.Error("provider:Init:docker.Initialize()")
-		return util.ErrorAppend(util.ErrorQuiet(err), "failed to initialize the docker client")
+		return util.ErrorAppend(util.ErrorfQuiet("[PROVIDER] %s", err.Error()), "failed to initialize the docker client")
 	}
 
 	// make sure we have the default ip
@@ -38,7 +38,7 @@ func Init() error {
 
 	// confirm it is up and working
 	if err := util.Retry(checkFunc, 20, time.Second); err != nil {
-		return util.Errorf("unable to communicate with Docker")
+		return util.Errorf("[PROVIDER] unable to communicate with Docker")
 	}
 
 	return nil
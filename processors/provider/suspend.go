@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/locker"
+	"github.com/nanobox-io/nanobox/util/provider"
+)
+
+// Suspend saves the state of the provider (the VM), the next Setup resumes it
+func Suspend() error {
+	locker.GlobalLock()
+	defer locker.GlobalUnlock()
+
+	display.OpenContext("Suspending Nanobox")
+	defer display.CloseContext()
+
+	if err := provider.Suspend(); err != nil {
+		lumber.Error("provider:Suspend:provider.Suspend(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to suspend the provider")
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package processors
+
+import (
+	"fmt"
+
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	process_provider "github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/images"
+	"github.com/nanobox-io/nanobox/util/secret"
+)
+
+// RegistryLogin verifies the credentials against a docker registry and
+// stores them, encrypted, for use when pulling images
+func RegistryLogin(host, username, password string) error {
+
+	if host == "" {
+		host = images.DefaultRegistry
+	}
+
+	if username == "" {
+		user, err := display.Ask(fmt.Sprintf("%s Username", host))
+		if err != nil {
+			return util.ErrorAppend(err, "unable to retrieve username")
+		}
+		username = user
+	}
+
+	if password == "" {
+		// ReadPassword prints Password: already
+		pass, err := display.ReadPassword(host)
+		if err != nil {
+			return util.ErrorAppend(err, "failed to read password")
+		}
+		password = pass
+	}
+
+	// init docker client
+	if err := process_provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	// let the registry verify the credentials
+	authConfig := types.AuthConfig{
+		Username:      username,
+		Password:      password,
+		ServerAddress: host,
+	}
+	if _, err := docker.Client.RegistryLogin(context.Background(), authConfig); err != nil {
+		lumber.Error("RegistryLogin:docker.Client.RegistryLogin(%s): %s", host, err.Error())
+		return util.Errorf("[USER] the registry rejected the credentials for %s: %s", host, err.Error())
+	}
+
+	encrypted, err := secret.Encrypt(password)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to encrypt registry password")
+	}
+
+	registryAuth := models.RegistryAuth{
+		Host:     host,
+		Username: username,
+		Password: encrypted,
+	}
+	if err := registryAuth.Save(); err != nil {
+		return util.ErrorAppend(err, "unable to save registry authentication")
+	}
+
+	fmt.Printf("%s Logged in to %s\n", display.TaskComplete, host)
+
+	return nil
+}
+
+// RegistryLogout removes the stored credentials of a docker registry
+func RegistryLogout(host string) error {
+
+	if host == "" {
+		host = images.DefaultRegistry
+	}
+
+	registryAuth, _ := models.FindRegistryAuthByHost(host)
+
+	// short-circuit if the auth is already deleted
+	if registryAuth.IsNew() {
+		fmt.Printf("%s Already logged out of %s\n", display.TaskComplete, host)
+		return nil
+	}
+
+	if err := registryAuth.Delete(); err != nil {
+		return util.ErrorAppend(err, "failed to delete registry authentication")
+	}
+
+	fmt.Printf("%s Logged out of %s\n", display.TaskComplete, host)
+
+	return nil
+}
+
+// RegistryList lists the registries with stored credentials
+func RegistryList() error {
+
+	registryAuths, err := models.AllRegistryAuths()
+	if err != nil {
+		return util.ErrorAppend(err, "failed to load registry authentications")
+	}
+
+	if len(registryAuths) == 0 {
+		fmt.Println("No registry credentials stored. Use 'nanobox registry login <host>' to add some.")
+		return nil
+	}
+
+	fmt.Println()
+	for _, registryAuth := range registryAuths {
+		fmt.Printf("  %s (%s)\n", registryAuth.Host, registryAuth.Username)
+	}
+	fmt.Println()
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package processors
+
+import (
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/code"
+	"github.com/nanobox-io/nanobox/processors/env"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/locker"
+)
+
+// BuildRemote sets up the environment and runs the code build on a remote
+// docker host
+func BuildRemote(envModel *models.Env, host string) error {
+	// make sure no other build is running for this app
+	if err := claimBuild(envModel, host); err != nil {
+		return err
+	}
+	defer releaseBuild(envModel)
+
+	// the artifacts replace the local build, so nothing else can build meanwhile
+	locker.LocalLock()
+	defer locker.LocalUnlock()
+
+	// init docker client and env mounts, the local volumes receive the build
+	if err := env.Setup(envModel); err != nil {
+		return util.ErrorAppend(err, "failed to prepare environment")
+	}
+
+	// print a warning if this is the first build
+	if envModel.BuiltBoxfile == "" {
+		display.FirstBuild()
+	}
+
+	if err := runBuild(envModel, host, func() error { return code.BuildRemote(envModel, host) }); err != nil {
+		return util.ErrorAppend(err, "failed to build the code on %s", host)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package processors
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/component"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/interpolate"
+	"github.com/nanobox-io/nanobox/util/overlay"
+)
+
+// renderedBoxfile is what 'nanobox render' reports
+type renderedBoxfile struct {
+	Env        string   `json:"env"`
+	Boxfile    string   `json:"boxfile"`
+	Unresolved []string `json:"unresolved"`
+}
+
+// Render prints the boxfile.yml as an environment is deployed with it, with
+// the Procfile, the overlay of the environment and the variables resolved.
+// The location and name are the ones of helpers.Endpoint.
+func Render(envModel *models.Env, location, name string) error {
+	data, err := ioutil.ReadFile(config.Boxfile())
+	if err != nil {
+		return util.Errorf("[USER] failed to read the boxfile - %s", err.Error())
+	}
+
+	env := remoteEnv(name)
+	var evars map[string]string
+
+	// the evars of a remote stay on the platform
+	if location == "local" {
+		appModel, _ := models.FindAppBySlug(envModel.ID, name)
+		env = overlay.Name(name)
+		evars = interpolate.Expanded(appModel.Evars)
+	}
+
+	box, missing, err := component.Resolve(string(data), env, evars)
+	if err != nil {
+		return err
+	}
+
+	if display.JSON() {
+		if missing == nil {
+			missing = []string{}
+		}
+		return display.PrintJSON(renderedBoxfile{Env: env, Boxfile: box, Unresolved: missing})
+	}
+
+	fmt.Print(box)
+
+	// the boxfile is printed on its own so it can be redirected to a file
+	if len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "\n! unresolved variables, set them in the environment or as evars: %s\n", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
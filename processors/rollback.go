@@ -0,0 +1,104 @@
+package processors
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/helpers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/odin"
+)
+
+// Rollback deploys the build a live app ran before its current deploy. Only
+// the deploys made from this machine are known.
+func Rollback(envModel *models.Env, deployConfig DeployConfig) error {
+
+	appID := deployConfig.App
+
+	// fetch the remote
+	remote, ok := envModel.Remotes[appID]
+	if ok {
+		// set the odin endpoint
+		odin.SetEndpoint(remote.Endpoint)
+		// set the app id
+		appID = remote.ID
+	}
+
+	// set the app id to the directory name if it's default
+	if appID == "default" {
+		appID = config.AppName()
+	}
+
+	// validate access to the app
+	if err := helpers.ValidateOdinApp(appID); err != nil {
+		return util.ErrorAppend(err, "unable to validate app")
+	}
+
+	live, err := models.LiveDeployRecords(appID)
+	if err != nil {
+		lumber.Error("rollback:models.LiveDeployRecords(%s): %s", appID, err.Error())
+		return util.ErrorAppend(err, "failed to load the deploy history")
+	}
+
+	if len(live) < 2 {
+		return util.Errorf("[USER] there is no previous deploy from this machine to roll back to")
+	}
+
+	current, previous := live[0], live[1]
+
+	message := deployConfig.Message
+	if message == "" {
+		message = fmt.Sprintf("rollback to deploy %d", previous.Number)
+	}
+
+	// the build is still in the warehouse, it only has to be deployed again
+	if err := odin.Deploy(appID, previous.BuildID, previous.Boxfile, message); err != nil {
+		lumber.Error("rollback:odin.Deploy(%s,%s): %s", appID, previous.BuildID, err.Error())
+		return util.ErrorAppend(err, "failed to deploy the previous build")
+	}
+
+	// the previous deploy is the newest live deploy again
+	current.Status = models.DeployRolledBack
+	if err := current.Save(); err != nil {
+		lumber.Error("rollback:models.DeployRecord.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to save the deploy record")
+	}
+
+	display.DeployComplete()
+
+	return nil
+}
+
+// recordDeploy adds a deploy of a live app to the deploy history
+func recordDeploy(appID, buildID, boxfile, message string) {
+	records, err := models.AllDeployRecordsByApp(appID)
+	if err != nil {
+		lumber.Error("deploy:recordDeploy:models.AllDeployRecordsByApp(%s): %s", appID, err.Error())
+		return
+	}
+
+	number := 1
+	if len(records) > 0 {
+		number = records[len(records)-1].Number + 1
+	}
+
+	record := &models.DeployRecord{
+		AppID:    appID,
+		Number:   number,
+		Status:   models.DeployLive,
+		BuildID:  buildID,
+		Boxfile:  boxfile,
+		Message:  message,
+		Started:  time.Now(),
+		Finished: time.Now(),
+	}
+
+	if err := record.Save(); err != nil {
+		lumber.Error("deploy:recordDeploy:models.DeployRecord.Save(): %s", err.Error())
+	}
+}
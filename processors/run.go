@@ -3,7 +3,6 @@ package processors
 import (
 	"runtime"
 	"strings"
-	"time"
 
 	"github.com/jcelliott/lumber"
 	"github.com/nanobox-io/golang-docker-client"
@@ -13,19 +12,32 @@ import (
 	build_generator "github.com/nanobox-io/nanobox/generators/hooks/build"
 
 	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/processors/code"
 	"github.com/nanobox-io/nanobox/processors/env"
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/console"
 	"github.com/nanobox-io/nanobox/util/display"
 	"github.com/nanobox-io/nanobox/util/hookit"
+	"github.com/nanobox-io/nanobox/util/images"
 	"github.com/nanobox-io/nanobox/util/locker"
 	"github.com/nanobox-io/nanobox/util/provider"
+	"github.com/nanobox-io/nanobox/util/secretref"
 	"github.com/nanobox-io/nanobox/util/watch"
 )
 
+// RunWatch rebuilds the runtime while the console is open, whenever the
+// boxfile or the dependency manifests change
+var RunWatch bool
+
 // Run a code container with your runtime installed
 func Run(envModel *models.Env, appModel *models.App, consoleConfig console.ConsoleConfig) error {
 
+	// make room for the images before anything is pulled
+	if err := ensureDiskSpace(); err != nil {
+		return err
+	}
+
 	// ensure the environment is setup
 	if err := env.Setup(envModel); err != nil {
 		return util.ErrorAppend(err, "failed to setup environment")
@@ -36,9 +48,45 @@ func Run(envModel *models.Env, appModel *models.App, consoleConfig console.Conso
 		return util.ErrorAppend(err, "failed to setup dev container")
 	}
 
+	// keep the code in the dev container in sync, unless another console
+	// is already syncing it
+	stopSync := func() {}
+	if !devInUse(container_generator.DevName()) {
+		var err error
+		if stopSync, err = code.StartDevSync(envModel, "nanobox_"+appModel.ID); err != nil {
+			return util.ErrorAppend(err, "failed to sync the code")
+		}
+		defer stopSync()
+	}
+
 	// start a watcher to watch for changes and inform the vm
 	watchFiles(envModel, appModel)
 
+	// run the boxfile cron jobs, unless another console is already running them
+	if !devInUse(container_generator.DevName()) {
+		stopCron, err := startCron(appModel)
+		if err != nil {
+			return util.ErrorAppend(err, "failed to start cron jobs")
+		}
+		defer stopCron()
+	}
+
+	// ship the logs of the components to the log-forward sinks
+	stopForward, err := app.StartForward(appModel)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to start forwarding the logs")
+	}
+	defer stopForward()
+
+	// rebuild the runtime when the build inputs change
+	if RunWatch {
+		stopWatch, err := watchDev(envModel, appModel)
+		if err != nil {
+			return util.ErrorAppend(err, "failed to start watching for changes")
+		}
+		defer stopWatch()
+	}
+
 	// create a dummy component using the appname
 	component := &models.Component{
 		ID: "nanobox_" + appModel.ID,
@@ -51,6 +99,9 @@ func Run(envModel *models.Env, appModel *models.App, consoleConfig console.Conso
 		return util.ErrorAppend(err, "failed to console into dev container")
 	}
 
+	// the last changes have to be synced before the container goes away
+	stopSync()
+
 	if err := teardown(appModel); err != nil {
 		return util.ErrorAppend(err, "unable to teardown dev")
 	}
@@ -79,6 +130,17 @@ func setup(appModel *models.App) error {
 	display.OpenContext("Building dev environment")
 	defer display.CloseContext()
 
+	// let the user know if the gpus they asked for can't be passed through
+	box := boxfile.New([]byte(appModel.DeployedBoxfile))
+	if container_generator.GPURequested(box) && !provider.GPUSupported() {
+		display.Warn("The %s provider can't pass gpus through, the dev container will run without them\n", provider.Name())
+	}
+
+	// the evars may reference a secret backend, it has to answer first
+	if _, err := secretref.ResolveAll(appModel.Evars); err != nil {
+		return util.Errorf("[USER] %s", err.Error())
+	}
+
 	// generate a container config
 	config := container_generator.DevConfig(appModel)
 
@@ -167,13 +229,9 @@ func downloadImage(image string) error {
 		// Prefix: image,
 	}
 
-	imagePull := func() error {
-		_, err := docker.ImagePull(image, dockerPercent)
-		return err
-	}
-	if err := util.Retry(imagePull, 5, time.Second); err != nil {
+	if err := images.Download(image, dockerPercent); err != nil {
 		display.ErrorTask()
-		lumber.Error("dev:Setup:downloadImage.ImagePull(%s, nil): %s", image, err.Error())
+		lumber.Error("dev:Setup:downloadImage:images.Download(%s): %s", image, err.Error())
 		return util.ErrorAppend(err, "failed to pull docker image (%s)", image)
 	}
 
@@ -182,6 +240,11 @@ func downloadImage(image string) error {
 
 func watchFiles(envModel *models.Env, appModel *models.App) {
 	boxfile := boxfile.New([]byte(appModel.DeployedBoxfile))
+	// the synced code is in a volume, its changes are seen by the container
+	if container_generator.CodeSync() {
+		return
+	}
+
 	if boxfile.Node("run.config").BoolValue("fs_watch") && (provider.RequiresMount() || specialException()) {
 		lumber.Info("watcher starting")
 		// todo: server.Watch to call the following, so we can pre-emptively set ulimit to much higher.
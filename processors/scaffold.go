@@ -0,0 +1,103 @@
+package processors
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/scaffold"
+)
+
+// Scaffold generates the boxfile of a new app in dir from a template, with
+// the data components of services, and adds what nanobox writes into the app
+// to its .gitignore. The data components are asked for when ask is set and
+// there is a terminal.
+func Scaffold(dir, template string, services []string, ask bool) error {
+	boxfilePath := filepath.Join(dir, "boxfile.yml")
+	if _, err := os.Stat(boxfilePath); err == nil {
+		return util.Errorf("[USER] a boxfile.yml already exists in %s", dir)
+	}
+
+	tmpl, ok := scaffold.Templates[template]
+	if !ok {
+		return util.Errorf("[USER] there is no '%s' template, use one of: %s", template, strings.Join(scaffold.Names(), ", "))
+	}
+
+	chosen := []scaffold.Service{}
+	for _, name := range services {
+		service, ok := tmpl.Service(name)
+		if !ok {
+			return util.Errorf("[USER] the %s template has no '%s' service, use one of: %s", template, name, serviceNames(tmpl))
+		}
+		chosen = append(chosen, service)
+	}
+
+	if ask && display.Interactive {
+		for _, service := range tmpl.Services {
+			add, err := askService(service)
+			if err != nil {
+				return util.ErrorAppend(err, "failed to read the answer")
+			}
+			if add {
+				chosen = append(chosen, service)
+			}
+		}
+	}
+
+	if err := ioutil.WriteFile(boxfilePath, []byte(tmpl.Render(filepath.Base(dir), chosen)), 0644); err != nil {
+		return util.ErrorAppend(err, "failed to write boxfile.yml")
+	}
+
+	added, err := scaffold.AddGitignore(filepath.Join(dir, ".gitignore"), tmpl.GitignoreEntries(filepath.Base(dir)))
+	if err != nil {
+		return util.ErrorAppend(err, "failed to update .gitignore")
+	}
+
+	display.Info("\n%s Generated boxfile.yml from the %s template\n", display.TaskComplete, template)
+	for _, service := range chosen {
+		display.Info("  data.%s : %s\n", service.Name, service.Image)
+	}
+	if len(added) > 0 {
+		display.Info("Added %s to .gitignore\n", strings.Join(added, ", "))
+	}
+	display.Info("\n")
+
+	return nil
+}
+
+// askService asks whether to add a data component, the answer defaults to
+// the template's
+func askService(service scaffold.Service) (bool, error) {
+	options := "[y/N]"
+	if service.Default {
+		options = "[Y/n]"
+	}
+
+	answer, err := display.Ask(fmt.Sprintf("Add data.%s (%s)? %s", service.Name, service.Image, options))
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	case "n", "no":
+		return false, nil
+	}
+
+	return service.Default, nil
+}
+
+// serviceNames returns the names of the services of a template
+func serviceNames(tmpl scaffold.Template) string {
+	names := []string{}
+	for _, service := range tmpl.Services {
+		names = append(names, fmt.Sprintf("%s (%s)", service.Name, service.Image))
+	}
+
+	return strings.Join(names, ", ")
+}
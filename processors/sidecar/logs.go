@@ -0,0 +1,76 @@
+package sidecar
+
+import (
+	"bufio"
+	"io"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// StreamLogs follows the output of every sidecar of the app and calls
+// publish with a log entry for each line. Sidecars run images without a log
+// shipper, so their output is read from docker instead.
+func StreamLogs(appModel *models.App, publish func(entry display.Entry)) {
+	componentModels, err := sidecarComponents(appModel)
+	if err != nil {
+		return
+	}
+
+	for _, componentModel := range componentModels {
+		go streamLogs(componentModel, publish)
+	}
+}
+
+// streamLogs follows the output of a single sidecar until its container is
+// removed
+func streamLogs(componentModel *models.Component, publish func(entry display.Entry)) {
+	opts := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Tail:       "0",
+	}
+
+	rc, err := docker.Client.ContainerLogs(context.Background(), componentModel.ID, opts)
+	if err != nil {
+		lumber.Error("sidecar:streamLogs:docker.Client.ContainerLogs(%s): %s", componentModel.ID, err.Error())
+		return
+	}
+	defer rc.Close()
+
+	// docker multiplexes stdout and stderr into frames, split them back out
+	// into a pipe we can read line by line
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(demux(rc, pw))
+	}()
+
+	scanner := bufio.NewScanner(pr)
+	for scanner.Scan() {
+		now := time.Now()
+		publish(display.Entry{
+			Time:     now,
+			UTime:    int(now.UnixNano()),
+			ID:       componentModel.Name,
+			Tag:      []string{componentModel.Name + "[sidecar]", componentModel.Name},
+			Type:     "app",
+			Priority: 4,
+			Message:  scanner.Text(),
+		})
+	}
+}
+
+// demux copies the payload of each frame of a docker log stream to w, the
+// stdout and stderr frames end up in the same stream
+func demux(r io.Reader, w io.Writer) error {
+	_, err := stdcopy.StdCopy(w, w, r)
+	return err
+}
@@ -0,0 +1,38 @@
+package sidecar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// frame builds a docker log frame for the stream
+func frame(stream byte, payload string) []byte {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, []byte(payload)...)
+}
+
+func TestDemux(t *testing.T) {
+	in := &bytes.Buffer{}
+	in.Write(frame(1, "hello\n"))
+	in.Write(frame(2, "oops\n"))
+
+	out := &bytes.Buffer{}
+	if err := demux(in, out); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if out.String() != "hello\noops\n" {
+		t.Errorf("unexpected output '%s'", out.String())
+	}
+}
+
+func TestDemuxUnknownStream(t *testing.T) {
+	in := bytes.NewBuffer(frame(9, "hello\n"))
+
+	if err := demux(in, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for an unknown stream")
+	}
+}
@@ -0,0 +1,100 @@
+package sidecar
+
+import (
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	container_generator "github.com/nanobox-io/nanobox/generators/containers"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/component"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/dhcp"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/images"
+)
+
+// Setup creates and starts the container of a sidecar
+func Setup(appModel *models.App, componentModel *models.Component) error {
+	if componentModel.Image == "" {
+		return util.Errorf("[USER] %s is missing an image", componentModel.Name)
+	}
+
+	// generate the missing component data
+	if err := componentModel.Generate(appModel, "sidecar"); err != nil {
+		lumber.Error("sidecar:Setup:models.Component:Generate(%s, sidecar): %s", appModel.ID, err.Error())
+		return util.ErrorAppend(err, "failed to generate component data")
+	}
+
+	// short-circuit if this sidecar is already setup
+	if componentModel.State != "initialized" {
+		return nil
+	}
+
+	display.OpenContext(componentModel.Label)
+	defer display.CloseContext()
+
+	if !docker.ImageExists(componentModel.Image) {
+		// generate a docker percent display
+		dockerPercent := &display.DockerPercentDisplay{
+			Output: display.NewStreamer("info"),
+		}
+
+		display.StartTask("Pulling %s image", componentModel.Image)
+		if err := images.Download(componentModel.Image, dockerPercent); err != nil {
+			lumber.Error("sidecar:Setup:images.Download(%s): %s", componentModel.Image, err.Error())
+			componentModel.Delete()
+			display.ErrorTask()
+			return util.ErrorAppend(err, "failed to pull docker image (%s)", componentModel.Image)
+		}
+		display.StopTask()
+	}
+
+	display.StartTask("Starting docker container")
+	if componentModel.IPAddr() == "" {
+		ip, err := dhcp.ReserveLocal()
+		if err != nil {
+			display.ErrorTask()
+			lumber.Error("sidecar:Setup:dhcp.ReserveLocal(): %s", err.Error())
+			return util.ErrorAppend(err, "failed to reserve an ip")
+		}
+		componentModel.IP = ip.String()
+	}
+
+	box := boxfile.New([]byte(appModel.DeployedBoxfile))
+	node := box.Node(componentModel.Name)
+	config := container_generator.SidecarConfig(appModel, componentModel, node)
+
+	// the resource limits from the boxfile
+	resources, err := component.Limits(appModel, componentModel)
+	if err != nil {
+		display.ErrorTask()
+		return err
+	}
+	container_generator.SetLimits(&config, resources)
+
+	// remove any container that may have been left by a killed process
+	docker.ContainerRemove(config.Name)
+
+	container, err := docker.CreateContainer(config)
+	if err != nil {
+		lumber.Error("sidecar:Setup:docker.CreateContainer(%s): %s", config.Name, err.Error())
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to start docker container")
+	}
+	display.StopTask()
+
+	componentModel.ID = container.ID
+	componentModel.ImageDigest = images.Digest(componentModel.Image)
+	componentModel.State = ACTIVE
+	if err := componentModel.Save(); err != nil {
+		lumber.Error("sidecar:Setup:models.Component.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to persist component")
+	}
+
+	if err := component.SetCPULimit(componentModel.ID, resources); err != nil {
+		return err
+	}
+
+	return nil
+}
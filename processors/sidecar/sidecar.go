@@ -0,0 +1,32 @@
+// Package sidecar manages the sidecar components of an app. Sidecars are
+// background processes declared with a sidecar.* node in the boxfile. They
+// run their own image with the app evars, and never receive http traffic.
+// They start and stop with the other components of the app, in the order of
+// their depends_on.
+package sidecar
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nanobox-io/nanobox-boxfile"
+)
+
+// these constants represent different potential states a sidecar can end up in
+const (
+	ACTIVE = "active"
+)
+
+// sidecarNodes returns the names of the sidecar nodes in the boxfile
+func sidecarNodes(box boxfile.Boxfile) []string {
+	names := []string{}
+
+	for key := range box.Parsed {
+		if strings.HasPrefix(key, "sidecar.") {
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,77 @@
+package sidecar
+
+import (
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/component"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/hostmount"
+	"github.com/nanobox-io/nanobox/util/locker"
+)
+
+// Sync syncronizes an app's sidecars with the boxfile config. Sidecars are
+// recreated on every sync so they pick up the latest app evars.
+func Sync(appModel *models.App) error {
+	box := boxfile.New([]byte(appModel.DeployedBoxfile))
+	names := sidecarNodes(box)
+
+	componentModels, err := sidecarComponents(appModel)
+	if err != nil {
+		return err
+	}
+
+	// short-circuit if there is nothing to do
+	if len(names) == 0 && len(componentModels) == 0 {
+		return nil
+	}
+
+	display.OpenContext("Syncing sidecars")
+	defer display.CloseContext()
+
+	locker.LocalLock()
+	defer locker.LocalUnlock()
+
+	for _, componentModel := range componentModels {
+		if err := component.Destroy(appModel, componentModel); err != nil {
+			return util.ErrorAppend(err, "failed to destroy sidecar (%s)", componentModel.Name)
+		}
+	}
+
+	for _, name := range names {
+		componentModel := &models.Component{
+			Name:          name,
+			Label:         name,
+			Image:         box.Node(name).StringValue("image"),
+			RestartPolicy: box.Node(name).StringValue("restart"),
+			Mounts:        hostmount.Resolve(box.Node(name).StringSliceValue("mounts"), config.LocalDir()),
+		}
+
+		if err := Setup(appModel, componentModel); err != nil {
+			return util.ErrorAppend(err, "failed to setup sidecar (%s)", name)
+		}
+	}
+
+	return nil
+}
+
+// sidecarComponents returns the sidecar components of an app
+func sidecarComponents(appModel *models.App) ([]*models.Component, error) {
+	componentModels, err := appModel.Components()
+	if err != nil {
+		lumber.Error("sidecar:sidecarComponents:models.App{ID:%s}.Components(): %s", appModel.ID, err.Error())
+		return nil, util.ErrorAppend(err, "unable to retrieve components")
+	}
+
+	sidecars := []*models.Component{}
+	for _, componentModel := range componentModels {
+		if componentModel.Type == "sidecar" {
+			sidecars = append(sidecars, componentModel)
+		}
+	}
+
+	return sidecars, nil
+}
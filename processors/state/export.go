@@ -0,0 +1,219 @@
+package state
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/dns"
+	"github.com/nanobox-io/nanobox/util/locker"
+)
+
+// Export bundles the apps nanobox knows about, their evars, dns aliases and
+// data services into a single file. With data the apps are stopped so the
+// data of the data services is consistent. Secrets are encrypted with a key
+// that never leaves this machine, so they're left out. The stopped apps are
+// started again once the bundle is written.
+func Export(path string, data bool) (err error) {
+	// the data is read from the containers
+	if data {
+		if err := provider.Init(); err != nil {
+			return util.ErrorAppend(err, "failed to init docker client")
+		}
+	}
+
+	locker.GlobalLock()
+	defer locker.GlobalUnlock()
+
+	envs, err := models.AllEnvs()
+	if err != nil {
+		lumber.Error("state:Export:models.AllEnvs(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to load the apps")
+	}
+
+	tmpDir, err := ioutil.TempDir("", "nanobox-state")
+	if err != nil {
+		return util.ErrorAppend(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	state := manifest{
+		Version: models.VersionString(),
+		Schema:  models.SchemaVersion(),
+		Created: time.Now(),
+		Data:    data,
+		Envs:    envs,
+	}
+
+	// whatever happens, the apps that were running are started again
+	stopped := []*models.App{}
+	defer func() {
+		if err2 := restartApps(stopped); err2 != nil && err == nil {
+			err = err2
+		}
+	}()
+
+	for _, envModel := range envs {
+		apps, err := envModel.Apps()
+		if err != nil {
+			lumber.Error("state:Export:models.Env{ID:%s}.Apps(): %s", envModel.ID, err.Error())
+			return util.ErrorAppend(err, "failed to load the apps of %s", envModel.Name)
+		}
+
+		for _, appModel := range apps {
+			// the data is read from the stopped containers so it's consistent
+			if data && appModel.Status == "up" {
+				if err := app.Stop(appModel); err != nil {
+					return util.ErrorAppend(err, "failed to stop %s (%s)", envModel.Name, appModel.DisplayName())
+				}
+				stopped = append(stopped, appModel)
+			}
+
+			bundled, err := exportApp(envModel, appModel, tmpDir, data)
+			if err != nil {
+				return util.ErrorAppend(err, "failed to export %s (%s)", envModel.Name, appModel.DisplayName())
+			}
+			state.Apps = append(state.Apps, bundled)
+		}
+	}
+
+	if err := writeBundle(path, state, tmpDir); err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	display.Info("\n%s Exported %d apps to %s\n\n", display.TaskComplete, len(state.Apps), path)
+
+	return nil
+}
+
+// exportApp writes the archive of an app into dir
+func exportApp(envModel *models.Env, appModel *models.App, dir string, data bool) (bundledApp, error) {
+	display.OpenContext("Exporting %s (%s)", envModel.Name, appModel.DisplayName())
+	defer display.CloseContext()
+
+	bundled := bundledApp{
+		EnvID:   envModel.ID,
+		Name:    appModel.Name,
+		Archive: fmt.Sprintf("apps/%s.tar.gz", appModel.ID),
+	}
+
+	for _, domain := range dns.List(appModel.ID) {
+		bundled.DNS = append(bundled.DNS, domain.Domain)
+	}
+
+	secrets, _ := models.FindSecretsByApp(appModel.ID)
+	if len(secrets.Values) > 0 {
+		display.Warn("The secrets of %s (%s) are not exported, set them again after the import\n", envModel.Name, appModel.DisplayName())
+	}
+
+	if err := app.WriteArchive(appModel, filepath.Join(dir, filepath.Base(bundled.Archive)), data); err != nil {
+		return bundled, util.ErrorAppend(err, "failed to archive the app")
+	}
+
+	return bundled, nil
+}
+
+// restartApps starts the apps the export stopped
+func restartApps(apps []*models.App) error {
+	for _, appModel := range apps {
+		envModel, err := appModel.Env()
+		if err != nil {
+			lumber.Error("state:restartApps:models.App{ID:%s}.Env(): %s", appModel.ID, err.Error())
+			return util.ErrorAppend(err, "failed to load app env")
+		}
+
+		if err := app.Start(envModel, appModel, appModel.Name); err != nil {
+			return util.ErrorAppend(err, "failed to start %s (%s) again", envModel.Name, appModel.DisplayName())
+		}
+	}
+
+	return nil
+}
+
+// writeBundle writes the manifest and the app archives into the bundle
+func writeBundle(path string, state manifest, dir string) error {
+	display.StartTask("Writing %s", path)
+	defer display.StopTask()
+
+	file, err := os.Create(path)
+	if err != nil {
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to create %s", path)
+	}
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+
+	if err := writeBundleEntries(tw, state, dir); err != nil {
+		display.ErrorTask()
+		tw.Close()
+		gz.Close()
+		file.Close()
+		return err
+	}
+
+	// the end of the archive and the gzip footer are only written on close
+	if err := tw.Close(); err != nil {
+		display.ErrorTask()
+		gz.Close()
+		file.Close()
+		return util.ErrorAppend(err, "failed to finish the bundle")
+	}
+
+	if err := gz.Close(); err != nil {
+		display.ErrorTask()
+		file.Close()
+		return util.ErrorAppend(err, "failed to compress the bundle")
+	}
+
+	if err := file.Close(); err != nil {
+		display.ErrorTask()
+		return util.ErrorAppend(err, "failed to write %s", path)
+	}
+
+	return nil
+}
+
+// writeBundleEntries writes the manifest and the app archives to tw
+func writeBundleEntries(tw *tar.Writer, state manifest, dir string) error {
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return util.ErrorAppend(err, "failed to encode the manifest")
+	}
+
+	header := &tar.Header{
+		Name:    "state.json",
+		Mode:    0644,
+		Size:    int64(len(b)),
+		ModTime: state.Created,
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return util.ErrorAppend(err, "failed to write bundle header")
+	}
+
+	if _, err := tw.Write(b); err != nil {
+		return util.ErrorAppend(err, "failed to write the manifest")
+	}
+
+	for _, bundled := range state.Apps {
+		if err := addFile(tw, bundled.Archive, filepath.Join(dir, filepath.Base(bundled.Archive))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
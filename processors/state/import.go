@@ -0,0 +1,161 @@
+package state
+
+import (
+	"crypto/md5"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	app_dns "github.com/nanobox-io/nanobox/processors/app/dns"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/locker"
+)
+
+// Import restores the apps of a state bundle. The data services are created
+// again and get new IPs, the dns aliases follow them. Code components are
+// created by the next 'nanobox run' or deploy. Apps that already exist on
+// this machine are left alone.
+//
+// The apps are found by their directory, moves maps the directories of the
+// machine the bundle was exported on to this one, ie: /Users/old=/Users/new
+func Import(path string, moves map[string]string) error {
+	// init docker client
+	if err := provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	locker.GlobalLock()
+	defer locker.GlobalUnlock()
+
+	tmpDir, err := ioutil.TempDir("", "nanobox-state")
+	if err != nil {
+		return util.ErrorAppend(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	state, err := extractBundle(path, tmpDir)
+	if err != nil {
+		return err
+	}
+
+	if state.Schema > models.SchemaVersion() {
+		return util.Errorf("[USER] %s was exported by a newer version of nanobox (%s), update nanobox to import it", path, state.Version)
+	}
+
+	imported := 0
+
+	for _, exported := range state.Envs {
+		envModel, err := importEnv(exported, moves)
+		if err != nil {
+			return util.ErrorAppend(err, "failed to import %s", exported.Name)
+		}
+
+		for _, bundled := range state.Apps {
+			if bundled.EnvID != exported.ID {
+				continue
+			}
+
+			ok, err := importApp(envModel, bundled, tmpDir)
+			if err != nil {
+				return util.ErrorAppend(err, "failed to import %s (%s)", envModel.Name, bundled.Name)
+			}
+			if ok {
+				imported++
+			}
+		}
+	}
+
+	display.Info("\n%s Imported %d apps from %s\n\n", display.TaskComplete, imported, path)
+
+	return nil
+}
+
+// importEnv saves the env of the bundle under its directory on this machine.
+// The build state belongs to the other machine, the next build starts over.
+func importEnv(exported *models.Env, moves map[string]string) (*models.Env, error) {
+	dir := movedDir(exported.Directory, moves)
+	if _, err := os.Stat(dir); err != nil {
+		display.Warn("%s isn't on this machine, move the code there or import with --move\n", dir)
+	}
+
+	// the env id is derived from the directory, see config.EnvID
+	id := fmt.Sprintf("%x", md5.Sum([]byte(dir)))
+
+	envModel, _ := models.FindEnvByID(id)
+	if !envModel.IsNew() {
+		return envModel, nil
+	}
+
+	envModel = &models.Env{
+		ID:           id,
+		Directory:    dir,
+		Name:         exported.Name,
+		Remotes:      exported.Remotes,
+		DryRun:       exported.DryRun,
+		Environments: exported.Environments,
+		TeamConfig:   exported.TeamConfig,
+		UserBoxfile:  exported.UserBoxfile,
+	}
+
+	if err := envModel.Save(); err != nil {
+		lumber.Error("state:importEnv:models.Env.Save(): %s", err.Error())
+		return nil, util.ErrorAppend(err, "failed to save the env")
+	}
+
+	return envModel, nil
+}
+
+// importApp restores an app from its archive in the bundle, it returns false
+// if the app already exists
+func importApp(envModel *models.Env, bundled bundledApp, dir string) (bool, error) {
+	appModel, _ := models.FindAppBySlug(envModel.ID, bundled.Name)
+	if !appModel.IsNew() {
+		display.Warn("%s (%s) already exists on this machine, skipped\n", envModel.Name, appModel.DisplayName())
+		return false, nil
+	}
+
+	// the bundled archive is restored like the archive of an archived app
+	archiveModel := &models.Archive{
+		ID:      fmt.Sprintf("%s_%s", envModel.ID, bundled.Name),
+		EnvID:   envModel.ID,
+		AppName: bundled.Name,
+		Path:    filepath.Join(dir, filepath.Base(bundled.Archive)),
+	}
+
+	if err := app.Unarchive(envModel, archiveModel); err != nil {
+		return false, err
+	}
+
+	appModel, err := models.FindAppBySlug(envModel.ID, bundled.Name)
+	if err != nil {
+		return false, util.ErrorAppend(err, "failed to load the imported app")
+	}
+
+	for _, domain := range bundled.DNS {
+		if err := app_dns.Add(envModel, appModel, domain); err != nil {
+			return false, util.ErrorAppend(err, "failed to add the dns alias %s", domain)
+		}
+	}
+
+	return true, nil
+}
+
+// movedDir returns where a directory of the other machine is on this one
+func movedDir(dir string, moves map[string]string) string {
+	for from, to := range moves {
+		from, to = strings.TrimSuffix(from, "/"), strings.TrimSuffix(to, "/")
+		if dir == from || strings.HasPrefix(dir, from+"/") {
+			return to + strings.TrimPrefix(dir, from)
+		}
+	}
+
+	return dir
+}
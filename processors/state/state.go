@@ -0,0 +1,125 @@
+// Package state moves the local state of nanobox from one machine to another.
+// A state bundle is a gzipped tarball with a manifest, state.json, and an app
+// archive for each app, see app.WriteArchive.
+package state
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+)
+
+// manifest describes the content of a state bundle
+type manifest struct {
+	Version string // the nanobox version that exported the bundle
+	Schema  int    // the database schema the models were exported with
+	Created time.Time
+	Data    bool // the app archives hold the data of the data components
+	Envs    []*models.Env
+	Apps    []bundledApp
+}
+
+// bundledApp is an app in the state bundle
+type bundledApp struct {
+	EnvID   string
+	Name    string
+	Archive string   // the app archive in the bundle
+	DNS     []string // the dns aliases of the app
+}
+
+// addFile adds a file on the host to the bundle as name
+func addFile(tw *tar.Writer, name, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to open %s", path)
+	}
+	defer file.Close()
+
+	fi, err := file.Stat()
+	if err != nil {
+		return util.ErrorAppend(err, "failed to stat %s", path)
+	}
+
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    fi.Size(),
+		ModTime: fi.ModTime(),
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return util.ErrorAppend(err, "failed to write bundle header")
+	}
+
+	if _, err := io.Copy(tw, file); err != nil {
+		return util.ErrorAppend(err, "failed to write %s", name)
+	}
+
+	return nil
+}
+
+// extractBundle unpacks the bundle into dir and decodes its manifest
+func extractBundle(path, dir string) (*manifest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, util.Errorf("[USER] failed to open %s: %s", path, err.Error())
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, util.Errorf("[USER] %s isn't a nanobox state bundle: %s", path, err.Error())
+	}
+	defer gz.Close()
+
+	var state *manifest
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, util.ErrorAppend(err, "failed to read the bundle")
+		}
+
+		switch {
+		case header.Name == "state.json":
+			state = &manifest{}
+			err = json.NewDecoder(tr).Decode(state)
+		case strings.HasPrefix(header.Name, "apps/"):
+			err = extractFile(tr, filepath.Join(dir, filepath.Base(header.Name)))
+		}
+
+		if err != nil {
+			return nil, util.ErrorAppend(err, "failed to unpack %s", header.Name)
+		}
+	}
+
+	if state == nil {
+		return nil, util.Errorf("[USER] %s isn't a nanobox state bundle, it has no state.json", path)
+	}
+
+	return state, nil
+}
+
+// extractFile writes the current bundle entry to dest
+func extractFile(r io.Reader, dest string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, r)
+	return err
+}
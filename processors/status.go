@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util/display"
 	"github.com/nanobox-io/nanobox/util/provider"
 )
 
@@ -17,9 +18,6 @@ type status struct {
 
 // displays status about provider status and running apps
 func Status() error {
-	fmt.Printf("Status: %s\n", provider.Status())
-	fmt.Println()
-
 	statuses := []status{}
 
 	envs, _ := models.AllEnvs()
@@ -36,6 +34,13 @@ func Status() error {
 		}
 	}
 
+	if display.JSON() {
+		return display.PrintJSON(statusResult(statuses))
+	}
+
+	fmt.Printf("Status: %s\n", provider.Status())
+	fmt.Println()
+
 	if len(statuses) == 0 {
 		return nil
 	}
@@ -59,6 +64,24 @@ func Status() error {
 	return nil
 }
 
+// statusResult returns the status of the vm and the apps, for scripts
+func statusResult(statuses []status) map[string]interface{} {
+	apps := []map[string]string{}
+	for _, status := range statuses {
+		apps = append(apps, map[string]string{
+			"app":    status.envName,
+			"env":    status.appName,
+			"status": status.status,
+			"path":   status.directory,
+		})
+	}
+
+	return map[string]interface{}{
+		"provider": provider.Status(),
+		"apps":     apps,
+	}
+}
+
 // returns the longest name
 func longestName(statuses []status) (rtn int) {
 
@@ -59,6 +59,14 @@ func stopAllApps() error {
 		return util.ErrorAppend(err, "failed to load running apps")
 	}
 
+	// the suspended apps are still in memory
+	suspended, err := models.AllAppsByStatus(models.StatusSuspended)
+	if err != nil {
+		lumber.Error("stopAllApps:models.AllAppsByStatus(suspended)")
+		return util.ErrorAppend(err, "failed to load suspended apps")
+	}
+	apps = append(apps, suspended...)
+
 	if len(apps) == 0 {
 		return nil
 	}
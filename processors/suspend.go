@@ -0,0 +1,79 @@
+package processors
+
+import (
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	util_provider "github.com/nanobox-io/nanobox/util/provider"
+)
+
+// Suspend pauses the containers of the running apps and saves the state of
+// the provider, unless keepVM is set. Nothing is torn down, so Resume brings
+// everything back much faster than a stop and a start.
+func Suspend(keepVM bool) error {
+	// if the util provider isnt ready there's nothing running
+	if !util_provider.IsReady() {
+		return nil
+	}
+
+	// init docker client
+	if err := provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	apps, err := models.AllAppsByStatus(models.StatusUp)
+	if err != nil {
+		lumber.Error("processors:Suspend:models.AllAppsByStatus(up): %s", err.Error())
+		return util.ErrorAppend(err, "failed to load running apps")
+	}
+
+	if len(apps) > 0 {
+		display.OpenContext("Suspending Apps and Components")
+		for _, appModel := range apps {
+			if err := app.Suspend(appModel); err != nil {
+				display.CloseContext()
+				return util.ErrorAppend(err, "failed to suspend running app")
+			}
+		}
+		display.CloseContext()
+	}
+
+	if keepVM {
+		return nil
+	}
+
+	return provider.Suspend()
+}
+
+// Resume starts the provider and brings the suspended apps back. A
+// component that was stopped after all, ie: the VM was shut down, is
+// started instead.
+func Resume() error {
+	if err := Start(); err != nil {
+		return err
+	}
+
+	apps, err := models.AllAppsByStatus(models.StatusSuspended)
+	if err != nil {
+		lumber.Error("processors:Resume:models.AllAppsByStatus(suspended): %s", err.Error())
+		return util.ErrorAppend(err, "failed to load suspended apps")
+	}
+
+	for _, appModel := range apps {
+		envModel, err := appModel.Env()
+		if err != nil {
+			lumber.Error("processors:Resume:models.App{ID:%s}.Env(): %s", appModel.ID, err.Error())
+			return util.ErrorAppend(err, "failed to load app env")
+		}
+
+		if err := app.Start(envModel, appModel, appModel.Name); err != nil {
+			return util.ErrorAppend(err, "failed to resume %s", appModel.DisplayName())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package processors
+
+import (
+	"fmt"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/telemetry"
+)
+
+// telemetryStatus is what 'nanobox telemetry status' reports
+type telemetryStatus struct {
+	Enabled  bool   `json:"enabled"`
+	Endpoint string `json:"endpoint"`
+	ID       string `json:"id,omitempty"`
+	Queued   int    `json:"queued"`
+}
+
+// TelemetryOn turns the usage metrics on, sent to endpoint if it's set
+func TelemetryOn(endpoint string) error {
+	conf, _ := models.LoadConfig()
+	conf.Telemetry = true
+	if endpoint != "" {
+		conf.TelemetryEndpoint = endpoint
+	}
+
+	if err := conf.Save(); err != nil {
+		return util.ErrorAppend(err, "failed to save the config")
+	}
+
+	display.Info("\n%s Telemetry is on, thank you! The events are sent to %s\n\n", display.TaskComplete, conf.TelemetryEndpoint)
+
+	return nil
+}
+
+// TelemetryOff turns the usage metrics off and discards the events that
+// weren't sent
+func TelemetryOff() error {
+	conf, _ := models.LoadConfig()
+	conf.Telemetry = false
+
+	if err := conf.Save(); err != nil {
+		return util.ErrorAppend(err, "failed to save the config")
+	}
+
+	if err := telemetry.Discard(); err != nil {
+		return util.ErrorAppend(err, "failed to remove the events that weren't sent")
+	}
+
+	display.Info("\n%s Telemetry is off\n\n", display.TaskComplete)
+
+	return nil
+}
+
+// TelemetryStatus prints whether the usage metrics are on, where they are
+// sent and what is collected
+func TelemetryStatus() error {
+	conf, _ := models.LoadConfig()
+	queued, _ := telemetry.Queued()
+
+	status := telemetryStatus{
+		Enabled:  conf.Telemetry,
+		Endpoint: conf.TelemetryEndpoint,
+		Queued:   len(queued),
+	}
+	if conf.Telemetry {
+		status.ID = telemetry.ID()
+	}
+
+	if display.JSON() {
+		return display.PrintJSON(status)
+	}
+
+	state := "off"
+	if status.Enabled {
+		state = "on"
+	}
+
+	fmt.Printf("\nTelemetry : %s\n", state)
+	fmt.Printf("Endpoint  : %s\n", status.Endpoint)
+	if status.ID != "" {
+		fmt.Printf("ID        : %s\n", status.ID)
+	}
+	fmt.Printf("Queued    : %d events (%s)\n", status.Queued, telemetry.Path())
+	fmt.Printf(`
+Each command records its name (without arguments), how long it took,
+whether it failed and the class of the error, the nanobox version, the
+os and the provider. Nothing identifies you, your apps or your code.
+
+`)
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package processors
+
+import (
+	"strings"
+
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/nanobox-boxfile"
+
+	"github.com/nanobox-io/nanobox/commands/registry"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/processors/component"
+	"github.com/nanobox-io/nanobox/processors/env"
+	process_provider "github.com/nanobox-io/nanobox/processors/provider"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// Test brings up a fresh copy of the app's data components, runs the test
+// commands in the dev runtime and tears everything down again. The commands
+// are the run.config test node of the boxfile, unless a command is given.
+// The exit code of the first failing command is nanobox's.
+func Test(envModel *models.Env, command []string) error {
+	commands := testCommands(envModel, command)
+	if len(commands) == 0 {
+		return util.Errorf("[USER] there is nothing to test, add the test commands to the run.config node of the boxfile, ie: test: 'bundle exec rspec', or give one, ie: nanobox test -- make test")
+	}
+
+	// init docker client
+	if err := process_provider.Init(); err != nil {
+		return util.ErrorAppend(err, "failed to init docker client")
+	}
+
+	if err := env.Setup(envModel); err != nil {
+		return util.ErrorAppend(err, "failed to setup environment")
+	}
+
+	// a run that was interrupted may have left its app behind
+	appModel, _ := models.FindAppBySlug(envModel.ID, models.TestAppName)
+	if !appModel.IsNew() {
+		if err := app.Destroy(appModel); err != nil {
+			return util.ErrorAppend(err, "failed to remove the previous test app")
+		}
+		appModel = &models.App{}
+	}
+
+	defer testTeardown(envModel)
+
+	if err := app.Start(envModel, appModel, models.TestAppName); err != nil {
+		return util.ErrorAppend(err, "failed to start the test app")
+	}
+
+	if err := component.Sync(envModel, appModel); err != nil {
+		return util.ErrorAppend(err, "failed to start the data components")
+	}
+
+	// the code runs like a one-off job in the dev runtime
+	codeComponent := &models.Component{AppID: appModel.ID, Name: "test", Type: "code"}
+
+	for _, cmd := range commands {
+		display.Info("\nRunning %s\n\n", cmd)
+
+		code, err := runJobContainer(appModel, codeComponent, cmd)
+		if err != nil {
+			return util.ErrorAppend(err, "failed to run the tests")
+		}
+
+		if code != 0 {
+			display.Info("\n'%s' failed with exit code %d\n", cmd, code)
+			registry.Set("exit_code", code)
+			return nil
+		}
+	}
+
+	display.Info("\nThe tests passed\n")
+
+	return nil
+}
+
+// testCommands returns the command given, or the test commands of the
+// boxfile. The test node is a command, or a list of them.
+func testCommands(envModel *models.Env, command []string) []string {
+	if len(command) > 0 {
+		return []string{strings.Join(command, " ")}
+	}
+
+	box := boxfile.New([]byte(envModel.BuiltBoxfile))
+	if commands := box.Node("run.config").StringSliceValue("test"); len(commands) > 0 {
+		return commands
+	}
+
+	if cmd := box.Node("run.config").StringValue("test"); cmd != "" {
+		return []string{cmd}
+	}
+
+	return nil
+}
+
+// testTeardown removes the test app with its components and data
+func testTeardown(envModel *models.Env) {
+	appModel, err := models.FindAppBySlug(envModel.ID, models.TestAppName)
+	if err != nil || appModel.IsNew() {
+		return
+	}
+
+	if err := app.Destroy(appModel); err != nil {
+		lumber.Error("processors:testTeardown:app.Destroy(%s): %s", appModel.ID, err.Error())
+		display.Warn("failed to remove the test app, it is removed by the next test run\n")
+	}
+}
@@ -0,0 +1,53 @@
+package token
+
+import (
+	"fmt"
+
+	"github.com/nanobox-io/nanobox/commands/registry"
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/odin"
+)
+
+// the scopes a token can have
+var scopes = map[string]string{
+	"deploy":    "build, deploy and read apps",
+	"read-only": "read apps, their logs and evars",
+}
+
+// Create mints a token limited to a scope. The token can be limited to one
+// app as well, by remote alias or app name.
+func Create(envModel *models.Env, name, scope, app string) error {
+	if _, ok := scopes[scope]; !ok {
+		return util.Errorf("[USER] '%s' isn't a token scope, use deploy or read-only", scope)
+	}
+
+	// fetch the remote
+	if remote, ok := envModel.Remotes[app]; ok {
+		// set the odin endpoint
+		odin.SetEndpoint(remote.Endpoint)
+		// set the app id
+		app = remote.ID
+	}
+
+	// set odins endpoint if the arguement is passed
+	if endpoint := registry.GetString("endpoint"); endpoint != "" {
+		odin.SetEndpoint(endpoint)
+	}
+
+	token, err := odin.CreateToken(name, scope, app)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to create the token")
+	}
+
+	fmt.Printf(`
+Token '%s' created, it can %s:
+
+  %s
+
+Save it now, it won't be shown again. Use it as NANOBOX_TOKEN in your ci.
+
+`, name, scopes[scope], token.Token)
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package token
+
+import (
+	"fmt"
+
+	"github.com/nanobox-io/nanobox/commands/registry"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/odin"
+)
+
+// List prints the tokens of the user, the tokens themselves are never shown
+// again
+func List() error {
+	// set odins endpoint if the arguement is passed
+	if endpoint := registry.GetString("endpoint"); endpoint != "" {
+		odin.SetEndpoint(endpoint)
+	}
+
+	tokens, err := odin.ListTokens()
+	if err != nil {
+		return util.ErrorAppend(err, "failed to list the tokens")
+	}
+
+	if len(tokens) == 0 {
+		fmt.Printf("\nNo tokens yet. Use 'nanobox token create' to create one.\n\n")
+		return nil
+	}
+
+	fmt.Printf("\n%-26s %-20s %-10s %-26s %s\n", "ID", "Name", "Scope", "App", "Created")
+	for _, token := range tokens {
+		app := token.AppID
+		if app == "" {
+			app = "all"
+		}
+		fmt.Printf("%-26s %-20s %-10s %-26s %s\n", token.ID, token.Name, token.Scope, app, token.CreatedAt)
+	}
+	fmt.Println()
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package token
+
+import (
+	"fmt"
+
+	"github.com/nanobox-io/nanobox/commands/registry"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/odin"
+)
+
+// Revoke revokes a token, by id or name. Anything using it is locked out
+// right away.
+func Revoke(token string) error {
+	// set odins endpoint if the arguement is passed
+	if endpoint := registry.GetString("endpoint"); endpoint != "" {
+		odin.SetEndpoint(endpoint)
+	}
+
+	tokens, err := odin.ListTokens()
+	if err != nil {
+		return util.ErrorAppend(err, "failed to list the tokens")
+	}
+
+	for _, t := range tokens {
+		if t.ID == token || t.Name == token {
+			if err := odin.RevokeToken(t.ID); err != nil {
+				return util.ErrorAppend(err, "failed to revoke the token")
+			}
+
+			fmt.Printf("\nToken '%s' revoked\n\n", t.Name)
+			return nil
+		}
+	}
+
+	return util.Errorf("[USER] there is no token '%s', see 'nanobox token ls'", token)
+}
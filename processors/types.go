@@ -4,6 +4,7 @@ type DeployConfig struct {
 	App     string
 	Message string
 	Force   bool
+	Canary  int
 }
 
 type ConsoleConfig struct {
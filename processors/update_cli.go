@@ -0,0 +1,103 @@
+package processors
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/update"
+)
+
+// UpdateCLI replaces the running nanobox with the latest release of a
+// channel, the configured one if channel is empty. The release is verified
+// against its checksum and signature before it's installed.
+func UpdateCLI(channel string) error {
+	configModel, _ := models.LoadConfig()
+	if channel == "" {
+		channel = configModel.UpdateChannel
+	}
+
+	if !update.ValidChannel(channel) {
+		return util.Errorf("[USER] '%s' isn't a release channel, use stable or beta", channel)
+	}
+
+	latest, err := update.Latest(channel)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to check the %s channel", channel)
+	}
+
+	if latest == models.VersionString() {
+		display.Info("\n%s nanobox is up to date (%s)\n\n", display.TaskComplete, latest)
+		return nil
+	}
+
+	path, err := executable()
+	if err != nil {
+		return util.ErrorAppend(err, "failed to find the nanobox executable")
+	}
+
+	display.Info("\nCurrent version: %s\nUpdating to: %s (%s)\n", models.VersionString(), latest, channel)
+
+	binary, digest, err := update.Download(channel, display.Out)
+	if err != nil {
+		lumber.Error("processors:UpdateCLI:update.Download(%s): %s", channel, err.Error())
+		return util.ErrorAppend(err, "failed to download the update")
+	}
+	defer os.Remove(binary)
+
+	if err := update.Install(binary, digest, path); err != nil {
+		if !os.IsPermission(err) {
+			return util.ErrorAppend(err, "failed to replace %s", path)
+		}
+
+		// the executable is in a system directory, the install is re-run as
+		// an administrator
+		display.Info("\nReplacing %s requires administrator privileges.\n", path)
+
+		cmd := fmt.Sprintf("\"%s\" update-cli --install \"%s\" --sha256 %s --internal", path, binary, digest)
+		if err := util.PrivilegeExec(cmd); err != nil {
+			lumber.Error("processors:UpdateCLI:util.PrivilegeExec(%s): %s", cmd, err)
+			return util.ErrorAppend(err, "failed to replace %s", path)
+		}
+	}
+
+	if err := update.Installed(); err != nil {
+		lumber.Error("processors:UpdateCLI:update.Installed(): %s", err.Error())
+	}
+
+	display.Info("\n%s Updated to %s\n", display.TaskComplete, latest)
+	display.Info("Check out the release notes here:\nhttps://github.com/nanobox-io/nanobox/blob/master/CHANGELOG.md\n\n")
+
+	return nil
+}
+
+// InstallCLI replaces the running nanobox with a binary that was downloaded
+// and verified already. It's what UpdateCLI runs as an administrator.
+func InstallCLI(binary, digest string) error {
+	path, err := executable()
+	if err != nil {
+		return util.ErrorAppend(err, "failed to find the nanobox executable")
+	}
+
+	if err := update.Install(binary, digest, path); err != nil {
+		lumber.Error("processors:InstallCLI:update.Install(%s, %s): %s", binary, path, err.Error())
+		return util.ErrorAppend(err, "failed to replace %s", path)
+	}
+
+	return nil
+}
+
+// executable returns the path of the running nanobox, following links
+func executable() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.EvalSymlinks(path)
+}
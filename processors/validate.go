@@ -0,0 +1,75 @@
+package processors
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/schema"
+)
+
+// Validate checks the boxfile against the nodes and keys nanobox knows and
+// prints every problem with its line
+func Validate() error {
+	problems, err := schema.ValidateFile(config.Boxfile())
+	if err != nil {
+		return util.Errorf("[USER] failed to read the boxfile - %s", err.Error())
+	}
+
+	if err := printProblems(problems, "valid"); err != nil {
+		return err
+	}
+
+	if schema.HasErrors(problems) {
+		return util.Errorf("[USER] the boxfile is invalid")
+	}
+
+	return nil
+}
+
+// Lint validates the boxfile and checks it for risky patterns, like an
+// unpinned engine or a database port open to everyone. In strict mode the
+// warnings fail it too.
+func Lint(strict bool) error {
+	problems, err := schema.LintFile(config.Boxfile())
+	if err != nil {
+		return util.Errorf("[USER] failed to read the boxfile - %s", err.Error())
+	}
+
+	if err := printProblems(problems, "clean"); err != nil {
+		return err
+	}
+
+	if schema.HasErrors(problems) {
+		return util.Errorf("[USER] the boxfile has errors")
+	}
+
+	if strict && schema.HasWarnings(problems) {
+		return util.Errorf("[USER] the boxfile has warnings, which --strict doesn't allow")
+	}
+
+	return nil
+}
+
+// printProblems prints the problems of the boxfile with their line, or that
+// it's fine when there are none
+func printProblems(problems []schema.Problem, fine string) error {
+	if display.JSON() {
+		if problems == nil {
+			problems = []schema.Problem{}
+		}
+		return display.PrintJSON(problems)
+	}
+
+	name := filepath.Base(config.Boxfile())
+	for _, problem := range problems {
+		fmt.Printf("%s:%s\n", name, problem)
+	}
+	if len(problems) == 0 {
+		display.Info("\n%s %s is %s\n\n", display.TaskComplete, name, fine)
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package processors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	util_provider "github.com/nanobox-io/nanobox/util/provider"
+)
+
+// VMConfig is what the vm is given, the ram in gigabytes and the disk in
+// megabytes like the config. What's left at zero stays as it is.
+type VMConfig struct {
+	CPUs int
+	RAM  int
+	Disk int
+}
+
+// the megabytes a build or a pull needs free on the vm disk, and how much
+// auto-grow-disk grows the disk by
+var (
+	minFreeDisk  = 2048
+	diskGrowStep = 10240
+)
+
+// vmStatus is the vm status nanobox vm status reports
+type vmStatus struct {
+	Status string `json:"status"`
+	util_provider.Resources
+}
+
+// VMSet gives the vm the cpus, ram and disk and saves them in the config. A
+// vm that runs is stopped with its apps, resized, and started again with the
+// apps that were running. The disk can only grow.
+func VMSet(vm VMConfig) error {
+	if util_provider.Name() == "native" {
+		return util.Errorf("[USER] the native provider has no VM, docker runs on the resources of the host")
+	}
+
+	conf, err := models.LoadConfig()
+	if err != nil {
+		lumber.Error("processors:VMSet:models.LoadConfig(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to load the config")
+	}
+
+	if vm.CPUs == 0 {
+		vm.CPUs = conf.CPUs
+	}
+	if vm.RAM == 0 {
+		vm.RAM = conf.RAM
+	}
+	if vm.Disk == 0 {
+		vm.Disk = conf.Disk
+	}
+
+	// a vm that isn't created yet is created with the config
+	if vmCreated() {
+		if err := resizeVM(vm); err != nil {
+			return err
+		}
+	}
+
+	conf.CPUs, conf.RAM, conf.Disk = vm.CPUs, vm.RAM, vm.Disk
+	if err := conf.Save(); err != nil {
+		lumber.Error("processors:VMSet:models.Config.Save(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to save the config")
+	}
+
+	display.Info("The VM has %d cpus, %dGB of memory and a %dMB disk", vm.CPUs, vm.RAM, vm.Disk)
+
+	return nil
+}
+
+// resizeVM stops the vm, resizes it, and brings it and its apps back if it
+// was running
+func resizeVM(vm VMConfig) error {
+	// a suspended vm can't be changed, its memory is saved with it
+	if util_provider.Status() == "Saved" {
+		if err := Resume(); err != nil {
+			return util.ErrorAppend(err, "failed to resume the vm")
+		}
+	}
+
+	running := util_provider.IsReady()
+
+	apps, err := models.AllAppsByStatus(models.StatusUp)
+	if err != nil {
+		lumber.Error("processors:resizeVM:models.AllAppsByStatus(up): %s", err.Error())
+		return util.ErrorAppend(err, "failed to load running apps")
+	}
+
+	if err := Stop(); err != nil {
+		return util.ErrorAppend(err, "failed to stop the vm")
+	}
+
+	if err := util_provider.Resize(vm.CPUs, vm.RAM*1024, vm.Disk); err != nil {
+		return util.ErrorAppend(err, "failed to resize the vm")
+	}
+
+	if !running {
+		return nil
+	}
+
+	// the filesystem grows onto the disk before anything runs on it
+	if err := util_provider.Start(); err != nil {
+		return util.ErrorAppend(err, "failed to start the vm")
+	}
+
+	if err := util_provider.GrowDisk(); err != nil {
+		return util.ErrorAppend(err, "failed to grow the vm disk")
+	}
+
+	if err := Start(); err != nil {
+		return util.ErrorAppend(err, "failed to start the vm")
+	}
+
+	for _, appModel := range apps {
+		envModel, err := appModel.Env()
+		if err != nil {
+			lumber.Error("processors:resizeVM:models.App{ID:%s}.Env(): %s", appModel.ID, err.Error())
+			return util.ErrorAppend(err, "failed to load app env")
+		}
+
+		if err := app.Start(envModel, appModel, appModel.Name); err != nil {
+			return util.ErrorAppend(err, "failed to start %s", appModel.DisplayName())
+		}
+	}
+
+	return nil
+}
+
+// VMStatus shows what the vm is given and what of it is in use
+func VMStatus() error {
+	if util_provider.Name() == "native" {
+		return util.Errorf("[USER] the native provider has no VM, docker runs on the resources of the host")
+	}
+
+	if !vmCreated() {
+		return util.Errorf("[USER] the VM isn't created yet, 'nanobox start' creates it")
+	}
+
+	res, err := util_provider.Usage()
+	if err != nil {
+		return util.ErrorAppend(err, "failed to read the vm resources")
+	}
+
+	status := vmStatus{Status: util_provider.Status(), Resources: res}
+
+	if display.JSON() {
+		return display.PrintJSON(status)
+	}
+
+	fmt.Printf("Status: %s\n\n", status.Status)
+	fmt.Printf("%-8s %-10s %s\n", "", "Allocated", "Used")
+	fmt.Printf("%-8s %-10d %s\n", "CPUs", res.CPUs, vmLoad(status))
+	fmt.Printf("%-8s %-10s %s\n", "Memory", vmSize(res.Memory), vmUsed(status, res.MemoryUsed, res.Memory))
+	fmt.Printf("%-8s %-10s %s\n", "Disk", vmSize(res.Disk), vmUsed(status, res.DiskUsed, res.DiskSize))
+
+	// the filesystem didn't grow onto the disk
+	if res.DiskSize > 0 && res.Disk-res.DiskSize > 1024 {
+		display.Warn("The VM filesystem is %s of the %s disk, 'nanobox vm set --disk %dM' grows it\n",
+			vmSize(res.DiskSize), vmSize(res.Disk), res.Disk)
+	}
+
+	return nil
+}
+
+// ensureDiskSpace makes sure the vm disk has room for a build or a pull,
+// rather than letting docker run out of space halfway. With auto-grow-disk
+// the disk grows, otherwise it fails with what to do about it.
+func ensureDiskSpace() error {
+	if util_provider.Name() == "native" || !util_provider.IsReady() {
+		return nil
+	}
+
+	res, err := util_provider.Usage()
+	if err != nil || res.DiskSize == 0 {
+		// not knowing isn't a reason to stop a build
+		lumber.Error("processors:ensureDiskSpace:util_provider.Usage(): %v", err)
+		return nil
+	}
+
+	free := res.DiskSize - res.DiskUsed
+	if free >= minFreeDisk {
+		return nil
+	}
+
+	// the filesystem may not have grown onto the disk yet, otherwise the
+	// disk grows too
+	disk := res.Disk
+	if disk-res.DiskSize+free < minFreeDisk {
+		disk += diskGrowStep
+	}
+
+	conf, _ := models.LoadConfig()
+	if !conf.AutoGrowDisk {
+		return util.Errorf("[USER] the VM disk has %s of %s free, a build or a pull needs %s. Free up space with 'nanobox clean --all', grow the disk with 'nanobox vm set --disk %dG', or let nanobox grow it when it fills up with 'nanobox config set auto-grow-disk true'",
+			vmSize(free), vmSize(res.DiskSize), vmSize(minFreeDisk), (disk+1023)/1024)
+	}
+
+	display.Warn("The VM disk has %s of %s free, growing it to %s\n", vmSize(free), vmSize(res.DiskSize), vmSize(disk))
+
+	return VMSet(VMConfig{Disk: disk})
+}
+
+// vmCreated returns true if the vm exists
+func vmCreated() bool {
+	status := util_provider.Status()
+	return status != "" && !strings.Contains(strings.ToLower(status), "not exist") && !strings.HasPrefix(status, "err:")
+}
+
+// vmLoad returns the load average of a running vm
+func vmLoad(status vmStatus) string {
+	if status.Status != "Running" {
+		return "-"
+	}
+
+	return fmt.Sprintf("load %.2f", status.Load)
+}
+
+// vmUsed returns what of a size in megabytes is used, if the vm runs
+func vmUsed(status vmStatus, used, size int) string {
+	if status.Status != "Running" || size == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%s of %s (%d%%)", vmSize(used), vmSize(size), used*100/size)
+}
+
+// vmSize returns megabytes as a human size, ie: 6144 is 6GB
+func vmSize(mb int) string {
+	if mb >= 1024 {
+		return fmt.Sprintf("%.1fGB", float64(mb)/1024)
+	}
+
+	return fmt.Sprintf("%dMB", mb)
+}
@@ -0,0 +1,144 @@
+package processors
+
+import (
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/processors/app"
+	"github.com/nanobox-io/nanobox/processors/code"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/watch"
+)
+
+// how long the files have to be left alone before a rebuild starts
+const watchDebounce = 2 * time.Second
+
+// BuildWatch builds the code, then rebuilds it whenever the source changes,
+// until ctrl + c. A failed build is reported and the watch goes on.
+func BuildWatch(envModel *models.Env) error {
+	if err := Build(envModel); err != nil {
+		display.Error("%s\n", err.Error())
+	}
+
+	changes, stop, err := watch.Changes(envModel.Directory, watchDebounce)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to watch the source")
+	}
+	defer stop()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt)
+	defer signal.Stop(sigChan)
+
+	display.Info("\nWatching for changes, ctrl + c to quit\n")
+
+	for {
+		select {
+		case files := <-changes:
+			display.Info("\n%s changed, rebuilding\n", describeChanges(files))
+
+			// the build stops on ctrl + c as well, so check before going on
+			err := Build(envModel)
+			select {
+			case <-sigChan:
+				return err
+			default:
+			}
+
+			if err != nil {
+				display.Error("%s\n", err.Error())
+			}
+		case <-sigChan:
+			return nil
+		}
+	}
+}
+
+// watchDev rebuilds the runtime in the background when a build input
+// changes, then syncs the data components with the new boxfile. The console
+// owns the terminal, so the build output only goes to the build history.
+func watchDev(envModel *models.Env, appModel *models.App) (func(), error) {
+	changes, stop, err := watch.Changes(envModel.Directory, watchDebounce)
+	if err != nil {
+		return nil, util.ErrorAppend(err, "failed to watch the source")
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case files := <-changes:
+				inputs := []string{}
+				for _, file := range files {
+					if code.BuildInput(file) {
+						inputs = append(inputs, file)
+					}
+				}
+				if len(inputs) == 0 {
+					continue
+				}
+
+				// \r because the console is in raw mode
+				os.Stderr.WriteString("\r\nnanobox: " + describeChanges(inputs) + " changed, rebuilding the runtime (see 'nanobox build ls')\r\n")
+
+				if err := quietly(func() error { return rebuildDev(envModel, appModel) }); err != nil {
+					lumber.Error("processors:watchDev:rebuildDev(): %s", err.Error())
+					os.Stderr.WriteString("\r\nnanobox: the rebuild failed, see 'nanobox build ls'\r\n")
+					continue
+				}
+
+				os.Stderr.WriteString("\r\nnanobox: the runtime was rebuilt\r\n")
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		stop()
+	}, nil
+}
+
+// rebuildDev builds the runtime and restarts the data components that
+// changed in the new boxfile
+func rebuildDev(envModel *models.Env, appModel *models.App) error {
+	if err := Build(envModel); err != nil {
+		return err
+	}
+
+	// reload the env for the new boxfile
+	envModel, err := models.FindEnvByID(envModel.ID)
+	if err != nil {
+		return util.ErrorAppend(err, "failed to reload the env")
+	}
+
+	return app.Deploy(envModel, appModel)
+}
+
+// quietly runs fn with the display output discarded. The summary writes
+// straight to stdout, so it's turned off as well.
+func quietly(fn func() error) error {
+	out, summary := display.Out, display.Summary
+	display.Out, display.Summary = ioutil.Discard, false
+	defer func() { display.Out, display.Summary = out, summary }()
+
+	return fn()
+}
+
+// describeChanges summarizes the changed files for a message
+func describeChanges(files []string) string {
+	if len(files) > 3 {
+		return strings.Join(files[:3], ", ") + " and more"
+	}
+
+	return strings.Join(files, ", ")
+}
@@ -0,0 +1,150 @@
+package processors
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/events"
+)
+
+// webhookStatus is what 'nanobox webhook ls' reports
+type webhookStatus struct {
+	Webhooks []string `json:"webhooks"`
+	Events   string   `json:"events"`
+	Signed   bool     `json:"signed"`
+}
+
+// WebhookAdd adds a webhook the lifecycle events are sent to, an http(s)
+// url or a unix socket
+func WebhookAdd(target string) error {
+	if err := validWebhook(target); err != nil {
+		return err
+	}
+
+	conf, _ := models.LoadConfig()
+
+	targets := events.Targets(conf.Webhooks)
+	for _, existing := range targets {
+		if existing == target {
+			return util.Errorf("[USER] %s is already a webhook", target)
+		}
+	}
+
+	conf.Webhooks = strings.Join(append(targets, target), ",")
+	if err := conf.Save(); err != nil {
+		return util.ErrorAppend(err, "failed to save the config")
+	}
+
+	display.Info("\n%s The events are sent to %s, try it with 'nanobox webhook test'\n\n", display.TaskComplete, target)
+
+	return nil
+}
+
+// WebhookRemove removes a webhook
+func WebhookRemove(target string) error {
+	conf, _ := models.LoadConfig()
+
+	targets := []string{}
+	for _, existing := range events.Targets(conf.Webhooks) {
+		if existing != target {
+			targets = append(targets, existing)
+		}
+	}
+
+	if len(targets) == len(events.Targets(conf.Webhooks)) {
+		return util.Errorf("[USER] %s isn't a webhook, see 'nanobox webhook ls'", target)
+	}
+
+	conf.Webhooks = strings.Join(targets, ",")
+	if err := conf.Save(); err != nil {
+		return util.ErrorAppend(err, "failed to save the config")
+	}
+
+	display.Info("\n%s Removed %s\n\n", display.TaskComplete, target)
+
+	return nil
+}
+
+// WebhookList prints the webhooks and the events they get
+func WebhookList() error {
+	conf, _ := models.LoadConfig()
+
+	status := webhookStatus{
+		Webhooks: events.Targets(conf.Webhooks),
+		Events:   conf.WebhookEvents,
+		Signed:   conf.WebhookSecret != "",
+	}
+	if status.Events == "" {
+		status.Events = "all"
+	}
+
+	if display.JSON() {
+		return display.PrintJSON(status)
+	}
+
+	if len(status.Webhooks) == 0 {
+		fmt.Printf("\nThere are no webhooks, add one with 'nanobox webhook add <url>'\n\n")
+		return nil
+	}
+
+	fmt.Println()
+	for _, target := range status.Webhooks {
+		fmt.Printf("  %s\n", target)
+	}
+	fmt.Printf("\nEvents : %s\n", status.Events)
+	fmt.Printf("Signed : %t\n\n", status.Signed)
+
+	return nil
+}
+
+// WebhookTest sends a test event to every webhook and reports the ones that
+// failed to take it
+func WebhookTest() error {
+	conf, _ := models.LoadConfig()
+
+	targets := events.Targets(conf.Webhooks)
+	if len(targets) == 0 {
+		return util.Errorf("[USER] there are no webhooks, add one with 'nanobox webhook add <url>'")
+	}
+
+	event := events.Event{Type: events.Test, App: config.AppName()}
+
+	failed := 0
+	for _, target := range targets {
+		display.StartTask("Sending a test event to %s", target)
+		if err := events.Send(target, conf.WebhookSecret, event); err != nil {
+			display.ErrorTask()
+			display.Warn("%s didn't take the event: %s\n", target, err.Error())
+			failed++
+			continue
+		}
+		display.StopTask()
+	}
+
+	if failed > 0 {
+		return util.Errorf("[USER] %d of %d webhooks failed to take the test event", failed, len(targets))
+	}
+
+	display.Info("\n%s Every webhook took the test event\n\n", display.TaskComplete)
+
+	return nil
+}
+
+// validWebhook checks a webhook is an http(s) url or a unix socket
+func validWebhook(target string) error {
+	if strings.HasPrefix(target, "unix:") {
+		return nil
+	}
+
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return util.Errorf("[USER] '%s' isn't a webhook, use an http(s) url or unix:///path/to.sock", target)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package processors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// workspaceRow is a shared component in the list of workspaces
+type workspaceRow struct {
+	Workspace string   `json:"workspace"`
+	Component string   `json:"component"`
+	Image     string   `json:"image"`
+	IP        string   `json:"ip"`
+	Apps      []string `json:"apps"` // ie: shop (local)
+}
+
+// Workspaces prints the shared components of every workspace and the apps
+// that use them
+func Workspaces() error {
+	shared, err := models.AllSharedComponents()
+	if err != nil {
+		lumber.Error("Workspaces:models.AllSharedComponents(): %s", err.Error())
+		return util.ErrorAppend(err, "failed to load the shared components")
+	}
+
+	if len(shared) == 0 && !display.JSON() {
+		fmt.Println("No shared components yet. Add 'shared: <workspace>' to a data component of the boxfile.yml to share it.")
+		return nil
+	}
+
+	// the apps are shown by name
+	names := map[string]string{}
+	envs, _ := models.AllEnvs()
+	for _, envModel := range envs {
+		apps, _ := envModel.Apps()
+		for _, appModel := range apps {
+			names[appModel.ID] = fmt.Sprintf("%s (%s)", envModel.Name, appModel.DisplayName())
+		}
+	}
+
+	rows := []workspaceRow{}
+	for _, component := range shared {
+		row := workspaceRow{
+			Workspace: component.Workspace,
+			Component: component.Name,
+			Image:     component.Component.Image,
+			IP:        component.Component.IPAddr(),
+			Apps:      []string{},
+		}
+
+		for _, id := range component.Apps {
+			name, ok := names[id]
+			if !ok {
+				name = id
+			}
+			row.Apps = append(row.Apps, name)
+		}
+
+		rows = append(rows, row)
+	}
+
+	if display.JSON() {
+		return display.PrintJSON(rows)
+	}
+
+	fmt.Printf("\n%-15s %-15s %-25s %-15s %s\n", "Workspace", "Component", "Image", "IP", "Apps")
+	fmt.Println(strings.Repeat("-", 100))
+
+	for _, row := range rows {
+		fmt.Printf("%-15s %-15s %-25s %-15s %s\n",
+			row.Workspace, row.Component, row.Image, row.IP, strings.Join(row.Apps, ", "))
+	}
+
+	fmt.Println()
+
+	return nil
+}
@@ -0,0 +1,167 @@
+// Package audit keeps an append-only log of the commands that ran, who ran
+// them, against which app, how long they took and how they ended. It answers
+// "how did my environment get into this state", see 'nanobox history'.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/util/config"
+)
+
+// the results of a command
+const (
+	ResultOK     = "ok"
+	ResultFailed = "failed"
+)
+
+// Entry is a command in the audit log
+type Entry struct {
+	Time     time.Time     `json:"time"`
+	User     string        `json:"user"`
+	Command  string        `json:"command"`
+	Args     []string      `json:"args,omitempty"`
+	Dir      string        `json:"dir"` // the app the command ran against
+	Result   string        `json:"result"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// the commands that only read, they don't change any state
+var readOnly = map[string]bool{
+	"help":    true,
+	"history": true,
+	"info":    true,
+	"inspect": true,
+	"list":    true,
+	"log":     true,
+	"ls":      true,
+	"stats":   true,
+	"status":  true,
+	"version": true,
+}
+
+var (
+	current *Entry
+	once    sync.Once
+)
+
+// Path returns the location of the audit log
+func Path() string {
+	return filepath.ToSlash(filepath.Join(config.GlobalDir(), "audit.log"))
+}
+
+// Start begins the entry of a command, ie: evar add
+func Start(command string, args []string) {
+	words := strings.Fields(command)
+	if len(words) == 0 || readOnly[words[len(words)-1]] {
+		return
+	}
+
+	current = &Entry{
+		Time:    time.Now(),
+		User:    username(),
+		Command: command,
+		Args:    redact(args),
+		Dir:     config.LocalDir(),
+	}
+}
+
+// Finish records the result of the command that started. A command only
+// ends once, the calls after the first are ignored.
+func Finish(err error) {
+	if current == nil {
+		return
+	}
+
+	once.Do(func() {
+		current.Duration = time.Since(current.Time)
+		current.Result = ResultOK
+		if err != nil {
+			current.Result = ResultFailed
+			current.Error = err.Error()
+		}
+
+		if err := appendEntry(current); err != nil {
+			lumber.Error("audit:Finish:appendEntry(): %s", err.Error())
+		}
+	})
+}
+
+// Read returns the entries of the audit log, oldest first
+func Read() ([]Entry, error) {
+	entries := []Entry{}
+
+	file, err := os.Open(Path())
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return entries, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry := Entry{}
+
+		// a line cut short by a crash doesn't spoil the rest of the log
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// appendEntry adds an entry to the end of the audit log
+func appendEntry(entry *Entry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(Path(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(b, '\n'))
+	return err
+}
+
+// redact leaves the values out of the arguments, they can be evars or
+// passwords, ie: KEY=value becomes KEY=***
+func redact(args []string) []string {
+	redacted := []string{}
+
+	for _, arg := range args {
+		if i := strings.Index(arg, "="); i != -1 {
+			arg = arg[:i+1] + "***"
+		}
+		redacted = append(redacted, arg)
+	}
+
+	return redacted
+}
+
+// username returns the user running nanobox
+func username() string {
+	for _, key := range []string{"USER", "USERNAME"} {
+		if user := os.Getenv(key); user != "" {
+			return user
+		}
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,246 @@
+// Package bundle packs the images and the pinned engines an app needs into a
+// single gzipped tarball, so they can be carried to a machine without a
+// network and imported there. A bundle holds a manifest.json, the images as
+// docker saves them in images.tar, and the engines under engines/.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// the files of a bundle
+const (
+	manifestFile = "manifest.json"
+	imagesFile   = "images.tar"
+	enginesDir   = "engines"
+)
+
+// Manifest describes what a bundle holds
+type Manifest struct {
+	App     string    `json:"app"`
+	Images  []string  `json:"images"`
+	Engines []string  `json:"engines"` // relative to the engine cache, ie: ruby/v1.2.0
+	Created time.Time `json:"created"`
+}
+
+// Write writes a bundle to path with the manifest, the images tarball at
+// images and the engines of the manifest, which are read from enginesRoot
+func Write(path string, manifest Manifest, images, enginesRoot string) error {
+	tmp := path + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	if err := write(tw, manifest, images, enginesRoot); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// write adds the files of the bundle to the tarball, the manifest first so
+// it's known before the images are loaded
+func write(tw *tar.Writer, manifest Manifest, images, enginesRoot string) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{Name: manifestFile, Mode: 0644, Size: int64(len(data)), ModTime: manifest.Created}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+
+	if err := addFile(tw, images, imagesFile); err != nil {
+		return err
+	}
+
+	for _, engine := range manifest.Engines {
+		root := filepath.Join(enginesRoot, filepath.FromSlash(engine))
+
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(enginesRoot, path)
+			if err != nil {
+				return err
+			}
+			name := enginesDir + "/" + filepath.ToSlash(rel)
+
+			switch {
+			case info.IsDir():
+				return tw.WriteHeader(&tar.Header{Name: name + "/", Mode: 0755, Typeflag: tar.TypeDir, ModTime: info.ModTime()})
+			case info.Mode().IsRegular():
+				return addFile(tw, path, name)
+			}
+
+			// links and the like aren't part of an engine
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add the engine %s: %s", engine, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// addFile adds the file at path to the tarball as name
+func addFile(tw *tar.Writer, path, name string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// Read reads the bundle at path. The images tarball is handed to load as it's
+// read, and the engines are extracted into enginesRoot, replacing the copies
+// that are there.
+func Read(path, enginesRoot string, load func(images io.Reader) error) (Manifest, error) {
+	manifest := Manifest{}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return manifest, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return manifest, fmt.Errorf("%s isn't a nanobox bundle: %s", path, err.Error())
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	replaced := map[string]bool{}
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, err
+		}
+
+		switch {
+		case header.Name == manifestFile:
+			if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+				return manifest, fmt.Errorf("failed to read the manifest: %s", err.Error())
+			}
+
+		case header.Name == imagesFile:
+			if err := load(tr); err != nil {
+				return manifest, err
+			}
+
+		case strings.HasPrefix(header.Name, enginesDir+"/"):
+			if err := extract(tr, header, enginesRoot, replaced); err != nil {
+				return manifest, err
+			}
+		}
+	}
+
+	if manifest.Created.IsZero() {
+		return manifest, fmt.Errorf("%s isn't a nanobox bundle, it has no manifest", path)
+	}
+
+	return manifest, nil
+}
+
+// extract writes an engine file of the bundle under enginesRoot. The first
+// time an engine is seen the copy that's there is removed, so no stale file
+// is left behind.
+func extract(tr *tar.Reader, header *tar.Header, enginesRoot string, replaced map[string]bool) error {
+	rel := strings.TrimPrefix(header.Name, enginesDir+"/")
+	path := filepath.Join(enginesRoot, filepath.FromSlash(rel))
+
+	// a bundle can't write outside of the engine cache
+	if rel == "" || !strings.HasPrefix(path, filepath.Clean(enginesRoot)+string(filepath.Separator)) {
+		return fmt.Errorf("the bundle has an invalid path %s", header.Name)
+	}
+
+	// engines are name/ref
+	parts := strings.SplitN(rel, "/", 3)
+	if len(parts) >= 2 && parts[1] != "" {
+		engine := filepath.Join(enginesRoot, parts[0], parts[1])
+		if !replaced[engine] {
+			replaced[engine] = true
+			if err := os.RemoveAll(engine); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(path, 0755)
+	case tar.TypeReg, tar.TypeRegA:
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode)&0755|0600)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	return nil
+}
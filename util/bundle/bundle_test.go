@@ -0,0 +1,81 @@
+package bundle_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nanobox-io/nanobox/util/bundle"
+)
+
+func TestWriteRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nanobox-bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	from := filepath.Join(dir, "from")
+	os.MkdirAll(filepath.Join(from, "ruby", "v1.2.0", "bin"), 0755)
+	ioutil.WriteFile(filepath.Join(from, "ruby", "v1.2.0", "bin", "build"), []byte("#!/bin/bash\n"), 0755)
+
+	images := filepath.Join(dir, "images.tar")
+	ioutil.WriteFile(images, []byte("docker save"), 0644)
+
+	manifest := bundle.Manifest{
+		App:     "shop",
+		Images:  []string{"nanobox/build", "nanobox/postgresql:9.5"},
+		Engines: []string{"ruby/v1.2.0"},
+		Created: time.Now().UTC().Truncate(time.Second),
+	}
+
+	path := filepath.Join(dir, "shop.tgz")
+	if err := bundle.Write(path, manifest, images, from); err != nil {
+		t.Fatal(err)
+	}
+
+	// a stale file of the engine is replaced
+	to := filepath.Join(dir, "to")
+	os.MkdirAll(filepath.Join(to, "ruby", "v1.2.0"), 0755)
+	ioutil.WriteFile(filepath.Join(to, "ruby", "v1.2.0", "stale"), []byte{}, 0644)
+
+	loaded := ""
+	read, err := bundle.Read(path, to, func(r io.Reader) error {
+		data, err := ioutil.ReadAll(r)
+		loaded = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded != "docker save" {
+		t.Errorf("the images weren't loaded: '%s'", loaded)
+	}
+	if read.App != "shop" || len(read.Images) != 2 || !read.Created.Equal(manifest.Created) {
+		t.Errorf("unexpected manifest %+v", read)
+	}
+	if data, _ := ioutil.ReadFile(filepath.Join(to, "ruby", "v1.2.0", "bin", "build")); string(data) != "#!/bin/bash\n" {
+		t.Errorf("the engine wasn't extracted: '%s'", data)
+	}
+	if _, err := os.Stat(filepath.Join(to, "ruby", "v1.2.0", "stale")); !os.IsNotExist(err) {
+		t.Errorf("the stale engine file wasn't removed")
+	}
+}
+
+func TestReadNotABundle(t *testing.T) {
+	f, err := ioutil.TempFile("", "nanobox-bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("not gzip")
+	f.Close()
+	defer os.Remove(f.Name())
+
+	if _, err := bundle.Read(f.Name(), os.TempDir(), func(io.Reader) error { return nil }); err == nil {
+		t.Errorf("expected an error reading a file that isn't a bundle")
+	}
+}
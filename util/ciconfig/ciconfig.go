@@ -0,0 +1,241 @@
+// Package ciconfig generates the pipeline configuration of the ci providers,
+// which installs nanobox and runs 'nanobox ci' with the build cache kept
+// between the runs.
+package ciconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// CacheDir is where the pipelines keep the build cache, relative to the
+	// app
+	CacheDir = ".nanobox-cache"
+	// SummaryFile is the json summary of the run the pipelines keep
+	SummaryFile = "nanobox-ci.json"
+)
+
+// the stable linux binary the pipelines install
+const binaryURL = "https://s3.amazonaws.com/tools.nanobox.io/nanobox/v2/linux/amd64/nanobox"
+
+// the lock files the github cache key covers along with the boxfile, a
+// change in any of them means new packages in the cache
+var lockFiles = []string{
+	"Gemfile.lock", "package-lock.json", "yarn.lock", "Pipfile.lock",
+	"requirements.txt", "composer.lock", "mix.lock", "Cargo.lock", "go.sum",
+}
+
+// Options tune the generated pipeline
+type Options struct {
+	// the remote alias the pipeline deploys to, nothing is deployed if it's
+	// empty
+	Deploy string
+	// the branch that is deployed, the other branches are only tested
+	Branch string
+}
+
+// Provider is a ci provider nanobox can generate the pipeline of
+type Provider struct {
+	Name   string
+	Path   string // where the provider reads the pipeline, relative to the app
+	render func(Options) string
+}
+
+// Providers are the providers by name
+var Providers = map[string]Provider{
+	"github": {Name: "github", Path: ".github/workflows/nanobox.yml", render: github},
+	"gitlab": {Name: "gitlab", Path: ".gitlab-ci.yml", render: gitlab},
+	"circle": {Name: "circle", Path: ".circleci/config.yml", render: circle},
+}
+
+// Names returns the names of the providers, sorted
+func Names() []string {
+	names := []string{}
+	for name := range Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Render returns the pipeline
+func (p Provider) Render(opts Options) string {
+	if opts.Branch == "" {
+		opts.Branch = "main"
+	}
+
+	return fmt.Sprintf("# generated by 'nanobox generate ci --%s'\n", p.Name) + p.render(opts)
+}
+
+// command returns the nanobox ci command line, with the deploy when given
+func command(deploy bool, opts Options) string {
+	cmd := fmt.Sprintf("nanobox ci --cache-dir %s --summary %s", CacheDir, SummaryFile)
+	if deploy {
+		cmd += " --deploy " + opts.Deploy
+	}
+
+	return cmd
+}
+
+// deployScript returns the shell lines that run nanobox ci, deploying only
+// when the branch variable of the provider is the deployed branch
+func deployScript(branchVar string, opts Options) []string {
+	if opts.Deploy == "" {
+		return []string{command(false, opts)}
+	}
+
+	return []string{
+		fmt.Sprintf(`if [ "$%s" = "%s" ]; then`, branchVar, opts.Branch),
+		"  " + command(true, opts),
+		"else",
+		"  " + command(false, opts),
+		"fi",
+	}
+}
+
+// indent prefixes each line
+func indent(lines []string, prefix string) string {
+	return prefix + strings.Join(lines, "\n"+prefix) + "\n"
+}
+
+// github runs on a hosted ubuntu runner, which has docker. The cache is
+// keyed on the boxfile and the lock files, and a run on a new key starts
+// from the closest one.
+func github(opts Options) string {
+	patterns := []string{"'boxfile.yml'"}
+	for _, file := range lockFiles {
+		patterns = append(patterns, fmt.Sprintf("'**/%s'", file))
+	}
+
+	ci := command(false, opts)
+	env := "          NANOBOX_PROVIDER: native\n"
+	if opts.Deploy != "" {
+		ci = fmt.Sprintf("nanobox ci --cache-dir %s --summary %s ${{ github.ref == 'refs/heads/%s' && '--deploy %s' || '' }}",
+			CacheDir, SummaryFile, opts.Branch, opts.Deploy)
+		env += "          NANOBOX_TOKEN: ${{ secrets.NANOBOX_TOKEN }}\n"
+	}
+
+	return `name: nanobox
+
+on:
+  push:
+  pull_request:
+
+jobs:
+  ci:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+
+      - name: Restore the build cache
+        uses: actions/cache@v4
+        with:
+          path: ` + CacheDir + `
+          key: nanobox-${{ runner.os }}-${{ hashFiles(` + strings.Join(patterns, ", ") + `) }}
+          restore-keys: |
+            nanobox-${{ runner.os }}-
+
+      - name: Install nanobox
+        run: |
+          sudo curl -sSfL -o /usr/local/bin/nanobox ` + binaryURL + `
+          sudo chmod +x /usr/local/bin/nanobox
+
+      - name: Run nanobox ci
+        env:
+` + env + `        run: ` + ci + `
+
+      - name: Keep the summary
+        if: always()
+        uses: actions/upload-artifact@v4
+        with:
+          name: nanobox-ci
+          path: ` + SummaryFile + `
+`
+}
+
+// gitlab runs in a docker image with a docker service. The cache is kept
+// per branch, a new branch starts from the cache of the deployed one.
+func gitlab(opts Options) string {
+	token := ""
+	if opts.Deploy != "" {
+		token = "# NANOBOX_TOKEN is set in the ci/cd variables of the project\n"
+	}
+
+	return token + `nanobox:
+  image: docker:24
+  services:
+    - docker:24-dind
+  variables:
+    DOCKER_HOST: tcp://docker:2375
+    DOCKER_TLS_CERTDIR: ""
+    NANOBOX_PROVIDER: native
+  cache:
+    key: nanobox-$CI_COMMIT_REF_SLUG
+    fallback_keys:
+      - nanobox-` + opts.Branch + `
+    paths:
+      - ` + CacheDir + `/
+    when: always
+  before_script:
+    - apk add --no-cache curl
+    - curl -sSfL -o /usr/local/bin/nanobox ` + binaryURL + `
+    - chmod +x /usr/local/bin/nanobox
+  script:
+    - |
+` + indent(deployScript("CI_COMMIT_BRANCH", opts), "      ") + `  artifacts:
+    when: always
+    paths:
+      - ` + SummaryFile + `
+`
+}
+
+// circle runs on a machine, the docker executor can't mount volumes. Its
+// caches can't be overwritten, so every revision saves its own, and a run
+// restores the closest one of its boxfile and branch.
+func circle(opts Options) string {
+	token := ""
+	if opts.Deploy != "" {
+		token = "# NANOBOX_TOKEN is set in the environment variables of the project\n"
+	}
+
+	return token + `version: 2.1
+
+jobs:
+  ci:
+    machine:
+      image: ubuntu-2204:current
+    environment:
+      NANOBOX_PROVIDER: native
+    steps:
+      - checkout
+      - restore_cache:
+          keys:
+            - nanobox-v1-{{ checksum "boxfile.yml" }}-{{ .Branch }}-
+            - nanobox-v1-{{ checksum "boxfile.yml" }}-
+            - nanobox-v1-
+      - run:
+          name: Install nanobox
+          command: |
+            sudo curl -sSfL -o /usr/local/bin/nanobox ` + binaryURL + `
+            sudo chmod +x /usr/local/bin/nanobox
+      - run:
+          name: Run nanobox ci
+          command: |
+` + indent(deployScript("CIRCLE_BRANCH", opts), "            ") + `      # the cache is saved once the app is compiled, even if the tests fail
+      - save_cache:
+          when: always
+          key: nanobox-v1-{{ checksum "boxfile.yml" }}-{{ .Branch }}-{{ .Revision }}
+          paths:
+            - ` + CacheDir + `
+      - store_artifacts:
+          path: ` + SummaryFile + `
+
+workflows:
+  nanobox:
+    jobs:
+      - ci
+`
+}
@@ -0,0 +1,35 @@
+package ciconfig_test
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/nanobox-io/nanobox/util/ciconfig"
+)
+
+func TestRender(t *testing.T) {
+	for _, name := range ciconfig.Names() {
+		for _, opts := range []ciconfig.Options{{}, {Deploy: "production", Branch: "release"}} {
+			pipeline := ciconfig.Providers[name].Render(opts)
+
+			parsed := map[interface{}]interface{}{}
+			if err := yaml.Unmarshal([]byte(pipeline), &parsed); err != nil {
+				t.Errorf("%s: invalid yaml: %s\n%s", name, err, pipeline)
+			}
+
+			if !strings.Contains(pipeline, "--cache-dir "+ciconfig.CacheDir) {
+				t.Errorf("%s: the cache dir isn't passed to nanobox ci in '%s'", name, pipeline)
+			}
+
+			deploys := strings.Contains(pipeline, "--deploy production")
+			if deploys != (opts.Deploy != "") {
+				t.Errorf("%s: expected deploy %t in '%s'", name, opts.Deploy != "", pipeline)
+			}
+			if deploys && !strings.Contains(pipeline, "release") {
+				t.Errorf("%s: the deployed branch is missing in '%s'", name, pipeline)
+			}
+		}
+	}
+}
@@ -35,3 +35,17 @@ func TestSSHDir(t *testing.T) {
 		t.Errorf("incorrect ssh directory")
 	}
 }
+
+func TestEngineCacheDir(t *testing.T) {
+	if dir := EngineCacheDir("ruby"); dir != "" {
+		t.Errorf("expected an engine that isn't pinned to have no cache, got '%s'", dir)
+	}
+
+	if dir := EngineCacheDir("ruby#v2.4.1"); dir != GlobalDir()+"/engines/ruby/v2.4.1" {
+		t.Errorf("incorrect engine cache '%s'", dir)
+	}
+
+	if dir := EngineCacheDir("https://github.com/acme/engine.git#main"); dir != GlobalDir()+"/engines/https_github.com_acme_engine/main" {
+		t.Errorf("incorrect engine cache '%s'", dir)
+	}
+}
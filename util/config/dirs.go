@@ -110,6 +110,9 @@ func SSHDir() string {
 
 var engineDir string
 
+// validLocalEngine matches an engine on the local file system
+var validLocalEngine = regexp.MustCompile(`^[~|\.|\/|\\]`)
+
 // EngineDir gets the directory of the engine if it is a directory and on the
 // local file system
 func EngineDir() (string, error) {
@@ -117,24 +120,31 @@ func EngineDir() (string, error) {
 		return engineDir, nil
 	}
 
-	engineName := boxfile.NewFromPath(Boxfile()).Node("run.config").StringValue("engine")
-	if engineName != "" {
-		// if engine specified is not a filepath, set engine path to blank
-		var validLocal = regexp.MustCompile(`^[~|\.|\/|\\]`)
-		if !validLocal.MatchString(engineName) {
-			return "", nil
-		}
+	path, err := EngineDirFor(boxfile.NewFromPath(Boxfile()).Node("run.config"))
+	if err != nil {
+		return "", err
+	}
 
-		fi, err := os.Stat(engineName)
-		if err != nil {
-			return "", fmt.Errorf("Failed to find engine - %s", err.Error())
-		}
-		if fi.IsDir() {
-			path, err := filepath.Abs(engineName)
-			if err != nil {
-				return "", fmt.Errorf("Failed to resolve engine location - %s", err.Error())
-			}
-			engineDir = path
+	engineDir = path
+	return path, nil
+}
+
+// EngineDirFor gets the local directory of the engine of a run.config node:
+// the engine_path that overrides the engine, the engine itself if it's a
+// directory or the copy of a pinned engine, ie: ruby#v1.2.0, once it's
+// fetched
+func EngineDirFor(runConfig boxfile.Boxfile) (string, error) {
+	if path := runConfig.StringValue("engine_path"); path != "" {
+		return localEngineDir(path)
+	}
+
+	engineName := runConfig.StringValue("engine")
+	if validLocalEngine.MatchString(engineName) {
+		return localEngineDir(engineName)
+	}
+
+	if path := EngineCacheDir(engineName); path != "" {
+		if _, err := os.Stat(path); err == nil {
 			return path, nil
 		}
 	}
@@ -142,6 +152,54 @@ func EngineDir() (string, error) {
 	return "", nil
 }
 
+// LocalEngine returns true if the engine of the boxfile is a directory of the
+// developer's rather than one nanobox fetches
+func LocalEngine() bool {
+	runConfig := boxfile.NewFromPath(Boxfile()).Node("run.config")
+
+	return runConfig.StringValue("engine_path") != "" || validLocalEngine.MatchString(runConfig.StringValue("engine"))
+}
+
+// EngineCacheDir is where a pinned engine, ie: ruby#v1.2.0, is fetched to. It
+// is empty if the engine isn't pinned.
+func EngineCacheDir(engine string) string {
+	parts := strings.SplitN(engine, "#", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return ""
+	}
+
+	// the name may be a repository url
+	unsafe := regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+	name := unsafe.ReplaceAllString(strings.TrimSuffix(parts[0], ".git"), "_")
+	ref := unsafe.ReplaceAllString(parts[1], "_")
+
+	return filepath.ToSlash(filepath.Join(EnginesDir(), name, ref))
+}
+
+// EnginesDir is the cache the pinned engines are fetched to
+func EnginesDir() string {
+	return filepath.Join(GlobalDir(), "engines")
+}
+
+// localEngineDir resolves an engine on the local file system
+func localEngineDir(engineName string) (string, error) {
+	fi, err := os.Stat(engineName)
+	if err != nil {
+		return "", fmt.Errorf("Failed to find engine - %s", err.Error())
+	}
+
+	if !fi.IsDir() {
+		return "", nil
+	}
+
+	path, err := filepath.Abs(engineName)
+	if err != nil {
+		return "", fmt.Errorf("Failed to resolve engine location - %s", err.Error())
+	}
+
+	return path, nil
+}
+
 // BinDir creates a directory where nanobox specific binaries can be downloaded
 // docker, dockermachine, etc
 func BinDir() string {
@@ -1,7 +1,7 @@
 package config
 
 import (
-	// "fmt"
+	"fmt"
 	// "io/ioutil"
 	// "os"
 	"path/filepath"
@@ -13,3 +13,14 @@ import (
 func Boxfile() string {
 	return filepath.ToSlash(filepath.Join(LocalDir(), "boxfile.yml"))
 }
+
+// BoxfileOverlay is the boxfile of an environment that is merged onto
+// boxfile.yml, ie: boxfile.dev.yml
+func BoxfileOverlay(env string) string {
+	return filepath.ToSlash(filepath.Join(LocalDir(), fmt.Sprintf("boxfile.%s.yml", env)))
+}
+
+// TeamConfig is the configuration of the app a team shares
+func TeamConfig() string {
+	return filepath.ToSlash(filepath.Join(LocalDir(), ".nanobox", "config.yml"))
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	syscall "github.com/docker/docker/pkg/signal"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/docker/pkg/term"
 	"github.com/jcelliott/lumber"
 	"github.com/nanobox-io/golang-docker-client"
@@ -101,8 +102,22 @@ func Run(id string, consoleConfig ConsoleConfig) error {
 		}
 	}
 
-	go io.Copy(resp.Conn, os.Stdin)
-	io.Copy(os.Stdout, resp.Reader)
+	// the end of the input is passed on, so a command reading a pipe ends
+	go func() {
+		io.Copy(resp.Conn, os.Stdin)
+		if conn, ok := resp.Conn.(interface {
+			CloseWrite() error
+		}); ok {
+			conn.CloseWrite()
+		}
+	}()
+
+	// without a tty, docker multiplexes stdout and stderr into frames
+	if isTerminal {
+		io.Copy(os.Stdout, resp.Reader)
+	} else {
+		stdcopy.StdCopy(os.Stdout, os.Stderr, resp.Reader)
+	}
 
 	// after the console closes lets get the exit code
 	exInspect, _ := docker.ExecInspect(exec.ID)
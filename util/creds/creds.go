@@ -0,0 +1,104 @@
+// Package creds generates the passwords for component users according to
+// the configured password policy.
+package creds
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// the charsets a policy can use
+var charsets = map[string]string{
+	"alnum":   "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+	"alpha":   "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"lower":   "abcdefghijklmnopqrstuvwxyz0123456789",
+	"hex":     "0123456789abcdef",
+	"symbols": "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.~",
+}
+
+// DefaultLength is the password length used when none is configured
+const DefaultLength = 10
+
+// Policy describes how passwords are generated
+type Policy struct {
+	Length  int    // number of characters
+	Charset string // one of alnum, alpha, lower, hex, symbols
+	// Seed makes the passwords deterministic. The same seed and parts always
+	// produce the same password, which keeps connection strings reproducible.
+	Seed string
+}
+
+// ValidCharset returns true if the charset is known
+func ValidCharset(charset string) bool {
+	_, ok := charsets[charset]
+	return ok
+}
+
+// Password generates a password. parts identify the credential (ie: the app,
+// component and user) and only matter for deterministic policies.
+func (p Policy) Password(parts ...string) (string, error) {
+	length := p.Length
+	if length <= 0 {
+		length = DefaultLength
+	}
+
+	charset, ok := charsets[p.Charset]
+	if !ok {
+		charset = charsets["alnum"]
+	}
+
+	var stream []uint64
+	if p.Seed != "" {
+		stream = seededStream(p.Seed, strings.Join(parts, "/"), length)
+	} else {
+		var err error
+		if stream, err = randomStream(length); err != nil {
+			return "", err
+		}
+	}
+
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = charset[stream[i]%uint64(len(charset))]
+	}
+
+	return string(b), nil
+}
+
+// randomStream returns n random numbers from the system's secure source
+func randomStream(n int) ([]uint64, error) {
+	buf := make([]byte, n*8)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("unable to read random bytes: %s", err.Error())
+	}
+
+	return toUint64s(buf), nil
+}
+
+// seededStream derives n numbers from the seed and the id with hmac-sha256
+func seededStream(seed, id string, n int) []uint64 {
+	buf := []byte{}
+
+	for counter := uint32(0); len(buf) < n*8; counter++ {
+		mac := hmac.New(sha256.New, []byte(seed))
+		mac.Write([]byte(id))
+		binary.Write(mac, binary.BigEndian, counter)
+		buf = append(buf, mac.Sum(nil)...)
+	}
+
+	return toUint64s(buf[:n*8])
+}
+
+// toUint64s splits the bytes into numbers
+func toUint64s(buf []byte) []uint64 {
+	nums := make([]uint64, len(buf)/8)
+	for i := range nums {
+		nums[i] = binary.BigEndian.Uint64(buf[i*8 : i*8+8])
+	}
+
+	return nums
+}
@@ -0,0 +1,56 @@
+package creds_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/creds"
+)
+
+// password generates a password, failing the test on an error
+func password(t *testing.T, policy creds.Policy, parts ...string) string {
+	password, err := policy.Password(parts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return password
+}
+
+func TestPasswordLength(t *testing.T) {
+	if password := password(t, creds.Policy{}); len(password) != creds.DefaultLength {
+		t.Errorf("expected a %d char password, got '%s'", creds.DefaultLength, password)
+	}
+
+	if password := password(t, creds.Policy{Length: 32}); len(password) != 32 {
+		t.Errorf("expected a 32 char password, got '%s'", password)
+	}
+}
+
+func TestPasswordCharset(t *testing.T) {
+	password := password(t, creds.Policy{Length: 64, Charset: "hex"})
+	if strings.Trim(password, "0123456789abcdef") != "" {
+		t.Errorf("expected a hex password, got '%s'", password)
+	}
+}
+
+func TestPasswordDeterministic(t *testing.T) {
+	policy := creds.Policy{Seed: "seed"}
+
+	first := password(t, policy, "app", "data.db", "nanobox")
+	second := password(t, policy, "app", "data.db", "nanobox")
+	other := password(t, policy, "app", "data.db", "admin")
+
+	if first != second {
+		t.Errorf("seeded passwords should match, got '%s' and '%s'", first, second)
+	}
+
+	if first == other {
+		t.Errorf("seeded passwords for different users should differ")
+	}
+
+	random := creds.Policy{}
+	if password(t, random) == password(t, random) {
+		t.Errorf("random passwords should differ")
+	}
+}
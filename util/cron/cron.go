@@ -0,0 +1,146 @@
+// Package cron parses the cron schedules used by the boxfile cron jobs
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron schedule. Each field holds the values it matches.
+type Schedule struct {
+	minute, hour, dom, month, dow map[int]bool
+
+	// cron matches either the day of month or the day of week when both are
+	// restricted
+	domStar, dowStar bool
+}
+
+// field bounds, in the order of a cron spec
+var bounds = []struct{ min, max int }{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7},  // day of week, 0 and 7 are sunday
+}
+
+// shortcuts for common schedules
+var shortcuts = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a standard 5 field cron spec, ie: '*/5 * * * *'
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if shortcut, ok := shortcuts[spec]; ok {
+		spec = shortcut
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in '%s', found %d", spec, len(fields))
+	}
+
+	sets := make([]map[int]bool, 5)
+	for i, field := range fields {
+		set, err := parseField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid field '%s' in '%s': %s", field, spec, err.Error())
+		}
+		sets[i] = set
+	}
+
+	// sunday can be written as 0 or 7
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	return &Schedule{
+		minute:  sets[0],
+		hour:    sets[1],
+		dom:     sets[2],
+		month:   sets[3],
+		dow:     sets[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}, nil
+}
+
+// Matches returns true if the schedule should fire during the minute of t
+func (s *Schedule) Matches(t time.Time) bool {
+	if !s.minute[t.Minute()] || !s.hour[t.Hour()] || !s.month[int(t.Month())] {
+		return false
+	}
+
+	dom := s.dom[t.Day()]
+	dow := s.dow[int(t.Weekday())]
+
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return dow
+	case s.dowStar:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// parseField parses a comma separated list of values, ranges and steps
+func parseField(field string, min, max int) (map[int]bool, error) {
+	set := map[int]bool{}
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i != -1 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step '%s'", part[i+1:])
+			}
+			part = part[:i]
+		}
+
+		start, end := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid value '%s'", bounds[0])
+			}
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("invalid value '%s'", bounds[1])
+			}
+		default:
+			val, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value '%s'", part)
+			}
+			start = val
+			// a single value with a step runs from the value to the max
+			if step == 1 {
+				end = val
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return nil, fmt.Errorf("'%s' is out of range %d-%d", part, min, max)
+		}
+
+		for i := start; i <= end; i += step {
+			set[i] = true
+		}
+	}
+
+	return set, nil
+}
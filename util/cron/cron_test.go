@@ -0,0 +1,53 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nanobox-io/nanobox/util/cron"
+)
+
+func TestMatches(t *testing.T) {
+	// a wednesday
+	now := time.Date(2017, 3, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		spec    string
+		matches bool
+	}{
+		{"* * * * *", true},
+		{"*/15 * * * *", true},
+		{"*/7 * * * *", false},
+		{"30 10 * * *", true},
+		{"0-29 * * * *", false},
+		{"20,30,40 9-11 * * *", true},
+		{"30 10 * * 1-5", true},
+		{"30 10 * * 0,6", false},
+		{"30 10 1 * 3", true},
+		{"30 10 15 * 0", true},
+		{"30 10 1 * 0", false},
+		{"@hourly", false},
+	}
+
+	for _, test := range tests {
+		schedule, err := cron.Parse(test.spec)
+		if err != nil {
+			t.Errorf("failed to parse '%s': %s", test.spec, err)
+			continue
+		}
+
+		if schedule.Matches(now) != test.matches {
+			t.Errorf("'%s' matches %t, expected %t", test.spec, !test.matches, test.matches)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	specs := []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"}
+
+	for _, spec := range specs {
+		if _, err := cron.Parse(spec); err == nil {
+			t.Errorf("expected an error parsing '%s'", spec)
+		}
+	}
+}
@@ -179,6 +179,21 @@ func ReturnIP(ip net.IP) error {
 	return nil
 }
 
+// Reserved returns true if the ip is still reserved
+func Reserved(ip net.IP) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	reservedIPs, _ := getReserved()
+	for _, reservedIP := range reservedIPs {
+		if reservedIP.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // getIPSpace do not store the space on the disk.
 func getIPSpace() (IPSpace, error) {
 	ipSpace := IPSpace{}
@@ -77,3 +77,18 @@ func TestReuseIP(t *testing.T) {
 	dhcp.ReturnIP(one)
 	dhcp.ReturnIP(three)
 }
+
+// TestReserved ...
+func TestReserved(t *testing.T) {
+	ip, err := dhcp.ReserveGlobal()
+	if err != nil {
+		t.Errorf("unable to reserve ip", err)
+	}
+	if !dhcp.Reserved(ip) {
+		t.Errorf("%s should be reserved", ip.String())
+	}
+	dhcp.ReturnIP(ip)
+	if dhcp.Reserved(ip) {
+		t.Errorf("%s should have been returned", ip.String())
+	}
+}
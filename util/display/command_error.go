@@ -12,8 +12,11 @@ import (
 	"github.com/nanobox-io/nanobox/commands/registry"
 	"github.com/nanobox-io/nanobox/models"
 	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/audit"
 	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/notify"
 	"github.com/nanobox-io/nanobox/util/odin"
+	"github.com/nanobox-io/nanobox/util/telemetry"
 )
 
 var (
@@ -33,13 +36,28 @@ func CommandErr(err error) {
 		// if an exit code is provided we need to quit here
 		// and use that exit code
 		if exitCode != 0 {
+			audit.Finish(fmt.Errorf("exited with %d", exitCode))
+			telemetry.Finish(true, "EXIT")
+			notify.Finish(true)
 			os.Exit(exitCode)
 		}
 		return
 	}
 
+	audit.Finish(err)
+
 	parsedErr := parseCommandErr(err)
 
+	// the exit code tells scripts what went wrong, see util.ExitCode
+	class := util.ErrorClass(err)
+	if exitCode == 0 {
+		exitCode = util.ExitCode(err)
+	}
+
+	// only the class of the error, never the message
+	telemetry.Finish(true, class)
+	notify.Finish(true)
+
 	output := fmt.Sprintf(`
 Error   : %s
 Context : %s`, parsedErr.cause, parsedErr.context)
@@ -99,9 +117,19 @@ Suggest : %s`, output, parsedErr.suggest)
 	)
 
 	// display error to user
-	fmt.Println(output)
+	if JSON() {
+		PrintJSON(map[string]interface{}{
+			"error":     parsedErr.cause,
+			"context":   parsedErr.context,
+			"suggest":   parsedErr.suggest,
+			"class":     class,
+			"exit_code": exitCode,
+		})
+	} else {
+		fmt.Println(output)
+	}
 
-	if runtime.GOOS == "windows" {
+	if runtime.GOOS == "windows" && Styled() {
 		// The update process was spawned in a separate window, which will
 		// close as soon as this command is finished. To ensure they see the
 		// message, we need to hold open the process until they hit enter.
@@ -109,11 +137,6 @@ Suggest : %s`, output, parsedErr.suggest)
 		var input string
 		fmt.Scanln(&input)
 	}
-	if exitCode == 0 {
-		// todo: handle
-		os.Exit(1)
-	}
-	// todo: handle
 	os.Exit(exitCode)
 }
 
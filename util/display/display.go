@@ -25,8 +25,16 @@ var (
 	// Summary - summarize the output and hide log details
 	Summary = true
 
-	// Interactive - re-draw the summary when updates occur
-	Interactive = terminal.IsTerminal(int(os.Stderr.Fd()))
+	// Interactive - stdout is a terminal, so the summary and the progress are
+	// re-drawn in place and the logs are colored. Otherwise, ie: in ci or a
+	// pipe, they're printed as plain lines every ProgressInterval.
+	Interactive = terminal.IsTerminal(int(os.Stdout.Fd()))
+
+	// ProgressInterval - how often progress is printed when not Interactive
+	ProgressInterval = 5 * time.Second
+
+	// CanPrompt - stdin is a terminal, so the user can answer prompts
+	CanPrompt = terminal.IsTerminal(int(os.Stdin.Fd()))
 
 	// Level - info, warn, error, debug, trace
 	Level = "info"
@@ -37,6 +45,9 @@ var (
 	// Out - writer to send output to
 	Out io.Writer = os.Stdout
 
+	// Capture - an extra writer that receives everything written to the log file
+	Capture io.Writer
+
 	// internal
 	logFile *os.File // open file descriptor of the log file
 	// context
@@ -353,6 +364,10 @@ func printOut(message string) error {
 
 // printLogFile prints a message to the log file
 func printLogFile(message string) error {
+	if Capture != nil {
+		io.WriteString(Capture, message)
+	}
+
 	// short-circuit if Log is set to false
 	if !Log {
 		return nil
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 )
 
 type (
@@ -38,6 +39,10 @@ type (
 		Prefix   string
 		parts    []*DockerPercentPart
 		leftover []byte
+
+		// when not Interactive
+		shown   string
+		shownAt time.Time
 	}
 )
 
@@ -146,8 +151,7 @@ func (display *DockerPercentDisplay) Write(data []byte) (int, error) {
 			}
 		}
 
-		fmt.Fprintf(display.Output, "\r\x1b[K")
-		fmt.Fprintf(display.Output, "%s %s", display.Prefix, display.show())
+		display.print()
 
 		if strings.HasPrefix(status.Status, "Status:") {
 			// maybe we want to display the status line here
@@ -157,6 +161,25 @@ func (display *DockerPercentDisplay) Write(data []byte) (int, error) {
 	return len(data), nil
 }
 
+// print re-draws the progress on the line, or prints it on a new line every
+// ProgressInterval when the output isn't a terminal
+func (display *DockerPercentDisplay) print() {
+	if Interactive {
+		fmt.Fprintf(display.Output, "\r\x1b[K")
+		fmt.Fprintf(display.Output, "%s %s", display.Prefix, display.show())
+		return
+	}
+
+	shown := display.show()
+	if shown == "" || shown == display.shown || time.Since(display.shownAt) < ProgressInterval {
+		return
+	}
+
+	fmt.Fprintf(display.Output, "%s %s\n", display.Prefix, shown)
+	display.shown = shown
+	display.shownAt = time.Now()
+}
+
 func displaySize(part *DockerPercentPart) string {
 	switch {
 	case part.downloadTotal > 1024*1024:
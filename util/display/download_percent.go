@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 )
 
 const bytesPerMB = 1024 * 1024
@@ -13,6 +14,10 @@ type DownloadPercent struct {
 	current int64
 	Total   int64
 	Output  io.Writer
+
+	// when not Interactive
+	shown   int64
+	shownAt time.Time
 }
 
 func (dp *DownloadPercent) Copy(writer io.Writer, reader io.Reader) (err error) {
@@ -64,9 +69,30 @@ func (dp *DownloadPercent) Copy(writer io.Writer, reader io.Reader) (err error)
 	return err
 }
 
+// Add counts n more bytes and updates the display, for progress that isn't
+// made by a single Copy
+func (dp *DownloadPercent) Add(n int64) {
+	if dp.Output == nil {
+		dp.Output = os.Stdout
+	}
+
+	dp.current += n
+	dp.UpdateDisplay()
+}
+
 func (dp *DownloadPercent) UpdateDisplay() {
-	// clear the link
-	fmt.Fprintf(dp.Output, "\r\x1b[K")
+	// without a terminal the progress is printed on a new line every so often
+	if !Interactive {
+		if !dp.shownAt.IsZero() && (dp.current == dp.shown || (time.Since(dp.shownAt) < ProgressInterval && dp.current != dp.Total)) {
+			return
+		}
+		dp.shown = dp.current
+		dp.shownAt = time.Now()
+		defer fmt.Fprintln(dp.Output)
+	} else {
+		// clear the link
+		fmt.Fprintf(dp.Output, "\r\x1b[K")
+	}
 
 	if dp.Total == 0 {
 		dp.SimpleDisplay()
@@ -78,7 +104,7 @@ func (dp *DownloadPercent) UpdateDisplay() {
 	totalInMB := float64(dp.Total) / bytesPerMB
 	percent := (float64(dp.current) / float64(dp.Total)) * 100
 
-	fmt.Fprintf(dp.Output, "\r   %.2f/%.2fMB [%-41s %.2f%%]", currentInMB, totalInMB, strings.Repeat("*", int(percent/2.5)), percent)
+	fmt.Fprintf(dp.Output, "   %.2f/%.2fMB [%-41s %.2f%%]", currentInMB, totalInMB, strings.Repeat("*", int(percent/2.5)), percent)
 
 }
 
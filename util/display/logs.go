@@ -42,7 +42,7 @@ func FormatLogMessage(msg mist.Message, showTimestamp bool) {
 	entry := Entry{}
 	if err := json.Unmarshal([]byte(msg.Data), &entry); err != nil {
 		message := fmt.Sprintf("[light_red]%s :: %s\n[reset]%s", time.Now().Format(layout), msg.Data, fmt.Sprintf("Failed to process entry - '%s'. Please upgrade your logging component and try again.", err.Error()))
-		fmt.Println(colorstring.Color(message))
+		fmt.Println(colorize(message))
 		return
 	}
 
@@ -67,7 +67,7 @@ func FormatLogMessage(msg mist.Message, showTimestamp bool) {
 	} else {
 		message = fmt.Sprintf("[%s]%s %s (%s) :: %s[reset]", logProcesses[entryTag], fmt.Sprintf(entry.Time.Format(layout)), entry.ID, entryTag, fmtMsg)
 	}
-	fmt.Println(colorstring.Color(message))
+	fmt.Println(colorize(message))
 	return
 }
 
@@ -110,6 +110,37 @@ func FormatLogvacMessage(msg logvac.Message, showTimestamp bool) {
 		message = fmt.Sprintf("[%s]%s %s (%s) :: %s[reset]", logProcesses[entryTag], fmt.Sprintf(entry.Time.Format(layout)), entry.ID, entryTag, fmtMsg)
 	}
 
-	fmt.Println(colorstring.Color(message))
+	fmt.Println(colorize(message))
 	return
 }
+
+// FormatLogLine prints a line of the aggregated log of an app, prefixed with
+// its component in a color of its own, ie: web.main (stderr) :: ...
+func FormatLogLine(component, stream, text string, t time.Time, showTimestamp bool) {
+	// set the time output format
+	layout := "Mon Jan 02 15:04:05 2006" // time.RFC822
+
+	// for each new component assign it a color to be used when output
+	if _, ok := logProcesses[component]; !ok {
+		logProcesses[component] = logColors[len(logProcesses)%len(logColors)]
+	}
+
+	message := fmt.Sprintf("[%s]%s (%s) ::[reset] %s", logProcesses[component], component, stream, text)
+	if showTimestamp {
+		message = fmt.Sprintf("[%s]%s %s (%s) ::[reset] %s", logProcesses[component], t.Local().Format(layout), component, stream, text)
+	}
+
+	fmt.Println(colorize(message))
+}
+
+// colorize turns the color tags of a message into escape sequences, or drops
+// them when the output isn't a terminal
+func colorize(message string) string {
+	colorize := colorstring.Colorize{
+		Colors:  colorstring.DefaultColors,
+		Disable: !Interactive,
+		Reset:   true,
+	}
+
+	return colorize.Color(message)
+}
@@ -76,6 +76,38 @@ Pass: available in your dashboard (if applicable)
 `, component, port))
 }
 
+// LocalTunnelEstablished shows how to connect through a tunnel to a local
+// component
+func LocalTunnelEstablished(component, port, user, pass string, connect []string) {
+	if user == "" {
+		user = "none"
+	}
+	if pass == "" {
+		pass = "none"
+	}
+
+	os.Stderr.WriteString(fmt.Sprintf(`
+--------------------------------------------------------------------------------
++ Tunnel established to %s, ctrl + c to close it
++ Use the following credentials to connect
+--------------------------------------------------------------------------------
+
+Host: 127.0.0.1
+Port: %s
+User: %s
+Pass: %s
+
+`, component, port, user, pass))
+
+	if len(connect) > 0 {
+		os.Stderr.WriteString("Connect with:\n\n")
+		for _, line := range connect {
+			os.Stderr.WriteString(fmt.Sprintf("  %s\n", line))
+		}
+		os.Stderr.WriteString("\n")
+	}
+}
+
 func InfoDevContainer(ip string) {
 	os.Stderr.WriteString(fmt.Sprintf(`
 --------------------------------------------------------------------------------
@@ -0,0 +1,55 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/nanobox-io/nanobox/util"
+)
+
+// Quiet - only print the results of a command and its errors
+var Quiet = false
+
+// SetOutput sets how commands print, text or json. In json mode and in quiet
+// mode the task headers, spinners and messages are left out of the output,
+// they still go to the log file.
+func SetOutput(mode string, quiet bool) error {
+	if mode != "text" && mode != "json" {
+		return util.Errorf("[USER] '%s' isn't an output, use text or json", mode)
+	}
+
+	Mode = mode
+	Quiet = quiet
+
+	if JSON() || Quiet {
+		Out = ioutil.Discard
+		Summary = false
+		Interactive = false
+	}
+
+	return nil
+}
+
+// JSON returns true when a command should print its result as json
+func JSON() bool {
+	return Mode == "json"
+}
+
+// Styled returns true when the output is meant for a person to read, not a
+// script
+func Styled() bool {
+	return !JSON() && !Quiet
+}
+
+// PrintJSON prints the result of a command as json
+func PrintJSON(v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the output: %s", err.Error())
+	}
+
+	_, err = fmt.Fprintf(os.Stdout, "%s\n", b)
+	return err
+}
@@ -0,0 +1,244 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lyondhill/vtclean"
+
+	"github.com/nanobox-io/nanobox/util"
+)
+
+// the states of a progress line
+const (
+	progressRunning = "running"
+	progressDone    = "done"
+	progressFailed  = "failed"
+)
+
+type (
+	// Progress shows a line for each of the things a task does at the same
+	// time, ie: the layers of an image or the components that start together.
+	// Each line is re-drawn in place as it updates, instead of the output of
+	// all of them being interleaved.
+	Progress struct {
+		Label  string
+		Prefix string
+
+		mu     sync.Mutex
+		lines  []*ProgressLine
+		drawn  int // the number of lines on the screen
+		spin   int
+		ticker *time.Ticker
+		done   chan struct{}
+	}
+
+	// ProgressLine is a line of a Progress
+	ProgressLine struct {
+		Name string
+
+		progress *Progress
+		state    string
+		detail   string
+		leftover string
+		shownAt  time.Time // when it was printed, when not Interactive
+	}
+)
+
+// StartProgress starts a task that shows a line for each of the things it
+// does at the same time
+func StartProgress(format string, args ...interface{}) *Progress {
+	p := &Progress{
+		Label:  fmt.Sprintf(format, args...),
+		Prefix: strings.Repeat("  ", context),
+		done:   make(chan struct{}),
+	}
+
+	if termWidth == 0 {
+		_, termWidth = util.GetTerminalSize()
+	}
+
+	printLogFile(fmt.Sprintf("%s%s :\n", p.Prefix, p.Label))
+
+	if !p.animated() {
+		printOut(fmt.Sprintf("%s%s :\n", p.Prefix, p.Label))
+		return p
+	}
+
+	p.draw()
+
+	p.ticker = time.NewTicker(time.Millisecond * 80)
+	go func() {
+		for {
+			select {
+			case <-p.done:
+				return
+			case <-p.ticker.C:
+				p.mu.Lock()
+				p.spin = (p.spin + 1) % len(TaskSpinner)
+				p.draw()
+				p.mu.Unlock()
+			}
+		}
+	}()
+
+	return p
+}
+
+// Line adds a line to the progress
+func (p *Progress) Line(name string) *ProgressLine {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	line := &ProgressLine{Name: name, progress: p, state: progressRunning}
+	p.lines = append(p.lines, line)
+
+	return line
+}
+
+// Stop stops re-drawing the progress and leaves the final state of every
+// line on the screen
+func (p *Progress) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ticker == nil {
+		return
+	}
+
+	p.ticker.Stop()
+	close(p.done)
+	p.ticker = nil
+
+	p.draw()
+}
+
+// animated returns true when the lines are re-drawn in place, otherwise each
+// update is printed on a line of its own
+func (p *Progress) animated() bool {
+	return Interactive && Summary
+}
+
+// draw re-draws the header and every line. The caller holds the lock.
+func (p *Progress) draw() {
+	out := strings.Repeat("\x1b[1A\x1b[K", p.drawn)
+
+	marker := TaskSpinner[p.spin]
+	if p.ticker == nil && p.drawn > 0 {
+		marker = TaskComplete
+		for _, line := range p.lines {
+			if line.state == progressFailed {
+				marker = "!"
+			}
+		}
+	}
+
+	out += fmt.Sprintf("%s%s %s :\n", p.Prefix, marker, p.Label)
+	for _, line := range p.lines {
+		out += line.String() + "\n"
+	}
+
+	printOut(out)
+	p.drawn = len(p.lines) + 1
+}
+
+// String returns the line as it's shown
+func (l *ProgressLine) String() string {
+	marker := "-"
+	if l.progress.animated() {
+		marker = TaskSpinner[l.progress.spin]
+	}
+
+	switch l.state {
+	case progressDone:
+		marker = TaskComplete
+	case progressFailed:
+		marker = "!"
+	}
+
+	line := fmt.Sprintf("%s  %s %s", l.progress.Prefix, marker, l.Name)
+	if l.detail != "" {
+		line = fmt.Sprintf("%s  %s", line, l.detail)
+	}
+
+	if termWidth > 5 && len(line) > termWidth-5 {
+		line = line[:termWidth-5] + "..."
+	}
+
+	return line
+}
+
+// Set sets the detail of the line, ie: the status of a component
+func (l *ProgressLine) Set(format string, args ...interface{}) {
+	l.update(l.state, fmt.Sprintf(format, args...))
+}
+
+// Done marks the line as finished
+func (l *ProgressLine) Done() {
+	l.update(progressDone, l.detail)
+}
+
+// Fail marks the line as failed
+func (l *ProgressLine) Fail(err error) {
+	l.update(progressFailed, err.Error())
+}
+
+// Write shows the last line of the output written to it as the detail, the
+// full output goes to the log file. It makes a line the Output of a
+// DockerPercentDisplay or of a command.
+func (l *ProgressLine) Write(data []byte) (int, error) {
+	// an incomplete line is kept until the rest of it is written
+	l.progress.mu.Lock()
+	printLogFile(string(data))
+	text := l.leftover + string(data)
+	i := strings.LastIndexAny(text, "\r\n")
+	l.leftover = text[i+1:]
+	text = text[:i+1]
+	l.progress.mu.Unlock()
+
+	detail := ""
+	for _, line := range strings.FieldsFunc(text, func(c rune) bool { return c == '\n' || c == '\r' }) {
+		line = strings.TrimSpace(vtclean.Clean(EscSeqRegex.ReplaceAllString(line, ""), false))
+		if line != "" {
+			detail = line
+		}
+	}
+
+	if detail != "" {
+		l.update(l.state, detail)
+	}
+
+	return len(data), nil
+}
+
+// update changes the state and the detail of the line and shows it
+func (l *ProgressLine) update(state, detail string) {
+	l.progress.mu.Lock()
+	defer l.progress.mu.Unlock()
+
+	changed := state != l.state
+	l.state = state
+	l.detail = detail
+
+	if state != progressRunning {
+		printLogFile(fmt.Sprintf("%s  %s: %s %s\n", l.progress.Prefix, l.Name, state, detail))
+	}
+
+	// the ticker re-draws the animated lines
+	if l.progress.animated() {
+		if changed {
+			l.progress.draw()
+		}
+		return
+	}
+
+	// a line is printed when it ends and, every so often, while it runs
+	if !changed && time.Since(l.shownAt) < ProgressInterval {
+		return
+	}
+
+	printOut(l.String() + "\n")
+	l.shownAt = time.Now()
+}
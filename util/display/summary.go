@@ -38,6 +38,11 @@ type (
 		shutdown    bool           // toggle to inform the run loop to exit
 		windowWidth int
 		leftover    string
+
+		// when not Interactive
+		printedHeader bool      // the header is printed once
+		printedDetail string    // the last detail printed
+		printedAt     time.Time // when the detail was printed
 	}
 
 	// Sending events to the summarizer needs to block the caller until
@@ -316,6 +321,11 @@ func (s *Summarizer) error() {
 func (s *Summarizer) reset() {
 	// http://bluesock.org/~willg/dev/ansi.html
 
+	// nothing is re-drawn, the lines stay
+	if !Interactive {
+		return
+	}
+
 	// todo: make this conditional on the progress estimator
 	lines := 2
 
@@ -327,6 +337,10 @@ func (s *Summarizer) reset() {
 
 // print prints the current summary
 func (s *Summarizer) print() {
+	if !Interactive {
+		s.printPlain()
+		return
+	}
 
 	header := fmt.Sprintf("%s%s %s :\n", s.Prefix, TaskSpinner[s.spinIdx], s.Label)
 
@@ -348,3 +362,20 @@ func (s *Summarizer) print() {
 	io.WriteString(s.Out, header)
 	io.WriteString(s.Out, detail)
 }
+
+// printPlain prints the header once and the detail as it changes, at most
+// every ProgressInterval, so logs without a terminal stay readable
+func (s *Summarizer) printPlain() {
+	if !s.printedHeader {
+		io.WriteString(s.Out, fmt.Sprintf("%s%s :\n", s.Prefix, s.Label))
+		s.printedHeader = true
+	}
+
+	if s.detail == "" || s.detail == s.printedDetail || time.Since(s.printedAt) < ProgressInterval {
+		return
+	}
+
+	io.WriteString(s.Out, fmt.Sprintf("%s  %s\n", s.Prefix, s.detail))
+	s.printedDetail = s.detail
+	s.printedAt = time.Now()
+}
@@ -0,0 +1,166 @@
+// Package engine fetches the pinned engines of the boxfile, ie:
+// engine: ruby#v2.4.1, into a cache on the workstation. The copy is mounted
+// into the build like a local engine, so every build of a version uses the
+// exact same engine.
+package engine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+)
+
+// the suffix of the file next to a cached engine that records its signature
+// was verified
+const verifiedSuffix = ".verified"
+
+// Pinned returns true if the engine is pinned to a version, ie: ruby#v2.4.1
+func Pinned(engine string) bool {
+	return config.EngineCacheDir(engine) != ""
+}
+
+// Cached returns true if a pinned engine has been fetched
+func Cached(engine string) bool {
+	_, err := os.Stat(config.EngineCacheDir(engine))
+	return Pinned(engine) && err == nil
+}
+
+// Current returns true if a pinned engine has been fetched and, with the
+// verify config on, its signature checked out
+func Current(engine string) bool {
+	if !Cached(engine) {
+		return false
+	}
+
+	if conf, _ := models.LoadConfig(); !conf.Verify {
+		return true
+	}
+
+	_, err := os.Stat(config.EngineCacheDir(engine) + verifiedSuffix)
+	return err == nil
+}
+
+// Check returns an error if the engine can't be used with the verify config
+// on: an engine that isn't pinned is fetched by the build, where its
+// signature can't be checked
+func Check(engine string) error {
+	if conf, _ := models.LoadConfig(); !conf.Verify || engine == "" || Pinned(engine) {
+		return nil
+	}
+
+	return util.Errorf("[USER] verify only allows pinned engines, pin %s to a signed tag, ie: engine: %s#v1.0.0", engine, engine)
+}
+
+// Repository returns the git repository of an engine, the engines of the
+// registry are the nanobox-io/nanobox-engine-* repositories
+func Repository(engine string) string {
+	name := strings.SplitN(engine, "#", 2)[0]
+
+	if strings.Contains(name, "://") || strings.HasPrefix(name, "git@") {
+		return name
+	}
+
+	// github.com/user/repo, or user/repo on github
+	if strings.Contains(name, "/") {
+		if !strings.Contains(strings.Split(name, "/")[0], ".") {
+			name = "github.com/" + name
+		}
+		return fmt.Sprintf("https://%s.git", strings.TrimSuffix(name, ".git"))
+	}
+
+	return fmt.Sprintf("https://github.com/nanobox-io/nanobox-engine-%s.git", name)
+}
+
+// Fetch fetches the version of a pinned engine into the cache, unless it is
+// already there. The ref can be a tag, a branch or a commit. With the verify
+// config on, the signature of the tag or the commit has to check out, and a
+// copy fetched without checking it is fetched again.
+func Fetch(engine string) error {
+	if !Pinned(engine) || Current(engine) {
+		return nil
+	}
+
+	conf, _ := models.LoadConfig()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return util.Errorf("[USER] git is needed to fetch the engine %s", engine)
+	}
+
+	ref := strings.SplitN(engine, "#", 2)[1]
+	dir := config.EngineCacheDir(engine)
+	tmp := dir + ".tmp"
+
+	os.RemoveAll(tmp)
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return util.ErrorAppend(err, "failed to create the engine cache")
+	}
+
+	// a tag or a branch is cloned on its own, a commit needs the history
+	if out, err := exec.Command("git", "clone", "--quiet", "--depth", "1", "--branch", ref, Repository(engine), tmp).CombinedOutput(); err != nil {
+		lumber.Debug("engine:Fetch:git clone --branch %s: %s", ref, out)
+
+		os.RemoveAll(tmp)
+		if out, err := exec.Command("git", "clone", "--quiet", Repository(engine), tmp).CombinedOutput(); err != nil {
+			lumber.Error("engine:Fetch:git clone %s: %s", Repository(engine), out)
+			return util.Errorf("[USER] failed to fetch the engine %s - %s", engine, strings.TrimSpace(string(out)))
+		}
+
+		if out, err := exec.Command("git", "-C", tmp, "checkout", "--quiet", ref).CombinedOutput(); err != nil {
+			os.RemoveAll(tmp)
+			lumber.Error("engine:Fetch:git checkout %s: %s", ref, out)
+			return util.Errorf("[USER] the engine %s has no version %s", strings.SplitN(engine, "#", 2)[0], ref)
+		}
+	}
+
+	if conf.Verify {
+		if err := verify(tmp, ref); err != nil {
+			os.RemoveAll(tmp)
+			return util.Errorf("[USER] the engine %s isn't signed by a key gpg trusts - %s", engine, err.Error())
+		}
+	}
+
+	// the history isn't part of the engine
+	os.RemoveAll(filepath.Join(tmp, ".git"))
+
+	// a copy that's there wasn't verified
+	os.RemoveAll(dir)
+	os.Remove(dir + verifiedSuffix)
+
+	if err := os.Rename(tmp, dir); err != nil {
+		os.RemoveAll(tmp)
+		return util.ErrorAppend(err, "failed to cache the engine")
+	}
+
+	if conf.Verify {
+		if err := ioutil.WriteFile(dir+verifiedSuffix, []byte(ref), 0644); err != nil {
+			return util.ErrorAppend(err, "failed to record the engine was verified")
+		}
+	}
+
+	return nil
+}
+
+// verify checks the signature of the tag the engine was fetched at or, for a
+// branch or a commit, of the commit
+func verify(dir, ref string) error {
+	cmd := exec.Command("git", "-C", dir, "verify-commit", "HEAD")
+	if exec.Command("git", "-C", dir, "rev-parse", "--verify", "--quiet", "refs/tags/"+ref).Run() == nil {
+		cmd = exec.Command("git", "-C", dir, "verify-tag", ref)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		lumber.Error("engine:verify:%s: %s", strings.Join(cmd.Args, " "), out)
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+// Package events tells editors, chat bots or desktop notifiers what nanobox
+// is doing. The lifecycle events, ie: a build finished or a component came
+// up, are sent to the webhooks of the config, urls or unix sockets.
+package events
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+)
+
+// the types of events
+const (
+	BuildStarted   = "build.started"
+	BuildFinished  = "build.finished"
+	BuildFailed    = "build.failed"
+	ServiceUp      = "service.up"
+	ServiceDown    = "service.down"
+	AppUp          = "app.up"
+	AppDown        = "app.down"
+	DeployComplete = "deploy.complete"
+	DeployFailed   = "deploy.failed"
+	Test           = "test"
+)
+
+// timeout is how long a webhook has to take an event, a command waits for
+// its events to be delivered
+const timeout = 3 * time.Second
+
+// Event is something that happened
+type Event struct {
+	Type      string    `json:"type"`
+	Time      time.Time `json:"time"`
+	App       string    `json:"app,omitempty"`       // the name of the app
+	Env       string    `json:"env,omitempty"`       // local, dry-run or a remote
+	Component string    `json:"component,omitempty"` // ie: data.db
+	Error     string    `json:"error,omitempty"`
+	Text      string    `json:"text"` // a sentence about it, for chat webhooks
+}
+
+// New returns an event of an environment of an app
+func New(kind string, appModel *models.App) Event {
+	event := Event{Type: kind}
+
+	if appModel != nil {
+		event.Env = appModel.DisplayName()
+		if envModel, err := appModel.Env(); err == nil {
+			event.App = envModel.Name
+		}
+	}
+
+	return event
+}
+
+// Emit sends an event to the webhooks of the config that want it
+func Emit(event Event) {
+	conf, _ := models.LoadConfig()
+
+	targets := Targets(conf.Webhooks)
+	if len(targets) == 0 || !Wanted(conf.WebhookEvents, event.Type) {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		wg.Add(1)
+		go func(target string) {
+			defer wg.Done()
+
+			if err := Send(target, conf.WebhookSecret, event); err != nil {
+				lumber.Error("events:Emit:Send(%s, %s): %s", target, event.Type, err.Error())
+			}
+		}(target)
+	}
+	wg.Wait()
+}
+
+// Send sends an event to a webhook: posted as json to an http(s) url, or a
+// line of json written to a unix socket, ie: unix:///tmp/nanobox.sock. A
+// post is signed with the secret, if there is one.
+func Send(target, secret string, event Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Text == "" {
+		event.Text = Describe(event)
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasPrefix(target, "unix:") {
+		return sendSocket(strings.TrimPrefix(strings.TrimPrefix(target, "unix:"), "//"), body)
+	}
+
+	return post(target, secret, event.Type, body)
+}
+
+// post posts an event to a url
+func post(url, secret, kind string, body []byte) error {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Nanobox-Event", kind)
+	if secret != "" {
+		req.Header.Set("X-Nanobox-Signature", "sha256="+Sign(secret, body))
+	}
+
+	client := &http.Client{Timeout: timeout}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("the webhook responded with %s", res.Status)
+	}
+
+	return nil
+}
+
+// sendSocket writes an event to a unix socket
+func sendSocket(path string, body []byte) error {
+	conn, err := net.DialTimeout("unix", path, timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(timeout))
+	_, err = conn.Write(append(body, '\n'))
+
+	return err
+}
+
+// Sign returns the hex hmac-sha256 of a body, so a webhook can check an
+// event came from nanobox
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Targets returns the webhooks of the config, separated by commas
+func Targets(value string) []string {
+	targets := []string{}
+
+	for _, target := range strings.Split(value, ",") {
+		if target = strings.TrimSpace(target); target != "" {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+// Wanted returns true if the webhook-events config includes a type of
+// event. It lists the types or their prefixes, ie: build,deploy.complete,
+// every event is wanted without it.
+func Wanted(value, kind string) bool {
+	wanted := Targets(value)
+	if len(wanted) == 0 || kind == Test {
+		return true
+	}
+
+	for _, want := range wanted {
+		if kind == want || strings.HasPrefix(kind, strings.TrimSuffix(want, ".*")+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Describe returns a sentence about an event, ie: "shop (local): the build
+// failed: exit status 1"
+func Describe(event Event) string {
+	subject := "nanobox"
+	if event.App != "" {
+		subject = event.App
+		if event.Env != "" {
+			subject = fmt.Sprintf("%s (%s)", event.App, event.Env)
+		}
+	}
+
+	what := map[string]string{
+		BuildStarted:   "the build started",
+		BuildFinished:  "the build finished",
+		BuildFailed:    "the build failed",
+		ServiceUp:      event.Component + " is up",
+		ServiceDown:    event.Component + " is down",
+		AppUp:          "the app is up",
+		AppDown:        "the app is down",
+		DeployComplete: "the deploy is complete",
+		DeployFailed:   "the deploy failed",
+		Test:           "this is a test event",
+	}[event.Type]
+	if what == "" {
+		what = event.Type
+	}
+
+	if event.Error != "" {
+		what = fmt.Sprintf("%s: %s", what, event.Error)
+	}
+
+	return fmt.Sprintf("%s: %s", subject, what)
+}
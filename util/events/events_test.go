@@ -0,0 +1,95 @@
+package events_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/events"
+)
+
+func TestSend(t *testing.T) {
+	event := events.Event{Type: events.BuildFailed, App: "shop", Env: "local", Error: "exit status 1"}
+
+	var got events.Event
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		signature = r.Header.Get("X-Nanobox-Signature")
+		if signature != "sha256="+events.Sign("s3cret", body) {
+			t.Errorf("unexpected signature %s", signature)
+		}
+		json.Unmarshal(body, &got)
+	}))
+	defer server.Close()
+
+	if err := events.Send(server.URL, "s3cret", event); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Type != events.BuildFailed || got.Text != "shop (local): the build failed: exit status 1" || got.Time.IsZero() {
+		t.Errorf("unexpected event %+v", got)
+	}
+
+	// a unix socket gets a line of json
+	dir, err := ioutil.TempDir("", "nanobox-events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "events.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	lines := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		lines <- line
+	}()
+
+	if err := events.Send("unix://"+socket, "", events.Event{Type: events.ServiceUp, Component: "data.db"}); err != nil {
+		t.Fatal(err)
+	}
+
+	json.Unmarshal([]byte(<-lines), &got)
+	if got.Type != events.ServiceUp || got.Text != "nanobox: data.db is up" {
+		t.Errorf("unexpected event %+v", got)
+	}
+}
+
+func TestWanted(t *testing.T) {
+	tests := []struct {
+		config, kind string
+		wanted       bool
+	}{
+		{"", events.ServiceDown, true},
+		{"build,deploy.complete", events.BuildFailed, true},
+		{"build,deploy.complete", events.DeployComplete, true},
+		{"build,deploy.complete", events.DeployFailed, false},
+		{"service.*", events.ServiceUp, true},
+		{"service.*", events.AppUp, false},
+		{"app", events.Test, true},
+	}
+
+	for _, test := range tests {
+		if got := events.Wanted(test.config, test.kind); got != test.wanted {
+			t.Errorf("expected %s wanted by '%s' to be %t", test.kind, test.config, test.wanted)
+		}
+	}
+}
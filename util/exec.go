@@ -15,6 +15,7 @@ type Cmd struct {
 	User   string
 	Path   string
 	Args   []string
+	Env    []string
 	Stdout io.Writer
 	Stderr io.Writer
 }
@@ -32,6 +33,7 @@ func (cmd *Cmd) Run() error {
 		ID:     cmd.ID,
 		User:   cmd.User,
 		Cmd:    run,
+		Env:    cmd.Env,
 		Stdin:  false,
 		Stdout: true,
 		Stderr: true,
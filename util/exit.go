@@ -0,0 +1,80 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The exit codes of nanobox. Every command ends with one of them, so scripts
+// and CI can tell the failures apart without reading the output.
+const (
+	ExitOK       = 0
+	ExitInternal = 1   // anything unexpected, a bug worth reporting
+	ExitUser     = 2   // the command, its arguments or the app's config are wrong
+	ExitAuth     = 3   // not logged in, or the credentials were rejected
+	ExitProvider = 4   // the VM or docker isn't available
+	ExitBuild    = 5   // the build or one of the app's hooks failed
+	ExitCanceled = 130 // the user canceled, ie: ctrl + c
+)
+
+// the exit codes of the error classes. The class of an error is the tag its
+// message starts with, ie: "[AUTH] not logged in", or its Code.
+var exitCodes = map[string]int{
+	"USER":     ExitUser,
+	"AUTH":     ExitAuth,
+	"PROVIDER": ExitProvider,
+	"BUILD":    ExitBuild,
+	"HOOKS":    ExitBuild,
+	"CANCELED": ExitCanceled,
+}
+
+// matches the tag at the start of the cause, ie: "[USER] "
+var classRegex = regexp.MustCompile(`\[([A-Z0-9]+)\] `)
+
+// ErrorClass returns the class of an error, empty if it has none
+func ErrorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	message := err.Error()
+	if er, ok := err.(Err); ok {
+		if er.Code != "" {
+			return codeClass(er.Code)
+		}
+		message = er.Message
+	}
+
+	if match := classRegex.FindStringSubmatch(message); len(match) > 1 {
+		return match[1]
+	}
+
+	return ""
+}
+
+// ExitCode returns the code nanobox exits with when a command fails with err
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	if code, ok := exitCodes[ErrorClass(err)]; ok {
+		return code
+	}
+
+	return ExitInternal
+}
+
+// codeClass returns the class of an error code. The numbered codes are
+// grouped by who is responsible, 1xxx is the user and 2xxx the hooks.
+// Codes like USER/DOCKER are of the first class.
+func codeClass(code string) string {
+	switch code[0] {
+	case '1':
+		return "USER"
+	case '2':
+		return "HOOKS"
+	}
+
+	return strings.SplitN(code, "/", 2)[0]
+}
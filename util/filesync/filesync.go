@@ -0,0 +1,206 @@
+// Package filesync tracks the state of a directory between syncs so only the
+// files that changed have to be transferred
+package filesync
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"github.com/nanobox-io/nanobox/util/hostpath"
+)
+
+// the os of the host, the files sent from windows are fixed up for linux
+var hostOS = runtime.GOOS
+
+// FileState is what we compare to tell if a file has changed
+type FileState struct {
+	Size    int64       `json:"size"`
+	ModTime int64       `json:"mod_time"`
+	Mode    os.FileMode `json:"mode"`
+}
+
+// Manifest maps the slash separated path of each file, relative to the
+// synced directory, to its state
+type Manifest map[string]FileState
+
+// Scan builds the manifest of dir. Directories aren't tracked, they are
+// created as needed when their files are transferred.
+func Scan(dir string) (Manifest, error) {
+	return ScanIgnoring(dir, nil)
+}
+
+// ScanIgnoring builds the manifest of dir like Scan, leaving out what ignore
+// matches. Ignored directories aren't walked at all.
+func ScanIgnoring(dir string, ignore *Ignore) (Manifest, error) {
+	manifest := Manifest{}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if info.IsDir() {
+			if rel != "." && ignore.match(rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if ignore.match(rel, false) {
+			return nil
+		}
+
+		manifest[rel] = FileState{
+			Size:    info.Size(),
+			ModTime: info.ModTime().UnixNano(),
+			Mode:    info.Mode(),
+		}
+
+		return nil
+	})
+
+	return manifest, err
+}
+
+// Diff returns the files that were added or modified, and the files that
+// were removed, since the old manifest. Both lists are sorted.
+func Diff(old, current Manifest) (changed, removed []string) {
+	for path, state := range current {
+		if prev, ok := old[path]; !ok || prev != state {
+			changed = append(changed, path)
+		}
+	}
+
+	for path := range old {
+		if _, ok := current[path]; !ok {
+			removed = append(removed, path)
+		}
+	}
+
+	sort.Strings(changed)
+	sort.Strings(removed)
+
+	return changed, removed
+}
+
+// Tar writes the files, relative to dir, into a tar archive on w
+func Tar(dir string, files []string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+
+		info, err := os.Lstat(path)
+		if err != nil {
+			// the file was removed since the scan, the next sync removes it
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = file
+
+		// a windows filesystem keeps no execute bit nor unix line ends
+		f, err := hostpath.ForContainer(path, info, hostOS)
+		if err != nil {
+			return err
+		}
+		header.Mode = header.Mode&^0777 | int64(f.Mode.Perm())
+		if f.Data != nil {
+			header.Size = int64(len(f.Data))
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		if f.Data != nil {
+			if _, err := tw.Write(f.Data); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := copyFile(tw, path); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+// copyFile copies the content of the file at path to w
+func copyFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// Load reads a manifest saved with Save. A missing manifest is empty.
+func Load(path string) (Manifest, error) {
+	manifest := Manifest{}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, nil
+		}
+		return manifest, err
+	}
+
+	return manifest, json.Unmarshal(data, &manifest)
+}
+
+// Save writes the manifest to path
+func (m Manifest) Save(path string) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Paths returns the paths in the manifest, sorted
+func (m Manifest) Paths() []string {
+	paths := make([]string, 0, len(m))
+	for path := range m {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
@@ -0,0 +1,150 @@
+package filesync_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/filesync"
+)
+
+func TestDiff(t *testing.T) {
+	old := filesync.Manifest{
+		"same.txt":    {Size: 1, ModTime: 1},
+		"changed.txt": {Size: 1, ModTime: 1},
+		"removed.txt": {Size: 1, ModTime: 1},
+	}
+	current := filesync.Manifest{
+		"same.txt":    {Size: 1, ModTime: 1},
+		"changed.txt": {Size: 1, ModTime: 2},
+		"added.txt":   {Size: 1, ModTime: 1},
+	}
+
+	changed, removed := filesync.Diff(old, current)
+
+	if !reflect.DeepEqual(changed, []string{"added.txt", "changed.txt"}) {
+		t.Errorf("unexpected changed files %v", changed)
+	}
+
+	if !reflect.DeepEqual(removed, []string{"removed.txt"}) {
+		t.Errorf("unexpected removed files %v", removed)
+	}
+}
+
+func TestScanAndTar(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nanobox-filesync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	os.MkdirAll(filepath.Join(dir, "lib"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "lib", "lib.go"), []byte("package lib"), 0644)
+
+	manifest, err := filesync.Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(manifest) != 2 || manifest["lib/lib.go"].Size != 11 {
+		t.Errorf("unexpected manifest %+v", manifest)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := filesync.Tar(dir, []string{"lib/lib.go", "missing.go"}, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	tr := tar.NewReader(buf)
+	header, err := tr.Next()
+	if err != nil || header.Name != "lib/lib.go" {
+		t.Fatalf("expected lib/lib.go in the archive, got %+v: %v", header, err)
+	}
+
+	if _, err := tr.Next(); err != io.EOF {
+		t.Errorf("expected only one file in the archive")
+	}
+}
+
+func TestIgnore(t *testing.T) {
+	ignore := &filesync.Ignore{}
+	for _, line := range []string{"# comment", "node_modules/", "*.log", "!keep.log", "/build", "docs/**/*.tmp", ""} {
+		ignore.Add(line)
+	}
+
+	tests := map[string]bool{
+		"node_modules/a/index.js": true,
+		"lib/node_modules/b.js":   true,
+		"app.log":                 true,
+		"logs/keep.log":           false,
+		"build/out.js":            true,
+		"src/build/out.js":        false,
+		"docs/a/b/c.tmp":          true,
+		"docs/c.tmp":              true,
+		"main.go":                 false,
+	}
+
+	for path, want := range tests {
+		if got := ignore.Match(path, false); got != want {
+			t.Errorf("Match(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	base := filesync.Base{
+		Host: filesync.Manifest{
+			"same.txt":      {Size: 1, ModTime: 1e9},
+			"host.txt":      {Size: 1, ModTime: 1e9},
+			"container.txt": {Size: 1, ModTime: 1e9},
+			"both.txt":      {Size: 1, ModTime: 1e9},
+			"gone.txt":      {Size: 1, ModTime: 1e9},
+		},
+		Container: filesync.Manifest{
+			"same.txt":      {Size: 1, ModTime: 5e9},
+			"host.txt":      {Size: 1, ModTime: 5e9},
+			"container.txt": {Size: 1, ModTime: 5e9},
+			"both.txt":      {Size: 1, ModTime: 5e9},
+			"gone.txt":      {Size: 1, ModTime: 5e9},
+		},
+	}
+	host := filesync.Manifest{
+		"same.txt":      {Size: 1, ModTime: 1e9},
+		"host.txt":      {Size: 2, ModTime: 2e9},
+		"container.txt": {Size: 1, ModTime: 1e9},
+		"both.txt":      {Size: 2, ModTime: 2e9},
+		"new.txt":       {Size: 1, ModTime: 2e9},
+	}
+	container := filesync.Manifest{
+		"same.txt":      {Size: 1, ModTime: 5e9},
+		"host.txt":      {Size: 1, ModTime: 5e9},
+		"container.txt": {Size: 3, ModTime: 6e9},
+		"both.txt":      {Size: 3, ModTime: 6e9},
+		"gone.txt":      {Size: 1, ModTime: 5e9},
+		"cache.bin":     {Size: 1, ModTime: 6e9},
+	}
+
+	plan := filesync.Reconcile(base, host, container)
+	want := filesync.Plan{
+		Push:            []string{"both.txt", "host.txt", "new.txt"},
+		Pull:            []string{"cache.bin", "container.txt"},
+		RemoveContainer: []string{"gone.txt"},
+		Conflicts:       []string{"both.txt"},
+	}
+
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("unexpected plan %+v, want %+v", plan, want)
+	}
+
+	// the first sync takes the differing files from the host
+	plan = filesync.Reconcile(filesync.Base{}, host, container)
+	if !reflect.DeepEqual(plan.Push, []string{"both.txt", "container.txt", "host.txt", "new.txt", "same.txt"}) || len(plan.Conflicts) != 0 {
+		t.Errorf("unexpected first plan %+v", plan)
+	}
+}
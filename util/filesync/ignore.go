@@ -0,0 +1,181 @@
+package filesync
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Ignore matches slash separated paths against gitignore style patterns. A
+// nil Ignore matches nothing.
+type Ignore struct {
+	rules []ignoreRule
+}
+
+// ignoreRule is a single line of an ignore file
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// LoadIgnore reads the rules of the ignore files found in dir, ie:
+// .gitignore and .nanoboxignore. Missing files are skipped.
+func LoadIgnore(dir string, files ...string) (*Ignore, error) {
+	ignore := &Ignore{}
+
+	for _, file := range files {
+		data, err := ioutil.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(strings.NewReader(string(data)))
+		for scanner.Scan() {
+			ignore.Add(scanner.Text())
+		}
+	}
+
+	return ignore, nil
+}
+
+// Add adds a pattern, as it's written in a .gitignore. Blank lines and
+// comments are skipped.
+func (ig *Ignore) Add(line string) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+
+	rule := ignoreRule{}
+
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	// a slash anywhere but the end ties the pattern to the root
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return
+	}
+
+	expr := globExpr(line)
+	if !anchored {
+		expr = "(.*/)?" + expr
+	}
+
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return
+	}
+	rule.re = re
+
+	ig.rules = append(ig.rules, rule)
+}
+
+// Match returns true if the path, or one of the directories it's in, is
+// ignored
+func (ig *Ignore) Match(path string, isDir bool) bool {
+	if ig == nil || len(ig.rules) == 0 {
+		return false
+	}
+
+	// like git, a file can't be brought back if its directory is ignored
+	parts := strings.Split(path, "/")
+	for i := 1; i < len(parts); i++ {
+		if ig.match(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+
+	return ig.match(path, isDir)
+}
+
+// match applies the rules to the path, the last matching rule wins
+func (ig *Ignore) match(path string, isDir bool) bool {
+	if ig == nil {
+		return false
+	}
+
+	ignored := false
+
+	for _, rule := range ig.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.re.MatchString(path) {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+// globExpr converts a gitignore glob into a regular expression
+func globExpr(glob string) string {
+	expr := ""
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			expr += "(.*/)?"
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			expr += "/.+"
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			expr += ".*"
+			i++
+		case c == '*':
+			expr += "[^/]*"
+		case c == '?':
+			expr += "[^/]"
+		case c == '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				expr += regexp.QuoteMeta(string(c))
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr += "[" + class + "]"
+			i += end
+		case c == '\\' && i+1 < len(glob):
+			i++
+			expr += regexp.QuoteMeta(string(glob[i]))
+		default:
+			expr += regexp.QuoteMeta(string(c))
+		}
+	}
+
+	return expr
+}
+
+// Filter returns the part of the manifest that isn't ignored
+func (m Manifest) Filter(ignore *Ignore) Manifest {
+	filtered := Manifest{}
+
+	for path, state := range m {
+		if !ignore.Match(path, false) {
+			filtered[path] = state
+		}
+	}
+
+	return filtered
+}
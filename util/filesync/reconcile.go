@@ -0,0 +1,152 @@
+package filesync
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+// Base is the state of both sides of a two-way sync, as they were after the
+// last sync. Each side is compared to its own state, the mod times of a
+// file copied between them don't have to match.
+type Base struct {
+	Host      Manifest `json:"host"`
+	Container Manifest `json:"container"`
+}
+
+// Plan is what a two-way sync has to do to bring both sides together. Every
+// list is sorted.
+type Plan struct {
+	// copied from the host to the container
+	Push []string
+	// copied from the container to the host
+	Pull []string
+	// removed from the host, they were removed in the container
+	RemoveHost []string
+	// removed from the container, they were removed on the host
+	RemoveContainer []string
+	// changed on both sides. The host's copy wins, so they are in Push too.
+	Conflicts []string
+}
+
+// Empty returns true if there's nothing to sync
+func (p Plan) Empty() bool {
+	return len(p.Push)+len(p.Pull)+len(p.RemoveHost)+len(p.RemoveContainer) == 0
+}
+
+// Reconcile compares both sides to the base and plans the sync. Without a
+// base, ie: the first sync, a file that differs is taken from the host and
+// the files only found on one side are copied to the other.
+func Reconcile(base Base, host, container Manifest) Plan {
+	plan := Plan{}
+	initial := len(base.Host) == 0 && len(base.Container) == 0
+
+	paths := map[string]struct{}{}
+	for _, m := range []Manifest{base.Host, base.Container, host, container} {
+		for path := range m {
+			paths[path] = struct{}{}
+		}
+	}
+
+	for path := range paths {
+		hostState, onHost := host[path]
+		containerState, inContainer := container[path]
+
+		// nothing to do if both sides have the same file
+		if onHost && inContainer && Same(hostState, containerState) {
+			continue
+		}
+
+		if initial {
+			switch {
+			case onHost:
+				plan.Push = append(plan.Push, path)
+			case inContainer:
+				plan.Pull = append(plan.Pull, path)
+			}
+			continue
+		}
+
+		hostChanged := changed(base.Host, host, path)
+		containerChanged := changed(base.Container, container, path)
+
+		switch {
+		case hostChanged && containerChanged:
+			switch {
+			case onHost && inContainer:
+				plan.Push = append(plan.Push, path)
+				plan.Conflicts = append(plan.Conflicts, path)
+			// a change wins over a removal, so nothing is lost
+			case onHost:
+				plan.Push = append(plan.Push, path)
+			case inContainer:
+				plan.Pull = append(plan.Pull, path)
+			}
+
+		case hostChanged:
+			if onHost {
+				plan.Push = append(plan.Push, path)
+			} else if inContainer {
+				plan.RemoveContainer = append(plan.RemoveContainer, path)
+			}
+
+		case containerChanged:
+			if inContainer {
+				plan.Pull = append(plan.Pull, path)
+			} else if onHost {
+				plan.RemoveHost = append(plan.RemoveHost, path)
+			}
+		}
+	}
+
+	for _, list := range [][]string{plan.Push, plan.Pull, plan.RemoveHost, plan.RemoveContainer, plan.Conflicts} {
+		sort.Strings(list)
+	}
+
+	return plan
+}
+
+// changed returns true if the file was added, modified or removed on a side
+// since the base
+func changed(base, current Manifest, path string) bool {
+	old, had := base[path]
+	state, has := current[path]
+
+	if had != has {
+		return true
+	}
+
+	return has && !Same(old, state)
+}
+
+// Same returns true if both states are of the same file. The mod times are
+// compared to the second, a copy doesn't always keep the rest.
+func Same(a, b FileState) bool {
+	return a.Size == b.Size && a.ModTime/1e9 == b.ModTime/1e9
+}
+
+// LoadBase reads a base saved with Save. A missing base is empty.
+func LoadBase(path string) (Base, error) {
+	base := Base{Host: Manifest{}, Container: Manifest{}}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return base, nil
+		}
+		return base, err
+	}
+
+	return base, json.Unmarshal(data, &base)
+}
+
+// Save writes the base to path
+func (b Base) Save(path string) error {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
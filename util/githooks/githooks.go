@@ -0,0 +1,114 @@
+// Package githooks installs the git hooks that tell nanobox when a checkout
+// or a merge changed the boxfile or the dependency manifests. A hook that was
+// there before is kept and runs first.
+package githooks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Hooks are the hooks nanobox installs
+var Hooks = []string{"post-checkout", "post-merge"}
+
+// the line that marks a hook as nanobox's
+const marker = "# installed by 'nanobox githooks install'"
+
+// the suffix of a hook that was there before nanobox's
+const keptSuffix = ".pre-nanobox"
+
+// Script returns the hook script. Git runs the hooks in the root of the repo,
+// the script moves to the app, which is at the relative path app. Git runs
+// the hooks without a stdin either, so the prompt reads the terminal when
+// there is one. With auto the changes are handled without asking.
+func Script(hook, app string, auto bool) string {
+	run := "nanobox githooks run " + hook
+	if auto {
+		run += " --auto"
+	}
+
+	if app == "" {
+		app = "."
+	}
+
+	return fmt.Sprintf(`#!/bin/sh
+%s
+if [ -x "$0%s" ]; then
+  "$0%s" "$@" || exit $?
+fi
+
+command -v nanobox >/dev/null 2>&1 || exit 0
+cd '%s' || exit 0
+
+if [ -t 1 ] && (: </dev/tty) 2>/dev/null; then
+  %s -- "$@" </dev/tty
+else
+  %s -- "$@"
+fi
+
+# the checkout or the merge is done, a failure here mustn't look like theirs
+exit 0
+`, marker, keptSuffix, keptSuffix, app, run, run)
+}
+
+// Installed returns true if the hook at path is nanobox's
+func Installed(path string) bool {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(string(data), marker)
+}
+
+// Install writes the hooks of the app into dir. A hook that isn't nanobox's
+// is kept next to it and runs first. Reinstalling replaces nanobox's hooks.
+func Install(dir, app string, auto bool) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, hook := range Hooks {
+		path := filepath.Join(dir, hook)
+
+		if _, err := os.Stat(path); err == nil && !Installed(path) {
+			if _, err := os.Stat(path + keptSuffix); err == nil {
+				return fmt.Errorf("both %s and %s exist, merge them first", path, path+keptSuffix)
+			}
+			if err := os.Rename(path, path+keptSuffix); err != nil {
+				return err
+			}
+		}
+
+		if err := ioutil.WriteFile(path, []byte(Script(hook, app, auto)), 0755); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Uninstall removes nanobox's hooks from dir and puts the kept ones back
+func Uninstall(dir string) error {
+	for _, hook := range Hooks {
+		path := filepath.Join(dir, hook)
+		if !Installed(path) {
+			continue
+		}
+
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(path + keptSuffix); err == nil {
+			if err := os.Rename(path+keptSuffix, path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
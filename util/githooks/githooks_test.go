@@ -0,0 +1,56 @@
+package githooks_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/githooks"
+)
+
+func TestInstall(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nanobox-githooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mine := "#!/bin/sh\necho mine\n"
+	ioutil.WriteFile(filepath.Join(dir, "post-merge"), []byte(mine), 0755)
+
+	// installing twice keeps the user's hook once
+	for i := 0; i < 2; i++ {
+		if err := githooks.Install(dir, "api/", true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, hook := range githooks.Hooks {
+		path := filepath.Join(dir, hook)
+		if !githooks.Installed(path) {
+			t.Errorf("%s isn't installed", hook)
+		}
+
+		data, _ := ioutil.ReadFile(path)
+		if !strings.Contains(string(data), "nanobox githooks run "+hook+" --auto") || !strings.Contains(string(data), "cd 'api/'") {
+			t.Errorf("%s doesn't run nanobox: '%s'", hook, data)
+		}
+	}
+
+	if data, _ := ioutil.ReadFile(filepath.Join(dir, "post-merge.pre-nanobox")); string(data) != mine {
+		t.Errorf("the user's hook wasn't kept: '%s'", data)
+	}
+
+	if err := githooks.Uninstall(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := ioutil.ReadFile(filepath.Join(dir, "post-merge")); string(data) != mine {
+		t.Errorf("the user's hook wasn't restored: '%s'", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "post-checkout")); !os.IsNotExist(err) {
+		t.Errorf("post-checkout wasn't removed")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/console"
 	"github.com/nanobox-io/nanobox/util/display"
+	"github.com/nanobox-io/nanobox/util/logs"
 )
 
 // since `--debug` runs the command twice, combined prevents duplication of the error output.
@@ -32,6 +33,11 @@ func Exec(container, hook, payload, displayLevel string) (string, error) {
 	}
 
 	outs := stream.Output()
+
+	// keep the output for 'nanobox logs', unless it's data the caller parses
+	if hook != "boxfile" && hook != "keys" && hook != "plan" {
+		logs.WriteHook(container, hook, outs)
+	}
 	// these hooks depend on the output, we shouldn't append anything.
 	if hook != "boxfile" && hook != "keys" {
 		if out == "" {
@@ -0,0 +1,140 @@
+// Package hostmount parses the host directories the boxfile mounts into the
+// containers of an app, ie: a gem under development mounted into the dev
+// container:
+//
+//	run.config:
+//	  mounts:
+//	    - ../my-gem:/opt/gems/my-gem
+//
+// A mount is host:container, with :ro at the end for a read-only one. A
+// relative host path is relative to the app directory.
+package hostmount
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/nanobox-io/nanobox/util/hostpath"
+)
+
+// Mount is a host directory mounted into a container
+type Mount struct {
+	Host      string // the absolute path on the host
+	Container string
+	ReadOnly  bool
+}
+
+// Parse parses a mount, a relative host path is made relative to dir
+func Parse(spec, dir string) (Mount, error) {
+	m := Mount{}
+
+	spec = strings.TrimSpace(spec)
+	switch {
+	case strings.HasSuffix(spec, ":ro"):
+		m.ReadOnly = true
+		spec = strings.TrimSuffix(spec, ":ro")
+	case strings.HasSuffix(spec, ":rw"):
+		spec = strings.TrimSuffix(spec, ":rw")
+	}
+
+	// the container path comes after the last ':', a windows host path has
+	// one after its drive
+	i := strings.LastIndex(spec, ":")
+	if i <= 0 || i == len(spec)-1 {
+		return m, fmt.Errorf("'%s' isn't host:container", spec)
+	}
+
+	m.Host, m.Container = spec[:i], spec[i+1:]
+
+	if !strings.HasPrefix(m.Container, "/") {
+		return m, fmt.Errorf("the container path of '%s' has to be absolute", spec)
+	}
+	m.Container = path.Clean(m.Container)
+
+	if !isAbs(m.Host) {
+		m.Host = filepath.Join(dir, m.Host)
+	}
+	m.Host = filepath.Clean(m.Host)
+
+	return m, nil
+}
+
+// ParseAll parses the mounts of a node of the boxfile
+func ParseAll(specs []string, dir string) ([]Mount, error) {
+	mounts := []Mount{}
+
+	for _, spec := range specs {
+		m, err := Parse(spec, dir)
+		if err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, m)
+	}
+
+	return mounts, nil
+}
+
+// Resolve returns the mounts of a node with their absolute host paths, the
+// way a component records them. A mount that doesn't parse is left out, the
+// boxfile validation reports it.
+func Resolve(specs []string, dir string) []string {
+	resolved := []string{}
+
+	for _, spec := range specs {
+		if m, err := Parse(spec, dir); err == nil {
+			resolved = append(resolved, m.String())
+		}
+	}
+
+	return resolved
+}
+
+// String returns the mount with its absolute host path, the way a component
+// records it
+func (m Mount) String() string {
+	return m.Bind(m.Host)
+}
+
+// Bind returns the docker bind of the mount, from source rather than the
+// host directory when it's shared into a vm
+func (m Mount) Bind(source string) string {
+	bind := source + ":" + m.Container
+	if m.ReadOnly {
+		bind += ":ro"
+	}
+
+	return bind
+}
+
+// ShareName returns a name for the share of the host directory, unique to
+// the directory, ie: my-gem-1a2b3c4d
+func (m Mount) ShareName() string {
+	sum := sha1.Sum([]byte(m.Host))
+
+	base := m.Host
+	if i := strings.LastIndexAny(base, `/\`); i >= 0 {
+		base = base[i+1:]
+	}
+
+	return fmt.Sprintf("%s-%s", base, hex.EncodeToString(sum[:4]))
+}
+
+// DockerPath returns the host path the way docker takes it in a bind on the
+// os, a windows path, ie: C:\Users\me\gem, is /c/Users/me/gem
+func DockerPath(host, goos string) string {
+	return hostpath.Docker(host, goos)
+}
+
+// isAbs returns true if the path is absolute, a windows path included
+// whatever the os
+func isAbs(p string) bool {
+	if filepath.IsAbs(p) || strings.HasPrefix(p, "/") || strings.HasPrefix(p, `\\`) {
+		return true
+	}
+
+	return len(p) >= 3 && p[1] == ':' && (p[2] == '\\' || p[2] == '/')
+}
@@ -0,0 +1,80 @@
+package hostmount_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/hostmount"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec string
+		want hostmount.Mount
+	}{
+		{"/src/gem:/opt/gem", hostmount.Mount{Host: "/src/gem", Container: "/opt/gem"}},
+		{"../gem:/opt/gem/", hostmount.Mount{Host: filepath.Join("/apps", "gem"), Container: "/opt/gem"}},
+		{"gem:/opt/gem:ro", hostmount.Mount{Host: filepath.Join("/apps/shop", "gem"), Container: "/opt/gem", ReadOnly: true}},
+		{"/src/gem:/opt/gem:rw", hostmount.Mount{Host: "/src/gem", Container: "/opt/gem"}},
+		{`C:\src\gem:/opt/gem`, hostmount.Mount{Host: `C:\src\gem`, Container: "/opt/gem"}},
+	}
+
+	for _, test := range tests {
+		got, err := hostmount.Parse(test.spec, "/apps/shop")
+		if err != nil {
+			t.Errorf("%s: %s", test.spec, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: expected %+v got %+v", test.spec, test.want, got)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{"/src/gem", "/src/gem:", ":/opt/gem", "/src/gem:opt/gem", `C:\src\gem`} {
+		if _, err := hostmount.Parse(spec, "/apps/shop"); err == nil {
+			t.Errorf("%s: expected an error", spec)
+		}
+	}
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	m := hostmount.Mount{Host: `C:\src\gem`, Container: "/opt/gem", ReadOnly: true}
+
+	got, err := hostmount.Parse(m.String(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != m {
+		t.Errorf("expected %+v got %+v", m, got)
+	}
+}
+
+func TestShareName(t *testing.T) {
+	a := hostmount.Mount{Host: "/src/one/gem"}
+	b := hostmount.Mount{Host: "/src/two/gem"}
+
+	if a.ShareName() == b.ShareName() {
+		t.Errorf("two directories share the name %s", a.ShareName())
+	}
+	if name := (hostmount.Mount{Host: `C:\src\gem`}).ShareName(); name[:4] != "gem-" {
+		t.Errorf("expected the name to start with gem- got %s", name)
+	}
+}
+
+func TestDockerPath(t *testing.T) {
+	tests := []struct {
+		host, goos, want string
+	}{
+		{"/src/gem", "linux", "/src/gem"},
+		{`C:\Users\me\gem`, "windows", "/c/Users/me/gem"},
+		{`D:/gem`, "windows", "/d/gem"},
+	}
+
+	for _, test := range tests {
+		if got := hostmount.DockerPath(test.host, test.goos); got != test.want {
+			t.Errorf("%s on %s: expected %s got %s", test.host, test.goos, test.want, got)
+		}
+	}
+}
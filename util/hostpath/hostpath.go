@@ -0,0 +1,141 @@
+// Package hostpath translates the paths and the files of the host into what
+// the linux containers expect, so what works on a mac or linux works on
+// windows too. Drive letters and UNC paths become paths docker binds, files
+// get the permissions a windows filesystem doesn't keep, scripts lose the
+// carriage returns that make their #! line "not found", and names that only
+// differ by case are caught before a case-insensitive filesystem merges them.
+package hostpath
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// the bytes of a file read to tell if it's a script
+const headSize = 512
+
+// Docker returns the host path the way docker takes it in a bind on goos:
+// C:\Users\me\app is /c/Users/me/app and \\server\share\app is
+// //server/share/app. A path on another os is left as it is.
+func Docker(p, goos string) string {
+	if goos != "windows" {
+		return p
+	}
+
+	p = Slash(p, goos)
+	if len(p) >= 2 && p[1] == ':' {
+		p = "/" + strings.ToLower(p[:1]) + p[2:]
+	}
+
+	return p
+}
+
+// Slash returns a path of the host with forward slashes, the way the path
+// of a file is written in a container. filepath.ToSlash only knows the os
+// it was built for.
+func Slash(p, goos string) string {
+	if goos != "windows" {
+		return p
+	}
+
+	return strings.Replace(p, `\`, "/", -1)
+}
+
+// CaseInsensitive returns true if the filesystems of goos don't tell names
+// apart by case by default, windows' and mac's don't
+func CaseInsensitive(goos string) bool {
+	return goos == "windows" || goos == "darwin"
+}
+
+// Collisions returns the paths that only differ by case from another one of
+// the lists, the ones a case-insensitive filesystem holds as one file. Each
+// colliding path is returned once, sorted as found.
+func Collisions(lists ...[]string) []string {
+	byFold := map[string]map[string]bool{}
+	order := []string{}
+
+	for _, list := range lists {
+		for _, p := range list {
+			fold := strings.ToLower(p)
+			if byFold[fold] == nil {
+				byFold[fold] = map[string]bool{}
+			}
+			if !byFold[fold][p] {
+				byFold[fold][p] = true
+				order = append(order, p)
+			}
+		}
+	}
+
+	collisions := []string{}
+	for _, p := range order {
+		if len(byFold[strings.ToLower(p)]) > 1 {
+			collisions = append(collisions, p)
+		}
+	}
+
+	return collisions
+}
+
+// File is a host file as it's written into a container
+type File struct {
+	Mode os.FileMode
+	// the content when it had to change, nil to copy the file as it is
+	Data []byte
+}
+
+// ForContainer returns the mode a host file gets in a container, and the
+// content of a script without its carriage returns. A windows filesystem
+// keeps no execute bit, so from windows a directory and a script are 0755
+// and the rest is 0644. From another os the file is kept as it is.
+func ForContainer(file string, info os.FileInfo, goos string) (File, error) {
+	if goos != "windows" || info.Mode()&os.ModeSymlink != 0 {
+		return File{Mode: info.Mode()}, nil
+	}
+
+	if info.IsDir() {
+		return File{Mode: os.ModeDir | 0755}, nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return File{}, err
+	}
+	defer f.Close()
+
+	head := make([]byte, headSize)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return File{}, err
+	}
+	head = head[:n]
+
+	if !IsScript(head) {
+		return File{Mode: 0644}, nil
+	}
+
+	rest, err := ioutil.ReadAll(f)
+	if err != nil {
+		return File{}, err
+	}
+
+	data := append(head, rest...)
+	if !bytes.Contains(data, []byte("\r\n")) {
+		return File{Mode: 0755}, nil
+	}
+
+	return File{Mode: 0755, Data: StripCR(data)}, nil
+}
+
+// IsScript returns true if the file starts with a #! line
+func IsScript(head []byte) bool {
+	return bytes.HasPrefix(head, []byte("#!"))
+}
+
+// StripCR turns the windows line ends of a script into unix ones
+func StripCR(data []byte) []byte {
+	return bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)
+}
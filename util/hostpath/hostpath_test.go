@@ -0,0 +1,87 @@
+package hostpath_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/hostpath"
+)
+
+func TestDocker(t *testing.T) {
+	tests := []struct {
+		p, goos, want string
+	}{
+		{"/Users/me/app", "darwin", "/Users/me/app"},
+		{`C:\Users\me\app`, "windows", "/c/Users/me/app"},
+		{`d:/app`, "windows", "/d/app"},
+		{`\\server\share\app`, "windows", "//server/share/app"},
+	}
+
+	for _, test := range tests {
+		if got := hostpath.Docker(test.p, test.goos); got != test.want {
+			t.Errorf("%s on %s: expected %s got %s", test.p, test.goos, test.want, got)
+		}
+	}
+}
+
+func TestCollisions(t *testing.T) {
+	got := hostpath.Collisions([]string{"app/Readme.md", "app/main.go"}, []string{"app/README.md", "app/main.go", "lib/a"})
+	want := []string{"app/Readme.md", "app/README.md"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v got %v", want, got)
+	}
+}
+
+func TestForContainer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hostpath")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"start.sh":   "#!/bin/sh\r\necho hi\r\n",
+		"unix.sh":    "#!/bin/sh\necho hi\n",
+		"notes.txt":  "line\r\n",
+		"empty.conf": "",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		goos string
+		want hostpath.File
+	}{
+		{"start.sh", "windows", hostpath.File{Mode: 0755, Data: []byte("#!/bin/sh\necho hi\n")}},
+		{"unix.sh", "windows", hostpath.File{Mode: 0755}},
+		{"notes.txt", "windows", hostpath.File{Mode: 0644}},
+		{"empty.conf", "windows", hostpath.File{Mode: 0644}},
+		{"start.sh", "linux", hostpath.File{Mode: 0600}},
+		{".", "windows", hostpath.File{Mode: os.ModeDir | 0755}},
+	}
+
+	for _, test := range tests {
+		file := filepath.Join(dir, test.name)
+		info, err := os.Lstat(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := hostpath.ForContainer(file, info, test.goos)
+		if err != nil {
+			t.Errorf("%s on %s: %s", test.name, test.goos, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s on %s: expected %+v got %+v", test.name, test.goos, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+// +build !windows
+
+package idle
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the command in a session of its own, so closing the
+// terminal doesn't stop it
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
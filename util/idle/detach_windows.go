@@ -0,0 +1,17 @@
+// +build windows
+
+package idle
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// the process doesn't get the console's ctrl + c
+const createNewProcessGroup = 0x00000200
+
+// detach starts the command in a process group of its own, so closing the
+// console doesn't stop it
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: createNewProcessGroup}
+}
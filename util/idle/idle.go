@@ -0,0 +1,117 @@
+// Package idle keeps track of when each app was last used, so the apps that
+// were left running can be suspended once they've been idle for a while. A
+// running nanobox command marks its app active, and a single watcher in the
+// background suspends the idle ones.
+package idle
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/nanobox-io/nanobox/util/config"
+)
+
+// Heartbeat is how often a running command marks its app active
+var Heartbeat = time.Minute
+
+// the port the watcher holds while it runs, so there's only ever one
+const watcherPort = 12344
+
+// dir is where the activity of the apps is kept, a file per app
+func dir() string {
+	return filepath.Join(config.GlobalDir(), "activity")
+}
+
+// Touch marks the app active now
+func Touch(envID string) error {
+	if err := os.MkdirAll(dir(), 0755); err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir(), envID)
+	now := time.Now()
+
+	if err := os.Chtimes(path, now, now); err == nil {
+		return nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// LastActive returns when the app was last used, zero if it never was
+func LastActive(envID string) time.Time {
+	info, err := os.Stat(filepath.Join(dir(), envID))
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
+
+// Track marks the app active until the returned func is called, or the
+// command exits
+func Track(envID string) func() {
+	Touch(envID)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-time.After(Heartbeat):
+				Touch(envID)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// Listen claims the watcher's port. It fails if a watcher is running.
+func Listen() (net.Listener, error) {
+	return net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", watcherPort))
+}
+
+// Running returns true if a watcher is running
+func Running() bool {
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", watcherPort), time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+
+	return true
+}
+
+// Spawn starts the watcher in the background, detached from the terminal,
+// unless one is running
+func Spawn() error {
+	if Running() {
+		return nil
+	}
+
+	path, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(path, "idle-watch", "--internal")
+	detach(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// the watcher outlives the command
+	return cmd.Process.Release()
+}
@@ -0,0 +1,40 @@
+package idle_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nanobox-io/nanobox/util/idle"
+)
+
+func TestTrack(t *testing.T) {
+	home, err := ioutil.TempDir("", "nanobox-idle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	os.Setenv("HOME", home)
+	os.Setenv("USERPROFILE", home)
+
+	if !idle.LastActive("abc").IsZero() {
+		t.Fatalf("an unused app was active")
+	}
+
+	idle.Heartbeat = 10 * time.Millisecond
+	stop := idle.Track("abc")
+
+	first := idle.LastActive("abc")
+	if first.IsZero() {
+		t.Fatalf("the app wasn't marked active")
+	}
+
+	// the heartbeat keeps it active while the command runs
+	time.Sleep(1100 * time.Millisecond)
+	if !idle.LastActive("abc").After(first) {
+		t.Errorf("the heartbeat didn't mark the app active")
+	}
+
+	stop()
+}
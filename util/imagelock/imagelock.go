@@ -0,0 +1,85 @@
+// Package imagelock reads and writes nanobox.lock, the digests the images of
+// an app are pinned to. The file lives next to the boxfile and is committed
+// with it, so every teammate runs the exact same images whatever their tags
+// point at upstream.
+package imagelock
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// File is the name of the lock file
+const File = "nanobox.lock"
+
+// Lock is the digest of each image, by the image as the boxfile names it
+type Lock struct {
+	Images map[string]string `json:"images"`
+}
+
+// Load reads the lock of the app in dir, an empty one if there's none
+func Load(dir string) (Lock, error) {
+	lock := Lock{Images: map[string]string{}}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, File))
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return lock, err
+	}
+
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return lock, err
+	}
+	if lock.Images == nil {
+		lock.Images = map[string]string{}
+	}
+
+	return lock, nil
+}
+
+// Save writes the lock of the app in dir
+func (l Lock) Save(dir string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, File), append(data, '\n'), 0644)
+}
+
+// Digest returns the digest the image is pinned to, ie: sha256:0123...
+func (l Lock) Digest(image string) (string, bool) {
+	digest, ok := l.Images[image]
+	return digest, ok && digest != ""
+}
+
+// Pin pins the image to the digest, and returns the digest it was pinned to
+// before if it moved
+func (l Lock) Pin(image, digest string) string {
+	before := l.Images[image]
+	l.Images[image] = digest
+
+	if before != digest {
+		return before
+	}
+
+	return ""
+}
+
+// Ref returns the reference of the pinned digest of an image, ie:
+// nanobox/postgresql:9.5 pinned to sha256:0123 is nanobox/postgresql@sha256:0123
+func Ref(image, digest string) string {
+	// the tag is after the last ':' that comes after the last '/', a ':'
+	// before that is the port of the registry
+	name := strings.SplitN(image, "@", 2)[0]
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+
+	return name + "@" + digest
+}
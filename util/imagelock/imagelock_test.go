@@ -0,0 +1,63 @@
+package imagelock_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/imagelock"
+)
+
+func TestLoadSave(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nanobox-imagelock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// no lock pins nothing
+	lock, err := imagelock.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := lock.Digest("nanobox/build"); ok {
+		t.Errorf("an empty lock pinned nanobox/build")
+	}
+
+	if before := lock.Pin("nanobox/build", "sha256:aaa"); before != "" {
+		t.Errorf("a new pin moved from '%s'", before)
+	}
+	if err := lock.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err = imagelock.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest, _ := lock.Digest("nanobox/build"); digest != "sha256:aaa" {
+		t.Errorf("expected sha256:aaa got '%s'", digest)
+	}
+	if before := lock.Pin("nanobox/build", "sha256:bbb"); before != "sha256:aaa" {
+		t.Errorf("expected the pin to move from sha256:aaa, got '%s'", before)
+	}
+	if before := lock.Pin("nanobox/build", "sha256:bbb"); before != "" {
+		t.Errorf("the same pin moved from '%s'", before)
+	}
+}
+
+func TestRef(t *testing.T) {
+	images := map[string]string{
+		"nanobox/build":                "nanobox/build@sha256:aaa",
+		"nanobox/postgresql:9.5":       "nanobox/postgresql@sha256:aaa",
+		"localhost:5000/image":         "localhost:5000/image@sha256:aaa",
+		"localhost:5000/image:1.0":     "localhost:5000/image@sha256:aaa",
+		"nanobox/redis@sha256:0123456": "nanobox/redis@sha256:aaa",
+	}
+
+	for image, ref := range images {
+		if got := imagelock.Ref(image, "sha256:aaa"); got != ref {
+			t.Errorf("%s: expected '%s' got '%s'", image, ref, got)
+		}
+	}
+}
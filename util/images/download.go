@@ -0,0 +1,355 @@
+package images
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/imagelock"
+)
+
+// DefaultConcurrency is how many layers the daemon downloads at once, and how
+// many images DownloadAll pulls at once, unless pull-concurrency says
+// otherwise
+const DefaultConcurrency = 3
+
+// how many attempts in a row a pull can fail without finishing a layer
+// before it's given up on, and how long to wait between them
+var (
+	maxStalls    = 3
+	retryBackoff = time.Second
+)
+
+// Download pulls the image unless it's there. An image nanobox.lock pins is
+// pulled by its digest and tagged as the image, so it's the same image
+// whatever the tag points at upstream. The mirrors of its registry are tried
+// before the registry itself. A pull that fails is tried again for as long
+// as it finishes layers, the daemon keeps the layers it finished, so an
+// interrupted pull resumes where it stopped rather than from zero. With the
+// verify config on, an image whose signature doesn't check out is rejected.
+func Download(image string, output io.Writer) error {
+	if output == nil {
+		output = ioutil.Discard
+	}
+
+	if Current(image) {
+		return Verify(image)
+	}
+
+	ref := image
+	if digest, pinned := pin(image); pinned {
+		ref = imagelock.Ref(image, digest)
+	}
+
+	if err := pullAs(ref, image, output); err != nil {
+		return err
+	}
+
+	return Verify(image)
+}
+
+// Current returns true if the image is there and, when nanobox.lock pins it,
+// is the pinned digest
+func Current(image string) bool {
+	if !docker.ImageExists(image) {
+		return false
+	}
+
+	digest, pinned := pin(image)
+	if !pinned {
+		return true
+	}
+
+	// an image loaded from a bundle has no digest, it was saved pinned
+	current := Digest(image)
+	if current == "" || current == digest {
+		return true
+	}
+
+	lumber.Warn("images:Current(%s): the tag moved to %s, %s pins %s", image, current, imagelock.File, digest)
+	return false
+}
+
+// pin returns the digest nanobox.lock pins the image to
+func pin(image string) (string, bool) {
+	lock, err := imagelock.Load(config.LocalDir())
+	if err != nil {
+		lumber.Error("images:pin:imagelock.Load(): %s", err.Error())
+	}
+
+	return lock.Digest(image)
+}
+
+// Refresh pulls the image from its registry even if it's there, to see where
+// the tag points now. nanobox.lock is left out of it.
+func Refresh(image string, output io.Writer) error {
+	if output == nil {
+		output = ioutil.Discard
+	}
+
+	return pullAs(image, image, output)
+}
+
+// Digest returns the digest the image was pulled with, ie: sha256:0123...,
+// empty if it didn't come from a registry
+func Digest(image string) string {
+	list, err := docker.ImageList()
+	if err != nil {
+		lumber.Error("images:Digest:docker.ImageList(): %s", err.Error())
+		return ""
+	}
+
+	tag := localTag(image)
+	for _, img := range list {
+		for _, t := range img.RepoTags {
+			if t != tag {
+				continue
+			}
+			for _, repoDigest := range img.RepoDigests {
+				if parts := strings.SplitN(repoDigest, "@", 2); len(parts) == 2 {
+					return parts[1]
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// pullAs pulls ref, from a mirror if one has it, and tags it as image
+func pullAs(ref, image string, output io.Writer) error {
+	for _, mirror := range Mirrors(RegistryHost(ref)) {
+		mirrored := MirrorImage(ref, mirror)
+
+		if err := pullResuming(mirrored, output); err != nil {
+			lumber.Info("images:pullAs:pullResuming(%s): %s, falling back on %s", mirrored, err.Error(), RegistryHost(ref))
+			continue
+		}
+
+		// the image is known by its own name, the mirror's name stays as the
+		// digest docker knows the image by is under it
+		if err := docker.Client.ImageTag(context.Background(), mirrored, image); err != nil {
+			lumber.Error("images:pullAs:docker.Client.ImageTag(%s, %s): %s", mirrored, image, err.Error())
+			continue
+		}
+
+		return nil
+	}
+
+	if err := pullResuming(ref, output); err != nil {
+		return err
+	}
+
+	// a digest isn't a tag, the image is tagged with its name
+	if ref != image {
+		if err := docker.Client.ImageTag(context.Background(), ref, image); err != nil {
+			lumber.Error("images:pullAs:docker.Client.ImageTag(%s, %s): %s", ref, image, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// localTag returns the tag docker lists an image under, ie: ruby is
+// ruby:latest and docker.io/library/ruby:2.4 is ruby:2.4
+func localTag(image string) string {
+	image = strings.TrimPrefix(image, DefaultRegistry+"/")
+	image = strings.TrimPrefix(image, "library/")
+
+	if i := strings.LastIndex(image, ":"); i <= strings.LastIndex(image, "/") {
+		image += ":latest"
+	}
+
+	return image
+}
+
+// DownloadAll downloads the images, pull-concurrency of them at once. The
+// progress of each image is written to the writer output returns for it.
+func DownloadAll(images []string, output func(image string) io.Writer) error {
+	conf, _ := models.LoadConfig()
+	concurrency := conf.PullConcurrency
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+
+	sem := make(chan struct{}, concurrency)
+	errs := make(chan error, len(images))
+	wg := sync.WaitGroup{}
+
+	for _, image := range images {
+		wg.Add(1)
+		go func(image string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if err := Download(image, output(image)); err != nil {
+				errs <- fmt.Errorf("failed to pull %s: %s", image, err.Error())
+			}
+		}(image)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	// the first failure is reported, every image was given its chance
+	for err := range errs {
+		return err
+	}
+
+	return nil
+}
+
+// pullResuming pulls the image until it's there, or an attempt after
+// maxStalls others in a row fails without finishing a layer, or the disk is
+// full
+func pullResuming(image string, output io.Writer) error {
+	stalls := 0
+	done := map[string]bool{}
+
+	for {
+		before := len(done)
+
+		err := pull(image, output, done)
+		if err == nil {
+			return nil
+		}
+
+		// retrying doesn't make room
+		if strings.Contains(err.Error(), "no space left on device") {
+			return util.Errorf("[USER] the disk ran out of space pulling %s, free up space with 'nanobox clean --all', or grow the VM disk with 'nanobox vm set --disk <size>'", image)
+		}
+
+		if len(done) == before {
+			stalls++
+		} else {
+			stalls = 0
+		}
+
+		if stalls >= maxStalls {
+			return err
+		}
+
+		lumber.Info("images:pullResuming(%s): %s, %d layers done, retrying", image, err.Error(), len(done))
+		<-time.After(retryBackoff * time.Duration(stalls+1))
+	}
+}
+
+// pull makes one attempt at pulling the image, adding the layers it
+// finished to done
+func pull(image string, output io.Writer, done map[string]bool) error {
+	auth, err := RegistryAuth(RegistryHost(image))
+	if err != nil {
+		return err
+	}
+
+	rc, err := docker.Client.ImagePull(context.Background(), image, types.ImagePullOptions{RegistryAuth: auth})
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	decoder := json.NewDecoder(io.TeeReader(rc, output))
+
+	for {
+		message := struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		}{}
+
+		if err := decoder.Decode(&message); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		if message.Error != "" {
+			return fmt.Errorf(message.Error)
+		}
+
+		if message.Status == "Pull complete" || message.Status == "Already exists" {
+			done[message.ID] = true
+		}
+	}
+}
+
+// Mirrors returns the mirrors of a registry host from the registry-mirror
+// config, a comma separated list. A mirror on its own is a mirror of the
+// docker hub, ie: https://mirror.example.com, one of another registry is set
+// as registry=mirror, ie: quay.io=mirror.example.com:5000.
+func Mirrors(host string) []string {
+	conf, _ := models.LoadConfig()
+	return parseMirrors(conf.RegistryMirror, host)
+}
+
+// HubMirrors returns the docker hub mirrors of the registry-mirror config,
+// the ones the docker daemon can be told about
+func HubMirrors(list string) []string {
+	mirrors := []string{}
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry != "" && !strings.Contains(entry, "=") {
+			mirrors = append(mirrors, entry)
+		}
+	}
+
+	return mirrors
+}
+
+// parseMirrors returns the mirrors of host in the list, without the scheme
+func parseMirrors(list, host string) []string {
+	mirrors := []string{}
+
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		registry, mirror := DefaultRegistry, entry
+		if parts := strings.SplitN(entry, "=", 2); len(parts) == 2 {
+			registry, mirror = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		}
+
+		if registry != host {
+			continue
+		}
+
+		mirror = strings.TrimPrefix(strings.TrimPrefix(mirror, "https://"), "http://")
+		mirrors = append(mirrors, strings.TrimSuffix(mirror, "/"))
+	}
+
+	return mirrors
+}
+
+// MirrorImage returns the reference of the image at the mirror. An official
+// image of the docker hub is under library/, ie: ruby is
+// mirror.example.com/library/ruby.
+func MirrorImage(image, mirror string) string {
+	path := image
+	if host := RegistryHost(image); host != DefaultRegistry || strings.HasPrefix(image, DefaultRegistry+"/") {
+		path = strings.SplitN(image, "/", 2)[1]
+	}
+
+	if !strings.Contains(strings.Split(path, ":")[0], "/") && RegistryHost(image) == DefaultRegistry {
+		path = "library/" + path
+	}
+
+	return mirror + "/" + path
+}
@@ -0,0 +1,62 @@
+package images
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMirrors(t *testing.T) {
+	list := "https://mirror.example.com/, quay.io=http://quay-mirror.example.com:5000,hub2.example.com"
+
+	hosts := map[string][]string{
+		DefaultRegistry: {"mirror.example.com", "hub2.example.com"},
+		"quay.io":       {"quay-mirror.example.com:5000"},
+		"gcr.io":        {},
+	}
+
+	for host, mirrors := range hosts {
+		if got := parseMirrors(list, host); !reflect.DeepEqual(got, mirrors) {
+			t.Errorf("%s: expected mirrors %v got %v", host, mirrors, got)
+		}
+	}
+
+	if got := HubMirrors(list); !reflect.DeepEqual(got, []string{"https://mirror.example.com/", "hub2.example.com"}) {
+		t.Errorf("unexpected hub mirrors %v", got)
+	}
+}
+
+func TestMirrorImage(t *testing.T) {
+	images := map[string]string{
+		"ruby":                      "mirror:5000/library/ruby",
+		"ruby:2.4":                  "mirror:5000/library/ruby:2.4",
+		"nanobox/build":             "mirror:5000/nanobox/build",
+		"docker.io/nanobox/build":   "mirror:5000/nanobox/build",
+		"quay.io/org/image:1.0":     "mirror:5000/org/image:1.0",
+		"localhost:5000/image":      "mirror:5000/image",
+		"org/image@sha256:01234567": "mirror:5000/org/image@sha256:01234567",
+	}
+
+	for image, mirrored := range images {
+		if got := MirrorImage(image, "mirror:5000"); got != mirrored {
+			t.Errorf("%s: expected '%s' got '%s'", image, mirrored, got)
+		}
+	}
+}
+
+func TestLocalTag(t *testing.T) {
+	images := map[string]string{
+		"ruby":                        "ruby:latest",
+		"ruby:2.4":                    "ruby:2.4",
+		"docker.io/library/ruby:2.4":  "ruby:2.4",
+		"nanobox/build":               "nanobox/build:latest",
+		"localhost:5000/image":        "localhost:5000/image:latest",
+		"quay.io/org/image:1.0":       "quay.io/org/image:1.0",
+		"docker.io/nanobox/redis:4.0": "nanobox/redis:4.0",
+	}
+
+	for image, tag := range images {
+		if got := localTag(image); got != tag {
+			t.Errorf("%s: expected '%s' got '%s'", image, tag, got)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Package images pulls docker images, authenticating against private
+// registries with the credentials stored by 'nanobox registry login', and
+// saves and loads them for the offline bundles of 'nanobox fetch'.
+package images
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/docker/engine-api/types"
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util/secret"
+)
+
+// DefaultRegistry is the registry used when an image doesn't name one
+const DefaultRegistry = "docker.io"
+
+// Pull makes a single attempt at pulling the image, writing the docker
+// progress stream to output. If credentials are stored for the image's
+// registry they are sent along. Download is what setups use, it tries the
+// mirrors and resumes interrupted pulls.
+func Pull(image string, output io.Writer) error {
+	if output == nil {
+		output = ioutil.Discard
+	}
+
+	return pull(image, output, map[string]bool{})
+}
+
+// RegistryHost returns the registry host of an image reference. Following
+// docker's rules, the first path component is a host only if it contains a
+// '.' or ':' or is 'localhost'.
+func RegistryHost(image string) string {
+	i := strings.Index(image, "/")
+	if i == -1 {
+		return DefaultRegistry
+	}
+
+	host := image[:i]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return DefaultRegistry
+	}
+
+	return host
+}
+
+// RegistryAuth returns the encoded auth header for a registry host, or an
+// empty string if no credentials are stored for it
+func RegistryAuth(host string) (string, error) {
+	registryAuth, _ := models.FindRegistryAuthByHost(host)
+	if registryAuth.IsNew() {
+		return "", nil
+	}
+
+	password, err := secret.Decrypt(registryAuth.Password)
+	if err != nil {
+		lumber.Error("images:RegistryAuth:secret.Decrypt(): %s", err.Error())
+		return "", fmt.Errorf("failed to decrypt credentials for %s: %s", host, err.Error())
+	}
+
+	return EncodeAuth(types.AuthConfig{
+		Username:      registryAuth.Username,
+		Password:      password,
+		ServerAddress: host,
+	})
+}
+
+// EncodeAuth encodes the credentials the way the docker api expects them
+func EncodeAuth(authConfig types.AuthConfig) (string, error) {
+	b, err := json.Marshal(authConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode registry auth: %s", err.Error())
+	}
+
+	return base64.URLEncoding.EncodeToString(b), nil
+}
+
+// Save writes the images to w as a tarball, the way 'docker save' does
+func Save(images []string, w io.Writer) error {
+	rc, err := docker.Client.ImageSave(context.Background(), images)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	_, err = io.Copy(w, rc)
+	return err
+}
+
+// Load loads the images of a tarball 'docker save' wrote, returning any
+// error reported by the daemon mid-stream
+func Load(r io.Reader) error {
+	res, err := docker.Client.ImageLoad(context.Background(), r, true)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	return readPullStream(res.Body, ioutil.Discard)
+}
+
+// readPullStream copies the progress stream to output, returning any error
+// reported by the daemon mid-stream
+func readPullStream(r io.Reader, output io.Writer) error {
+	decoder := json.NewDecoder(io.TeeReader(r, output))
+
+	for {
+		message := struct {
+			Error string `json:"error"`
+		}{}
+
+		if err := decoder.Decode(&message); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		if message.Error != "" {
+			return fmt.Errorf(message.Error)
+		}
+	}
+}
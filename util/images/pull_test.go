@@ -0,0 +1,23 @@
+package images
+
+import (
+	"testing"
+)
+
+func TestRegistryHost(t *testing.T) {
+	images := map[string]string{
+		"nanobox/build":                   DefaultRegistry,
+		"ubuntu":                          DefaultRegistry,
+		"quay.io/org/image:1.0":           "quay.io",
+		"localhost:5000/image":            "localhost:5000",
+		"localhost/image":                 "localhost",
+		"registry.example.com:443/a/b/c":  "registry.example.com:443",
+		"org/image@sha256:0123456789abcd": DefaultRegistry,
+	}
+
+	for image, host := range images {
+		if RegistryHost(image) != host {
+			t.Errorf("%s: expected host '%s' got '%s'", image, host, RegistryHost(image))
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package images
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+	"github.com/nanobox-io/golang-docker-client"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/imagelock"
+)
+
+// Verify checks the signature of the image with cosign against the public
+// key of the cosign-key config, when the verify config is on. The image is
+// checked by its digest, so what's checked is what runs. An image that
+// fails is removed.
+func Verify(image string) error {
+	conf, _ := models.LoadConfig()
+	if !conf.Verify {
+		return nil
+	}
+
+	digest := Digest(image)
+	if digest == "" {
+		docker.ImageRemove(image, false)
+		return fmt.Errorf("%s has no digest to verify, only images pulled from a registry can be verified", image)
+	}
+
+	if verified(digest, conf.CosignKey) {
+		return nil
+	}
+
+	if conf.CosignKey == "" {
+		return fmt.Errorf("verifying images needs the public key they're signed with, see 'nanobox config set cosign-key <path>'")
+	}
+
+	if _, err := exec.LookPath("cosign"); err != nil {
+		return fmt.Errorf("cosign is needed to verify %s", image)
+	}
+
+	ref := imagelock.Ref(image, digest)
+	out, err := exec.Command("cosign", "verify", "--key", conf.CosignKey, ref).CombinedOutput()
+	if err != nil {
+		lumber.Error("images:Verify:cosign verify %s: %s", ref, out)
+		docker.ImageRemove(image, false)
+		return fmt.Errorf("%s isn't signed with %s, it was removed", image, conf.CosignKey)
+	}
+
+	markVerified(digest, conf.CosignKey)
+
+	return nil
+}
+
+// verifiedPath is where a digest that was verified is recorded, with the
+// key it was verified against
+func verifiedPath(digest string) string {
+	return filepath.Join(config.GlobalDir(), "verified", strings.Replace(digest, ":", "_", 1))
+}
+
+// verified returns true if the digest was verified against the key
+func verified(digest, key string) bool {
+	data, err := ioutil.ReadFile(verifiedPath(digest))
+	return err == nil && key != "" && string(data) == key
+}
+
+// markVerified records that the digest was verified against the key, so it
+// isn't checked again every time
+func markVerified(digest, key string) {
+	path := verifiedPath(digest)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		lumber.Error("images:markVerified:os.MkdirAll(): %s", err.Error())
+		return
+	}
+
+	if err := ioutil.WriteFile(path, []byte(key), 0644); err != nil {
+		lumber.Error("images:markVerified:ioutil.WriteFile(%s): %s", path, err.Error())
+	}
+}
@@ -0,0 +1,126 @@
+package interpolate
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// a variable of the boxfile, with an optional default, ie: ${PG_VERSION:-9.6}
+var variable = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// scriptKeys are the keys of the boxfile that hold commands. Their variables
+// are expanded by the shell that runs them, not by nanobox.
+var scriptKeys = map[string]bool{
+	"start":           true,
+	"stop":            true,
+	"command":         true,
+	"extra_steps":     true,
+	"transform":       true,
+	"before_build":    true,
+	"after_build":     true,
+	"before_deploy":   true,
+	"before_live":     true,
+	"before_live_all": true,
+	"after_live":      true,
+	"after_live_all":  true,
+	"parallel_steps":  true,
+}
+
+// Boxfile expands the variables of a boxfile, ie: image: nanobox/postgresql:${PG_VERSION}.
+// A variable is looked up in the environment of the host first, then in the
+// evars, and falls back to its default. The variables that can't be resolved
+// are left as they are and returned. $${NAME} is a literal ${NAME}.
+func Boxfile(box string, evars map[string]string) (string, []string, error) {
+	doc := yaml.MapSlice{}
+	if err := yaml.Unmarshal([]byte(box), &doc); err != nil {
+		return box, nil, err
+	}
+
+	missing := map[string]bool{}
+	lookup := func(value string) string {
+		return variable.ReplaceAllStringFunc(value, func(match string) string {
+			if strings.HasPrefix(match, "$$") {
+				return match[1:]
+			}
+
+			parts := variable.FindStringSubmatch(match)
+			if val, ok := os.LookupEnv(parts[1]); ok {
+				return val
+			}
+			if val, ok := evars[parts[1]]; ok {
+				return val
+			}
+			if parts[2] != "" {
+				return parts[3]
+			}
+
+			missing[parts[1]] = true
+			return match
+		})
+	}
+
+	out, err := yaml.Marshal(expandValue(doc, lookup))
+	if err != nil {
+		return box, nil, err
+	}
+
+	names := []string{}
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return string(out), names, nil
+}
+
+// expandValue expands the strings of a value, the keys of maps and the
+// values of the scriptKeys are kept as they are
+func expandValue(value interface{}, lookup func(string) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return typed(lookup(v), v)
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = expandValue(item, lookup)
+		}
+		return list
+	case yaml.MapSlice:
+		node := yaml.MapSlice{}
+		for _, item := range v {
+			if scriptKeys[fmt.Sprintf("%v", item.Key)] {
+				node = append(node, item)
+				continue
+			}
+			node = append(node, yaml.MapItem{Key: item.Key, Value: expandValue(item.Value, lookup)})
+		}
+		return node
+	}
+
+	return value
+}
+
+// typed returns an expanded value as the number or bool it stands for, so
+// 'memory: ${MEMORY}' is the same as 'memory: 512' once it's expanded
+func typed(expanded, original string) interface{} {
+	if expanded == original {
+		return original
+	}
+
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(expanded), &value); err != nil {
+		return expanded
+	}
+
+	switch value.(type) {
+	case int, int64, uint64, float64, bool:
+		return value
+	}
+
+	return expanded
+}
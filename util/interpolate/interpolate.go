@@ -0,0 +1,83 @@
+// Package interpolate expands evars that reference other evars, ie:
+// CACHE_URL=redis://${DATA_CACHE_HOST}:6379/0. The evars keep the template,
+// the values are expanded when they're rendered into a container.
+package interpolate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+)
+
+// a reference to another evar, $${NAME} is a literal ${NAME}
+var reference = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Evars returns a copy of the evars with the references expanded. References
+// to evars that don't exist are left as they are, evars that reference each
+// other are an error.
+func Evars(evars map[string]string) (map[string]string, error) {
+	expanded := map[string]string{}
+
+	for key := range evars {
+		if _, err := expand(key, evars, expanded, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return expanded, nil
+}
+
+// Expanded returns a copy of the evars with the references expanded, for
+// callers that can't fail. Evars that reference each other are left out,
+// Evars reports them.
+func Expanded(evars map[string]string) map[string]string {
+	expanded := map[string]string{}
+
+	for key := range evars {
+		if _, err := expand(key, evars, expanded, nil); err != nil {
+			lumber.Error("interpolate:Expanded:expand(%s): %s", key, err.Error())
+		}
+	}
+
+	return expanded
+}
+
+// expand expands the value of an evar, expanding the evars it references
+// first. The evars being expanded are tracked to catch cycles.
+func expand(key string, evars, expanded map[string]string, expanding []string) (string, error) {
+	if val, ok := expanded[key]; ok {
+		return val, nil
+	}
+
+	for i, name := range expanding {
+		if name == key {
+			return "", fmt.Errorf("%s reference each other", strings.Join(append(expanding[i:], key), " -> "))
+		}
+	}
+	expanding = append(expanding, key)
+
+	var err error
+	val := reference.ReplaceAllStringFunc(evars[key], func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+
+		name := match[2 : len(match)-1]
+		if _, ok := evars[name]; !ok || err != nil {
+			return match
+		}
+
+		var sub string
+		sub, err = expand(name, evars, expanded, expanding)
+		return sub
+	})
+	if err != nil {
+		return "", err
+	}
+
+	expanded[key] = val
+
+	return val, nil
+}
@@ -0,0 +1,98 @@
+package interpolate_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/interpolate"
+)
+
+func TestEvars(t *testing.T) {
+	evars := map[string]string{
+		"DATA_CACHE_HOST": "192.168.0.5",
+		"CACHE_URL":       "redis://${DATA_CACHE_HOST}:6379/0",
+		"WORKER_CACHE":    "${CACHE_URL}?pool=5",
+		"UNKNOWN":         "${NOT_SET}",
+		"LITERAL":         "$${DATA_CACHE_HOST}",
+		"PASSWORD":        "pa$$word",
+	}
+
+	expected := map[string]string{
+		"DATA_CACHE_HOST": "192.168.0.5",
+		"CACHE_URL":       "redis://192.168.0.5:6379/0",
+		"WORKER_CACHE":    "redis://192.168.0.5:6379/0?pool=5",
+		"UNKNOWN":         "${NOT_SET}",
+		"LITERAL":         "${DATA_CACHE_HOST}",
+		"PASSWORD":        "pa$$word",
+	}
+
+	expanded, err := interpolate.Evars(evars)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expanded, expected) {
+		t.Errorf("expected %v got %v", expected, expanded)
+	}
+}
+
+func TestEvarsCycle(t *testing.T) {
+	evars := map[string]string{
+		"A":     "${B}",
+		"B":     "${C}",
+		"C":     "${A}",
+		"PLAIN": "value",
+	}
+
+	if _, err := interpolate.Evars(evars); err == nil {
+		t.Errorf("expected evars that reference each other to fail")
+	}
+
+	expanded := interpolate.Expanded(evars)
+	if !reflect.DeepEqual(expanded, map[string]string{"PLAIN": "value"}) {
+		t.Errorf("expected only PLAIN to be expanded, got %v", expanded)
+	}
+}
+
+func TestBoxfile(t *testing.T) {
+	os.Setenv("NANOBOX_TEST_PG", "9.6")
+	defer os.Unsetenv("NANOBOX_TEST_PG")
+
+	box := `web.main:
+  start: puma -p ${PORT}
+  ports:
+  - tcp:${SSH_PORT}:22
+  config:
+    memory: ${MEMORY:-512}
+data.db:
+  image: nanobox/postgresql:${NANOBOX_TEST_PG}
+  config:
+    name: $${LITERAL}
+`
+
+	expected := `web.main:
+  start: puma -p ${PORT}
+  ports:
+  - tcp:${SSH_PORT}:22
+  config:
+    memory: 512
+data.db:
+  image: nanobox/postgresql:9.6
+  config:
+    name: ${LITERAL}
+`
+
+	resolved, missing, err := interpolate.Boxfile(box, map[string]string{"NANOBOX_TEST_PG": "9.4"})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+
+	if resolved != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, resolved)
+	}
+
+	if !reflect.DeepEqual(missing, []string{"SSH_PORT"}) {
+		t.Errorf("expected SSH_PORT to be unresolved, got %v", missing)
+	}
+}
@@ -0,0 +1,40 @@
+// Package keychain keeps secrets in the credential store of the os: the
+// Keychain on macOS, the Credential Manager on Windows and the Secret
+// Service (libsecret) on linux.
+package keychain
+
+import (
+	"errors"
+)
+
+// Service is the name the secrets are filed under
+const Service = "nanobox"
+
+var (
+	// ErrUnavailable is returned when the os has no credential store nanobox
+	// can use, callers are expected to fall back to their own storage
+	ErrUnavailable = errors.New("no keychain available")
+
+	// ErrNotFound is returned when there is no secret for the account
+	ErrNotFound = errors.New("secret not found in keychain")
+)
+
+// Set stores the secret of an account, replacing the existing one
+func Set(account, secret string) error {
+	return set(account, secret)
+}
+
+// Get returns the secret of an account
+func Get(account string) (string, error) {
+	return get(account)
+}
+
+// Delete removes the secret of an account, it is not an error if there is
+// none
+func Delete(account string) error {
+	if err := del(account); err != nil && err != ErrNotFound {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package keychain
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// the exit code of security when an item doesn't exist
+const errSecItemNotFound = 44
+
+func set(account, secret string) error {
+	if _, err := exec.LookPath("security"); err != nil {
+		return ErrUnavailable
+	}
+
+	// a command can only span a line
+	if strings.ContainsAny(secret, "\r\n") {
+		return fmt.Errorf("failed to store in keychain: the secret spans multiple lines")
+	}
+
+	// the command is fed to security on stdin so the secret never shows up
+	// in ps, -U updates the item when it already exists
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", quote(Service), quote(account), quote(secret)))
+
+	// the interactive mode prints its prompt, and the errors of the command
+	// without failing
+	out, err := cmd.CombinedOutput()
+	msg := strings.TrimSpace(strings.Replace(string(out), "security>", "", -1))
+	if err != nil || msg != "" {
+		return fmt.Errorf("failed to store in keychain: %s", msg)
+	}
+
+	return nil
+}
+
+// quote double quotes an argument of an interactive security command
+func quote(arg string) string {
+	arg = strings.Replace(arg, `\`, `\\`, -1)
+	return `"` + strings.Replace(arg, `"`, `\"`, -1) + `"`
+}
+
+func get(account string) (string, error) {
+	if _, err := exec.LookPath("security"); err != nil {
+		return "", ErrUnavailable
+	}
+
+	out, err := exec.Command("security", "find-generic-password", "-s", Service, "-a", account, "-w").Output()
+	if err != nil {
+		if exitCode(err) == errSecItemNotFound {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to read from keychain: %s", err.Error())
+	}
+
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func del(account string) error {
+	if _, err := exec.LookPath("security"); err != nil {
+		return ErrUnavailable
+	}
+
+	if err := exec.Command("security", "delete-generic-password", "-s", Service, "-a", account).Run(); err != nil {
+		if exitCode(err) == errSecItemNotFound {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to delete from keychain: %s", err.Error())
+	}
+
+	return nil
+}
+
+// exitCode returns the exit code of a command that failed
+func exitCode(err error) int {
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(interface {
+			ExitStatus() int
+		}); ok {
+			return status.ExitStatus()
+		}
+	}
+
+	return -1
+}
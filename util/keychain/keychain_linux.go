@@ -0,0 +1,60 @@
+package keychain
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// the secret service is reached through secret-tool (libsecret-tools), it
+// needs a session bus, which headless machines often don't have
+func available() bool {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return false
+	}
+
+	return os.Getenv("DBUS_SESSION_BUS_ADDRESS") != ""
+}
+
+func set(account, secret string) error {
+	if !available() {
+		return ErrUnavailable
+	}
+
+	// secret-tool reads the secret from stdin so it never shows up in ps
+	cmd := exec.Command("secret-tool", "store", "--label", fmt.Sprintf("%s (%s)", Service, account), "service", Service, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to store in keychain: %s", strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+func get(account string) (string, error) {
+	if !available() {
+		return "", ErrUnavailable
+	}
+
+	out, err := exec.Command("secret-tool", "lookup", "service", Service, "account", account).Output()
+	if err != nil {
+		// secret-tool exits 1 without output when nothing matches
+		return "", ErrNotFound
+	}
+
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func del(account string) error {
+	if !available() {
+		return ErrUnavailable
+	}
+
+	if err := exec.Command("secret-tool", "clear", "service", Service, "account", account).Run(); err != nil {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+// +build !darwin,!linux,!windows
+
+package keychain
+
+func set(account, secret string) error {
+	return ErrUnavailable
+}
+
+func get(account string) (string, error) {
+	return "", ErrUnavailable
+}
+
+func del(account string) error {
+	return ErrUnavailable
+}
@@ -0,0 +1,124 @@
+package keychain
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredRead   = advapi32.NewProc("CredReadW")
+	procCredWrite  = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+// credential is the CREDENTIALW struct of wincred.h
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// target returns the name of the credential of an account
+func target(account string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(fmt.Sprintf("%s:%s", Service, account))
+}
+
+func set(account, secret string) error {
+	if procCredWrite.Find() != nil {
+		return ErrUnavailable
+	}
+
+	name, err := target(account)
+	if err != nil {
+		return err
+	}
+
+	user, err := syscall.UTF16PtrFromString(account)
+	if err != nil {
+		return err
+	}
+
+	blob := []byte(secret)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         name,
+		UserName:           user,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+
+	if ret, _, err := procCredWrite.Call(uintptr(unsafe.Pointer(&cred)), 0); ret == 0 {
+		return fmt.Errorf("failed to store in credential manager: %s", err.Error())
+	}
+
+	return nil
+}
+
+func get(account string) (string, error) {
+	if procCredRead.Find() != nil {
+		return "", ErrUnavailable
+	}
+
+	name, err := target(account)
+	if err != nil {
+		return "", err
+	}
+
+	var cred *credential
+	if ret, _, err := procCredRead.Call(uintptr(unsafe.Pointer(name)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred))); ret == 0 {
+		if err == errorNotFound {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to read from credential manager: %s", err.Error())
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+
+	if cred.CredentialBlobSize == 0 {
+		return "", nil
+	}
+
+	blob := (*[1 << 20]byte)(unsafe.Pointer(cred.CredentialBlob))[:cred.CredentialBlobSize:cred.CredentialBlobSize]
+
+	return string(blob), nil
+}
+
+func del(account string) error {
+	if procCredDelete.Find() != nil {
+		return ErrUnavailable
+	}
+
+	name, err := target(account)
+	if err != nil {
+		return err
+	}
+
+	if ret, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(name)), credTypeGeneric, 0); ret == 0 {
+		if err == errorNotFound {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to delete from credential manager: %s", err.Error())
+	}
+
+	return nil
+}
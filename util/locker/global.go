@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"net"
 	"sync"
-	"time"
 
 	"github.com/jcelliott/lumber"
 
@@ -21,14 +20,7 @@ var (
 func GlobalLock() error {
 	lumber.Trace("global locking")
 
-	//
-	for {
-		if success, _ := GlobalTryLock(); success {
-			break
-		}
-		lumber.Trace("global lock waiting...")
-		<-time.After(time.Second)
-	}
+	acquire(GlobalTryLock, "Another nanobox command is changing the nanobox vm")
 
 	mutex.Lock()
 	gCount++
@@ -3,7 +3,6 @@ package locker
 import (
 	"fmt"
 	"net"
-	"time"
 
 	"github.com/jcelliott/lumber"
 	"github.com/nanobox-io/nanobox/models"
@@ -18,14 +17,7 @@ var (
 // LocalLock locks on port
 func LocalLock() error {
 
-	//
-	for {
-		if success, _ := LocalTryLock(); success {
-			break
-		}
-		lumber.Trace("local lock waiting...")
-		<-time.After(time.Second)
-	}
+	acquire(LocalTryLock, "Another nanobox command is running for this app")
 
 	mutex.Lock()
 	lCount++
@@ -0,0 +1,43 @@
+package locker
+
+import (
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// Force runs a command without the lock another nanobox command holds,
+// rather than waiting for it to finish, see --ignore-lock
+var Force bool
+
+// the holders the user was already told about, locks are taken many times
+// during a command
+var told = map[string]bool{}
+
+// acquire tries a lock until it's free. The user is told when another command
+// holds it, with Force it gives up and runs without the lock.
+func acquire(tryLock func() (bool, error), holder string) {
+	for {
+		if success, _ := tryLock(); success {
+			return
+		}
+
+		if Force {
+			if !told[holder] {
+				display.Warn("%s, running anyway because of --ignore-lock\n", holder)
+				told[holder] = true
+			}
+			return
+		}
+
+		if !told[holder] {
+			display.Warn("%s, waiting for it to finish (use --ignore-lock to run anyway)\n", holder)
+			told[holder] = true
+		}
+
+		lumber.Trace("lock waiting (%s)...", holder)
+		<-time.After(time.Second)
+	}
+}
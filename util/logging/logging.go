@@ -0,0 +1,82 @@
+// Package logging keeps the debug log of every command in ~/.nanobox/logs.
+// The log of the last command is nanobox.log, the ones before it are
+// rotated to nanobox.1.log, nanobox.2.log and so on, so there's a log to
+// attach to a bug report even after running something else.
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/util/config"
+)
+
+// the number of logs kept, including the current one
+const keep = 10
+
+// the levels, by name
+var levels = map[string]int{
+	"trace": lumber.TRACE,
+	"debug": lumber.DEBUG,
+	"info":  lumber.INFO,
+	"warn":  lumber.WARN,
+	"error": lumber.ERROR,
+}
+
+// Dir returns the directory of the logs
+func Dir() string {
+	return filepath.ToSlash(filepath.Join(config.GlobalDir(), "logs"))
+}
+
+// Path returns the log of the current command
+func Path() string {
+	return rotated(0)
+}
+
+// Start sets up the log of the command. The logs of the commands before it
+// are rotated, unless the command continues the log of another, ie: the
+// internal commands nanobox runs with privileges.
+func Start(rotate bool) error {
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return err
+	}
+
+	if rotate {
+		for i := keep - 1; i > 0; i-- {
+			os.Rename(rotated(i-1), rotated(i))
+		}
+	}
+
+	fileLogger, err := lumber.NewAppendLogger(Path())
+	if err != nil {
+		return err
+	}
+
+	lumber.SetLogger(fileLogger)
+	lumber.Level(lumber.DEBUG)
+
+	return nil
+}
+
+// Level returns the lumber level of a name, ie: the value of NANOBOX_LOG
+func Level(name string) (int, error) {
+	level, ok := levels[name]
+	if !ok {
+		return 0, fmt.Errorf("'%s' isn't a log level, use error, warn, info, debug or trace", name)
+	}
+
+	return level, nil
+}
+
+// rotated returns the path of the nth log before the current one
+func rotated(n int) string {
+	name := "nanobox.log"
+	if n > 0 {
+		name = fmt.Sprintf("nanobox.%d.log", n)
+	}
+
+	return filepath.ToSlash(filepath.Join(Dir(), name))
+}
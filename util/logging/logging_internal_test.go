@@ -0,0 +1,18 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/jcelliott/lumber"
+)
+
+func TestLevel(t *testing.T) {
+	level, err := Level("debug")
+	if err != nil || level != lumber.DEBUG {
+		t.Errorf("expected debug got %d %v", level, err)
+	}
+
+	if _, err := Level("loud"); err == nil {
+		t.Errorf("expected an error for an unknown level")
+	}
+}
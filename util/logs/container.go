@@ -0,0 +1,107 @@
+package logs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/engine-api/types"
+	"github.com/nanobox-io/golang-docker-client"
+	"golang.org/x/net/context"
+)
+
+// ContainerSource is the stdout and stderr of the container of a component
+type ContainerSource struct {
+	Component string
+	Container string
+}
+
+// Stream sends the output of the container, when following until the
+// context is done or the container is removed
+func (s ContainerSource) Stream(ctx context.Context, since time.Time, follow bool, out chan<- Line) error {
+	opts := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     follow,
+		Timestamps: true,
+	}
+	if !since.IsZero() {
+		opts.Since = strconv.FormatInt(since.Unix(), 10)
+	}
+
+	rc, err := docker.Client.ContainerLogs(ctx, s.Container, opts)
+	if err != nil {
+		return fmt.Errorf("failed to read the logs of %s: %s", s.Component, err.Error())
+	}
+	defer rc.Close()
+
+	// docker multiplexes stdout and stderr into frames
+	stdout := &lineWriter{ctx: ctx, out: out, component: s.Component, stream: StreamStdout}
+	stderr := &lineWriter{ctx: ctx, out: out, component: s.Component, stream: StreamStderr}
+
+	_, err = stdcopy.StdCopy(stdout, stderr, rc)
+	stdout.flush()
+	stderr.flush()
+
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to read the logs of %s: %s", s.Component, err.Error())
+	}
+
+	return nil
+}
+
+// lineWriter turns the output of a stream of a container into lines, each
+// with the time docker prefixed it with
+type lineWriter struct {
+	ctx       context.Context
+	out       chan<- Line
+	component string
+	stream    string
+	partial   string
+}
+
+// Write sends the complete lines and keeps the rest for the next write
+func (w *lineWriter) Write(data []byte) (int, error) {
+	text := w.partial + string(data)
+
+	i := strings.LastIndex(text, "\n")
+	w.partial = text[i+1:]
+
+	for _, raw := range strings.Split(text[:i+1], "\n") {
+		if raw == "" {
+			continue
+		}
+		if !send(w.ctx, w.out, parseDockerLine(w.component, w.stream, raw)) {
+			return 0, w.ctx.Err()
+		}
+	}
+
+	return len(data), nil
+}
+
+// flush sends a last line that didn't end with a new line
+func (w *lineWriter) flush() {
+	if w.partial != "" {
+		send(w.ctx, w.out, parseDockerLine(w.component, w.stream, w.partial))
+		w.partial = ""
+	}
+}
+
+// parseDockerLine parses a line docker prefixed with its time, ie:
+// 2017-03-02T15:04:05.123456789Z Listening on 8080
+func parseDockerLine(component, stream, text string) Line {
+	line := Line{Time: time.Now(), Component: component, Stream: stream, Text: strings.TrimRight(text, "\r")}
+
+	fields := strings.SplitN(line.Text, " ", 2)
+	if t, err := time.Parse(time.RFC3339Nano, fields[0]); err == nil {
+		line.Time = t
+		line.Text = ""
+		if len(fields) > 1 {
+			line.Text = fields[1]
+		}
+	}
+
+	return line
+}
@@ -0,0 +1,151 @@
+package logs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/util/config"
+)
+
+// pollInterval is how often a followed hook log is checked for new lines
+const pollInterval = 500 * time.Millisecond
+
+// HookLog returns the file the output of the hooks run in a container is
+// kept in
+func HookLog(container string) string {
+	return filepath.Join(config.GlobalDir(), "logs", "hooks", container+".log")
+}
+
+// WriteHook appends the output of a hook to the hook log of its container,
+// each line with the time and the name of the hook
+func WriteHook(container, hook, output string) error {
+	if container == "" || strings.TrimSpace(output) == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(HookLog(container)), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(HookLog(container), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(f, "%s %s %s\n", now, hook, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveHookLog removes the hook log of a container that is gone
+func RemoveHookLog(container string) {
+	if container != "" {
+		os.Remove(HookLog(container))
+	}
+}
+
+// HookSource is the output of the hooks run in the container of a component
+type HookSource struct {
+	Component string
+	Container string
+}
+
+// Stream sends the lines of the hook log, when following it's checked for
+// new lines until the context is done
+func (s HookSource) Stream(ctx context.Context, since time.Time, follow bool, out chan<- Line) error {
+	f, err := s.open(ctx, follow)
+	if f == nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	partial := ""
+
+	for {
+		text, err := reader.ReadString('\n')
+		if err == io.EOF {
+			// the rest of the line hasn't been written yet
+			partial += text
+			if !follow {
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read the hook log of %s: %s", s.Component, err.Error())
+		}
+
+		line, ok := parseHookLine(s.Component, partial+text)
+		partial = ""
+		if !ok || line.Time.Before(since) {
+			continue
+		}
+
+		if !send(ctx, out, line) {
+			return nil
+		}
+	}
+}
+
+// open opens the hook log, when following it waits for the first hook to
+// write it
+func (s HookSource) open(ctx context.Context, follow bool) (*os.File, error) {
+	for {
+		f, err := os.Open(HookLog(s.Container))
+		if err == nil {
+			return f, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to open the hook log of %s: %s", s.Component, err.Error())
+		}
+		if !follow {
+			return nil, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// parseHookLine parses a line of a hook log, ie:
+// 2017-03-02T15:04:05.123Z configure Creating user...
+func parseHookLine(component, text string) (Line, bool) {
+	fields := strings.SplitN(strings.TrimRight(text, "\r\n"), " ", 3)
+	if len(fields) < 3 {
+		return Line{}, false
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		return Line{}, false
+	}
+
+	return Line{Time: t, Component: component, Stream: StreamHook, Text: fmt.Sprintf("%s: %s", fields[1], fields[2])}, true
+}
@@ -0,0 +1,166 @@
+// Package logs aggregates the logs of the components of an app, the output
+// of their containers and of the hooks run in them, into a single stream
+// ordered by time.
+package logs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// the streams of a line
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+	StreamHook   = "hook"
+)
+
+// settle is how long the lines of a followed log are held, so the lines
+// the sources send at about the same time are put in order
+const settle = 200 * time.Millisecond
+
+// Line is a line of the log of a component
+type Line struct {
+	Time      time.Time `json:"time"`
+	Component string    `json:"component"` // ie: web.main
+	Stream    string    `json:"stream"`    // stdout, stderr or hook
+	Text      string    `json:"text"`
+}
+
+// Source is somewhere the lines of a component come from. It sends the lines
+// since a time to out, and when following, keeps sending them until the
+// context is done.
+type Source interface {
+	Stream(ctx context.Context, since time.Time, follow bool, out chan<- Line) error
+}
+
+// Filter is the lines of the aggregated log that are shown
+type Filter struct {
+	Since  time.Time      // the zero time is from the start
+	Grep   *regexp.Regexp // nil is every line
+	Follow bool
+}
+
+// Match returns true if the line passes the filter
+func (f Filter) Match(line Line) bool {
+	if !f.Since.IsZero() && line.Time.Before(f.Since) {
+		return false
+	}
+
+	if f.Grep != nil && !f.Grep.MatchString(line.Text) {
+		return false
+	}
+
+	return true
+}
+
+// Aggregate reads the sources at the same time and calls print with every
+// line that passes the filter, in order. Without follow it returns once
+// every source is read, otherwise when the context is done.
+func Aggregate(ctx context.Context, sources []Source, filter Filter, print func(Line)) error {
+	lines := make(chan Line, 1000)
+	errs := make(chan error, len(sources))
+
+	var wg sync.WaitGroup
+	for _, source := range sources {
+		wg.Add(1)
+		go func(source Source) {
+			defer wg.Done()
+			if err := source.Stream(ctx, filter.Since, filter.Follow, lines); err != nil {
+				errs <- err
+			}
+		}(source)
+	}
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	buffer := []Line{}
+	flush := func() {
+		sort.Stable(byTime(buffer))
+		for _, line := range buffer {
+			print(line)
+		}
+		buffer = buffer[:0]
+	}
+
+	ticker := time.NewTicker(settle)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				flush()
+				select {
+				case err := <-errs:
+					return err
+				default:
+					return nil
+				}
+			}
+			if filter.Match(line) {
+				buffer = append(buffer, line)
+			}
+		case <-ticker.C:
+			// without follow the whole log is put in order at the end
+			if filter.Follow {
+				flush()
+			}
+		}
+	}
+}
+
+// ParseSince parses how far back the log goes, a duration, ie: 10m, a time,
+// ie: 2017-03-02T15:04:05Z, or today or yesterday, from midnight
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch value {
+	case "":
+		return time.Time{}, nil
+	case "today":
+		return midnight, nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	}
+
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	// a unix timestamp, like docker takes
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	return time.Time{}, fmt.Errorf("'%s' isn't a duration or a time, ie: 10m, yesterday or 2017-03-02T15:04:05Z", value)
+}
+
+// send sends a line unless the context is done
+func send(ctx context.Context, out chan<- Line, line Line) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case out <- line:
+		return true
+	}
+}
+
+// byTime sorts the lines by time
+type byTime []Line
+
+func (l byTime) Len() int           { return len(l) }
+func (l byTime) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l byTime) Less(i, j int) bool { return l[i].Time.Before(l[j].Time) }
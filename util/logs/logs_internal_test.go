@@ -0,0 +1,163 @@
+package logs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// fakeSource sends its lines
+type fakeSource []Line
+
+func (s fakeSource) Stream(ctx context.Context, since time.Time, follow bool, out chan<- Line) error {
+	for _, line := range s {
+		if !send(ctx, out, line) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func TestAggregate(t *testing.T) {
+	start := time.Date(2017, 3, 2, 15, 0, 0, 0, time.UTC)
+
+	web := fakeSource{
+		{Time: start, Component: "web.main", Text: "booting"},
+		{Time: start.Add(2 * time.Second), Component: "web.main", Text: "GET / 200"},
+		{Time: start.Add(4 * time.Second), Component: "web.main", Text: "GET /health 200"},
+	}
+	db := fakeSource{
+		{Time: start.Add(time.Second), Component: "data.db", Text: "ready"},
+		{Time: start.Add(3 * time.Second), Component: "data.db", Text: "GET is not sql"},
+	}
+
+	filter := Filter{Since: start.Add(time.Second), Grep: regexp.MustCompile(`GET|ready`)}
+
+	got := []string{}
+	err := Aggregate(context.Background(), []Source{web, db}, filter, func(line Line) {
+		got = append(got, line.Component+" "+line.Text)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"data.db ready", "web.main GET / 200", "data.db GET is not sql", "web.main GET /health 200"}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], got[i])
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2017, 3, 2, 15, 0, 0, 0, time.UTC)
+
+	since, err := ParseSince("10m", now)
+	if err != nil || !since.Equal(now.Add(-10*time.Minute)) {
+		t.Errorf("10m: got %s, %v", since, err)
+	}
+
+	since, err = ParseSince("2017-03-02T14:00:00Z", now)
+	if err != nil || !since.Equal(now.Add(-time.Hour)) {
+		t.Errorf("time: got %s, %v", since, err)
+	}
+
+	if since, _ := ParseSince("", now); !since.IsZero() {
+		t.Errorf("expected the zero time, got %s", since)
+	}
+
+	since, err = ParseSince("yesterday", now)
+	if err != nil || !since.Equal(time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("yesterday: got %s, %v", since, err)
+	}
+
+	if _, err := ParseSince("last week", now); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestParseLines(t *testing.T) {
+	line := parseDockerLine("web.main", StreamStderr, "2017-03-02T15:04:05.123456789Z Listening on 8080\r")
+	if line.Text != "Listening on 8080" || line.Time.Second() != 5 || line.Stream != StreamStderr {
+		t.Errorf("docker line: got %+v", line)
+	}
+
+	hook, ok := parseHookLine("data.db", "2017-03-02T15:04:05.1Z configure Creating user\n")
+	if !ok || hook.Text != "configure: Creating user" || hook.Stream != StreamHook {
+		t.Errorf("hook line: got %+v", hook)
+	}
+
+	if _, ok := parseHookLine("data.db", "garbage\n"); ok {
+		t.Errorf("expected garbage not to parse")
+	}
+}
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nanobox-logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// every append rotates
+	store := &Store{Dir: dir, MaxSize: 1, Keep: 2}
+	start := time.Date(2017, 3, 2, 15, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 4; i++ {
+		line := Line{Time: start.Add(time.Duration(i) * time.Second), Component: "web.main", Text: fmt.Sprintf("line %d", i)}
+		if err := store.Append("web.main", []Line{line}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := os.Stat(store.Path("web.main") + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 rotated files")
+	}
+
+	got := []string{}
+	Aggregate(context.Background(), []Source{store.Source("web.main")}, Filter{}, func(line Line) {
+		got = append(got, line.Text)
+	})
+
+	// the oldest rotated file was dropped
+	if len(got) != 2 || got[0] != "line 2" || got[1] != "line 3" {
+		t.Errorf("expected the last 2 lines, got %v", got)
+	}
+}
+
+func TestSinks(t *testing.T) {
+	line := Line{Time: time.Date(2017, 3, 2, 15, 4, 5, 0, time.UTC), Component: "web.main", Stream: StreamStderr, Text: "boom"}
+
+	expected := "<131>1 2017-03-02T15:04:05Z nanobox shop-local web.main - - boom"
+	if got := syslogMessage("shop-local", line); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	event := fmt.Sprintf("%x", fluentdEvent("shop", line))
+	if prefix := "93b56e616e6f626f782e73686f702e7765622e6d61696ece58b8346584"; event[:len(prefix)] != prefix {
+		t.Errorf("expected the event to start with %s, got %s", prefix, event)
+	}
+
+	sinks, err := ParseSinks("syslog://localhost, fluentd://localhost:24225,loki+https://logs.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sinks) != 3 || sinks[0].(*syslogSink).address != "localhost:514" || sinks[2].(*lokiSink).url != "https://logs.example.com:443/loki/api/v1/push" {
+		t.Errorf("unexpected sinks %+v", sinks)
+	}
+
+	for _, invalid := range []string{"localhost:514", "kafka://localhost:9092"} {
+		if _, err := ParseSink(invalid); err == nil {
+			t.Errorf("expected %s to be invalid", invalid)
+		}
+	}
+}
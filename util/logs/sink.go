@@ -0,0 +1,321 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dialTimeout is how long a sink has to accept a connection
+const dialTimeout = 5 * time.Second
+
+// Sink is somewhere the logs of an app are forwarded to, ie: the syslog,
+// fluentd or Loki of a team's production logging pipeline
+type Sink interface {
+	Send(app string, line Line) error
+	Close() error
+}
+
+// ParseSinks parses the sinks of the log-forward config, urls separated by
+// commas, ie: syslog://logs.local:514,loki://localhost:3100
+func ParseSinks(value string) ([]Sink, error) {
+	sinks := []Sink{}
+
+	for _, raw := range strings.Split(value, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		sink, err := ParseSink(raw)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	return sinks, nil
+}
+
+// ParseSink parses the url of a sink:
+//
+//	syslog://host:514        syslog over udp
+//	syslog+tcp://host:601    syslog over tcp
+//	fluentd://host:24224     fluentd's forward protocol
+//	loki://host:3100         loki's push api, loki+https:// over tls
+func ParseSink(raw string) (Sink, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("'%s' isn't a sink url, ie: syslog://localhost:514", raw)
+	}
+
+	switch u.Scheme {
+	case "syslog", "syslog+udp":
+		return &syslogSink{network: "udp", address: withPort(u.Host, "514")}, nil
+	case "syslog+tcp":
+		return &syslogSink{network: "tcp", address: withPort(u.Host, "601")}, nil
+	case "fluentd":
+		return &fluentdSink{address: withPort(u.Host, "24224")}, nil
+	case "loki", "loki+http":
+		return newLokiSink("http://" + withPort(u.Host, "3100")), nil
+	case "loki+https":
+		return newLokiSink("https://" + withPort(u.Host, "443")), nil
+	}
+
+	return nil, fmt.Errorf("'%s' isn't a kind of sink, use syslog, syslog+tcp, fluentd or loki", u.Scheme)
+}
+
+// withPort adds the default port to a host that has none
+func withPort(host, port string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+
+	return net.JoinHostPort(host, port)
+}
+
+// syslogSink sends each line as an rfc5424 message, framed by its length
+// over tcp
+type syslogSink struct {
+	network string
+	address string
+	conn    net.Conn
+}
+
+// Send sends a line, a connection that broke is dialed again
+func (s *syslogSink) Send(app string, line Line) error {
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.network, s.address, dialTimeout)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog at %s: %s", s.address, err.Error())
+		}
+		s.conn = conn
+	}
+
+	message := syslogMessage(app, line)
+	if s.network == "tcp" {
+		message = fmt.Sprintf("%d %s", len(message), message)
+	}
+
+	if _, err := s.conn.Write([]byte(message)); err != nil {
+		s.Close()
+		return fmt.Errorf("failed to send to syslog at %s: %s", s.address, err.Error())
+	}
+
+	return nil
+}
+
+// Close closes the connection
+func (s *syslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	s.conn = nil
+
+	return err
+}
+
+// syslogMessage formats a line as an rfc5424 message of the local0 facility,
+// the output on stderr is an error, ie:
+// <134>1 2017-03-02T15:04:05Z nanobox shop web.main - - Listening on 8080
+func syslogMessage(app string, line Line) string {
+	severity := 6 // info
+	if line.Stream == StreamStderr {
+		severity = 3 // err
+	}
+
+	return fmt.Sprintf("<%d>1 %s nanobox %s %s - - %s",
+		16*8+severity, line.Time.UTC().Format(time.RFC3339Nano), nilValue(app), nilValue(line.Component), line.Text)
+}
+
+// nilValue returns the value of a header field of a syslog message, a dash
+// when it's empty
+func nilValue(value string) string {
+	value = strings.Replace(value, " ", "_", -1)
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+// fluentdSink sends each line as an event of fluentd's forward protocol,
+// tagged nanobox.<app>.<component>
+type fluentdSink struct {
+	address string
+	conn    net.Conn
+}
+
+// Send sends a line, a connection that broke is dialed again
+func (s *fluentdSink) Send(app string, line Line) error {
+	if s.conn == nil {
+		conn, err := net.DialTimeout("tcp", s.address, dialTimeout)
+		if err != nil {
+			return fmt.Errorf("failed to connect to fluentd at %s: %s", s.address, err.Error())
+		}
+		s.conn = conn
+	}
+
+	if _, err := s.conn.Write(fluentdEvent(app, line)); err != nil {
+		s.Close()
+		return fmt.Errorf("failed to send to fluentd at %s: %s", s.address, err.Error())
+	}
+
+	return nil
+}
+
+// Close closes the connection
+func (s *fluentdSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	s.conn = nil
+
+	return err
+}
+
+// fluentdEvent encodes a line as the msgpack of a forward protocol message,
+// [tag, time, record]
+func fluentdEvent(app string, line Line) []byte {
+	buf := &bytes.Buffer{}
+
+	buf.WriteByte(0x93) // an array of 3
+	msgpackString(buf, fmt.Sprintf("nanobox.%s.%s", app, line.Component))
+
+	buf.WriteByte(0xce) // a uint32
+	binary.Write(buf, binary.BigEndian, uint32(line.Time.Unix()))
+
+	record := [][2]string{
+		{"app", app},
+		{"component", line.Component},
+		{"stream", line.Stream},
+		{"log", line.Text},
+	}
+	buf.WriteByte(0x80 | byte(len(record))) // a map
+	for _, pair := range record {
+		msgpackString(buf, pair[0])
+		msgpackString(buf, pair[1])
+	}
+
+	return buf.Bytes()
+}
+
+// msgpackString encodes a string as msgpack
+func msgpackString(buf *bytes.Buffer, s string) {
+	switch n := len(s); {
+	case n < 32:
+		buf.WriteByte(0xa0 | byte(n))
+	case n < 1<<8:
+		buf.WriteByte(0xd9)
+		buf.WriteByte(byte(n))
+	case n < 1<<16:
+		buf.WriteByte(0xda)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(0xdb)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+
+	buf.WriteString(s)
+}
+
+// lokiSink pushes the lines to loki in batches, a batch is pushed once it's
+// full or a second old
+type lokiSink struct {
+	url     string
+	client  *http.Client
+	batch   map[string]*lokiStream
+	size    int
+	started time.Time
+}
+
+// lokiStream is a stream of a loki push, the lines with the same labels
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"` // the time in nanoseconds and the line
+}
+
+// the size and the age of a batch when it's pushed
+const (
+	lokiBatchSize = 100
+	lokiBatchAge  = time.Second
+)
+
+// newLokiSink returns the sink of a loki server
+func newLokiSink(server string) *lokiSink {
+	return &lokiSink{
+		url:    server + "/loki/api/v1/push",
+		client: &http.Client{Timeout: 10 * time.Second},
+		batch:  map[string]*lokiStream{},
+	}
+}
+
+// Send adds a line to the batch, and pushes it if it's time
+func (s *lokiSink) Send(app string, line Line) error {
+	key := app + "/" + line.Component + "/" + line.Stream
+	stream, ok := s.batch[key]
+	if !ok {
+		stream = &lokiStream{Stream: map[string]string{"app": app, "component": line.Component, "stream": line.Stream, "source": "nanobox"}}
+		s.batch[key] = stream
+	}
+
+	stream.Values = append(stream.Values, [2]string{strconv.FormatInt(line.Time.UnixNano(), 10), line.Text})
+
+	if s.size == 0 {
+		s.started = time.Now()
+	}
+	s.size++
+
+	if s.size >= lokiBatchSize || time.Since(s.started) >= lokiBatchAge {
+		return s.push()
+	}
+
+	return nil
+}
+
+// Close pushes what's left in the batch
+func (s *lokiSink) Close() error {
+	return s.push()
+}
+
+// push pushes the batch to loki
+func (s *lokiSink) push() error {
+	if s.size == 0 {
+		return nil
+	}
+
+	streams := []*lokiStream{}
+	for _, stream := range s.batch {
+		streams = append(streams, stream)
+	}
+	s.batch = map[string]*lokiStream{}
+	s.size = 0
+
+	body, err := json.Marshal(map[string]interface{}{"streams": streams})
+	if err != nil {
+		return err
+	}
+
+	res, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to push to loki at %s: %s", s.url, err.Error())
+	}
+	res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("loki at %s responded with %s", s.url, res.Status)
+	}
+
+	return nil
+}
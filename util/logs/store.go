@@ -0,0 +1,176 @@
+package logs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/nanobox-io/nanobox/util/config"
+)
+
+// the size a log file of a component grows to before it's rotated, and how
+// many rotated files are kept, ie: web.main.log.1 to web.main.log.5
+const (
+	maxLogSize  = 10 << 20
+	keepRotated = 5
+)
+
+// AppDir returns the directory the logs of an app are kept in
+func AppDir(appID string) string {
+	return filepath.Join(config.GlobalDir(), "apps", appID, "logs")
+}
+
+// Store keeps the logs of the components of an app that outlive their
+// containers, a file of json lines per component
+type Store struct {
+	Dir     string
+	MaxSize int64
+	Keep    int
+}
+
+// NewStore returns the store of an app
+func NewStore(appID string) *Store {
+	return &Store{Dir: AppDir(appID), MaxSize: maxLogSize, Keep: keepRotated}
+}
+
+// Path returns the log file of a component
+func (s *Store) Path(component string) string {
+	return filepath.Join(s.Dir, component+".log")
+}
+
+// Append adds lines to the log file of a component, rotating it once it
+// grows over the maximum size
+func (s *Store) Append(component string, lines []Line) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.Path(component), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(f)
+	for _, line := range lines {
+		if err := encoder.Encode(line); err != nil {
+			f.Close()
+			return err
+		}
+	}
+
+	info, err := f.Stat()
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	if info.Size() > s.MaxSize {
+		return s.rotate(component)
+	}
+
+	return nil
+}
+
+// rotate moves the log file of a component to .1, the rotated files down by
+// one and drops the oldest
+func (s *Store) rotate(component string) error {
+	path := s.Path(component)
+
+	os.Remove(fmt.Sprintf("%s.%d", path, s.Keep))
+	for i := s.Keep - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1))
+	}
+
+	return os.Rename(path, path+".1")
+}
+
+// Source returns the source of the stored log of a component
+func (s *Store) Source(component string) Source {
+	return storeSource{store: s, component: component}
+}
+
+// storeSource reads the stored log of a component, the rotated files from
+// the oldest. The log only grows when a container is removed, so there is
+// nothing to follow.
+type storeSource struct {
+	store     *Store
+	component string
+}
+
+// Stream sends the stored lines since a time
+func (s storeSource) Stream(ctx context.Context, since time.Time, follow bool, out chan<- Line) error {
+	path := s.store.Path(s.component)
+
+	paths := []string{}
+	for i := s.store.Keep; i > 0; i-- {
+		paths = append(paths, fmt.Sprintf("%s.%d", path, i))
+	}
+	paths = append(paths, path)
+
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to open the stored log of %s: %s", s.component, err.Error())
+		}
+
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := Line{}
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil || line.Time.Before(since) {
+				continue
+			}
+			if !send(ctx, out, line) {
+				f.Close()
+				return nil
+			}
+		}
+		f.Close()
+	}
+
+	return nil
+}
+
+// Persist stores the output of a container and of its hooks with the logs
+// of its app before the container is removed, so they're still there once
+// it's recreated
+func Persist(appID, component, container string) error {
+	if container == "" {
+		return nil
+	}
+
+	sources := []Source{
+		ContainerSource{Component: component, Container: container},
+		HookSource{Component: component, Container: container},
+	}
+
+	// a container that's already gone still has its hook log
+	lines := []Line{}
+	err := Aggregate(context.Background(), sources, Filter{}, func(line Line) {
+		lines = append(lines, line)
+	})
+	if err != nil && !strings.Contains(err.Error(), "No such container") {
+		return err
+	}
+
+	if err := NewStore(appID).Append(component, lines); err != nil {
+		return fmt.Errorf("failed to store the logs of %s: %s", component, err.Error())
+	}
+
+	RemoveHookLog(container)
+
+	return nil
+}
@@ -0,0 +1,82 @@
+// Package metrics writes the resource usage of the components of an app in
+// the text format prometheus scrapes, for profiling an app's local
+// footprint.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Sample is the resource usage of a component
+type Sample struct {
+	App        string  `json:"app"`
+	Component  string  `json:"component"`
+	Up         bool    `json:"up"`
+	CPUPercent float64 `json:"cpu_percent"`
+	CPUSeconds float64 `json:"cpu_seconds"` // the cpu time used since it started
+	Memory     uint64  `json:"memory_bytes"`
+	MemLimit   uint64  `json:"memory_limit_bytes"`
+	NetRx      uint64  `json:"network_receive_bytes"`
+	NetTx      uint64  `json:"network_transmit_bytes"`
+	Restarts   int     `json:"restarts"`
+}
+
+// metric is a metric of every sample
+type metric struct {
+	name  string
+	kind  string // gauge or counter
+	help  string
+	value func(Sample) float64
+}
+
+// metrics are the metrics written for each component
+var metrics = []metric{
+	{"nanobox_component_up", "gauge", "Whether the container of the component is running.", func(s Sample) float64 {
+		if s.Up {
+			return 1
+		}
+		return 0
+	}},
+	{"nanobox_component_cpu_percent", "gauge", "The cpu the component used since the previous sample, in percent of one cpu.", func(s Sample) float64 { return s.CPUPercent }},
+	{"nanobox_component_cpu_seconds_total", "counter", "The cpu time the component used.", func(s Sample) float64 { return s.CPUSeconds }},
+	{"nanobox_component_memory_usage_bytes", "gauge", "The memory the component uses.", func(s Sample) float64 { return float64(s.Memory) }},
+	{"nanobox_component_memory_limit_bytes", "gauge", "The memory the component can use.", func(s Sample) float64 { return float64(s.MemLimit) }},
+	{"nanobox_component_network_receive_bytes_total", "counter", "The bytes the component received.", func(s Sample) float64 { return float64(s.NetRx) }},
+	{"nanobox_component_network_transmit_bytes_total", "counter", "The bytes the component sent.", func(s Sample) float64 { return float64(s.NetTx) }},
+	{"nanobox_component_restarts_total", "counter", "The times docker restarted the container of the component.", func(s Sample) float64 { return float64(s.Restarts) }},
+}
+
+// Write writes the samples in prometheus' text format
+func Write(w io.Writer, samples []Sample) error {
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind); err != nil {
+			return err
+		}
+
+		for _, sample := range samples {
+			_, err := fmt.Fprintf(w, "%s{app=\"%s\",component=\"%s\"} %s\n",
+				m.name, escape(sample.App), escape(sample.Component), formatValue(m.value(sample)))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// escape escapes the value of a label
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// formatValue formats a value without an exponent for the whole numbers
+func formatValue(value float64) string {
+	if value == float64(int64(value)) {
+		return fmt.Sprintf("%d", int64(value))
+	}
+
+	return fmt.Sprintf("%g", value)
+}
@@ -0,0 +1,37 @@
+package metrics_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/metrics"
+)
+
+func TestWrite(t *testing.T) {
+	samples := []metrics.Sample{
+		{App: "shop", Component: "web.main", Up: true, CPUPercent: 12.5, CPUSeconds: 3.25, Memory: 64 << 20, MemLimit: 1 << 30, NetRx: 2048, NetTx: 512, Restarts: 2},
+		{App: `sh"op`, Component: "data.db"},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := metrics.Write(buf, samples); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		"# TYPE nanobox_component_up gauge\n",
+		`nanobox_component_up{app="shop",component="web.main"} 1`,
+		`nanobox_component_up{app="sh\"op",component="data.db"} 0`,
+		`nanobox_component_cpu_percent{app="shop",component="web.main"} 12.5`,
+		`nanobox_component_memory_usage_bytes{app="shop",component="web.main"} 67108864`,
+		"# TYPE nanobox_component_restarts_total counter\n",
+		`nanobox_component_restarts_total{app="shop",component="web.main"} 2`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(out, line) {
+			t.Errorf("expected %q in:\n%s", line, out)
+		}
+	}
+}
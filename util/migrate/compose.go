@@ -0,0 +1,263 @@
+package migrate
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// composeFile is the subset of a docker-compose file that we translate
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+// composeService is a docker-compose service
+type composeService struct {
+	Image       string      `yaml:"image"`
+	Build       interface{} `yaml:"build"`
+	Command     interface{} `yaml:"command"`
+	Ports       []string    `yaml:"ports"`
+	Volumes     []string    `yaml:"volumes"`
+	Environment interface{} `yaml:"environment"`
+	EnvFile     interface{} `yaml:"env_file"`
+	DependsOn   []string    `yaml:"depends_on"`
+	Links       []string    `yaml:"links"`
+}
+
+// storageImage is the image of the data component the shared volumes of the
+// app are translated to
+const storageImage = "nanobox/unfs"
+
+// FromCompose translates a docker-compose file
+func FromCompose(source string, data []byte) (*Result, error) {
+	compose := composeFile{}
+	if err := yaml.Unmarshal(data, &compose); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", source, err.Error())
+	}
+
+	// version 1 files have the services at the top level
+	if len(compose.Services) == 0 {
+		services := map[string]composeService{}
+		if err := yaml.Unmarshal(data, &services); err == nil {
+			compose.Services = services
+		}
+	}
+
+	result := newResult(source)
+
+	// the node each service is translated to, for the depends_on
+	nodes := map[string]string{}
+
+	for _, name := range sortedServices(compose.Services) {
+		service := compose.Services[name]
+
+		switch {
+		case service.Build != nil:
+			// services built from the project are the app itself
+			translateAppService(result, name, service)
+			nodes[name] = "web." + name
+		case service.Image != "":
+			if image, ok := serviceImage(service.Image); ok {
+				result.Data[name] = image
+				nodes[name] = "data." + name
+				if service.Environment != nil {
+					result.note("data.%s: environment settings of '%s' were not carried over; nanobox generates credentials and exposes them as evars", name, service.Image)
+				}
+				continue
+			}
+
+			// anything else runs next to the app
+			result.Sidecars[name] = Sidecar{Image: service.Image, Start: composeCommand(service.Command)}
+			nodes[name] = "sidecar." + name
+			result.note("sidecar.%s: no nanobox data component for image '%s', it runs as a sidecar and its data isn't kept between rebuilds", name, service.Image)
+			if len(service.Ports) > 0 {
+				result.note("sidecar.%s: ports %s are not published, the app reaches the sidecar on its own ports", name, strings.Join(service.Ports, ", "))
+			}
+		default:
+			result.note("%s: has neither an image nor a build, skipped", name)
+		}
+	}
+
+	// the depends_on of the services that were translated
+	for _, name := range sortedServices(compose.Services) {
+		node, ok := nodes[name]
+		if !ok {
+			continue
+		}
+
+		for _, dependency := range compose.Services[name].DependsOn {
+			if other, ok := nodes[dependency]; ok && !strings.HasPrefix(other, "web.") {
+				result.DependsOn[node] = append(result.DependsOn[node], other)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// translateAppService maps a service built from the project to a web
+// component
+func translateAppService(result *Result, name string, service composeService) {
+	command := composeCommand(service.Command)
+	if command == "" {
+		result.note("web.%s: no command found, set the start command", name)
+		command = "echo 'set the start command in boxfile.yml'"
+	}
+	result.Web[name] = command
+
+	for _, port := range service.Ports {
+		mapped, ok := composePort(port)
+		if !ok {
+			result.note("web.%s: port '%s' was not migrated", name, port)
+			continue
+		}
+		result.Ports[name] = append(result.Ports[name], mapped)
+	}
+	if len(service.Ports) > 0 {
+		result.note("web.%s: ports %s were published as %s; nanobox also routes http traffic to port 8080 inside the component", name, strings.Join(service.Ports, ", "), strings.Join(result.Ports[name], ", "))
+	}
+
+	for key, value := range composeEnvironment(service.Environment) {
+		result.Evars[key] = value
+	}
+
+	if service.EnvFile != nil {
+		result.note("web.%s: env_file %v was not migrated; load it with 'nanobox evar load local <file>'", name, service.EnvFile)
+	}
+
+	for _, volume := range service.Volumes {
+		dir, ok := composeVolume(volume)
+		switch {
+		case ok && dir == "":
+			// the code mount is handled by nanobox
+		case ok:
+			// the directories the app writes to are shared through a
+			// storage component
+			result.NetworkDirs[name] = append(result.NetworkDirs[name], dir)
+			if _, exists := result.Data["storage"]; !exists {
+				result.Data["storage"] = storageImage
+			}
+		default:
+			result.note("web.%s: volume '%s' was not migrated, only the directories in /app can be shared", name, volume)
+		}
+	}
+}
+
+// composePort translates a compose port to a nanobox port, ie:
+// '127.0.0.1:2222:22/tcp' -> 'tcp:2222:22'
+func composePort(port string) (string, bool) {
+	protocol := "tcp"
+	if i := strings.Index(port, "/"); i != -1 {
+		protocol = port[i+1:]
+		port = port[:i]
+	}
+
+	parts := strings.Split(port, ":")
+	switch len(parts) {
+	case 1:
+		parts = []string{parts[0], parts[0]}
+	case 3:
+		// the interface is always the env ip
+		parts = parts[1:]
+	}
+
+	if len(parts) != 2 || strings.Contains(port, "-") || !isNumber(parts[0]) || !isNumber(parts[1]) {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s:%s:%s", protocol, parts[0], parts[1]), true
+}
+
+// composeVolume returns the directory of the app a volume is mounted at, ie:
+// 'uploads:/app/public/uploads' -> 'public/uploads'. The directory is empty
+// for the code itself.
+func composeVolume(volume string) (string, bool) {
+	parts := strings.Split(volume, ":")
+	if len(parts) < 2 {
+		return "", false
+	}
+
+	// the code mount
+	if parts[0] == "." || parts[0] == "./" {
+		return "", true
+	}
+
+	target := path.Clean(parts[1])
+	if !strings.HasPrefix(target, "/app/") {
+		return "", false
+	}
+
+	return strings.TrimPrefix(target, "/app/"), true
+}
+
+// composeEnvironment returns the environment of a service, compose allows
+// both a map and a list of KEY=VALUE
+func composeEnvironment(environment interface{}) map[string]string {
+	evars := map[string]string{}
+
+	switch e := environment.(type) {
+	case map[interface{}]interface{}:
+		for key, value := range e {
+			if value == nil {
+				value = ""
+			}
+			evars[fmt.Sprintf("%v", key)] = fmt.Sprintf("%v", value)
+		}
+	case []interface{}:
+		for _, item := range e {
+			parts := strings.SplitN(fmt.Sprintf("%v", item), "=", 2)
+			if len(parts) == 1 {
+				parts = append(parts, "")
+			}
+			evars[parts[0]] = parts[1]
+		}
+	}
+
+	return evars
+}
+
+// composeCommand returns the command as a string, compose allows both a
+// string and a list
+func composeCommand(command interface{}) string {
+	switch c := command.(type) {
+	case string:
+		return c
+	case []interface{}:
+		parts := []string{}
+		for _, part := range c {
+			parts = append(parts, fmt.Sprintf("%v", part))
+		}
+		return strings.Join(parts, " ")
+	}
+
+	return ""
+}
+
+// isNumber returns true if s is a number
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortedServices returns the service names in order
+func sortedServices(services map[string]composeService) []string {
+	names := []string{}
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
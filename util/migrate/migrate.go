@@ -0,0 +1,249 @@
+// Package migrate translates Vagrant and docker-compose projects into a
+// boxfile, collecting the things it couldn't translate into a report.
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Result is the outcome of a migration
+type Result struct {
+	Source       string              // the file that was migrated
+	Engine       string              // the engine for run.config
+	Packages     []string            // extra_packages for run.config
+	Steps        []string            // extra_steps for run.config
+	Web          map[string]string   // web component name -> start command
+	Ports        map[string][]string // web component name -> ports, ie: tcp:2222:22
+	NetworkDirs  map[string][]string // web component name -> dirs shared through data.storage
+	Data         map[string]string   // data component name -> image
+	Sidecars     map[string]Sidecar  // sidecar name -> sidecar
+	DependsOn    map[string][]string // node -> the nodes it depends on
+	Evars        map[string]string   // the environment of the app, see EnvFile
+	Untranslated []string            // notes for the migration report
+}
+
+// Sidecar is a service without a nanobox data component
+type Sidecar struct {
+	Image string
+	Start string // the command of the image if empty
+}
+
+// newResult creates an empty Result
+func newResult(source string) *Result {
+	return &Result{
+		Source:      source,
+		Web:         map[string]string{},
+		Ports:       map[string][]string{},
+		NetworkDirs: map[string][]string{},
+		Data:        map[string]string{},
+		Sidecars:    map[string]Sidecar{},
+		DependsOn:   map[string][]string{},
+		Evars:       map[string]string{},
+	}
+}
+
+// note adds a line to the migration report
+func (r *Result) note(format string, args ...interface{}) {
+	r.Untranslated = append(r.Untranslated, fmt.Sprintf(format, args...))
+}
+
+// serviceImages maps well known service names to the nanobox data images
+var serviceImages = map[string]string{
+	"postgres":      "nanobox/postgresql",
+	"postgresql":    "nanobox/postgresql",
+	"mysql":         "nanobox/mysql",
+	"mysql-server":  "nanobox/mysql",
+	"mariadb":       "nanobox/mysql",
+	"redis":         "nanobox/redis",
+	"redis-server":  "nanobox/redis",
+	"mongo":         "nanobox/mongodb",
+	"mongodb":       "nanobox/mongodb",
+	"mongodb-org":   "nanobox/mongodb",
+	"memcached":     "nanobox/memcached",
+	"elasticsearch": "nanobox/elasticsearch",
+}
+
+// engineFiles maps files that identify a project's language to an engine
+var engineFiles = []struct {
+	file   string
+	engine string
+}{
+	{"package.json", "nodejs"},
+	{"Gemfile", "ruby"},
+	{"requirements.txt", "python"},
+	{"setup.py", "python"},
+	{"composer.json", "php"},
+	{"mix.exs", "elixir"},
+	{"pom.xml", "java"},
+	{"build.gradle", "java"},
+	{"Godeps", "golang"},
+	{"glide.yaml", "golang"},
+	{"vendor/vendor.json", "golang"},
+}
+
+// DetectEngine guesses the engine from the files in the project directory
+func DetectEngine(dir string) string {
+	for _, engineFile := range engineFiles {
+		if _, err := os.Stat(filepath.Join(dir, engineFile.file)); err == nil {
+			return engineFile.engine
+		}
+	}
+
+	return ""
+}
+
+// Boxfile renders the boxfile for the result
+func (r *Result) Boxfile() string {
+	lines := []string{"run.config:"}
+
+	if r.Engine != "" {
+		lines = append(lines, fmt.Sprintf("  engine: %s", r.Engine))
+	} else {
+		lines = append(lines, "  # TODO: set the engine for your language, see https://docs.nanobox.io/engines/")
+		lines = append(lines, "  engine: none")
+	}
+
+	if len(r.Packages) > 0 {
+		lines = append(lines, "  extra_packages:")
+		for _, pkg := range r.Packages {
+			lines = append(lines, fmt.Sprintf("    - %s", pkg))
+		}
+	}
+
+	if len(r.Steps) > 0 {
+		lines = append(lines, "  extra_steps:")
+		for _, step := range r.Steps {
+			lines = append(lines, fmt.Sprintf("    - %s", quote(step)))
+		}
+	}
+
+	for _, name := range sortedKeys(r.Web) {
+		lines = append(lines, "", fmt.Sprintf("web.%s:", name))
+		lines = append(lines, fmt.Sprintf("  start: %s", quote(r.Web[name])))
+		lines = append(lines, listLines("ports", r.Ports[name])...)
+		if dirs := r.NetworkDirs[name]; len(dirs) > 0 {
+			lines = append(lines, "  network_dirs:", "    data.storage:")
+			for _, dir := range dirs {
+				lines = append(lines, fmt.Sprintf("      - %s", quote(dir)))
+			}
+		}
+		lines = append(lines, listLines("depends_on", r.DependsOn["web."+name])...)
+	}
+
+	for _, name := range sortedKeys(r.Data) {
+		lines = append(lines, "", fmt.Sprintf("data.%s:", name))
+		lines = append(lines, fmt.Sprintf("  image: %s", r.Data[name]))
+		lines = append(lines, listLines("depends_on", r.DependsOn["data."+name])...)
+	}
+
+	names := []string{}
+	for name := range r.Sidecars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		lines = append(lines, "", fmt.Sprintf("sidecar.%s:", name))
+		lines = append(lines, fmt.Sprintf("  image: %s", r.Sidecars[name].Image))
+		if start := r.Sidecars[name].Start; start != "" {
+			lines = append(lines, fmt.Sprintf("  start: %s", quote(start)))
+		}
+		lines = append(lines, listLines("depends_on", r.DependsOn["sidecar."+name])...)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// EnvFile renders the evars of the result as a dotenv file, for
+// 'nanobox evar load'
+func (r *Result) EnvFile() string {
+	lines := []string{}
+	for _, key := range sortedKeys(r.Evars) {
+		lines = append(lines, fmt.Sprintf("%s=%s", key, r.Evars[key]))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Report renders the migration report
+func (r *Result) Report() string {
+	lines := []string{
+		fmt.Sprintf("Nanobox migration report for %s", r.Source),
+		"",
+	}
+
+	if len(r.Untranslated) == 0 {
+		lines = append(lines, "Everything was translated. Review the boxfile.yml before starting.")
+		return strings.Join(lines, "\n") + "\n"
+	}
+
+	lines = append(lines, "The following could not be translated automatically and need your attention:", "")
+	for _, note := range r.Untranslated {
+		lines = append(lines, fmt.Sprintf("  - %s", note))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// serviceImage finds the nanobox image for a service name or image, ie:
+// 'postgres:9.5' -> 'nanobox/postgresql:9.5'
+func serviceImage(image string) (string, bool) {
+	name, tag := image, ""
+	if i := strings.LastIndex(image, ":"); i != -1 && !strings.Contains(image[i:], "/") {
+		name, tag = image[:i], image[i+1:]
+	}
+
+	// drop the registry and namespace, ie: library/postgres
+	name = name[strings.LastIndex(name, "/")+1:]
+
+	nanoboxImage, ok := serviceImages[name]
+	if !ok {
+		return "", false
+	}
+
+	// only carry over numeric versions, nanobox doesn't publish variants
+	if tag != "" && tag[0] >= '0' && tag[0] <= '9' {
+		nanoboxImage = fmt.Sprintf("%s:%s", nanoboxImage, tag)
+	}
+
+	return nanoboxImage, true
+}
+
+// quote quotes a yaml scalar when it contains characters yaml would treat
+// specially
+func quote(s string) string {
+	if strings.ContainsAny(s, ":#{}[]&*!|>'\"%@`,") || strings.HasPrefix(s, "-") {
+		return fmt.Sprintf("'%s'", strings.Replace(s, "'", "''", -1))
+	}
+
+	return s
+}
+
+// listLines renders a list key of a node, nothing if the list is empty
+func listLines(key string, items []string) []string {
+	if len(items) == 0 {
+		return nil
+	}
+
+	lines := []string{fmt.Sprintf("  %s:", key)}
+	for _, item := range items {
+		lines = append(lines, fmt.Sprintf("    - %s", quote(item)))
+	}
+
+	return lines
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]string) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
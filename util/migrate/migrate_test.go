@@ -0,0 +1,136 @@
+package migrate_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/migrate"
+)
+
+func TestFromCompose(t *testing.T) {
+	compose := `
+version: '2'
+services:
+  app:
+    build: .
+    command: npm start
+    ports:
+      - "3000:3000"
+  db:
+    image: postgres:9.5
+  queue:
+    image: rabbitmq
+`
+
+	result, err := migrate.FromCompose("docker-compose.yml", []byte(compose))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Web["app"] != "npm start" {
+		t.Errorf("expected web.app to start with 'npm start', got '%s'", result.Web["app"])
+	}
+
+	if result.Data["db"] != "nanobox/postgresql:9.5" {
+		t.Errorf("expected data.db to use nanobox/postgresql:9.5, got '%s'", result.Data["db"])
+	}
+
+	if _, ok := result.Data["queue"]; ok {
+		t.Errorf("rabbitmq has no nanobox image and should not be migrated")
+	}
+
+	if !strings.Contains(result.Report(), "rabbitmq") || !strings.Contains(result.Report(), "3000:3000") {
+		t.Errorf("report is missing untranslated items:\n%s", result.Report())
+	}
+}
+
+func TestFromComposeServices(t *testing.T) {
+	compose := `
+version: '3'
+services:
+  app:
+    build: .
+    command: ["bundle", "exec", "puma"]
+    ports:
+      - "127.0.0.1:2222:22"
+      - "53:53/udp"
+    volumes:
+      - .:/app
+      - uploads:/app/public/uploads
+      - /var/run/docker.sock:/var/run/docker.sock
+    environment:
+      - RAILS_ENV=development
+      - SECRET
+    depends_on:
+      - db
+      - mail
+  db:
+    image: postgres:9.6
+  mail:
+    image: mailhog/mailhog
+    depends_on:
+      - db
+`
+
+	result, err := migrate.FromCompose("docker-compose.yml", []byte(compose))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	boxfile := result.Boxfile()
+	for _, expected := range []string{
+		"web.app:\n  start: bundle exec puma\n  ports:\n    - 'tcp:2222:22'\n    - 'udp:53:53'\n",
+		"  network_dirs:\n    data.storage:\n      - public/uploads\n",
+		"  depends_on:\n    - data.db\n    - sidecar.mail\n",
+		"data.storage:\n  image: nanobox/unfs\n",
+		"sidecar.mail:\n  image: mailhog/mailhog\n  depends_on:\n    - data.db\n",
+	} {
+		if !strings.Contains(boxfile, expected) {
+			t.Errorf("boxfile is missing %q:\n%s", expected, boxfile)
+		}
+	}
+
+	if result.EnvFile() != "RAILS_ENV=development\nSECRET=\n" {
+		t.Errorf("unexpected evars:\n%s", result.EnvFile())
+	}
+
+	if !strings.Contains(result.Report(), "/var/run/docker.sock") {
+		t.Errorf("report is missing the volume that couldn't be migrated:\n%s", result.Report())
+	}
+}
+
+func TestFromVagrantfile(t *testing.T) {
+	vagrantfile := `
+Vagrant.configure("2") do |config|
+  config.vm.box = "ubuntu/trusty64"
+  config.vm.network "forwarded_port", guest: 80, host: 8080
+  config.vm.provision "shell", inline: <<-SHELL
+    sudo apt-get update
+    sudo apt-get install -y imagemagick redis-server
+    bundle install
+  SHELL
+end
+`
+
+	result, err := migrate.FromVagrantfile("Vagrantfile", []byte(vagrantfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Packages) != 1 || result.Packages[0] != "imagemagick" {
+		t.Errorf("expected imagemagick in extra_packages, got %v", result.Packages)
+	}
+
+	if result.Data["cache"] != "nanobox/redis" {
+		t.Errorf("expected a redis data component, got %v", result.Data)
+	}
+
+	if len(result.Steps) != 1 || result.Steps[0] != "bundle install" {
+		t.Errorf("expected 'bundle install' in extra_steps, got %v", result.Steps)
+	}
+
+	boxfile := result.Boxfile()
+	if !strings.Contains(boxfile, "data.cache:\n  image: nanobox/redis") {
+		t.Errorf("boxfile is missing the redis component:\n%s", boxfile)
+	}
+}
@@ -0,0 +1,110 @@
+package migrate
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// inline shell provisioners, either a heredoc or a quoted string
+	heredocProvision = regexp.MustCompile(`(?s)inline:\s*<<-?['"]?(\w+)['"]?\s*\n(.*?)\n\s*(\w+)\s*\n`)
+	stringProvision  = regexp.MustCompile(`inline:\s*"([^"]*)"`)
+
+	// provisioners we can't follow
+	otherProvision = regexp.MustCompile(`vm\.provision\s+:?"?(\w+)"?`)
+
+	// port forwards and synced folders
+	forwardedPort = regexp.MustCompile(`forwarded_port.*guest:\s*(\d+).*host:\s*(\d+)`)
+	syncedFolder  = regexp.MustCompile(`synced_folder\s+"([^"]+)",\s*"([^"]+)"`)
+
+	// package installs
+	aptInstall = regexp.MustCompile(`(?:apt-get|apt|yum)\s+(?:-\S+\s+)*install\s+(.*)`)
+)
+
+// FromVagrantfile translates the provisioning of a Vagrantfile
+func FromVagrantfile(source string, data []byte) (*Result, error) {
+	vagrantfile := string(data)
+	result := newResult(source)
+
+	script := []string{}
+	for _, match := range heredocProvision.FindAllStringSubmatch(vagrantfile, -1) {
+		script = append(script, strings.Split(match[2], "\n")...)
+	}
+	for _, match := range stringProvision.FindAllStringSubmatch(vagrantfile, -1) {
+		script = append(script, strings.Split(match[1], ";")...)
+	}
+
+	for _, match := range otherProvision.FindAllStringSubmatch(vagrantfile, -1) {
+		if match[1] != "shell" {
+			result.note("the %s provisioner is not supported, translate it to extra_steps by hand", match[1])
+		}
+	}
+
+	if strings.Contains(vagrantfile, "path:") {
+		result.note("external provisioning scripts (path:) were not read, add their steps to extra_steps")
+	}
+
+	for _, line := range script {
+		translateShellLine(result, strings.TrimSpace(line))
+	}
+
+	for _, match := range forwardedPort.FindAllStringSubmatch(vagrantfile, -1) {
+		result.note("port forward %s -> %s was not migrated; nanobox routes http traffic to port 8080 and gives each app its own IP", match[2], match[1])
+	}
+
+	for _, match := range syncedFolder.FindAllStringSubmatch(vagrantfile, -1) {
+		if match[1] == "." {
+			continue // nanobox mounts the project at /app
+		}
+		result.note("synced folder %s -> %s was not migrated", match[1], match[2])
+	}
+
+	return result, nil
+}
+
+// translateShellLine maps a provisioning command to boxfile constructs
+func translateShellLine(result *Result, line string) {
+	line = strings.TrimPrefix(line, "sudo ")
+
+	switch {
+	case line == "" || strings.HasPrefix(line, "#"):
+		return
+	case strings.Contains(line, "apt-get update") || strings.Contains(line, "yum update"):
+		// nanobox manages the package index
+		return
+	case aptInstall.MatchString(line):
+		packages := strings.Fields(aptInstall.FindStringSubmatch(line)[1])
+		for _, pkg := range packages {
+			if strings.HasPrefix(pkg, "-") {
+				continue
+			}
+			if image, ok := serviceImage(pkg); ok {
+				result.Data[dataName(image)] = image
+				continue
+			}
+			result.Packages = append(result.Packages, pkg)
+		}
+		result.note("extra_packages come from pkgsrc, check that the names match: %s", strings.Join(packages, " "))
+	case strings.HasPrefix(line, "service ") || strings.HasPrefix(line, "systemctl "):
+		result.note("'%s' was dropped; nanobox starts data services itself", line)
+	default:
+		result.Steps = append(result.Steps, line)
+	}
+}
+
+// dataName names a data component after its image, ie: nanobox/postgresql -> db
+func dataName(image string) string {
+	switch {
+	case strings.Contains(image, "postgresql"), strings.Contains(image, "mysql"), strings.Contains(image, "mongodb"):
+		return "db"
+	case strings.Contains(image, "redis"), strings.Contains(image, "memcached"):
+		return "cache"
+	}
+
+	name := image[strings.LastIndex(image, "/")+1:]
+	if i := strings.Index(name, ":"); i != -1 {
+		name = name[:i]
+	}
+
+	return name
+}
@@ -0,0 +1,67 @@
+// Package notify shows a desktop notification when a command that took a
+// while finishes or fails, for the users that tabbed away while it ran. It's
+// off unless the notify-after config is set, to the seconds a command has to
+// take before it's worth a notification.
+package notify
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+)
+
+// Title is the title of the notifications
+const Title = "nanobox"
+
+var (
+	command string
+	started time.Time
+	once    sync.Once
+)
+
+// Start begins timing a command
+func Start(name string) {
+	command = name
+	started = time.Now()
+}
+
+// Finish shows a notification if the command took longer than the
+// notify-after config. Only the first call counts, so a command that hands
+// over to the user, ie: a console, calls it once it's ready.
+func Finish(failed bool) {
+	once.Do(func() {
+		if command == "" {
+			return
+		}
+
+		conf, _ := models.LoadConfig()
+		took := time.Since(started)
+		if conf.NotifyAfter <= 0 || conf.CIMode || took < time.Duration(conf.NotifyAfter)*time.Second {
+			return
+		}
+
+		if err := Send(Title, Message(command, failed, took)); err != nil {
+			lumber.Debug("notify:Send(): %s", err.Error())
+		}
+	})
+}
+
+// Send shows a desktop notification
+func Send(title, message string) error {
+	return send(title, message)
+}
+
+// Message returns the text of the notification of a command, ie: "nanobox
+// build finished after 4m12s"
+func Message(command string, failed bool, took time.Duration) string {
+	result := "finished"
+	if failed {
+		result = "failed"
+	}
+
+	return fmt.Sprintf("nanobox %s %s after %s", command, result, took/time.Second*time.Second)
+}
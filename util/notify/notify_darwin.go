@@ -0,0 +1,21 @@
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+func send(title, message string) error {
+	// the text has no control characters, so go's quoting is valid
+	// applescript
+	script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(message), strconv.Quote(title))
+
+	out, err := exec.Command("osascript", "-e", script).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("osascript failed: %s", strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
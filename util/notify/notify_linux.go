@@ -0,0 +1,26 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+func send(title, message string) error {
+	// there is nothing to show it on without a desktop session
+	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return fmt.Errorf("no desktop session")
+	}
+
+	if _, err := exec.LookPath("notify-send"); err != nil {
+		return fmt.Errorf("notify-send isn't installed")
+	}
+
+	out, err := exec.Command("notify-send", "--app-name", Title, title, message).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("notify-send failed: %s", strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
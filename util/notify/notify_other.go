@@ -0,0 +1,11 @@
+// +build !darwin,!linux,!windows
+
+package notify
+
+import (
+	"fmt"
+)
+
+func send(title, message string) error {
+	return fmt.Errorf("desktop notifications aren't supported on this os")
+}
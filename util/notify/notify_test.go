@@ -0,0 +1,25 @@
+package notify_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nanobox-io/nanobox/util/notify"
+)
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		failed   bool
+		took     time.Duration
+		expected string
+	}{
+		{false, 4*time.Minute + 12*time.Second + 300*time.Millisecond, "nanobox build finished after 4m12s"},
+		{true, 95 * time.Second, "nanobox build failed after 1m35s"},
+	}
+
+	for _, test := range tests {
+		if got := notify.Message("build", test.failed, test.took); got != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, got)
+		}
+	}
+}
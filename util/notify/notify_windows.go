@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// balloonScript shows a balloon tip from the notification area, it's
+// shown for as long as the powershell that shows it runs
+const balloonScript = `
+Add-Type -AssemblyName System.Windows.Forms
+$icon = New-Object System.Windows.Forms.NotifyIcon
+$icon.Icon = [System.Drawing.SystemIcons]::Information
+$icon.Visible = $true
+$icon.ShowBalloonTip(10000, '%s', '%s', 'Info')
+Start-Sleep -Seconds 10
+$icon.Dispose()
+`
+
+func send(title, message string) error {
+	script := fmt.Sprintf(balloonScript, quote(title), quote(message))
+
+	// it's not waited for, the command exits while it's shown
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-WindowStyle", "Hidden", "-Command", script)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run powershell: %s", err.Error())
+	}
+
+	return nil
+}
+
+// quote escapes a powershell single quoted string
+func quote(s string) string {
+	return strings.Replace(s, "'", "''", -1)
+}
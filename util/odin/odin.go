@@ -30,6 +30,15 @@ var (
 	// set the default endpoint to nanobox
 	endpoint = "nanobox"
 	apiKey   string
+
+	// token replaces the stored login, see SetToken
+	token string
+
+	// Reauthenticate logs the user in again when their token expired or was
+	// revoked and couldn't be refreshed. The login processor sets it.
+	Reauthenticate = func(endpoint string) error {
+		return SessionExpired()
+	}
 )
 
 type (
@@ -38,6 +47,16 @@ type (
 		Key   string `json:"title"`
 		Value string `json:"value"`
 	}
+
+	// APIToken is a token with a limited scope, for machines like ci
+	APIToken struct {
+		ID        string `json:"id"`
+		Name      string `json:"name"`
+		Scope     string `json:"scope"`
+		AppID     string `json:"app_id,omitempty"`
+		Token     string `json:"token,omitempty"` // only returned when it is created
+		CreatedAt string `json:"created_at"`
+	}
 )
 
 // sets the odin endpoint
@@ -45,6 +64,21 @@ func SetEndpoint(stage string) {
 	endpoint = stage
 }
 
+// SetToken makes every request use token instead of the stored login, so ci
+// pipelines don't need to log in. NANOBOX_TOKEN does the same.
+func SetToken(t string) {
+	token = t
+}
+
+// Token returns the token that replaces the stored login, if there is one
+func Token() string {
+	if token != "" {
+		return token
+	}
+
+	return os.Getenv("NANOBOX_TOKEN")
+}
+
 // Auth authenticates the user with odin.
 func Auth(username, password string) (string, error) {
 
@@ -62,6 +96,31 @@ func Auth(username, password string) (string, error) {
 	return resBody["authentication_token"], nil
 }
 
+// Refresh exchanges a refresh token for a new auth token, the refresh token
+// may be rotated as well
+func Refresh(refreshToken string) (string, string, error) {
+	body := map[string]string{"refresh_token": refreshToken}
+	resBody := map[string]string{}
+
+	// straight to request, a rejected refresh token must not refresh again
+	if err := request("POST", "user_auth_token/refresh", nil, body, &resBody); err != nil {
+		return "", "", err
+	}
+
+	return resBody["authentication_token"], resBody["refresh_token"], nil
+}
+
+// SessionExpired is the error of a request made with a token that expired or
+// was revoked
+func SessionExpired() error {
+	err := util.ErrorfQuiet("[USER] Your nanobox session expired or was revoked")
+	if err2, ok := err.(util.Err); ok {
+		err2.Suggest = "Run `nanobox login` and try again"
+		return err2
+	}
+	return err
+}
+
 // App ...
 func App(slug string) (models.App, error) {
 	app := models.App{}
@@ -162,6 +221,33 @@ func RemoveEvar(appID, id string) error {
 	return doRequest("DELETE", fmt.Sprintf("apps/%s/evars/%s", appID, id), params, nil, nil)
 }
 
+// CreateToken mints an api token limited to a scope and, optionally, an app
+func CreateToken(name, scope, appID string) (APIToken, error) {
+	token := APIToken{}
+
+	body := map[string]map[string]string{
+		"api_token": {
+			"name":   name,
+			"scope":  scope,
+			"app_id": appID,
+		},
+	}
+
+	return token, doRequest("POST", "api_tokens", nil, body, &token)
+}
+
+// ListTokens lists the api tokens of the user
+func ListTokens() ([]APIToken, error) {
+	tokens := []APIToken{}
+
+	return tokens, doRequest("GET", "api_tokens", nil, nil, &tokens)
+}
+
+// RevokeToken revokes an api token
+func RevokeToken(id string) error {
+	return doRequest("DELETE", fmt.Sprintf("api_tokens/%s", id), nil, nil, nil)
+}
+
 // EstablishTunnel requests a tunnel from odin.
 func EstablishTunnel(tunCfg models.TunnelConfig) (models.TunnelInfo, error) {
 	r := models.TunnelInfo{Port: tunCfg.DestPort}
@@ -322,8 +408,84 @@ func SubmitEvent(action, message, app string, meta map[string]interface{}) error
 	return nil
 }
 
-// doRequest ...
+// unauthorized is the body of a 401 response
+type unauthorized []byte
+
+func (u unauthorized) Error() string {
+	return fmt.Sprintf("Unauthorized (%s)", []byte(u))
+}
+
+// doRequest makes a request to odin. When the token is rejected it is
+// refreshed, or the user logs in again, and the request is retried once.
 func doRequest(method, path string, params url.Values, requestBody, responseBody interface{}) error {
+	err := request(method, path, params, requestBody, responseBody)
+
+	if _, ok := err.(unauthorized); ok && path != "user_auth_token" {
+		renewed, rerr := renewAuth()
+		if rerr != nil {
+			return rerr
+		}
+		if renewed {
+			err = request(method, path, params, requestBody, responseBody)
+		}
+	}
+
+	if b, ok := err.(unauthorized); ok {
+		err = util.ErrorfQuiet("[AUTH] Unauthorized (%s)", []byte(b))
+		if err2, ok := err.(util.Err); ok {
+			err2.Suggest = "It appears you are not logged in, run `nanobox login` and try again"
+			return err2
+		}
+	}
+
+	return err
+}
+
+// renewAuth gets a new token with the refresh token of the endpoint, or has
+// the user log in again. There is nothing to renew if they never logged in.
+func renewAuth() (bool, error) {
+	// a token that was handed to us can't be renewed
+	if Token() != "" {
+		err := util.ErrorfQuiet("[USER] The nanobox token was rejected")
+		if err2, ok := err.(util.Err); ok {
+			err2.Suggest = "Check the token in NANOBOX_TOKEN or --token, it may have expired or been revoked"
+			return false, err2
+		}
+		return false, err
+	}
+
+	auth, _ := models.LoadAuthByEndpoint(endpoint)
+	if auth.Key == "" {
+		return false, nil
+	}
+
+	if auth.RefreshKey != "" {
+		token, refreshToken, err := Refresh(auth.RefreshKey)
+		if err == nil && token != "" {
+			auth.Key = token
+			if refreshToken != "" {
+				auth.RefreshKey = refreshToken
+			}
+			return true, auth.Save()
+		}
+		lumber.Debug("odin:renewAuth:Refresh(): %v", err)
+	}
+
+	// credentials in the environment are used without asking
+	if os.Getenv("NANOBOX_USERNAME") != "" && os.Getenv("NANOBOX_PASSWORD") != "" {
+		token, err := Auth(os.Getenv("NANOBOX_USERNAME"), os.Getenv("NANOBOX_PASSWORD"))
+		if err == nil && token != "" {
+			auth.Key = token
+			auth.RefreshKey = ""
+			return true, auth.Save()
+		}
+	}
+
+	return true, Reauthenticate(endpoint)
+}
+
+// request makes a single request to odin
+func request(method, path string, params url.Values, requestBody, responseBody interface{}) error {
 
 	var rbodyReader io.Reader
 
@@ -337,6 +499,9 @@ func doRequest(method, path string, params url.Values, requestBody, responseBody
 	}
 
 	auth, _ := models.LoadAuthByEndpoint(endpoint)
+	if Token() != "" {
+		auth = &models.Auth{Endpoint: endpoint, Key: Token()}
+	}
 
 	// if they have not logged in but the user name and password are both set
 	// use attempt to authenticate
@@ -382,14 +547,7 @@ func doRequest(method, path string, params url.Values, requestBody, responseBody
 	}
 
 	if res.StatusCode == 401 {
-		err = util.ErrorfQuiet("[USER] Unauthorized (%s)", b)
-		if err != nil {
-			if err2, ok := err.(util.Err); ok {
-				err2.Suggest = "It appears you are not logged in, run `nanobox login` and try again"
-				return err2
-			}
-		}
-		return err
+		return unauthorized(b)
 	}
 
 	if res.StatusCode == 404 {
@@ -437,6 +595,19 @@ func doRequest(method, path string, params url.Values, requestBody, responseBody
 	return nil
 }
 
+// AuthURL returns the dashboard page that signs the user in, through the
+// single sign-on of their org if it has one, and sends the token to the
+// callback
+func AuthURL(callback, state string) string {
+	dashboard := strings.Replace(strings.TrimSuffix(odinURL(), "v1/"), "://api.", "://dashboard.", 1)
+
+	params := url.Values{}
+	params.Set("callback", callback)
+	params.Set("state", state)
+
+	return fmt.Sprintf("%scli/authorize?%s", dashboard, params.Encode())
+}
+
 func odinURL() string {
 	if o := os.Getenv("ODIN_URL"); o != "" {
 		return o
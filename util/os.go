@@ -44,3 +44,15 @@ func toDarwin(v string) (string, error) {
 		return "incompatible", fmt.Errorf("Incompatible OSX version. Please contact support.")
 	}
 }
+
+// OpenBrowser opens a url in the default browser
+func OpenBrowser(url string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+	}
+
+	return exec.Command("xdg-open", url).Start()
+}
@@ -0,0 +1,170 @@
+// Package overlay merges the boxfile of an environment, ie: boxfile.dev.yml
+// or boxfile.staging.yml, onto the boxfile of the app. The nodes only one
+// environment needs, like a mailcatcher in dev, live in its overlay instead
+// of in the boxfile every environment is deployed with.
+//
+// Maps are merged key by key, any other value replaces the one it overlays
+// and a key set to null (~) is removed, ie:
+//
+//	data.mailcatcher:
+//	  image: nanobox/mailcatcher
+//
+//	web.main:
+//	  config:
+//	    memory: 256m
+//
+//	worker.mailer: ~
+//
+// The boxfile section of the team config, .nanobox/config.yml, holds the
+// same overlays by environment, with the dev one under local. It's merged
+// before the overlay file of the environment.
+package overlay
+
+import (
+	"io/ioutil"
+	"os"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+)
+
+// Name returns the environment an app is deployed as, its overlay is
+// boxfile.<name>.yml
+func Name(appName string) string {
+	if appName == "sim" {
+		return "dry-run"
+	}
+
+	return appName
+}
+
+// Apply merges the overlays of an environment onto a boxfile, the boxfile is
+// returned as it is if the environment has no overlay
+func Apply(box, env string) (string, error) {
+	box, err := applyTeam(box, env)
+	if err != nil {
+		return box, err
+	}
+
+	data, err := ioutil.ReadFile(config.BoxfileOverlay(env))
+	if os.IsNotExist(err) {
+		return box, nil
+	}
+	if err != nil {
+		return box, util.ErrorAppend(err, "failed to read the boxfile of %s", env)
+	}
+
+	merged, err := Merge([]byte(box), data)
+	if err != nil {
+		return box, util.Errorf("[USER] %s is invalid - %s", config.BoxfileOverlay(env), err.Error())
+	}
+
+	return string(merged), nil
+}
+
+// applyTeam merges the overlay of an environment from the boxfile section of
+// the team config onto a boxfile
+func applyTeam(box, env string) (string, error) {
+	data, err := ioutil.ReadFile(config.TeamConfig())
+	if os.IsNotExist(err) {
+		return box, nil
+	}
+	if err != nil {
+		return box, util.ErrorAppend(err, "failed to read the team config")
+	}
+
+	team := struct {
+		Boxfile map[string]yaml.MapSlice `yaml:"boxfile"`
+	}{}
+	if err := yaml.Unmarshal(data, &team); err != nil {
+		return box, util.Errorf("[USER] %s is invalid - %s", config.TeamConfig(), err.Error())
+	}
+
+	// the team config calls the dev environment local
+	if env == "dev" {
+		env = "local"
+	}
+
+	doc, ok := team.Boxfile[env]
+	if !ok {
+		return box, nil
+	}
+
+	data, err = yaml.Marshal(doc)
+	if err != nil {
+		return box, util.ErrorAppend(err, "failed to encode the boxfile of %s", env)
+	}
+
+	merged, err := Merge([]byte(box), data)
+	if err != nil {
+		return box, util.Errorf("[USER] the boxfile of %s in %s is invalid - %s", env, config.TeamConfig(), err.Error())
+	}
+
+	return string(merged), nil
+}
+
+// Merge deep-merges an overlay onto a boxfile
+func Merge(base, overlay []byte) ([]byte, error) {
+	baseDoc := yaml.MapSlice{}
+	if err := yaml.Unmarshal(base, &baseDoc); err != nil {
+		return nil, err
+	}
+
+	overlayDoc := yaml.MapSlice{}
+	if err := yaml.Unmarshal(overlay, &overlayDoc); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(merge(baseDoc, overlayDoc))
+}
+
+// merge merges the keys of overlay onto base, keeping the order of base
+func merge(base, overlay yaml.MapSlice) yaml.MapSlice {
+	merged := yaml.MapSlice{}
+	overlaid := map[interface{}]bool{}
+
+	for _, item := range base {
+		value, ok := lookup(overlay, item.Key)
+		if !ok {
+			merged = append(merged, item)
+			continue
+		}
+
+		overlaid[item.Key] = true
+
+		// null removes the key
+		if value == nil {
+			continue
+		}
+
+		baseMap, baseIsMap := item.Value.(yaml.MapSlice)
+		overlayMap, overlayIsMap := value.(yaml.MapSlice)
+		if baseIsMap && overlayIsMap {
+			value = merge(baseMap, overlayMap)
+		}
+
+		merged = append(merged, yaml.MapItem{Key: item.Key, Value: value})
+	}
+
+	// the keys the base doesn't have are added at the end
+	for _, item := range overlay {
+		if !overlaid[item.Key] && item.Value != nil {
+			merged = append(merged, item)
+		}
+	}
+
+	return merged
+}
+
+// lookup returns the value of a key of a map
+func lookup(m yaml.MapSlice, key interface{}) (interface{}, bool) {
+	for _, item := range m {
+		if item.Key == key {
+			return item.Value, true
+		}
+	}
+
+	return nil, false
+}
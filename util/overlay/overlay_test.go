@@ -0,0 +1,119 @@
+package overlay_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/overlay"
+)
+
+func TestMerge(t *testing.T) {
+	base := `run.config:
+  engine: ruby
+  extra_packages:
+    - nodejs
+web.main:
+  start: puma
+  config:
+    cpu: 1
+    memory: 1g
+worker.mailer:
+  start: sidekiq
+`
+
+	dev := `run.config:
+  extra_packages:
+    - sqlite
+web.main:
+  config:
+    memory: 256m
+worker.mailer: ~
+data.mail:
+  image: nanobox/mailcatcher
+`
+
+	expected := `run.config:
+  engine: ruby
+  extra_packages:
+  - sqlite
+web.main:
+  start: puma
+  config:
+    cpu: 1
+    memory: 256m
+data.mail:
+  image: nanobox/mailcatcher
+`
+
+	merged, err := overlay.Merge([]byte(base), []byte(dev))
+	if err != nil {
+		t.Fatalf("failed to merge - %s", err.Error())
+	}
+
+	if string(merged) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, merged)
+	}
+
+	if _, err := overlay.Merge([]byte(base), []byte("web.main: [")); err == nil {
+		t.Errorf("expected an invalid overlay to fail")
+	}
+}
+
+func TestApplyTeam(t *testing.T) {
+	dir, err := ioutil.TempDir("", "overlay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cwd, _ := os.Getwd()
+	defer os.Chdir(cwd)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	team := `boxfile:
+  local:
+    web.main:
+      start: rails s
+    data.mail:
+      image: nanobox/mailcatcher
+  staging:
+    web.main:
+      start: puma -w 4
+`
+	os.Mkdir(filepath.Join(dir, ".nanobox"), 0755)
+	if err := ioutil.WriteFile(filepath.Join(dir, ".nanobox", "config.yml"), []byte(team), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the overlay file goes on top of the team config
+	if err := ioutil.WriteFile(filepath.Join(dir, "boxfile.dev.yml"), []byte("data.mail: ~\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	base := `web.main:
+  start: puma
+`
+
+	tests := []struct {
+		env, expected string
+	}{
+		{"dev", "web.main:\n  start: rails s\n"},
+		{"staging", "web.main:\n  start: puma -w 4\n"},
+		{"production", base},
+	}
+
+	for _, test := range tests {
+		box, err := overlay.Apply(base, test.env)
+		if err != nil {
+			t.Errorf("%s: failed to apply - %s", test.env, err.Error())
+			continue
+		}
+		if box != test.expected {
+			t.Errorf("%s: expected:\n%s\ngot:\n%s", test.env, test.expected, box)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Package procfile reads the process types of a Heroku style Procfile, ie:
+//
+//	web: bundle exec puma -C config/puma.rb
+//	worker: bundle exec sidekiq
+//	clock: bundle exec clockwork clock.rb
+//
+// When the boxfile has no web or worker components, each process type
+// becomes a component with its own start command and log stream: web is
+// web.main, worker is worker.main and any other type is a worker of the same
+// name, ie: worker.clock. The release type isn't a process and is ignored.
+package procfile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+)
+
+// Process is a process type of a Procfile
+type Process struct {
+	Type    string
+	Command string
+}
+
+// the lines of a Procfile, ie: web: bundle exec puma
+var processRegex = regexp.MustCompile(`^([A-Za-z0-9_-]+):\s*(.+)$`)
+
+// Path is the Procfile of the app
+func Path() string {
+	return filepath.ToSlash(filepath.Join(config.LocalDir(), "Procfile"))
+}
+
+// Parse returns the process types of a Procfile in order
+func Parse(data []byte) ([]Process, error) {
+	processes := []Process{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		match := processRegex.FindStringSubmatch(text)
+		if match == nil {
+			return nil, fmt.Errorf("line %d isn't a process, ie: web: bundle exec puma", line)
+		}
+
+		processes = append(processes, Process{Type: match[1], Command: strings.TrimSpace(match[2])})
+	}
+
+	return processes, scanner.Err()
+}
+
+// Node returns the boxfile node of a process type
+func (p Process) Node() string {
+	switch p.Type {
+	case "web":
+		return "web.main"
+	case "worker":
+		return "worker.main"
+	}
+
+	return "worker." + strings.ToLower(p.Type)
+}
+
+// Apply adds the process types of the app's Procfile to a boxfile that has
+// no web or worker components, or sets the start command of the components
+// of the same name that have none. The boxfile is returned as it is if the
+// app has no Procfile.
+func Apply(box string) (string, error) {
+	data, err := ioutil.ReadFile(Path())
+	if os.IsNotExist(err) {
+		return box, nil
+	}
+	if err != nil {
+		return box, util.ErrorAppend(err, "failed to read the Procfile")
+	}
+
+	processes, err := Parse(data)
+	if err != nil {
+		return box, util.Errorf("[USER] the Procfile is invalid - %s", err.Error())
+	}
+
+	doc := yaml.MapSlice{}
+	if err := yaml.Unmarshal([]byte(box), &doc); err != nil {
+		return box, util.ErrorAppend(err, "failed to parse the boxfile")
+	}
+
+	out, err := yaml.Marshal(merge(doc, processes))
+	if err != nil {
+		return box, util.ErrorAppend(err, "failed to encode the boxfile")
+	}
+
+	return string(out), nil
+}
+
+// merge adds the processes to the nodes of a boxfile
+func merge(doc yaml.MapSlice, processes []Process) yaml.MapSlice {
+	hasCode := false
+	for _, item := range doc {
+		name := fmt.Sprintf("%v", item.Key)
+		if strings.HasPrefix(name, "web.") || strings.HasPrefix(name, "worker.") {
+			hasCode = true
+		}
+	}
+
+	for _, process := range processes {
+		if process.Type == "release" {
+			continue
+		}
+
+		found := false
+		for i, item := range doc {
+			if fmt.Sprintf("%v", item.Key) != process.Node() {
+				continue
+			}
+			found = true
+
+			node, _ := item.Value.(yaml.MapSlice)
+			if !hasKey(node, "start") {
+				doc[i].Value = append(node, yaml.MapItem{Key: "start", Value: process.Command})
+			}
+		}
+
+		if !found && !hasCode {
+			doc = append(doc, yaml.MapItem{Key: process.Node(), Value: yaml.MapSlice{{Key: "start", Value: process.Command}}})
+		}
+	}
+
+	return doc
+}
+
+// hasKey returns true if a node has a key
+func hasKey(node yaml.MapSlice, key string) bool {
+	for _, item := range node {
+		if fmt.Sprintf("%v", item.Key) == key {
+			return true
+		}
+	}
+
+	return false
+}
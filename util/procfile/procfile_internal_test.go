@@ -0,0 +1,46 @@
+package procfile
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestMerge(t *testing.T) {
+	processes, err := Parse([]byte("# processes\nweb: bundle exec puma -p 8080\nworker: bundle exec sidekiq\nclock: clockwork clock.rb\nrelease: rake db:migrate\n"))
+	if err != nil {
+		t.Fatalf("failed to parse - %s", err.Error())
+	}
+
+	if len(processes) != 4 || processes[2].Node() != "worker.clock" {
+		t.Fatalf("unexpected processes %v", processes)
+	}
+
+	tests := []struct {
+		box      string
+		expected string
+	}{
+		// every process becomes a component
+		{"run.config:\n  engine: ruby\n", "run.config:\n  engine: ruby\nweb.main:\n  start: bundle exec puma -p 8080\nworker.main:\n  start: bundle exec sidekiq\nworker.clock:\n  start: clockwork clock.rb\n"},
+		// the components of the boxfile win
+		{"web.site:\n  start: rackup\n", "web.site:\n  start: rackup\n"},
+		// a component without a start command gets the one of its process
+		{"web.main:\n  routes:\n  - /\n", "web.main:\n  routes:\n  - /\n  start: bundle exec puma -p 8080\n"},
+	}
+
+	for _, test := range tests {
+		doc := yaml.MapSlice{}
+		if err := yaml.Unmarshal([]byte(test.box), &doc); err != nil {
+			t.Fatal(err)
+		}
+
+		out, _ := yaml.Marshal(merge(doc, processes))
+		if string(out) != test.expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", test.expected, out)
+		}
+	}
+
+	if _, err := Parse([]byte("web bundle exec puma\n")); err == nil {
+		t.Errorf("expected a line without a process type to fail")
+	}
+}
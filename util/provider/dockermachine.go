@@ -17,6 +17,7 @@ import (
 	"github.com/nanobox-io/nanobox/util"
 	"github.com/nanobox-io/nanobox/util/display"
 	// "github.com/nanobox-io/nanobox/util/fileutil"
+	"github.com/nanobox-io/nanobox/util/images"
 	"github.com/nanobox-io/nanobox/util/vbox"
 )
 
@@ -58,10 +59,9 @@ func (machine DockerMachine) Valid() (error, []string) {
 		}
 	}
 
-	// return early if we are running native mounts
+	// the nfs server is only needed to share over nfs
 	config, _ := models.LoadConfig()
-
-	if config.MountType == "native" {
+	if config.MountType != models.MountNFS {
 		if len(missingParts) == 0 && masterErr == nil {
 			return nil, missingParts
 		}
@@ -153,7 +153,7 @@ func (machine DockerMachine) Create() error {
 		"--driver",
 		"virtualbox",
 		"--virtualbox-boot2docker-url",
-		"https://d1ormdui8qdvue.cloudfront.net/boot2docker/v1/boot2docker.iso",
+		conf.Boot2DockerURL,
 		"--engine-env",
 		fmt.Sprintf("HTTP_PROXY=%s", os.Getenv("HTTP_PROXY")),
 		"--engine-env",
@@ -172,6 +172,16 @@ func (machine DockerMachine) Create() error {
 		fmt.Sprintf("%d", ram*1024),
 	}
 
+	// the daemon only knows about the mirrors of the docker hub, nanobox
+	// tries the others itself
+	for _, mirror := range images.HubMirrors(conf.RegistryMirror) {
+		cmd = append(cmd, "--engine-registry-mirror", mirror)
+	}
+
+	if conf.PullConcurrency > 0 {
+		cmd = append(cmd, "--engine-opt", fmt.Sprintf("max-concurrent-downloads=%d", conf.PullConcurrency))
+	}
+
 	// append the disk if they set it big enough
 	if disk >= 15360 {
 		cmd = append(cmd, "--virtualbox-disk-size", fmt.Sprintf("%d", disk))
@@ -246,6 +256,38 @@ func (machine DockerMachine) Stop() error {
 	return nil
 }
 
+// Suspend saves the state of the vm, the containers and their memory
+// included, so the next start picks up where it left off instead of booting
+func (machine DockerMachine) Suspend() error {
+
+	if !machine.IsReady() {
+		return nil
+	}
+
+	cmd := []string{
+		vboxManageCmd,
+		"controlvm",
+		"nanobox",
+		"savestate",
+	}
+
+	process := exec.Command(cmd[0], cmd[1:]...)
+
+	process.Stdout = display.NewStreamer("info")
+	process.Stderr = display.NewStreamer("info")
+
+	display.StartTask("Saving VM state")
+
+	if err := process.Run(); err != nil {
+		display.ErrorTask()
+		return util.Errorf("failed to save the vm state: %s", err)
+	}
+
+	display.StopTask()
+
+	return nil
+}
+
 // imploding the docker-machine provider
 // is the same as destroying it
 func (machine DockerMachine) Implode() error {
@@ -418,7 +460,7 @@ func (machine DockerMachine) Start() error {
 	if err := process.Run(); err != nil {
 		display.ErrorTask()
 		display.VMCommunicationError()
-		return util.ErrorfQuiet("[NANOBOX] VM cannot communicate with host")
+		return util.ErrorfQuiet("[PROVIDER] VM cannot communicate with host")
 	}
 
 	if machine.changedIP() {
@@ -779,6 +821,12 @@ func (machine DockerMachine) ReservedIPs() (rtn []string) {
 	return
 }
 
+// GPUSupported ...
+func (machine DockerMachine) GPUSupported() bool {
+	// the virtualbox vm has no access to the host gpus
+	return false
+}
+
 // Run a command in the vm
 func (machine DockerMachine) Run(command []string) ([]byte, error) {
 
@@ -79,7 +79,7 @@ func (machine DockerMachine) AddMount(local, host string) error {
 	config, _ := models.LoadConfig()
 	switch config.MountType {
 
-	case "netfs":
+	case models.MountNFS:
 
 		// add netfs share
 		// here we use the processor so we can do privilage exec
@@ -96,6 +96,9 @@ func (machine DockerMachine) AddMount(local, host string) error {
 
 	default:
 
+		// sync shares the same way as vboxsf, the builds and the consoles
+		// read the code from the volumes it's synced into
+
 		// add share
 		lumber.Info("adding share for native %s", local)
 		if err := machine.addShare(local, host); err != nil {
@@ -309,9 +312,11 @@ func (machine DockerMachine) removeNativeMount(local, host string) error {
 
 // setupCoreUtilsScript returns a string containing the script to setup cifs
 func setupCoreUtilsScript() string {
+	conf, _ := models.LoadConfig()
+
 	script := `
 		if [ ! -f /usr/local/bin/stat ]; then
-			wget -O /mnt/sda1/tmp/tce/optional/coreutils.tcz http://repo.tinycorelinux.net/7.x/x86_64/tcz/coreutils.tcz &&
+			wget -O /mnt/sda1/tmp/tce/optional/coreutils.tcz ` + conf.TCEMirror + `/coreutils.tcz &&
 
 			tce-load -i coreutils;
 		fi
@@ -91,10 +91,12 @@ func (machine DockerMachine) addNetfsMount(local, host string) error {
 
 // setupCifsUtilsScript returns a string containing the script to setup cifs
 func setupCifsUtilsScript() string {
+	conf, _ := models.LoadConfig()
+
 	script := `
 		if [ ! -f /sbin/mount.cifs ]; then
-			wget -O /mnt/sda1/tmp/tce/optional/samba-libs.tcz http://repo.tinycorelinux.net/7.x/x86_64/tcz/samba-libs.tcz &&
-			wget -O /mnt/sda1/tmp/tce/optional/cifs-utils.tcz http://repo.tinycorelinux.net/7.x/x86_64/tcz/cifs-utils.tcz &&
+			wget -O /mnt/sda1/tmp/tce/optional/samba-libs.tcz ` + conf.TCEMirror + `/samba-libs.tcz &&
+			wget -O /mnt/sda1/tmp/tce/optional/cifs-utils.tcz ` + conf.TCEMirror + `/cifs-utils.tcz &&
 
 			tce-load -i samba-libs &&
 			tce-load -i cifs-utils;
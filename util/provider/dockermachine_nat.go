@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// NatRules returns the nat rules that make containers reachable on an ip of
+// the vm
+func (machine DockerMachine) NatRules() ([]NatRule, error) {
+	if !machine.IsReady() {
+		return nil, nil
+	}
+
+	out, err := machine.Run([]string{"sudo", "/usr/local/sbin/iptables", "-t", "nat", "-S"})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", out, err)
+	}
+
+	return parseNatRules(out), nil
+}
+
+// parseNatRules parses the output of iptables -S for the rules AddNat adds,
+// ie:
+//
+//	-A PREROUTING -d 192.168.99.50/32 -j DNAT --to-destination 172.21.0.4
+//	-A POSTROUTING -s 172.21.0.4/32 -j SNAT --to-source 192.168.99.50
+//
+// The rules docker adds for itself are left out.
+func parseNatRules(out []byte) []NatRule {
+	rules := []NatRule{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 8 || fields[0] != "-A" {
+			continue
+		}
+
+		chain, match, target, to := fields[1], fields[3], fields[5], fields[7]
+		addr := strings.TrimSuffix(match, "/32")
+
+		switch {
+		case chain == "PREROUTING" && fields[2] == "-d" && target == "DNAT" && fields[6] == "--to-destination":
+			rules = append(rules, NatRule{Chain: chain, Host: addr, Container: to})
+		case chain == "POSTROUTING" && fields[2] == "-s" && target == "SNAT" && fields[6] == "--to-source":
+			rules = append(rules, NatRule{Chain: chain, Host: to, Container: addr})
+		}
+	}
+
+	return rules
+}
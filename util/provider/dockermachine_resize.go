@@ -0,0 +1,300 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// the marker growPartitionScript prints when it grew the partition
+const partitionGrown = "nanobox:partition-grown"
+
+// Usage returns what the vm is given, and what of it is in use when it's
+// running
+func (machine DockerMachine) Usage() (Resources, error) {
+	res, _, err := machine.allocation()
+	if err != nil {
+		return res, err
+	}
+
+	if !machine.IsReady() {
+		return res, nil
+	}
+
+	if out, err := machine.Run([]string{"cat", "/proc/meminfo"}); err == nil {
+		res.MemoryUsed = parseMeminfo(out)
+	}
+
+	if out, err := machine.Run([]string{"df", "-m", "/mnt/sda1"}); err == nil {
+		res.DiskSize, res.DiskUsed = parseDF(out)
+	}
+
+	if out, err := machine.Run([]string{"cat", "/proc/loadavg"}); err == nil {
+		if fields := strings.Fields(string(out)); len(fields) > 0 {
+			res.Load, _ = strconv.ParseFloat(fields[0], 64)
+		}
+	}
+
+	return res, nil
+}
+
+// Resize gives the stopped vm the cpus, memory and disk, memory and disk in
+// megabytes. The disk can only grow, GrowDisk grows the filesystem onto it
+// once the vm runs.
+func (machine DockerMachine) Resize(cpus, memory, disk int) error {
+	if status := machine.Status(); status != "Stopped" {
+		return util.Errorf("the VM has to be stopped to be resized, it's %s", strings.ToLower(status))
+	}
+
+	res, attached, err := machine.allocation()
+	if err != nil {
+		return err
+	}
+
+	if disk < res.Disk {
+		return util.Errorf("[USER] the disk can only grow, it's %dMB", res.Disk)
+	}
+
+	display.StartTask("Resizing VM")
+
+	if cpus != res.CPUs || memory != res.Memory {
+		if out, err := exec.Command(vboxManageCmd, "modifyvm", "nanobox",
+			"--cpus", strconv.Itoa(cpus),
+			"--memory", strconv.Itoa(memory)).CombinedOutput(); err != nil {
+			display.ErrorTask()
+			lumber.Error("provider:Resize:modifyvm: %s", out)
+			return util.Errorf("failed to set the cpus and memory of the vm: %s", bytes.TrimSpace(out))
+		}
+	}
+
+	if disk > res.Disk {
+		if err := growMedium(attached, disk); err != nil {
+			display.ErrorTask()
+			return err
+		}
+	}
+
+	display.StopTask()
+
+	return nil
+}
+
+// GrowDisk grows the data partition of the running vm and its filesystem to
+// fill the disk. The vm is rebooted if the partition had to grow, the
+// kernel only picks up the new partition table on boot.
+func (machine DockerMachine) GrowDisk() error {
+	if !machine.IsReady() {
+		return util.Errorf("the VM has to run for its disk to grow")
+	}
+
+	display.StartTask("Growing VM disk")
+
+	out, err := machine.Run([]string{"sh", "-c", growPartitionScript()})
+	if err != nil {
+		display.ErrorTask()
+		lumber.Error("provider:GrowDisk:growPartitionScript: %s", out)
+		return util.Errorf("failed to grow the partition: %s", err)
+	}
+
+	display.StopTask()
+
+	if bytes.Contains(out, []byte(partitionGrown)) {
+		if err := machine.Reboot(); err != nil {
+			return util.ErrorAppend(err, "failed to reboot the vm")
+		}
+	}
+
+	if out, err := machine.Run([]string{"sudo", "resize2fs", "/dev/sda1"}); err != nil {
+		lumber.Error("provider:GrowDisk:resize2fs: %s", out)
+		return util.Errorf("failed to grow the filesystem: %s", bytes.TrimSpace(out))
+	}
+
+	return nil
+}
+
+// allocation returns the cpus, memory and disk virtualbox gives the vm, and
+// the medium of the disk
+func (machine DockerMachine) allocation() (Resources, medium, error) {
+	res := Resources{}
+
+	out, err := exec.Command(vboxManageCmd, "showvminfo", "nanobox", "--machinereadable").CombinedOutput()
+	if err != nil {
+		lumber.Error("provider:allocation:showvminfo: %s", out)
+		return res, medium{}, util.Errorf("failed to read the vm info: %s", bytes.TrimSpace(out))
+	}
+
+	info := parseMachineReadable(out)
+	res.CPUs, _ = strconv.Atoi(info["cpus"])
+	res.Memory, _ = strconv.Atoi(info["memory"])
+
+	disk, ok := diskMedium(info)
+	if !ok {
+		return res, disk, util.Errorf("the vm has no disk")
+	}
+
+	out, err = exec.Command(vboxManageCmd, "showmediuminfo", "disk", disk.path).CombinedOutput()
+	if err != nil {
+		lumber.Error("provider:allocation:showmediuminfo(%s): %s", disk.path, out)
+		return res, disk, util.Errorf("failed to read the disk info: %s", bytes.TrimSpace(out))
+	}
+	res.Disk = parseCapacity(out)
+
+	return res, disk, nil
+}
+
+// medium is a disk attached to the vm
+type medium struct {
+	path       string
+	controller string
+	port       string
+	device     string
+}
+
+// growMedium resizes the disk to size megabytes. virtualbox can't resize the
+// vmdk docker-machine creates, so it's converted to a vdi first.
+func growMedium(disk medium, size int) error {
+	if strings.EqualFold(filepath.Ext(disk.path), ".vmdk") {
+		vdi := strings.TrimSuffix(disk.path, filepath.Ext(disk.path)) + ".vdi"
+
+		steps := [][]string{
+			{vboxManageCmd, "clonemedium", "disk", disk.path, vdi, "--format", "VDI"},
+			{vboxManageCmd, "storageattach", "nanobox", "--storagectl", disk.controller,
+				"--port", disk.port, "--device", disk.device, "--type", "hdd", "--medium", vdi},
+			{vboxManageCmd, "closemedium", "disk", disk.path, "--delete"},
+		}
+
+		for _, step := range steps {
+			if out, err := exec.Command(step[0], step[1:]...).CombinedOutput(); err != nil {
+				lumber.Error("provider:growMedium:%s: %s", step[1], out)
+				return util.Errorf("failed to convert the disk to vdi: %s", bytes.TrimSpace(out))
+			}
+		}
+
+		disk.path = vdi
+	}
+
+	out, err := exec.Command(vboxManageCmd, "modifymedium", "disk", disk.path, "--resize", strconv.Itoa(size)).CombinedOutput()
+	if err != nil {
+		lumber.Error("provider:growMedium:modifymedium(%s): %s", disk.path, out)
+		return util.Errorf("failed to grow the disk: %s", bytes.TrimSpace(out))
+	}
+
+	return nil
+}
+
+// growPartitionScript recreates the data partition at the same start and up
+// to the end of the disk, if the disk grew. boot2docker puts the swap
+// partition first, so the data partition is the last one.
+func growPartitionScript() string {
+	script := `
+		start=$(cat /sys/block/sda/sda1/start);
+		end=$(($start + $(cat /sys/block/sda/sda1/size)));
+		if [ $end -lt $(($(cat /sys/block/sda/size) - 2048)) ]; then
+			printf "d\n1\nn\np\n1\n$start\n\nw\n" | sudo fdisk -u /dev/sda;
+			echo ` + partitionGrown + `;
+		fi
+	`
+
+	return strings.Replace(script, "\n", "", -1)
+}
+
+// parseMachineReadable returns the keys and values of the output of
+// showvminfo --machinereadable, without their quotes
+func parseMachineReadable(out []byte) map[string]string {
+	info := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		info[strings.Trim(parts[0], `"`)] = strings.Trim(parts[1], `"`)
+	}
+
+	return info
+}
+
+// diskMedium returns the hard disk attached to the vm, an attachment is
+// listed as "SATA-1-0"="/path/to/disk.vmdk"
+func diskMedium(info map[string]string) (medium, bool) {
+	for key, path := range info {
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".vmdk" && ext != ".vdi" {
+			continue
+		}
+
+		parts := strings.Split(key, "-")
+		if len(parts) < 3 {
+			continue
+		}
+
+		return medium{
+			path:       path,
+			controller: strings.Join(parts[:len(parts)-2], "-"),
+			port:       parts[len(parts)-2],
+			device:     parts[len(parts)-1],
+		}, true
+	}
+
+	return medium{}, false
+}
+
+// parseCapacity returns the megabytes of the "Capacity: 20000 MBytes" line
+// of showmediuminfo
+func parseCapacity(out []byte) int {
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "Capacity:" {
+			size, _ := strconv.Atoi(fields[1])
+			return size
+		}
+	}
+
+	return 0
+}
+
+// parseMeminfo returns the megabytes of memory in use according to
+// /proc/meminfo, what isn't available
+func parseMeminfo(out []byte) int {
+	values := map[string]int{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 {
+			values[strings.TrimSuffix(fields[0], ":")], _ = strconv.Atoi(fields[1])
+		}
+	}
+
+	return (values["MemTotal"] - values["MemAvailable"]) / 1024
+}
+
+// parseDF returns the size and the used megabytes of the filesystem df -m
+// reports on
+func parseDF(out []byte) (int, int) {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) < 2 {
+		return 0, 0
+	}
+
+	// a long device name wraps the line, the numbers are the last fields
+	fields := strings.Fields(strings.Join(lines[1:], " "))
+	if len(fields) < 5 {
+		return 0, 0
+	}
+
+	size, _ := strconv.Atoi(fields[len(fields)-5])
+	used, _ := strconv.Atoi(fields[len(fields)-4])
+
+	return size, used
+}
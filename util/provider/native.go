@@ -59,6 +59,26 @@ func (native Native) Stop() error {
 	return nil
 }
 
+// Suspend does nothing on native, there is no vm to save
+func (native Native) Suspend() error {
+	return nil
+}
+
+// Usage isn't known on native, docker runs on the resources of the host
+func (native Native) Usage() (Resources, error) {
+	return Resources{}, fmt.Errorf("the native provider has no VM, docker runs on the resources of the host")
+}
+
+// Resize isn't possible on native, there is no vm to resize
+func (native Native) Resize(cpus, memory, disk int) error {
+	return fmt.Errorf("the native provider has no VM to resize, docker runs on the resources of the host")
+}
+
+// GrowDisk does nothing on native, there is no vm disk
+func (native Native) GrowDisk() error {
+	return nil
+}
+
 // implode loops through the docker containers we created
 // and removes each one
 func (native Native) Implode() error {
@@ -257,6 +277,11 @@ func (native Native) RemoveNat(ip, containerIP string) error {
 	return nil
 }
 
+// NatRules returns no rules, docker runs on the host network stack
+func (native Native) NatRules() ([]NatRule, error) {
+	return nil, nil
+}
+
 func (native Native) RequiresMount() bool {
 	return false
 }
@@ -315,6 +340,18 @@ func (native Native) Run(command []string) ([]byte, error) {
 	return cmd.CombinedOutput()
 }
 
+// GPUSupported returns true if the docker daemon runs containers with the
+// nvidia runtime by default. The docker client can't pick a runtime per
+// container, so the daemon needs `"default-runtime": "nvidia"` configured.
+func (native Native) GPUSupported() bool {
+	out, err := exec.Command("docker", "info", "--format", "{{.DefaultRuntime}}").Output()
+	if err != nil {
+		return false
+	}
+
+	return strings.TrimSpace(string(out)) == "nvidia"
+}
+
 //
 func (native Native) RemoveEnvDir(id string) error {
 	if id == "" {
@@ -20,6 +20,7 @@ type Provider interface {
 	Create() error
 	Reboot() error
 	Stop() error
+	Suspend() error
 	Implode() error
 	Destroy() error
 	Start() error
@@ -29,13 +30,39 @@ type Provider interface {
 	RemoveIP(ip string) error
 	SetDefaultIP(ip string) error
 	// AddNat(host, container string) error
-	// RemoveNat(host, container string) error
+	RemoveNat(host, container string) error
+	NatRules() ([]NatRule, error)
 	RequiresMount() bool
 	HasMount(mount string) bool
 	AddMount(local, host string) error
 	RemoveMount(local, host string) error
 	RemoveEnvDir(id string) error
 	Run(command []string) ([]byte, error)
+	GPUSupported() bool
+	Usage() (Resources, error)
+	Resize(cpus, memory, disk int) error
+	GrowDisk() error
+}
+
+// Resources is what the vm is given and how much of it is in use, memory and
+// disk in megabytes. DiskSize is the size of the filesystem, which is less
+// than the disk until the filesystem is grown onto it.
+type Resources struct {
+	CPUs       int     `json:"cpus"`
+	Memory     int     `json:"memory"`
+	Disk       int     `json:"disk"`
+	MemoryUsed int     `json:"memory_used"`
+	DiskSize   int     `json:"disk_size"`
+	DiskUsed   int     `json:"disk_used"`
+	Load       float64 `json:"load"`
+}
+
+// NatRule is a nat of the vm that makes a container reachable on a host ip.
+// AddNat adds a PREROUTING and a POSTROUTING rule for each.
+type NatRule struct {
+	Chain     string `json:"chain"`
+	Host      string `json:"host"`
+	Container string `json:"container"`
 }
 
 var (
@@ -115,6 +142,17 @@ func Stop() error {
 	return p.Stop()
 }
 
+// Suspend ...
+func Suspend() error {
+
+	p, err := fetchProvider()
+	if err != nil {
+		return err
+	}
+
+	return p.Suspend()
+}
+
 // Implode ..
 func Implode() error {
 
@@ -246,16 +284,27 @@ func SetDefaultIP(ip string) error {
 // 	return p.AddNat(host, container)
 // }
 
-// // RemoveNat ..
-// func RemoveNat(host, container string) error {
+// RemoveNat ..
+func RemoveNat(host, container string) error {
 
-// 	p, err := fetchProvider()
-// 	if err != nil {
-// 		return err
-// 	}
+	p, err := fetchProvider()
+	if err != nil {
+		return err
+	}
 
-// 	return p.RemoveNat(host, container)
-// }
+	return p.RemoveNat(host, container)
+}
+
+// NatRules ...
+func NatRules() ([]NatRule, error) {
+
+	p, err := fetchProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.NatRules()
+}
 
 // RequiresMount ...
 func RequiresMount() bool {
@@ -343,6 +392,50 @@ func BridgeRequired() bool {
 	return p.BridgeRequired()
 }
 
+// GPUSupported ...
+func GPUSupported() bool {
+
+	p, err := fetchProvider()
+	if err != nil {
+		return false
+	}
+
+	return p.GPUSupported()
+}
+
+// Usage ...
+func Usage() (Resources, error) {
+
+	p, err := fetchProvider()
+	if err != nil {
+		return Resources{}, err
+	}
+
+	return p.Usage()
+}
+
+// Resize ...
+func Resize(cpus, memory, disk int) error {
+
+	p, err := fetchProvider()
+	if err != nil {
+		return err
+	}
+
+	return p.Resize(cpus, memory, disk)
+}
+
+// GrowDisk ...
+func GrowDisk() error {
+
+	p, err := fetchProvider()
+	if err != nil {
+		return err
+	}
+
+	return p.GrowDisk()
+}
+
 // fetchProvider fetches the registered provider from the configured name
 func fetchProvider() (Provider, error) {
 	p, ok := providers[Name()]
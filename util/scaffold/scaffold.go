@@ -0,0 +1,213 @@
+// Package scaffold generates the boxfile of a new app from a template of its
+// framework, with the data components it needs and the entries nanobox adds
+// to its .gitignore.
+package scaffold
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Template is the starting point of an app of a framework
+type Template struct {
+	Name      string
+	Boxfile   string    // {{app}} is the name of the app
+	Services  []Service // the data components that can be added
+	Gitignore []string
+}
+
+// Service is a data component a template can add
+type Service struct {
+	Name    string // the name of the data component, ie: db for data.db
+	Image   string
+	Default bool // added unless the user says otherwise
+}
+
+// the entries every app gets, the files nanobox writes into the app that
+// shouldn't be committed
+var gitignore = []string{".env", "nanobox.env", "nanobox-migration.txt"}
+
+// the data components of the templates
+var (
+	postgres = Service{Name: "db", Image: "nanobox/postgresql:9.6"}
+	mysql    = Service{Name: "mysql", Image: "nanobox/mysql:5.7"}
+	redis    = Service{Name: "redis", Image: "nanobox/redis:3.2"}
+	mongodb  = Service{Name: "mongodb", Image: "nanobox/mongodb:3.4"}
+)
+
+// Templates are the templates by name
+var Templates = map[string]Template{
+	"rails": {
+		Name: "rails",
+		Boxfile: `run.config:
+  engine: ruby
+  # the asset pipeline needs a javascript runtime
+  extra_packages:
+    - nodejs
+  cache_dirs:
+    - node_modules
+
+deploy.config:
+  extra_steps:
+    - bundle exec rake assets:precompile
+  before_live:
+    web.main:
+      - bundle exec rake db:migrate
+
+web.main:
+  start: bundle exec rails server -b 0.0.0.0 -p 8080
+  writable_dirs:
+    - tmp
+    - log
+  log_watch:
+    rails: log/production.log
+`,
+		Services:  []Service{withDefault(postgres), redis},
+		Gitignore: []string{"log/*.log", "tmp/", "node_modules/"},
+	},
+	"django": {
+		Name: "django",
+		Boxfile: `run.config:
+  engine: python
+  extra_packages:
+    - libjpeg-turbo
+
+deploy.config:
+  extra_steps:
+    - python manage.py collectstatic --noinput
+  before_live:
+    web.main:
+      - python manage.py migrate --noinput
+
+web.main:
+  start: gunicorn --bind 0.0.0.0:8080 {{app}}.wsgi
+`,
+		Services:  []Service{withDefault(postgres), mysql, redis},
+		Gitignore: []string{"__pycache__/", "*.pyc", "staticfiles/"},
+	},
+	"node": {
+		Name: "node",
+		Boxfile: `run.config:
+  engine: nodejs
+  cache_dirs:
+    - node_modules
+
+web.main:
+  # the app listens on 8080, ie: process.env.PORT || 8080
+  start: npm start
+`,
+		Services:  []Service{mongodb, postgres, redis},
+		Gitignore: []string{"node_modules/", "npm-debug.log"},
+	},
+	"go": {
+		Name: "go",
+		Boxfile: `run.config:
+  engine: golang
+  engine.config:
+    # the import path of the app, ie: github.com/you/{{app}}
+    package: {{app}}
+
+web.main:
+  # the app listens on 8080
+  start: {{app}}
+`,
+		Services:  []Service{postgres, redis},
+		Gitignore: []string{"/{{app}}", "vendor/"},
+	},
+}
+
+// the names of the apps are part of the import paths and module names
+var unsafeName = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// Names returns the names of the templates in order
+func Names() []string {
+	names := []string{}
+	for name := range Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Service returns the service of a template by name or image, ie: db or
+// postgres
+func (t Template) Service(name string) (Service, bool) {
+	for _, service := range t.Services {
+		if service.Name == name || strings.HasPrefix(strings.TrimPrefix(service.Image, "nanobox/"), name) {
+			return service, true
+		}
+	}
+
+	return Service{}, false
+}
+
+// Render renders the boxfile of an app with the data components
+func (t Template) Render(app string, services []Service) string {
+	box := strings.Replace(t.Boxfile, "{{app}}", appName(app), -1)
+
+	for _, service := range services {
+		box += fmt.Sprintf("\ndata.%s:\n  image: %s\n", service.Name, service.Image)
+	}
+
+	return box
+}
+
+// GitignoreEntries returns the entries the app's .gitignore needs
+func (t Template) GitignoreEntries(app string) []string {
+	entries := append([]string{}, gitignore...)
+	for _, entry := range t.Gitignore {
+		entries = append(entries, strings.Replace(entry, "{{app}}", appName(app), -1))
+	}
+
+	return entries
+}
+
+// AddGitignore adds the entries a .gitignore is missing and returns them
+func AddGitignore(path string, entries []string) ([]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	existing := map[string]bool{}
+	for _, line := range strings.Split(string(data), "\n") {
+		existing[strings.TrimSpace(line)] = true
+	}
+
+	added := []string{}
+	for _, entry := range entries {
+		if !existing[entry] {
+			added = append(added, entry)
+			existing[entry] = true
+		}
+	}
+
+	if len(added) == 0 {
+		return added, nil
+	}
+
+	out := string(data)
+	if out != "" && !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	out += "\n# nanobox\n" + strings.Join(added, "\n") + "\n"
+
+	return added, ioutil.WriteFile(path, []byte(out), 0644)
+}
+
+// withDefault returns the service added by default
+func withDefault(service Service) Service {
+	service.Default = true
+	return service
+}
+
+// appName returns the name of the app as code can use it, ie: my-app is
+// my_app
+func appName(app string) string {
+	return strings.Trim(unsafeName.ReplaceAllString(strings.ToLower(app), "_"), "_")
+}
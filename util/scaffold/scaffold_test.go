@@ -0,0 +1,83 @@
+package scaffold_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/nanobox-io/nanobox/util/scaffold"
+)
+
+func TestRender(t *testing.T) {
+	for _, name := range scaffold.Names() {
+		tmpl := scaffold.Templates[name]
+		box := tmpl.Render("My-App", tmpl.Services)
+
+		parsed := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(box), &parsed); err != nil {
+			t.Errorf("%s: invalid boxfile: %s", name, err)
+		}
+
+		if strings.Contains(box, "{{") {
+			t.Errorf("%s: placeholder left in '%s'", name, box)
+		}
+
+		for _, service := range tmpl.Services {
+			if _, ok := parsed["data."+service.Name]; !ok {
+				t.Errorf("%s: missing data.%s", name, service.Name)
+			}
+		}
+	}
+
+	box := scaffold.Templates["django"].Render("My-App", nil)
+	if !strings.Contains(box, "my_app.wsgi") {
+		t.Errorf("app name not replaced in '%s'", box)
+	}
+}
+
+func TestService(t *testing.T) {
+	tmpl := scaffold.Templates["django"]
+
+	if service, ok := tmpl.Service("postgres"); !ok || service.Name != "db" {
+		t.Errorf("postgres: got %+v", service)
+	}
+	if service, ok := tmpl.Service("mysql"); !ok || service.Image != "nanobox/mysql:5.7" {
+		t.Errorf("mysql: got %+v", service)
+	}
+	if _, ok := tmpl.Service("mongodb"); ok {
+		t.Errorf("mongodb isn't a django service")
+	}
+}
+
+func TestAddGitignore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nanobox-scaffold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, ".gitignore")
+	ioutil.WriteFile(path, []byte("node_modules/\n.env"), 0644)
+
+	added, err := scaffold.AddGitignore(path, []string{".env", "node_modules/", "nanobox.env"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 1 || added[0] != "nanobox.env" {
+		t.Errorf("added %v", added)
+	}
+
+	added, _ = scaffold.AddGitignore(path, []string{"nanobox.env"})
+	if len(added) != 0 {
+		t.Errorf("added %v again", added)
+	}
+
+	data, _ := ioutil.ReadFile(path)
+	if string(data) != "node_modules/\n.env\n\n# nanobox\nnanobox.env\n" {
+		t.Errorf("wrong .gitignore '%s'", data)
+	}
+}
@@ -0,0 +1,242 @@
+package schema
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// SeverityInfo is a lint problem that is only a suggestion, even --strict
+// doesn't fail on it
+const SeverityInfo = "info"
+
+// the lint rules, by name
+const (
+	RuleUnpinnedEngine = "unpinned-engine"
+	RuleUnpinnedImage  = "unpinned-image"
+	RuleWorldOpenPort  = "world-open-port"
+	RuleHugeMemory     = "huge-memory"
+	RuleNoHealthcheck  = "missing-healthcheck"
+	RuleDeprecatedNode = "deprecated-node"
+)
+
+// hugeMemory is the memory limit of a component, in megabytes, that is more
+// than a dev machine has to give
+const hugeMemory = 8 * 1024
+
+// the ports of services that shouldn't be reachable by anyone who can reach
+// the app, the docker daemon is as good as root on the host
+var (
+	sensitivePorts = map[int]string{
+		22:    "ssh",
+		2376:  "docker",
+		3306:  "mysql",
+		5432:  "postgresql",
+		6379:  "redis",
+		9200:  "elasticsearch",
+		11211: "memcached",
+		27017: "mongodb",
+	}
+	dangerousPorts = map[int]string{
+		2375: "the unencrypted docker daemon",
+	}
+)
+
+// the nodes of the first version of the boxfile and what replaced them
+var deprecatedNodes = map[string]string{
+	"build":       "run.config",
+	"code.build":  "run.config",
+	"code.deploy": "deploy.config",
+	"dev":         "run.config",
+	"env":         "the app's evars, see 'nanobox evar'",
+}
+
+// LintFile validates and lints the boxfile at path
+func LintFile(path string) ([]Problem, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Lint(data), nil
+}
+
+// Lint validates a boxfile and checks it for patterns that work but are
+// risky, like an engine that isn't pinned to a version or a database port
+// open to everyone. The problems are sorted by line.
+func Lint(data []byte) []Problem {
+	problems := Validate(data)
+
+	doc := yaml.MapSlice{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return problems
+	}
+
+	v := &validator{lines: lineIndex(data), problems: problems}
+
+	for _, item := range doc {
+		name := fmt.Sprintf("%v", item.Key)
+		values, _ := item.Value.(yaml.MapSlice)
+
+		if replacement, ok := deprecatedNodes[name]; ok {
+			v.lint(RuleDeprecatedNode, SeverityWarning, []string{name}, "is from the first version of the boxfile, use %s instead", replacement)
+			continue
+		}
+
+		switch {
+		case name == "run.config":
+			v.lintEngine(values)
+		case strings.HasPrefix(name, "web."):
+			v.lintImage(name, values)
+			v.lintPorts(name, values)
+			v.lintMemory(name, values)
+			v.lintHealthcheck(name, values)
+		case strings.HasPrefix(name, "worker."):
+			v.lintImage(name, values)
+			v.lintPorts(name, values)
+			v.lintMemory(name, values)
+		case strings.HasPrefix(name, "data."), strings.HasPrefix(name, "sidecar."):
+			v.lintImage(name, values)
+			v.lintMemory(name, values)
+		}
+	}
+
+	sort.Stable(byLine(v.problems))
+
+	return v.problems
+}
+
+// HasWarnings returns true if any of the problems is a warning or an error,
+// --strict fails on them
+func HasWarnings(problems []Problem) bool {
+	for _, problem := range problems {
+		if problem.Severity == SeverityError || problem.Severity == SeverityWarning {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lint adds a problem found by a lint rule
+func (v *validator) lint(rule, severity string, path []string, format string, args ...interface{}) {
+	v.add(severity, path, format, args...)
+	v.problems[len(v.problems)-1].Rule = rule
+}
+
+// lintEngine checks the engine is pinned to a version, a local engine is
+// whatever is checked out
+func (v *validator) lintEngine(values yaml.MapSlice) {
+	engine, _ := value(values, "engine").(string)
+	if engine == "" || value(values, "engine_path") != nil || localEngineRegex.MatchString(engine) {
+		return
+	}
+
+	if !strings.Contains(engine, "#") {
+		v.lint(RuleUnpinnedEngine, SeverityWarning, []string{"run.config", "engine"},
+			"'%s' isn't pinned to a version, the build changes whenever the engine does, ie: %s#v1.0.0", engine, engine)
+	}
+}
+
+// lintImage checks the image of a component is pinned to a tag
+func (v *validator) lintImage(name string, values yaml.MapSlice) {
+	image, _ := value(values, "image").(string)
+	if image == "" || strings.Contains(image, "@sha256:") {
+		return
+	}
+
+	// the tag is after the last colon, unless that's the port of a registry
+	tag := ""
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		tag = image[i+1:]
+	}
+
+	switch tag {
+	case "":
+		v.lint(RuleUnpinnedImage, SeverityWarning, []string{name, "image"}, "'%s' has no tag, it's whatever 'latest' is when it's pulled", image)
+	case "latest":
+		v.lint(RuleUnpinnedImage, SeverityWarning, []string{name, "image"}, "'%s' changes whenever a new version is pushed, use a version tag", image)
+	}
+}
+
+// lintPorts checks the ports a component opens to everyone who can reach
+// the app, ie: tcp:5432:5432
+func (v *validator) lintPorts(name string, values yaml.MapSlice) {
+	ports, _ := value(values, "ports").([]interface{})
+
+	for _, port := range ports {
+		fields := strings.Split(fmt.Sprintf("%v", port), ":")
+		if len(fields) > 1 && (fields[0] == "tcp" || fields[0] == "udp") {
+			fields = fields[1:]
+		}
+
+		public, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+
+		if service, ok := dangerousPorts[public]; ok {
+			v.lint(RuleWorldOpenPort, SeverityError, []string{name, "ports"}, "port %d opens %s to everyone who can reach the app", public, service)
+		} else if service, ok := sensitivePorts[public]; ok {
+			v.lint(RuleWorldOpenPort, SeverityWarning, []string{name, "ports"}, "port %d opens %s to everyone who can reach the app, use 'nanobox tunnel' instead", public, service)
+		}
+	}
+}
+
+// lintMemory checks the memory limit of a component is something a machine
+// can give
+func (v *validator) lintMemory(name string, values yaml.MapSlice) {
+	config, _ := value(values, "config").(yaml.MapSlice)
+	memory := value(config, "memory")
+	if memory == nil {
+		return
+	}
+
+	if size, ok := megabytes(fmt.Sprintf("%v", memory)); ok && size > hugeMemory {
+		v.lint(RuleHugeMemory, SeverityWarning, []string{name, "config", "memory"}, "%v is more memory than most machines have, the vm may start swapping", memory)
+	}
+}
+
+// lintHealthcheck suggests a health check for a web component, otherwise a
+// deploy only knows the component answers on /
+func (v *validator) lintHealthcheck(name string, values yaml.MapSlice) {
+	if value(values, "healthcheck") == nil {
+		v.lint(RuleNoHealthcheck, SeverityInfo, []string{name}, "has no healthcheck, deploys only check it answers on /, ie: healthcheck: /health")
+	}
+}
+
+// value returns the value of a key of a node
+func value(values yaml.MapSlice, key string) interface{} {
+	for _, item := range values {
+		if fmt.Sprintf("%v", item.Key) == key {
+			return item.Value
+		}
+	}
+
+	return nil
+}
+
+// megabytes returns a memory size in megabytes, plain numbers are megabytes
+// like in the limits of a component
+func megabytes(size string) (float64, bool) {
+	size = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(size)), "b")
+
+	unit := 1.0
+	switch {
+	case strings.HasSuffix(size, "k"):
+		unit = 1.0 / 1024
+	case strings.HasSuffix(size, "g"):
+		unit = 1024
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimRight(size, "kmg"), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return n * unit, true
+}
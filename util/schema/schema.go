@@ -0,0 +1,473 @@
+// Package schema checks a boxfile against the nodes and keys nanobox knows,
+// with the line of each problem, before anything is built or started. An
+// error stops the build, a warning, like a key nanobox doesn't know, is
+// only reported.
+package schema
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/nanobox-io/nanobox/util/hostmount"
+)
+
+// the severities of a problem
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Problem is something wrong with the boxfile
+type Problem struct {
+	Line     int    `json:"line"` // 0 when it isn't on a line of its own
+	Path     string `json:"path"` // ie: web.main.start
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Rule     string `json:"rule,omitempty"` // the lint rule, ie: unpinned-engine
+}
+
+// String returns the problem as it's shown, ie: 12: error: web.main.start ...
+func (p Problem) String() string {
+	message := p.Message
+	if p.Rule != "" {
+		message = fmt.Sprintf("%s [%s]", message, p.Rule)
+	}
+
+	if p.Path == "" {
+		return fmt.Sprintf("%d: %s: %s", p.Line, p.Severity, message)
+	}
+
+	return fmt.Sprintf("%d: %s: %s: %s", p.Line, p.Severity, p.Path, message)
+}
+
+// the kinds of values a key takes
+type kind int
+
+const (
+	kindAny kind = iota
+	kindString
+	kindBool
+	kindInt
+	kindList
+	kindMap
+	kindStringOrList
+	kindStringOrMap
+	kindListOrMap
+)
+
+// the kinds as they are named in the problems
+var kindNames = map[kind]string{
+	kindString:       "a string",
+	kindBool:         "true or false",
+	kindInt:          "a whole number",
+	kindList:         "a list",
+	kindMap:          "a map",
+	kindStringOrList: "a string or a list",
+	kindStringOrMap:  "a string or a map",
+	kindListOrMap:    "a list or a map",
+}
+
+// node is the schema of a node of the boxfile
+type node struct {
+	keys     map[string]kind
+	required []string
+}
+
+// the keys of the code components, web.* and worker.*
+var codeKeys = map[string]kind{
+	"image":         kindString,
+	"start":         kindStringOrMap,
+	"stop":          kindStringOrMap,
+	"stop_timeout":  kindInt,
+	"stop_force":    kindBool,
+	"cwd":           kindStringOrMap,
+	"routes":        kindList,
+	"ports":         kindList,
+	"writable_dirs": kindList,
+	"network_dirs":  kindMap,
+	"log_watch":     kindMap,
+	"cron":          kindList,
+	"restart":       kindString,
+	"depends_on":    kindList,
+	"config":        kindMap,
+	"healthcheck":   kindString,
+	"mounts":        kindList,
+}
+
+// the nodes of the boxfile, by name or by the prefix of their name
+var (
+	nodes = map[string]node{
+		"run.config": {keys: map[string]kind{
+			"engine":          kindString,
+			"engine.config":   kindMap,
+			"engine_path":     kindString,
+			"image":           kindString,
+			"cache_dirs":      kindList,
+			"extra_packages":  kindList,
+			"dev_packages":    kindList,
+			"extra_path_dirs": kindList,
+			"extra_steps":     kindList,
+			"build_triggers":  kindList,
+			"fs_watch":        kindBool,
+			"cwd":             kindString,
+			"gpu":             kindBool,
+			"mirrors":         kindMap,
+			"parallel_steps":  kindListOrMap,
+			"before_build":    kindStringOrList,
+			"after_build":     kindStringOrList,
+			"before_deploy":   kindStringOrList,
+			"mounts":          kindList,
+		}},
+		"deploy.config": {keys: map[string]kind{
+			"extra_steps":         kindList,
+			"transform":           kindList,
+			"deploy_hook_timeout": kindInt,
+			"before_live":         kindMap,
+			"before_live_all":     kindMap,
+			"after_live":          kindMap,
+			"after_live_all":      kindMap,
+		}},
+	}
+
+	prefixes = map[string]node{
+		"web.":    {keys: codeKeys},
+		"worker.": {keys: codeKeys},
+		"data.": {keys: map[string]kind{
+			"image":          kindString,
+			"config":         kindMap,
+			"extra_packages": kindList,
+			"extra_steps":    kindList,
+			"cron":           kindList,
+			"restart":        kindString,
+			"depends_on":     kindList,
+			"shared":         kindString,
+			"mounts":         kindList,
+		}, required: []string{"image"}},
+		"sidecar.": {keys: map[string]kind{
+			"image":      kindString,
+			"start":      kindString,
+			"restart":    kindString,
+			"depends_on": kindList,
+			"config":     kindMap,
+			"mounts":     kindList,
+		}, required: []string{"image"}},
+	}
+)
+
+var (
+	// the names of the components after their prefix, ie: web.main
+	componentNameRegex = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+	// an engine from the registry or a repository, ie: ruby, ruby#v1.0.0 or
+	// https://github.com/nanobox-io/nanobox-engine-ruby.git
+	engineRegex = regexp.MustCompile(`^[A-Za-z0-9_.@:/-]+(#[A-Za-z0-9_./-]+)?$`)
+
+	// an engine on the local file system
+	localEngineRegex = regexp.MustCompile(`^[~|\.|\/|\\]`)
+
+	// the memory of a component, ie: 512, 512m or 2g
+	memoryRegex = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?[kKmMgG]?[bB]?$`)
+
+	// the line of a yaml syntax error
+	yamlLineRegex = regexp.MustCompile(`line ([0-9]+)`)
+)
+
+// ValidateFile validates the boxfile at path
+func ValidateFile(path string) ([]Problem, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Validate(data), nil
+}
+
+// Validate validates a boxfile, the problems are sorted by line
+func Validate(data []byte) []Problem {
+	doc := yaml.MapSlice{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		line := 0
+		if match := yamlLineRegex.FindStringSubmatch(err.Error()); len(match) > 1 {
+			line, _ = strconv.Atoi(match[1])
+		}
+		return []Problem{{Line: line, Severity: SeverityError, Message: fmt.Sprintf("invalid yaml: %s", strings.TrimPrefix(err.Error(), "yaml: "))}}
+	}
+
+	v := &validator{lines: lineIndex(data)}
+
+	hasCode := false
+	hasEngine := false
+
+	for _, item := range doc {
+		name := fmt.Sprintf("%v", item.Key)
+		schema, ok := nodeSchema(name)
+		if !ok {
+			v.add(SeverityWarning, []string{name}, "unknown node, it's ignored")
+			continue
+		}
+
+		if strings.HasPrefix(name, "web.") || strings.HasPrefix(name, "worker.") {
+			hasCode = true
+		}
+
+		values, ok := item.Value.(yaml.MapSlice)
+		if item.Value != nil && !ok {
+			v.add(SeverityError, []string{name}, "must be a map")
+			continue
+		}
+
+		v.validateNode(name, schema, values)
+
+		if name == "run.config" {
+			for _, value := range values {
+				switch fmt.Sprintf("%v", value.Key) {
+				case "engine":
+					hasEngine = true
+					v.validateEngine(value.Value)
+				case "engine_path":
+					hasEngine = true
+				}
+			}
+		}
+	}
+
+	if hasCode && !hasEngine {
+		v.add(SeverityError, []string{"run.config"}, "an engine is required to build the web and worker components, ie: engine: ruby")
+	}
+
+	sort.Stable(byLine(v.problems))
+
+	return v.problems
+}
+
+// HasErrors returns true if any of the problems is an error
+func HasErrors(problems []Problem) bool {
+	for _, problem := range problems {
+		if problem.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nodeSchema returns the schema of a node of the boxfile
+func nodeSchema(name string) (node, bool) {
+	if schema, ok := nodes[name]; ok {
+		return schema, true
+	}
+
+	for prefix, schema := range prefixes {
+		if strings.HasPrefix(name, prefix) && componentNameRegex.MatchString(strings.TrimPrefix(name, prefix)) {
+			return schema, true
+		}
+	}
+
+	return node{}, false
+}
+
+// validator collects the problems of a boxfile
+type validator struct {
+	lines    map[string]int
+	problems []Problem
+}
+
+// add adds a problem at the line of the key at path
+func (v *validator) add(severity string, path []string, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{
+		Line:     v.line(path),
+		Path:     strings.Join(path, "."),
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// line returns the line of the key at path, or of its closest parent
+func (v *validator) line(path []string) int {
+	for i := len(path); i > 0; i-- {
+		if line, ok := v.lines[strings.Join(path[:i], "/")]; ok {
+			return line
+		}
+	}
+
+	return 0
+}
+
+// validateNode checks the keys of a node and the kinds of their values
+func (v *validator) validateNode(name string, schema node, values yaml.MapSlice) {
+	found := map[string]bool{}
+
+	for _, item := range values {
+		key := fmt.Sprintf("%v", item.Key)
+		found[key] = true
+
+		expected, ok := schema.keys[key]
+		if !ok {
+			v.add(SeverityWarning, []string{name, key}, "unknown key, it's ignored")
+			continue
+		}
+
+		if !isKind(item.Value, expected) {
+			v.add(SeverityError, []string{name, key}, "must be %s", kindNames[expected])
+			continue
+		}
+
+		switch key {
+		case "config":
+			v.validateResources(name, item.Value)
+		case "mounts":
+			v.validateMounts(name, item.Value)
+		}
+	}
+
+	for _, key := range schema.required {
+		if !found[key] {
+			v.add(SeverityError, []string{name}, "'%s' is required", key)
+		}
+	}
+}
+
+// validateEngine checks the engine is a name, a repository or a path
+func (v *validator) validateEngine(value interface{}) {
+	engine, _ := value.(string)
+	path := []string{"run.config", "engine"}
+
+	switch {
+	case engine == "":
+		v.add(SeverityError, path, "the engine can't be empty")
+	case localEngineRegex.MatchString(engine):
+		// a local engine is checked when the build starts
+	case !engineRegex.MatchString(engine):
+		v.add(SeverityError, path, "'%s' isn't an engine name, repository or path", engine)
+	}
+}
+
+// validateResources checks the cpu and memory limits of a component
+func (v *validator) validateResources(name string, value interface{}) {
+	config, _ := value.(yaml.MapSlice)
+
+	for _, item := range config {
+		path := []string{name, "config", fmt.Sprintf("%v", item.Key)}
+
+		switch path[2] {
+		case "cpu":
+			if _, err := strconv.ParseFloat(fmt.Sprintf("%v", item.Value), 64); err != nil {
+				v.add(SeverityError, path, "must be a number of cores, ie: 1.5")
+			}
+		case "memory":
+			if !memoryRegex.MatchString(fmt.Sprintf("%v", item.Value)) {
+				v.add(SeverityError, path, "must be a size, ie: 512m or 2g")
+			}
+		}
+	}
+}
+
+// validateMounts checks each host directory mount is host:container
+func (v *validator) validateMounts(name string, value interface{}) {
+	mounts, _ := value.([]interface{})
+	path := []string{name, "mounts"}
+
+	for _, item := range mounts {
+		spec, ok := item.(string)
+		if !ok {
+			v.add(SeverityError, path, "a mount must be a string, ie: ../my-gem:/opt/gems/my-gem")
+			continue
+		}
+
+		if _, err := hostmount.Parse(spec, ""); err != nil {
+			v.add(SeverityError, path, "%s, ie: ../my-gem:/opt/gems/my-gem", err.Error())
+		}
+	}
+}
+
+// isKind returns true if the value is of the kind
+func isKind(value interface{}, expected kind) bool {
+	// an empty key is the same as a missing one
+	if value == nil {
+		return true
+	}
+
+	switch value.(type) {
+	case string:
+		return oneOf(expected, kindAny, kindString, kindStringOrList, kindStringOrMap)
+	case bool:
+		return oneOf(expected, kindAny, kindBool)
+	case int, int64, uint64:
+		return oneOf(expected, kindAny, kindInt)
+	case []interface{}:
+		return oneOf(expected, kindAny, kindList, kindStringOrList, kindListOrMap)
+	case yaml.MapSlice:
+		return oneOf(expected, kindAny, kindMap, kindStringOrMap, kindListOrMap)
+	}
+
+	return expected == kindAny
+}
+
+// oneOf returns true if the kind is one of the kinds
+func oneOf(k kind, kinds ...kind) bool {
+	for _, other := range kinds {
+		if k == other {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lineIndex returns the line of each key of a block style yaml document, by
+// the path of the key joined with '/'. The items of lists aren't indexed,
+// their problems are reported at the line of the list.
+func lineIndex(data []byte) map[string]int {
+	lines := map[string]int{}
+
+	type level struct {
+		indent int
+		path   string
+	}
+	stack := []level{}
+
+	keyRegex := regexp.MustCompile(`^(?:"([^"]+)"|'([^']+)'|([^'"#:\s][^#:]*?))\s*:(?:\s|$)`)
+
+	for i, text := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "-") {
+			continue
+		}
+
+		match := keyRegex.FindStringSubmatch(trimmed)
+		if match == nil {
+			continue
+		}
+		key := match[1] + match[2] + match[3]
+
+		indent := len(text) - len(trimmed)
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		path := key
+		if len(stack) > 0 {
+			path = stack[len(stack)-1].path + "/" + key
+		}
+
+		if _, ok := lines[path]; !ok {
+			lines[path] = i + 1
+		}
+		stack = append(stack, level{indent, path})
+	}
+
+	return lines
+}
+
+// byLine sorts the problems by line
+type byLine []Problem
+
+func (p byLine) Len() int           { return len(p) }
+func (p byLine) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p byLine) Less(i, j int) bool { return p[i].Line < p[j].Line }
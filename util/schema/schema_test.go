@@ -0,0 +1,151 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/schema"
+)
+
+func TestValidate(t *testing.T) {
+	box := `run.config:
+  engine: ruby
+  # a comment
+  cache_dirs: vendor
+
+web.main:
+  start: bundle exec puma
+  ports:
+    - tcp:2222:22
+  config:
+    memory: lots
+  colour: blue
+
+data.db:
+  config:
+    version: 9.6
+
+unknown.node:
+  key: value
+`
+
+	problems := schema.Validate([]byte(box))
+
+	expected := []schema.Problem{
+		{Line: 4, Path: "run.config.cache_dirs", Severity: schema.SeverityError},
+		{Line: 11, Path: "web.main.config.memory", Severity: schema.SeverityError},
+		{Line: 12, Path: "web.main.colour", Severity: schema.SeverityWarning},
+		{Line: 14, Path: "data.db", Severity: schema.SeverityError},
+		{Line: 18, Path: "unknown.node", Severity: schema.SeverityWarning},
+	}
+
+	if len(problems) != len(expected) {
+		t.Fatalf("expected %d problems, got %d: %v", len(expected), len(problems), problems)
+	}
+
+	for i, problem := range problems {
+		if problem.Line != expected[i].Line || problem.Path != expected[i].Path || problem.Severity != expected[i].Severity {
+			t.Errorf("expected %v, got %v", expected[i], problem)
+		}
+	}
+
+	if !schema.HasErrors(problems) {
+		t.Errorf("expected errors")
+	}
+}
+
+func TestValidateMounts(t *testing.T) {
+	box := `run.config:
+  engine: ruby
+  mounts:
+    - ../my-gem:/opt/gems/my-gem
+    - ../other-gem:opt/gems/other-gem
+
+data.db:
+  image: nanobox/postgresql
+  mounts:
+    - ./extensions:/usr/share/extensions:ro
+`
+
+	problems := schema.Validate([]byte(box))
+
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d: %v", len(problems), problems)
+	}
+
+	if problems[0].Path != "run.config.mounts" || problems[0].Severity != schema.SeverityError {
+		t.Errorf("expected an error on run.config.mounts, got %v", problems[0])
+	}
+}
+
+func TestValidateEngine(t *testing.T) {
+	tests := []struct {
+		box   string
+		valid bool
+	}{
+		{"run.config:\n  engine: ruby\nweb.main:\n  start: puma\n", true},
+		{"run.config:\n  engine: ruby#v1.2\n", true},
+		{"run.config:\n  engine: ../my-engine\n", true},
+		{"run.config:\n  engine: not an engine\n", false},
+		{"run.config:\n  engine:\n    - ruby\n", false},
+		{"web.main:\n  start: puma\n", false},
+		{"run.config:\n  engine: [ruby\n", false},
+	}
+
+	for _, test := range tests {
+		problems := schema.Validate([]byte(test.box))
+		if schema.HasErrors(problems) == test.valid {
+			t.Errorf("expected valid to be %t for %q, got %v", test.valid, test.box, problems)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	box := `run.config:
+  engine: ruby
+
+web.main:
+  start: bundle exec puma
+  ports:
+    - tcp:5432:5432
+    - 2375:2375
+    - tcp:8080:8080
+
+data.db:
+  image: nanobox/postgresql
+  config:
+    memory: 16g
+
+data.cache:
+  image: registry.local:5000/redis:3.2
+`
+
+	problems := schema.Lint([]byte(box))
+
+	expected := []schema.Problem{
+		{Line: 2, Rule: schema.RuleUnpinnedEngine, Severity: schema.SeverityWarning},
+		{Line: 4, Rule: schema.RuleNoHealthcheck, Severity: schema.SeverityInfo},
+		{Line: 6, Rule: schema.RuleWorldOpenPort, Severity: schema.SeverityWarning},
+		{Line: 6, Rule: schema.RuleWorldOpenPort, Severity: schema.SeverityError},
+		{Line: 12, Rule: schema.RuleUnpinnedImage, Severity: schema.SeverityWarning},
+		{Line: 14, Rule: schema.RuleHugeMemory, Severity: schema.SeverityWarning},
+	}
+
+	if len(problems) != len(expected) {
+		t.Fatalf("expected %d problems, got %d: %v", len(expected), len(problems), problems)
+	}
+
+	for i, problem := range problems {
+		if problem.Line != expected[i].Line || problem.Rule != expected[i].Rule || problem.Severity != expected[i].Severity {
+			t.Errorf("expected %v, got %v", expected[i], problem)
+		}
+	}
+
+	if !schema.HasWarnings(problems) {
+		t.Errorf("expected warnings")
+	}
+
+	pinned := schema.Lint([]byte("run.config:\n  engine: ruby#v1.0.0\n\nweb.main:\n  start: puma\n  healthcheck: /health\n"))
+	if schema.HasWarnings(pinned) || len(pinned) != 0 {
+		t.Errorf("expected no problems, got %v", pinned)
+	}
+}
@@ -0,0 +1,176 @@
+// Package secret encrypts small values (credentials, tokens) before they are
+// persisted. The key is generated on first use and kept in the os keychain,
+// or in the global nanobox dir, readable only by the current user, when there
+// is no keychain.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/nanobox-io/nanobox/util/config"
+	"github.com/nanobox-io/nanobox/util/keychain"
+)
+
+// keySize is the size of the AES-256 key
+const keySize = 32
+
+// KeyFile returns the path of the encryption key
+func KeyFile() string {
+	return filepath.ToSlash(filepath.Join(config.GlobalDir(), "secret.key"))
+}
+
+// Encrypt encrypts plain and returns it base64 encoded
+func Encrypt(plain string) (string, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %s", err.Error())
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value returned by Encrypt
+func Decrypt(encrypted string) (string, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret: %s", err.Error())
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("secret is too short")
+	}
+
+	nonce, sealed := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+
+	plain, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret: %s", err.Error())
+	}
+
+	return string(plain), nil
+}
+
+// newGCM creates the cipher from the key on disk
+func newGCM() (cipher.AEAD, error) {
+	key, err := loadKey()
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %s", err.Error())
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// keyAccount is the keychain account of the key
+const keyAccount = "secret-key"
+
+// keychainMarker is left next to where the key file was once the key is in
+// the keychain, so a keychain that stops working isn't taken for a first run
+func keychainMarker() string {
+	return KeyFile() + ".keychain"
+}
+
+// loadKey reads the key, generating it if it doesn't exist yet. The key is
+// kept in the os keychain when there is one, a key file from before is moved
+// there.
+func loadKey() ([]byte, error) {
+	encoded, err := keychain.Get(keyAccount)
+	if err == nil {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("secret key in the keychain is corrupt")
+		}
+		return key, nil
+	}
+
+	// anything but a missing key means the keychain holds it but won't give
+	// it up, a new key would make every secret unreadable
+	if err != keychain.ErrNotFound && err != keychain.ErrUnavailable {
+		return nil, fmt.Errorf("failed to read secret key from keychain: %s", err.Error())
+	}
+	keychainErr := err
+
+	key, err := ioutil.ReadFile(KeyFile())
+	if err == nil && len(key) == keySize {
+		// the file is only removed once the keychain gives the key back
+		if storeKey(key) == nil {
+			os.Remove(KeyFile())
+		}
+		return key, nil
+	}
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read secret key: %s", err.Error())
+	}
+
+	if err == nil {
+		return nil, fmt.Errorf("secret key %s is corrupt", KeyFile())
+	}
+
+	// the key was moved to the keychain, it's there but can't be read
+	if _, err := os.Stat(keychainMarker()); err == nil {
+		return nil, fmt.Errorf("the secret key is kept in the os keychain, which can't be read (%s), remove %s to start over with a new key and lose the saved secrets", keychainErr.Error(), keychainMarker())
+	}
+
+	key = make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("failed to generate secret key: %s", err.Error())
+	}
+
+	if storeKey(key) == nil {
+		return key, nil
+	}
+
+	if err := ioutil.WriteFile(KeyFile(), key, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write secret key: %s", err.Error())
+	}
+
+	return key, nil
+}
+
+// storeKey puts the key in the keychain and reads it back, the key is only
+// taken to be in the keychain when both work
+func storeKey(key []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(key)
+	if err := keychain.Set(keyAccount, encoded); err != nil {
+		return err
+	}
+
+	stored, err := keychain.Get(keyAccount)
+	if err == nil && stored != encoded {
+		err = fmt.Errorf("the keychain returned a different key")
+	}
+	if err == nil {
+		err = ioutil.WriteFile(keychainMarker(), nil, 0600)
+	}
+	if err != nil {
+		keychain.Delete(keyAccount)
+		return err
+	}
+
+	return nil
+}
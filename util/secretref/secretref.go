@@ -0,0 +1,122 @@
+// Package secretref resolves evars that reference an external secret
+// backend, ie: vault:secret/data/app#api_key. The values are resolved when a
+// container starts and only live in its environment, the evars keep the
+// reference.
+package secretref
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jcelliott/lumber"
+)
+
+// Resolver looks up secrets in a backend
+type Resolver interface {
+	// Resolve returns the field of the secret at path
+	Resolve(path, field string) (string, error)
+}
+
+var (
+	resolvers = map[string]Resolver{}
+
+	// the values resolved by this run, a start resolves the same evars a few
+	// times over
+	cache   = map[string]string{}
+	cacheMu sync.Mutex
+)
+
+// Register makes a backend available under a scheme
+func Register(scheme string, resolver Resolver) {
+	resolvers[scheme] = resolver
+}
+
+// IsReference returns true if the value references a registered backend
+func IsReference(val string) bool {
+	scheme, _, _, ok := parse(val)
+	if !ok {
+		return false
+	}
+
+	_, registered := resolvers[scheme]
+	return registered
+}
+
+// Resolve returns the secret a value references, values that aren't
+// references are returned as they are
+func Resolve(val string) (string, error) {
+	scheme, path, field, ok := parse(val)
+	if !ok {
+		return val, nil
+	}
+
+	resolver, registered := resolvers[scheme]
+	if !registered {
+		return val, nil
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if resolved, ok := cache[val]; ok {
+		return resolved, nil
+	}
+
+	resolved, err := resolver.Resolve(path, field)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %s", val, err.Error())
+	}
+	cache[val] = resolved
+
+	return resolved, nil
+}
+
+// ResolveAll returns a copy of the evars with the references resolved
+func ResolveAll(evars map[string]string) (map[string]string, error) {
+	resolved := map[string]string{}
+
+	for key, val := range evars {
+		secret, err := Resolve(val)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", key, err.Error())
+		}
+		resolved[key] = secret
+	}
+
+	return resolved, nil
+}
+
+// Resolved returns a copy of the evars with the references resolved, for
+// callers that can't fail. A reference that doesn't resolve is left out so
+// it never reaches the app as its value, ResolveAll reports it.
+func Resolved(evars map[string]string) map[string]string {
+	resolved := map[string]string{}
+
+	for key, val := range evars {
+		secret, err := Resolve(val)
+		if err != nil {
+			lumber.Error("secretref:Resolved:Resolve(%s): %s", key, err.Error())
+			continue
+		}
+		resolved[key] = secret
+	}
+
+	return resolved
+}
+
+// parse splits a reference into its scheme, path and field. The field is
+// optional, ie: vault:secret/data/app#api_key
+func parse(val string) (scheme, path, field string, ok bool) {
+	parts := strings.SplitN(val, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.ContainsAny(parts[0], " /") {
+		return "", "", "", false
+	}
+
+	scheme, path = parts[0], parts[1]
+	if i := strings.LastIndex(path, "#"); i != -1 {
+		path, field = path[:i], path[i+1:]
+	}
+
+	return scheme, path, field, path != ""
+}
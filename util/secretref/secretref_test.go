@@ -0,0 +1,63 @@
+package secretref_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/secretref"
+)
+
+type fakeResolver map[string]string
+
+func (f fakeResolver) Resolve(path, field string) (string, error) {
+	val, ok := f[path+"#"+field]
+	if !ok {
+		return "", fmt.Errorf("not found")
+	}
+	return val, nil
+}
+
+func init() {
+	secretref.Register("fake", fakeResolver{"secret/app#api_key": "s3cr3t"})
+}
+
+func TestIsReference(t *testing.T) {
+	refs := map[string]bool{
+		"fake:secret/app#api_key": true,
+		"fake:secret/app":         true,
+		"plain":                   false,
+		"postgres://user@host":    false,
+		"http://example.com":      false,
+		"unknown:secret/app":      false,
+	}
+
+	for val, expected := range refs {
+		if secretref.IsReference(val) != expected {
+			t.Errorf("expected IsReference(%s) to be %t", val, expected)
+		}
+	}
+}
+
+func TestResolveAll(t *testing.T) {
+	evars := map[string]string{
+		"API_KEY": "fake:secret/app#api_key",
+		"PLAIN":   "value",
+	}
+
+	resolved, err := secretref.ResolveAll(evars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resolved["API_KEY"] != "s3cr3t" || resolved["PLAIN"] != "value" {
+		t.Errorf("unexpected evars %v", resolved)
+	}
+
+	if evars["API_KEY"] != "fake:secret/app#api_key" {
+		t.Errorf("the evars shouldn't change")
+	}
+
+	if _, err := secretref.ResolveAll(map[string]string{"MISSING": "fake:secret/other#key"}); err == nil {
+		t.Errorf("expected an error for a missing secret")
+	}
+}
@@ -0,0 +1,104 @@
+package secretref
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+func init() {
+	Register("vault", vault{})
+}
+
+// vault resolves secrets from HashiCorp Vault, with the same environment
+// the vault cli uses: VAULT_ADDR, VAULT_TOKEN (or ~/.vault-token) and
+// VAULT_NAMESPACE. Both versions of the kv engine are supported.
+type vault struct{}
+
+// Resolve reads the secret at path. The field defaults to 'value'.
+func (v vault) Resolve(path, field string) (string, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return "", fmt.Errorf("VAULT_ADDR isn't set")
+	}
+
+	token, err := vaultToken()
+	if err != nil {
+		return "", err
+	}
+
+	if field == "" {
+		field = "value"
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/v1/%s", strings.TrimSuffix(addr, "/"), strings.TrimPrefix(path, "/")), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	client := http.Client{Timeout: 10 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if res.StatusCode != 200 {
+		return "", fmt.Errorf("vault responded with %d", res.StatusCode)
+	}
+
+	// kv v2 nests the secret in data.data
+	secret := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("failed to decode the vault response: %s", err.Error())
+	}
+
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+
+	val, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("the secret has no field '%s'", field)
+	}
+
+	if str, ok := val.(string); ok {
+		return str, nil
+	}
+
+	return fmt.Sprintf("%v", val), nil
+}
+
+// vaultToken returns the token of the vault cli
+func vaultToken() (string, error) {
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	home, _ := homedir.Dir()
+	token, err := ioutil.ReadFile(filepath.Join(home, ".vault-token"))
+	if err != nil {
+		return "", fmt.Errorf("VAULT_TOKEN isn't set and there is no ~/.vault-token")
+	}
+
+	return strings.TrimSpace(string(token)), nil
+}
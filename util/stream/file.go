@@ -0,0 +1,111 @@
+package stream
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/nanopack/mist/core"
+)
+
+// fileStream replays the messages recorded in a file, a message of json
+// per line, see Record
+type fileStream struct {
+	path     string
+	messages chan mist.Message
+	done     chan struct{}
+	closed   chan struct{}
+
+	mu            sync.Mutex
+	subscriptions [][]string
+	started       bool
+	once          sync.Once
+}
+
+// NewFile replays the messages recorded in the file at path
+func NewFile(path string) (MessageStream, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open the recorded messages - %s", err.Error())
+	}
+
+	return &fileStream{
+		path:     path,
+		messages: make(chan mist.Message),
+		done:     make(chan struct{}),
+		closed:   make(chan struct{}),
+	}, nil
+}
+
+// Subscribe subscribes to the recorded messages with the tags, the replay
+// starts with the first subscription
+func (s *fileStream) Subscribe(tags []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscriptions = append(s.subscriptions, tags)
+
+	if !s.started {
+		s.started = true
+		go s.replay()
+	}
+
+	return nil
+}
+
+// Publish does nothing, there is no one to publish to
+func (s *fileStream) Publish(tags []string, data string) error {
+	return nil
+}
+
+// replay sends the recorded messages the subscriptions match
+func (s *fileStream) replay() {
+	defer close(s.done)
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		msg := mist.Message{}
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue
+		}
+
+		s.mu.Lock()
+		wanted := matches(s.subscriptions, msg.Tags)
+		s.mu.Unlock()
+
+		if !wanted {
+			continue
+		}
+
+		select {
+		case s.messages <- msg:
+		case <-s.closed:
+			return
+		}
+	}
+}
+
+// Messages returns the recorded messages
+func (s *fileStream) Messages() <-chan mist.Message {
+	return s.messages
+}
+
+// Done is closed once every message is replayed
+func (s *fileStream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close stops the replay
+func (s *fileStream) Close() error {
+	s.once.Do(func() { close(s.closed) })
+	return nil
+}
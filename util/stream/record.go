@@ -0,0 +1,91 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/nanopack/mist/core"
+)
+
+// recorder records the messages of a stream to a file as they come in, so
+// they can be replayed with the file transport
+type recorder struct {
+	MessageStream
+
+	file     *os.File
+	messages chan mist.Message
+	done     chan struct{}
+	closed   chan struct{}
+	once     sync.Once
+}
+
+// Record records the messages of a stream to the file at path, it's
+// appended to
+func Record(stream MessageStream, path string) (MessageStream, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s to record to - %s", path, err.Error())
+	}
+
+	r := &recorder{
+		MessageStream: stream,
+		file:          file,
+		messages:      make(chan mist.Message),
+		done:          make(chan struct{}),
+		closed:        make(chan struct{}),
+	}
+
+	go r.record()
+
+	return r, nil
+}
+
+// record writes each message to the file before passing it on, until the
+// stream ends
+func (r *recorder) record() {
+	defer close(r.done)
+
+	encoder := json.NewEncoder(r.file)
+	in := r.MessageStream.Messages()
+
+	for {
+		select {
+		case msg := <-in:
+			if msg.Error == "" {
+				encoder.Encode(mist.Message{Tags: msg.Tags, Data: msg.Data})
+			}
+
+			select {
+			case r.messages <- msg:
+			case <-r.closed:
+				return
+			}
+		case <-r.MessageStream.Done():
+			return
+		case <-r.closed:
+			return
+		}
+	}
+}
+
+// Messages returns the messages of the stream, once they're recorded
+func (r *recorder) Messages() <-chan mist.Message {
+	return r.messages
+}
+
+// Done is closed once the stream ends
+func (r *recorder) Done() <-chan struct{} {
+	return r.done
+}
+
+// Close closes the stream and the file
+func (r *recorder) Close() error {
+	r.once.Do(func() { close(r.closed) })
+
+	err := r.MessageStream.Close()
+	r.file.Close()
+
+	return err
+}
@@ -0,0 +1,82 @@
+// Package stream connects to mist, the message bus the logs of the
+// components are published on, over the transport that works where nanobox
+// runs: mist's tcp port, a websocket, or a file of recorded messages.
+package stream
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nanopack/mist/core"
+)
+
+// the ports mist listens on
+const (
+	tcpPort       = "1445"
+	websocketPort = "1446"
+)
+
+// MessageStream is a connection to mist
+type MessageStream interface {
+	// Subscribe subscribes to the messages with the tags
+	Subscribe(tags []string) error
+	// Publish publishes data with the tags
+	Publish(tags []string, data string) error
+	// Messages returns the messages of the subscriptions
+	Messages() <-chan mist.Message
+	// Done returns a channel that is closed once the stream ends, ie: the
+	// connection dropped or the replay is over
+	Done() <-chan struct{}
+	// Close closes the stream
+	Close() error
+}
+
+// Dial connects to the mist at host over a transport, one of:
+//
+//	tcp           mist's raw tcp port, the default
+//	websocket     mist's websocket, for networks that block the tcp port
+//	file:<path>   replays the messages recorded in a file, offline
+func Dial(transport, host, token string) (MessageStream, error) {
+	switch {
+	case transport == "" || transport == "tcp":
+		return NewTCP(host+":"+tcpPort, token)
+	case transport == "websocket":
+		return NewWebSocket(WebSocketURL(host, token))
+	case strings.HasPrefix(transport, "file:"):
+		return NewFile(strings.TrimPrefix(transport, "file:"))
+	}
+
+	return nil, fmt.Errorf("'%s' isn't a mist transport, use tcp, websocket or file:<path>", transport)
+}
+
+// WebSocketURL returns the url of the websocket of the mist at host
+func WebSocketURL(host, token string) string {
+	return "wss://" + host + ":" + websocketPort + "/subscribe/websocket?X-AUTH-TOKEN=" + token
+}
+
+// matches returns true if a message has all of the tags of a subscription
+func matches(subscriptions [][]string, tags []string) bool {
+	if len(subscriptions) == 0 {
+		return false
+	}
+
+	has := map[string]bool{}
+	for _, tag := range tags {
+		has[tag] = true
+	}
+
+	for _, subscription := range subscriptions {
+		found := true
+		for _, tag := range subscription {
+			if !has[tag] {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,74 @@
+package stream_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/stream"
+)
+
+func TestReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nanobox-stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	recording := filepath.Join(dir, "recording.json")
+	data := `{"tags":["log","app"],"data":"GET / 200"}
+not a message
+{"tags":["job"],"data":"unrelated"}
+{"tags":["log","deploy"],"data":"compiling"}
+`
+	if err := ioutil.WriteFile(recording, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := stream.Dial("file:"+recording, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the replay is recorded again, to check the recorder keeps the messages
+	copied := filepath.Join(dir, "copy.json")
+	client, err := stream.Record(replay, copied)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if err := client.Subscribe([]string{"log"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := []string{}
+	for done := false; !done; {
+		select {
+		case msg := <-client.Messages():
+			got = append(got, msg.Data)
+		case <-client.Done():
+			done = true
+		}
+	}
+
+	if len(got) != 2 || got[0] != "GET / 200" || got[1] != "compiling" {
+		t.Errorf("expected the messages tagged log, got %v", got)
+	}
+
+	again, err := stream.NewFile(copied)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again.Subscribe([]string{"log", "deploy"})
+
+	msg := <-again.Messages()
+	if msg.Data != "compiling" {
+		t.Errorf("expected the recording to be replayed, got %q", msg.Data)
+	}
+
+	if _, err := stream.Dial("carrier-pigeon", "", ""); err == nil {
+		t.Errorf("expected an unknown transport to fail")
+	}
+}
@@ -0,0 +1,51 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/nanopack/mist/clients"
+	"github.com/nanopack/mist/core"
+)
+
+// tcpStream is a connection to mist's raw tcp port
+type tcpStream struct {
+	client *clients.TCP
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewTCP connects to mist's tcp port at address, ie: 192.168.0.5:1445
+func NewTCP(address, token string) (MessageStream, error) {
+	client, err := clients.New(address, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tcpStream{client: client, done: make(chan struct{})}, nil
+}
+
+// Subscribe subscribes to the messages with the tags
+func (s *tcpStream) Subscribe(tags []string) error {
+	return s.client.Subscribe(tags)
+}
+
+// Publish publishes data with the tags
+func (s *tcpStream) Publish(tags []string, data string) error {
+	return s.client.Publish(tags, data)
+}
+
+// Messages returns the messages of the subscriptions
+func (s *tcpStream) Messages() <-chan mist.Message {
+	return s.client.Messages()
+}
+
+// Done is closed once the stream is closed
+func (s *tcpStream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close closes the connection
+func (s *tcpStream) Close() error {
+	s.once.Do(func() { close(s.done) })
+	return s.client.Close()
+}
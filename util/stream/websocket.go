@@ -0,0 +1,122 @@
+package stream
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/nanopack/mist/core"
+	"golang.org/x/net/websocket"
+)
+
+// the origin the websocket is opened from
+const websocketOrigin = "https://nanoapp.localhost"
+
+// websocketStream is a connection to mist's websocket
+type websocketStream struct {
+	conn     *websocket.Conn
+	messages chan mist.Message
+	done     chan struct{}
+
+	mu sync.Mutex // writes to the connection
+}
+
+// command is a command sent to mist
+type command struct {
+	Command string   `json:"command"`
+	Tags    []string `json:"tags"`
+	Data    string   `json:"data,omitempty"`
+}
+
+// NewWebSocket connects to mist's websocket at url, see WebSocketURL
+func NewWebSocket(url string) (MessageStream, error) {
+	config, err := websocket.NewConfig(url, websocketOrigin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config - %s", err.Error())
+	}
+
+	config.TlsConfig = &tls.Config{InsecureSkipVerify: true}
+
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial mist - %s", err.Error())
+	}
+
+	s := &websocketStream{
+		conn:     conn,
+		messages: make(chan mist.Message, 1),
+		done:     make(chan struct{}),
+	}
+
+	go s.read()
+
+	return s, nil
+}
+
+// read reads the messages off the connection until it drops
+func (s *websocketStream) read() {
+	// once the connection is gone, let the listener know
+	defer close(s.done)
+
+	decoder := json.NewDecoder(s.conn)
+
+	for decoder.More() {
+		msg := mist.Message{}
+
+		if err := decoder.Decode(&msg); err != nil {
+			// invalid character '\x15' looking for beginning of value
+			if strings.Contains(err.Error(), "invalid character '\\x15'") {
+				fmt.Printf("Must dial TLS - %s\n", err.Error())
+				return
+			}
+
+			// an error decoding should be sent to the user
+			bytes, _ := ioutil.ReadAll(decoder.Buffered())
+			msg.Error = string(bytes)
+		}
+
+		s.messages <- msg
+	}
+}
+
+// Subscribe subscribes to the messages with the tags
+func (s *websocketStream) Subscribe(tags []string) error {
+	return s.send(command{Command: "subscribe", Tags: tags})
+}
+
+// Publish publishes data with the tags
+func (s *websocketStream) Publish(tags []string, data string) error {
+	return s.send(command{Command: "publish", Tags: tags, Data: data})
+}
+
+// send writes a command to the connection
+func (s *websocketStream) send(cmd command) error {
+	b, err := json.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.conn.Write(b)
+	return err
+}
+
+// Messages returns the messages of the subscriptions
+func (s *websocketStream) Messages() <-chan mist.Message {
+	return s.messages
+}
+
+// Done is closed once the connection drops
+func (s *websocketStream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close closes the connection
+func (s *websocketStream) Close() error {
+	return s.conn.Close()
+}
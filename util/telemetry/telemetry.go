@@ -0,0 +1,222 @@
+// Package telemetry records, for the users that opt in, which commands ran,
+// how long they took and the class of the error they failed with, and sends
+// them in batches to an endpoint, nanobox's or a self-hosted one. It helps
+// the maintainers see which commands matter and where they fail. Nothing in
+// an event identifies the user or the app: no arguments, paths, names or
+// error messages, and the id is random.
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jcelliott/lumber"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util"
+	"github.com/nanobox-io/nanobox/util/config"
+)
+
+const (
+	// how many events are sent at once
+	batchSize = 20
+
+	// the oldest events are dropped beyond this, ie: while the endpoint is
+	// unreachable
+	maxQueued = 1000
+)
+
+// Event is a command that ran
+type Event struct {
+	ID         string    `json:"id"` // random, one per install
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"` // ie: evar add
+	Version    string    `json:"version"`
+	OS         string    `json:"os"`
+	Arch       string    `json:"arch"`
+	Provider   string    `json:"provider"`
+	Duration   int64     `json:"duration_ms"`
+	Result     string    `json:"result"`
+	ErrorClass string    `json:"error_class,omitempty"` // the team of the error, ie: USER
+}
+
+var (
+	current *Event
+	once    sync.Once
+)
+
+// Path returns the location of the events that haven't been sent yet
+func Path() string {
+	return filepath.ToSlash(filepath.Join(config.GlobalDir(), "telemetry.log"))
+}
+
+// idPath returns the location of the id of this install
+func idPath() string {
+	return filepath.ToSlash(filepath.Join(config.GlobalDir(), "telemetry-id"))
+}
+
+// Start begins the event of a command, if telemetry is on
+func Start(command string) {
+	conf, _ := models.LoadConfig()
+	if !conf.Telemetry || command == "" {
+		return
+	}
+
+	current = &Event{
+		ID:       ID(),
+		Time:     time.Now(),
+		Command:  command,
+		Version:  models.VersionInfo()["version"],
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Provider: conf.Provider,
+	}
+}
+
+// Finish records how the command ended and sends the events once there are
+// enough of them. errClass is the team an error is tagged with, only the
+// first call counts.
+func Finish(failed bool, errClass string) {
+	once.Do(func() {
+		// it may have been turned off by the command, ie: telemetry off
+		conf, _ := models.LoadConfig()
+		if current == nil || !conf.Telemetry {
+			return
+		}
+
+		current.Duration = int64(time.Since(current.Time) / time.Millisecond)
+		current.Result = "ok"
+		if failed {
+			current.Result = "failed"
+			current.ErrorClass = errClass
+			if current.ErrorClass == "" {
+				current.ErrorClass = "UNKNOWN"
+			}
+		}
+
+		events, _ := Queued()
+		events = append(events, *current)
+		if len(events) > maxQueued {
+			events = events[len(events)-maxQueued:]
+		}
+
+		if len(events) >= batchSize {
+			if err := send(conf.TelemetryEndpoint, events); err != nil {
+				lumber.Debug("telemetry:send(%s): %s", conf.TelemetryEndpoint, err)
+			} else {
+				events = []Event{}
+			}
+		}
+
+		if err := write(events); err != nil {
+			lumber.Error("telemetry:write(): %s", err)
+		}
+	})
+}
+
+// ID returns the random id of this install, it's created the first time
+func ID() string {
+	data, err := ioutil.ReadFile(idPath())
+	if err == nil && len(bytes.TrimSpace(data)) > 0 {
+		return string(bytes.TrimSpace(data))
+	}
+
+	id := util.RandomString(32)
+	if err := ioutil.WriteFile(idPath(), []byte(id), 0644); err != nil {
+		lumber.Error("telemetry:ioutil.WriteFile(%s): %s", idPath(), err)
+	}
+
+	return id
+}
+
+// Queued returns the events that haven't been sent yet
+func Queued() ([]Event, error) {
+	events := []Event{}
+
+	file, err := os.Open(Path())
+	if os.IsNotExist(err) {
+		return events, nil
+	}
+	if err != nil {
+		return events, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		event := Event{}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return events, scanner.Err()
+}
+
+// Discard removes the events that haven't been sent and the id, a new one is
+// created if telemetry is turned back on
+func Discard() error {
+	for _, path := range []string{Path(), idPath()} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// write replaces the queue with events
+func write(events []Event) error {
+	buf := &bytes.Buffer{}
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(line, '\n'))
+	}
+
+	tmp := Path() + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, Path())
+}
+
+// send posts the events to the endpoint, it doesn't hold the command up for
+// long when the endpoint is slow or unreachable
+func send(endpoint string, events []Event) error {
+	if endpoint == "" {
+		return fmt.Errorf("no endpoint")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"events": events})
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 3 * time.Second}
+	res, err := client.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%s responded with %s", endpoint, strings.TrimSpace(res.Status))
+	}
+
+	return nil
+}
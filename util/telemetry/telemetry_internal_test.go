@@ -0,0 +1,33 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSend(t *testing.T) {
+	received := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		batch := map[string][]Event{}
+		json.Unmarshal(body, &batch)
+		received = len(batch["events"])
+	}))
+	defer server.Close()
+
+	events := []Event{{Command: "run"}, {Command: "deploy", Result: "failed", ErrorClass: "USER"}}
+	if err := send(server.URL, events); err != nil {
+		t.Errorf("failed to send: %s", err)
+	}
+
+	if received != 2 {
+		t.Errorf("expected 2 events, the endpoint received %d", received)
+	}
+
+	if err := send("", events); err == nil {
+		t.Errorf("sent without an endpoint")
+	}
+}
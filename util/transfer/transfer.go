@@ -0,0 +1,261 @@
+// Package transfer packs and unpacks the tar archives the docker copy api
+// speaks, so files can be moved between the host and a component.
+package transfer
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/nanobox-io/nanobox/util/hostpath"
+)
+
+// the os of the host, the files packed from windows are fixed up for linux
+var hostOS = runtime.GOOS
+
+// Size returns the number of bytes in the regular files under root, which is
+// what Pack reports as progress
+func Size(root string) (int64, error) {
+	var size int64
+
+	err := filepath.Walk(root, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+
+	return size, err
+}
+
+// Pack writes the file or directory at root to w as a tar archive. The root
+// entry is named name, directories are packed recursively. progress, if not
+// nil, is called with the number of bytes of file content written.
+func Pack(root, name string, w io.Writer, progress func(int64)) error {
+	tw := tar.NewWriter(w)
+
+	err := filepath.Walk(root, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(name, filepath.ToSlash(rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		// a windows filesystem keeps no execute bit nor unix line ends
+		f, err := hostpath.ForContainer(file, info, hostOS)
+		if err != nil {
+			return err
+		}
+		header.Mode = header.Mode&^0777 | int64(f.Mode.Perm())
+		if f.Data != nil {
+			header.Size = int64(len(f.Data))
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		if f.Data != nil {
+			_, err := (&progressWriter{tw, progress}).Write(f.Data)
+			return err
+		}
+
+		return copyFile(&progressWriter{tw, progress}, file)
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// Unpack extracts the archive read from r into dir. If name is not empty the
+// root entry of the archive is renamed to it. Entries that would be written
+// outside of dir are refused. progress, if not nil, is called with the
+// number of bytes of file content written.
+func Unpack(r io.Reader, dir, name string, progress func(int64)) error {
+	tr := tar.NewReader(r)
+	links := map[string]bool{}
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		entry := header.Name
+		if name != "" {
+			entry = rename(entry, name)
+		}
+
+		target, err := join(dir, entry)
+		if err != nil {
+			return err
+		}
+		if underLink(target, links) {
+			return fmt.Errorf("refusing to extract %s through a symlink", entry)
+		}
+
+		mode := os.FileMode(header.Mode).Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode|0700); err != nil {
+				return err
+			}
+
+		case tar.TypeReg, tar.TypeRegA:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := writeFile(target, mode, &progressReader{tr, progress}); err != nil {
+				return err
+			}
+
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			os.Remove(target)
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+			links[target] = true
+			continue
+
+		default:
+			// devices, fifos and hard links aren't worth copying to the host
+			continue
+		}
+
+		os.Chtimes(target, header.ModTime, header.ModTime)
+	}
+}
+
+// rename replaces the first element of the entry with name
+func rename(entry, name string) string {
+	parts := strings.SplitN(strings.TrimPrefix(entry, "./"), "/", 2)
+	if len(parts) == 1 {
+		return name
+	}
+	return name + "/" + parts[1]
+}
+
+// join returns the host path of the entry in dir, failing if it would be
+// outside of dir, ie: ../../etc/passwd
+func join(dir, entry string) (string, error) {
+	clean := path.Clean(strings.TrimPrefix(entry, "/"))
+	if clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("refusing to extract %s outside of %s", entry, dir)
+	}
+
+	return filepath.Join(dir, filepath.FromSlash(clean)), nil
+}
+
+// underLink returns true if target is one of the links or inside one, writing
+// through a link extracted from the archive could land anywhere
+func underLink(target string, links map[string]bool) bool {
+	for dir := target; dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if links[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFile replaces the file at target with the content of r. What was at
+// target is removed and the file is created anew, so a link that was there
+// isn't followed.
+func writeFile(target string, mode os.FileMode, r io.Reader) error {
+	if info, err := os.Lstat(target); err == nil && !info.IsDir() {
+		if err := os.Remove(target); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// copyFile copies the content of the file at file to w
+func copyFile(w io.Writer, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// progressWriter reports the bytes written through it
+type progressWriter struct {
+	w        io.Writer
+	progress func(int64)
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+	if pw.progress != nil && n > 0 {
+		pw.progress(int64(n))
+	}
+	return n, err
+}
+
+// progressReader reports the bytes read through it
+type progressReader struct {
+	r        io.Reader
+	progress func(int64)
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	if pr.progress != nil && n > 0 {
+		pr.progress(int64(n))
+	}
+	return n, err
+}
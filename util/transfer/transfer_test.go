@@ -0,0 +1,145 @@
+package transfer_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nanobox-io/nanobox/util/transfer"
+)
+
+func TestPackUnpack(t *testing.T) {
+	src, err := ioutil.TempDir("", "transfer-src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+
+	os.MkdirAll(filepath.Join(src, "dumps", "core"), 0755)
+	ioutil.WriteFile(filepath.Join(src, "dumps", "a.txt"), []byte("hello"), 0644)
+	ioutil.WriteFile(filepath.Join(src, "dumps", "core", "b.txt"), []byte("world!"), 0600)
+
+	size, err := transfer.Size(filepath.Join(src, "dumps"))
+	if err != nil || size != 11 {
+		t.Fatalf("size = %d, %v, want 11", size, err)
+	}
+
+	var packed int64
+	buf := &bytes.Buffer{}
+	err = transfer.Pack(filepath.Join(src, "dumps"), "dumps", buf, func(n int64) { packed += n })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packed != size {
+		t.Errorf("packed %d bytes, want %d", packed, size)
+	}
+
+	dest, err := ioutil.TempDir("", "transfer-dest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dest)
+
+	// the root entry is renamed, like cp does for a destination that doesn't exist
+	if err := transfer.Unpack(bytes.NewReader(buf.Bytes()), dest, "copy", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dest, "copy", "core", "b.txt"))
+	if err != nil || string(data) != "world!" {
+		t.Fatalf("b.txt = %q, %v", data, err)
+	}
+	info, err := os.Stat(filepath.Join(dest, "copy", "core", "b.txt"))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("b.txt mode = %v, %v, want 0600", info.Mode(), err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "copy", "a.txt")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUnpackOutside(t *testing.T) {
+	dest, err := ioutil.TempDir("", "transfer-dest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dest)
+
+	archives := map[string][]*tar.Header{
+		"parent": {
+			{Name: "../escape.txt", Typeflag: tar.TypeReg, Mode: 0644},
+		},
+		"symlink": {
+			{Name: "link", Typeflag: tar.TypeSymlink, Linkname: os.TempDir()},
+			{Name: "link/escape.txt", Typeflag: tar.TypeReg, Mode: 0644},
+		},
+	}
+
+	for name, headers := range archives {
+		buf := &bytes.Buffer{}
+		tw := tar.NewWriter(buf)
+		for _, header := range headers {
+			tw.WriteHeader(header)
+		}
+		tw.Close()
+
+		if err := transfer.Unpack(buf, dest, "", nil); err == nil {
+			t.Errorf("%s: expected the archive to be refused", name)
+		}
+	}
+}
+
+func TestUnpackOverLink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "transfer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	outside := filepath.Join(dir, "outside.txt")
+	if err := ioutil.WriteFile(outside, []byte("safe"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(dir, "dest")
+	os.Mkdir(dest, 0755)
+
+	// a link and then a file of the same name, the file would be written
+	// through the link
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: outside})
+	tw.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeReg, Mode: 0644, Size: 6})
+	tw.Write([]byte("pwned!"))
+	tw.Close()
+
+	if err := transfer.Unpack(buf, dest, "", nil); err == nil {
+		t.Errorf("expected the archive to be refused")
+	}
+
+	// a link left in the destination is replaced rather than followed
+	os.Remove(filepath.Join(dest, "link"))
+	if err := os.Symlink(outside, filepath.Join(dest, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	buf = &bytes.Buffer{}
+	tw = tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeReg, Mode: 0644, Size: 6})
+	tw.Write([]byte("pwned!"))
+	tw.Close()
+
+	if err := transfer.Unpack(buf, dest, "", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := ioutil.ReadFile(outside); string(data) != "safe" {
+		t.Errorf("the file outside of the destination was overwritten: %q", data)
+	}
+	if data, _ := ioutil.ReadFile(filepath.Join(dest, "link")); string(data) != "pwned!" {
+		t.Errorf("the link wasn't replaced by the file: %q", data)
+	}
+}
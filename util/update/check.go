@@ -13,35 +13,28 @@ import (
 // Check for updates to nanobox every other day
 const checkFrequency = (48 * time.Hour)
 
-// Check checks to see if there is an update available for the nanobox CLI
+// Check tells the user about a newer release of the nanobox CLI, if one was
+// found. The channel is checked in the background while the command runs, a
+// release it finds is told about the next time. Checking is opt-in, with
+// 'nanobox config set update-check true'.
 func Check() {
+	configModel, _ := models.LoadConfig()
+	if !configModel.UpdateCheck {
+		return
+	}
+
 	// load the update model
 	updateInfo, err := models.LoadUpdate()
 	if err != nil {
-		if strings.Contains(err.Error(), "no record found") {
-			checkTomorrow(&models.Update{})
+		if !strings.Contains(err.Error(), "no record found") {
+			lumber.Error("update:models.LoadUpdate(): %s", err)
 			return
 		}
-		lumber.Error("update:models.LoadUpdate(): %s", err)
-		return
-	}
-
-	// return early if it's not time to check yet
-	if !checkable(updateInfo) {
-		return
-	}
-
-	latest := latestVersion()
-	if latest == "" {
-		checkTomorrow(updateInfo)
-		return
+		updateInfo = &models.Update{}
 	}
-	lumber.Debug("CurrVers: %s\nLatest:   %s\n", models.VersionString(), latest)
 
-	// if the versions don't match and it's 'time' for an update (14 days) inform the
-	// user that updates are available
-	if models.VersionString() != latest {
-		//
+	// inform the user that an update is available
+	if updateInfo.Latest != "" && updateInfo.Channel == configModel.UpdateChannel && updateInfo.Latest != models.VersionString() {
 		fmt.Printf(`
 ------------------------------------------------
 Hey! A newer version of nanobox is available.
@@ -50,13 +43,31 @@ Hey! A newer version of nanobox is available.
 
 Run the following command to update:
 
-$ nanobox-update
+$ nanobox update-cli
 ------------------------------------------------
-`, latest)
+`, updateInfo.Latest)
+	}
+
+	// return early if it's not time to check yet
+	if !checkable(updateInfo) && updateInfo.Channel == configModel.UpdateChannel {
+		return
+	}
 
+	go refresh(updateInfo, configModel.UpdateChannel)
+}
+
+// refresh looks up the latest release on the channel
+func refresh(updateInfo *models.Update, channel string) {
+	latest, err := Latest(channel)
+	if err != nil {
+		checkTomorrow(updateInfo)
+		return
 	}
+	lumber.Debug("CurrVers: %s\nLatest:   %s\n", models.VersionString(), latest)
 
 	// renew the update last checked time
+	updateInfo.Latest = latest
+	updateInfo.Channel = channel
 	updateInfo.LastCheckAt = time.Now()
 	if err := updateInfo.Save(); err != nil {
 		lumber.Error("update:updateInfo.Save(): %s", err)
@@ -0,0 +1,153 @@
+package update
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/nanobox-io/nanobox/models"
+	"github.com/nanobox-io/nanobox/util/display"
+)
+
+// Download fetches the binary of a channel for this platform and verifies
+// it. The binary is written to a temporary file, its path and sha256 are
+// returned.
+func Download(channel string, progress io.Writer) (string, string, error) {
+	checksum, err := fetch(channelPath(channel, Name+".sha256"), nil)
+	if err != nil {
+		return "", "", err
+	}
+
+	signature, err := fetch(channelPath(channel, Name+".sig"), nil)
+	if err != nil {
+		return "", "", err
+	}
+
+	binary, err := fetch(channelPath(channel, Name), progress)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := verify(binary, checksum, signature); err != nil {
+		return "", "", err
+	}
+
+	tmpFile, err := ioutil.TempFile("", "nanobox-update")
+	if err != nil {
+		return "", "", err
+	}
+	defer tmpFile.Close()
+
+	if _, err := tmpFile.Write(binary); err != nil {
+		os.Remove(tmpFile.Name())
+		return "", "", err
+	}
+
+	digest := sha256.Sum256(binary)
+
+	return tmpFile.Name(), hex.EncodeToString(digest[:]), nil
+}
+
+// Install replaces the executable at path with the binary at src, provided
+// it still has the digest it was verified with. The binary is copied next to
+// the executable and renamed over it, so the executable is never partly
+// written.
+func Install(src, digest, path string) error {
+	binary, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(binary)
+	if hex.EncodeToString(sum[:]) != digest {
+		return fmt.Errorf("%s changed since it was verified", src)
+	}
+
+	tmpFileName := filepath.Join(filepath.Dir(path), TmpName)
+	tmpFile, err := os.OpenFile(tmpFileName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmpFile.Write(binary); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFileName)
+		return err
+	}
+
+	// make sure it's on disk before it takes the place of the executable
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFileName)
+		return err
+	}
+	tmpFile.Close()
+
+	// windows won't replace a running executable, but it will rename it
+	old := ""
+	if runtime.GOOS == "windows" {
+		old = path + ".old"
+		os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			os.Remove(tmpFileName)
+			return err
+		}
+	}
+
+	if err := os.Rename(tmpFileName, path); err != nil {
+		os.Remove(tmpFileName)
+
+		// put the executable back, otherwise there would be none
+		if old != "" {
+			if err2 := os.Rename(old, path); err2 != nil {
+				return fmt.Errorf("%s, and the previous version couldn't be restored from %s: %s", err.Error(), old, err2.Error())
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Installed records that nanobox was updated
+func Installed() error {
+	update, _ := models.LoadUpdate()
+	update.LastCheckAt = time.Now()
+	update.LastUpdatedAt = time.Now()
+	update.Latest = ""
+
+	return update.Save()
+}
+
+// fetch downloads a file, showing the progress on progress if it's set
+func fetch(url string, progress io.Writer) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	if progress == nil {
+		return ioutil.ReadAll(resp.Body)
+	}
+
+	buf := &bytes.Buffer{}
+	dp := display.DownloadPercent{Total: resp.ContentLength, Output: progress}
+	if err := dp.Copy(buf, resp.Body); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
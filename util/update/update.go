@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/jcelliott/lumber"
@@ -13,27 +14,61 @@ import (
 	"github.com/nanobox-io/nanobox/models"
 )
 
+// the release channels and where their builds are published. stable is what
+// most people run, beta gets the releases before they are promoted.
+var channels = map[string]string{
+	"stable": "https://s3.amazonaws.com/tools.nanobox.io/nanobox/v2",
+	"beta":   "https://s3.amazonaws.com/tools.nanobox.io/nanobox/v2/beta",
+}
+
+// ValidChannel returns true if name is a release channel
+func ValidChannel(name string) bool {
+	_, ok := channels[name]
+	return ok
+}
+
 func remotePath() string {
-	return fmt.Sprintf("https://s3.amazonaws.com/tools.nanobox.io/nanobox/v2/%s/%s/%s", runtime.GOOS, runtime.GOARCH, Name)
+	return channelPath("stable", Name)
+}
+
+// channelPath returns the url of a file of this platform's build on a
+// channel, ie: the binary or its checksum
+func channelPath(channel, file string) string {
+	base, ok := channels[channel]
+	if !ok {
+		base = channels["stable"]
+	}
+
+	return fmt.Sprintf("%s/%s/%s/%s", base, runtime.GOOS, runtime.GOARCH, file)
 }
 
-func latestVersion() string {
-	remotePath := "https://s3.amazonaws.com/tools.nanobox.io/nanobox/v2/version"
+// Latest returns the version string of the newest release on a channel
+func Latest(channel string) (string, error) {
+	base, ok := channels[channel]
+	if !ok {
+		return "", fmt.Errorf("unknown release channel '%s'", channel)
+	}
+
+	remotePath := base + "/version"
 	res, err := http.Get(remotePath)
 	if err != nil {
 		lumber.Error("update:http.Get(%s): %s", remotePath, err)
-		return ""
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch %s: %s", remotePath, res.Status)
 	}
 
 	// read the remote version string
 	vers, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		lumber.Error("update:ioutil.ReadAll(body): %s", err)
-		return ""
+		return "", err
 	}
-	defer res.Body.Close()
 
-	return string(vers)
+	return strings.TrimSpace(string(vers)), nil
 }
 
 func newUpdate() models.Update {
@@ -0,0 +1,83 @@
+package update
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// PublicKey is the key the releases are signed with, the base64 of its DER
+// encoding. It is set with build flags, a build without it can't verify a
+// download and won't update itself.
+var PublicKey string
+
+// ecdsaSignature is the ASN.1 form of a signature
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// verify checks a downloaded binary against the published checksum, in the
+// form of sha256sum's output, and the signature of its digest
+func verify(binary, checksum, signature []byte) error {
+	digest := sha256.Sum256(binary)
+
+	fields := strings.Fields(string(checksum))
+	if len(fields) == 0 {
+		return fmt.Errorf("the checksum is empty")
+	}
+
+	if !strings.EqualFold(fields[0], hex.EncodeToString(digest[:])) {
+		return fmt.Errorf("the checksum doesn't match, the download may be corrupt")
+	}
+
+	key, err := publicKey()
+	if err != nil {
+		return err
+	}
+
+	der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("the signature is malformed: %s", err)
+	}
+
+	sig := ecdsaSignature{}
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return fmt.Errorf("the signature is malformed: %s", err)
+	}
+
+	if !ecdsa.Verify(key, digest[:], sig.R, sig.S) {
+		return fmt.Errorf("the signature doesn't match, the download isn't a nanobox release")
+	}
+
+	return nil
+}
+
+// publicKey decodes the release key
+func publicKey() (*ecdsa.PublicKey, error) {
+	if PublicKey == "" {
+		return nil, fmt.Errorf("this build of nanobox has no release key to verify updates with")
+	}
+
+	der, err := base64.StdEncoding.DecodeString(PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("the release key is malformed: %s", err)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("the release key is malformed: %s", err)
+	}
+
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("the release key isn't an ecdsa key")
+	}
+
+	return ecdsaKey, nil
+}
@@ -0,0 +1,56 @@
+package update
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate a key: %s", err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to encode the key: %s", err)
+	}
+	PublicKey = base64.StdEncoding.EncodeToString(der)
+	defer func() { PublicKey = "" }()
+
+	binary := []byte("nanobox")
+	digest := sha256.Sum256(binary)
+	checksum := []byte(hex.EncodeToString(digest[:]) + "  nanobox\n")
+
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign: %s", err)
+	}
+	sig, _ := asn1.Marshal(ecdsaSignature{r, s})
+	signature := []byte(base64.StdEncoding.EncodeToString(sig) + "\n")
+
+	if err := verify(binary, checksum, signature); err != nil {
+		t.Errorf("failed to verify a signed release: %s", err)
+	}
+
+	if err := verify([]byte("nanobux"), checksum, signature); err == nil {
+		t.Errorf("verified a binary that doesn't match the checksum")
+	}
+
+	other := sha256.Sum256([]byte("nanobux"))
+	if err := verify([]byte("nanobux"), []byte(hex.EncodeToString(other[:])), signature); err == nil {
+		t.Errorf("verified a binary that isn't signed")
+	}
+
+	PublicKey = ""
+	if err := verify(binary, checksum, signature); err == nil {
+		t.Errorf("verified a release without a release key")
+	}
+}
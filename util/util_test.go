@@ -41,3 +41,25 @@ func TestError(t *testing.T) {
 		t.Errorf("append failed")
 	}
 }
+
+func TestExitCode(t *testing.T) {
+	cases := []struct {
+		err  error
+		code int
+	}{
+		{nil, util.ExitOK},
+		{fmt.Errorf("boom"), util.ExitInternal},
+		{util.Errorf("[USER] missing boxfile"), util.ExitUser},
+		{util.ErrorAppend(util.Errorf("[AUTH] bad token"), "nope"), util.ExitAuth},
+		{util.Err{Message: "failed", Code: "1001"}, util.ExitUser},
+		{util.Err{Message: "failed", Code: "USER/DOCKER"}, util.ExitUser},
+		{util.Errorf("[HOOKS] failed to execute hook"), util.ExitBuild},
+		{util.Errorf("[CANCELED] the build was canceled"), util.ExitCanceled},
+	}
+
+	for _, c := range cases {
+		if found := util.ExitCode(c.err); found != c.code {
+			t.Errorf("expected %d for '%v', got %d", c.code, c.err, found)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"syscall"
 )
 
 // IsPrivileged will return true if the current process is running under a
@@ -48,3 +49,24 @@ func sudoExists() bool {
 	_, err := exec.LookPath("sudo")
 	return err == nil
 }
+
+// ProcessAlive returns true if a process with the pid is running
+func ProcessAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	// signal 0 only checks the process exists
+	return proc.Signal(syscall.Signal(0)) == nil
+}
+
+// StopProcess asks a process to stop, the same as ctrl + c would
+func StopProcess(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+
+	return proc.Signal(os.Interrupt)
+}
@@ -107,3 +107,26 @@ func preparePrivilegeCmd(command string) string {
 	// generate a new command without the absolute path to the .exe
 	return fmt.Sprintf("%s%s", executable, parts[1])
 }
+
+// ProcessAlive returns true if a process with the pid is running
+func ProcessAlive(pid int) bool {
+	// finding a process opens a handle to it, which fails once it's gone
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	proc.Release()
+
+	return true
+}
+
+// StopProcess stops a process. Windows can't deliver ctrl + c to another
+// console, so the process is killed.
+func StopProcess(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+
+	return proc.Kill()
+}
@@ -0,0 +1,82 @@
+package watch
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jcelliott/lumber"
+)
+
+// Changes watches a directory and sends the files that changed, relative to
+// the directory, once no change has been seen for the debounce period. This
+// lets a burst of saves (a branch checkout, a formatter) trigger one
+// rebuild. Calling stop ends the watch.
+func Changes(path string, debounce time.Duration) (<-chan []string, func(), error) {
+	populateIgnore(path)
+
+	watcher, err := newRecursiveWatcher(path)
+	if err != nil {
+		// fall back to the slower crawler, ie: when the ulimit is too low
+		lumber.Info("watch:Changes:newRecursiveWatcher(%s): %s", path, err.Error())
+		watcher = newCrawlWatcher(path)
+	}
+
+	if err := watcher.watch(); err != nil {
+		return nil, nil, err
+	}
+
+	batches := make(chan []string)
+	done := make(chan struct{})
+
+	go func() {
+		pending := map[string]struct{}{}
+		var quiet <-chan time.Time
+
+		for {
+			select {
+			case e, ok := <-watcher.eventChan():
+				if !ok {
+					return
+				}
+				if e.error != nil {
+					lumber.Error("watch:Changes: %s", e.error.Error())
+					continue
+				}
+
+				file, err := filepath.Rel(path, e.file)
+				if err != nil || strings.HasPrefix(file, "..") {
+					file = e.file
+				}
+				pending[filepath.ToSlash(file)] = struct{}{}
+
+				// wait for the changes to settle down
+				quiet = time.After(debounce)
+			case <-quiet:
+				files := []string{}
+				for file := range pending {
+					files = append(files, file)
+				}
+				sort.Strings(files)
+				pending = map[string]struct{}{}
+				quiet = nil
+
+				select {
+				case batches <- files:
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	stop := func() {
+		close(done)
+		watcher.close()
+	}
+
+	return batches, stop, nil
+}
@@ -54,7 +54,13 @@ func newRecursiveWatcher(path string) (Watcher, error) {
 func run(watcher *notify) {
 	for {
 		select {
-		case evnt := <-watcher.Events:
+		case evnt, ok := <-watcher.Events:
+			// the watcher was closed
+			if !ok {
+				close(watcher.events)
+				return
+			}
+
 			if shouldIgnoreFile(filepath.Base(evnt.Name)) {
 				continue
 			}